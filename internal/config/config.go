@@ -1,6 +1,10 @@
 package config
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"fyne.io/fyne/v2"
 )
 
@@ -8,10 +12,75 @@ const (
 	// Ключи для глобальных настроек
 	keyTheme            = "global.theme"
 	keyNotificationMode = "global.notification_mode"
+	keySoundOnSuccess   = "global.sound_on_success"
+	keySoundOnFailure   = "global.sound_on_failure"
+	keyAccentColor      = "global.accent_color"
+	keyDensity          = "global.density"
+	keyRetryStalled     = "global.retry_stalled"
+	keyMaxConcurrent    = "global.max_concurrent_uploads"
+	keyPreventSleep     = "global.prevent_sleep"
+	keySplitLargeFiles  = "global.split_large_files"
+	keySplitPartMB      = "global.split_part_mb"
+	keyMetricsEnabled   = "global.metrics_enabled"
+	keyMetricsPort      = "global.metrics_port"
+	keyCACertFile       = "global.ca_cert_file"
+	keyUserAgent        = "global.user_agent"
+	keyXFSHosts         = "global.xfs_hosts"
+	keyReadBufferKB     = "global.read_buffer_kb"
+	keyReadAheadMB      = "global.read_ahead_mb"
+	keyUIScale          = "global.ui_scale"
 
 	// Префиксы для настроек провайдеров
-	prefixEnabled = ".enabled"
-	prefixAPIKey  = ".api_key"
+	prefixEnabled  = ".enabled"
+	prefixAPIKey   = ".api_key"
+	prefixBackup   = ".backup_api_key"
+	prefixChunkMB  = ".chunk_size_mb"
+	prefixThreshMB = ".multipart_threshold_mb"
+	prefixFolder   = ".folder_id"
+	prefixStatus   = ".status_url"
+	prefixSetting  = ".setting."
+	prefixInsecure = ".insecure_tls"
+	prefixHeaders  = ".headers"
+	prefixSignKey  = ".signing_key_id"
+	prefixSignSec  = ".signing_secret"
+	prefixSignAlg  = ".signing_algorithm"
+	prefixExpires  = ".expires"
+	prefixReminded = ".expiry_reminded"
+
+	// Префикс и суффиксы для настроек публикации в чаты
+	prefixShare   = "share."
+	suffixWebhook = ".webhook"
+	suffixTmpl    = ".template"
+	suffixAuto    = ".auto"
+
+	// Ключи для обработки картинок перед загрузкой
+	keyImageEnabled   = "image.enabled"
+	keyImageMaxWidth  = "image.max_width"
+	keyImageMaxHeight = "image.max_height"
+	keyImageFormat    = "image.format"
+	keyImageQuality   = "image.quality"
+
+	// Ключи для проверки файлов на вирусы перед загрузкой
+	keyScanMode       = "scan.mode"
+	keyScanClamd      = "scan.clamd_address"
+	keyScanVirusTotal = "scan.virustotal_key"
+
+	// Ключи для шаблона итога нескольких загрузок
+	keyResultPreset = "result.preset"
+	keyResultHeader = "result.header"
+	keyResultRow    = "result.row"
+	keyResultFooter = "result.footer"
+	keyResultHTML   = "result.html"
+
+	// Ключи для автоматической очистки истории загрузок
+	keyHistoryMaxEntries = "history.max_entries"
+	keyHistoryMaxDays    = "history.max_days"
+	keyHistorySuccessful = "history.successful_only"
+
+	// Ключи для состояния интерфейса между запусками
+	keyLastProvider = "state.last_provider"
+	keyLastDir      = "state.last_dir"
+	keyFileFilter   = "state.file_filter"
 )
 
 // NotificationMode определяет режим показа уведомлений
@@ -33,8 +102,74 @@ type GlobalConfig struct {
 
 	// NotificationMode режим показа уведомлений
 	NotificationMode NotificationMode
+
+	// SoundOnSuccess проигрывать звук при успешной загрузке
+	SoundOnSuccess bool
+
+	// SoundOnFailure проигрывать звук при ошибке загрузки
+	SoundOnFailure bool
+
+	// AccentColor акцентный цвет в формате "#RRGGBB" (пустая строка - цвет темы по умолчанию)
+	AccentColor string
+
+	// Density плотность интерфейса: "normal", "compact"
+	Density string
+
+	// UIScale масштаб интерфейса в процентах (100 - как задал FYNE_SCALE или система)
+	UIScale int
+
+	// RetryStalled автоматически перезапускать зависшие части загрузки
+	RetryStalled bool
+
+	// MaxConcurrentUploads сколько загрузок идет одновременно, остальные ждут в очереди (0 - без ограничения)
+	MaxConcurrentUploads int
+
+	// PreventSleep не давать системе уснуть, пока идут загрузки
+	PreventSleep bool
+
+	// SplitLargeFiles резать файлы больше лимита провайдера на части и загружать их по очереди
+	SplitLargeFiles bool
+
+	// SplitPartMB размер части в МБ (0 - по лимиту провайдера)
+	SplitPartMB int
+
+	// MetricsEnabled отдавать счетчики загрузок в формате Prometheus на http://127.0.0.1:MetricsPort/metrics
+	MetricsEnabled bool
+
+	// MetricsPort порт endpoint метрик
+	MetricsPort int
+
+	// CACertFile PEM файл с дополнительными доверенными CA ("" - только системные)
+	CACertFile string
+
+	// UserAgent User-Agent всех запросов ("" - по умолчанию)
+	UserAgent string
+
+	// XFSHosts хостинги XFileSharing, добавленные пользователем: "Имя = https://адрес" на строку
+	XFSHosts string
+
+	// ReadBufferKB буфер чтения файла потоковыми провайдерами в КБ (0 - без буфера)
+	ReadBufferKB int
+
+	// ReadAheadMB сколько файла читается с диска впереди сети в МБ (0 - без упреждения)
+	ReadAheadMB int
 }
 
+// DefaultMaxConcurrentUploads число одновременных загрузок по умолчанию
+const DefaultMaxConcurrentUploads = 3
+
+// DefaultMetricsPort порт endpoint метрик по умолчанию
+const DefaultMetricsPort = 9464
+
+// DefaultReadBufferKB буфер чтения файла по умолчанию
+const DefaultReadBufferKB = 1024
+
+// DefaultReadAheadMB упреждающее чтение файла по умолчанию
+const DefaultReadAheadMB = 16
+
+// DefaultUIScale масштаб интерфейса по умолчанию
+const DefaultUIScale = 100
+
 // ProviderConfig содержит настройки для конкретного провайдера
 type ProviderConfig struct {
 	// Enabled включен ли провайдер
@@ -42,8 +177,157 @@ type ProviderConfig struct {
 
 	// APIKey API ключ для провайдера
 	APIKey string
+
+	// BackupAPIKey запасной ключ: с ним повторяется загрузка, если основной отклонен или исчерпал лимит
+	BackupAPIKey string
+
+	// ChunkSizeMB размер части multipart загрузки в МБ (0 - авто)
+	ChunkSizeMB int
+
+	// MultipartThresholdMB размер файла в МБ, начиная с которого загрузка идет частями (0 - авто)
+	MultipartThresholdMB int
+
+	// FolderID папка аккаунта для загрузки ("" - корень)
+	FolderID string
+
+	// StatusURL страница состояния провайдера ("" - проверяется сам сервер провайдера)
+	StatusURL string
+
+	// InsecureTLS не проверять TLS сертификат своего сервера провайдера (самоподписанный сертификат)
+	InsecureTLS bool
+
+	// Headers дополнительные заголовки запросов провайдера: по одному "Имя: значение" на строку
+	Headers string
+
+	// SigningSecret секрет HMAC подписи запросов ("" - запросы не подписываются)
+	SigningSecret string
+
+	// SigningKeyID идентификатор ключа подписи (заголовок X-Key-Id)
+	SigningKeyID string
+
+	// SigningAlgorithm алгоритм подписи: "sha256" или "sha512" ("" - sha256)
+	SigningAlgorithm string
+
+	// Expires день окончания премиума или срока ключа (нулевое значение - не задан)
+	// Вводится в настройках или берется из сведений об аккаунте
+	Expires time.Time
+}
+
+// ExpiresLayout формат дня окончания в настройках
+const ExpiresLayout = "2006-01-02"
+
+// ParseExpires разбирает день окончания в формате ExpiresLayout ("" - не задан)
+func ParseExpires(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return time.Time{}, nil
+	}
+	day, err := time.ParseInLocation(ExpiresLayout, text, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry date %q, expected YYYY-MM-DD", text)
+	}
+	return day, nil
 }
 
+// formatExpires записывает день окончания для хранения ("" - не задан)
+func formatExpires(day time.Time) string {
+	if day.IsZero() {
+		return ""
+	}
+	return day.Format(ExpiresLayout)
+}
+
+// ShareConfig настройки публикации результата в чат (Discord, Slack)
+type ShareConfig struct {
+	// Webhook адрес входящего webhook ("" - чат не настроен)
+	Webhook string
+
+	// Template шаблон сообщения ("" - шаблон чата по умолчанию)
+	Template string
+
+	// Auto публиковать каждую успешную загрузку автоматически
+	Auto bool
+}
+
+// ImageConfig настройки уменьшения и пережатия картинок перед загрузкой
+type ImageConfig struct {
+	// Enabled обрабатывать картинки перед загрузкой
+	Enabled bool
+
+	// MaxWidth и MaxHeight наибольший размер в пикселях (0 - без ограничения)
+	MaxWidth  int
+	MaxHeight int
+
+	// Format формат результата: "" - как у исходного файла, "jpeg", "png"
+	Format string
+
+	// Quality качество JPEG от 1 до 100
+	Quality int
+}
+
+// Режимы проверки на вирусы
+const (
+	ScanOff        = ""
+	ScanClamAV     = "clamav"
+	ScanVirusTotal = "virustotal"
+)
+
+// ScanConfig проверка файлов на вирусы перед загрузкой
+type ScanConfig struct {
+	// Mode чем проверять: ScanOff, ScanClamAV или ScanVirusTotal
+	Mode string
+
+	// ClamdAddress сокет clamd: путь к unix сокету или host:port
+	ClamdAddress string
+
+	// VirusTotalKey API ключ VirusTotal
+	VirusTotalKey string
+}
+
+// ResultConfig шаблон итога нескольких загрузок для вставки в пост, письмо или страницу
+type ResultConfig struct {
+	// Preset ключ готового шаблона или "custom" ("" - шаблон по умолчанию)
+	Preset string
+
+	// Header, Row и Footer собственный шаблон (используется при Preset "custom")
+	Header string
+	Row    string
+	Footer string
+
+	// HTML экранировать значения собственного шаблона для HTML
+	HTML bool
+}
+
+// HistoryConfig автоматическая очистка истории загрузок
+type HistoryConfig struct {
+	// MaxEntries сколько последних записей хранить (0 - все)
+	MaxEntries int
+
+	// MaxDays сколько дней хранить запись (0 - без ограничения)
+	MaxDays int
+
+	// SuccessfulOnly удалять записи с расхождением при проверке целостности и с мертвой ссылкой
+	SuccessfulOnly bool
+}
+
+// UIState выбор пользователя, который восстанавливается при следующем запуске
+type UIState struct {
+	// LastProvider последний выбранный провайдер ("" - первый включенный)
+	LastProvider string
+
+	// LastDir папка, из которой последний раз выбирался файл ("" - домашняя)
+	LastDir string
+
+	// FileFilter группа файлов в диалоге выбора ("" - все файлы)
+	FileFilter string
+}
+
+// Размер и качество картинок по умолчанию
+const (
+	DefaultImageMaxSize = 1920
+	DefaultImageQuality = 85
+)
+
 // ConfigManager управляет настройками приложения
 type ConfigManager struct {
 	prefs fyne.Preferences
@@ -64,6 +348,24 @@ func (c *ConfigManager) GetGlobalConfig() GlobalConfig {
 	return GlobalConfig{
 		Theme:            theme,
 		NotificationMode: NotificationMode(notificationMode),
+		SoundOnSuccess:   c.prefs.BoolWithFallback(keySoundOnSuccess, false),
+		SoundOnFailure:   c.prefs.BoolWithFallback(keySoundOnFailure, false),
+		AccentColor:      c.prefs.StringWithFallback(keyAccentColor, ""),
+		Density:          c.prefs.StringWithFallback(keyDensity, "normal"),
+		RetryStalled:     c.prefs.BoolWithFallback(keyRetryStalled, true),
+
+		MaxConcurrentUploads: c.prefs.IntWithFallback(keyMaxConcurrent, DefaultMaxConcurrentUploads),
+		PreventSleep:         c.prefs.BoolWithFallback(keyPreventSleep, true),
+		SplitLargeFiles:      c.prefs.BoolWithFallback(keySplitLargeFiles, false),
+		SplitPartMB:          c.prefs.IntWithFallback(keySplitPartMB, 0),
+		MetricsEnabled:       c.prefs.BoolWithFallback(keyMetricsEnabled, false),
+		MetricsPort:          c.prefs.IntWithFallback(keyMetricsPort, DefaultMetricsPort),
+		CACertFile:           c.prefs.StringWithFallback(keyCACertFile, ""),
+		UserAgent:            c.prefs.StringWithFallback(keyUserAgent, ""),
+		XFSHosts:             c.prefs.StringWithFallback(keyXFSHosts, ""),
+		ReadBufferKB:         c.prefs.IntWithFallback(keyReadBufferKB, DefaultReadBufferKB),
+		ReadAheadMB:          c.prefs.IntWithFallback(keyReadAheadMB, DefaultReadAheadMB),
+		UIScale:              c.prefs.IntWithFallback(keyUIScale, DefaultUIScale),
 	}
 }
 
@@ -71,16 +373,48 @@ func (c *ConfigManager) GetGlobalConfig() GlobalConfig {
 func (c *ConfigManager) SetGlobalConfig(cfg GlobalConfig) {
 	c.prefs.SetString(keyTheme, cfg.Theme)
 	c.prefs.SetString(keyNotificationMode, string(cfg.NotificationMode))
+	c.prefs.SetBool(keySoundOnSuccess, cfg.SoundOnSuccess)
+	c.prefs.SetBool(keySoundOnFailure, cfg.SoundOnFailure)
+	c.prefs.SetString(keyAccentColor, cfg.AccentColor)
+	c.prefs.SetString(keyDensity, cfg.Density)
+	c.prefs.SetBool(keyRetryStalled, cfg.RetryStalled)
+	c.prefs.SetInt(keyMaxConcurrent, cfg.MaxConcurrentUploads)
+	c.prefs.SetBool(keyPreventSleep, cfg.PreventSleep)
+	c.prefs.SetBool(keySplitLargeFiles, cfg.SplitLargeFiles)
+	c.prefs.SetInt(keySplitPartMB, cfg.SplitPartMB)
+	c.prefs.SetBool(keyMetricsEnabled, cfg.MetricsEnabled)
+	c.prefs.SetInt(keyMetricsPort, cfg.MetricsPort)
+	c.prefs.SetString(keyCACertFile, cfg.CACertFile)
+	c.prefs.SetString(keyUserAgent, cfg.UserAgent)
+	c.prefs.SetString(keyXFSHosts, cfg.XFSHosts)
+	c.prefs.SetInt(keyReadBufferKB, cfg.ReadBufferKB)
+	c.prefs.SetInt(keyReadAheadMB, cfg.ReadAheadMB)
+	c.prefs.SetInt(keyUIScale, cfg.UIScale)
 }
 
 // GetProviderConfig возвращает настройки для конкретного провайдера
 func (c *ConfigManager) GetProviderConfig(providerName string) ProviderConfig {
 	enabled := c.prefs.BoolWithFallback(providerName+prefixEnabled, false)
 	apiKey := c.prefs.StringWithFallback(providerName+prefixAPIKey, "")
+	// Испорченное значение считается незаданным
+	expires, _ := ParseExpires(c.prefs.StringWithFallback(providerName+prefixExpires, ""))
 
 	return ProviderConfig{
-		Enabled: enabled,
-		APIKey:  apiKey,
+		Enabled:      enabled,
+		APIKey:       apiKey,
+		BackupAPIKey: c.prefs.StringWithFallback(providerName+prefixBackup, ""),
+		ChunkSizeMB:  c.prefs.IntWithFallback(providerName+prefixChunkMB, 0),
+		FolderID:     c.prefs.StringWithFallback(providerName+prefixFolder, ""),
+		StatusURL:    c.prefs.StringWithFallback(providerName+prefixStatus, ""),
+		InsecureTLS:  c.prefs.BoolWithFallback(providerName+prefixInsecure, false),
+		Headers:      c.prefs.StringWithFallback(providerName+prefixHeaders, ""),
+		Expires:      expires,
+
+		SigningSecret:    c.prefs.StringWithFallback(providerName+prefixSignSec, ""),
+		SigningKeyID:     c.prefs.StringWithFallback(providerName+prefixSignKey, ""),
+		SigningAlgorithm: c.prefs.StringWithFallback(providerName+prefixSignAlg, ""),
+
+		MultipartThresholdMB: c.prefs.IntWithFallback(providerName+prefixThreshMB, 0),
 	}
 }
 
@@ -88,6 +422,148 @@ func (c *ConfigManager) GetProviderConfig(providerName string) ProviderConfig {
 func (c *ConfigManager) SetProviderConfig(providerName string, cfg ProviderConfig) {
 	c.prefs.SetBool(providerName+prefixEnabled, cfg.Enabled)
 	c.prefs.SetString(providerName+prefixAPIKey, cfg.APIKey)
+	c.prefs.SetString(providerName+prefixBackup, cfg.BackupAPIKey)
+	c.prefs.SetInt(providerName+prefixChunkMB, cfg.ChunkSizeMB)
+	c.prefs.SetInt(providerName+prefixThreshMB, cfg.MultipartThresholdMB)
+	c.prefs.SetString(providerName+prefixFolder, cfg.FolderID)
+	c.prefs.SetString(providerName+prefixStatus, cfg.StatusURL)
+	c.prefs.SetBool(providerName+prefixInsecure, cfg.InsecureTLS)
+	c.prefs.SetString(providerName+prefixHeaders, cfg.Headers)
+	c.prefs.SetString(providerName+prefixSignSec, cfg.SigningSecret)
+	c.prefs.SetString(providerName+prefixSignKey, cfg.SigningKeyID)
+	c.prefs.SetString(providerName+prefixSignAlg, cfg.SigningAlgorithm)
+	c.prefs.SetString(providerName+prefixExpires, formatExpires(cfg.Expires))
+}
+
+// GetExpiryReminded возвращает день окончания, о котором уже напоминали (нулевое значение - не напоминали)
+func (c *ConfigManager) GetExpiryReminded(providerName string) time.Time {
+	day, _ := ParseExpires(c.prefs.StringWithFallback(providerName+prefixReminded, ""))
+	return day
+}
+
+// SetExpiryReminded запоминает, что о дне окончания expires напомнили: напоминание показывается один раз
+func (c *ConfigManager) SetExpiryReminded(providerName string, expires time.Time) {
+	c.prefs.SetString(providerName+prefixReminded, formatExpires(expires))
+}
+
+// GetProviderSettings возвращает значения дополнительных полей провайдера
+func (c *ConfigManager) GetProviderSettings(providerName string, keys []string) map[string]string {
+	values := make(map[string]string, len(keys))
+	for _, key := range keys {
+		values[key] = c.prefs.StringWithFallback(providerName+prefixSetting+key, "")
+	}
+	return values
+}
+
+// SetProviderSettings сохраняет значения дополнительных полей провайдера
+func (c *ConfigManager) SetProviderSettings(providerName string, values map[string]string) {
+	for key, value := range values {
+		c.prefs.SetString(providerName+prefixSetting+key, value)
+	}
+}
+
+// GetShareConfig возвращает настройки публикации в чат
+func (c *ConfigManager) GetShareConfig(target string) ShareConfig {
+	return ShareConfig{
+		Webhook:  c.prefs.StringWithFallback(prefixShare+target+suffixWebhook, ""),
+		Template: c.prefs.StringWithFallback(prefixShare+target+suffixTmpl, ""),
+		Auto:     c.prefs.BoolWithFallback(prefixShare+target+suffixAuto, false),
+	}
+}
+
+// SetShareConfig сохраняет настройки публикации в чат
+func (c *ConfigManager) SetShareConfig(target string, cfg ShareConfig) {
+	c.prefs.SetString(prefixShare+target+suffixWebhook, cfg.Webhook)
+	c.prefs.SetString(prefixShare+target+suffixTmpl, cfg.Template)
+	c.prefs.SetBool(prefixShare+target+suffixAuto, cfg.Auto)
+}
+
+// GetImageConfig возвращает настройки обработки картинок
+func (c *ConfigManager) GetImageConfig() ImageConfig {
+	return ImageConfig{
+		Enabled:   c.prefs.BoolWithFallback(keyImageEnabled, false),
+		MaxWidth:  c.prefs.IntWithFallback(keyImageMaxWidth, DefaultImageMaxSize),
+		MaxHeight: c.prefs.IntWithFallback(keyImageMaxHeight, DefaultImageMaxSize),
+		Format:    c.prefs.StringWithFallback(keyImageFormat, ""),
+		Quality:   c.prefs.IntWithFallback(keyImageQuality, DefaultImageQuality),
+	}
+}
+
+// SetImageConfig сохраняет настройки обработки картинок
+func (c *ConfigManager) SetImageConfig(cfg ImageConfig) {
+	c.prefs.SetBool(keyImageEnabled, cfg.Enabled)
+	c.prefs.SetInt(keyImageMaxWidth, cfg.MaxWidth)
+	c.prefs.SetInt(keyImageMaxHeight, cfg.MaxHeight)
+	c.prefs.SetString(keyImageFormat, cfg.Format)
+	c.prefs.SetInt(keyImageQuality, cfg.Quality)
+}
+
+// GetScanConfig возвращает настройки проверки на вирусы
+func (c *ConfigManager) GetScanConfig() ScanConfig {
+	return ScanConfig{
+		Mode:          c.prefs.StringWithFallback(keyScanMode, ScanOff),
+		ClamdAddress:  c.prefs.StringWithFallback(keyScanClamd, ""),
+		VirusTotalKey: c.prefs.StringWithFallback(keyScanVirusTotal, ""),
+	}
+}
+
+// SetScanConfig сохраняет настройки проверки на вирусы
+func (c *ConfigManager) SetScanConfig(cfg ScanConfig) {
+	c.prefs.SetString(keyScanMode, cfg.Mode)
+	c.prefs.SetString(keyScanClamd, cfg.ClamdAddress)
+	c.prefs.SetString(keyScanVirusTotal, cfg.VirusTotalKey)
+}
+
+// GetResultConfig возвращает шаблон итога нескольких загрузок
+func (c *ConfigManager) GetResultConfig() ResultConfig {
+	return ResultConfig{
+		Preset: c.prefs.StringWithFallback(keyResultPreset, ""),
+		Header: c.prefs.StringWithFallback(keyResultHeader, ""),
+		Row:    c.prefs.StringWithFallback(keyResultRow, ""),
+		Footer: c.prefs.StringWithFallback(keyResultFooter, ""),
+		HTML:   c.prefs.BoolWithFallback(keyResultHTML, false),
+	}
+}
+
+// SetResultConfig сохраняет шаблон итога нескольких загрузок
+func (c *ConfigManager) SetResultConfig(cfg ResultConfig) {
+	c.prefs.SetString(keyResultPreset, cfg.Preset)
+	c.prefs.SetString(keyResultHeader, cfg.Header)
+	c.prefs.SetString(keyResultRow, cfg.Row)
+	c.prefs.SetString(keyResultFooter, cfg.Footer)
+	c.prefs.SetBool(keyResultHTML, cfg.HTML)
+}
+
+// GetHistoryConfig возвращает настройки очистки истории
+func (c *ConfigManager) GetHistoryConfig() HistoryConfig {
+	return HistoryConfig{
+		MaxEntries:     c.prefs.IntWithFallback(keyHistoryMaxEntries, 0),
+		MaxDays:        c.prefs.IntWithFallback(keyHistoryMaxDays, 0),
+		SuccessfulOnly: c.prefs.BoolWithFallback(keyHistorySuccessful, false),
+	}
+}
+
+// SetHistoryConfig сохраняет настройки очистки истории
+func (c *ConfigManager) SetHistoryConfig(cfg HistoryConfig) {
+	c.prefs.SetInt(keyHistoryMaxEntries, cfg.MaxEntries)
+	c.prefs.SetInt(keyHistoryMaxDays, cfg.MaxDays)
+	c.prefs.SetBool(keyHistorySuccessful, cfg.SuccessfulOnly)
+}
+
+// GetUIState возвращает состояние интерфейса с прошлого запуска
+func (c *ConfigManager) GetUIState() UIState {
+	return UIState{
+		LastProvider: c.prefs.StringWithFallback(keyLastProvider, ""),
+		LastDir:      c.prefs.StringWithFallback(keyLastDir, ""),
+		FileFilter:   c.prefs.StringWithFallback(keyFileFilter, ""),
+	}
+}
+
+// SetUIState сохраняет состояние интерфейса
+func (c *ConfigManager) SetUIState(state UIState) {
+	c.prefs.SetString(keyLastProvider, state.LastProvider)
+	c.prefs.SetString(keyLastDir, state.LastDir)
+	c.prefs.SetString(keyFileFilter, state.FileFilter)
 }
 
 // IsProviderEnabled проверяет, включен ли провайдер
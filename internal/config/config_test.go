@@ -214,6 +214,149 @@ func TestGlobalConfig(t *testing.T) {
 			t.Errorf("Updated theme = %s, want 'dark'", config.Theme)
 		}
 	})
+
+	t.Run("Appearance", func(t *testing.T) {
+		prefs := newMockPreferences()
+		cm := NewConfigManager(prefs)
+
+		config := cm.GetGlobalConfig()
+		if config.AccentColor != "" || config.Density != "normal" {
+			t.Errorf("Default appearance = %q/%q, want \"\"/\"normal\"", config.AccentColor, config.Density)
+		}
+		if config.UIScale != DefaultUIScale {
+			t.Errorf("Default UIScale = %d, want %d", config.UIScale, DefaultUIScale)
+		}
+
+		cm.SetGlobalConfig(GlobalConfig{Theme: "dark", AccentColor: "#43a047", Density: "compact", UIScale: 150})
+
+		config = cm.GetGlobalConfig()
+		if config.AccentColor != "#43a047" || config.Density != "compact" {
+			t.Errorf("Saved appearance = %q/%q, want \"#43a047\"/\"compact\"", config.AccentColor, config.Density)
+		}
+		if config.UIScale != 150 {
+			t.Errorf("Saved UIScale = %d, want 150", config.UIScale)
+		}
+	})
+
+	t.Run("Sounds", func(t *testing.T) {
+		prefs := newMockPreferences()
+		cm := NewConfigManager(prefs)
+
+		// По умолчанию звуки выключены
+		config := cm.GetGlobalConfig()
+		if config.SoundOnSuccess || config.SoundOnFailure {
+			t.Errorf("Default sounds = %v/%v, want false/false", config.SoundOnSuccess, config.SoundOnFailure)
+		}
+
+		cm.SetGlobalConfig(GlobalConfig{Theme: "auto", SoundOnFailure: true})
+
+		config = cm.GetGlobalConfig()
+		if config.SoundOnSuccess || !config.SoundOnFailure {
+			t.Errorf("Saved sounds = %v/%v, want false/true", config.SoundOnSuccess, config.SoundOnFailure)
+		}
+	})
+
+	t.Run("Retry stalled", func(t *testing.T) {
+		prefs := newMockPreferences()
+		cm := NewConfigManager(prefs)
+
+		// По умолчанию зависшие части перезапускаются
+		if !cm.GetGlobalConfig().RetryStalled {
+			t.Error("Default RetryStalled = false, want true")
+		}
+
+		cm.SetGlobalConfig(GlobalConfig{Theme: "auto", RetryStalled: false})
+		if cm.GetGlobalConfig().RetryStalled {
+			t.Error("Saved RetryStalled = true, want false")
+		}
+	})
+
+	t.Run("Max concurrent uploads", func(t *testing.T) {
+		prefs := newMockPreferences()
+		cm := NewConfigManager(prefs)
+
+		if n := cm.GetGlobalConfig().MaxConcurrentUploads; n != DefaultMaxConcurrentUploads {
+			t.Errorf("Default MaxConcurrentUploads = %d, want %d", n, DefaultMaxConcurrentUploads)
+		}
+
+		// 0 - без ограничения
+		cm.SetGlobalConfig(GlobalConfig{Theme: "auto", MaxConcurrentUploads: 0})
+		if n := cm.GetGlobalConfig().MaxConcurrentUploads; n != 0 {
+			t.Errorf("Saved MaxConcurrentUploads = %d, want 0", n)
+		}
+	})
+
+	t.Run("Prevent sleep", func(t *testing.T) {
+		prefs := newMockPreferences()
+		cm := NewConfigManager(prefs)
+
+		// По умолчанию система не засыпает во время загрузок
+		if !cm.GetGlobalConfig().PreventSleep {
+			t.Error("Default PreventSleep = false, want true")
+		}
+
+		cm.SetGlobalConfig(GlobalConfig{Theme: "auto", PreventSleep: false})
+		if cm.GetGlobalConfig().PreventSleep {
+			t.Error("Saved PreventSleep = true, want false")
+		}
+	})
+
+	t.Run("Split large files", func(t *testing.T) {
+		cm := NewConfigManager(newMockPreferences())
+
+		// По умолчанию файлы не режутся
+		if cfg := cm.GetGlobalConfig(); cfg.SplitLargeFiles || cfg.SplitPartMB != 0 {
+			t.Errorf("Default split = %v, %d MB, want off", cfg.SplitLargeFiles, cfg.SplitPartMB)
+		}
+
+		cm.SetGlobalConfig(GlobalConfig{SplitLargeFiles: true, SplitPartMB: 500})
+		if cfg := cm.GetGlobalConfig(); !cfg.SplitLargeFiles || cfg.SplitPartMB != 500 {
+			t.Errorf("Saved split = %v, %d MB, want on, 500 MB", cfg.SplitLargeFiles, cfg.SplitPartMB)
+		}
+	})
+
+	t.Run("Metrics endpoint", func(t *testing.T) {
+		cm := NewConfigManager(newMockPreferences())
+
+		// По умолчанию endpoint выключен
+		if cfg := cm.GetGlobalConfig(); cfg.MetricsEnabled || cfg.MetricsPort != DefaultMetricsPort {
+			t.Errorf("Default metrics = %v, port %d, want off, port %d", cfg.MetricsEnabled, cfg.MetricsPort, DefaultMetricsPort)
+		}
+
+		cm.SetGlobalConfig(GlobalConfig{MetricsEnabled: true, MetricsPort: 9100})
+		if cfg := cm.GetGlobalConfig(); !cfg.MetricsEnabled || cfg.MetricsPort != 9100 {
+			t.Errorf("Saved metrics = %v, port %d, want on, port 9100", cfg.MetricsEnabled, cfg.MetricsPort)
+		}
+	})
+
+	t.Run("CA certificate file", func(t *testing.T) {
+		cm := NewConfigManager(newMockPreferences())
+		if cfg := cm.GetGlobalConfig(); cfg.CACertFile != "" {
+			t.Errorf("Default CACertFile = %q, want empty", cfg.CACertFile)
+		}
+
+		cm.SetGlobalConfig(GlobalConfig{CACertFile: "/etc/ssl/homelab.pem"})
+		if cfg := cm.GetGlobalConfig(); cfg.CACertFile != "/etc/ssl/homelab.pem" {
+			t.Errorf("Saved CACertFile = %q", cfg.CACertFile)
+		}
+	})
+
+	t.Run("User-Agent", func(t *testing.T) {
+		cm := NewConfigManager(newMockPreferences())
+		cm.SetGlobalConfig(GlobalConfig{UserAgent: "Mozilla/5.0"})
+		if cfg := cm.GetGlobalConfig(); cfg.UserAgent != "Mozilla/5.0" {
+			t.Errorf("Saved UserAgent = %q", cfg.UserAgent)
+		}
+	})
+
+	t.Run("XFS hosts", func(t *testing.T) {
+		cm := NewConfigManager(newMockPreferences())
+		hosts := "MyHost = https://myhost.example"
+		cm.SetGlobalConfig(GlobalConfig{XFSHosts: hosts})
+		if cfg := cm.GetGlobalConfig(); cfg.XFSHosts != hosts {
+			t.Errorf("Saved XFSHosts = %q", cfg.XFSHosts)
+		}
+	})
 }
 
 // TestProviderConfig проверяет работу с настройками провайдеров
@@ -237,8 +380,9 @@ func TestProviderConfig(t *testing.T) {
 
 		// Устанавливаем настройки
 		config := ProviderConfig{
-			Enabled: true,
-			APIKey:  "test-api-key-123",
+			Enabled:      true,
+			APIKey:       "test-api-key-123",
+			BackupAPIKey: "backup-key-456",
 		}
 		cm.SetProviderConfig("DataVaults", config)
 
@@ -250,6 +394,40 @@ func TestProviderConfig(t *testing.T) {
 		if savedConfig.APIKey != "test-api-key-123" {
 			t.Errorf("Saved APIKey = %s, want 'test-api-key-123'", savedConfig.APIKey)
 		}
+		if savedConfig.BackupAPIKey != "backup-key-456" {
+			t.Errorf("Saved BackupAPIKey = %s, want 'backup-key-456'", savedConfig.BackupAPIKey)
+		}
+	})
+
+	t.Run("Expiry date", func(t *testing.T) {
+		prefs := newMockPreferences()
+		cm := NewConfigManager(prefs)
+
+		expires, err := ParseExpires(" 2026-03-15 ")
+		if err != nil {
+			t.Fatalf("ParseExpires() error = %v", err)
+		}
+		cm.SetProviderConfig("DataVaults", ProviderConfig{APIKey: "key", Expires: expires})
+		if got := cm.GetProviderConfig("DataVaults").Expires; !got.Equal(expires) {
+			t.Errorf("Expires = %v, want %v", got, expires)
+		}
+
+		cm.SetProviderConfig("DataVaults", ProviderConfig{APIKey: "key"})
+		if got := cm.GetProviderConfig("DataVaults").Expires; !got.IsZero() {
+			t.Errorf("Expires after clearing = %v, want zero", got)
+		}
+
+		if _, err := ParseExpires("15.03.2026"); err == nil {
+			t.Error("ParseExpires() accepted a date in the wrong format")
+		}
+
+		if !cm.GetExpiryReminded("DataVaults").IsZero() {
+			t.Error("GetExpiryReminded() should be zero by default")
+		}
+		cm.SetExpiryReminded("DataVaults", expires)
+		if got := cm.GetExpiryReminded("DataVaults"); !got.Equal(expires) {
+			t.Errorf("GetExpiryReminded() = %v, want %v", got, expires)
+		}
 	})
 
 	t.Run("Multiple providers", func(t *testing.T) {
@@ -279,6 +457,99 @@ func TestProviderConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("Chunk size", func(t *testing.T) {
+		prefs := newMockPreferences()
+		cm := NewConfigManager(prefs)
+
+		// По умолчанию размер части выбирает сервер
+		if config := cm.GetProviderConfig("Rootz"); config.ChunkSizeMB != 0 {
+			t.Errorf("Default ChunkSizeMB = %d, want 0", config.ChunkSizeMB)
+		}
+
+		cm.SetProviderConfig("Rootz", ProviderConfig{Enabled: true, ChunkSizeMB: 64, MultipartThresholdMB: 16})
+
+		config := cm.GetProviderConfig("Rootz")
+		if config.ChunkSizeMB != 64 {
+			t.Errorf("Saved ChunkSizeMB = %d, want 64", config.ChunkSizeMB)
+		}
+		if config.MultipartThresholdMB != 16 {
+			t.Errorf("Saved MultipartThresholdMB = %d, want 16", config.MultipartThresholdMB)
+		}
+	})
+
+	t.Run("Folder", func(t *testing.T) {
+		prefs := newMockPreferences()
+		cm := NewConfigManager(prefs)
+
+		// По умолчанию загрузка идет в корень
+		if config := cm.GetProviderConfig("DataVaults"); config.FolderID != "" {
+			t.Errorf("Default FolderID = %q, want empty", config.FolderID)
+		}
+
+		cm.SetProviderConfig("DataVaults", ProviderConfig{Enabled: true, FolderID: "42"})
+
+		if config := cm.GetProviderConfig("DataVaults"); config.FolderID != "42" {
+			t.Errorf("Saved FolderID = %q, want \"42\"", config.FolderID)
+		}
+	})
+
+	t.Run("Status page", func(t *testing.T) {
+		prefs := newMockPreferences()
+		cm := NewConfigManager(prefs)
+
+		// По умолчанию проверяется сам сервер провайдера
+		if config := cm.GetProviderConfig("Rootz"); config.StatusURL != "" {
+			t.Errorf("Default StatusURL = %q, want empty", config.StatusURL)
+		}
+
+		statusURL := "https://status.example.com/api/v2/status.json"
+		cm.SetProviderConfig("Rootz", ProviderConfig{Enabled: true, StatusURL: statusURL})
+
+		if config := cm.GetProviderConfig("Rootz"); config.StatusURL != statusURL {
+			t.Errorf("Saved StatusURL = %q, want %q", config.StatusURL, statusURL)
+		}
+	})
+
+	t.Run("Insecure TLS", func(t *testing.T) {
+		cm := NewConfigManager(newMockPreferences())
+
+		// По умолчанию сертификат проверяется
+		if config := cm.GetProviderConfig("tus"); config.InsecureTLS {
+			t.Error("Default InsecureTLS = true, want false")
+		}
+
+		cm.SetProviderConfig("tus", ProviderConfig{Enabled: true, InsecureTLS: true})
+		if config := cm.GetProviderConfig("tus"); !config.InsecureTLS {
+			t.Error("Saved InsecureTLS = false, want true")
+		}
+		if config := cm.GetProviderConfig("IPFS"); config.InsecureTLS {
+			t.Error("InsecureTLS leaked to another provider")
+		}
+	})
+
+	t.Run("Extra headers", func(t *testing.T) {
+		cm := NewConfigManager(newMockPreferences())
+		headers := "X-Api-Client: multiUploader\nReferer: https://example.com/"
+		cm.SetProviderConfig("Rootz", ProviderConfig{Enabled: true, Headers: headers})
+		if config := cm.GetProviderConfig("Rootz"); config.Headers != headers {
+			t.Errorf("Saved Headers = %q, want %q", config.Headers, headers)
+		}
+	})
+
+	t.Run("Request signing", func(t *testing.T) {
+		cm := NewConfigManager(newMockPreferences())
+		want := ProviderConfig{Enabled: true, SigningSecret: "s3cr3t", SigningKeyID: "team-1", SigningAlgorithm: "sha512"}
+		cm.SetProviderConfig("tus", want)
+		config := cm.GetProviderConfig("tus")
+		if config.SigningSecret != want.SigningSecret || config.SigningKeyID != want.SigningKeyID || config.SigningAlgorithm != want.SigningAlgorithm {
+			t.Errorf("Saved signing = %q/%q/%q, want %q/%q/%q", config.SigningSecret, config.SigningKeyID, config.SigningAlgorithm,
+				want.SigningSecret, want.SigningKeyID, want.SigningAlgorithm)
+		}
+		if config := cm.GetProviderConfig("IPFS"); config.SigningSecret != "" {
+			t.Error("SigningSecret leaked to another provider")
+		}
+	})
+
 	t.Run("Update provider config", func(t *testing.T) {
 		prefs := newMockPreferences()
 		cm := NewConfigManager(prefs)
@@ -350,6 +621,100 @@ func TestGetProviderAPIKey(t *testing.T) {
 	}
 }
 
+// TestShareConfig проверяет настройки публикации в чаты
+func TestShareConfig(t *testing.T) {
+	cm := NewConfigManager(newMockPreferences())
+
+	if cfg := cm.GetShareConfig("discord"); cfg != (ShareConfig{}) {
+		t.Errorf("default share config = %+v, want empty", cfg)
+	}
+
+	want := ShareConfig{Webhook: "https://discord.com/api/webhooks/1/x", Template: "{url}", Auto: true}
+	cm.SetShareConfig("discord", want)
+	if cfg := cm.GetShareConfig("discord"); cfg != want {
+		t.Errorf("share config = %+v, want %+v", cfg, want)
+	}
+	if cfg := cm.GetShareConfig("slack"); cfg != (ShareConfig{}) {
+		t.Errorf("slack config = %+v, want it untouched", cfg)
+	}
+}
+
+// TestImageConfig проверяет значения по умолчанию и сохранение настроек картинок
+func TestImageConfig(t *testing.T) {
+	cm := NewConfigManager(newMockPreferences())
+
+	want := ImageConfig{MaxWidth: DefaultImageMaxSize, MaxHeight: DefaultImageMaxSize, Quality: DefaultImageQuality}
+	if cfg := cm.GetImageConfig(); cfg != want {
+		t.Errorf("default image config = %+v, want %+v", cfg, want)
+	}
+
+	want = ImageConfig{Enabled: true, MaxWidth: 1280, Format: "jpeg", Quality: 70}
+	cm.SetImageConfig(want)
+	if cfg := cm.GetImageConfig(); cfg != want {
+		t.Errorf("image config = %+v, want %+v", cfg, want)
+	}
+}
+
+// TestScanConfig проверяет, что проверка на вирусы по умолчанию выключена и настройки сохраняются
+func TestScanConfig(t *testing.T) {
+	cm := NewConfigManager(newMockPreferences())
+
+	if cfg := cm.GetScanConfig(); cfg != (ScanConfig{}) {
+		t.Errorf("default scan config = %+v, want scanning off", cfg)
+	}
+
+	want := ScanConfig{Mode: ScanClamAV, ClamdAddress: "127.0.0.1:3310", VirusTotalKey: "vt-key"}
+	cm.SetScanConfig(want)
+	if cfg := cm.GetScanConfig(); cfg != want {
+		t.Errorf("scan config = %+v, want %+v", cfg, want)
+	}
+}
+
+// TestResultConfig проверяет сохранение шаблона итога
+func TestResultConfig(t *testing.T) {
+	cm := NewConfigManager(newMockPreferences())
+
+	if cfg := cm.GetResultConfig(); cfg != (ResultConfig{}) {
+		t.Errorf("default result config = %+v, want empty", cfg)
+	}
+
+	want := ResultConfig{Preset: "custom", Header: "<ul>", Row: "<li>{file}: {url}</li>", Footer: "</ul>", HTML: true}
+	cm.SetResultConfig(want)
+	if cfg := cm.GetResultConfig(); cfg != want {
+		t.Errorf("result config = %+v, want %+v", cfg, want)
+	}
+}
+
+// TestHistoryConfig проверяет, что история по умолчанию не очищается и настройки сохраняются
+func TestHistoryConfig(t *testing.T) {
+	cm := NewConfigManager(newMockPreferences())
+
+	if cfg := cm.GetHistoryConfig(); cfg != (HistoryConfig{}) {
+		t.Errorf("default history config = %+v, want no pruning", cfg)
+	}
+
+	want := HistoryConfig{MaxEntries: 500, MaxDays: 90, SuccessfulOnly: true}
+	cm.SetHistoryConfig(want)
+	if cfg := cm.GetHistoryConfig(); cfg != want {
+		t.Errorf("history config = %+v, want %+v", cfg, want)
+	}
+}
+
+// TestUIState проверяет сохранение выбора пользователя между запусками
+func TestUIState(t *testing.T) {
+	cm := NewConfigManager(newMockPreferences())
+
+	if state := cm.GetUIState(); state != (UIState{}) {
+		t.Errorf("default UI state = %+v, want empty", state)
+	}
+
+	want := UIState{LastProvider: "Rootz", LastDir: "/home/user/Videos", FileFilter: "video"}
+	cm.SetUIState(want)
+	if state := cm.GetUIState(); state != want {
+		t.Errorf("UI state = %+v, want %+v", state, want)
+	}
+}
+
 // TestConfigPersistence проверяет что настройки сохраняются
 func TestConfigPersistence(t *testing.T) {
 	prefs := newMockPreferences()
@@ -0,0 +1,365 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"multiUploader/internal/providers"
+)
+
+// maxAttempts сколько раз пробуем докачать файл после обрыва соединения
+const maxAttempts = 4
+
+// retryDelay базовая пауза перед повтором, растет с номером попытки (переопределяется в тестах)
+var retryDelay = time.Second
+
+// Ошибки скачивания
+var (
+	ErrNotAFile         = errors.New("URL points to a web page, not a file")
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// errInvalidURL ссылку нельзя превратить в запрос - повтор не поможет
+var errInvalidURL = errors.New("invalid download URL")
+
+// Doer выполняет HTTP запрос (httpclient.Client или *http.Client)
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Request параметры скачивания
+type Request struct {
+	URL string
+	// Dir директория, в которую сохраняется файл
+	Dir string
+	// FileName имя файла (пусто - из Content-Disposition или URL)
+	FileName string
+	// SHA256 ожидаемый хеш в hex (пусто - не проверять)
+	SHA256 string
+}
+
+// Progress прогресс скачивания
+type Progress struct {
+	Bytes int64
+	// Total размер файла (0 - сервер не сообщил)
+	Total int64
+	// Speed скорость в байтах/сек
+	Speed float64
+}
+
+// Result скачанный файл
+type Result struct {
+	Path   string
+	Size   int64
+	SHA256 string
+}
+
+// Download скачивает файл в req.Dir, докачивая его через Range после обрывов,
+// и сверяет SHA-256 с ожидаемым. Файл пишется во временный ".part" и переименовывается в конце
+func Download(ctx context.Context, client Doer, req Request, progress chan<- Progress) (*Result, error) {
+	resp, err := getFirst(ctx, client, req.URL)
+	if err != nil {
+		return nil, err
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "text/html" {
+		resp.Body.Close()
+		return nil, ErrNotAFile
+	}
+
+	name := req.FileName
+	if name == "" {
+		name = fileNameFromResponse(resp, req.URL)
+	}
+	dest := uniquePath(filepath.Join(req.Dir, name))
+	partPath := dest + ".part"
+
+	file, err := os.Create(partPath)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to create file: %w", err)
+	}
+
+	d := &downloader{
+		file:      file,
+		total:     max(resp.ContentLength, 0),
+		speedCalc: providers.NewSpeedCalculator(),
+		progress:  progress,
+	}
+	err = d.run(ctx, client, req.URL, resp)
+	closeErr := file.Close()
+	if err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write file: %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(partPath)
+		return nil, err
+	}
+
+	if err := os.Rename(partPath, dest); err != nil {
+		os.Remove(partPath)
+		return nil, fmt.Errorf("failed to save file: %w", err)
+	}
+
+	sum, err := hashFile(dest)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Path: dest, Size: d.written, SHA256: sum}
+
+	if req.SHA256 != "" && !strings.EqualFold(req.SHA256, sum) {
+		return result, fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, req.SHA256, sum)
+	}
+	return result, nil
+}
+
+// downloader пишет тело ответа в файл и докачивает остаток при обрыве
+type downloader struct {
+	file      *os.File
+	written   int64
+	total     int64
+	speedCalc *providers.SpeedCalculator
+	progress  chan<- Progress
+}
+
+// run копирует первый ответ и при ошибках чтения повторяет запрос с Range от записанного места
+func (d *downloader) run(ctx context.Context, client Doer, rawURL string, resp *http.Response) error {
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return providers.ErrCancelled
+			case <-time.After(retryDelay * time.Duration(attempt-1)):
+			}
+
+			var err error
+			resp, err = get(ctx, client, rawURL, d.written)
+			if err != nil {
+				if ctx.Err() != nil {
+					return providers.ErrCancelled
+				}
+				if !retryable(err) {
+					return err
+				}
+				lastErr = err
+				continue
+			}
+			if err := d.resume(resp); err != nil {
+				resp.Body.Close()
+				return err
+			}
+		}
+
+		lastErr = d.copy(resp.Body)
+		resp.Body.Close()
+		if lastErr == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return providers.ErrCancelled
+		}
+	}
+
+	return fmt.Errorf("download interrupted: %w (after %d attempts)", lastErr, maxAttempts)
+}
+
+// resume готовит файл к продолжению: сервер без поддержки Range отдает файл заново
+// Ответ 206 принимается, только если Content-Range начинается с уже записанного места
+func (d *downloader) resume(resp *http.Response) error {
+	if resp.StatusCode == http.StatusPartialContent {
+		start, total, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != d.written {
+			return fmt.Errorf("server resumed the download with Content-Range %q, expected bytes %d-", resp.Header.Get("Content-Range"), d.written)
+		}
+		if total > 0 {
+			d.total = total
+		}
+		return nil
+	}
+
+	if err := d.file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to restart download: %w", err)
+	}
+	if _, err := d.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to restart download: %w", err)
+	}
+	d.written = 0
+	d.total = max(resp.ContentLength, 0)
+	return nil
+}
+
+// copy пишет тело ответа в файл, сообщая о прогрессе
+func (d *downloader) copy(body io.Reader) error {
+	buf := make([]byte, 256*1024)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if _, werr := d.file.Write(buf[:n]); werr != nil {
+				return fmt.Errorf("failed to write file: %w", werr)
+			}
+			d.written += int64(n)
+			d.report()
+		}
+		if err == io.EOF {
+			if d.total > 0 && d.written < d.total {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// report отправляет прогресс без блокировки
+func (d *downloader) report() {
+	select {
+	case d.progress <- Progress{Bytes: d.written, Total: d.total, Speed: d.speedCalc.Update(d.written)}:
+	default:
+	}
+}
+
+// getFirst выполняет первый запрос, повторяя его после сетевых ошибок и ответов 5xx и 429
+func getFirst(ctx context.Context, client Doer, rawURL string) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := get(ctx, client, rawURL, 0)
+		if err == nil || attempt == maxAttempts || !retryable(err) {
+			return resp, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, providers.ErrCancelled
+		case <-time.After(retryDelay * time.Duration(attempt)):
+		}
+	}
+}
+
+// retryable сообщает, что запрос стоит повторить: сеть или временная ошибка сервера
+func retryable(err error) bool {
+	var httpErr *providers.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Status >= http.StatusInternalServerError || httpErr.Status == http.StatusTooManyRequests
+	}
+	return !errors.Is(err, providers.ErrCancelled) && !errors.Is(err, errInvalidURL)
+}
+
+// parseContentRange разбирает "bytes 100-199/200": начало диапазона и размер файла (0 - "*")
+func parseContentRange(value string) (start, total int64, ok bool) {
+	spec, found := strings.CutPrefix(value, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rng, size, found := strings.Cut(spec, "/")
+	first, _, found2 := strings.Cut(rng, "-")
+	if !found || !found2 {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if size != "*" {
+		if total, err = strconv.ParseInt(size, 10, 64); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, total, true
+}
+
+// get выполняет GET запрос, начиная с offset байт
+func get(ctx context.Context, client Doer, rawURL string, offset int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidURL, err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, providers.ErrCancelled
+		}
+		return nil, fmt.Errorf("download request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, &providers.HTTPError{Op: "download", Status: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+// fileNameFromResponse берет имя файла из Content-Disposition, иначе из пути URL
+func fileNameFromResponse(resp *http.Response, rawURL string) string {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		if name := safeName(params["filename"]); name != "" {
+			return name
+		}
+	}
+	return NameFromURL(rawURL)
+}
+
+// NameFromURL возвращает имя файла из пути ссылки ("download", если его там нет)
+func NameFromURL(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		if name := safeName(path.Base(u.Path)); name != "" {
+			return name
+		}
+	}
+	return "download"
+}
+
+// safeName отбрасывает путь из имени файла, полученного от сервера
+func safeName(name string) string {
+	name = filepath.Base(filepath.FromSlash(strings.ReplaceAll(name, `\`, "/")))
+	if name == "." || name == ".." || name == "/" || name == string(filepath.Separator) {
+		return ""
+	}
+	return name
+}
+
+// uniquePath добавляет к имени " (N)", если такой файл уже существует
+func uniquePath(p string) string {
+	if _, err := os.Stat(p); errors.Is(err, os.ErrNotExist) {
+		return p
+	}
+
+	ext := filepath.Ext(p)
+	base := strings.TrimSuffix(p, ext)
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if _, err := os.Stat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate
+		}
+	}
+}
+
+// hashFile считает SHA-256 файла
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to open downloaded file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash downloaded file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
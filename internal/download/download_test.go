@@ -0,0 +1,211 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"multiUploader/internal/providers"
+)
+
+// noRetryDelay убирает паузу между повторами
+func noRetryDelay(t *testing.T) {
+	t.Helper()
+	old := retryDelay
+	retryDelay = 0
+	t.Cleanup(func() { retryDelay = old })
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// TestDownloadResume проверяет докачку после обрыва соединения и проверку хеша
+func TestDownloadResume(t *testing.T) {
+	noRetryDelay(t)
+
+	data := bytes.Repeat([]byte("0123456789"), 100*1024)
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// Первый ответ обрывается на середине
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.Header().Set("Content-Disposition", `attachment; filename="../report.bin"`)
+			w.Write(data[:len(data)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		if r.Header.Get("Range") == "" {
+			t.Error("retry request has no Range header")
+		}
+		http.ServeContent(w, r, "report.bin", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	progress := make(chan Progress, 1000)
+	result, err := Download(context.Background(), server.Client(), Request{
+		URL:    server.URL + "/files/x",
+		Dir:    dir,
+		SHA256: sha256Hex(data),
+	}, progress)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+
+	if result.Path != filepath.Join(dir, "report.bin") {
+		t.Errorf("Path = %s, want report.bin in target dir", result.Path)
+	}
+	got, _ := os.ReadFile(result.Path)
+	if !bytes.Equal(got, data) {
+		t.Errorf("downloaded %d bytes, content differs", len(got))
+	}
+	if calls.Load() != 2 {
+		t.Errorf("server calls = %d, want 2", calls.Load())
+	}
+	if _, err := os.Stat(result.Path + ".part"); !errors.Is(err, os.ErrNotExist) {
+		t.Error(".part file was not removed")
+	}
+}
+
+// TestDownloadRetryFirst проверяет повтор первого запроса после временной ошибки сервера
+func TestDownloadRetryFirst(t *testing.T) {
+	noRetryDelay(t)
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("data"))
+	}))
+	defer server.Close()
+
+	result, err := Download(context.Background(), server.Client(), Request{URL: server.URL + "/file.txt", Dir: t.TempDir()}, make(chan Progress, 10))
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if result.Size != 4 || calls.Load() != 2 {
+		t.Errorf("Size = %d, server calls = %d, want 4 bytes after 2 calls", result.Size, calls.Load())
+	}
+}
+
+// TestDownloadWrongRange проверяет, что ответ 206 не с того места не дописывается в файл
+func TestDownloadWrongRange(t *testing.T) {
+	noRetryDelay(t)
+
+	data := bytes.Repeat([]byte("0123456789"), 10*1024)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.Write(data[:len(data)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		// Сервер отдает файл с начала, но отвечает 206
+		w.Header().Set("Content-Range", "bytes 0-"+strconv.Itoa(len(data)-1)+"/"+strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if _, err := Download(context.Background(), server.Client(), Request{URL: server.URL + "/file.bin", Dir: dir}, make(chan Progress, 100)); err == nil {
+		t.Fatal("Download() succeeded, want Content-Range error")
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("files left in target dir: %v", files)
+	}
+}
+
+// TestParseContentRange проверяет разбор заголовка Content-Range
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		value        string
+		start, total int64
+		ok           bool
+	}{
+		{"bytes 100-199/200", 100, 200, true},
+		{"bytes 0-99/*", 0, 0, true},
+		{"bytes */200", 0, 0, false},
+		{"items 0-1/2", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		start, total, ok := parseContentRange(tt.value)
+		if start != tt.start || total != tt.total || ok != tt.ok {
+			t.Errorf("parseContentRange(%q) = %d, %d, %v, want %d, %d, %v", tt.value, start, total, ok, tt.start, tt.total, tt.ok)
+		}
+	}
+}
+
+// TestDownloadErrors проверяет ошибки скачивания
+func TestDownloadErrors(t *testing.T) {
+	noRetryDelay(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			w.Write([]byte("data"))
+		}
+	}))
+	defer server.Close()
+
+	t.Run("web page", func(t *testing.T) {
+		_, err := Download(context.Background(), server.Client(), Request{URL: server.URL + "/page", Dir: t.TempDir()}, make(chan Progress, 10))
+		if !errors.Is(err, ErrNotAFile) {
+			t.Errorf("error = %v, want ErrNotAFile", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := Download(context.Background(), server.Client(), Request{URL: server.URL + "/missing", Dir: t.TempDir()}, make(chan Progress, 10))
+		var httpErr *providers.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Status != http.StatusNotFound {
+			t.Errorf("error = %v, want HTTPError 404", err)
+		}
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		dir := t.TempDir()
+		result, err := Download(context.Background(), server.Client(), Request{URL: server.URL + "/file.txt", Dir: dir, SHA256: sha256Hex([]byte("other"))}, make(chan Progress, 10))
+		if !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("error = %v, want ErrChecksumMismatch", err)
+		}
+		// Файл остается, чтобы его можно было изучить
+		if result == nil || result.Path != filepath.Join(dir, "file.txt") {
+			t.Errorf("result = %+v", result)
+		}
+	})
+
+	t.Run("existing file", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "file.txt"), []byte("old"), 0o644)
+		result, err := Download(context.Background(), server.Client(), Request{URL: server.URL + "/file.txt", Dir: dir}, make(chan Progress, 10))
+		if err != nil {
+			t.Fatalf("Download() error = %v", err)
+		}
+		if result.Path != filepath.Join(dir, "file (1).txt") {
+			t.Errorf("Path = %s, want \"file (1).txt\"", result.Path)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/providers"
+)
+
+// Status итоговое состояние соединения
+type Status int
+
+const (
+	// StatusUnknown проверка еще не выполнялась
+	StatusUnknown Status = iota
+	// StatusOnline интернет и все проверенные провайдеры доступны
+	StatusOnline
+	// StatusDegraded интернет есть, но часть провайдеров недоступна
+	StatusDegraded
+	// StatusOffline нет подключения к интернету
+	StatusOffline
+)
+
+const (
+	// dialTimeout ограничивает проверку подключения к интернету
+	dialTimeout = 5 * time.Second
+	// maxOfflineWait сколько ждать возвращения связи, прежде чем признать загрузку неудачной
+	maxOfflineWait = 30 * time.Minute
+)
+
+// onlineCheckInterval период проверки подключения, пока связи нет (переопределяется в тестах)
+var onlineCheckInterval = 5 * time.Second
+
+// connectivityTargets адреса для проверки подключения к интернету (переопределяются в тестах)
+// Используются IP адреса, чтобы отличать отсутствие сети от проблем с DNS провайдера
+var connectivityTargets = []string{"1.1.1.1:443", "8.8.8.8:443"}
+
+// ErrNoInternet нет соединения ни с одним из адресов проверки
+var ErrNoInternet = errors.New("no internet connection")
+
+// ProviderResult результат проверки одного провайдера
+type ProviderResult struct {
+	Name    string
+	Err     error // nil - сервер отвечает
+	Latency time.Duration
+}
+
+// Report результат проверки соединения
+type Report struct {
+	Status Status
+	// Internet nil, если подключение к интернету есть
+	Internet  error
+	Providers []ProviderResult // по имени провайдера
+	CheckedAt time.Time
+}
+
+// Check проверяет подключение к интернету и доступность провайдеров (параллельно)
+func Check(ctx context.Context, probers map[string]providers.Prober) Report {
+	report := Report{Providers: make([]ProviderResult, 0, len(probers))}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		report.Internet = checkInternet(ctx)
+	}()
+
+	for name, prober := range probers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := prober.Probe(httpclient.WithProvider(ctx, name))
+			result := ProviderResult{Name: name, Err: err, Latency: time.Since(start)}
+
+			mu.Lock()
+			report.Providers = append(report.Providers, result)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(report.Providers, func(i, j int) bool {
+		return report.Providers[i].Name < report.Providers[j].Name
+	})
+	report.Status = summarize(report)
+	report.CheckedAt = time.Now()
+	return report
+}
+
+// summarize выводит итоговое состояние
+// Отвечающий провайдер доказывает, что интернет есть, даже если адреса проверки закрыты фаерволом
+func summarize(report Report) Status {
+	reachable, unreachable := 0, 0
+	for _, p := range report.Providers {
+		if p.Err == nil {
+			reachable++
+		} else {
+			unreachable++
+		}
+	}
+
+	switch {
+	case report.Internet != nil && reachable == 0:
+		return StatusOffline
+	case unreachable > 0:
+		return StatusDegraded
+	default:
+		return StatusOnline
+	}
+}
+
+// WaitOnline ждет подключения к интернету (providers.WaitOnlineFunc)
+// Если связи нет, вызывает offline и проверяет подключение каждые onlineCheckInterval
+// Возвращает true, если связь пропадала; ErrNoInternet - если она не вернулась за maxOfflineWait
+func WaitOnline(ctx context.Context, offline func()) (bool, error) {
+	if checkInternet(ctx) == nil {
+		return false, nil
+	}
+	if offline != nil {
+		offline()
+	}
+
+	ticker := time.NewTicker(onlineCheckInterval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(maxOfflineWait)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, providers.ErrCancelled
+		case <-deadline.C:
+			return true, ErrNoInternet
+		case <-ticker.C:
+			if checkInternet(ctx) == nil {
+				return true, nil
+			}
+		}
+	}
+}
+
+// checkInternet устанавливает TCP соединение с любым из адресов проверки
+func checkInternet(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	for _, target := range connectivityTargets {
+		conn, err := dialer.DialContext(ctx, "tcp", target)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return ErrNoInternet
+}
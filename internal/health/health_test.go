@@ -0,0 +1,123 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"multiUploader/internal/providers"
+)
+
+// fakeProber возвращает заданную ошибку
+type fakeProber struct {
+	err error
+}
+
+func (p fakeProber) Probe(ctx context.Context) error {
+	return p.err
+}
+
+// useTargets подменяет адреса проверки интернета на время теста
+func useTargets(t *testing.T, online bool) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := listener.Addr().String()
+	if !online {
+		// Порт освобождается - соединение будет отклонено
+		listener.Close()
+	} else {
+		t.Cleanup(func() { listener.Close() })
+	}
+
+	previous := connectivityTargets
+	connectivityTargets = []string{target}
+	t.Cleanup(func() { connectivityTargets = previous })
+}
+
+// TestCheck проверяет итоговое состояние по результатам проверок
+func TestCheck(t *testing.T) {
+	down := fakeProber{err: errors.New("connection refused")}
+
+	tests := []struct {
+		name    string
+		online  bool
+		probers map[string]providers.Prober
+		want    Status
+	}{
+		{"Everything reachable", true, map[string]providers.Prober{"A": fakeProber{}, "B": fakeProber{}}, StatusOnline},
+		{"No providers", true, nil, StatusOnline},
+		{"One provider down", true, map[string]providers.Prober{"A": fakeProber{}, "B": down}, StatusDegraded},
+		{"Offline", false, map[string]providers.Prober{"A": down}, StatusOffline},
+		{"Targets blocked but provider answers", false, map[string]providers.Prober{"A": fakeProber{}}, StatusOnline},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTargets(t, tt.online)
+
+			report := Check(context.Background(), tt.probers)
+			if report.Status != tt.want {
+				t.Errorf("Status = %v, want %v (report %+v)", report.Status, tt.want, report)
+			}
+			if (report.Internet == nil) != tt.online {
+				t.Errorf("Internet = %v, want online %v", report.Internet, tt.online)
+			}
+			if len(report.Providers) != len(tt.probers) {
+				t.Fatalf("got %d provider results, want %d", len(report.Providers), len(tt.probers))
+			}
+			for i := 1; i < len(report.Providers); i++ {
+				if report.Providers[i-1].Name > report.Providers[i].Name {
+					t.Errorf("providers are not sorted: %+v", report.Providers)
+				}
+			}
+		})
+	}
+}
+
+// TestWaitOnline проверяет ожидание возвращения связи
+func TestWaitOnline(t *testing.T) {
+	previous := onlineCheckInterval
+	onlineCheckInterval = 10 * time.Millisecond
+	t.Cleanup(func() { onlineCheckInterval = previous })
+
+	t.Run("Online", func(t *testing.T) {
+		useTargets(t, true)
+		lost, err := WaitOnline(context.Background(), func() { t.Error("offline called while online") })
+		if lost || err != nil {
+			t.Errorf("WaitOnline() = %v, %v, want false, nil", lost, err)
+		}
+	})
+
+	t.Run("Connection returns", func(t *testing.T) {
+		useTargets(t, false)
+		target := connectivityTargets[0]
+
+		// Связь "возвращается", когда на адресе проверки снова слушают
+		offline := func() {
+			listener, err := net.Listen("tcp", target)
+			if err != nil {
+				t.Skipf("port %s was taken: %v", target, err)
+			}
+			t.Cleanup(func() { listener.Close() })
+		}
+		lost, err := WaitOnline(context.Background(), offline)
+		if !lost || err != nil {
+			t.Errorf("WaitOnline() = %v, %v, want true, nil", lost, err)
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		useTargets(t, false)
+		ctx, cancel := context.WithCancel(context.Background())
+		_, err := WaitOnline(ctx, cancel)
+		if !errors.Is(err, providers.ErrCancelled) {
+			t.Errorf("WaitOnline() error = %v, want ErrCancelled", err)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/providers"
+)
+
+// maxStatusPageBody сколько читать из ответа страницы состояния
+const maxStatusPageBody = 64 << 10
+
+// StatusPage проверяет провайдера по его странице состояния (providers.Prober)
+// Ответ в формате Statuspage (/api/v2/status.json) разбирается: major и critical означают сбой
+// Для любой другой страницы достаточно ответа 2xx
+type StatusPage struct {
+	URL string
+}
+
+// statusPageResponse ответ Statuspage API
+type statusPageResponse struct {
+	Status struct {
+		Indicator   string `json:"indicator"`
+		Description string `json:"description"`
+	} `json:"status"`
+}
+
+// ValidateStatusURL проверяет адрес страницы состояния
+func ValidateStatusURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("status page URL must be an http or https address")
+	}
+	return nil
+}
+
+// Probe возвращает nil, если страница состояния не сообщает о сбое
+func (p StatusPage) Probe(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpclient.Probe().Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return providers.ErrCancelled
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status page returned %s", resp.Status)
+	}
+
+	var status statusPageResponse
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxStatusPageBody))
+	if err != nil || json.Unmarshal(body, &status) != nil {
+		// Обычная страница, а не Statuspage API - доступна, значит провайдер работает
+		return nil
+	}
+	switch status.Status.Indicator {
+	case "major", "critical":
+		if status.Status.Description != "" {
+			return fmt.Errorf("provider reports an outage: %s", status.Status.Description)
+		}
+		return errors.New("provider reports an outage")
+	}
+	return nil
+}
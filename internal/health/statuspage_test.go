@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestStatusPage проверяет разбор ответа страницы состояния
+func TestStatusPage(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{"Statuspage operational", http.StatusOK, `{"status":{"indicator":"none","description":"All Systems Operational"}}`, false},
+		{"Statuspage minor incident", http.StatusOK, `{"status":{"indicator":"minor","description":"Minor Service Outage"}}`, false},
+		{"Statuspage major outage", http.StatusOK, `{"status":{"indicator":"major","description":"Major Service Outage"}}`, true},
+		{"Plain page", http.StatusOK, `<html>OK</html>`, false},
+		{"Server error", http.StatusBadGateway, ``, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := StatusPage{URL: server.URL}.Probe(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Probe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestValidateStatusURL проверяет адреса страницы состояния
+func TestValidateStatusURL(t *testing.T) {
+	for _, raw := range []string{"https://status.example.com/api/v2/status.json", "http://example.com/health"} {
+		if err := ValidateStatusURL(raw); err != nil {
+			t.Errorf("ValidateStatusURL(%q) = %v, want nil", raw, err)
+		}
+	}
+	for _, raw := range []string{"status.example.com", "ftp://example.com", "https://"} {
+		if err := ValidateStatusURL(raw); err == nil {
+			t.Errorf("ValidateStatusURL(%q) = nil, want error", raw)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"multiUploader/internal/upload"
+)
+
+// csvHeader столбцы экспорта в CSV
+var csvHeader = []string{
+	"uploaded_at", "file_name", "size", "provider", "url", "download_url", "delete_url",
+	"source_url", "file_path", "sha256", "integrity", "mime_type", "parts",
+	"collection_url", "note", "tags",
+}
+
+// Filter возвращает записи, в имени файла, провайдере, ссылках, заметке или метках которых есть query (без учета регистра)
+// Пустой query возвращает все записи
+func Filter(entries []Entry, query string) []Entry {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return entries
+	}
+
+	var filtered []Entry
+	for _, e := range entries {
+		for _, field := range append([]string{e.FileName, e.Provider, e.URL, e.DownloadURL, e.SourceURL, e.CollectionURL, e.Note}, e.Tags...) {
+			if strings.Contains(strings.ToLower(field), query) {
+				filtered = append(filtered, e)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// WriteCSV записывает записи в CSV с заголовком; время в UTC (RFC 3339), размер в байтах
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{
+			e.UploadedAt.UTC().Format(time.RFC3339),
+			csvText(e.FileName),
+			strconv.FormatInt(e.Size, 10),
+			csvText(e.Provider),
+			csvText(e.URL),
+			csvText(e.DownloadURL),
+			csvText(e.DeleteURL),
+			csvText(e.SourceURL),
+			csvText(e.FilePath),
+			e.SHA256,
+			string(e.Integrity),
+			e.MIMEType,
+			csvText(partLinks(e.Parts)),
+			csvText(e.CollectionURL),
+			csvText(e.Note),
+			csvText(strings.Join(e.Tags, ", ")),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON записывает записи массивом JSON в том же формате, что и файл истории
+func WriteJSON(w io.Writer, entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// partLinks ссылки на части файла через пробел
+func partLinks(parts []upload.PartLink) string {
+	links := make([]string, len(parts))
+	for i, part := range parts {
+		links[i] = part.Link()
+	}
+	return strings.Join(links, " ")
+}
+
+// csvText защищает текст от выполнения как формулы в табличных редакторах
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
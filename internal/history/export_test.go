@@ -0,0 +1,88 @@
+package history
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"multiUploader/internal/upload"
+)
+
+// exportEntries записи для проверки экспорта
+func exportEntries() []Entry {
+	at := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	return []Entry{
+		{ID: "1", FileName: "report.pdf", Size: 2048, Provider: "Rootz", URL: "https://rootz.so/d/abc", UploadedAt: at, SHA256: "ff00", Integrity: upload.VerifyOK, MIMEType: "application/pdf",
+			CollectionURL: "https://datavaults.co/folder/42", Note: "Sent to the client in March", Tags: []string{"acme", "contracts"}},
+		{ID: "2", FileName: "=cmd.csv", Size: 10, Provider: "AkiraBox", DownloadURL: "https://akirabox.com/x", UploadedAt: at,
+			Parts: []upload.PartLink{{Name: "=cmd.csv.001", DownloadURL: "https://akirabox.com/x"}, {Name: "=cmd.csv.002", URL: "https://akirabox.com/y"}}},
+	}
+}
+
+// TestFilter проверяет отбор записей по имени, провайдеру и ссылке
+func TestFilter(t *testing.T) {
+	entries := exportEntries()
+	for query, want := range map[string]int{"": 2, "REPORT": 1, "akirabox": 1, "rootz.so": 1, "folder/42": 1, "in march": 1, "Contracts": 1, "missing": 0} {
+		if got := Filter(entries, query); len(got) != want {
+			t.Errorf("Filter(%q) returned %d entries, want %d", query, len(got), want)
+		}
+	}
+}
+
+// TestWriteCSV проверяет столбцы, формат времени и защиту от формул
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, exportEntries()); err != nil {
+		t.Fatalf("WriteCSV() = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("exported CSV is invalid: %v", err)
+	}
+	if len(records) != 3 || len(records[0]) != len(csvHeader) {
+		t.Fatalf("got %d records, want header and 2 rows", len(records))
+	}
+	if want := []string{"2025-03-01T12:30:00Z", "report.pdf", "2048", "Rootz", "https://rootz.so/d/abc"}; !slices.Equal(records[1][:len(want)], want) {
+		t.Errorf("first row = %v, want prefix %v", records[1], want)
+	}
+	if records[1][9] != "ff00" || records[1][10] != string(upload.VerifyOK) || records[1][11] != "application/pdf" {
+		t.Errorf("checksum and type columns = %v", records[1][9:])
+	}
+	if records[2][1] != "'=cmd.csv" {
+		t.Errorf("file name = %q, want it escaped", records[2][1])
+	}
+	if want := "https://akirabox.com/x https://akirabox.com/y"; records[2][12] != want {
+		t.Errorf("parts = %q, want %q", records[2][12], want)
+	}
+	if want := "https://datavaults.co/folder/42"; records[1][13] != want {
+		t.Errorf("collection = %q, want %q", records[1][13], want)
+	}
+	if records[1][14] != "Sent to the client in March" || records[1][15] != "acme, contracts" {
+		t.Errorf("note and tags = %q", records[1][14:])
+	}
+}
+
+// TestWriteJSON проверяет, что экспорт читается как история
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, exportEntries()); err != nil {
+		t.Fatalf("WriteJSON() = %v", err)
+	}
+	var decoded []Entry
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("exported JSON is invalid: %v", err)
+	}
+	if len(decoded) != 2 || decoded[1].FileName != "=cmd.csv" || decoded[0].SHA256 != "ff00" {
+		t.Errorf("decoded = %+v", decoded)
+	}
+
+	buf.Reset()
+	WriteJSON(&buf, nil)
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("empty export = %q, want []", got)
+	}
+}
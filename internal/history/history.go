@@ -0,0 +1,195 @@
+package history
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"multiUploader/internal/jsonfile"
+	"multiUploader/internal/upload"
+)
+
+// fileName имя файла истории в директории состояния приложения
+const fileName = "history.json"
+
+// Entry запись об успешной загрузке
+type Entry struct {
+	ID       string `json:"id"`
+	FileName string `json:"file_name"`
+	FilePath string `json:"file_path,omitempty"`
+	// SourceURL ссылка, по которой файл был взят при загрузке по URL
+	SourceURL   string    `json:"source_url,omitempty"`
+	Size        int64     `json:"size"`
+	MIMEType    string    `json:"mime_type,omitempty"`
+	Provider    string    `json:"provider"`
+	URL         string    `json:"url,omitempty"`
+	DownloadURL string    `json:"download_url,omitempty"`
+	DeleteURL   string    `json:"delete_url,omitempty"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+
+	// CollectionURL ссылка на папку, в которую файл загружен вместе с группой
+	CollectionURL string `json:"collection_url,omitempty"`
+	// FolderID папка аккаунта провайдера, выбранная при загрузке ("" - корень или провайдер без папок)
+	FolderID string `json:"folder_id,omitempty"`
+	// Mirrors провайдеры, в которые файл загружался вместе с этим
+	Mirrors []string `json:"mirrors,omitempty"`
+
+	// Note заметка пользователя (кому и зачем отправлена ссылка)
+	Note string `json:"note,omitempty"`
+	// Tags метки пользователя для отбора в истории
+	Tags []string `json:"tags,omitempty"`
+
+	// Parts части файла, если он был разрезан под лимит провайдера (URL ведет на первую)
+	Parts []upload.PartLink `json:"parts,omitempty"`
+
+	// SHA256 хеш локального файла на момент загрузки
+	SHA256 string `json:"sha256,omitempty"`
+	// Integrity результат проверки целостности после загрузки
+	Integrity upload.VerifyStatus `json:"integrity,omitempty"`
+	// IntegrityDetail описание расхождения, если проверка не прошла
+	IntegrityDetail string `json:"integrity_detail,omitempty"`
+
+	// Link результат последней проверки ссылки ("" - не проверялась)
+	Link upload.LinkStatus `json:"link,omitempty"`
+	// LinkCheckedAt время последней проверки ссылки
+	LinkCheckedAt time.Time `json:"link_checked_at,omitzero"`
+
+	// Log журнал передачи: этапы, части, повторы и время их завершения
+	Log []LogEvent `json:"log,omitempty"`
+}
+
+// LogEvent событие журнала передачи
+type LogEvent struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// PrimaryLink ссылка, по которой проверяется доступность файла
+func (e Entry) PrimaryLink() string {
+	if e.URL != "" {
+		return e.URL
+	}
+	return e.DownloadURL
+}
+
+// Store история загрузок, сохраняемая в JSON файл
+// Записи хранятся от новых к старым
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	entries []Entry
+	// retention правила очистки, применяемые при добавлении записей
+	retention Retention
+}
+
+// DefaultPath возвращает путь к файлу истории в директории настроек пользователя
+func DefaultPath() (string, error) {
+	return jsonfile.DefaultPath(fileName)
+}
+
+// Open загружает историю из файла; отсутствующий файл означает пустую историю
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	if err := jsonfile.Load(path, &s.entries, "history"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewInMemory создает историю без файла (если файл истории недоступен)
+func NewInMemory() *Store {
+	return &Store{}
+}
+
+// Entries возвращает копию записей от новых к старым
+func (s *Store) Entries() []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Entry(nil), s.entries...)
+}
+
+// RecentFiles возвращает до limit путей локальных файлов из записей, от новых к старым, без повторов
+// Существование файлов не проверяется
+func RecentFiles(entries []Entry, limit int) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if len(paths) == limit {
+			break
+		}
+		if e.FilePath == "" || seen[e.FilePath] {
+			continue
+		}
+		seen[e.FilePath] = true
+		paths = append(paths, e.FilePath)
+	}
+	return paths
+}
+
+// Add добавляет запись в начало истории и сохраняет файл
+// Пустые ID и UploadedAt заполняются автоматически; лишние по правилам очистки записи удаляются
+func (s *Store) Add(e Entry) (Entry, error) {
+	if e.ID == "" {
+		e.ID = newID()
+	}
+	if e.UploadedAt.IsZero() {
+		e.UploadedAt = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append([]Entry{e}, s.entries...)
+	s.entries = s.retention.keep(s.entries, time.Now())
+	return e, s.save()
+}
+
+// Update изменяет запись с указанным ID и сохраняет файл
+func (s *Store) Update(id string, fn func(e *Entry)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.entries {
+		if s.entries[i].ID == id {
+			fn(&s.entries[i])
+			return s.save()
+		}
+	}
+	return fmt.Errorf("history entry %s not found", id)
+}
+
+// UpdateEach изменяет записи с ID из changes и сохраняет файл один раз
+// Записи, которых уже нет в истории (например, удалены очисткой), пропускаются
+func (s *Store) UpdateEach(changes map[string]func(e *Entry)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for i := range s.entries {
+		if fn, ok := changes[s.entries[i].ID]; ok {
+			fn(&s.entries[i])
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return s.save()
+}
+
+// save записывает историю через временный файл, чтобы сбой не испортил ее
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	return jsonfile.Save(s.path, s.entries, "history")
+}
+
+// newID возвращает случайный идентификатор записи
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,97 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"multiUploader/internal/upload"
+)
+
+// TestStore проверяет добавление, изменение и загрузку истории
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", fileName)
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() on missing file error = %v", err)
+	}
+	if len(s.Entries()) != 0 {
+		t.Fatalf("new history has %d entries", len(s.Entries()))
+	}
+
+	first, err := s.Add(Entry{FileName: "a.bin", Provider: "Rootz"})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if first.ID == "" || first.UploadedAt.IsZero() {
+		t.Errorf("Add() did not fill ID/UploadedAt: %+v", first)
+	}
+	if _, err := s.Add(Entry{FileName: "b.bin", Provider: "AkiraBox"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	err = s.Update(first.ID, func(e *Entry) {
+		e.Integrity = upload.VerifyMismatch
+		e.IntegrityDetail = "size mismatch"
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := s.Update("missing", func(*Entry) {}); err == nil {
+		t.Error("Update() of missing entry error = nil")
+	}
+	err = s.UpdateEach(map[string]func(*Entry){
+		first.ID:  func(e *Entry) { e.Link = upload.LinkDead },
+		"missing": func(*Entry) { t.Error("UpdateEach() changed a missing entry") },
+	})
+	if err != nil {
+		t.Fatalf("UpdateEach() error = %v", err)
+	}
+
+	// Перечитываем с диска
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	entries := reopened.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].FileName != "b.bin" {
+		t.Errorf("newest entry = %s, want b.bin", entries[0].FileName)
+	}
+	if entries[1].Integrity != upload.VerifyMismatch || entries[1].IntegrityDetail != "size mismatch" || entries[1].Link != upload.LinkDead {
+		t.Errorf("updated entry = %+v", entries[1])
+	}
+}
+
+// TestOpenCorrupted проверяет ошибку при поврежденном файле
+func TestOpenCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), fileName)
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); err == nil {
+		t.Error("Open() error = nil, want parse error")
+	}
+}
+
+// TestRecentFiles проверяет список недавних файлов: новые первыми, без повторов и загрузок по ссылке
+func TestRecentFiles(t *testing.T) {
+	entries := []Entry{
+		{FilePath: "/data/c.mp4"},
+		{SourceURL: "https://example.com/x.zip"},
+		{FilePath: "/data/a.zip"},
+		{FilePath: "/data/c.mp4"},
+		{FilePath: "/data/b.png"},
+	}
+
+	if got, want := RecentFiles(entries, 10), []string{"/data/c.mp4", "/data/a.zip", "/data/b.png"}; !slices.Equal(got, want) {
+		t.Errorf("RecentFiles() = %v, want %v", got, want)
+	}
+	if got, want := RecentFiles(entries, 2), []string{"/data/c.mp4", "/data/a.zip"}; !slices.Equal(got, want) {
+		t.Errorf("RecentFiles(limit 2) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package history
+
+import (
+	"time"
+
+	"multiUploader/internal/upload"
+)
+
+// Retention правила автоматической очистки истории (нулевое значение - хранить все)
+type Retention struct {
+	// MaxEntries сколько последних записей хранить (0 - без ограничения)
+	MaxEntries int
+	// MaxAge сколько хранить запись после загрузки (0 - без ограничения)
+	MaxAge time.Duration
+	// SuccessfulOnly удалять записи с расхождением при проверке целостности и с мертвой ссылкой
+	SuccessfulOnly bool
+}
+
+// Successful сообщает, что загрузка прошла проверку целостности (или не проверялась) и ссылка не мертва
+func (e Entry) Successful() bool {
+	return e.Integrity != upload.VerifyMismatch && e.Link != upload.LinkDead
+}
+
+// keep возвращает записи, оставшиеся по правилам r на момент now; порядок сохраняется
+func (r Retention) keep(entries []Entry, now time.Time) []Entry {
+	var kept []Entry
+	for _, e := range entries {
+		if r.MaxEntries > 0 && len(kept) == r.MaxEntries {
+			break
+		}
+		if r.MaxAge > 0 && now.Sub(e.UploadedAt) > r.MaxAge {
+			continue
+		}
+		if r.SuccessfulOnly && !e.Successful() {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
+// SetRetention задает правила очистки и сразу удаляет лишние записи
+// Правила применяются и при каждом добавлении записи
+func (s *Store) SetRetention(r Retention) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.retention = r
+	return s.prune()
+}
+
+// Clear удаляет все записи истории
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = nil
+	return s.save()
+}
+
+// prune удаляет записи, не подходящие под правила очистки, и сохраняет файл, если что-то удалено
+func (s *Store) prune() error {
+	kept := s.retention.keep(s.entries, time.Now())
+	if len(kept) == len(s.entries) {
+		return nil
+	}
+	s.entries = kept
+	return s.save()
+}
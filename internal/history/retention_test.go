@@ -0,0 +1,89 @@
+package history
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"multiUploader/internal/upload"
+)
+
+// TestRetentionKeep проверяет отбор записей по числу, возрасту и успешности
+func TestRetentionKeep(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	entries := []Entry{
+		{ID: "new", UploadedAt: now.Add(-time.Hour)},
+		{ID: "dead", UploadedAt: now.Add(-2 * day), Link: upload.LinkDead},
+		{ID: "mismatch", UploadedAt: now.Add(-3 * day), Integrity: upload.VerifyMismatch},
+		{ID: "month", UploadedAt: now.Add(-31 * day), Integrity: upload.VerifyOK},
+		{ID: "year", UploadedAt: now.Add(-365 * day), Link: upload.LinkAlive},
+	}
+
+	tests := []struct {
+		name      string
+		retention Retention
+		want      []string
+	}{
+		{"keep all", Retention{}, []string{"new", "dead", "mismatch", "month", "year"}},
+		{"max entries", Retention{MaxEntries: 2}, []string{"new", "dead"}},
+		{"max age", Retention{MaxAge: 30 * day}, []string{"new", "dead", "mismatch"}},
+		{"successful only", Retention{SuccessfulOnly: true}, []string{"new", "month", "year"}},
+		{"combined", Retention{MaxEntries: 2, MaxAge: 60 * day, SuccessfulOnly: true}, []string{"new", "month"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range tt.retention.keep(entries, now) {
+				got = append(got, e.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("keep() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStoreRetention проверяет очистку при смене правил и добавлении записей, а также полную очистку
+func TestStoreRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), fileName)
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.bin", "b.bin", "c.bin"} {
+		if _, err := s.Add(Entry{FileName: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.SetRetention(Retention{MaxEntries: 2}); err != nil {
+		t.Fatalf("SetRetention() error = %v", err)
+	}
+	if _, err := s.Add(Entry{FileName: "d.bin"}); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range reopened.Entries() {
+		names = append(names, e.FileName)
+	}
+	if want := []string{"d.bin", "c.bin"}; !slices.Equal(names, want) {
+		t.Errorf("entries after pruning = %v, want %v", names, want)
+	}
+
+	if err := s.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if reopened, err = Open(path); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(reopened.Entries()); n != 0 {
+		t.Errorf("history has %d entries after Clear()", n)
+	}
+}
@@ -0,0 +1,55 @@
+package history
+
+import (
+	"slices"
+	"strings"
+)
+
+// ParseTags разбирает метки, введенные через запятую: пробелы по краям убираются,
+// пустые метки и повторы (без учета регистра) отбрасываются, порядок сохраняется
+func ParseTags(text string) []string {
+	var tags []string
+	for _, tag := range strings.Split(text, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !hasTag(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// Tags возвращает все метки записей по алфавиту, без повторов
+// Из меток, отличающихся только регистром, берется первая встреченная
+func Tags(entries []Entry) []string {
+	var tags []string
+	for _, e := range entries {
+		for _, tag := range e.Tags {
+			if !hasTag(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	slices.SortFunc(tags, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return tags
+}
+
+// FilterTag возвращает записи с меткой tag (без учета регистра); пустая метка возвращает все записи
+func FilterTag(entries []Entry, tag string) []Entry {
+	if tag == "" {
+		return entries
+	}
+	var filtered []Entry
+	for _, e := range entries {
+		if hasTag(e.Tags, tag) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// hasTag сообщает, что среди tags есть tag без учета регистра
+func hasTag(tags []string, tag string) bool {
+	return slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
@@ -0,0 +1,37 @@
+package history
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestParseTags проверяет разбор меток через запятую
+func TestParseTags(t *testing.T) {
+	for text, want := range map[string][]string{
+		"":                          nil,
+		" , ,":                      nil,
+		"client, march":             {"client", "march"},
+		" Client ,client,CLIENT,x ": {"Client", "x"},
+	} {
+		if got := ParseTags(text); !slices.Equal(got, want) {
+			t.Errorf("ParseTags(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+// TestTags проверяет список меток и отбор записей по метке
+func TestTags(t *testing.T) {
+	entries := []Entry{
+		{ID: "1", Tags: []string{"march", "Client"}},
+		{ID: "2", Tags: []string{"client"}},
+		{ID: "3"},
+	}
+	if got, want := Tags(entries), []string{"Client", "march"}; !slices.Equal(got, want) {
+		t.Errorf("Tags() = %q, want %q", got, want)
+	}
+	for tag, want := range map[string]int{"": 3, "CLIENT": 2, "march": 1, "april": 0} {
+		if got := FilterTag(entries, tag); len(got) != want {
+			t.Errorf("FilterTag(%q) returned %d entries, want %d", tag, len(got), want)
+		}
+	}
+}
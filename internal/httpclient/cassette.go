@@ -0,0 +1,307 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// maxCassetteBody максимальный размер сохраняемого тела запроса или ответа
+	// Тела файлов не сохраняются - только их размер
+	maxCassetteBody = 64 * 1024
+	// redacted значение, которым заменяются секреты
+	redacted = "REDACTED"
+)
+
+// secretParams параметры запроса, значения которых не попадают в кассету
+var secretParams = []string{"key", "api_key", "api_token", "token", "sess_id"}
+
+// skippedHeaders заголовки ответа, которые не сохраняются в кассету
+var skippedHeaders = []string{"Set-Cookie", "Date", "Cf-Ray", "Report-To", "Nel"}
+
+// recording кассета, в которую записываются запросы shared клиентов (nil - запись выключена)
+var recording atomic.Pointer[Cassette]
+
+// RecordedRequest запрос в кассете
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"` // секретные параметры заменены на REDACTED
+	// Body тело JSON или формы (секретные поля формы скрыты); тела файлов не сохраняются
+	Body string `json:"body,omitempty"`
+	Size int64  `json:"size,omitempty"`
+}
+
+// RecordedResponse ответ в кассете
+type RecordedResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   string            `json:"body,omitempty"`
+}
+
+// Interaction записанная пара запрос/ответ
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// Cassette записанные HTTP взаимодействия для воспроизведения в тестах
+// При воспроизведении запросы сопоставляются по методу, URL без секретов, телу (JSON и формы) и порядку
+type Cassette struct {
+	mu sync.Mutex
+	// Source откуда взяты запросы: "recorded" - записаны с живого сервиса, иное - описание источника
+	Source       string        `json:"source,omitempty"`
+	Interactions []Interaction `json:"interactions"`
+	used         []bool
+}
+
+// LoadCassette читает кассету из файла
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save записывает кассету в файл
+func (c *Cassette) Save(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// StartRecording записывает запросы всех shared клиентов в кассету
+func StartRecording(c *Cassette) {
+	recording.Store(c)
+}
+
+// StopRecording останавливает запись
+func StopRecording() {
+	recording.Store(nil)
+}
+
+// Handler возвращает обработчик, отвечающий записанными ответами
+// Используется с EnableDryRun; на незаписанный запрос отвечает 501 с описанием запроса
+func (c *Cassette) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := redactURL(r.URL)
+		body, err := textBody(r)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("cassette: failed to read request body: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		c.mu.Lock()
+		if len(c.used) != len(c.Interactions) {
+			c.used = make([]bool, len(c.Interactions))
+		}
+		var found *Interaction
+		for i := range c.Interactions {
+			recorded := c.Interactions[i].Request
+			if !c.used[i] && recorded.Method == r.Method && recorded.URL == key && sameBody(recorded.Body, body) {
+				c.used[i] = true
+				found = &c.Interactions[i]
+				break
+			}
+		}
+		c.mu.Unlock()
+
+		if found == nil {
+			http.Error(w, fmt.Sprintf("cassette: no recorded interaction for %s %s %s", r.Method, key, body), http.StatusNotImplemented)
+			return
+		}
+
+		for name, value := range found.Response.Header {
+			w.Header().Set(name, value)
+		}
+		w.WriteHeader(found.Response.Status)
+		io.WriteString(w, found.Response.Body)
+	})
+}
+
+// Unused возвращает записанные запросы, которые не были воспроизведены
+// Непустой список значит, что провайдер перестал выполнять часть протокола
+func (c *Cassette) Unused() []RecordedRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var unused []RecordedRequest
+	for i, interaction := range c.Interactions {
+		if i >= len(c.used) || !c.used[i] {
+			unused = append(unused, interaction.Request)
+		}
+	}
+	return unused
+}
+
+// record выполняет запрос через next и сохраняет взаимодействие
+func (c *Cassette) record(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	recorded := RecordedRequest{Method: req.Method, URL: redactURL(req.URL)}
+
+	// Тело JSON и формы сохраняем, у остальных (файлы) - только размер
+	var counter *countingBody
+	if req.Body != nil && req.Body != http.NoBody {
+		if isTextBody(req) {
+			body, err := textBody(req)
+			if err != nil {
+				return nil, err
+			}
+			recorded.Body = body
+			recorded.Size = req.ContentLength
+		} else {
+			counter = &countingBody{ReadCloser: req.Body}
+			req.Body = counter
+		}
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if counter != nil {
+		recorded.Size = counter.n.Load()
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCassetteBody))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.mu.Lock()
+	c.Interactions = append(c.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+			Header: recordHeader(resp.Header),
+			Body:   string(body),
+		},
+	})
+	c.mu.Unlock()
+
+	return resp, nil
+}
+
+// isTextBody сообщает, что тело запроса - JSON или форма, а не содержимое файла
+func isTextBody(req *http.Request) bool {
+	contentType := req.Header.Get("Content-Type")
+	return strings.HasPrefix(contentType, "application/json") || strings.HasPrefix(contentType, "application/x-www-form-urlencoded")
+}
+
+// textBody читает тело JSON или формы для кассеты и возвращает его запросу
+// Секретные поля формы скрываются так же, как параметры URL; для тел файлов возвращает ""
+func textBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody || !isTextBody(req) {
+		return "", nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return string(body), nil
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return string(body), nil
+	}
+	for _, name := range secretParams {
+		if form.Has(name) {
+			form.Set(name, redacted)
+		}
+	}
+	return form.Encode(), nil
+}
+
+// sameBody сравнивает записанное тело с телом запроса; JSON сравнивается без учета порядка полей и отступов
+func sameBody(recorded, actual string) bool {
+	if recorded == actual {
+		return true
+	}
+	var want, got any
+	if json.Unmarshal([]byte(recorded), &want) != nil || json.Unmarshal([]byte(actual), &got) != nil {
+		return false
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+// botTokenPath токен Telegram бота в пути запроса
+var botTokenPath = regexp.MustCompile(`/bot\d+:[^/]+/`)
+
+// redactURL возвращает URL с отсортированными параметрами и скрытыми секретами
+func redactURL(u *url.URL) string {
+	clean := *u
+	query := clean.Query()
+	for _, name := range secretParams {
+		if query.Has(name) {
+			query.Set(name, redacted)
+		}
+	}
+	clean.RawQuery = query.Encode()
+	clean.User = nil
+	// Telegram передает токен бота в пути: /bot<token>/method
+	clean.Path = botTokenPath.ReplaceAllString(clean.Path, "/bot"+redacted+"/")
+	clean.RawPath = ""
+	return clean.String()
+}
+
+// recordHeader оставляет заголовки ответа, которые нужны провайдерам (ETag, Content-Type, ...)
+func recordHeader(header http.Header) map[string]string {
+	recorded := make(map[string]string)
+	for name := range header {
+		if !containsFold(skippedHeaders, name) {
+			recorded[name] = header.Get(name)
+		}
+	}
+	if len(recorded) == 0 {
+		return nil
+	}
+	return recorded
+}
+
+// containsFold ищет строку без учета регистра
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// countingBody считает прочитанные байты тела запроса
+type countingBody struct {
+	io.ReadCloser
+	n atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n.Add(int64(n))
+	return n, err
+}
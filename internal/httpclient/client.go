@@ -23,9 +23,11 @@ type Client struct {
 
 // ClientConfig конфигурация для HTTP клиента
 type ClientConfig struct {
-	Timeout    time.Duration // Таймаут для запроса
+	Timeout    time.Duration // Таймаут для запроса (0 - без ограничения общей длительности)
 	MaxRetries int           // Максимальное количество попыток
 	MaxElapsed time.Duration // Максимальное время на все попытки
+	// IdleTimeout прерывает запрос, если данные не передаются дольше (0 - выключено)
+	IdleTimeout time.Duration
 }
 
 // DefaultConfig возвращает стандартную конфигурацию
@@ -38,11 +40,21 @@ func DefaultConfig() *ClientConfig {
 }
 
 // LongLivedConfig конфигурация для длительных операций (uploads)
+// Общая длительность не ограничена: медленная загрузка большой части прерывается, только если данные перестали идти
 func LongLivedConfig() *ClientConfig {
 	return &ClientConfig{
-		Timeout:    10 * time.Minute,
-		MaxRetries: 3,
-		MaxElapsed: 30 * time.Minute,
+		MaxRetries:  3,
+		MaxElapsed:  30 * time.Minute,
+		IdleTimeout: 2 * time.Minute,
+	}
+}
+
+// ProbeConfig конфигурация для проверки доступности серверов: быстрый ответ без повторов
+func ProbeConfig() *ClientConfig {
+	return &ClientConfig{
+		Timeout:    10 * time.Second,
+		MaxRetries: 0,
+		MaxElapsed: 10 * time.Second,
 	}
 }
 
@@ -52,26 +64,36 @@ func NewClient(config *ClientConfig) *Client {
 		config = DefaultConfig()
 	}
 
+	// Таймауты для установки соединения
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
 	// Настраиваем Transport для connection pooling
 	transport := &http.Transport{
 		// Connection pooling settings
 		MaxIdleConns:        100,              // Максимум idle connections
 		MaxIdleConnsPerHost: 10,               // Максимум idle connections на хост
 		IdleConnTimeout:     90 * time.Second, // Время жизни idle connection
-		// Таймауты для установки соединения
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		// Таймауты для TLS
-		TLSHandshakeTimeout:   10 * time.Second,
+		DialContext:         dialer.DialContext,
+		// TLS с дополнительными CA и хостами без проверки (см. tls.go)
+		DialTLSContext:        dialTLS(dialer),
 		ResponseHeaderTimeout: 10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
+		// Тело запроса пишется в соединение блоками по 64 КБ вместо 4 КБ:
+		// меньше системных вызовов и TLS записей на многогигабайтных загрузках
+		WriteBufferSize: 64 << 10,
+	}
+
+	var next http.RoundTripper = transport
+	if config.IdleTimeout > 0 {
+		next = &idleTransport{next: transport, timeout: config.IdleTimeout}
 	}
 
 	return &Client{
 		httpClient: &http.Client{
-			Transport: transport,
+			Transport: &hookTransport{next: next},
 			Timeout:   config.Timeout,
 		},
 		maxRetries: config.MaxRetries,
@@ -81,12 +103,16 @@ func NewClient(config *ClientConfig) *Client {
 
 // Do выполняет HTTP запрос с retry логикой
 // Retry применяется только для идемпотентных методов (GET, PUT) и временных ошибок
+// Запрос с телом повторяется, только если задан GetBody: иначе повтор отправил бы уже прочитанное (пустое) тело
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	// Проверяем, нужен ли retry для этого метода
 	if !isIdempotent(req.Method) {
 		// Для неидемпотентных методов (POST, PATCH, DELETE) не делаем retry
 		return c.httpClient.Do(req)
 	}
+	if !canRewindBody(req) {
+		return c.httpClient.Do(req)
+	}
 
 	// Создаем exponential backoff
 	b := backoff.NewExponentialBackOff()
@@ -146,6 +172,12 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	return resp, nil
 }
 
+// canRewindBody проверяет, можно ли отправить тело запроса повторно
+// http.NewRequest задает GetBody для bytes.Reader, bytes.Buffer и strings.Reader
+func canRewindBody(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
 // isIdempotent проверяет, является ли HTTP метод идемпотентным
 func isIdempotent(method string) bool {
 	switch method {
@@ -156,6 +188,12 @@ func isIdempotent(method string) bool {
 	}
 }
 
+// IsNetworkError сообщает, что запрос прервался из-за сети (таймаут, DNS, разрыв соединения),
+// а не из-за ответа сервера
+func IsNetworkError(err error) bool {
+	return isTemporaryError(err)
+}
+
 // isTemporaryError проверяет, является ли ошибка временной (стоит retry)
 func isTemporaryError(err error) bool {
 	if err == nil {
@@ -0,0 +1,58 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRetryResendsBody проверяет, что повтор отправляет тело заново, а тело без GetBody не повторяется
+func TestRetryResendsBody(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{MaxRetries: 2, MaxElapsed: 10 * time.Second})
+	put := func(body io.Reader) int {
+		t.Helper()
+		mu.Lock()
+		bodies = nil
+		mu.Unlock()
+		req, _ := http.NewRequest(http.MethodPut, server.URL, body)
+		req.ContentLength = 4
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := put(strings.NewReader("data")); status != http.StatusOK {
+		t.Fatalf("status = %d, want 200 after retry", status)
+	}
+	if len(bodies) != 2 || bodies[1] != "data" {
+		t.Errorf("server got bodies %q, want the body twice", bodies)
+	}
+
+	// MultiReader не дает http.NewRequest задать GetBody
+	if status := put(io.MultiReader(strings.NewReader("data"))); status != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 without retry", status)
+	}
+	if len(bodies) != 1 {
+		t.Errorf("server got %d requests, want 1 for a body without GetBody", len(bodies))
+	}
+}
@@ -0,0 +1,148 @@
+package httpclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// dryRunBodyLimit сколько байт тела запроса передается обработчику dry run
+	// (остальное только читается - содержимое файлов обработчику не нужно)
+	dryRunBodyLimit = 64 * 1024
+	// maxDryRunLog сколько последних запросов хранится в журнале dry run
+	maxDryRunLog = 200
+)
+
+// DryRunTransaction запрос, на который ответил обработчик dry run вместо сервера
+type DryRunTransaction struct {
+	Method string
+	URL    string // без query, чтобы в журнал не попадали API ключи
+	Sent   int64  // размер тела запроса
+	Status int
+}
+
+// dryRunState обработчик dry run и журнал запросов
+type dryRunState struct {
+	handler http.Handler
+
+	mu  sync.Mutex
+	log []DryRunTransaction
+}
+
+// dryRun текущий режим dry run (nil - запросы идут в сеть)
+var dryRun atomic.Pointer[dryRunState]
+
+// EnableDryRun направляет запросы всех клиентов в handler вместо сети
+// Тела запросов читаются полностью, поэтому провайдеры проходят весь путь загрузки с прогрессом
+func EnableDryRun(handler http.Handler) {
+	dryRun.Store(&dryRunState{handler: handler})
+}
+
+// DisableDryRun возвращает отправку запросов в сеть
+func DisableDryRun() {
+	dryRun.Store(nil)
+}
+
+// DryRunEnabled сообщает, включен ли dry run
+func DryRunEnabled() bool {
+	return dryRun.Load() != nil
+}
+
+// DryRunLog возвращает последние запросы, обработанные в режиме dry run
+func DryRunLog() []DryRunTransaction {
+	state := dryRun.Load()
+	if state == nil {
+		return nil
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return append([]DryRunTransaction(nil), state.log...)
+}
+
+// hookTransport отвечает через обработчик dry run, если он включен,
+// иначе передает запрос в сеть (и записывает его, если идет запись кассеты)
+type hookTransport struct {
+	next http.RoundTripper
+}
+
+func (t *hookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = applyHeaders(req)
+	req, err := applySigning(req)
+	if err != nil {
+		return nil, err
+	}
+	span := startRequestSpan(req)
+	resp, err := t.roundTrip(req)
+	endRequestSpan(span, resp, err)
+	return resp, err
+}
+
+func (t *hookTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	if state := dryRun.Load(); state != nil {
+		return state.serve(req)
+	}
+	if cassette := recording.Load(); cassette != nil {
+		return cassette.record(t.next, req)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// serve выполняет запрос обработчиком через httptest.ResponseRecorder
+func (s *dryRunState) serve(req *http.Request) (*http.Response, error) {
+	var head []byte
+	var sent int64
+	if req.Body != nil {
+		defer req.Body.Close()
+
+		var err error
+		head, err = io.ReadAll(io.LimitReader(req.Body, dryRunBodyLimit))
+		if err != nil {
+			return nil, err
+		}
+		rest, err := io.Copy(io.Discard, req.Body)
+		if err != nil {
+			return nil, err
+		}
+		sent = int64(len(head)) + rest
+	}
+
+	// Как и настоящий транспорт, отмененный запрос не получает ответа
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	inner := req.Clone(req.Context())
+	inner.Body = io.NopCloser(bytes.NewReader(head))
+	inner.ContentLength = sent
+	// ServeMux выбирает обработчик по Host, у клиентских запросов он часто пустой
+	inner.Host = req.URL.Host
+
+	recorder := httptest.NewRecorder()
+	s.handler.ServeHTTP(recorder, inner)
+	resp := recorder.Result()
+	resp.Request = req
+
+	s.record(DryRunTransaction{
+		Method: req.Method,
+		URL:    req.URL.Scheme + "://" + req.URL.Host + req.URL.Path,
+		Sent:   sent,
+		Status: resp.StatusCode,
+	})
+	return resp, nil
+}
+
+// record добавляет запрос в журнал, вытесняя самые старые
+func (s *dryRunState) record(tx DryRunTransaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.log = append(s.log, tx)
+	if len(s.log) > maxDryRunLog {
+		s.log = s.log[len(s.log)-maxDryRunLog:]
+	}
+}
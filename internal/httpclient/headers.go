@@ -0,0 +1,111 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	// userAgent User-Agent всех запросов ("" - по умолчанию Go)
+	userAgent atomic.Pointer[string]
+	// providerHeaders дополнительные заголовки запросов по имени провайдера
+	providerHeaders atomic.Pointer[map[string]http.Header]
+)
+
+// reservedHeaders заголовки, которые задает сам транспорт: их замена ломает запрос
+var reservedHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+}
+
+// providerKey ключ имени провайдера в контексте запроса
+type providerKey struct{}
+
+// SetUserAgent задает User-Agent всех запросов ("" - по умолчанию Go)
+func SetUserAgent(ua string) {
+	userAgent.Store(&ua)
+}
+
+// SetProviderHeaders задает дополнительные заголовки по имени провайдера
+// Они добавляются к запросам, контекст которых помечен WithProvider
+func SetProviderHeaders(headers map[string]http.Header) {
+	providerHeaders.Store(&headers)
+}
+
+// WithProvider помечает контекст запросов провайдера name
+// Пустое name снимает пометку (например, для скачивания с чужого адреса внутри загрузки)
+func WithProvider(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, providerKey{}, name)
+}
+
+// ParseHeaders разбирает заголовки из настроек: по одному "Имя: значение" на строку
+func ParseHeaders(text string) (http.Header, error) {
+	headers := make(http.Header)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || !validHeaderName(name) {
+			return nil, fmt.Errorf("invalid header line %q, expected \"Name: value\"", line)
+		}
+		name = textproto.CanonicalMIMEHeaderKey(name)
+		if reservedHeaders[name] {
+			return nil, fmt.Errorf("header %s cannot be changed", name)
+		}
+		headers.Add(name, strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+// validHeaderName проверяет, что имя состоит из символов token (RFC 9110)
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// applyHeaders возвращает запрос с User-Agent и заголовками провайдера из настроек
+// Исходный запрос не меняется (требование http.RoundTripper); заголовки провайдера
+// заменяют одноименные заголовки запроса
+func applyHeaders(req *http.Request) *http.Request {
+	var ua string
+	if p := userAgent.Load(); p != nil {
+		ua = *p
+	}
+	var extra http.Header
+	if name, _ := req.Context().Value(providerKey{}).(string); name != "" {
+		if p := providerHeaders.Load(); p != nil {
+			extra = (*p)[name]
+		}
+	}
+	if ua == "" && len(extra) == 0 {
+		return req
+	}
+
+	req = req.Clone(req.Context())
+	if ua != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", ua)
+	}
+	for name, values := range extra {
+		req.Header[name] = values
+	}
+	return req
+}
@@ -0,0 +1,66 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders("x-api-client: multiUploader\n\n  Referer: https://example.com/  \n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := headers.Get("X-Api-Client"); got != "multiUploader" {
+		t.Errorf("X-Api-Client = %q", got)
+	}
+	if got := headers.Get("Referer"); got != "https://example.com/" {
+		t.Errorf("Referer = %q", got)
+	}
+
+	for _, text := range []string{"no colon", "Bad Name: value", ": value", "Content-Length: 5"} {
+		if _, err := ParseHeaders(text); err == nil {
+			t.Errorf("ParseHeaders(%q) accepted invalid header", text)
+		}
+	}
+}
+
+// TestApplyHeaders проверяет, что заголовки провайдера уходят только в запросы его контекста
+func TestApplyHeaders(t *testing.T) {
+	got := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got <- r.Header.Clone()
+	}))
+	defer server.Close()
+	defer SetUserAgent("")
+	defer SetProviderHeaders(nil)
+
+	SetUserAgent("multiUploader/test")
+	SetProviderHeaders(map[string]http.Header{"Rootz": {"X-Api-Client": {"uploader"}}})
+	client := NewClient(&ClientConfig{MaxRetries: 0})
+
+	do := func(ctx context.Context) http.Header {
+		t.Helper()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return <-got
+	}
+
+	headers := do(WithProvider(context.Background(), "Rootz"))
+	if headers.Get("User-Agent") != "multiUploader/test" || headers.Get("X-Api-Client") != "uploader" {
+		t.Errorf("provider request headers: %v", headers)
+	}
+
+	headers = do(WithProvider(context.Background(), "AkiraBox"))
+	if headers.Get("X-Api-Client") != "" {
+		t.Error("headers of one provider sent with another provider's request")
+	}
+	if headers.Get("User-Agent") != "multiUploader/test" {
+		t.Errorf("User-Agent = %q", headers.Get("User-Agent"))
+	}
+}
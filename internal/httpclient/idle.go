@@ -0,0 +1,133 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// IdleTimeoutError запрос прерван: данные не передавались дольше Idle
+// Считается сетевым таймаутом, поэтому httpclient повторяет такой запрос
+type IdleTimeoutError struct {
+	Idle time.Duration
+}
+
+func (e *IdleTimeoutError) Error() string {
+	return fmt.Sprintf("no data transferred for %s", e.Idle)
+}
+
+// Timeout реализует net.Error
+func (e *IdleTimeoutError) Timeout() bool { return true }
+
+// Temporary реализует net.Error
+func (e *IdleTimeoutError) Temporary() bool { return true }
+
+// idleTransport прерывает запрос, если ни тело запроса, ни тело ответа не передаются дольше timeout
+// В отличие от http.Client.Timeout не ограничивает общую длительность: медленная, но идущая загрузка не обрывается
+type idleTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *idleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancelCause(req.Context())
+	w := &idleWatch{ctx: ctx, timeout: t.timeout, cancel: cancel, done: make(chan struct{})}
+	w.touch()
+
+	req = req.WithContext(ctx)
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = &activityBody{ReadCloser: req.Body, watch: w}
+	}
+	go w.run()
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		w.stop()
+		return nil, w.translate(err)
+	}
+	// Наблюдение продолжается, пока читается тело ответа
+	resp.Body = &activityBody{ReadCloser: resp.Body, watch: w, closeStops: true}
+	return resp, nil
+}
+
+// idleWatch отменяет запрос, если активность не отмечалась дольше timeout
+type idleWatch struct {
+	ctx     context.Context
+	timeout time.Duration
+	cancel  context.CancelCauseFunc
+	last    atomic.Int64
+	done    chan struct{}
+	stopped atomic.Bool
+}
+
+func (w *idleWatch) touch() {
+	w.last.Store(time.Now().UnixNano())
+}
+
+func (w *idleWatch) run() {
+	ticker := time.NewTicker(w.timeout / 10)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			if time.Since(time.Unix(0, w.last.Load())) >= w.timeout {
+				w.cancel(&IdleTimeoutError{Idle: w.timeout})
+				return
+			}
+		}
+	}
+}
+
+// stop завершает наблюдение (повторные вызовы ничего не делают)
+func (w *idleWatch) stop() {
+	if w.stopped.CompareAndSwap(false, true) {
+		close(w.done)
+		w.cancel(nil)
+	}
+}
+
+// translate заменяет ошибку отмены на IdleTimeoutError, если запрос прервало наблюдение
+func (w *idleWatch) translate(err error) error {
+	if err == nil {
+		return nil
+	}
+	if idle, ok := context.Cause(w.ctx).(*IdleTimeoutError); ok {
+		return idle
+	}
+	return err
+}
+
+// activityBody отмечает активность при каждом чтении
+// closeStops - тело ответа: его закрытие завершает запрос и наблюдение
+type activityBody struct {
+	io.ReadCloser
+	watch      *idleWatch
+	closeStops bool
+}
+
+func (b *activityBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.watch.touch()
+	}
+	if err != nil && err != io.EOF {
+		err = b.watch.translate(err)
+	}
+	return n, err
+}
+
+func (b *activityBody) Close() error {
+	err := b.ReadCloser.Close()
+	if b.closeStops {
+		b.watch.stop()
+	}
+	return err
+}
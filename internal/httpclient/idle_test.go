@@ -0,0 +1,67 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestIdleTimeout проверяет, что прерывается только запрос без передачи данных, а не долгий
+func TestIdleTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			// Ответ идет дольше таймаута, но без пауз больше него
+			for i := 0; i < 10; i++ {
+				w.Write([]byte("x"))
+				w.(http.Flusher).Flush()
+				time.Sleep(50 * time.Millisecond)
+			}
+		case "/stalled":
+			w.Write([]byte("x"))
+			w.(http.Flusher).Flush()
+			<-release
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(&ClientConfig{MaxRetries: 0, IdleTimeout: 200 * time.Millisecond})
+
+	resp, err := client.Do(mustRequest(t, server.URL+"/slow"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || len(body) != 10 {
+		t.Fatalf("slow response: read %d bytes, err = %v", len(body), err)
+	}
+
+	resp, err = client.Do(mustRequest(t, server.URL+"/stalled"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	var idle *IdleTimeoutError
+	if !errors.As(err, &idle) {
+		t.Fatalf("stalled response: err = %v, want IdleTimeoutError", err)
+	}
+	if !IsNetworkError(err) {
+		t.Error("IdleTimeoutError should count as a network error")
+	}
+}
+
+func mustRequest(t *testing.T, target string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
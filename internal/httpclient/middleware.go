@@ -0,0 +1,120 @@
+package httpclient
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"multiUploader/internal/logging"
+)
+
+// Middleware оборачивает отправку запроса: авторизация, журнал, ограничение частоты, метрики
+// Вызывается для каждой попытки, включая повторы Do
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripFunc позволяет использовать функцию как http.RoundTripper
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// With возвращает клиент с теми же таймаутами, повторами и пулом соединений,
+// запросы которого проходят через middleware
+// Первый middleware внешний: он первым видит запрос и последним - ответ
+func (c *Client) With(middleware ...Middleware) *Client {
+	transport := c.httpClient.Transport
+	for i := len(middleware) - 1; i >= 0; i-- {
+		transport = middleware[i](transport)
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	return &Client{
+		httpClient: &httpClient,
+		maxRetries: c.maxRetries,
+		maxElapsed: c.maxElapsed,
+	}
+}
+
+// SetHeader задает заголовок каждого запроса ("" - заголовок не меняется)
+func SetHeader(name, value string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if value == "" {
+			return next
+		}
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			// http.RoundTripper не должен менять исходный запрос
+			req = req.Clone(req.Context())
+			req.Header.Set(name, value)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// BearerAuth авторизует запросы токеном ("" - без авторизации)
+func BearerAuth(token string) Middleware {
+	if token != "" {
+		token = "Bearer " + token
+	}
+	return SetHeader("Authorization", token)
+}
+
+// RateLimit выдерживает не меньше interval между началом запросов
+// Ограничение общее для всех клиентов, созданных с этим middleware
+func RateLimit(interval time.Duration) Middleware {
+	var mu sync.Mutex
+	var next time.Time
+
+	return func(transport http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			now := time.Now()
+			start := next
+			if start.Before(now) {
+				start = now
+			}
+			next = start.Add(interval)
+			mu.Unlock()
+
+			if wait := time.Until(start); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-req.Context().Done():
+					timer.Stop()
+					return nil, req.Context().Err()
+				case <-timer.C:
+				}
+			}
+			return transport.RoundTrip(req)
+		})
+	}
+}
+
+// Observe вызывает fn после каждого запроса с его результатом и длительностью (для метрик)
+// Длительность - до получения заголовков ответа, без чтения тела
+func Observe(fn func(req *http.Request, resp *http.Response, err error, elapsed time.Duration)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			fn(req, resp, err, time.Since(start))
+			return resp, err
+		})
+	}
+}
+
+// LogFailures пишет в журнал неудачные запросы: сетевые ошибки и ответы 4xx/5xx
+// В журнал попадают метод, хост и путь - без query, где бывают API ключи
+func LogFailures(provider string) Middleware {
+	return Observe(func(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+		switch {
+		case err != nil:
+			logging.ErrorWithError("Request failed", err,
+				"provider", provider, "method", req.Method, "host", req.URL.Host, "path", req.URL.Path, "elapsed", elapsed)
+		case resp.StatusCode >= 400:
+			logging.Error("Request returned error status",
+				"provider", provider, "method", req.Method, "host", req.URL.Host, "path", req.URL.Path, "status", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestWithMiddleware проверяет порядок middleware и то, что они видят каждую попытку повтора
+func TestWithMiddleware(t *testing.T) {
+	var attempts int
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		auth = append(auth, r.Header.Get("Authorization"))
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	base := NewClient(&ClientConfig{Timeout: 5 * time.Second, MaxRetries: 2, MaxElapsed: 10 * time.Second})
+	client := base.With(trace("outer"), BearerAuth("token"), trace("inner"))
+
+	req := mustRequest(t, server.URL)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if want := []string{"outer", "inner", "outer", "inner"}; !slices.Equal(order, want) {
+		t.Errorf("middleware order = %v, want %v", order, want)
+	}
+	if want := []string{"Bearer token", "Bearer token"}; !slices.Equal(auth, want) {
+		t.Errorf("Authorization = %q, want %q", auth, want)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("middleware changed the original request")
+	}
+
+	// Исходный клиент middleware не получает
+	order = nil
+	resp, err = base.Do(mustRequest(t, server.URL))
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if len(order) != 0 || auth[len(auth)-1] != "" {
+		t.Errorf("base client ran middleware: %v", order)
+	}
+}
+
+// TestRateLimit проверяет интервал между запросами и отмену ожидания
+func TestRateLimit(t *testing.T) {
+	var starts []time.Time
+	limited := RateLimit(50 * time.Millisecond)(RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		starts = append(starts, time.Now())
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+
+	for range 3 {
+		if _, err := limited.RoundTrip(mustRequest(t, "http://example.invalid")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 1; i < len(starts); i++ {
+		if gap := starts[i].Sub(starts[i-1]); gap < 40*time.Millisecond {
+			t.Errorf("gap between requests %d and %d = %v", i-1, i, gap)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := limited.RoundTrip(mustRequest(t, "http://example.invalid").WithContext(ctx)); err == nil {
+		t.Error("RoundTrip() waited despite cancelled context")
+	}
+}
@@ -8,6 +8,8 @@ var (
 	longLivedClient   *Client
 	defaultClientOnce sync.Once
 	longLivedOnce     sync.Once
+	probeClient       *Client
+	probeOnce         sync.Once
 )
 
 // Default возвращает shared HTTP клиент для обычных запросов
@@ -20,10 +22,19 @@ func Default() *Client {
 }
 
 // LongLived возвращает shared HTTP клиент для длительных операций (uploads)
-// Timeout: 10 минут, MaxRetries: 3, MaxElapsed: 30 минут
+// Без общего таймаута, обрыв после 2 минут без передачи данных, MaxRetries: 3, MaxElapsed: 30 минут
 func LongLived() *Client {
 	longLivedOnce.Do(func() {
 		longLivedClient = NewClient(LongLivedConfig())
 	})
 	return longLivedClient
 }
+
+// Probe возвращает shared HTTP клиент для проверки доступности серверов
+// Timeout: 10 секунд, без повторов
+func Probe() *Client {
+	probeOnce.Do(func() {
+		probeClient = NewClient(ProbeConfig())
+	})
+	return probeClient
+}
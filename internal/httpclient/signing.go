@@ -0,0 +1,179 @@
+package httpclient
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// unsignedPayload значение хеша тела, которое не подписывается (поток файла, большое тело)
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	// signedBodyLimit наибольшее тело, которое хешируется для подписи: загрузку файла целиком
+	// пришлось бы прочитать дважды
+	signedBodyLimit = 1 << 20 // 1MB
+)
+
+// providerSigners подписи запросов по имени провайдера
+var providerSigners atomic.Pointer[map[string]*Signer]
+
+// Signer подписывает запросы HMAC для API, которые проверяют подпись каждого запроса
+// (собственные хранилища компаний, нативные API хранилищ)
+//
+// Подписывается строка из метода, пути с запросом, времени и SHA-256 тела, по одной на строке:
+//
+//	PUT
+//	/v1/files/report.pdf?part=2
+//	1767225600
+//	e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855
+//
+// Время (Unix, секунды) уходит в TimestampHeader, хеш тела - в ContentHashHeader,
+// подпись в hex - в SignatureHeader, идентификатор ключа - в KeyIDHeader.
+// Тело больше signedBodyLimit или без GetBody не хешируется: вместо хеша стоит UNSIGNED-PAYLOAD
+type Signer struct {
+	// KeyID идентификатор ключа ("" - заголовок не отправляется)
+	KeyID string
+	// Secret секрет HMAC
+	Secret string
+	// Algorithm "sha256" или "sha512" ("" - sha256)
+	Algorithm string
+
+	// Заголовки подписи ("" - X-Timestamp, X-Content-Sha256, X-Signature, X-Key-Id)
+	TimestampHeader   string
+	ContentHashHeader string
+	SignatureHeader   string
+	KeyIDHeader       string
+
+	// now текущее время (переопределяется в тестах)
+	now func() time.Time
+}
+
+// Validate проверяет секрет и алгоритм подписи
+func (s *Signer) Validate() error {
+	if s.Secret == "" {
+		return fmt.Errorf("signing secret is empty")
+	}
+	if _, err := s.hash(); err != nil {
+		return err
+	}
+	for _, name := range []string{s.TimestampHeader, s.ContentHashHeader, s.SignatureHeader, s.KeyIDHeader} {
+		if name != "" && !validHeaderName(name) {
+			return fmt.Errorf("invalid signing header name %q", name)
+		}
+	}
+	return nil
+}
+
+// hash возвращает хеш-функцию алгоритма подписи
+func (s *Signer) hash() (func() hash.Hash, error) {
+	switch strings.ToLower(s.Algorithm) {
+	case "", "sha256":
+		return sha256.New, nil
+	case "sha512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("unsupported signing algorithm %q, expected sha256 or sha512", s.Algorithm)
+}
+
+// Sign возвращает копию запроса с заголовками подписи
+// Вызывается на каждую попытку: повтор получает свежее время
+func (s *Signer) Sign(req *http.Request) (*http.Request, error) {
+	newHash, err := s.hash()
+	if err != nil {
+		return nil, err
+	}
+	bodyHash, err := payloadHash(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash request body for signing: %w", err)
+	}
+
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	timestamp := strconv.FormatInt(now().Unix(), 10)
+
+	mac := hmac.New(newHash, []byte(s.Secret))
+	mac.Write([]byte(strings.Join([]string{req.Method, req.URL.RequestURI(), timestamp, bodyHash}, "\n")))
+
+	req = req.Clone(req.Context())
+	req.Header.Set(headerOr(s.TimestampHeader, "X-Timestamp"), timestamp)
+	req.Header.Set(headerOr(s.ContentHashHeader, "X-Content-Sha256"), bodyHash)
+	req.Header.Set(headerOr(s.SignatureHeader, "X-Signature"), hex.EncodeToString(mac.Sum(nil)))
+	if s.KeyID != "" {
+		req.Header.Set(headerOr(s.KeyIDHeader, "X-Key-Id"), s.KeyID)
+	}
+	return req, nil
+}
+
+// payloadHash возвращает SHA-256 тела запроса в hex или UNSIGNED-PAYLOAD
+// Тело читается через GetBody, поэтому сам запрос отправляется без изменений
+func payloadHash(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return hex.EncodeToString(sha256.New().Sum(nil)), nil
+	}
+	if req.GetBody == nil || req.ContentLength < 0 || req.ContentLength > signedBodyLimit {
+		return unsignedPayload, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// headerOr возвращает name или fallback, если имя не задано
+func headerOr(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+// HMACSign подписывает каждую попытку запроса (для провайдеров, API которых требует подпись)
+func HMACSign(s *Signer) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+			signed, err := s.Sign(req)
+			if err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(signed)
+		})
+	}
+}
+
+// SetProviderSigners задает подписи запросов по имени провайдера из настроек
+// Как и дополнительные заголовки, подпись добавляется к запросам, контекст которых помечен WithProvider
+func SetProviderSigners(signers map[string]*Signer) {
+	providerSigners.Store(&signers)
+}
+
+// applySigning подписывает запрос провайдера, для которого подпись задана в настройках
+// Подпись ставится последней, после дополнительных заголовков
+func applySigning(req *http.Request) (*http.Request, error) {
+	name, _ := req.Context().Value(providerKey{}).(string)
+	if name == "" {
+		return req, nil
+	}
+	p := providerSigners.Load()
+	if p == nil || (*p)[name] == nil {
+		return req, nil
+	}
+	return (*p)[name].Sign(req)
+}
@@ -0,0 +1,122 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// expectedSignature считает подпись так же, как ее проверяет сервер
+func expectedSignature(secret, method, uri, timestamp, bodyHash string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + "\n" + uri + "\n" + timestamp + "\n" + bodyHash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestSignerSign(t *testing.T) {
+	signer := &Signer{KeyID: "team-1", Secret: "s3cr3t", now: func() time.Time { return time.Unix(1767225600, 0) }}
+
+	req, _ := http.NewRequest(http.MethodPut, "https://files.example.com/v1/files/report.pdf?part=2", strings.NewReader("hello"))
+	signed, err := signer.Sign(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("hello"))
+	bodyHash := hex.EncodeToString(sum[:])
+
+	if got := signed.Header.Get("X-Timestamp"); got != "1767225600" {
+		t.Errorf("X-Timestamp = %q", got)
+	}
+	if got := signed.Header.Get("X-Content-Sha256"); got != bodyHash {
+		t.Errorf("X-Content-Sha256 = %q, want %q", got, bodyHash)
+	}
+	if got := signed.Header.Get("X-Key-Id"); got != "team-1" {
+		t.Errorf("X-Key-Id = %q", got)
+	}
+	want := expectedSignature("s3cr3t", http.MethodPut, "/v1/files/report.pdf?part=2", "1767225600", bodyHash)
+	if got := signed.Header.Get("X-Signature"); got != want {
+		t.Errorf("X-Signature = %q, want %q", got, want)
+	}
+	if req.Header.Get("X-Signature") != "" {
+		t.Error("Sign modified the original request")
+	}
+	// Тело хешируется через GetBody и уходит на сервер целиком
+	if body, _ := io.ReadAll(signed.Body); string(body) != "hello" {
+		t.Errorf("body after signing = %q", body)
+	}
+
+	// Поток файла не перечитать: хеш тела не подписывается
+	stream, _ := http.NewRequest(http.MethodPost, "https://files.example.com/upload", io.NopCloser(strings.NewReader("data")))
+	signed, err = signer.Sign(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := signed.Header.Get("X-Content-Sha256"); got != unsignedPayload {
+		t.Errorf("stream X-Content-Sha256 = %q, want %q", got, unsignedPayload)
+	}
+}
+
+func TestSignerValidate(t *testing.T) {
+	for _, signer := range []*Signer{
+		{},
+		{Secret: "s", Algorithm: "md5"},
+		{Secret: "s", SignatureHeader: "Bad Name"},
+	} {
+		if err := signer.Validate(); err == nil {
+			t.Errorf("Validate(%+v) accepted invalid signer", signer)
+		}
+	}
+	if err := (&Signer{Secret: "s", Algorithm: "SHA512"}).Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+// TestApplySigning проверяет, что подпись из настроек уходит только в запросы провайдера и обновляется на повторе
+func TestApplySigning(t *testing.T) {
+	got := make(chan http.Header, 2)
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got <- r.Header.Clone()
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	defer SetProviderSigners(nil)
+
+	SetProviderSigners(map[string]*Signer{"tus": {Secret: "s3cr3t"}})
+	client := NewClient(&ClientConfig{MaxRetries: 1, MaxElapsed: 5 * time.Second})
+
+	req, _ := http.NewRequestWithContext(WithProvider(context.Background(), "tus"), http.MethodGet, server.URL+"/files?id=1", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	for range 2 {
+		headers := <-got
+		want := expectedSignature("s3cr3t", http.MethodGet, "/files?id=1", headers.Get("X-Timestamp"), headers.Get("X-Content-Sha256"))
+		if headers.Get("X-Signature") != want {
+			t.Errorf("attempt signature = %q, want %q", headers.Get("X-Signature"), want)
+		}
+	}
+
+	req, _ = http.NewRequestWithContext(WithProvider(context.Background(), "IPFS"), http.MethodGet, server.URL, nil)
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if headers := <-got; headers.Get("X-Signature") != "" {
+		t.Error("signature leaked to another provider")
+	}
+}
@@ -0,0 +1,148 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoCertificates в файле нет ни одного PEM сертификата
+var ErrNoCertificates = errors.New("no PEM certificates found")
+
+// tlsPolicy доверенные сертификаты и серверы без проверки сертификата
+type tlsPolicy struct {
+	// roots системные CA и дополнительные из файла (nil - только системные)
+	roots *x509.CertPool
+	// insecure имена хостов, сертификат которых не проверяется
+	insecure map[string]bool
+}
+
+// policy текущие настройки TLS, общие для всех клиентов
+var policy atomic.Pointer[tlsPolicy]
+
+func init() {
+	policy.Store(&tlsPolicy{})
+}
+
+// tlsHandshakeTimeout ограничивает TLS рукопожатие
+const tlsHandshakeTimeout = 10 * time.Second
+
+// dialTLS устанавливает TLS соединение с проверкой сертификата по текущим настройкам
+// Стандартная проверка выключена и выполняется в verifyConnection: так изменения
+// доверенных CA и списка хостов применяются к уже созданным клиентам
+func dialTLS(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		conn := tls.Client(raw, &tls.Config{
+			ServerName:         host,
+			InsecureSkipVerify: true,
+			VerifyConnection: func(cs tls.ConnectionState) error {
+				return verifyConnection(host, cs)
+			},
+		})
+		handshakeCtx, cancel := context.WithTimeout(ctx, tlsHandshakeTimeout)
+		defer cancel()
+		if err := conn.HandshakeContext(handshakeCtx); err != nil {
+			raw.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+}
+
+// verifyConnection проверяет цепочку сертификатов host так же, как стандартная проверка Go,
+// но с дополнительными CA и без проверки для хостов, где пользователь ее отключил
+// host берется из адреса, а не из ConnectionState: для IP адресов ServerName пуст
+func verifyConnection(host string, cs tls.ConnectionState) error {
+	p := policy.Load()
+	if p.insecure[strings.ToLower(host)] {
+		return nil
+	}
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: server sent no certificates")
+	}
+
+	opts := x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         p.roots,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// SetTrustedCAFile добавляет к системным CA сертификаты из PEM файла ("" - только системные)
+// При ошибке действующие настройки не меняются
+func SetTrustedCAFile(path string) error {
+	roots, err := loadCAFile(path)
+	if err != nil {
+		return err
+	}
+	for {
+		old := policy.Load()
+		if policy.CompareAndSwap(old, &tlsPolicy{roots: roots, insecure: old.insecure}) {
+			return nil
+		}
+	}
+}
+
+// ValidateCAFile проверяет, что файл читается и содержит сертификаты
+func ValidateCAFile(path string) error {
+	_, err := loadCAFile(path)
+	return err
+}
+
+// SetInsecureHosts задает хосты, сертификат которых не проверяется (самоподписанные сертификаты своих серверов)
+// Для остальных хостов проверка сохраняется
+func SetInsecureHosts(hosts []string) {
+	insecure := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		if host != "" {
+			insecure[strings.ToLower(host)] = true
+		}
+	}
+	for {
+		old := policy.Load()
+		if policy.CompareAndSwap(old, &tlsPolicy{roots: old.roots, insecure: insecure}) {
+			return
+		}
+	}
+}
+
+// loadCAFile читает системные CA и дополняет их сертификатами из path
+func loadCAFile(path string) (*x509.CertPool, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%s: %w", path, ErrNoCertificates)
+	}
+	return roots, nil
+}
@@ -0,0 +1,62 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestTLSPolicy проверяет дополнительные CA и отключение проверки для отдельного хоста
+func TestTLSPolicy(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	defer SetTrustedCAFile("")
+	defer SetInsecureHosts(nil)
+
+	get := func() error {
+		t.Helper()
+		// Новый клиент на каждую проверку, чтобы не переиспользовать соединение
+		resp, err := NewClient(&ClientConfig{MaxRetries: 0}).Do(mustRequest(t, server.URL))
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := get(); err == nil {
+		t.Fatal("self-signed certificate accepted without configuration")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, cert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetTrustedCAFile(caFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := get(); err != nil {
+		t.Fatalf("request with trusted CA file: %v", err)
+	}
+
+	SetTrustedCAFile("")
+	SetInsecureHosts([]string{"127.0.0.1"})
+	if err := get(); err != nil {
+		t.Fatalf("request to insecure host: %v", err)
+	}
+	SetInsecureHosts([]string{"example.com"})
+	if err := get(); err == nil {
+		t.Fatal("verification skipped for a host that is not in the list")
+	}
+
+	// Файл без сертификатов не заменяет действующие настройки
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(empty, []byte("not a certificate"), 0o600)
+	if err := ValidateCAFile(empty); !errors.Is(err, ErrNoCertificates) {
+		t.Errorf("ValidateCAFile() = %v, want ErrNoCertificates", err)
+	}
+}
@@ -0,0 +1,49 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"multiUploader/internal/tracing"
+)
+
+// startRequestSpan начинает span запроса (nil, если трассировка выключена)
+// Каждая попытка повтора получает свой span
+func startRequestSpan(req *http.Request) *tracing.Span {
+	if !tracing.Enabled() {
+		return nil
+	}
+
+	attrs := []tracing.Attr{
+		tracing.String("http.request.method", req.Method),
+		tracing.String("server.address", req.URL.Hostname()),
+		tracing.String("url.full", traceURL(req.URL)),
+	}
+	if req.ContentLength > 0 {
+		attrs = append(attrs, tracing.Int("http.request.body.size", req.ContentLength))
+	}
+	_, span := tracing.StartClient(req.Context(), "HTTP "+req.Method, attrs...)
+	return span
+}
+
+// endRequestSpan завершает span запроса; ответ 4xx/5xx отмечается как ошибка
+func endRequestSpan(span *tracing.Span, resp *http.Response, err error) {
+	if span == nil {
+		return
+	}
+	if err == nil {
+		span.SetAttributes(tracing.Int("http.response.status_code", int64(resp.StatusCode)))
+		if resp.StatusCode >= 400 {
+			err = fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+	}
+	span.End(err)
+}
+
+// traceURL адрес запроса для трассы: без query (там бывают ключи и подписи presigned URL) и без токена в пути
+func traceURL(u *url.URL) string {
+	clean := *u
+	clean.RawQuery = ""
+	return redactURL(&clean)
+}
@@ -0,0 +1,155 @@
+package instance
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	portFileName = "instance.port"
+	dialTimeout  = 2 * time.Second
+)
+
+// ErrNotRunning возвращается, если запущенный экземпляр приложения не найден
+var ErrNotRunning = errors.New("no running instance")
+
+// stateDir директория для файла с портом (переопределяется в тестах)
+var stateDir = defaultStateDir
+
+// message сообщение, которое второй экземпляр передает запущенному
+type message struct {
+	Files []string `json:"files"`
+}
+
+// Server принимает файлы от других экземпляров приложения
+type Server struct {
+	listener net.Listener
+	portFile string
+}
+
+// Listen запускает локальный сервер и записывает его порт в файл состояния
+// handler вызывается в отдельной горутине для каждого полученного списка файлов
+func Listen(handler func(files []string)) (*Server, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	// Слушаем только loopback, чтобы файлы не могли прислать по сети
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	portFile := filepath.Join(dir, portFileName)
+	if err := os.WriteFile(portFile, []byte(strconv.Itoa(port)), 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to write port file: %w", err)
+	}
+
+	s := &Server{listener: listener, portFile: portFile}
+	go s.serve(handler)
+
+	return s, nil
+}
+
+// serve принимает соединения до закрытия listener
+func (s *Server) serve(handler func(files []string)) {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func(conn net.Conn) {
+			defer conn.Close()
+			_ = conn.SetDeadline(time.Now().Add(dialTimeout))
+
+			var msg message
+			if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&msg); err != nil {
+				return
+			}
+
+			// Подтверждаем получение, чтобы отправитель мог завершиться
+			_, _ = conn.Write([]byte("ok\n"))
+
+			if len(msg.Files) > 0 && handler != nil {
+				handler(msg.Files)
+			}
+		}(conn)
+	}
+}
+
+// Close останавливает сервер и удаляет файл с портом
+func (s *Server) Close() error {
+	_ = os.Remove(s.portFile)
+	return s.listener.Close()
+}
+
+// SendToRunning передает файлы уже запущенному экземпляру
+// Возвращает ErrNotRunning если экземпляр не найден
+func SendToRunning(files []string) error {
+	dir, err := stateDir()
+	if err != nil {
+		return ErrNotRunning
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, portFileName))
+	if err != nil {
+		return ErrNotRunning
+	}
+
+	port, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return ErrNotRunning
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), dialTimeout)
+	if err != nil {
+		// Файл остался от упавшего процесса
+		return ErrNotRunning
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(dialTimeout))
+
+	// Передаем абсолютные пути, рабочие директории экземпляров могут отличаться
+	absFiles := make([]string, 0, len(files))
+	for _, f := range files {
+		if abs, err := filepath.Abs(f); err == nil {
+			f = abs
+		}
+		absFiles = append(absFiles, f)
+	}
+
+	if err := json.NewEncoder(conn).Encode(message{Files: absFiles}); err != nil {
+		return fmt.Errorf("failed to send files: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || strings.TrimSpace(reply) != "ok" {
+		return fmt.Errorf("running instance did not acknowledge files")
+	}
+
+	return nil
+}
+
+// defaultStateDir возвращает кроссплатформенную директорию состояния приложения
+func defaultStateDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "multiUploader"), nil
+}
@@ -0,0 +1,72 @@
+package instance
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// useTempStateDir переключает директорию состояния на временную
+func useTempStateDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	old := stateDir
+	stateDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { stateDir = old })
+}
+
+// TestSendToRunning проверяет передачу файлов запущенному экземпляру
+func TestSendToRunning(t *testing.T) {
+	useTempStateDir(t)
+
+	received := make(chan []string, 1)
+	server, err := Listen(func(files []string) {
+		received <- files
+	})
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer server.Close()
+
+	if err := SendToRunning([]string{"/tmp/a.bin", "b.bin"}); err != nil {
+		t.Fatalf("SendToRunning() error = %v", err)
+	}
+
+	select {
+	case files := <-received:
+		if len(files) != 2 {
+			t.Fatalf("received %d files, want 2", len(files))
+		}
+		if files[0] != "/tmp/a.bin" {
+			t.Errorf("files[0] = %s, want /tmp/a.bin", files[0])
+		}
+		if !filepath.IsAbs(files[1]) {
+			t.Errorf("files[1] = %s, want absolute path", files[1])
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler was not called")
+	}
+}
+
+// TestSendToRunningNoInstance проверяет поведение без запущенного экземпляра
+func TestSendToRunningNoInstance(t *testing.T) {
+	useTempStateDir(t)
+
+	err := SendToRunning([]string{"/tmp/a.bin"})
+	if !errors.Is(err, ErrNotRunning) {
+		t.Errorf("SendToRunning() error = %v, want ErrNotRunning", err)
+	}
+
+	// После закрытия сервера файл порта удаляется
+	server, err := Listen(nil)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	server.Close()
+
+	err = SendToRunning([]string{"/tmp/a.bin"})
+	if !errors.Is(err, ErrNotRunning) {
+		t.Errorf("SendToRunning() after Close error = %v, want ErrNotRunning", err)
+	}
+}
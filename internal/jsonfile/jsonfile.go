@@ -0,0 +1,59 @@
+// Package jsonfile хранит состояние приложения (история, очередь) в JSON файлах в директории настроек
+package jsonfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DefaultPath возвращает путь к файлу name в директории настроек пользователя
+func DefaultPath(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "multiUploader", name), nil
+}
+
+// Load читает JSON из файла в v; отсутствующий файл не ошибка, v остается как есть
+// what - что хранится в файле, для текста ошибок
+func Load(path string, v any, what string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", what, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", what, err)
+	}
+	return nil
+}
+
+// Save записывает v в файл через временный файл, чтобы сбой не испортил прежнее содержимое
+func Save(path string, v any, what string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", what, err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", what, err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", what, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to save %s: %w", what, err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package jsonfile
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestSaveLoad проверяет запись во вложенную папку, чтение и отсутствующий файл
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "state.json")
+
+	var missing []string
+	if err := Load(path, &missing, "state"); err != nil || missing != nil {
+		t.Fatalf("Load() of missing file = %v, %v; want nil, nil", missing, err)
+	}
+
+	want := []string{"a", "b"}
+	if err := Save(path, want, "state"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+
+	var got []string
+	if err := Load(path, &got, "state"); err != nil || !slices.Equal(got, want) {
+		t.Errorf("Load() = %v, %v; want %v", got, err, want)
+	}
+}
+
+// TestLoadCorrupted проверяет ошибку с названием содержимого при поврежденном файле
+func TestLoadCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var v []string
+	err := Load(path, &v, "state")
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to parse state") {
+		t.Errorf("Load() error = %v, want parse error", err)
+	}
+}
@@ -0,0 +1,124 @@
+package localization
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// format правила форматирования чисел, размеров и дат для языка
+type format struct {
+	decimal   string    // десятичный разделитель
+	clock24   bool      // 24-часовой формат времени
+	date      string    // раскладка даты для time.Format
+	sizeUnits [4]string // B, KB, MB, GB
+	perSecond string    // суффикс скорости
+	hours     string
+	minutes   string
+	seconds   string
+}
+
+// formats правила форматирования по коду языка
+var formats = map[string]format{
+	"en": {".", false, "01/02/2006", [4]string{"B", "KB", "MB", "GB"}, "/s", "h", "m", "s"},
+	"ru": {",", true, "02.01.2006", [4]string{"Б", "КБ", "МБ", "ГБ"}, "/с", " ч", " мин", " с"},
+	"de": {",", true, "02.01.2006", [4]string{"B", "KB", "MB", "GB"}, "/s", " Std.", " Min.", " Sek."},
+	"es": {",", true, "02/01/2006", [4]string{"B", "KB", "MB", "GB"}, "/s", " h", " min", " s"},
+	"fr": {",", true, "02/01/2006", [4]string{"o", "Ko", "Mo", "Go"}, "/s", " h", " min", " s"},
+	"zh": {".", true, "2006-01-02", [4]string{"B", "KB", "MB", "GB"}, "/秒", "小时", "分", "秒"},
+}
+
+// currentFormat возвращает правила форматирования для активного языка
+func currentFormat() format {
+	if f, ok := formats[activeCode]; ok {
+		return f
+	}
+	return formats["en"]
+}
+
+// formatFloat форматирует число с нужной точностью и десятичным разделителем языка
+func (f format) formatFloat(value float64, precision int) string {
+	s := fmt.Sprintf("%.*f", precision, value)
+	if f.decimal != "." {
+		s = strings.Replace(s, ".", f.decimal, 1)
+	}
+	return s
+}
+
+// FormatSize форматирует размер в байтах с учетом текущего языка
+func FormatSize(bytes int64) string {
+	f := currentFormat()
+
+	switch {
+	case bytes < 1024:
+		return fmt.Sprintf("%d %s", bytes, f.sizeUnits[0])
+	case bytes < 1024*1024:
+		return f.formatFloat(float64(bytes)/1024, 1) + " " + f.sizeUnits[1]
+	case bytes < 1024*1024*1024:
+		return f.formatFloat(float64(bytes)/(1024*1024), 2) + " " + f.sizeUnits[2]
+	default:
+		return f.formatFloat(float64(bytes)/(1024*1024*1024), 2) + " " + f.sizeUnits[3]
+	}
+}
+
+// FormatMegabytes форматирует целое число мегабайт, например "16 MB"
+func FormatMegabytes(mb int) string {
+	return fmt.Sprintf("%d %s", mb, currentFormat().sizeUnits[2])
+}
+
+// FormatSpeed форматирует скорость в байтах/сек с учетом текущего языка
+func FormatSpeed(bytesPerSec float64) string {
+	f := currentFormat()
+
+	switch {
+	case bytesPerSec < 1024:
+		return f.formatFloat(bytesPerSec, 0) + " " + f.sizeUnits[0] + f.perSecond
+	case bytesPerSec < 1024*1024:
+		return f.formatFloat(bytesPerSec/1024, 1) + " " + f.sizeUnits[1] + f.perSecond
+	default:
+		return f.formatFloat(bytesPerSec/(1024*1024), 2) + " " + f.sizeUnits[2] + f.perSecond
+	}
+}
+
+// FormatETA форматирует оставшееся время загрузки
+// Пока скорость неизвестна возвращает переведенное "calculating..."
+func FormatETA(bytesRemaining int64, speed float64) string {
+	if speed <= 0 {
+		return T("calculating...")
+	}
+
+	return "~" + FormatDuration(time.Duration(float64(bytesRemaining)/speed)*time.Second)
+}
+
+// FormatDuration форматирует длительность с точностью до секунд, например "1m 30s"
+func FormatDuration(duration time.Duration) string {
+	f := currentFormat()
+	duration = duration.Round(time.Second)
+
+	switch {
+	case duration < time.Minute:
+		return fmt.Sprintf("%d%s", int(duration.Seconds()), f.seconds)
+	case duration < time.Hour:
+		return fmt.Sprintf("%d%s %d%s", int(duration.Minutes()), f.minutes, int(duration.Seconds())%60, f.seconds)
+	default:
+		return fmt.Sprintf("%d%s %d%s", int(duration.Hours()), f.hours, int(duration.Minutes())%60, f.minutes)
+	}
+}
+
+// FormatTime форматирует время (часы и минуты) в 12- или 24-часовом формате языка
+func FormatTime(t time.Time) string {
+	if currentFormat().clock24 {
+		return t.Format("15:04")
+	}
+	return t.Format("3:04 PM")
+}
+
+// FormatDate форматирует дату в формате языка
+func FormatDate(t time.Time) string {
+	return t.Format(currentFormat().date)
+}
+
+// FormatDateTime форматирует дату и время, например для истории загрузок
+func FormatDateTime(t time.Time) string {
+	return FormatDate(t) + " " + FormatTime(t)
+}
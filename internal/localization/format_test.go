@@ -0,0 +1,75 @@
+package localization
+
+import (
+	"testing"
+	"time"
+)
+
+// useLanguage временно переключает активный язык форматирования
+func useLanguage(t *testing.T, code string) {
+	t.Helper()
+	old := activeCode
+	activeCode = code
+	t.Cleanup(func() { activeCode = old })
+}
+
+// TestFormatSize проверяет разделители и единицы для разных языков
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		code  string
+		bytes int64
+		want  string
+	}{
+		{"en", 512, "512 B"},
+		{"en", 1536, "1.5 KB"},
+		{"ru", 1536, "1,5 КБ"},
+		{"de", 5 * 1024 * 1024, "5,00 MB"},
+		{"fr", 3 * 1024 * 1024 * 1024, "3,00 Go"},
+		{"zh", 1536, "1.5 KB"},
+		{"xx", 1536, "1.5 KB"},
+	}
+
+	for _, tt := range tests {
+		useLanguage(t, tt.code)
+		if got := FormatSize(tt.bytes); got != tt.want {
+			t.Errorf("[%s] FormatSize(%d) = %q, want %q", tt.code, tt.bytes, got, tt.want)
+		}
+	}
+}
+
+// TestFormatSpeedAndETA проверяет форматирование скорости и оставшегося времени
+func TestFormatSpeedAndETA(t *testing.T) {
+	useLanguage(t, "en")
+	if got := FormatSpeed(2.5 * 1024 * 1024); got != "2.50 MB/s" {
+		t.Errorf("FormatSpeed() = %q, want 2.50 MB/s", got)
+	}
+	if got := FormatETA(90*1024, 1024); got != "~1m 30s" {
+		t.Errorf("FormatETA() = %q, want ~1m 30s", got)
+	}
+	if got := FormatDuration(4500 * time.Millisecond); got != "5s" {
+		t.Errorf("FormatDuration() = %q, want 5s", got)
+	}
+
+	useLanguage(t, "ru")
+	if got := FormatSpeed(1536); got != "1,5 КБ/с" {
+		t.Errorf("FormatSpeed() = %q, want 1,5 КБ/с", got)
+	}
+	if got := FormatETA(2*3600+5*60, 1); got != "~2 ч 5 мин" {
+		t.Errorf("FormatETA() = %q, want ~2 ч 5 мин", got)
+	}
+}
+
+// TestFormatDateTime проверяет 12- и 24-часовой формат
+func TestFormatDateTime(t *testing.T) {
+	ts := time.Date(2024, time.March, 5, 14, 7, 0, 0, time.UTC)
+
+	useLanguage(t, "en")
+	if got := FormatDateTime(ts); got != "03/05/2024 2:07 PM" {
+		t.Errorf("[en] FormatDateTime() = %q", got)
+	}
+
+	useLanguage(t, "de")
+	if got := FormatDateTime(ts); got != "05.03.2024 14:07" {
+		t.Errorf("[de] FormatDateTime() = %q", got)
+	}
+}
@@ -2,6 +2,7 @@ package localization
 
 import (
 	"embed"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/lang"
@@ -13,48 +14,74 @@ var translationsFS embed.FS
 // currentLocale хранит текущую выбранную локаль
 var currentLocale = ""
 
+// activeCode код языка, перевод которого загружен (с учетом "auto")
+var activeCode = "en"
+
+// language описывает доступный язык интерфейса
+type language struct {
+	Code string // код локали, совпадает с именем файла перевода
+	Name string // название языка на самом языке
+}
+
+// languages доступные языки в порядке отображения в настройках
+var languages = []language{
+	{"en", "English"},
+	{"ru", "Русский"},
+	{"de", "Deutsch"},
+	{"es", "Español"},
+	{"fr", "Français"},
+	{"zh", "中文"},
+	// Название в порядке отображения: Fyne не переворачивает текст справа налево
+	{"he", "תירבע"},
+}
+
 // Init инициализирует систему локализации
-// locale может быть "en", "ru" или "auto" (для использования системной локали)
+// locale может быть кодом языка ("en", "ru", ...) или "auto" (для использования системной локали)
+// Может вызываться повторно во время работы для смены языка
 func Init(locale string) error {
 	// Устанавливаем текущую локаль
 	SetLocale(locale)
 
-	// Хак для переопределения системной локали
-	// Обсуждение: https://github.com/fyne-io/fyne/issues/5333
-	var content []byte
-	var err error
-
-	switch locale {
-	case "en":
-		content, err = translationsFS.ReadFile("translations/en.json")
-	case "ru":
-		content, err = translationsFS.ReadFile("translations/ru.json")
-	case "auto":
-		// Используем автоопределение - загружаем все переводы
-		if err := lang.AddTranslationsFS(translationsFS, "translations"); err != nil {
-			return err
+	// Для "auto" и неизвестных локалей берем язык системы, если для него есть перевод
+	code := locale
+	if !isAvailable(code) {
+		code = lang.SystemLocale().LanguageString()
+		if i := strings.IndexAny(code, "-_"); i != -1 {
+			code = code[:i]
 		}
-		return nil
-	default:
-		// Если неизвестная локаль, используем автоопределение
-		if err := lang.AddTranslationsFS(translationsFS, "translations"); err != nil {
-			return err
+		if !isAvailable(code) {
+			code = "en"
 		}
-		return nil
 	}
 
+	content, err := translationsFS.ReadFile("translations/" + code + ".json")
 	if err != nil {
 		return err
 	}
+	if rtlLanguages[code] {
+		if content, err = visualTranslation(content); err != nil {
+			return err
+		}
+	}
+	activeCode = code
 
+	// Хак для переопределения системной локали
+	// Обсуждение: https://github.com/fyne-io/fyne/issues/5333
 	// Регистрируем выбранный перевод под именем системной локали
-	// Это заставляет Fyne использовать выбранный язык вместо системного
-	if content != nil {
-		name := lang.SystemLocale().LanguageString()
-		return lang.AddTranslations(fyne.NewStaticResource(name+".json", content))
-	}
+	// Это заставляет Fyne использовать выбранный язык вместо системного,
+	// а повторная регистрация перезаписывает строки предыдущего языка
+	name := lang.SystemLocale().LanguageString()
+	return lang.AddTranslations(fyne.NewStaticResource(name+".json", content))
+}
 
-	return nil
+// isAvailable проверяет, есть ли перевод для кода локали
+func isAvailable(code string) bool {
+	for _, l := range languages {
+		if l.Code == code {
+			return true
+		}
+	}
+	return false
 }
 
 // SetLocale устанавливает текущую локаль приложения
@@ -78,31 +105,31 @@ func T(text string) string {
 
 // GetAvailableLanguages возвращает список доступных языков для UI
 func GetAvailableLanguages() []string {
-	return []string{"Auto", "English", "Русский"}
+	names := []string{"Auto"}
+	for _, l := range languages {
+		names = append(names, l.Name)
+	}
+	return names
 }
 
 // LanguageNameToCode конвертирует название языка в код локали
 func LanguageNameToCode(name string) string {
-	switch name {
-	case "English":
-		return "en"
-	case "Русский":
-		return "ru"
-	default:
-		return "auto"
+	for _, l := range languages {
+		if l.Name == name {
+			return l.Code
+		}
 	}
+	return "auto"
 }
 
 // LanguageCodeToName конвертирует код локали в название для UI
 func LanguageCodeToName(code string) string {
-	switch code {
-	case "en":
-		return "English"
-	case "ru":
-		return "Русский"
-	default:
-		return "Auto"
+	for _, l := range languages {
+		if l.Code == code {
+			return l.Name
+		}
 	}
+	return "Auto"
 }
 
 // GetFyneLocale возвращает Fyne-совместимую локаль
@@ -0,0 +1,101 @@
+package localization
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestTranslationsComplete проверяет, что во всех переводах одинаковый набор ключей
+// При смене языка во время работы строки перезаписываются, поэтому
+// отсутствующий ключ оставил бы текст на предыдущем языке
+func TestTranslationsComplete(t *testing.T) {
+	reference := loadTranslation(t, "en")
+
+	for _, l := range languages {
+		translation := loadTranslation(t, l.Code)
+
+		for key := range reference {
+			if _, ok := translation[key]; !ok {
+				t.Errorf("%s.json: missing key %q", l.Code, key)
+			}
+		}
+		for key, value := range translation {
+			if _, ok := reference[key]; !ok {
+				t.Errorf("%s.json: unknown key %q", l.Code, key)
+			}
+			if value == "" {
+				t.Errorf("%s.json: empty translation for %q", l.Code, key)
+			}
+		}
+	}
+}
+
+// TestTranslationVerbs проверяет, что переводы используют те же подстановки fmt, что и английский текст
+// Лишняя или потерянная подстановка выводит в интерфейс "%!d(MISSING)" или "%!(EXTRA ...)"
+func TestTranslationVerbs(t *testing.T) {
+	reference := loadTranslation(t, "en")
+
+	for _, l := range languages {
+		translation := loadTranslation(t, l.Code)
+
+		for key, value := range reference {
+			forms := strings.Split(value, pluralSeparator)
+			want := formatVerbs(forms[len(forms)-1])
+			for _, form := range strings.Split(translation[key], pluralSeparator) {
+				if got := formatVerbs(form); !slices.Equal(got, want) {
+					t.Errorf("%s.json: %q uses %v, want %v", l.Code, form, got, want)
+				}
+			}
+		}
+	}
+}
+
+// formatVerbs подстановки fmt строки в порядке сортировки
+func formatVerbs(s string) []string {
+	runes := []rune(s)
+	var verbs []string
+	for i := range runes {
+		if end := verbEnd(runes, i); end != -1 {
+			verbs = append(verbs, string(runes[i:end]))
+		}
+	}
+	slices.Sort(verbs)
+	return verbs
+}
+
+// TestLanguageCodes проверяет конвертацию названий языков в коды и обратно
+func TestLanguageCodes(t *testing.T) {
+	for _, l := range languages {
+		if got := LanguageNameToCode(LanguageCodeToName(l.Code)); got != l.Code {
+			t.Errorf("round trip for %s = %s", l.Code, got)
+		}
+	}
+
+	if got := LanguageNameToCode("Auto"); got != "auto" {
+		t.Errorf("LanguageNameToCode(Auto) = %s, want auto", got)
+	}
+	if got := LanguageCodeToName("xx"); got != "Auto" {
+		t.Errorf("LanguageCodeToName(xx) = %s, want Auto", got)
+	}
+
+	if got := len(GetAvailableLanguages()); got != len(languages)+1 {
+		t.Errorf("GetAvailableLanguages() returned %d items, want %d", got, len(languages)+1)
+	}
+}
+
+func loadTranslation(t *testing.T, code string) map[string]string {
+	t.Helper()
+
+	data, err := translationsFS.ReadFile("translations/" + code + ".json")
+	if err != nil {
+		t.Fatalf("failed to read %s.json: %v", code, err)
+	}
+
+	var translation map[string]string
+	if err := json.Unmarshal(data, &translation); err != nil {
+		t.Fatalf("failed to parse %s.json: %v", code, err)
+	}
+	return translation
+}
@@ -0,0 +1,60 @@
+package localization
+
+import "strings"
+
+// pluralSeparator разделяет формы множественного числа в переводе
+const pluralSeparator = "|"
+
+// TN переводит строку с количеством count, выбирая форму множественного числа языка
+// Перевод содержит формы через "|" в порядке правил языка: в английском "%d file|%d files",
+// в русском три формы (1 файл, 2 файла, 5 файлов). Число подставляет вызывающий через fmt.Sprintf
+func TN(text string, count int) string {
+	forms := strings.Split(T(text), pluralSeparator)
+	return forms[min(pluralForm(activeCode, count), len(forms)-1)]
+}
+
+// pluralForm номер формы множественного числа для count по правилам CLDR языка code
+func pluralForm(code string, count int) int {
+	n := count
+	if n < 0 {
+		n = -n
+	}
+
+	switch code {
+	case "ru":
+		// 1, 21, 31 файл; 2-4, 22-24 файла; 0, 5-20, 25-30 файлов
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return 1
+		default:
+			return 2
+		}
+	case "fr":
+		// Во французском 0 и 1 - единственное число
+		if n <= 1 {
+			return 0
+		}
+		return 1
+	case "zh":
+		return 0
+	default:
+		if n == 1 {
+			return 0
+		}
+		return 1
+	}
+}
+
+// pluralForms сколько форм множественного числа в переводах языка code
+func pluralForms(code string) int {
+	switch code {
+	case "ru":
+		return 3
+	case "zh":
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,70 @@
+package localization
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPluralForm проверяет выбор формы множественного числа по правилам языков
+func TestPluralForm(t *testing.T) {
+	tests := []struct {
+		code  string
+		count int
+		want  int
+	}{
+		{"en", 0, 1},
+		{"en", 1, 0},
+		{"en", 2, 1},
+		{"de", 1, 0},
+		{"fr", 0, 0},
+		{"fr", 1, 0},
+		{"fr", 2, 1},
+		{"zh", 1, 0},
+		{"zh", 5, 0},
+		{"ru", 1, 0},
+		{"ru", 21, 0},
+		{"ru", 101, 0},
+		{"ru", 2, 1},
+		{"ru", 4, 1},
+		{"ru", 22, 1},
+		{"ru", 0, 2},
+		{"ru", 5, 2},
+		{"ru", 11, 2},
+		{"ru", 12, 2},
+		{"ru", 14, 2},
+		{"ru", 111, 2},
+		{"ru", -1, 0},
+	}
+
+	for _, tt := range tests {
+		if got := pluralForm(tt.code, tt.count); got != tt.want {
+			t.Errorf("pluralForm(%s, %d) = %d, want %d", tt.code, tt.count, got, tt.want)
+		}
+	}
+}
+
+// TestPluralTranslations проверяет, что у строк с формами множественного числа
+// в каждом переводе столько форм, сколько их в языке, и все формы содержат число
+func TestPluralTranslations(t *testing.T) {
+	reference := loadTranslation(t, "en")
+
+	for _, l := range languages {
+		translation := loadTranslation(t, l.Code)
+
+		for key, value := range reference {
+			if !strings.Contains(value, pluralSeparator) {
+				continue
+			}
+			forms := strings.Split(translation[key], pluralSeparator)
+			if len(forms) != pluralForms(l.Code) {
+				t.Errorf("%s.json: %q has %d plural forms, want %d", l.Code, key, len(forms), pluralForms(l.Code))
+				continue
+			}
+			for _, form := range forms {
+				if !strings.Contains(form, "%d") {
+					t.Errorf("%s.json: plural form %q of %q has no %%d", l.Code, form, key)
+				}
+			}
+		}
+	}
+}
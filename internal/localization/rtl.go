@@ -0,0 +1,180 @@
+package localization
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// rtlLanguages коды языков с письмом справа налево
+// Арабского нет: после переворота строки его буквы соединялись бы неправильно
+var rtlLanguages = map[string]bool{
+	"he": true,
+}
+
+// mirroredRunes парные символы, которые в тексте справа налево отображаются зеркально
+var mirroredRunes = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+}
+
+// IsRTL сообщает, что активный язык пишется справа налево
+// UI по нему зеркально раскладывает строки: подписи справа, кнопки слева
+func IsRTL() bool {
+	return rtlLanguages[activeCode]
+}
+
+// visualTranslation переводит строки RTL перевода в порядок отображения
+// Fyne раскладывает текст только слева направо, поэтому строки переворачиваются заранее
+// Формы множественного числа переворачиваются по отдельности, чтобы TN нашел их на своих местах,
+// а подстановки fmt получают явные номера аргументов: после переворота они идут в обратном порядке
+func visualTranslation(content []byte) ([]byte, error) {
+	var translation map[string]string
+	if err := json.Unmarshal(content, &translation); err != nil {
+		return nil, err
+	}
+	for key, value := range translation {
+		forms := strings.Split(value, pluralSeparator)
+		for i, form := range forms {
+			forms[i] = visualOrder(indexVerbs(form))
+		}
+		translation[key] = strings.Join(forms, pluralSeparator)
+	}
+	return json.Marshal(translation)
+}
+
+// visualOrder переставляет строку абзаца справа налево в порядок отображения слева направо
+// Упрощенный алгоритм Unicode Bidi: слова на иврите идут справа налево, а латиница,
+// числа и подстановки fmt (%s, %d) остаются как есть. Значения подстановок не переворачиваются,
+// поэтому имя файла на иврите внутри строки покажется в обратном порядке
+func visualOrder(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = visualLine(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// visualLine переставляет одну строку без переводов строк
+func visualLine(line string) string {
+	runes := []rune(line)
+	rtl := runeDirections(runes)
+
+	// Делим строку на отрезки одного направления и выводим их в обратном порядке
+	var b strings.Builder
+	end := len(runes)
+	for end > 0 {
+		start := end - 1
+		for start > 0 && rtl[start-1] == rtl[end-1] {
+			start--
+		}
+		if rtl[start] {
+			for i := end - 1; i >= start; i-- {
+				r := runes[i]
+				if m, ok := mirroredRunes[r]; ok {
+					r = m
+				}
+				b.WriteRune(r)
+			}
+		} else {
+			b.WriteString(string(runes[start:end]))
+		}
+		end = start
+	}
+	return b.String()
+}
+
+// runeDirections определяет для каждого символа, идет ли он справа налево
+// Пробелы и знаки между двумя символами письма слева направо остаются в отрезке
+// слева направо ("Google Drive", "1.5 MB"), остальные принадлежат абзацу справа налево
+func runeDirections(runes []rune) []bool {
+	const (
+		neutral = iota
+		ltr
+		rtl
+	)
+	strong := make([]int, len(runes))
+	for i, r := range runes {
+		switch {
+		case unicode.In(r, unicode.Hebrew, unicode.Arabic):
+			strong[i] = rtl
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			strong[i] = ltr
+		}
+	}
+	// Подстановка fmt не разрывается: "%.1f" целиком идет слева направо
+	for i := range runes {
+		if end := verbEnd(runes, i); end != -1 {
+			for j := i; j < end; j++ {
+				strong[j] = ltr
+			}
+		}
+	}
+
+	directions := make([]bool, len(runes))
+	for i := range runes {
+		if strong[i] != neutral {
+			directions[i] = strong[i] == rtl
+			continue
+		}
+		prev, next := neutral, neutral
+		for j := i - 1; j >= 0 && prev == neutral; j-- {
+			prev = strong[j]
+		}
+		for j := i + 1; j < len(strong) && next == neutral; j++ {
+			next = strong[j]
+		}
+		directions[i] = prev != ltr || next != ltr
+	}
+	return directions
+}
+
+// indexVerbs проставляет подстановкам fmt номера аргументов по порядку: "%d из %d" → "%[1]d из %[2]d"
+// Подстановки с номером и "%%" не меняются
+func indexVerbs(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	n := 0
+	for i := 0; i < len(runes); {
+		end := verbEnd(runes, i)
+		if end == -1 {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+		verb := string(runes[i:end])
+		if verb != "%%" && !strings.Contains(verb, "[") {
+			n++
+			// Номер ставится после флагов: "%-5d" → "%-[1]5d"
+			flags := 1 + len(verb[1:]) - len(strings.TrimLeft(verb[1:], "+-# 0"))
+			verb = fmt.Sprintf("%s[%d]%s", verb[:flags], n, verb[flags:])
+		}
+		b.WriteString(verb)
+		i = end
+	}
+	return b.String()
+}
+
+// verbEnd возвращает конец подстановки fmt, начинающейся в runes[i] (-1 - подстановки нет)
+func verbEnd(runes []rune, i int) int {
+	if runes[i] != '%' {
+		return -1
+	}
+	for j := i + 1; j < len(runes); j++ {
+		r := runes[j]
+		switch {
+		case r == '%' && j == i+1:
+			return j + 1
+		case strings.ContainsRune("+-# 0123456789.*[]", r):
+		case r < unicode.MaxASCII && unicode.IsLetter(r):
+			return j + 1
+		default:
+			return -1
+		}
+	}
+	return -1
+}
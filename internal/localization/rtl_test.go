@@ -0,0 +1,56 @@
+package localization
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestVisualOrder проверяет перестановку строк справа налево в порядок отображения
+func TestVisualOrder(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"שלום עולם", "םלוע םולש"},
+		{"Google Drive", "Google Drive"},
+		{"העלאה ל-Google Drive", "Google Drive-ל האלעה"},
+		{"גודל חלק:", ":קלח לדוג"},
+		{"(שלום)", "(םולש)"},
+		{"נשמר %s", "%s רמשנ"},
+		{"גודל: %.1f MB", "%.1f MB :לדוג"},
+		{"שורה\nשנייה", "הרוש\nהיינש"},
+	}
+
+	for _, tt := range tests {
+		if got := visualOrder(tt.in); got != tt.want {
+			t.Errorf("visualOrder(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestVisualTranslationArguments проверяет, что после переворота подстановки получают свои аргументы
+func TestVisualTranslationArguments(t *testing.T) {
+	content, err := visualTranslation([]byte(`{"%d of %d": "%d מתוך %d|%d מתוך %d"}`))
+	if err != nil {
+		t.Fatalf("visualTranslation() error = %v", err)
+	}
+	want := `{"%d of %d":"%[2]d ךותמ %[1]d|%[2]d ךותמ %[1]d"}`
+	if string(content) != want {
+		t.Fatalf("visualTranslation() = %s, want %s", content, want)
+	}
+
+	var translation map[string]string
+	if err := json.Unmarshal(content, &translation); err != nil {
+		t.Fatal(err)
+	}
+	// Читается справа налево как "5 מתוך 10"
+	form := strings.Split(translation["%d of %d"], pluralSeparator)[0]
+	if got := fmt.Sprintf(form, 5, 10); got != "10 ךותמ 5" {
+		t.Errorf("Sprintf(%q, 5, 10) = %q", form, got)
+	}
+	if got := indexVerbs("%-5d%% %s %[1]d"); got != "%-[1]5d%% %[2]s %[1]d" {
+		t.Errorf("indexVerbs() = %q", got)
+	}
+}
@@ -0,0 +1,175 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// providerStats счетчики одного провайдера
+type providerStats struct {
+	started   uint64
+	completed uint64
+	failed    uint64
+	bytes     uint64
+
+	// Длительность успешных загрузок (sum/count как у Prometheus summary)
+	durationSum   float64
+	durationCount uint64
+
+	// Задержка последней проверки доступности (0 - проверки не было)
+	probeLatency float64
+	probeUp      bool
+	probed       bool
+}
+
+// Registry счетчики загрузок по провайдерам
+type Registry struct {
+	mu        sync.Mutex
+	providers map[string]*providerStats
+}
+
+// NewRegistry создает пустой набор счетчиков
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[string]*providerStats)}
+}
+
+// std счетчики приложения
+var std = NewRegistry()
+
+// Default возвращает счетчики приложения
+func Default() *Registry {
+	return std
+}
+
+func (r *Registry) stats(provider string) *providerStats {
+	s, ok := r.providers[provider]
+	if !ok {
+		s = &providerStats{}
+		r.providers[provider] = s
+	}
+	return s
+}
+
+// UploadStarted учитывает начало загрузки
+func (r *Registry) UploadStarted(provider string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats(provider).started++
+}
+
+// UploadCompleted учитывает успешную загрузку size байт за duration
+func (r *Registry) UploadCompleted(provider string, size int64, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.stats(provider)
+	s.completed++
+	if size > 0 {
+		s.bytes += uint64(size)
+	}
+	s.durationSum += duration.Seconds()
+	s.durationCount++
+}
+
+// UploadFailed учитывает неудачную загрузку
+func (r *Registry) UploadFailed(provider string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats(provider).failed++
+}
+
+// ProbeFinished запоминает результат проверки доступности провайдера
+func (r *Registry) ProbeFinished(provider string, latency time.Duration, up bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.stats(provider)
+	s.probeLatency = latency.Seconds()
+	s.probeUp = up
+	s.probed = true
+}
+
+// metric описание одной метрики для вывода
+// family, kind и help задают заголовок HELP/TYPE (пустой family - продолжение предыдущей метрики)
+type metric struct {
+	family, kind, help string
+	name               string
+	value              func(s *providerStats) (float64, bool)
+}
+
+var metricsList = []metric{
+	{"multiuploader_uploads_started_total", "counter", "Uploads started.",
+		"multiuploader_uploads_started_total",
+		func(s *providerStats) (float64, bool) { return float64(s.started), true }},
+	{"multiuploader_uploads_completed_total", "counter", "Uploads finished successfully.",
+		"multiuploader_uploads_completed_total",
+		func(s *providerStats) (float64, bool) { return float64(s.completed), true }},
+	{"multiuploader_uploads_failed_total", "counter", "Uploads that failed.",
+		"multiuploader_uploads_failed_total",
+		func(s *providerStats) (float64, bool) { return float64(s.failed), true }},
+	{"multiuploader_uploaded_bytes_total", "counter", "Bytes of successfully uploaded files.",
+		"multiuploader_uploaded_bytes_total",
+		func(s *providerStats) (float64, bool) { return float64(s.bytes), true }},
+	{"multiuploader_upload_duration_seconds", "summary", "Duration of successful uploads.",
+		"multiuploader_upload_duration_seconds_sum",
+		func(s *providerStats) (float64, bool) { return s.durationSum, true }},
+	{"", "", "",
+		"multiuploader_upload_duration_seconds_count",
+		func(s *providerStats) (float64, bool) { return float64(s.durationCount), true }},
+	{"multiuploader_probe_latency_seconds", "gauge", "Latency of the last availability check.",
+		"multiuploader_probe_latency_seconds",
+		func(s *providerStats) (float64, bool) { return s.probeLatency, s.probed }},
+	{"multiuploader_probe_up", "gauge", "Whether the last availability check succeeded.",
+		"multiuploader_probe_up",
+		func(s *providerStats) (float64, bool) {
+			if s.probeUp {
+				return 1, s.probed
+			}
+			return 0, s.probed
+		}},
+}
+
+// WriteTo выводит счетчики в текстовом формате Prometheus
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.providers))
+	snapshot := make(map[string]providerStats, len(r.providers))
+	for name, s := range r.providers {
+		names = append(names, name)
+		snapshot[name] = *s
+	}
+	r.mu.Unlock()
+	slices.Sort(names)
+
+	var b strings.Builder
+	for _, m := range metricsList {
+		if m.family != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", m.family, m.help, m.family, m.kind)
+		}
+		for _, name := range names {
+			s := snapshot[name]
+			if value, ok := m.value(&s); ok {
+				fmt.Fprintf(&b, "%s{provider=%s} %s\n", m.name, strconv.Quote(name), strconv.FormatFloat(value, 'g', -1, 64))
+			}
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Handler отдает счетчики по HTTP
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = r.WriteTo(w)
+	})
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistryWriteTo(t *testing.T) {
+	r := NewRegistry()
+	r.UploadStarted("Catbox")
+	r.UploadStarted("Catbox")
+	r.UploadCompleted("Catbox", 2048, 1500*time.Millisecond)
+	r.UploadFailed("Catbox")
+	r.UploadStarted("Gofile")
+	r.ProbeFinished("Gofile", 250*time.Millisecond, true)
+
+	var b strings.Builder
+	if _, err := r.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	for _, line := range []string{
+		"# TYPE multiuploader_uploads_started_total counter",
+		`multiuploader_uploads_started_total{provider="Catbox"} 2`,
+		`multiuploader_uploads_started_total{provider="Gofile"} 1`,
+		`multiuploader_uploads_completed_total{provider="Catbox"} 1`,
+		`multiuploader_uploads_failed_total{provider="Catbox"} 1`,
+		`multiuploader_uploaded_bytes_total{provider="Catbox"} 2048`,
+		"# TYPE multiuploader_upload_duration_seconds summary",
+		`multiuploader_upload_duration_seconds_sum{provider="Catbox"} 1.5`,
+		`multiuploader_upload_duration_seconds_count{provider="Catbox"} 1`,
+		`multiuploader_probe_latency_seconds{provider="Gofile"} 0.25`,
+		`multiuploader_probe_up{provider="Gofile"} 1`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("output missing %q:\n%s", line, out)
+		}
+	}
+
+	// Провайдер без проверки доступности не выводит ее метрики
+	if strings.Contains(out, `multiuploader_probe_up{provider="Catbox"}`) {
+		t.Errorf("unexpected probe metric for unchecked provider:\n%s", out)
+	}
+}
+
+func TestServer(t *testing.T) {
+	r := NewRegistry()
+	r.UploadStarted("Catbox")
+
+	s, err := Listen(r, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if !strings.HasPrefix(s.Addr(), "127.0.0.1:") {
+		t.Errorf("Addr() = %q, want loopback", s.Addr())
+	}
+
+	resp, err := http.Get("http://" + s.Addr() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Errorf("Content-Type = %q", resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), `multiuploader_uploads_started_total{provider="Catbox"} 1`) {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+
+	resp, err = http.Post("http://"+s.Addr()+"/metrics", "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", resp.StatusCode)
+	}
+}
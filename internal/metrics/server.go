@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// shutdownTimeout сколько ждать завершения текущих запросов при остановке
+const shutdownTimeout = 2 * time.Second
+
+// Server отдает метрики по адресу http://127.0.0.1:<port>/metrics
+type Server struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+// Listen запускает endpoint метрик на loopback, чтобы счетчики не были видны из сети
+func Listen(registry *Registry, port int) (*Server, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+
+	s := &Server{
+		server:   &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second},
+		listener: listener,
+	}
+	// Serve возвращает ошибку только после Close
+	go s.server.Serve(listener)
+
+	return s, nil
+}
+
+// Addr адрес, на котором слушает сервер
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close останавливает сервер
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
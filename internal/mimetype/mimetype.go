@@ -0,0 +1,89 @@
+// Package mimetype определяет MIME тип файла по содержимому и расширению
+package mimetype
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// Default тип для файлов, которые не удалось распознать
+	Default = "application/octet-stream"
+	// SniffLen сколько первых байт файла нужно для определения типа
+	SniffLen = 512
+)
+
+// extraTypes расширения, которых нет во встроенной таблице Go и которые часто загружают
+// Системная таблица (mime.types, реестр Windows) есть не везде, поэтому основные типы заданы здесь
+var extraTypes = map[string]string{
+	".7z":   "application/x-7z-compressed",
+	".apk":  "application/vnd.android.package-archive",
+	".avi":  "video/x-msvideo",
+	".csv":  "text/csv",
+	".epub": "application/epub+zip",
+	".flac": "audio/flac",
+	".gz":   "application/gzip",
+	".iso":  "application/x-iso9660-image",
+	".log":  "text/plain",
+	".m4a":  "audio/mp4",
+	".md":   "text/markdown",
+	".mkv":  "video/x-matroska",
+	".mov":  "video/quicktime",
+	".mp3":  "audio/mpeg",
+	".mp4":  "video/mp4",
+	".ogg":  "audio/ogg",
+	".rar":  "application/vnd.rar",
+	".tar":  "application/x-tar",
+	".txt":  "text/plain",
+	".wav":  "audio/wav",
+	".webm": "video/webm",
+	".zip":  "application/zip",
+}
+
+// Detect определяет MIME тип по первым байтам файла (до SniffLen) и имени, без параметров
+// Содержимое надежнее для двоичных форматов, расширение - для текстовых (JSON, CSV, исходники)
+func Detect(head []byte, name string) string {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	byExt := ByExtension(name)
+
+	if byExt != "" && (sniffed == Default || sniffed == "text/plain") {
+		return byExt
+	}
+	if sniffed == "" {
+		return Default
+	}
+	return sniffed
+}
+
+// ByExtension возвращает MIME тип по расширению имени файла ("" - расширение неизвестно)
+func ByExtension(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == "" {
+		return ""
+	}
+	if mediaType, _, err := mime.ParseMediaType(mime.TypeByExtension(ext)); err == nil {
+		return mediaType
+	}
+	return extraTypes[ext]
+}
+
+// DetectReader читает начало файла и возвращает позицию чтения в исходную
+func DetectReader(r io.ReadSeeker, name string) (string, error) {
+	pos, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", err
+	}
+
+	head := make([]byte, SniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	if _, err := r.Seek(pos, io.SeekStart); err != nil {
+		return "", err
+	}
+	return Detect(head[:n], name), nil
+}
@@ -0,0 +1,50 @@
+package mimetype
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestDetect проверяет выбор между содержимым и расширением
+func TestDetect(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")
+	for _, tc := range []struct {
+		head []byte
+		name string
+		want string
+	}{
+		{png, "image.png", "image/png"},
+		// Двоичный формат определяется по содержимому, даже если расширение другое
+		{png, "image.txt", "image/png"},
+		{[]byte(`{"a": 1}`), "data.json", "application/json"},
+		{[]byte("a,b\n1,2\n"), "table.CSV", "text/csv"},
+		{[]byte("plain text"), "notes", "text/plain"},
+		{[]byte{0x37, 0x7A, 0xBC, 0xAF, 0x27, 0x1C}, "archive.7z", "application/x-7z-compressed"},
+		{[]byte{0, 1, 2, 3}, "blob", Default},
+		{nil, "empty", "text/plain"},
+	} {
+		if got := Detect(tc.head, tc.name); got != tc.want {
+			t.Errorf("Detect(%q) = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+// TestDetectReader проверяет, что позиция чтения возвращается на место
+func TestDetectReader(t *testing.T) {
+	r := strings.NewReader("skip <html><body>page</body></html>")
+	r.Seek(5, io.SeekStart)
+
+	got, err := DetectReader(r, "page.bin")
+	if err != nil {
+		t.Fatalf("DetectReader() = %v", err)
+	}
+	if got != "text/html" {
+		t.Errorf("DetectReader() = %q, want text/html", got)
+	}
+	rest, _ := io.ReadAll(r)
+	if !bytes.HasPrefix(rest, []byte("<html>")) {
+		t.Errorf("position after DetectReader() = %q, want unchanged", rest)
+	}
+}
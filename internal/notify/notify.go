@@ -0,0 +1,43 @@
+package notify
+
+import "errors"
+
+// ErrUnsupported возвращается, если платформа не поддерживает уведомления с действиями
+// В этом случае вызывающий код должен показать обычное уведомление
+var ErrUnsupported = errors.New("actionable notifications are not supported on this platform")
+
+// Action действие уведомления (кнопка или клик по самому уведомлению)
+type Action struct {
+	// Label текст кнопки
+	Label string
+
+	// URL открывается при выборе действия (поддерживается на всех платформах)
+	URL string
+
+	// Callback вызывается при выборе действия, если платформа умеет возвращать событие в приложение
+	// Вызывается из горутины
+	Callback func()
+}
+
+// Notification уведомление с действиями
+type Notification struct {
+	// AppID идентификатор приложения (нужен Windows для отображения toast)
+	AppID string
+
+	// AppName имя приложения, отображаемое системой
+	AppName string
+
+	Title   string
+	Content string
+
+	// Default действие при клике по уведомлению (может быть nil)
+	Default *Action
+
+	// Actions дополнительные кнопки
+	Actions []Action
+}
+
+// Send отправляет уведомление с действиями через нативный механизм ОС
+func Send(n Notification) error {
+	return send(n)
+}
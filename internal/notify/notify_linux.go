@@ -0,0 +1,134 @@
+//go:build !android
+
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusDest      = "org.freedesktop.Notifications"
+	dbusPath      = "/org/freedesktop/Notifications"
+	dbusInterface = "org.freedesktop.Notifications"
+
+	// defaultActionKey ключ действия по клику на уведомление (спецификация freedesktop)
+	defaultActionKey = "default"
+
+	// actionWaitTimeout сколько ждем выбора действия, прежде чем перестать слушать сигналы
+	actionWaitTimeout = 30 * time.Minute
+)
+
+// send отправляет уведомление через org.freedesktop.Notifications с действиями
+func send(n Notification) error {
+	conn, err := dbus.SessionBus() // shared connection, не закрываем
+	if err != nil {
+		return fmt.Errorf("failed to connect to session bus: %w", err)
+	}
+
+	// Список действий в формате [key1, label1, key2, label2, ...]
+	handlers := make(map[string]Action)
+	actions := make([]string, 0, 2*(len(n.Actions)+1))
+	if n.Default != nil {
+		handlers[defaultActionKey] = *n.Default
+		actions = append(actions, defaultActionKey, n.Default.Label)
+	}
+	for i, action := range n.Actions {
+		key := "action-" + strconv.Itoa(i)
+		handlers[key] = action
+		actions = append(actions, key, action.Label)
+	}
+
+	// Подписываемся до отправки, чтобы не пропустить быстрый клик
+	matchOptions := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(dbusPath),
+		dbus.WithMatchInterface(dbusInterface),
+	}
+	signals := make(chan *dbus.Signal, 10)
+	if len(handlers) > 0 {
+		if err := conn.AddMatchSignal(matchOptions...); err != nil {
+			return fmt.Errorf("failed to subscribe to notification signals: %w", err)
+		}
+		conn.Signal(signals)
+	}
+
+	unsubscribe := func() {
+		if len(handlers) == 0 {
+			return
+		}
+		conn.RemoveSignal(signals)
+		_ = conn.RemoveMatchSignal(matchOptions...)
+	}
+
+	obj := conn.Object(dbusDest, dbusPath)
+	call := obj.Call(dbusInterface+".Notify", 0,
+		n.AppName, uint32(0), "", n.Title, n.Content, actions,
+		map[string]dbus.Variant{}, int32(-1))
+	if call.Err != nil {
+		unsubscribe()
+		return fmt.Errorf("failed to send notification: %w", call.Err)
+	}
+
+	var id uint32
+	if err := call.Store(&id); err != nil {
+		unsubscribe()
+		return fmt.Errorf("failed to read notification id: %w", err)
+	}
+
+	if len(handlers) > 0 {
+		go waitForAction(id, handlers, signals, unsubscribe)
+	}
+
+	return nil
+}
+
+// waitForAction ждет выбора действия или закрытия уведомления
+func waitForAction(id uint32, handlers map[string]Action, signals <-chan *dbus.Signal, unsubscribe func()) {
+	defer unsubscribe()
+
+	timeout := time.NewTimer(actionWaitTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case <-timeout.C:
+			return
+		case sig, ok := <-signals:
+			if !ok {
+				return
+			}
+			if len(sig.Body) < 2 {
+				continue
+			}
+			if sigID, ok := sig.Body[0].(uint32); !ok || sigID != id {
+				continue
+			}
+
+			switch sig.Name {
+			case dbusInterface + ".ActionInvoked":
+				key, _ := sig.Body[1].(string)
+				if action, ok := handlers[key]; ok {
+					runAction(action)
+				}
+				return
+			case dbusInterface + ".NotificationClosed":
+				return
+			}
+		}
+	}
+}
+
+// runAction выполняет действие: callback приложения или открытие URL
+func runAction(action Action) {
+	if action.Callback != nil {
+		action.Callback()
+		return
+	}
+	if action.URL != "" {
+		_ = exec.Command("xdg-open", action.URL).Start()
+	}
+}
@@ -0,0 +1,8 @@
+//go:build (!linux && !windows) || android
+
+package notify
+
+// send на остальных платформах (macOS без bundle-API, мобильные) не поддерживается
+func send(n Notification) error {
+	return ErrUnsupported
+}
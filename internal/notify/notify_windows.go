@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+)
+
+// toastTemplate toast-уведомление с protocol-активацией
+// Windows открывает URL сам, поэтому поддерживаются только действия с URL
+const toastTemplate = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] > $null
+$xml = New-Object Windows.Data.Xml.Dom.XmlDocument
+$xml.LoadXml(@'
+%s
+'@)
+$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('%s').Show($toast)`
+
+var scriptNum atomic.Int64
+
+// send показывает toast через PowerShell (как это делает Fyne)
+func send(n Notification) error {
+	var sb strings.Builder
+
+	sb.WriteString(`<toast`)
+	if n.Default != nil && n.Default.URL != "" {
+		sb.WriteString(` activationType="protocol" launch="` + escapeXML(n.Default.URL) + `"`)
+	}
+	sb.WriteString(`><visual><binding template="ToastGeneric">`)
+	sb.WriteString(`<text>` + escapeXML(n.Title) + `</text>`)
+	sb.WriteString(`<text>` + escapeXML(n.Content) + `</text>`)
+	sb.WriteString(`</binding></visual>`)
+
+	actions := make([]Action, 0, len(n.Actions))
+	for _, action := range n.Actions {
+		if action.URL != "" {
+			actions = append(actions, action)
+		}
+	}
+	if len(actions) > 0 {
+		sb.WriteString(`<actions>`)
+		for _, action := range actions {
+			sb.WriteString(`<action activationType="protocol" content="` + escapeXML(action.Label) +
+				`" arguments="` + escapeXML(action.URL) + `"/>`)
+		}
+		sb.WriteString(`</actions>`)
+	}
+	sb.WriteString(`</toast>`)
+
+	appID := strings.ReplaceAll(n.AppID, "'", "''")
+	script := fmt.Sprintf(toastTemplate, sb.String(), appID)
+
+	fileName := fmt.Sprintf("multiuploader-notify-%d.ps1", scriptNum.Add(1))
+	path := filepath.Join(os.TempDir(), fileName)
+	if err := os.WriteFile(path, []byte(script), 0o600); err != nil {
+		return fmt.Errorf("failed to write notification script: %w", err)
+	}
+
+	go func() {
+		defer os.Remove(path)
+
+		launch := "(Get-Content -Encoding UTF8 -Path " + path + " -Raw) | Invoke-Expression"
+		cmd := exec.Command("PowerShell", "-ExecutionPolicy", "Bypass", launch)
+		cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+		_ = cmd.Run()
+	}()
+
+	return nil
+}
+
+// escapeXML экранирует текст для XML (включая апостроф, чтобы не закрыть here-string PowerShell)
+func escapeXML(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return strings.ReplaceAll(sb.String(), "'", "&apos;")
+}
@@ -0,0 +1,61 @@
+// Package ontop закрепляет окно поверх остальных средствами ОС (в Fyne такой настройки нет)
+package ontop
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver"
+)
+
+// ErrNotSupported ОС или оконная система не дает закрепить окно (macOS, Wayland)
+var ErrNotSupported = errors.New("keeping the window on top is not supported on this system")
+
+// errNotShown окно еще не создано оконной системой
+var errNotShown = errors.New("window is not shown")
+
+// Set закрепляет окно поверх остальных или снимает закрепление
+// Вызывается из главного потока после Show
+func Set(w fyne.Window, on bool) error {
+	native, ok := w.(driver.NativeWindow)
+	if !ok {
+		return ErrNotSupported
+	}
+
+	err := ErrNotSupported
+	native.RunNative(func(context any) {
+		switch ctx := context.(type) {
+		case driver.WindowsWindowContext:
+			if ctx.HWND == 0 {
+				err = errNotShown
+				return
+			}
+			err = setTopmost(ctx.HWND, on)
+		case driver.X11WindowContext:
+			if ctx.WindowHandle == 0 {
+				err = errNotShown
+				return
+			}
+			err = setAboveX11(ctx.WindowHandle, on)
+		}
+	})
+	return err
+}
+
+// setAboveX11 просит оконный менеджер X11 держать окно выше остальных через wmctrl
+func setAboveX11(window uintptr, on bool) error {
+	action := "remove,above"
+	if on {
+		action = "add,above"
+	}
+	cmd := exec.Command("wmctrl", "-i", "-r", fmt.Sprintf("0x%x", window), "-b", action)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return fmt.Errorf("%w: install wmctrl", ErrNotSupported)
+		}
+		return fmt.Errorf("wmctrl failed: %w: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package ontop
+
+// setTopmost окна Win32 бывают только в Windows
+func setTopmost(hwnd uintptr, on bool) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,19 @@
+package ontop
+
+import (
+	"errors"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+// TestSetWithoutNativeWindow проверяет отказ для окна без доступа к оконной системе
+func TestSetWithoutNativeWindow(t *testing.T) {
+	test.NewTempApp(t)
+	w := test.NewWindow(nil)
+	defer w.Close()
+
+	if err := Set(w, true); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Set() = %v, want ErrNotSupported", err)
+	}
+}
@@ -0,0 +1,32 @@
+package ontop
+
+import (
+	"fmt"
+	"syscall"
+)
+
+const (
+	swpNoSize     = 0x0001
+	swpNoMove     = 0x0002
+	swpNoActivate = 0x0010
+)
+
+var (
+	setWindowPos = syscall.NewLazyDLL("user32.dll").NewProc("SetWindowPos")
+
+	// hwndTopmost и hwndNoTopmost особые значения hWndInsertAfter (-1 и -2)
+	hwndTopmost   = ^uintptr(0)
+	hwndNoTopmost = ^uintptr(1)
+)
+
+// setTopmost меняет порядок окна через SetWindowPos, не трогая размер и положение
+func setTopmost(hwnd uintptr, on bool) error {
+	after := hwndNoTopmost
+	if on {
+		after = hwndTopmost
+	}
+	if r, _, err := setWindowPos.Call(hwnd, after, 0, 0, 0, 0, swpNoSize|swpNoMove|swpNoActivate); r == 0 {
+		return fmt.Errorf("SetWindowPos failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Package power не дает системе уснуть во время загрузок и выключает компьютер по завершении очереди
+package power
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrUnsupported возвращается, если платформа не поддерживает операцию
+var ErrUnsupported = errors.New("power management is not supported on this platform")
+
+// inhibitSleep берет запрет сна у ОС (переопределяется в тестах)
+var inhibitSleep = inhibit
+
+// Inhibitor запрет сна системы
+// Acquire и Release можно вызывать многократно: запрет держится, пока он взят
+type Inhibitor struct {
+	reason string
+
+	mu      sync.Mutex
+	release func()
+}
+
+// NewInhibitor создает запрет сна; reason показывается системой в списке блокировок
+func NewInhibitor(reason string) *Inhibitor {
+	return &Inhibitor{reason: reason}
+}
+
+// Acquire запрещает сон, если запрет еще не взят
+func (i *Inhibitor) Acquire() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.release != nil {
+		return nil
+	}
+	release, err := inhibitSleep(i.reason)
+	if err != nil {
+		return err
+	}
+	i.release = release
+	return nil
+}
+
+// Release снимает запрет сна
+func (i *Inhibitor) Release() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.release != nil {
+		i.release()
+		i.release = nil
+	}
+}
+
+// Held сообщает, взят ли запрет
+func (i *Inhibitor) Held() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.release != nil
+}
+
+// Shutdown просит систему выключить компьютер
+func Shutdown() error {
+	return shutdown()
+}
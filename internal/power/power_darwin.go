@@ -0,0 +1,33 @@
+//go:build !ios
+
+package power
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// inhibit запускает caffeinate, который не дает системе уснуть, пока работает
+// -w завершает caffeinate вместе с приложением, даже если оно упало
+func inhibit(reason string) (func(), error) {
+	cmd := exec.Command("caffeinate", "-i", "-w", strconv.Itoa(os.Getpid()))
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start caffeinate: %w", err)
+	}
+
+	return func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}, nil
+}
+
+// shutdown выключает компьютер через System Events (как пункт меню "Выключить")
+func shutdown() error {
+	out, err := exec.Command("osascript", "-e", `tell application "System Events" to shut down`).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("osascript failed: %w: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+//go:build !android
+
+package power
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	login1Dest    = "org.freedesktop.login1"
+	login1Path    = "/org/freedesktop/login1"
+	login1Manager = "org.freedesktop.login1.Manager"
+)
+
+// inhibit берет блокировку сна у systemd-logind
+// Блокировка действует, пока открыт полученный дескриптор
+func inhibit(reason string) (func(), error) {
+	conn, err := dbus.SystemBus() // shared connection, не закрываем
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to system bus: %w", err)
+	}
+
+	var fd dbus.UnixFD
+	err = conn.Object(login1Dest, login1Path).
+		Call(login1Manager+".Inhibit", 0, "sleep:idle", "multiUploader", reason, "block").
+		Store(&fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inhibit sleep: %w", err)
+	}
+
+	return func() { syscall.Close(int(fd)) }, nil
+}
+
+// shutdown выключает компьютер через systemd-logind (polkit может запросить пароль)
+func shutdown() error {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return fmt.Errorf("failed to connect to system bus: %w", err)
+	}
+
+	call := conn.Object(login1Dest, login1Path).Call(login1Manager+".PowerOff", 0, true)
+	if call.Err != nil {
+		return fmt.Errorf("failed to power off: %w", call.Err)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build (!linux && !darwin && !windows) || android || ios
+
+package power
+
+func inhibit(reason string) (func(), error) {
+	return nil, ErrUnsupported
+}
+
+func shutdown() error {
+	return ErrUnsupported
+}
@@ -0,0 +1,29 @@
+package power
+
+import "testing"
+
+// TestInhibitor проверяет, что запрет сна берется и снимается один раз
+func TestInhibitor(t *testing.T) {
+	acquired, released := 0, 0
+	inhibitSleep = func(reason string) (func(), error) {
+		acquired++
+		return func() { released++ }, nil
+	}
+	t.Cleanup(func() { inhibitSleep = inhibit })
+
+	i := NewInhibitor("test")
+	for range 2 {
+		if err := i.Acquire(); err != nil {
+			t.Fatalf("Acquire() = %v", err)
+		}
+	}
+	if !i.Held() || acquired != 1 {
+		t.Fatalf("Held() = %v, acquired %d times, want held once", i.Held(), acquired)
+	}
+
+	i.Release()
+	i.Release()
+	if i.Held() || released != 1 {
+		t.Errorf("Held() = %v, released %d times, want released once", i.Held(), released)
+	}
+}
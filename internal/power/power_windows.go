@@ -0,0 +1,56 @@
+package power
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"syscall"
+)
+
+const (
+	esContinuous     = 0x80000000
+	esSystemRequired = 0x00000001
+)
+
+var setThreadExecutionState = syscall.NewLazyDLL("kernel32.dll").NewProc("SetThreadExecutionState")
+
+// inhibit запрещает сон через SetThreadExecutionState
+// Состояние привязано к потоку, поэтому запрет держит отдельная горутина на закрепленном потоке
+func inhibit(reason string) (func(), error) {
+	errc := make(chan error, 1)
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer close(stopped)
+
+		if r, _, err := setThreadExecutionState.Call(esContinuous | esSystemRequired); r == 0 {
+			errc <- fmt.Errorf("SetThreadExecutionState failed: %w", err)
+			return
+		}
+		errc <- nil
+
+		<-stop
+		setThreadExecutionState.Call(esContinuous)
+	}()
+
+	if err := <-errc; err != nil {
+		return nil, err
+	}
+	return func() {
+		close(stop)
+		<-stopped
+	}, nil
+}
+
+// shutdown выключает компьютер через shutdown.exe
+func shutdown() error {
+	cmd := exec.Command("shutdown", "/s", "/t", "0")
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("shutdown failed: %w: %s", err, out)
+	}
+	return nil
+}
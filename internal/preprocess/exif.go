@@ -0,0 +1,117 @@
+package preprocess
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"io"
+)
+
+// exifOrientationTag тег EXIF с поворотом снимка
+const exifOrientationTag = 0x0112
+
+// exifOrientation читает поворот из EXIF заголовка JPEG (1 - без поворота)
+func exifOrientation(r io.Reader) int {
+	br := bufio.NewReader(r)
+	var marker [2]byte
+	if _, err := io.ReadFull(br, marker[:]); err != nil || marker != [2]byte{0xFF, 0xD8} {
+		return 1
+	}
+
+	// Идем по сегментам до начала данных картинки (SOS)
+	for {
+		if _, err := io.ReadFull(br, marker[:]); err != nil || marker[0] != 0xFF || marker[1] == 0xDA {
+			return 1
+		}
+		var length uint16
+		if err := binary.Read(br, binary.BigEndian, &length); err != nil || length < 2 {
+			return 1
+		}
+		segment := make([]byte, length-2)
+		if _, err := io.ReadFull(br, segment); err != nil {
+			return 1
+		}
+		if marker[1] == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+	}
+}
+
+// tiffOrientation ищет тег поворота в первом каталоге TIFF заголовка
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := range count {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			if o := int(order.Uint16(tiff[entry+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// orient поворачивает и отражает картинку по значению EXIF Orientation
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	src := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+	w, h := b.Dx(), b.Dy()
+
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := range dstH {
+		for x := range dstW {
+			var sx, sy int
+			switch orientation {
+			case 2: // отражение по горизонтали
+				sx, sy = w-1-x, y
+			case 3: // поворот на 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // отражение по вертикали
+				sx, sy = x, h-1-y
+			case 5: // отражение по главной диагонали
+				sx, sy = y, x
+			case 6: // поворот на 90° по часовой
+				sx, sy = y, h-1-x
+			case 7: // отражение по побочной диагонали
+				sx, sy = w-1-y, h-1-x
+			case 8: // поворот на 90° против часовой
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):][:4], src.Pix[src.PixOffset(sx, sy):][:4])
+		}
+	}
+	return dst
+}
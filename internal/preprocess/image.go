@@ -0,0 +1,195 @@
+// Package preprocess готовит файлы перед загрузкой: уменьшает и пережимает картинки
+package preprocess
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/draw"
+
+	_ "golang.org/x/image/bmp" // форматы для image.Decode
+	_ "golang.org/x/image/webp"
+)
+
+// Format формат, в который сохраняется обработанная картинка
+type Format string
+
+const (
+	// FormatKeep формат исходного файла (WebP сохраняется в JPEG, BMP - в PNG)
+	FormatKeep Format = ""
+	FormatJPEG Format = "jpeg"
+	FormatPNG  Format = "png"
+)
+
+// DefaultQuality качество JPEG по умолчанию
+const DefaultQuality = 85
+
+// ImageOptions настройки обработки картинок
+type ImageOptions struct {
+	// Enabled обрабатывать картинки перед загрузкой
+	Enabled bool
+	// MaxWidth и MaxHeight наибольший размер в пикселях (0 - без ограничения); картинки не увеличиваются
+	MaxWidth, MaxHeight int
+	// Format формат результата
+	Format Format
+	// Quality качество JPEG от 1 до 100 (0 - DefaultQuality)
+	Quality int
+}
+
+// ErrNotSupported формат файла не обрабатывается
+var ErrNotSupported = errors.New("image format is not supported")
+
+// supportedFormats форматы, которые можно обработать
+// GIF не обрабатывается: перекодирование потеряло бы анимацию
+var supportedFormats = map[string]bool{"jpeg": true, "png": true, "webp": true, "bmp": true}
+
+// Supported сообщает, может ли Image обработать файл
+func Supported(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	_, format, err := image.DecodeConfig(f)
+	return err == nil && supportedFormats[format]
+}
+
+// Image уменьшает картинку src до заданного размера и пережимает ее в папку dir
+// Возвращает путь к результату или "", если картинку не нужно менять:
+// размер уже в пределах, а пережатый файл не меньше исходного
+func Image(src, dir string, opts ImageOptions) (string, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	img, format, err := image.Decode(f)
+	if err != nil {
+		return "", err
+	}
+	if !supportedFormats[format] {
+		return "", ErrNotSupported
+	}
+
+	// Пиксели сохраняются без EXIF, поэтому поворот фотографии применяется заранее
+	if format == "jpeg" {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		}
+		img = orient(img, exifOrientation(f))
+	}
+
+	bounds := img.Bounds()
+	width, height := fit(bounds.Dx(), bounds.Dy(), opts.MaxWidth, opts.MaxHeight)
+	resized := width != bounds.Dx() || height != bounds.Dy()
+
+	out := outputFormat(format, opts.Format)
+	dst := filepath.Join(dir, outputName(filepath.Base(src), format, out))
+	if err := encode(dst, scale(img, width, height, out), out, opts.Quality); err != nil {
+		os.Remove(dst)
+		return "", err
+	}
+
+	// Без уменьшения пережатый файл нужен, только если он меньше исходного
+	if !resized {
+		srcInfo, err := f.Stat()
+		if err != nil {
+			os.Remove(dst)
+			return "", err
+		}
+		dstInfo, err := os.Stat(dst)
+		if err != nil {
+			return "", err
+		}
+		if dstInfo.Size() >= srcInfo.Size() {
+			os.Remove(dst)
+			return "", nil
+		}
+	}
+	return dst, nil
+}
+
+// fit вписывает размер в наибольший, сохраняя пропорции (0 - без ограничения)
+func fit(width, height, maxWidth, maxHeight int) (int, int) {
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		scale = float64(maxWidth) / float64(width)
+	}
+	if maxHeight > 0 && height > maxHeight {
+		scale = min(scale, float64(maxHeight)/float64(height))
+	}
+	if scale == 1 {
+		return width, height
+	}
+	return max(int(float64(width)*scale+0.5), 1), max(int(float64(height)*scale+0.5), 1)
+}
+
+// outputFormat формат результата: выбранный в настройках или исходный, если его можно записать
+func outputFormat(src string, format Format) Format {
+	if format != FormatKeep {
+		return format
+	}
+	switch src {
+	case "png", "bmp":
+		return FormatPNG
+	}
+	// Кодировщика WebP в стандартной библиотеке нет
+	return FormatJPEG
+}
+
+// outputName имя результата: исходное, если формат не изменился, иначе с расширением нового формата
+func outputName(name, src string, out Format) string {
+	if string(out) == src {
+		return name
+	}
+	ext := ".jpg"
+	if out == FormatPNG {
+		ext = ".png"
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name)) + ext
+}
+
+// scale уменьшает картинку; для JPEG прозрачные места заливаются белым
+func scale(img image.Image, width, height int, out Format) image.Image {
+	rect := image.Rect(0, 0, width, height)
+	if out == FormatJPEG {
+		dst := image.NewRGBA(rect)
+		draw.Draw(dst, rect, image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.CatmullRom.Scale(dst, rect, img, img.Bounds(), draw.Over, nil)
+		return dst
+	}
+	if width == img.Bounds().Dx() && height == img.Bounds().Dy() {
+		return img
+	}
+	dst := image.NewNRGBA(rect)
+	draw.CatmullRom.Scale(dst, rect, img, img.Bounds(), draw.Src, nil)
+	return dst
+}
+
+// encode записывает картинку в файл
+func encode(path string, img image.Image, out Format, quality int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if out == FormatPNG {
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(f, img)
+	} else {
+		if quality <= 0 || quality > 100 {
+			quality = DefaultQuality
+		}
+		err = jpeg.Encode(f, img, &jpeg.Options{Quality: quality})
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
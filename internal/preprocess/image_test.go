@@ -0,0 +1,177 @@
+package preprocess
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// noisy картинка, которая плохо сжимается (чтобы пережатый JPEG был меньше PNG)
+func noisy(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	rnd := rand.New(rand.NewPCG(1, 2))
+	for i := range img.Pix {
+		img.Pix[i] = byte(rnd.IntN(256))
+	}
+	return img
+}
+
+// writeImage сохраняет картинку в файл
+func writeImage(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// decodeFile читает размер и формат результата
+func decodeFile(t *testing.T, path string) (image.Config, string) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	cfg, format, err := image.DecodeConfig(f)
+	if err != nil {
+		t.Fatalf("result is not an image: %v", err)
+	}
+	return cfg, format
+}
+
+// TestImage проверяет уменьшение, смену формата и пропуск ненужной обработки
+func TestImage(t *testing.T) {
+	var pngData bytes.Buffer
+	if err := (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&pngData, noisy(400, 200)); err != nil {
+		t.Fatal(err)
+	}
+	src := writeImage(t, "shot.png", pngData.Bytes())
+
+	for _, tc := range []struct {
+		name       string
+		opts       ImageOptions
+		wantName   string
+		wantFormat string
+		wantW      int
+		wantH      int
+	}{
+		{"Resize keeps PNG", ImageOptions{MaxWidth: 100}, "shot.png", "png", 100, 50},
+		{"Both limits", ImageOptions{MaxWidth: 300, MaxHeight: 50}, "shot.png", "png", 100, 50},
+		{"Convert to JPEG", ImageOptions{Format: FormatJPEG, Quality: 70}, "shot.jpg", "jpeg", 400, 200},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Image(src, t.TempDir(), tc.opts)
+			if err != nil {
+				t.Fatalf("Image() = %v", err)
+			}
+			if filepath.Base(got) != tc.wantName {
+				t.Fatalf("result = %q, want %s", got, tc.wantName)
+			}
+			cfg, format := decodeFile(t, got)
+			if format != tc.wantFormat || cfg.Width != tc.wantW || cfg.Height != tc.wantH {
+				t.Errorf("result = %s %dx%d, want %s %dx%d", format, cfg.Width, cfg.Height, tc.wantFormat, tc.wantW, tc.wantH)
+			}
+		})
+	}
+
+	// Картинка уже в пределах, а пережатый PNG не меньше исходного - файл не меняется
+	got, err := Image(src, t.TempDir(), ImageOptions{MaxWidth: 1000})
+	if err != nil || got != "" {
+		t.Errorf("Image() of small image = %q, %v, want no result", got, err)
+	}
+}
+
+// TestImageNotImage проверяет ошибку для файла, который не является картинкой
+func TestImageNotImage(t *testing.T) {
+	src := writeImage(t, "notes.txt", []byte("text"))
+	if Supported(src) {
+		t.Error("Supported() = true for text file")
+	}
+	if _, err := Image(src, t.TempDir(), ImageOptions{MaxWidth: 10}); err == nil {
+		t.Error("Image() of text file succeeded")
+	}
+}
+
+// withOrientation вставляет в JPEG сегмент EXIF с поворотом
+func withOrientation(jpg []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2A\x00\x00\x00\x08\x00\x01")
+	tiff = binary.BigEndian.AppendUint16(tiff, exifOrientationTag)
+	tiff = append(tiff, 0, 3, 0, 0, 0, 1) // SHORT, 1 значение
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := binary.BigEndian.AppendUint16([]byte{0xFF, 0xE1}, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+	return append(append([]byte{0xFF, 0xD8}, app1...), jpg[2:]...)
+}
+
+// TestImageOrientation проверяет, что поворот из EXIF применяется к пикселям
+func TestImageOrientation(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := range 20 {
+		for x := range 40 {
+			img.Set(x, y, color.White)
+		}
+	}
+	// Левый верхний угол черный: после поворота на 90° по часовой он оказывается в правом верхнем
+	for y := range 8 {
+		for x := range 8 {
+			img.Set(x, y, color.Black)
+		}
+	}
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	src := writeImage(t, "photo.jpg", withOrientation(jpg.Bytes(), 6))
+
+	got, err := Image(src, t.TempDir(), ImageOptions{MaxWidth: 10})
+	if err != nil {
+		t.Fatalf("Image() = %v", err)
+	}
+	f, err := os.Open(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	result, err := jpeg.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b := result.Bounds(); b.Dx() != 10 || b.Dy() != 20 {
+		t.Fatalf("result size = %dx%d, want 10x20", b.Dx(), b.Dy())
+	}
+	dark := func(x, y int) bool {
+		r, _, _, _ := result.At(x, y).RGBA()
+		return r < 0x8000
+	}
+	if !dark(9, 0) || dark(0, 0) {
+		t.Error("black corner is not at the top right after rotation")
+	}
+}
+
+// TestFit проверяет вписывание в наибольший размер
+func TestFit(t *testing.T) {
+	for _, tc := range []struct{ w, h, maxW, maxH, wantW, wantH int }{
+		{4000, 3000, 1920, 1920, 1920, 1440},
+		{3000, 4000, 1920, 1920, 1440, 1920},
+		{800, 600, 1920, 1920, 800, 600},
+		{800, 600, 0, 300, 400, 300},
+		{5000, 1, 100, 0, 100, 1},
+	} {
+		if w, h := fit(tc.w, tc.h, tc.maxW, tc.maxH); w != tc.wantW || h != tc.wantH {
+			t.Errorf("fit(%d, %d, %d, %d) = %dx%d, want %dx%d", tc.w, tc.h, tc.maxW, tc.maxH, w, h, tc.wantW, tc.wantH)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package preview
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"strings"
+)
+
+// listArchive возвращает первые файлы zip, tar или tar.gz архива
+func listArchive(f io.ReaderAt, size int64, name string) ([]string, bool) {
+	lower := strings.ToLower(name)
+	if strings.HasSuffix(lower, ".tar") || strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") {
+		return listTar(io.NewSectionReader(f, 0, size), !strings.HasSuffix(lower, ".tar"))
+	}
+
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		return nil, false
+	}
+	var names []string
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if len(names) == maxArchiveEntries {
+			return names, true
+		}
+		names = append(names, file.Name)
+	}
+	return names, false
+}
+
+// listTar читает заголовки tar архива, пока не наберет maxArchiveEntries файлов
+// Сжатый архив читается последовательно, поэтому дальше первых файлов не идем
+func listTar(r io.Reader, compressed bool) ([]string, bool) {
+	if compressed {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, false
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			return names, false
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if len(names) == maxArchiveEntries {
+			return names, true
+		}
+		names = append(names, header.Name)
+	}
+}
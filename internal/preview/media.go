@@ -0,0 +1,188 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// maxBoxes сколько MP4 боксов просматривается в поисках длительности
+const maxBoxes = 64
+
+// readMedia читает длительность и теги: MP4/MOV (mvhd), WAV (заголовок), MP3 (ID3v2)
+func readMedia(f io.ReaderAt, size int64, mimeType string, info *Info) {
+	switch mimeType {
+	case "video/mp4", "audio/mp4", "video/quicktime":
+		info.Duration = mp4Duration(f, size)
+	case "audio/wave", "audio/wav", "audio/x-wav":
+		info.Duration = wavDuration(f, size)
+	case "audio/mpeg":
+		info.Title, info.Artist = id3Tags(f)
+	}
+}
+
+// mp4Duration находит бокс moov/mvhd и возвращает длительность ролика
+func mp4Duration(f io.ReaderAt, size int64) time.Duration {
+	moov, moovSize, ok := findBox(f, 0, size, "moov")
+	if !ok {
+		return 0
+	}
+	mvhd, _, ok := findBox(f, moov, moov+moovSize, "mvhd")
+	if !ok {
+		return 0
+	}
+
+	// version(1) flags(3), затем времена создания и изменения: 4 байта в версии 0, 8 - в версии 1
+	buf := make([]byte, 32)
+	n, _ := f.ReadAt(buf, mvhd)
+	var timescale, duration uint64
+	switch {
+	case n >= 32 && buf[0] == 1:
+		timescale = uint64(binary.BigEndian.Uint32(buf[20:24]))
+		duration = binary.BigEndian.Uint64(buf[24:32])
+	case n >= 20 && buf[0] == 0:
+		timescale = uint64(binary.BigEndian.Uint32(buf[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(buf[16:20]))
+	}
+	if timescale == 0 {
+		return 0
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+}
+
+// findBox ищет бокс с типом name в диапазоне [start, end) и возвращает начало и размер его содержимого
+func findBox(f io.ReaderAt, start, end int64, name string) (int64, int64, bool) {
+	header := make([]byte, 16)
+	for offset, i := start, 0; offset+8 <= end && i < maxBoxes; i++ {
+		if _, err := f.ReadAt(header[:8], offset); err != nil {
+			return 0, 0, false
+		}
+		boxSize := int64(binary.BigEndian.Uint32(header[:4]))
+		headerSize := int64(8)
+		switch boxSize {
+		case 0: // бокс до конца файла
+			boxSize = end - offset
+		case 1: // 64-битный размер
+			if _, err := f.ReadAt(header[8:16], offset+8); err != nil {
+				return 0, 0, false
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+		if boxSize < headerSize {
+			return 0, 0, false
+		}
+		if string(header[4:8]) == name {
+			return offset + headerSize, boxSize - headerSize, true
+		}
+		offset += boxSize
+	}
+	return 0, 0, false
+}
+
+// wavDuration считает длительность WAV по скорости потока и размеру данных
+func wavDuration(f io.ReaderAt, size int64) time.Duration {
+	header := make([]byte, 12)
+	if _, err := f.ReadAt(header, 0); err != nil || string(header[:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return 0
+	}
+
+	var byteRate uint32
+	chunk := make([]byte, 8)
+	for offset := int64(12); offset+8 <= size; {
+		if _, err := f.ReadAt(chunk, offset); err != nil {
+			return 0
+		}
+		chunkSize := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+		switch string(chunk[:4]) {
+		case "fmt ":
+			format := make([]byte, 16)
+			if _, err := f.ReadAt(format, offset+8); err != nil {
+				return 0
+			}
+			byteRate = binary.LittleEndian.Uint32(format[8:12])
+		case "data":
+			if byteRate == 0 {
+				return 0
+			}
+			return time.Duration(float64(chunkSize) / float64(byteRate) * float64(time.Second))
+		}
+		// Чанки выровнены по четной границе
+		offset += 8 + chunkSize + chunkSize%2
+	}
+	return 0
+}
+
+// id3Tags читает название и исполнителя из тега ID3v2 (версии 2.3 и 2.4)
+func id3Tags(f io.ReaderAt) (title, artist string) {
+	header := make([]byte, 10)
+	if _, err := f.ReadAt(header, 0); err != nil || string(header[:3]) != "ID3" {
+		return "", ""
+	}
+	version := header[3]
+	if version != 3 && version != 4 {
+		return "", ""
+	}
+
+	tag := make([]byte, syncsafe(header[6:10]))
+	n, _ := f.ReadAt(tag, 10)
+	tag = tag[:n]
+
+	for len(tag) >= 10 && tag[0] != 0 {
+		id := string(tag[:4])
+		frameSize := int(binary.BigEndian.Uint32(tag[4:8]))
+		if version == 4 {
+			frameSize = syncsafe(tag[4:8])
+		}
+		if frameSize <= 0 || 10+frameSize > len(tag) {
+			break
+		}
+		frame := tag[10 : 10+frameSize]
+		switch id {
+		case "TIT2":
+			title = id3Text(frame)
+		case "TPE1":
+			artist = id3Text(frame)
+		}
+		tag = tag[10+frameSize:]
+	}
+	return title, artist
+}
+
+// syncsafe число ID3, в каждом байте которого используются 7 бит
+func syncsafe(b []byte) int {
+	return int(b[0])<<21 | int(b[1])<<14 | int(b[2])<<7 | int(b[3])
+}
+
+// id3Text декодирует текстовый фрейм ID3: первый байт - кодировка
+func id3Text(frame []byte) string {
+	if len(frame) < 2 {
+		return ""
+	}
+	data := frame[1:]
+	switch frame[0] {
+	case 0: // ISO-8859-1
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return strings.TrimRight(string(runes), "\x00")
+	case 1, 2: // UTF-16 с BOM или без него (big endian)
+		order := binary.ByteOrder(binary.BigEndian)
+		if bytes.HasPrefix(data, []byte{0xFF, 0xFE}) {
+			order, data = binary.LittleEndian, data[2:]
+		} else if bytes.HasPrefix(data, []byte{0xFE, 0xFF}) {
+			data = data[2:]
+		}
+		units := make([]uint16, 0, len(data)/2)
+		for i := 0; i+1 < len(data); i += 2 {
+			units = append(units, order.Uint16(data[i:]))
+		}
+		return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+	default: // UTF-8
+		return strings.TrimRight(string(data), "\x00")
+	}
+}
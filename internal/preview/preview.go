@@ -0,0 +1,137 @@
+// Package preview собирает сведения о файле для предпросмотра перед загрузкой:
+// тип, дату изменения и содержимое (размер картинки, начало текста, список архива, теги аудио и видео)
+package preview
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif" // форматы для image.DecodeConfig
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"multiUploader/internal/mimetype"
+)
+
+const (
+	// textHeadSize сколько байт текста показывается в предпросмотре
+	textHeadSize = 2048
+	// textHeadLines сколько строк текста показывается в предпросмотре
+	textHeadLines = 12
+	// maxArchiveEntries сколько файлов архива показывается в списке
+	maxArchiveEntries = 20
+)
+
+// Kind вид содержимого файла
+type Kind int
+
+const (
+	KindOther Kind = iota
+	KindImage
+	KindText
+	KindAudio
+	KindVideo
+	KindArchive
+)
+
+// Info сведения о файле для предпросмотра
+type Info struct {
+	Name     string
+	Size     int64
+	ModTime  time.Time
+	MIMEType string
+	Kind     Kind
+
+	// Width и Height размер картинки (0, если формат не поддерживается)
+	Width, Height int
+
+	// TextHead начало текстового файла
+	TextHead string
+
+	// Entries первые файлы архива; More - в архиве есть еще файлы
+	Entries []string
+	More    bool
+
+	// Duration, Title и Artist сведения об аудио и видео (пусто, если не удалось прочитать)
+	Duration      time.Duration
+	Title, Artist string
+}
+
+// Inspect читает сведения о файле
+// Ошибки разбора содержимого не возвращаются: предпросмотр показывает то, что удалось прочитать
+func Inspect(path string) (Info, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Info{}, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return Info{}, err
+	}
+	info := Info{Name: filepath.Base(path), Size: stat.Size(), ModTime: stat.ModTime()}
+
+	head := make([]byte, mimetype.SniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return info, err
+	}
+	info.MIMEType = mimetype.Detect(head[:n], info.Name)
+	info.Kind = kindOf(info.MIMEType, info.Name)
+
+	switch info.Kind {
+	case KindImage:
+		if cfg, _, err := image.DecodeConfig(io.NewSectionReader(f, 0, info.Size)); err == nil {
+			info.Width, info.Height = cfg.Width, cfg.Height
+		}
+	case KindText:
+		info.TextHead = textHead(io.NewSectionReader(f, 0, textHeadSize))
+	case KindArchive:
+		info.Entries, info.More = listArchive(f, info.Size, info.Name)
+	case KindAudio, KindVideo:
+		readMedia(f, info.Size, info.MIMEType, &info)
+	}
+	return info, nil
+}
+
+// kindOf вид содержимого по MIME типу и имени
+func kindOf(mimeType, name string) Kind {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return KindImage
+	case strings.HasPrefix(mimeType, "audio/"):
+		return KindAudio
+	case strings.HasPrefix(mimeType, "video/"):
+		return KindVideo
+	case mimeType == "application/zip", mimeType == "application/x-tar",
+		strings.HasSuffix(lower, ".tar"), strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return KindArchive
+	case strings.HasPrefix(mimeType, "text/"), mimeType == "application/json", mimeType == "application/xml",
+		mimeType == "application/javascript", mimeType == "image/svg+xml":
+		return KindText
+	}
+	return KindOther
+}
+
+// textHead возвращает первые строки текста
+func textHead(r io.Reader) string {
+	data, _ := io.ReadAll(r)
+	// Отрезаем символ, попавший на границу чтения
+	for len(data) > 0 && !utf8.Valid(data) {
+		data = data[:len(data)-1]
+	}
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	lines := strings.Split(string(data), "\n")
+	if len(lines) > textHeadLines {
+		lines = lines[:textHeadLines]
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
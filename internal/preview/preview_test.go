@@ -0,0 +1,168 @@
+package preview
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile создает файл во временной папке
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// box собирает MP4 бокс
+func box(name string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	out := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(out, name...), body...)
+}
+
+// TestInspect проверяет тип, вид и содержимое файлов разных форматов
+func TestInspect(t *testing.T) {
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, image.NewRGBA(image.Rect(0, 0, 30, 20))); err != nil {
+		t.Fatal(err)
+	}
+
+	var zipData bytes.Buffer
+	zw := zip.NewWriter(&zipData)
+	for _, name := range []string{"a.txt", "dir/b.txt"} {
+		w, _ := zw.Create(name)
+		w.Write([]byte(name))
+	}
+	zw.Close()
+
+	var tgzData bytes.Buffer
+	gz := gzip.NewWriter(&tgzData)
+	tw := tar.NewWriter(gz)
+	for i := range maxArchiveEntries + 1 {
+		name := string(rune('a'+i)) + ".txt"
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 1, Typeflag: tar.TypeReg})
+		tw.Write([]byte("x"))
+	}
+	tw.Close()
+	gz.Close()
+
+	// mvhd версии 0: timescale 1000, длительность 90 с
+	mvhd := make([]byte, 20)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:], 90000)
+	mp4 := append(box("ftyp", []byte("isom\x00\x00\x02\x00isommp41")), box("moov", box("mvhd", mvhd))...)
+
+	// WAV: 8000 байт/с, 16000 байт данных
+	wav := []byte("RIFF\x00\x00\x00\x00WAVEfmt \x10\x00\x00\x00")
+	format := make([]byte, 16)
+	binary.LittleEndian.PutUint32(format[8:], 8000)
+	wav = append(wav, format...)
+	wav = append(wav, "data"...)
+	wav = binary.LittleEndian.AppendUint32(wav, 16000)
+	wav = append(wav, make([]byte, 16000)...)
+
+	// ID3v2.3 с названием в UTF-8 и исполнителем в ISO-8859-1
+	frame := func(id string, text []byte) []byte {
+		return append(append([]byte(id), binary.BigEndian.AppendUint32(nil, uint32(len(text)))...), append([]byte{0, 0}, text...)...)
+	}
+	frames := append(frame("TIT2", append([]byte{3}, "Песня"...)), frame("TPE1", []byte("\x00Caf\xe9"))...)
+	mp3 := append([]byte{'I', 'D', '3', 3, 0, 0, 0, 0, 0, byte(len(frames))}, frames...)
+	mp3 = append(mp3, 0xFF, 0xFB, 0x90, 0x00)
+
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = "line"
+	}
+
+	for _, tc := range []struct {
+		name  string
+		data  []byte
+		mime  string
+		kind  Kind
+		check func(t *testing.T, info Info)
+	}{
+		{"image.png", pngData.Bytes(), "image/png", KindImage, func(t *testing.T, info Info) {
+			if info.Width != 30 || info.Height != 20 {
+				t.Errorf("size = %dx%d, want 30x20", info.Width, info.Height)
+			}
+		}},
+		// Картинка с неверным расширением определяется по содержимому
+		{"image.txt", pngData.Bytes(), "image/png", KindImage, nil},
+		{"notes.txt", []byte(strings.Join(lines, "\r\n")), "text/plain", KindText, func(t *testing.T, info Info) {
+			if want := strings.Join(lines[:textHeadLines], "\n"); info.TextHead != want {
+				t.Errorf("TextHead = %q, want %q", info.TextHead, want)
+			}
+		}},
+		{"data.json", []byte(`{"a": 1}`), "application/json", KindText, nil},
+		{"files.zip", zipData.Bytes(), "application/zip", KindArchive, func(t *testing.T, info Info) {
+			if want := []string{"a.txt", "dir/b.txt"}; !slices.Equal(info.Entries, want) || info.More {
+				t.Errorf("Entries = %v, More = %v, want %v", info.Entries, info.More, want)
+			}
+		}},
+		{"files.tar.gz", tgzData.Bytes(), "application/x-gzip", KindArchive, func(t *testing.T, info Info) {
+			if len(info.Entries) != maxArchiveEntries || !info.More {
+				t.Errorf("Entries = %d, More = %v, want %d and more", len(info.Entries), info.More, maxArchiveEntries)
+			}
+		}},
+		{"clip.mp4", mp4, "video/mp4", KindVideo, func(t *testing.T, info Info) {
+			if info.Duration != 90*time.Second {
+				t.Errorf("Duration = %v, want 1m30s", info.Duration)
+			}
+		}},
+		{"sound.wav", wav, "audio/wave", KindAudio, func(t *testing.T, info Info) {
+			if info.Duration != 2*time.Second {
+				t.Errorf("Duration = %v, want 2s", info.Duration)
+			}
+		}},
+		{"song.mp3", mp3, "audio/mpeg", KindAudio, func(t *testing.T, info Info) {
+			if info.Title != "Песня" || info.Artist != "Café" {
+				t.Errorf("tags = %q by %q, want Песня by Café", info.Title, info.Artist)
+			}
+		}},
+		{"blob.bin", []byte{0, 1, 2, 3}, "application/octet-stream", KindOther, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			info, err := Inspect(writeFile(t, tc.name, tc.data))
+			if err != nil {
+				t.Fatalf("Inspect() = %v", err)
+			}
+			if info.MIMEType != tc.mime || info.Kind != tc.kind {
+				t.Fatalf("MIMEType, Kind = %q, %v, want %q, %v", info.MIMEType, info.Kind, tc.mime, tc.kind)
+			}
+			if info.Size != int64(len(tc.data)) || info.ModTime.IsZero() {
+				t.Errorf("Size, ModTime = %d, %v, want %d and modification time", info.Size, info.ModTime, len(tc.data))
+			}
+			if tc.check != nil {
+				tc.check(t, info)
+			}
+		})
+	}
+}
+
+// TestTextHeadCutsRune проверяет, что символ на границе чтения отбрасывается
+func TestTextHeadCutsRune(t *testing.T) {
+	data := []byte("ok Я")
+	if got := textHead(bytes.NewReader(data[:len(data)-1])); got != "ok " {
+		t.Errorf("textHead() = %q, want %q", got, "ok ")
+	}
+}
+
+// TestInspectMissing проверяет ошибку для отсутствующего файла
+func TestInspectMissing(t *testing.T) {
+	if _, err := Inspect(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Inspect() of missing file succeeded")
+	}
+}
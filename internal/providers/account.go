@@ -0,0 +1,22 @@
+package providers
+
+import (
+	"context"
+	"time"
+)
+
+// AccountInfo сведения об аккаунте провайдера
+type AccountInfo struct {
+	// StorageUsed занятое место в байтах
+	StorageUsed int64
+	// StorageQuota общий объем хранилища в байтах (0 - без ограничения или неизвестно)
+	StorageQuota int64
+	Premium      bool
+	// PremiumExpires окончание премиума (нулевое значение - неизвестно)
+	PremiumExpires time.Time
+}
+
+// AccountInfoProvider реализуется провайдерами, которые умеют отдавать сведения об аккаунте
+type AccountInfoProvider interface {
+	AccountInfo(ctx context.Context) (*AccountInfo, error)
+}
@@ -9,7 +9,6 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 
 	"multiUploader/internal/httpclient"
 )
@@ -21,6 +20,8 @@ const (
 // AkiraBoxProvider провайдер для AkiraBox.com
 type AkiraBoxProvider struct {
 	apiToken string
+
+	opts Options
 }
 
 // NewAkiraBoxProvider создает новый провайдер AkiraBox.com
@@ -36,6 +37,19 @@ func (a *AkiraBoxProvider) RequiresAuth() bool {
 	return true
 }
 
+// Probe проверяет доступность сервера AkiraBox
+func (a *AkiraBoxProvider) Probe(ctx context.Context) error {
+	return probeURL(ctx, akiraboxBaseURL)
+}
+
+func (a *AkiraBoxProvider) Capabilities() Capabilities {
+	return Capabilities{MaxFileSize: NoSizeLimit, Multipart: true}
+}
+
+func (a *AkiraBoxProvider) SetOptions(opts Options) {
+	a.opts = opts
+}
+
 func (a *AkiraBoxProvider) ValidateAPIKey(apiKey string) error {
 	if apiKey == "" {
 		return fmt.Errorf("API token is required")
@@ -45,28 +59,35 @@ func (a *AkiraBoxProvider) ValidateAPIKey(apiKey string) error {
 
 // Upload загружает файл на AkiraBox.com
 func (a *AkiraBoxProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
-	// 1. Инициализация upload
-	startData, err := a.startUpload(ctx, filename, fileSize)
-	if err != nil {
-		return nil, fmt.Errorf("start upload failed: %w", err)
-	}
-
-	// 2. Загружаем части
-	parts, err := a.uploadParts(ctx, file, fileSize, startData, progress)
-	if err != nil {
-		return nil, fmt.Errorf("upload parts failed: %w", err)
-	}
-
-	// 3. Завершаем upload
-	downloadLink, err := a.completeUpload(ctx, startData, parts)
-	if err != nil {
-		return nil, fmt.Errorf("complete upload failed: %w", err)
+	var startData *startUploadResponse
+	upload := &presignedUpload{
+		provider:    a.Name(),
+		contentType: fileContentType(file, filename),
+		opts:        a.opts,
+		init: func(ctx context.Context) (partLayout, error) {
+			var err error
+			startData, err = a.startUpload(ctx, filename, fileSize)
+			if err != nil {
+				return partLayout{}, err
+			}
+			return partLayout{ChunkSize: startData.ChunkSize, TotalParts: startData.TotalChunks}, nil
+		},
+		// URL запрашивается на каждую попытку, поэтому при повторе он всегда свежий
+		partURL: func(ctx context.Context, _ partLayout, partNum int, _ bool) (string, error) {
+			return a.getChunkURL(ctx, startData, partNum)
+		},
+		complete: func(ctx context.Context, parts []uploadedPart) (*UploadResult, error) {
+			downloadLink, err := a.completeUpload(ctx, startData, parts)
+			if err != nil {
+				return nil, err
+			}
+			return &UploadResult{
+				URL:         downloadLink,
+				DownloadURL: downloadLink,
+			}, nil
+		},
 	}
-
-	return &UploadResult{
-		URL:         downloadLink,
-		DownloadURL: downloadLink,
-	}, nil
+	return upload.run(ctx, file, fileSize, progress)
 }
 
 // startUploadResponse структура ответа от /api/upload/start
@@ -105,7 +126,7 @@ func (a *AkiraBoxProvider) startUpload(ctx context.Context, filename string, fil
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("start upload failed with status %d", resp.StatusCode)
+		return nil, statusError("start upload", resp)
 	}
 
 	var result startUploadResponse
@@ -149,7 +170,7 @@ func (a *AkiraBoxProvider) getChunkURL(ctx context.Context, startData *startUplo
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("get chunk URL failed with status %d", resp.StatusCode)
+		return "", statusError("get chunk URL", resp)
 	}
 
 	var result chunkURLResponse
@@ -160,118 +181,8 @@ func (a *AkiraBoxProvider) getChunkURL(ctx context.Context, startData *startUplo
 	return result.URL, nil
 }
 
-// uploadParts загружает все части файла
-func (a *AkiraBoxProvider) uploadParts(ctx context.Context, file io.ReadSeeker, fileSize int64, startData *startUploadResponse, progress chan<- UploadProgress) ([]map[string]interface{}, error) {
-	speedCalc := NewSpeedCalculator()
-	uploadedParts := make([]map[string]interface{}, startData.TotalChunks)
-	var totalUploaded int64
-
-	chunkSize := startData.ChunkSize
-
-	// Загружаем части последовательно
-	for partNum := 1; partNum <= startData.TotalChunks; partNum++ {
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("upload cancelled")
-		default:
-		}
-
-		// Вычисляем границы части
-		start := int64(partNum-1) * chunkSize
-		partSize := chunkSize
-		if start+partSize > fileSize {
-			partSize = fileSize - start
-		}
-
-		// Перемещаемся к началу части
-		_, err := file.Seek(start, io.SeekStart)
-		if err != nil {
-			return nil, fmt.Errorf("failed to seek to part %d: %w", partNum, err)
-		}
-
-		// Создаем LimitReader для чтения только текущего чанка
-		limitedReader := io.LimitReader(file, partSize)
-
-		// Получаем URL для загрузки
-		uploadURL, err := a.getChunkURL(ctx, startData, partNum)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get URL for part %d: %w", partNum, err)
-		}
-
-		// Загружаем часть с отслеживанием прогресса
-		etag, err := a.uploadPartWithProgress(ctx, uploadURL, limitedReader, partSize, &totalUploaded, fileSize, speedCalc, progress)
-		if err != nil {
-			return nil, fmt.Errorf("failed to upload part %d: %w", partNum, err)
-		}
-
-		// Сохраняем информацию о части
-		uploadedParts[partNum-1] = map[string]interface{}{
-			"PartNumber": partNum,
-			"ETag":       etag,
-		}
-	}
-
-	return uploadedParts, nil
-}
-
-// uploadPartWithProgress загружает часть файла с отслеживанием прогресса
-func (a *AkiraBoxProvider) uploadPartWithProgress(ctx context.Context, uploadURL string, reader io.Reader, partSize int64, totalUploaded *int64, fileSize int64, speedCalc *SpeedCalculator, progress chan<- UploadProgress) (string, error) {
-	// Создаем reader с отслеживанием прогресса
-	const progressChunkSize = 512 * 1024 // 512KB
-	var lastProgressUpdate int64
-
-	progressReader := &progressReader{
-		reader: reader,
-		onProgress: func(n int64) {
-			*totalUploaded += n
-
-			// Обновляем прогресс не чаще чем каждые 512KB
-			if *totalUploaded-lastProgressUpdate >= progressChunkSize || *totalUploaded == fileSize {
-				lastProgressUpdate = *totalUploaded
-				speed := speedCalc.Update(*totalUploaded)
-				percentage := int(float64(*totalUploaded) / float64(fileSize) * 100)
-
-				select {
-				case progress <- UploadProgress{
-					BytesUploaded: *totalUploaded,
-					TotalBytes:    fileSize,
-					Speed:         speed,
-					Percentage:    percentage,
-				}:
-				default:
-					// Канал прогресса заполнен, пропускаем обновление
-				}
-			}
-		},
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, progressReader)
-	if err != nil {
-		return "", err
-	}
-
-	req.ContentLength = partSize
-	req.Header.Set("Content-Type", "application/octet-stream")
-
-	resp, err := httpclient.LongLived().Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("upload failed with status %d", resp.StatusCode)
-	}
-
-	// Получаем ETag и убираем кавычки
-	etag := resp.Header.Get("ETag")
-	etag = strings.Trim(etag, "\"")
-
-	return etag, nil
-}
-
 // completeUpload завершает загрузку
-func (a *AkiraBoxProvider) completeUpload(ctx context.Context, startData *startUploadResponse, parts []map[string]interface{}) (string, error) {
+func (a *AkiraBoxProvider) completeUpload(ctx context.Context, startData *startUploadResponse, parts []uploadedPart) (string, error) {
 	u, err := url.Parse(akiraboxBaseURL + "/api/upload/complete")
 	if err != nil {
 		return "", err
@@ -283,10 +194,18 @@ func (a *AkiraBoxProvider) completeUpload(ctx context.Context, startData *startU
 	q.Set("providerId", strconv.FormatInt(startData.ProviderID, 10))
 	u.RawQuery = q.Encode()
 
+	partList := make([]map[string]interface{}, len(parts))
+	for i, part := range parts {
+		partList[i] = map[string]interface{}{
+			"PartNumber": part.Number,
+			"ETag":       part.ETag,
+		}
+	}
+
 	body := map[string]interface{}{
 		"UploadId": startData.UploadID,
 		"MultipartUpload": map[string]interface{}{
-			"Parts": parts,
+			"Parts": partList,
 		},
 		"metadata": startData.Metadata,
 	}
@@ -309,7 +228,7 @@ func (a *AkiraBoxProvider) completeUpload(ctx context.Context, startData *startU
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("complete upload failed with status %d", resp.StatusCode)
+		return "", statusError("complete upload", resp)
 	}
 
 	var result map[string]interface{}
@@ -0,0 +1,503 @@
+package providers
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"multiUploader/internal/httpclient"
+)
+
+const (
+	// b2AuthURL сервер авторизации нативного API Backblaze B2
+	b2AuthURL = "https://api.backblazeb2.com"
+	// b2APIPath префикс методов API
+	b2APIPath = "/b2api/v2/"
+	// b2MaxParts наибольшее число частей large file
+	b2MaxParts = 10000
+	// b2MaxFileSize наибольший размер файла в B2 (10 ТБ)
+	b2MaxFileSize = 10_000_000_000_000
+	// b2DefaultPartSize и b2MinPartSize размеры частей, если сервер их не сообщил
+	b2DefaultPartSize = 100 * 1024 * 1024
+	b2MinPartSize     = 5 * 1024 * 1024
+	// b2CancelTimeout ограничивает отмену незавершенного large file
+	b2CancelTimeout = 5 * time.Second
+)
+
+// Ключи полей настроек Backblaze B2
+const (
+	// B2KeyID идентификатор ключа приложения (keyID); сам ключ вводится в поле API ключа
+	B2KeyID = "key_id"
+	// B2Bucket имя бакета
+	B2Bucket = "bucket"
+	// B2Prefix папка внутри бакета, например "uploads/" (необязательно)
+	B2Prefix = "prefix"
+	// B2PublicURL начало ссылки вместо адреса B2, например свой домен через CDN (необязательно)
+	B2PublicURL = "public_url"
+)
+
+// b2BucketPattern имя бакета B2: 6-63 латинских букв, цифр и дефисов
+var b2BucketPattern = regexp.MustCompile(`^[A-Za-z0-9-]{6,63}$`)
+
+// B2Provider загружает файлы в бакет Backblaze B2 через нативное API (без слоя совместимости с S3)
+// Маленькие файлы уходят одним b2_upload_file, большие - частями через large file API
+type B2Provider struct {
+	// apiKey ключ приложения (applicationKey)
+	apiKey    string
+	keyID     string
+	bucket    string
+	prefix    string
+	publicURL string
+
+	// opts настройки "Advanced"; ChunkSize 0 - размер, рекомендованный сервером,
+	// MultipartThreshold 0 - одним запросом идут файлы до размера части
+	opts Options
+}
+
+// NewB2Provider создает провайдер Backblaze B2; apiKey - ключ приложения, остальное задается через SetSettings
+func NewB2Provider(apiKey string) *B2Provider {
+	return &B2Provider{apiKey: apiKey}
+}
+
+func (p *B2Provider) Name() string {
+	return "Backblaze B2"
+}
+
+func (p *B2Provider) RequiresAuth() bool {
+	return true
+}
+
+func (p *B2Provider) ValidateAPIKey(apiKey string) error {
+	if strings.TrimSpace(apiKey) == "" {
+		return errors.New("application key is required")
+	}
+	return nil
+}
+
+func (p *B2Provider) Capabilities() Capabilities {
+	return Capabilities{MaxFileSize: b2MaxFileSize, Multipart: true, SinglePart: true}
+}
+
+func (p *B2Provider) SetOptions(opts Options) {
+	p.opts = opts
+}
+
+func (p *B2Provider) SettingFields() []SettingField {
+	return []SettingField{
+		{Key: B2KeyID, Label: "Key ID:", Placeholder: "0051a2b3c4d5e6f0000000001"},
+		{Key: B2Bucket, Label: "Bucket:", Placeholder: "my-bucket"},
+		{Key: B2Prefix, Label: "Path prefix:", Placeholder: "uploads/"},
+		{Key: B2PublicURL, Label: "Public URL base:", Placeholder: "https://cdn.example.com/file/my-bucket"},
+	}
+}
+
+func (p *B2Provider) SetSettings(values map[string]string) {
+	p.keyID = strings.TrimSpace(values[B2KeyID])
+	p.bucket = strings.TrimSpace(values[B2Bucket])
+	p.prefix = strings.TrimSpace(values[B2Prefix])
+	p.publicURL = strings.TrimSpace(values[B2PublicURL])
+}
+
+func (p *B2Provider) ValidateSettings() error {
+	if p.keyID == "" {
+		return errors.New("key ID is required")
+	}
+	if !b2BucketPattern.MatchString(p.bucket) {
+		return errors.New("bucket name must be 6-63 letters, digits or hyphens")
+	}
+	if strings.HasPrefix(p.prefix, "/") {
+		return errors.New("path prefix must not start with /")
+	}
+	if p.publicURL != "" && !isHTTPURL(p.publicURL) {
+		return errors.New("public URL base must be an http or https address")
+	}
+	return nil
+}
+
+// Probe проверяет доступность сервера авторизации B2
+func (p *B2Provider) Probe(ctx context.Context) error {
+	return probeURL(ctx, b2AuthURL)
+}
+
+// b2Session авторизованный доступ к бакету на время одной загрузки
+type b2Session struct {
+	apiURL      string
+	downloadURL string
+	token       string
+	bucketID    string
+	// public бакет открыт для скачивания без авторизации (allPublic)
+	public bool
+
+	partSize    int64
+	minPartSize int64
+}
+
+// b2UploadTarget адрес загрузки с собственным токеном
+// B2 принимает на один адрес только один запрос одновременно
+type b2UploadTarget struct {
+	URL   string `json:"uploadUrl"`
+	Token string `json:"authorizationToken"`
+}
+
+// b2File файл в ответе b2_upload_file и b2_finish_large_file
+type b2File struct {
+	FileID   string `json:"fileId"`
+	FileName string `json:"fileName"`
+	// ContentMD5 MD5 файла в hex; B2 считает его только для файлов, загруженных одним запросом
+	ContentMD5 string `json:"contentMd5"`
+}
+
+// Upload загружает файл в бакет и возвращает ссылку на него
+// Файл не больше одной части загружается одним запросом, больший - частями (large file)
+func (p *B2Provider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
+	if ctx.Err() != nil {
+		return nil, ErrCancelled
+	}
+
+	sess, err := p.authorize(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ErrCancelled
+		}
+		return nil, err
+	}
+
+	name := p.prefix + filename
+	contentType := fileContentType(file, filename)
+	partSize := p.partSize(sess, fileSize)
+
+	// Large file состоит хотя бы из двух частей, поэтому порог ниже размера части не действует
+	var uploaded *b2File
+	if fileSize <= max(partSize, p.opts.MultipartThreshold) {
+		uploaded, err = p.uploadSmall(ctx, sess, file, name, contentType, fileSize, progress)
+	} else {
+		uploaded, err = p.uploadLarge(ctx, sess, file, name, contentType, fileSize, partSize, progress)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ErrCancelled
+		}
+		return nil, err
+	}
+
+	link := p.fileURL(sess, cmp.Or(uploaded.FileName, name))
+	result := &UploadResult{URL: link, DownloadURL: link, FileID: uploaded.FileID, MD5: uploaded.ContentMD5}
+	if !sess.public && p.publicURL == "" {
+		result.Message = "The bucket is private: the link opens only with a B2 download authorization"
+	}
+	return result, nil
+}
+
+// uploadSmall загружает файл одним запросом b2_upload_file
+// Идет через chunkUploader как одна часть: повторы с новым адресом, ожидание связи и пауза общие
+func (p *B2Provider) uploadSmall(ctx context.Context, sess *b2Session, file io.ReadSeeker, name, contentType string, fileSize int64, progress chan<- UploadProgress) (*b2File, error) {
+	targets := &b2Targets{fetch: func(ctx context.Context) (b2UploadTarget, error) {
+		var target b2UploadTarget
+		err := p.call(ctx, sess, "b2_get_upload_url", map[string]any{"bucketId": sess.bucketID}, &target)
+		return target, err
+	}}
+
+	var uploaded b2File
+	uploader := &chunkUploader{
+		file:       file,
+		fileSize:   fileSize,
+		chunkSize:  max(fileSize, 1),
+		totalParts: 1,
+		progress:   progress,
+		opts:       p.opts,
+		uploadPart: func(ctx context.Context, partNum int, retry bool, body io.Reader, size int64) (string, error) {
+			sum, err := b2SHA1(file, 0, size)
+			if err != nil {
+				return "", err
+			}
+			err = targets.send(ctx, "upload", body, size, map[string]string{
+				"X-Bz-File-Name":    s3Escape(name, true),
+				"Content-Type":      contentType,
+				"X-Bz-Content-Sha1": sum,
+			}, &uploaded)
+			return sum, err
+		},
+	}
+	if _, err := uploader.run(ctx); err != nil {
+		return nil, err
+	}
+	return &uploaded, nil
+}
+
+// uploadLarge загружает файл частями: b2_start_large_file, части параллельно и b2_finish_large_file
+// Незавершенный файл отменяется, чтобы B2 не хранил загруженные части
+func (p *B2Provider) uploadLarge(ctx context.Context, sess *b2Session, file io.ReadSeeker, name, contentType string, fileSize, partSize int64, progress chan<- UploadProgress) (_ *b2File, err error) {
+	var started b2File
+	if err := p.call(ctx, sess, "b2_start_large_file", map[string]any{
+		"bucketId":    sess.bucketID,
+		"fileName":    name,
+		"contentType": contentType,
+	}, &started); err != nil {
+		return nil, fmt.Errorf("init failed: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			p.cancelLarge(ctx, sess, started.FileID)
+		}
+	}()
+
+	targets := &b2Targets{fetch: func(ctx context.Context) (b2UploadTarget, error) {
+		var target b2UploadTarget
+		err := p.call(ctx, sess, "b2_get_upload_part_url", map[string]any{"fileId": started.FileID}, &target)
+		return target, err
+	}}
+
+	uploader := &chunkUploader{
+		file:       file,
+		fileSize:   fileSize,
+		chunkSize:  partSize,
+		totalParts: partCount(fileSize, partSize),
+		progress:   progress,
+		opts:       p.opts,
+		uploadPart: func(ctx context.Context, partNum int, retry bool, body io.Reader, size int64) (string, error) {
+			sum, err := b2SHA1(file, int64(partNum-1)*partSize, size)
+			if err != nil {
+				return "", err
+			}
+			err = targets.send(ctx, "upload part", body, size, map[string]string{
+				"X-Bz-Part-Number":  strconv.Itoa(partNum),
+				"X-Bz-Content-Sha1": sum,
+			}, nil)
+			return sum, err
+		},
+	}
+	parts, err := uploader.run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("upload parts failed: %w", err)
+	}
+
+	reportFinalizing(ctx, progress, fileSize)
+
+	sums := make([]string, len(parts))
+	for i, part := range parts {
+		sums[i] = part.ETag
+	}
+	var finished b2File
+	if err := p.call(ctx, sess, "b2_finish_large_file", map[string]any{
+		"fileId":        started.FileID,
+		"partSha1Array": sums,
+	}, &finished); err != nil {
+		return nil, fmt.Errorf("complete failed: %w", err)
+	}
+	return &finished, nil
+}
+
+// cancelLarge удаляет незавершенный large file вместе с загруженными частями
+// Вызывается и после отмены ctx, поэтому запрос идет с отдельным коротким таймаутом; ошибка не важна
+func (p *B2Provider) cancelLarge(ctx context.Context, sess *b2Session, fileID string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), b2CancelTimeout)
+	defer cancel()
+	_ = p.call(ctx, sess, "b2_cancel_large_file", map[string]any{"fileId": fileID}, nil)
+}
+
+// authorize получает токен аккаунта (b2_authorize_account) и находит бакет из настроек
+func (p *B2Provider) authorize(ctx context.Context) (*b2Session, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b2AuthURL+b2APIPath+"b2_authorize_account", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(p.keyID, p.apiKey)
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("authorize", resp)
+	}
+
+	var auth struct {
+		AccountID               string `json:"accountId"`
+		AuthorizationToken      string `json:"authorizationToken"`
+		APIURL                  string `json:"apiUrl"`
+		DownloadURL             string `json:"downloadUrl"`
+		RecommendedPartSize     int64  `json:"recommendedPartSize"`
+		AbsoluteMinimumPartSize int64  `json:"absoluteMinimumPartSize"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&auth); err != nil {
+		return nil, fmt.Errorf("failed to decode authorize response: %w", err)
+	}
+
+	sess := &b2Session{
+		apiURL:      strings.TrimRight(auth.APIURL, "/"),
+		downloadURL: strings.TrimRight(auth.DownloadURL, "/"),
+		token:       auth.AuthorizationToken,
+		partSize:    cmp.Or(auth.RecommendedPartSize, b2DefaultPartSize),
+		minPartSize: cmp.Or(auth.AbsoluteMinimumPartSize, b2MinPartSize),
+	}
+
+	var buckets struct {
+		Buckets []struct {
+			BucketID   string `json:"bucketId"`
+			BucketName string `json:"bucketName"`
+			BucketType string `json:"bucketType"`
+		} `json:"buckets"`
+	}
+	if err := p.call(ctx, sess, "b2_list_buckets", map[string]any{"accountId": auth.AccountID, "bucketName": p.bucket}, &buckets); err != nil {
+		return nil, err
+	}
+	for _, bucket := range buckets.Buckets {
+		if bucket.BucketName == p.bucket {
+			sess.bucketID = bucket.BucketID
+			sess.public = bucket.BucketType == "allPublic"
+			return sess, nil
+		}
+	}
+	return nil, &ServerError{Op: "find bucket", Message: fmt.Sprintf("bucket %q not found or not allowed for this key", p.bucket)}
+}
+
+// call вызывает метод API B2 с токеном аккаунта: JSON тело запроса, JSON ответ в out (nil - ответ не нужен)
+func (p *B2Provider) call(ctx context.Context, sess *b2Session, method string, data, out any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sess.apiURL+b2APIPath+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", sess.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError(method, resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	return nil
+}
+
+// partSize размер части large file: из настроек или рекомендованный сервером,
+// но не меньше минимума B2 и такой, чтобы частей было не больше b2MaxParts
+func (p *B2Provider) partSize(sess *b2Session, fileSize int64) int64 {
+	size := sess.partSize
+	if p.opts.ChunkSize > 0 {
+		size = p.opts.ChunkSize
+	}
+	size = max(size, sess.minPartSize)
+	return max(size, (fileSize+b2MaxParts-1)/b2MaxParts)
+}
+
+// fileURL ссылка на загруженный файл: начало из настроек или "дружественный" адрес B2 /file/<бакет>/<имя>
+func (p *B2Provider) fileURL(sess *b2Session, name string) string {
+	if p.publicURL != "" {
+		return strings.TrimRight(p.publicURL, "/") + "/" + s3Escape(name, true)
+	}
+	return sess.downloadURL + "/file/" + s3Escape(p.bucket, true) + "/" + s3Escape(name, true)
+}
+
+// b2Targets адреса загрузки, которые можно использовать повторно
+// Параллельные части берут разные адреса; после ошибки адрес выбрасывается, как советует B2
+type b2Targets struct {
+	mu    sync.Mutex
+	free  []b2UploadTarget
+	fetch func(ctx context.Context) (b2UploadTarget, error)
+}
+
+// send отправляет тело на свободный адрес загрузки и разбирает JSON ответ в out (nil - ответ не нужен)
+func (t *b2Targets) send(ctx context.Context, op string, body io.Reader, size int64, headers map[string]string, out any) error {
+	target, err := t.take(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get upload URL: %w", err)
+	}
+
+	req, err := newBodyRequest(ctx, http.MethodPost, target.URL, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Authorization", target.Token)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := httpclient.LongLived().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError(op, resp)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", op, err)
+		}
+	}
+	t.put(target)
+	return nil
+}
+
+// take возвращает свободный адрес или запрашивает новый
+func (t *b2Targets) take(ctx context.Context) (b2UploadTarget, error) {
+	t.mu.Lock()
+	if n := len(t.free); n > 0 {
+		target := t.free[n-1]
+		t.free = t.free[:n-1]
+		t.mu.Unlock()
+		return target, nil
+	}
+	t.mu.Unlock()
+	return t.fetch(ctx)
+}
+
+// put возвращает адрес после успешной загрузки
+func (t *b2Targets) put(target b2UploadTarget) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.free = append(t.free, target)
+}
+
+// b2SHA1 считает SHA-1 участка файла в hex (B2 проверяет его у каждого файла и части)
+// Файл без io.ReaderAt после чтения возвращается к началу участка: тело части читает его оттуда
+func b2SHA1(file io.ReadSeeker, offset, size int64) (string, error) {
+	h := sha1.New()
+	if readerAt, ok := file.(io.ReaderAt); ok {
+		if _, err := io.Copy(h, io.NewSectionReader(readerAt, offset, size)); err != nil {
+			return "", fmt.Errorf("failed to hash file: %w", err)
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to seek to offset %d: %w", offset, err)
+	}
+	if _, err := io.Copy(h, io.LimitReader(file, size)); err != nil {
+		return "", fmt.Errorf("failed to hash file: %w", err)
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to seek to offset %d: %w", offset, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,215 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// b2TestServer B2 API для тестов: части по 4 КБ, бакет test-bucket
+type b2TestServer struct {
+	t          *testing.T
+	bucketType string
+	// failParts части отвечают 400
+	failParts bool
+
+	mu        sync.Mutex
+	names     []string
+	parts     map[int]string
+	finished  []string
+	cancelled bool
+}
+
+func (s *b2TestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	method := r.URL.Path[len(b2APIPath):]
+	if r.Host == "api.backblazeb2.com" {
+		if id, key, _ := r.BasicAuth(); id != "key-id" || key != "app-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeDryRunJSON(w, map[string]any{
+			"accountId":               "acc",
+			"authorizationToken":      "account-token",
+			"apiUrl":                  "https://api.b2.test",
+			"downloadUrl":             "https://f.b2.test",
+			"recommendedPartSize":     4096,
+			"absoluteMinimumPartSize": 4096,
+		})
+		return
+	}
+
+	var req map[string]any
+	if r.Host == "api.b2.test" {
+		if r.Header.Get("Authorization") != "account-token" {
+			s.t.Errorf("%s: Authorization = %q", method, r.Header.Get("Authorization"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+	}
+
+	switch {
+	case method == "b2_list_buckets":
+		writeDryRunJSON(w, map[string]any{"buckets": []map[string]any{{"bucketId": "bkt", "bucketName": req["bucketName"], "bucketType": s.bucketType}}})
+	case method == "b2_get_upload_url":
+		writeDryRunJSON(w, map[string]any{"uploadUrl": "https://pod.b2.test/b2api/v2/upload_file", "authorizationToken": "upload-token"})
+	case method == "b2_start_large_file":
+		s.names = append(s.names, req["fileName"].(string))
+		writeDryRunJSON(w, map[string]any{"fileId": "large-1"})
+	case method == "b2_get_upload_part_url":
+		writeDryRunJSON(w, map[string]any{"uploadUrl": "https://pod.b2.test/b2api/v2/upload_part", "authorizationToken": "upload-token"})
+	case method == "b2_finish_large_file":
+		for _, sum := range req["partSha1Array"].([]any) {
+			s.finished = append(s.finished, sum.(string))
+		}
+		writeDryRunJSON(w, map[string]any{"fileId": "large-1", "fileName": s.names[0]})
+	case method == "b2_cancel_large_file":
+		s.cancelled = true
+		writeDryRunJSON(w, map[string]any{"fileId": "large-1"})
+	case method == "upload_file" || method == "upload_part":
+		body, _ := io.ReadAll(r.Body)
+		sum := sha1.Sum(body)
+		if got := r.Header.Get("X-Bz-Content-Sha1"); got != hex.EncodeToString(sum[:]) {
+			s.t.Errorf("%s: X-Bz-Content-Sha1 = %q, want SHA-1 of the body", method, got)
+		}
+		if r.Header.Get("Authorization") != "upload-token" {
+			s.t.Errorf("%s: Authorization = %q", method, r.Header.Get("Authorization"))
+		}
+		if method == "upload_file" {
+			s.names = append(s.names, r.Header.Get("X-Bz-File-Name"))
+			writeDryRunJSON(w, map[string]any{"fileId": "small-1", "contentMd5": "0a1b2c3d4e5f60718293a4b5c6d7e8f9"})
+			return
+		}
+		if s.failParts {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"status":400,"code":"bad_request","message":"part rejected"}`)
+			return
+		}
+		num, _ := strconv.Atoi(r.Header.Get("X-Bz-Part-Number"))
+		s.parts[num] = r.Header.Get("X-Bz-Content-Sha1")
+		writeDryRunJSON(w, map[string]any{"fileId": "large-1", "partNumber": num})
+	default:
+		s.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		http.NotFound(w, r)
+	}
+}
+
+// useB2TestServer направляет запросы провайдера в тестовый B2
+func useB2TestServer(t *testing.T, bucketType string) *b2TestServer {
+	server := &b2TestServer{t: t, bucketType: bucketType, parts: make(map[int]string)}
+	useDryRunServer(t, server)
+	return server
+}
+
+// newTestB2Provider провайдер с ключом и бакетом тестового сервера
+func newTestB2Provider(t *testing.T, prefix string) *B2Provider {
+	return configured(t, NewB2Provider("app-key"), map[string]string{B2KeyID: "key-id", B2Bucket: "test-bucket", B2Prefix: prefix})
+}
+
+// TestB2LargeFile проверяет загрузку частями: SHA-1 каждой части, список SHA-1 при завершении и ссылку на файл
+func TestB2LargeFile(t *testing.T) {
+	server := useB2TestServer(t, "allPrivate")
+
+	data := make([]byte, 10*1024+123)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	result, err := newTestB2Provider(t, "docs/").Upload(context.Background(), bytes.NewReader(data), "a+b ü.bin", int64(len(data)), make(chan UploadProgress, 100))
+	if err != nil {
+		t.Fatalf("Upload() = %v", err)
+	}
+
+	if len(server.parts) != 3 {
+		t.Fatalf("uploaded %d parts, want 3", len(server.parts))
+	}
+	want := []string{server.parts[1], server.parts[2], server.parts[3]}
+	if !slices.Equal(server.finished, want) {
+		t.Errorf("partSha1Array = %v, want %v", server.finished, want)
+	}
+	if server.names[0] != "docs/a+b ü.bin" {
+		t.Errorf("fileName = %q", server.names[0])
+	}
+	if wantURL := "https://f.b2.test/file/test-bucket/docs/a%2Bb%20%C3%BC.bin"; result.URL != wantURL {
+		t.Errorf("URL = %q, want %q", result.URL, wantURL)
+	}
+	if result.FileID != "large-1" || result.Message == "" {
+		t.Errorf("result = %+v, want large file ID and a note about the private bucket", result)
+	}
+}
+
+// TestB2SmallFile проверяет загрузку одним запросом: имя в X-Bz-File-Name и ссылку от своего домена
+func TestB2SmallFile(t *testing.T) {
+	server := useB2TestServer(t, "allPublic")
+
+	p := newTestB2Provider(t, "")
+	p.SetSettings(map[string]string{B2KeyID: "key-id", B2Bucket: "test-bucket", B2PublicURL: "https://cdn.example.com/files/"})
+	data := []byte("hello, b2")
+
+	result, err := p.Upload(context.Background(), bytes.NewReader(data), "note 1.txt", int64(len(data)), make(chan UploadProgress, 100))
+	if err != nil {
+		t.Fatalf("Upload() = %v", err)
+	}
+	if len(server.names) != 1 || server.names[0] != "note%201.txt" {
+		t.Errorf("X-Bz-File-Name = %v, want note%%201.txt", server.names)
+	}
+	// MD5 от B2 сверяется с локальным файлом после загрузки
+	if result.URL != "https://cdn.example.com/files/note%201.txt" || result.FileID != "small-1" || result.Message != "" || result.MD5 != "0a1b2c3d4e5f60718293a4b5c6d7e8f9" {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+// TestB2MultipartThreshold проверяет, что файл меньше порога из настроек уходит одним запросом
+func TestB2MultipartThreshold(t *testing.T) {
+	server := useB2TestServer(t, "allPublic")
+
+	p := newTestB2Provider(t, "")
+	p.SetOptions(Options{MultipartThreshold: 16 * 1024})
+	data := make([]byte, 10*1024)
+	if _, err := p.Upload(context.Background(), bytes.NewReader(data), "file.bin", int64(len(data)), make(chan UploadProgress, 100)); err != nil {
+		t.Fatalf("Upload() = %v", err)
+	}
+	if len(server.parts) != 0 || !slices.Equal(server.names, []string{"file.bin"}) {
+		t.Errorf("parts = %v, names = %v, want one b2_upload_file request", server.parts, server.names)
+	}
+}
+
+// TestB2CancelLargeFile проверяет, что large file отменяется, если часть так и не загрузилась
+func TestB2CancelLargeFile(t *testing.T) {
+	server := useB2TestServer(t, "allPublic")
+	server.failParts = true
+
+	data := make([]byte, 10*1024)
+	_, err := newTestB2Provider(t, "").Upload(context.Background(), bytes.NewReader(data), "file.bin", int64(len(data)), make(chan UploadProgress, 100))
+	if err == nil {
+		t.Fatal("Upload() succeeded, want error")
+	}
+	if !server.cancelled {
+		t.Error("unfinished large file was not cancelled")
+	}
+}
+
+func TestB2ValidateSettings(t *testing.T) {
+	for _, values := range []map[string]string{
+		{B2Bucket: "test-bucket"},
+		{B2KeyID: "id", B2Bucket: "b"},
+		{B2KeyID: "id", B2Bucket: "test_bucket"},
+		{B2KeyID: "id", B2Bucket: "test-bucket", B2Prefix: "/uploads"},
+		{B2KeyID: "id", B2Bucket: "test-bucket", B2PublicURL: "cdn.example.com"},
+	} {
+		p := NewB2Provider("key")
+		p.SetSettings(values)
+		if err := p.ValidateSettings(); err == nil {
+			t.Errorf("ValidateSettings(%v) accepted invalid settings", values)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// errBodyClosed чтение тела попытки, которую httpclient уже закрыл
+var errBodyClosed = errors.New("request body closed")
+
+// retryBody тело запроса с участком файла, которое httpclient может отправить повторно
+// Каждая попытка читает участок заново с начала, прогресс прерванной попытки откатывается
+type retryBody struct {
+	file       io.ReadSeeker
+	offset     int64
+	size       int64
+	onProgress func(n int64)
+
+	mu      sync.Mutex
+	current *attemptBody
+}
+
+// newRetryBody создает тело для участка file размером size, начиная с offset
+// onProgress получает прочитанные байты (отрицательные - откат попытки)
+func newRetryBody(file io.ReadSeeker, offset, size int64, onProgress func(n int64)) (*retryBody, error) {
+	b := &retryBody{file: file, offset: offset, size: size, onProgress: onProgress}
+	if _, err := b.GetBody(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Read читает текущую попытку (используется, если httpclient не повторяет запрос)
+func (b *retryBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	current := b.current
+	b.mu.Unlock()
+	return current.Read(p)
+}
+
+// GetBody закрывает предыдущую попытку и открывает участок с начала (для http.Request.GetBody)
+// Для файлов без io.ReaderAt части загружаются по одной, поэтому Seek безопасен
+func (b *retryBody) GetBody() (io.ReadCloser, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.current != nil {
+		if read := b.current.close(); read > 0 && b.onProgress != nil {
+			b.onProgress(-read)
+		}
+	}
+
+	var reader io.Reader
+	if readerAt, ok := b.file.(io.ReaderAt); ok {
+		reader = io.NewSectionReader(readerAt, b.offset, b.size)
+	} else {
+		if _, err := b.file.Seek(b.offset, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to seek to offset %d: %w", b.offset, err)
+		}
+		reader = io.LimitReader(b.file, b.size)
+	}
+
+	b.current = &attemptBody{reader: reader, onProgress: b.onProgress}
+	return b.current, nil
+}
+
+// attemptBody тело одной попытки запроса
+// После закрытия не читает файл: транспорт может обращаться к телу и после возврата из Do
+type attemptBody struct {
+	mu         sync.Mutex
+	reader     io.Reader
+	onProgress func(n int64)
+	read       int64
+	closed     bool
+}
+
+func (a *attemptBody) Read(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return 0, errBodyClosed
+	}
+
+	n, err := a.reader.Read(p)
+	if n > 0 {
+		a.read += int64(n)
+		if a.onProgress != nil {
+			a.onProgress(int64(n))
+		}
+	}
+	return n, err
+}
+
+func (a *attemptBody) Close() error {
+	a.close()
+	return nil
+}
+
+// close закрывает попытку и возвращает, сколько байт из нее прочитано
+func (a *attemptBody) close() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+	return a.read
+}
+
+// newBodyRequest создает запрос; для retryBody задает GetBody, чтобы повторы httpclient отправляли данные заново
+func newBodyRequest(ctx context.Context, method, target string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if rb, ok := body.(*retryBody); ok {
+		req.GetBody = rb.GetBody
+	}
+	return req, nil
+}
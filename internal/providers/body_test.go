@@ -0,0 +1,61 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestRetryBody проверяет, что каждая попытка читает участок заново и откатывает прогресс предыдущей
+func TestRetryBody(t *testing.T) {
+	const content = "0123456789"
+	sources := map[string]io.ReadSeeker{
+		"ReaderAt": strings.NewReader(content),
+		"Seek":     seekOnly{strings.NewReader(content)},
+	}
+
+	for name, file := range sources {
+		t.Run(name, func(t *testing.T) {
+			var progress int64
+			body, err := newRetryBody(file, 2, 5, func(n int64) { progress += n })
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req, err := newBodyRequest(context.Background(), http.MethodPut, "https://example.com/part", body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if req.GetBody == nil {
+				t.Fatal("GetBody is not set for retryBody")
+			}
+
+			// Прерванная попытка
+			first, _ := req.GetBody()
+			buf := make([]byte, 3)
+			io.ReadFull(first, buf)
+			if progress != 3 {
+				t.Fatalf("progress = %d after partial read, want 3", progress)
+			}
+
+			second, err := req.GetBody()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if progress != 0 {
+				t.Errorf("progress = %d after rewind, want 0", progress)
+			}
+			if _, err := first.Read(buf); err != errBodyClosed {
+				t.Errorf("read from replaced attempt: err = %v, want errBodyClosed", err)
+			}
+
+			data, _ := io.ReadAll(second)
+			if !bytes.Equal(data, []byte("23456")) || progress != 5 {
+				t.Errorf("retry read %q with progress %d, want %q and 5", data, progress, "23456")
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Capabilities описывает ограничения и возможности провайдера
+type Capabilities struct {
+	// MaxFileSize максимальный размер файла в байтах (NoSizeLimit - без ограничений)
+	MaxFileSize int64
+
+	// Multipart провайдер загружает большие файлы частями
+	Multipart bool
+
+	// SinglePart файлы меньше порога Options.MultipartThreshold загружаются одним запросом
+	SinglePart bool
+
+	// AllowedTypes расширения файлов, которые принимает провайдер: с точкой, в нижнем регистре (nil - любые)
+	AllowedTypes []string
+
+	// BlockedTypes расширения файлов, которые провайдер запрещает загружать
+	BlockedTypes []string
+}
+
+// NoSizeLimit провайдер не публикует предел размера файла или он зависит от тарифа.
+// Приложение файл не отклоняет, а ответ 413 сервера становится QuotaFileTooLarge
+const NoSizeLimit int64 = 0
+
+// ExecutableTypes исполняемые файлы и установщики - их не принимают многие файлообменники
+var ExecutableTypes = []string{".exe", ".msi", ".bat", ".cmd", ".com", ".scr", ".pif", ".vbs", ".ps1", ".jar", ".apk"}
+
+// AcceptsFile сообщает, разрешает ли провайдер загружать файл с таким именем
+func (c Capabilities) AcceptsFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	if slices.Contains(c.BlockedTypes, ext) {
+		return false
+	}
+	return c.AllowedTypes == nil || slices.Contains(c.AllowedTypes, ext)
+}
+
+// CapabilitiesProvider реализуется провайдерами, которые сообщают свои ограничения
+type CapabilitiesProvider interface {
+	Capabilities() Capabilities
+}
+
+// GetCapabilities возвращает возможности провайдера
+// Для провайдеров без CapabilitiesProvider возвращаются значения по умолчанию
+func GetCapabilities(p Provider) Capabilities {
+	if cp, ok := p.(CapabilitiesProvider); ok {
+		return cp.Capabilities()
+	}
+	return Capabilities{}
+}
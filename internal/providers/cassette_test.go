@@ -0,0 +1,95 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"multiUploader/internal/httpclient"
+)
+
+// record перезаписывает кассеты с живых сервисов:
+//
+//	MULTIUPLOADER_ROOTZ_KEY=... go test ./internal/providers -run TestProviderCassettes -record
+//
+// Провайдеры без ключа в окружении пропускаются
+var record = flag.Bool("record", false, "record provider cassettes against live services")
+
+// TestProviderCassettes воспроизводит кассеты загрузки всех провайдеров
+// Тест ловит изменение запросов самого провайдера (новый, пропавший или измененный запрос).
+// Кассеты с Source не "recorded" составлены по документации API: изменения на стороне сервиса
+// они не покажут, пока не будут перезаписаны с живого сервиса
+func TestProviderCassettes(t *testing.T) {
+	tests := []struct {
+		name        string
+		newProvider func(apiKey string) Provider
+		size        int64
+		wantURL     string
+	}{
+		{"datavaults", func(k string) Provider { return NewDataVaultsProvider(k) }, 1024, "https://datavaults.co/q7w4e1r8t5y2"},
+		{"filekeeper", func(k string) Provider { return NewFileKeeperProvider(k) }, 1024, "https://filekeeper.net/h3j6k9l2m5n8"},
+		// Больше одной части, чтобы записать multipart протокол
+		{"akirabox", func(k string) Provider { return NewAkiraBoxProvider(k) }, 6 << 20, "https://akirabox.com/f/Lm4Np7Qr"},
+		{"rootz", func(k string) Provider { return NewRootzProvider(k) }, 6 << 20, "https://www.rootz.so/d/Xy7Kp2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join("testdata", "cassettes", tt.name+".json")
+			apiKey := "test-key"
+
+			var cassette *httpclient.Cassette
+			if *record {
+				apiKey = os.Getenv("MULTIUPLOADER_" + strings.ToUpper(tt.name) + "_KEY")
+				if apiKey == "" {
+					t.Skipf("MULTIUPLOADER_%s_KEY is not set", strings.ToUpper(tt.name))
+				}
+				cassette = &httpclient.Cassette{Source: "recorded"}
+				httpclient.StartRecording(cassette)
+				defer httpclient.StopRecording()
+			} else {
+				var err error
+				if cassette, err = httpclient.LoadCassette(path); err != nil {
+					t.Fatal(err)
+				}
+				if cassette.Source != "recorded" {
+					t.Logf("%s is not recorded from the live service: %s", path, cassette.Source)
+				}
+				httpclient.EnableDryRun(cassette.Handler())
+				defer httpclient.DisableDryRun()
+			}
+
+			progress := make(chan UploadProgress, 100)
+			go func() {
+				for range progress {
+				}
+			}()
+			defer close(progress)
+
+			content := bytes.Repeat([]byte("multiUploader"), int(tt.size)/13+1)[:tt.size]
+			result, err := tt.newProvider(apiKey).Upload(context.Background(), bytes.NewReader(content), "cassette.bin", tt.size, progress)
+			if err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+
+			if *record {
+				if err := cassette.Save(path); err != nil {
+					t.Fatal(err)
+				}
+				t.Logf("recorded %s, wantURL = %q", path, result.URL)
+				return
+			}
+
+			if result.URL != tt.wantURL {
+				t.Errorf("URL = %q, want %q", result.URL, tt.wantURL)
+			}
+			for _, req := range cassette.Unused() {
+				t.Errorf("recorded request was not made: %s %s", req.Method, req.URL)
+			}
+		})
+	}
+}
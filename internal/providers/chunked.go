@@ -0,0 +1,524 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/tracing"
+)
+
+const (
+	// initialConcurrency количество частей, загружаемых параллельно в начале
+	initialConcurrency = 2
+	// maxConcurrency максимальное количество параллельных частей
+	maxConcurrency = 8
+
+	// progressChunkSize минимальный объем данных между обновлениями прогресса
+	progressChunkSize = 512 * 1024 // 512KB
+
+	// maxPartAttempts сколько раз пробуем загрузить часть, прежде чем прервать всю загрузку
+	maxPartAttempts = 4
+)
+
+// partRetryDelay базовая пауза перед повтором части, растет с номером попытки (переопределяется в тестах)
+var partRetryDelay = time.Second
+
+// errPartStalled причина отмены попытки, по которой данные не передавались дольше таймаута зависания
+var errPartStalled = errors.New("part upload stalled")
+
+// uploadedPart загруженная часть multipart upload
+type uploadedPart struct {
+	Number int
+	ETag   string
+}
+
+// partUploadFunc загружает одну часть и возвращает ее ETag
+// retry = true для повторной попытки, провайдер может обновить presigned URL
+type partUploadFunc func(ctx context.Context, partNum int, retry bool, body io.Reader, size int64) (string, error)
+
+// chunkUploader загружает части файла параллельно, подбирая число потоков по скорости и ошибкам
+type chunkUploader struct {
+	file       io.ReadSeeker
+	fileSize   int64
+	chunkSize  int64
+	totalParts int
+	uploadPart partUploadFunc
+	progress   chan<- UploadProgress
+
+	// opts настройки провайдера: RetryStalled перезапускает зависшую попытку части,
+	// WaitOnline ждет возвращения связи после обрыва
+	opts Options
+	// sequential части загружаются по одной и по порядку (например, сообщения в чат)
+	sequential bool
+	// permanent ошибки, которые повтор части не исправит (nil - повторяются все)
+	permanent func(error) bool
+}
+
+// partResult результат загрузки одной части
+type partResult struct {
+	num  int
+	etag string
+	size int64
+	err  error
+}
+
+// run загружает все части и возвращает их в порядке номеров
+// Если файл не поддерживает io.ReaderAt или задан sequential, части загружаются последовательно
+func (u *chunkUploader) run(ctx context.Context) (_ []uploadedPart, err error) {
+	ctx, span := tracing.Start(ctx, "upload parts", tracing.Int("parts.count", int64(u.totalParts)), tracing.Int("file.size", u.fileSize))
+	defer func() { span.End(err) }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	_, parallel := u.file.(io.ReaderAt)
+	maxLimit := maxConcurrency
+	if !parallel || u.sequential {
+		maxLimit = 1
+	}
+	ctrl := newConcurrencyController(min(initialConcurrency, maxLimit), maxLimit)
+	tracker := newProgressTracker(u.fileSize, u.progress)
+	tracker.trackParts(u.totalParts, u.partSize)
+
+	parts := make([]uploadedPart, u.totalParts)
+	results := make(chan partResult)
+	active := 0
+	next := 1
+	var firstErr error
+
+	for active > 0 || (next <= u.totalParts && firstErr == nil) {
+		// Запускаем новые части, пока не достигнут текущий лимит
+		for firstErr == nil && next <= u.totalParts && active < ctrl.Limit() {
+			if ctx.Err() != nil {
+				firstErr = ErrCancelled
+				break
+			}
+
+			num := next
+			go func() {
+				etag, err := u.uploadWithRetry(ctx, num, ctrl, tracker)
+				results <- partResult{num: num, etag: etag, size: u.partSize(num), err: err}
+			}()
+			next++
+			active++
+		}
+
+		if active == 0 {
+			break
+		}
+
+		res := <-results
+		active--
+
+		if res.err != nil {
+			if firstErr == nil {
+				if ctx.Err() != nil {
+					firstErr = ErrCancelled
+				} else {
+					firstErr = fmt.Errorf("failed to upload part %d: %w", res.num, res.err)
+				}
+				// Останавливаем остальные части
+				cancel()
+			}
+			continue
+		}
+
+		parts[res.num-1] = uploadedPart{Number: res.num, ETag: res.etag}
+		ctrl.OnPartDone(res.size)
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return parts, nil
+}
+
+// uploadWithRetry загружает часть, повторяя только ее при ошибках
+// Прогресс неудачной попытки откатывается, чтобы не завышать процент
+func (u *chunkUploader) uploadWithRetry(ctx context.Context, num int, ctrl *concurrencyController, tracker *progressTracker) (string, error) {
+	size := u.partSize(num)
+
+	var lastErr error
+	for attempt := 1; attempt <= maxPartAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(partRetryDelay * time.Duration(attempt-1)):
+			}
+		}
+
+		// Точка паузы: пока загрузки на паузе, новая попытка части не начинается
+		if err := waitUnpaused(ctx); err != nil {
+			return "", err
+		}
+
+		var sent, lastRead atomic.Int64
+		lastRead.Store(time.Now().UnixNano())
+		// Тело перечитывается с начала части и при повторах запроса внутри httpclient
+		body, err := newRetryBody(u.file, int64(num-1)*u.chunkSize, size, func(n int64) {
+			sent.Add(n)
+			lastRead.Store(time.Now().UnixNano())
+			tracker.AddPart(num, n)
+		})
+		if err != nil {
+			err = fmt.Errorf("failed to read part %d: %w", num, err)
+			tracker.partFinished(num, err)
+			return "", err
+		}
+
+		tracker.partStarted(num)
+		spanCtx, span := tracing.Start(ctx, "upload part",
+			tracing.Int("part.number", int64(num)), tracing.Int("part.attempt", int64(attempt)), tracing.Int("part.size", size))
+		attemptCtx, cancel := context.WithCancelCause(spanCtx)
+
+		stopWatch := u.watchStall(attemptCtx, cancel, &sent, &lastRead, size)
+		etag, err := u.uploadPart(attemptCtx, num, attempt > 1, body, size)
+		stopWatch()
+		stalled := errors.Is(context.Cause(attemptCtx), errPartStalled)
+		cancel(nil)
+		if stalled {
+			span.End(errPartStalled)
+		} else {
+			span.End(err)
+		}
+
+		if err == nil {
+			tracker.partFinished(num, nil)
+			return etag, nil
+		}
+
+		tracker.AddPart(num, -sent.Load())
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if stalled {
+			err = fmt.Errorf("%w: no data sent for %s", errPartStalled, u.opts.stallTimeout())
+		}
+		tracker.partRetrying(num, err)
+
+		// Часть прервалась из-за обрыва связи - ждем сеть и повторяем ее, не тратя попытку
+		if lost, waitErr := u.waitForNetwork(ctx, err, tracker); waitErr != nil {
+			return "", waitErr
+		} else if lost {
+			attempt--
+			continue
+		}
+
+		if u.permanent != nil && u.permanent(err) {
+			tracker.partFinished(num, err)
+			return "", err
+		}
+
+		ctrl.OnError()
+		lastErr = err
+	}
+
+	tracker.partFinished(num, lastErr)
+	return "", fmt.Errorf("%w (after %d attempts)", lastErr, maxPartAttempts)
+}
+
+// waitForNetwork ждет возвращения связи, если попытка прервалась из-за сети
+// Возвращает true, если связь пропадала и часть нужно повторить
+func (u *chunkUploader) waitForNetwork(ctx context.Context, err error, tracker *progressTracker) (bool, error) {
+	if u.opts.WaitOnline == nil || !(httpclient.IsNetworkError(err) || errors.Is(err, errPartStalled)) {
+		return false, nil
+	}
+
+	lost, waitErr := u.opts.WaitOnline(ctx, tracker.Offline)
+	if lost && waitErr == nil {
+		tracker.Online()
+	}
+	return lost, waitErr
+}
+
+// watchStall отменяет попытку, если данные части не передаются дольше таймаута зависания
+// Ожидание ответа сервера после отправки всей части зависанием не считается
+func (u *chunkUploader) watchStall(ctx context.Context, cancel context.CancelCauseFunc, sent, lastRead *atomic.Int64, size int64) (stop func()) {
+	timeout := u.opts.stallTimeout()
+	if timeout <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(timeout / 10)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if sent.Load() >= size {
+					return
+				}
+				if time.Since(time.Unix(0, lastRead.Load())) >= timeout {
+					cancel(errPartStalled)
+					return
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// partSize возвращает размер части (последняя может быть меньше)
+func (u *chunkUploader) partSize(num int) int64 {
+	start := int64(num-1) * u.chunkSize
+	if start+u.chunkSize > u.fileSize {
+		return u.fileSize - start
+	}
+	return u.chunkSize
+}
+
+// progressTracker суммирует прогресс параллельных частей и отправляет его в канал
+type progressTracker struct {
+	mu         sync.Mutex
+	uploaded   int64
+	lastUpdate int64
+	fileSize   int64
+	speedCalc  *SpeedCalculator
+	progress   chan<- UploadProgress
+	// parts состояние частей (nil - загрузка не разбита на части)
+	parts []PartStatus
+	// speed последняя скорость, повторяется в обновлениях состояния частей
+	speed float64
+}
+
+func newProgressTracker(fileSize int64, progress chan<- UploadProgress) *progressTracker {
+	return &progressTracker{
+		fileSize:  fileSize,
+		speedCalc: NewSpeedCalculator(),
+		progress:  progress,
+	}
+}
+
+// trackParts включает учет состояния частей для загрузки частями
+func (p *progressTracker) trackParts(total int, size func(num int) int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.parts = make([]PartStatus, total)
+	for i := range p.parts {
+		p.parts[i] = PartStatus{Number: i + 1, Size: size(i + 1)}
+	}
+}
+
+// Add учитывает отправленные байты (отрицательное значение откатывает прогресс части)
+func (p *progressTracker) Add(n int64) {
+	p.AddPart(0, n)
+}
+
+// AddPart учитывает отправленные байты части num (0 - загрузка без частей)
+func (p *progressTracker) AddPart(num int, n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.uploaded += n
+	if num > 0 && p.parts != nil {
+		p.parts[num-1].Sent += n
+	}
+	if n < 0 {
+		// Откат неудачной попытки: следующее обновление считаем от нового значения
+		p.lastUpdate = min(p.lastUpdate, p.uploaded)
+		return
+	}
+
+	// Обновляем прогресс не чаще чем каждые 512KB
+	if p.uploaded-p.lastUpdate < progressChunkSize && p.uploaded != p.fileSize {
+		return
+	}
+	p.lastUpdate = p.uploaded
+
+	speed := p.speedCalc.Update(p.uploaded)
+	p.speed = speed
+	select {
+	case p.progress <- UploadProgress{
+		BytesUploaded: p.uploaded,
+		TotalBytes:    p.fileSize,
+		Speed:         speed,
+		Percentage:    percentOf(p.uploaded, p.fileSize),
+		Parts:         p.partsSnapshot(),
+	}:
+	default:
+		// Канал прогресса заполнен, пропускаем обновление
+	}
+}
+
+// partStarted отмечает начало очередной попытки части
+func (p *progressTracker) partStarted(num int) {
+	p.updatePart(num, func(part *PartStatus) {
+		part.Attempts++
+		part.State = PartUploading
+	})
+}
+
+// partRetrying отмечает неудачную попытку части
+func (p *progressTracker) partRetrying(num int, err error) {
+	p.updatePart(num, func(part *PartStatus) {
+		part.State = PartRetrying
+		part.Err = err.Error()
+	})
+}
+
+// partFinished отмечает итог части: загружена (err == nil) или не загружена
+func (p *progressTracker) partFinished(num int, err error) {
+	p.updatePart(num, func(part *PartStatus) {
+		if err == nil {
+			part.State = PartDone
+			part.Sent = part.Size
+			return
+		}
+		part.State = PartFailed
+		part.Err = err.Error()
+	})
+}
+
+// updatePart меняет состояние части и отправляет его, не меняя этап загрузки
+func (p *progressTracker) updatePart(num int, update func(part *PartStatus)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.parts == nil {
+		return
+	}
+	update(&p.parts[num-1])
+
+	select {
+	case p.progress <- UploadProgress{
+		BytesUploaded: p.uploaded,
+		TotalBytes:    p.fileSize,
+		Speed:         p.speed,
+		Percentage:    percentOf(p.uploaded, p.fileSize),
+		Parts:         p.partsSnapshot(),
+	}:
+	default:
+	}
+}
+
+// partsSnapshot копия состояния частей для отправки (вызывается под p.mu)
+func (p *progressTracker) partsSnapshot() []PartStatus {
+	if p.parts == nil {
+		return nil
+	}
+	return slices.Clone(p.parts)
+}
+
+// Offline сообщает, что связь пропала и передача приостановлена
+func (p *progressTracker) Offline() {
+	p.report(PhaseOffline)
+}
+
+// Online сообщает, что связь вернулась и передача продолжается
+func (p *progressTracker) Online() {
+	p.report(PhaseUploading)
+}
+
+// report отправляет текущий прогресс со сменой этапа
+func (p *progressTracker) report(phase UploadPhase) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	select {
+	case p.progress <- UploadProgress{
+		BytesUploaded: p.uploaded,
+		TotalBytes:    p.fileSize,
+		Percentage:    percentOf(p.uploaded, p.fileSize),
+		Phase:         phase,
+		Parts:         p.partsSnapshot(),
+	}:
+	default:
+	}
+}
+
+// concurrencyController подбирает число параллельных частей
+// После каждого "раунда" (столько завершенных частей, сколько потоков) сравнивает скорость
+// с предыдущим раундом: рост - добавляет поток, заметное падение - убирает
+// Ошибка части сразу уменьшает число потоков вдвое
+type concurrencyController struct {
+	mu  sync.Mutex
+	now func() time.Time
+
+	limit    int
+	maxLimit int
+
+	windowStart    time.Time
+	windowBytes    int64
+	windowParts    int
+	lastThroughput float64
+}
+
+func newConcurrencyController(initial, maxLimit int) *concurrencyController {
+	c := &concurrencyController{
+		now:      time.Now,
+		limit:    initial,
+		maxLimit: maxLimit,
+	}
+	c.windowStart = c.now()
+	return c
+}
+
+// Limit возвращает текущее число параллельных частей
+func (c *concurrencyController) Limit() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limit
+}
+
+// OnPartDone учитывает успешно загруженную часть
+func (c *concurrencyController) OnPartDone(bytes int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.windowBytes += bytes
+	c.windowParts++
+	if c.windowParts < c.limit {
+		return
+	}
+
+	elapsed := c.now().Sub(c.windowStart).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	throughput := float64(c.windowBytes) / elapsed
+
+	switch {
+	case c.lastThroughput == 0 || throughput > c.lastThroughput*1.1:
+		if c.limit < c.maxLimit {
+			c.limit++
+		}
+	case throughput < c.lastThroughput*0.8:
+		if c.limit > 1 {
+			c.limit--
+		}
+	}
+
+	c.lastThroughput = throughput
+	c.resetWindow()
+}
+
+// OnError учитывает неудачную часть
+func (c *concurrencyController) OnError() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.limit = max(1, c.limit/2)
+	c.lastThroughput = 0
+	c.resetWindow()
+}
+
+func (c *concurrencyController) resetWindow() {
+	c.windowStart = c.now()
+	c.windowBytes = 0
+	c.windowParts = 0
+}
@@ -0,0 +1,400 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"multiUploader/internal/httpclient"
+)
+
+// seekOnly скрывает io.ReaderAt, чтобы проверить последовательную загрузку
+type seekOnly struct {
+	io.ReadSeeker
+}
+
+// TestChunkUploader проверяет, что все части загружаются с правильным содержимым
+func TestChunkUploader(t *testing.T) {
+	data := make([]byte, 10*1024+123)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	for _, tc := range []struct {
+		name string
+		file io.ReadSeeker
+	}{
+		{"Parallel", bytes.NewReader(data)},
+		{"Sequential", seekOnly{bytes.NewReader(data)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			const chunkSize = 1024
+			var mu sync.Mutex
+			received := make(map[int][]byte)
+
+			uploader := &chunkUploader{
+				file:       tc.file,
+				fileSize:   int64(len(data)),
+				chunkSize:  chunkSize,
+				totalParts: partCount(int64(len(data)), chunkSize),
+				progress:   make(chan UploadProgress, 100),
+				uploadPart: func(ctx context.Context, partNum int, retry bool, body io.Reader, size int64) (string, error) {
+					b, err := io.ReadAll(body)
+					if err != nil {
+						return "", err
+					}
+					if int64(len(b)) != size {
+						return "", fmt.Errorf("part %d: read %d bytes, want %d", partNum, len(b), size)
+					}
+					mu.Lock()
+					received[partNum] = b
+					mu.Unlock()
+					return fmt.Sprintf("etag-%d", partNum), nil
+				},
+			}
+
+			parts, err := uploader.run(context.Background())
+			if err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			if len(parts) != 11 {
+				t.Fatalf("got %d parts, want 11", len(parts))
+			}
+
+			var assembled []byte
+			for i, part := range parts {
+				if part.Number != i+1 || part.ETag != fmt.Sprintf("etag-%d", i+1) {
+					t.Errorf("parts[%d] = %+v", i, part)
+				}
+				assembled = append(assembled, received[part.Number]...)
+			}
+			if !bytes.Equal(assembled, data) {
+				t.Error("assembled parts differ from source data")
+			}
+		})
+	}
+}
+
+// TestChunkUploaderError проверяет остановку загрузки при ошибке части
+func TestChunkUploaderError(t *testing.T) {
+	noRetryDelay(t)
+
+	data := make([]byte, 8*1024)
+	errPart := errors.New("boom")
+	var calls atomic.Int32
+
+	uploader := &chunkUploader{
+		file:       bytes.NewReader(data),
+		fileSize:   int64(len(data)),
+		chunkSize:  1024,
+		totalParts: 8,
+		progress:   make(chan UploadProgress, 100),
+		uploadPart: func(ctx context.Context, partNum int, retry bool, body io.Reader, size int64) (string, error) {
+			calls.Add(1)
+			if partNum == 1 {
+				return "", errPart
+			}
+			// Остальные части ждут отмены после ошибки первой
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	}
+
+	_, err := uploader.run(context.Background())
+	if !errors.Is(err, errPart) {
+		t.Fatalf("run() error = %v, want %v", err, errPart)
+	}
+	// Первая часть повторяется maxPartAttempts раз, вторая ждет отмены
+	if got := calls.Load(); got != maxPartAttempts+1 {
+		t.Errorf("uploadPart called %d times, want %d", got, maxPartAttempts+1)
+	}
+}
+
+// TestChunkUploaderRetry проверяет повтор только неудачной части
+func TestChunkUploaderRetry(t *testing.T) {
+	noRetryDelay(t)
+
+	data := make([]byte, 4*1024)
+	progress := make(chan UploadProgress, 100)
+	var mu sync.Mutex
+	attempts := make(map[int][]bool)
+
+	uploader := &chunkUploader{
+		file:       bytes.NewReader(data),
+		fileSize:   int64(len(data)),
+		chunkSize:  1024,
+		totalParts: 4,
+		progress:   progress,
+		uploadPart: func(ctx context.Context, partNum int, retry bool, body io.Reader, size int64) (string, error) {
+			mu.Lock()
+			attempts[partNum] = append(attempts[partNum], retry)
+			first := len(attempts[partNum]) == 1
+			mu.Unlock()
+
+			// Часть 3 обрывается на середине при первой попытке
+			if partNum == 3 && first {
+				io.CopyN(io.Discard, body, size/2)
+				return "", errors.New("connection reset")
+			}
+			io.Copy(io.Discard, body)
+			return "etag", nil
+		},
+	}
+
+	parts, err := uploader.run(context.Background())
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if len(parts) != 4 {
+		t.Fatalf("got %d parts, want 4", len(parts))
+	}
+
+	for num, tries := range attempts {
+		want := []bool{false}
+		if num == 3 {
+			want = []bool{false, true}
+		}
+		if fmt.Sprint(tries) != fmt.Sprint(want) {
+			t.Errorf("part %d attempts = %v, want %v", num, tries, want)
+		}
+	}
+
+	// Прогресс неудачной попытки откатывается и не превышает размер файла
+	close(progress)
+	var last UploadProgress
+	for p := range progress {
+		if p.BytesUploaded > int64(len(data)) {
+			t.Errorf("progress %d exceeds file size", p.BytesUploaded)
+		}
+		last = p
+	}
+	if last.BytesUploaded != int64(len(data)) {
+		t.Errorf("final progress = %d, want %d", last.BytesUploaded, len(data))
+	}
+
+	// Состояние частей: все загружены, у части 3 видна неудачная попытка
+	if len(last.Parts) != 4 {
+		t.Fatalf("got %d part statuses, want 4", len(last.Parts))
+	}
+	for _, part := range last.Parts {
+		wantAttempts, wantErr := 1, ""
+		if part.Number == 3 {
+			wantAttempts, wantErr = 2, "connection reset"
+		}
+		if part.State != PartDone || part.Sent != part.Size || part.Attempts != wantAttempts || part.Err != wantErr {
+			t.Errorf("part %d status = %+v, want done after %d attempts with error %q", part.Number, part, wantAttempts, wantErr)
+		}
+	}
+}
+
+// TestChunkUploaderStall проверяет перезапуск части, по которой перестали идти данные
+func TestChunkUploaderStall(t *testing.T) {
+	noRetryDelay(t)
+	oldStall := partStallTimeout
+	partStallTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { partStallTimeout = oldStall })
+
+	data := make([]byte, 2*1024)
+	var calls atomic.Int32
+
+	uploader := &chunkUploader{
+		file:       bytes.NewReader(data),
+		fileSize:   int64(len(data)),
+		chunkSize:  1024,
+		totalParts: 2,
+		progress:   make(chan UploadProgress, 100),
+		opts:       Options{RetryStalled: true},
+		uploadPart: func(ctx context.Context, partNum int, retry bool, body io.Reader, size int64) (string, error) {
+			// Первая попытка части 2 отправляет немного данных и зависает
+			if partNum == 2 && !retry {
+				calls.Add(1)
+				io.CopyN(io.Discard, body, 10)
+				<-ctx.Done()
+				return "", ctx.Err()
+			}
+			io.Copy(io.Discard, body)
+			return "etag", nil
+		},
+	}
+
+	parts, err := uploader.run(context.Background())
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if len(parts) != 2 || calls.Load() != 1 {
+		t.Errorf("got %d parts after %d stalled attempts, want 2 parts after 1", len(parts), calls.Load())
+	}
+}
+
+// TestChunkUploaderOffline проверяет, что обрыв связи не тратит попытки части
+func TestChunkUploaderOffline(t *testing.T) {
+	noRetryDelay(t)
+
+	data := make([]byte, 1024)
+	progress := make(chan UploadProgress, 100)
+	var calls, waits atomic.Int32
+
+	uploader := &chunkUploader{
+		file:       bytes.NewReader(data),
+		fileSize:   int64(len(data)),
+		chunkSize:  1024,
+		totalParts: 1,
+		progress:   progress,
+		// Связь пропадала при каждой неудачной попытке
+		opts: Options{WaitOnline: func(ctx context.Context, offline func()) (bool, error) {
+			waits.Add(1)
+			offline()
+			return true, nil
+		}},
+		uploadPart: func(ctx context.Context, partNum int, retry bool, body io.Reader, size int64) (string, error) {
+			// Разрывов больше, чем попыток части
+			if calls.Add(1) <= maxPartAttempts+1 {
+				return "", &net.OpError{Op: "write", Net: "tcp", Err: syscall.ECONNRESET}
+			}
+			io.Copy(io.Discard, body)
+			return "etag", nil
+		},
+	}
+
+	if _, err := uploader.run(context.Background()); err != nil {
+		t.Fatalf("run() error = %v, want success after the connection returns", err)
+	}
+	if n := waits.Load(); n != maxPartAttempts+1 {
+		t.Errorf("waited for network %d times, want %d", n, maxPartAttempts+1)
+	}
+
+	close(progress)
+	offline := false
+	for p := range progress {
+		offline = offline || p.Phase == PhaseOffline
+	}
+	if !offline {
+		t.Error("no PhaseOffline progress reported")
+	}
+}
+
+// noRetryDelay убирает паузу между повторами частей
+func noRetryDelay(t *testing.T) {
+	t.Helper()
+	old := partRetryDelay
+	partRetryDelay = 0
+	t.Cleanup(func() { partRetryDelay = old })
+}
+
+// useDryRunServer направляет запросы провайдеров в обработчик тестового сервера, повторы частей без паузы
+func useDryRunServer(t *testing.T, handler http.Handler) {
+	t.Helper()
+	noRetryDelay(t)
+	httpclient.EnableDryRun(handler)
+	t.Cleanup(httpclient.DisableDryRun)
+}
+
+// configured задает настройки тестового провайдера и проверяет, что их достаточно для загрузки
+func configured[P SettingsProvider](t *testing.T, p P, settings map[string]string) P {
+	t.Helper()
+	p.SetSettings(settings)
+	if err := p.ValidateSettings(); err != nil {
+		t.Fatalf("ValidateSettings() = %v", err)
+	}
+	return p
+}
+
+// TestChunkUploaderPause проверяет, что на паузе части не начинаются, а после нее загрузка доходит до конца
+func TestChunkUploaderPause(t *testing.T) {
+	data := make([]byte, 4*1024)
+	var uploaded atomic.Int32
+	resume := make(chan struct{})
+	waiting := make(chan struct{}, 16)
+	ctx := WithPause(context.Background(), func(ctx context.Context) error {
+		waiting <- struct{}{}
+		select {
+		case <-resume:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	uploader := &chunkUploader{
+		file:       bytes.NewReader(data),
+		fileSize:   int64(len(data)),
+		chunkSize:  1024,
+		totalParts: 4,
+		progress:   make(chan UploadProgress, 100),
+		uploadPart: func(ctx context.Context, partNum int, retry bool, body io.Reader, size int64) (string, error) {
+			uploaded.Add(1)
+			return fmt.Sprintf("etag-%d", partNum), nil
+		},
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := uploader.run(ctx)
+		done <- err
+	}()
+
+	<-waiting
+	time.Sleep(50 * time.Millisecond)
+	if n := uploaded.Load(); n != 0 {
+		t.Fatalf("%d parts uploaded while paused, want 0", n)
+	}
+
+	close(resume)
+	if err := <-done; err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if n := uploaded.Load(); n != 4 {
+		t.Errorf("uploaded %d parts, want 4", n)
+	}
+}
+
+// TestConcurrencyController проверяет адаптацию числа потоков
+func TestConcurrencyController(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := newConcurrencyController(2, 4)
+	c.now = func() time.Time { return now }
+	c.resetWindow()
+
+	// Раунд завершается, когда готово столько частей, сколько потоков
+	finishRound := func(bytesPerPart int64, d time.Duration) {
+		now = now.Add(d)
+		for i, n := 0, c.Limit(); i < n; i++ {
+			c.OnPartDone(bytesPerPart)
+		}
+	}
+
+	finishRound(1000, time.Second) // первый замер - пробуем больше потоков
+	if got := c.Limit(); got != 3 {
+		t.Fatalf("after first round Limit() = %d, want 3", got)
+	}
+
+	finishRound(1000, time.Second) // 3000 B/s > 2000 B/s
+	finishRound(1000, time.Second) // 4000 B/s
+	finishRound(1000, time.Second) // достигнут максимум
+	if got := c.Limit(); got != 4 {
+		t.Fatalf("Limit() = %d, want max 4", got)
+	}
+
+	finishRound(100, time.Second) // скорость упала
+	if got := c.Limit(); got != 3 {
+		t.Fatalf("after slowdown Limit() = %d, want 3", got)
+	}
+
+	c.OnError()
+	if got := c.Limit(); got != 1 {
+		t.Fatalf("after error Limit() = %d, want 1", got)
+	}
+	c.OnError()
+	if got := c.Limit(); got != 1 {
+		t.Fatalf("Limit() must not drop below 1, got %d", got)
+	}
+}
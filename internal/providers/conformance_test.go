@@ -0,0 +1,74 @@
+package providers_test
+
+import (
+	"testing"
+
+	"multiUploader/internal/providers"
+	"multiUploader/internal/providers/providertest"
+)
+
+// TestConformance прогоняет общий набор проверок для всех провайдеров
+func TestConformance(t *testing.T) {
+	tests := []struct {
+		name   string
+		config providertest.Config
+	}{
+		{"DataVaults", providertest.Config{New: func() providers.Provider { return providers.NewDataVaultsProvider("test-key") }}},
+		{"FileKeeper", providertest.Config{New: func() providers.Provider { return providers.NewFileKeeperProvider("test-key") }}},
+		// Rootz, AkiraBox и образец Example проверяем и на файле из нескольких частей
+		{"Rootz", providertest.Config{New: func() providers.Provider { return providers.NewRootzProvider("test-key") }, FileSize: 20 << 20}},
+		{"AkiraBox", providertest.Config{New: func() providers.Provider { return providers.NewAkiraBoxProvider("test-key") }, FileSize: 20 << 20}},
+		{"Example", providertest.Config{New: func() providers.Provider { return providers.NewExampleProvider("") }, FileSize: 20 << 20}},
+		{"tus", providertest.Config{New: newTusProvider, FileSize: 20 << 20}},
+		{"Backblaze B2", providertest.Config{New: newB2Provider, FileSize: 20 << 20}},
+		{"Storj", providertest.Config{New: newStorjProvider, FileSize: 20 << 20}},
+		{"Telegram", providertest.Config{New: newTelegramProvider}},
+		{"IPFS node", providertest.Config{New: func() providers.Provider { return providers.NewIPFSProvider() }}},
+		{"IPFS Pinata", providertest.Config{New: newPinataProvider}},
+		// Мок медленный, чтобы отмена успела прийти до конца загрузки
+		{"Mock", providertest.Config{New: func() providers.Provider { return providers.NewMockProvider("Mock", 2) }, Offline: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providertest.Run(t, tt.config)
+		})
+	}
+}
+
+// newTusProvider создает tus провайдер с адресом сервера и частями по 8 МБ
+func newTusProvider() providers.Provider {
+	p := providers.NewTusProvider()
+	p.SetSettings(map[string]string{providers.TusEndpoint: "https://tus.dry-run.invalid/files/"})
+	p.SetOptions(providers.Options{ChunkSize: 8 << 20})
+	return p
+}
+
+// newB2Provider создает провайдер Backblaze B2 с бакетом; файл 20 МБ уходит частями (large file)
+func newB2Provider() providers.Provider {
+	p := providers.NewB2Provider("test-key")
+	p.SetSettings(map[string]string{providers.B2KeyID: "test-key-id", providers.B2Bucket: "dry-run-bucket"})
+	return p
+}
+
+// newStorjProvider создает провайдер Storj с бакетом и частями по 8 МБ
+func newStorjProvider() providers.Provider {
+	p := providers.NewStorjProvider("1Dv4ZLuyhhvTPjPqJWamsUzGLs1TCcmzsPLEyqM4ZMEhMVqpsLgc1TBfi8d5iUY7rH")
+	p.SetSettings(map[string]string{providers.StorjBucket: "dry-run"})
+	p.SetOptions(providers.Options{ChunkSize: 8 << 20})
+	return p
+}
+
+// newTelegramProvider создает Telegram провайдер с токеном бота и каналом
+func newTelegramProvider() providers.Provider {
+	p := providers.NewTelegramProvider("123456:test-token-test-token-test-token")
+	p.SetSettings(map[string]string{providers.TelegramChatID: "@dryrun"})
+	return p
+}
+
+// newPinataProvider создает IPFS провайдер, закрепляющий файлы через Pinata
+func newPinataProvider() providers.Provider {
+	p := providers.NewIPFSProvider()
+	p.SetSettings(map[string]string{providers.IPFSPinataJWT: "test-jwt"})
+	return p
+}
@@ -0,0 +1,322 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// dryRunUploadHost сервер загрузки в ответах dry run (presigned URL, сервер XFS)
+	dryRunUploadHost = "upload.dry-run.invalid"
+	// dryRunChunkSize размер части, который "сервер" предлагает в режиме dry run
+	dryRunChunkSize = 8 * 1024 * 1024
+	// dryRunFileCode код файла в ответах dry run
+	dryRunFileCode = "dryrun000000"
+)
+
+// DryRunHandler отвечает на запросы всех провайдеров заготовленными ответами
+// Используется с httpclient.EnableDryRun: провайдеры проходят весь путь загрузки,
+// но данные никуда не отправляются
+func DryRunHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	xfsDryRun(mux, mustHost(DataVaultsHost.BaseURL), "datavaults")
+	xfsDryRun(mux, mustHost(FileKeeperHost.BaseURL), "filekeeper")
+	// Хостинги XFileSharing, добавленные пользователем: API узнаем по пути на любом хосте
+	xfsDryRun(mux, "", "custom")
+	akiraboxDryRun(mux, mustHost(akiraboxBaseURL))
+	rootzDryRun(mux, mustHost(rootzBaseURL))
+	telegramDryRun(mux, mustHost(telegramAPIURL))
+	ipfsDryRun(mux, mustHost(pinataAPIURL))
+	exampleDryRun(mux, mustHost(exampleBaseURL))
+	b2DryRun(mux, mustHost(b2AuthURL))
+	storjDryRun(mux, mustHost(storjAuthURL))
+
+	// Presigned URL частей: сервер возвращает ETag
+	mux.HandleFunc("PUT "+dryRunUploadHost+"/part/{provider}/{number}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", fmt.Sprintf(`"dry-run-%s"`, r.PathValue("number")))
+	})
+
+	// Адрес tus сервера задает пользователь, поэтому запросы протокола узнаем по заголовку
+	tus := tusDryRun()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Tus-Resumable") != "" {
+			tus.ServeHTTP(w, r)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// xfsDryRun ответы XFileSharing API (DataVaults, FileKeeper и добавленные хостинги)
+func xfsDryRun(mux *http.ServeMux, host, name string) {
+	uploadURL := "https://" + dryRunUploadHost + "/xfs/" + name
+
+	mux.HandleFunc("GET "+host+"/api/upload/server", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"status": 200, "msg": "OK", "sess_id": "dry-run", "result": uploadURL})
+	})
+	mux.HandleFunc("GET "+host+"/api/upload/url", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"status": 200, "msg": "OK", "result": map[string]any{"filecode": dryRunFileCode}})
+	})
+	mux.HandleFunc("GET "+host+"/api/folder/list", func(w http.ResponseWriter, r *http.Request) {
+		folders := []map[string]any{}
+		if r.URL.Query().Get("fld_id") == "0" {
+			folders = append(folders, map[string]any{"name": "Dry run", "fld_id": 1})
+		}
+		writeDryRunJSON(w, map[string]any{"status": 200, "msg": "OK", "result": map[string]any{"folders": folders, "files": []any{}}})
+	})
+	mux.HandleFunc("GET "+host+"/api/account/info", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"status": 200, "msg": "OK", "result": map[string]any{
+			"storage_used":  0,
+			"storage_left":  "inf",
+			"premim_expire": "",
+		}})
+	})
+	mux.HandleFunc("POST "+dryRunUploadHost+"/xfs/"+name, func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, []map[string]any{{"file_code": dryRunFileCode, "file_status": "OK"}})
+	})
+}
+
+// akiraboxDryRun ответы AkiraBox API
+func akiraboxDryRun(mux *http.ServeMux, host string) {
+	mux.HandleFunc("POST "+host+"/api/upload/start", func(w http.ResponseWriter, r *http.Request) {
+		fileSize, _ := strconv.ParseInt(r.URL.Query().Get("fileSize"), 10, 64)
+		writeDryRunJSON(w, map[string]any{
+			"uploadId":    "dry-run",
+			"key":         "dry-run",
+			"providerId":  1,
+			"chunkSize":   dryRunChunkSize,
+			"totalChunks": partCount(fileSize, dryRunChunkSize),
+		})
+	})
+	mux.HandleFunc("GET "+host+"/api/upload/chunk-url", func(w http.ResponseWriter, r *http.Request) {
+		part := r.URL.Query().Get("part-number")
+		writeDryRunJSON(w, map[string]any{"url": "https://" + dryRunUploadHost + "/part/akirabox/" + part})
+	})
+	mux.HandleFunc("POST "+host+"/api/upload/complete", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"download_link": akiraboxBaseURL + "/" + dryRunFileCode})
+	})
+}
+
+// rootzDryRun ответы Rootz API
+func rootzDryRun(mux *http.ServeMux, host string) {
+	mux.HandleFunc("POST "+host+"/api/files/upload", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"success": true, "data": map[string]any{"shortId": dryRunFileCode}})
+	})
+	mux.HandleFunc("POST "+host+"/api/files/multipart/init", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			FileSize int64 `json:"fileSize"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeDryRunJSON(w, map[string]any{
+			"uploadId":   "dry-run",
+			"key":        "dry-run",
+			"chunkSize":  dryRunChunkSize,
+			"totalParts": partCount(req.FileSize, dryRunChunkSize),
+		})
+	})
+	mux.HandleFunc("POST "+host+"/api/files/multipart/batch-urls", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			TotalParts int `json:"totalParts"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		urls := make(map[string]string, req.TotalParts)
+		for i := 1; i <= req.TotalParts; i++ {
+			urls[strconv.Itoa(i)] = fmt.Sprintf("https://%s/part/rootz/%d", dryRunUploadHost, i)
+		}
+		writeDryRunJSON(w, map[string]any{"success": true, "urls": urls})
+	})
+	mux.HandleFunc("POST "+host+"/api/files/multipart/complete", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"success": true, "file": map[string]any{"shortId": dryRunFileCode}})
+	})
+}
+
+// exampleDryRun ответы API образца провайдера (ExampleProvider): его сервер существует только здесь
+func exampleDryRun(mux *http.ServeMux, host string) {
+	mux.HandleFunc("POST "+host+"/v1/uploads", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Size int64 `json:"size"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeDryRunJSON(w, map[string]any{"id": "dry-run", "part_size": dryRunChunkSize, "parts": partCount(req.Size, dryRunChunkSize)})
+	})
+	mux.HandleFunc("GET "+host+"/v1/uploads/{id}/parts/{number}", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"url": "https://" + dryRunUploadHost + "/part/example/" + r.PathValue("number")})
+	})
+	mux.HandleFunc("POST "+host+"/v1/uploads/{id}/complete", func(w http.ResponseWriter, r *http.Request) {
+		link := "https://example.invalid/f/" + dryRunFileCode
+		writeDryRunJSON(w, map[string]any{"url": link, "download_url": link + "/download"})
+	})
+}
+
+// b2DryRun ответы нативного API Backblaze B2: авторизация, бакет, адреса загрузки и large file
+func b2DryRun(mux *http.ServeMux, host string) {
+	const apiHost = "api000.dry-run.invalid"
+	uploadURL := "https://" + dryRunUploadHost + "/b2/"
+
+	mux.HandleFunc("GET "+host+b2APIPath+"b2_authorize_account", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{
+			"accountId":               "dry-run",
+			"authorizationToken":      "dry-run",
+			"apiUrl":                  "https://" + apiHost,
+			"downloadUrl":             "https://f000.dry-run.invalid",
+			"recommendedPartSize":     dryRunChunkSize,
+			"absoluteMinimumPartSize": b2MinPartSize,
+		})
+	})
+	mux.HandleFunc("POST "+apiHost+b2APIPath+"b2_list_buckets", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			BucketName string `json:"bucketName"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeDryRunJSON(w, map[string]any{"buckets": []map[string]any{
+			{"bucketId": "dry-run", "bucketName": req.BucketName, "bucketType": "allPublic"},
+		}})
+	})
+	mux.HandleFunc("POST "+apiHost+b2APIPath+"b2_get_upload_url", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"uploadUrl": uploadURL + "file", "authorizationToken": "dry-run"})
+	})
+	mux.HandleFunc("POST "+apiHost+b2APIPath+"b2_start_large_file", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"fileId": dryRunFileCode})
+	})
+	mux.HandleFunc("POST "+apiHost+b2APIPath+"b2_get_upload_part_url", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"uploadUrl": uploadURL + "part", "authorizationToken": "dry-run"})
+	})
+	mux.HandleFunc("POST "+apiHost+b2APIPath+"b2_cancel_large_file", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"fileId": dryRunFileCode})
+	})
+	mux.HandleFunc("POST "+apiHost+b2APIPath+"b2_finish_large_file", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"fileId": dryRunFileCode})
+	})
+	mux.HandleFunc("POST "+dryRunUploadHost+"/b2/file", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"fileId": dryRunFileCode})
+	})
+	mux.HandleFunc("POST "+dryRunUploadHost+"/b2/part", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"fileId": dryRunFileCode, "partNumber": r.Header.Get("X-Bz-Part-Number")})
+	})
+}
+
+// storjDryRun ответы сервиса авторизации Storj и его S3 шлюза
+func storjDryRun(mux *http.ServeMux, authHost string) {
+	const gatewayHost = "gateway.dry-run.invalid"
+
+	mux.HandleFunc("POST "+authHost+"/v1/access", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"access_key_id": "dry-run", "secret_key": "dry-run", "endpoint": "https://" + gatewayHost})
+	})
+	// Операцию S3 определяют метод и параметры query
+	mux.HandleFunc(gatewayHost+"/{bucket}/{key...}", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPost && query.Has("uploads"):
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>dry-run</UploadId></InitiateMultipartUploadResult>")
+		case r.Method == http.MethodPut && query.Has("partNumber"):
+			w.Header().Set("ETag", fmt.Sprintf(`"dry-run-%s"`, query.Get("partNumber")))
+		case r.Method == http.MethodPost && query.Has("uploadId"):
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, "<CompleteMultipartUploadResult><Key>%s</Key></CompleteMultipartUploadResult>", r.PathValue("key"))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// telegramDryRun ответы Telegram Bot API: каждый документ становится новым сообщением канала
+func telegramDryRun(mux *http.ServeMux, host string) {
+	var messageID atomic.Int64
+	mux.HandleFunc("POST "+host+"/{bot}/sendDocument", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"ok": true, "result": map[string]any{
+			"message_id": messageID.Add(1),
+			"chat":       map[string]any{"id": int64(-1001234567890), "type": "channel", "username": "dryrun"},
+			"document":   map[string]any{"file_id": dryRunFileCode},
+		}})
+	})
+}
+
+// ipfsDryRun ответы узла IPFS (на любом адресе) и Pinata
+func ipfsDryRun(mux *http.ServeMux, pinataHost string) {
+	// CIDv1 пустого файла: настоящий CID зависит от содержимого, которое dry run не читает
+	const cid = "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"
+
+	mux.HandleFunc("POST /api/v0/add", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"Name": "dry-run", "Hash": cid, "Size": strconv.FormatInt(r.ContentLength, 10)})
+	})
+	mux.HandleFunc("POST "+pinataHost+"/pinning/pinFileToIPFS", func(w http.ResponseWriter, r *http.Request) {
+		writeDryRunJSON(w, map[string]any{"IpfsHash": cid, "PinSize": r.ContentLength})
+	})
+}
+
+// tusDryRun ответы tus сервера: помнит размер и смещение каждой созданной загрузки
+func tusDryRun() http.Handler {
+	var mu sync.Mutex
+	offsets := make(map[string]int64)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Tus-Resumable", tusVersion)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodPost {
+			id := fmt.Sprintf("dry-run-%d", len(offsets)+1)
+			offsets[id] = 0
+			w.Header().Set("Location", id)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		id := path.Base(r.URL.Path)
+		offset, ok := offsets[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(offsets, id)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.Method == http.MethodPatch {
+			// Тело обрезано транспортом dry run - размер берем из заголовка
+			offset += r.ContentLength
+			offsets[id] = offset
+		}
+		w.Header().Set("Upload-Offset", strconv.FormatInt(offset, 10))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// writeDryRunJSON пишет ответ в формате JSON
+func writeDryRunJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// mustHost возвращает хост из базового URL провайдера
+func mustHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		panic(err)
+	}
+	return u.Host
+}
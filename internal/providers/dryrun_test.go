@@ -0,0 +1,60 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"multiUploader/internal/httpclient"
+)
+
+// TestDryRun проверяет, что каждый провайдер проходит загрузку на заготовленных ответах
+func TestDryRun(t *testing.T) {
+	httpclient.EnableDryRun(DryRunHandler())
+	defer httpclient.DisableDryRun()
+
+	uploaders := []Provider{
+		NewDataVaultsProvider("key"),
+		NewFileKeeperProvider("key"),
+		NewXFSProvider(XFSHost{Name: "Custom", BaseURL: "https://xfs.example"}, "key"),
+		NewAkiraBoxProvider("key"),
+		NewRootzProvider("key"),
+		NewExampleProvider(""),
+	}
+
+	// Маленький файл и файл из нескольких частей (Rootz, AkiraBox и Example грузят его по частям)
+	sizes := []int64{1024, 2*dryRunChunkSize + 1}
+
+	for _, provider := range uploaders {
+		for _, size := range sizes {
+			progress := make(chan UploadProgress, 100)
+			go func() {
+				for range progress {
+				}
+			}()
+
+			result, err := provider.Upload(context.Background(), bytes.NewReader(make([]byte, size)), "file.bin", size, progress)
+			close(progress)
+			if err != nil {
+				t.Errorf("%s: Upload(%d bytes) error = %v", provider.Name(), size, err)
+				continue
+			}
+			if !strings.Contains(result.URL, dryRunFileCode) {
+				t.Errorf("%s: URL = %q, want dry run file code", provider.Name(), result.URL)
+			}
+		}
+	}
+
+	// Тела запросов прочитаны полностью, но в журнал попали без API ключей
+	var sent int64
+	for _, tx := range httpclient.DryRunLog() {
+		sent += tx.Sent
+		if strings.Contains(tx.URL, "key=") {
+			t.Errorf("API key leaked into dry run log: %s", tx.URL)
+		}
+	}
+	if want := int64(len(uploaders)) * (sizes[0] + sizes[1]); sent < want {
+		t.Errorf("dry run received %d bytes, want at least %d", sent, want)
+	}
+}
@@ -0,0 +1,159 @@
+package providers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxErrorBody максимальный размер тела ответа, сохраняемого в HTTPError
+	maxErrorBody = 1024
+	// maxErrorSnippet максимальная длина фрагмента тела в тексте ошибки
+	maxErrorSnippet = 200
+)
+
+// ErrCancelled возвращается, если загрузка отменена пользователем
+var ErrCancelled = errors.New("upload cancelled")
+
+// HTTPError неуспешный HTTP ответ сервера провайдера
+type HTTPError struct {
+	Op     string // операция, например "start upload"
+	Status int    // HTTP статус код
+	Body   string // начало тела ответа без разметки и управляющих символов
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%s failed with status %d", e.Op, e.Status)
+	}
+	return fmt.Sprintf("%s failed with status %d: %s", e.Op, e.Status, truncate(e.Body, maxErrorSnippet))
+}
+
+// AuthError сервер отклонил API ключ или запретил операцию
+type AuthError struct {
+	// Forbidden true для 403 (ключ верный, но не хватает прав)
+	Forbidden bool
+	Err       error
+}
+
+func (e *AuthError) Error() string {
+	return "authentication failed: " + e.Err.Error()
+}
+
+func (e *AuthError) Unwrap() error { return e.Err }
+
+// QuotaReason причина превышения лимита
+type QuotaReason int
+
+const (
+	// QuotaFileTooLarge файл больше допустимого размера
+	QuotaFileTooLarge QuotaReason = iota
+	// QuotaRateLimit слишком много запросов
+	QuotaRateLimit
+)
+
+// QuotaError превышен лимит провайдера (размер файла, частота запросов)
+type QuotaError struct {
+	Reason QuotaReason
+	// RetryAfter пауза из заголовка Retry-After (0 - сервер не указал)
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *QuotaError) Error() string {
+	return "quota exceeded: " + e.Err.Error()
+}
+
+func (e *QuotaError) Unwrap() error { return e.Err }
+
+// ServerError ошибка, о которой сервер сообщил в теле успешного ответа
+type ServerError struct {
+	Op      string // операция
+	Message string // сообщение сервера
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("%s: server returned error: %s", e.Op, e.Message)
+}
+
+// statusError создает типизированную ошибку по неуспешному HTTP ответу
+// Тело ответа читается частично, закрытие остается на вызывающем
+func statusError(op string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	httpErr := &HTTPError{
+		Op:     op,
+		Status: resp.StatusCode,
+		Body:   sanitizeBody(body),
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return &AuthError{Err: httpErr}
+	case http.StatusForbidden:
+		return &AuthError{Forbidden: true, Err: httpErr}
+	case http.StatusRequestEntityTooLarge:
+		return &QuotaError{Reason: QuotaFileTooLarge, Err: httpErr}
+	case http.StatusTooManyRequests:
+		return &QuotaError{Reason: QuotaRateLimit, RetryAfter: retryAfter(resp.Header, time.Now()), Err: httpErr}
+	}
+	return httpErr
+}
+
+// retryAfter разбирает Retry-After: число секунд или HTTP дата
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
+
+// sanitizeBody готовит тело ответа к выводу в ошибке и логах:
+// убирает HTML теги и управляющие символы, схлопывает пробелы
+func sanitizeBody(body []byte) string {
+	if !utf8.Valid(body) {
+		body = bytes.ToValidUTF8(body, []byte("?"))
+	}
+
+	var sb strings.Builder
+	inTag := false
+	for _, r := range string(body) {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+			sb.WriteRune(' ')
+		case inTag:
+		case unicode.IsSpace(r) || unicode.IsControl(r):
+			sb.WriteRune(' ')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// truncate обрезает строку до max символов
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "…"
+}
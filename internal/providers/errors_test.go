@@ -0,0 +1,175 @@
+package providers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TestStatusError проверяет выбор типа ошибки по HTTP статусу
+func TestStatusError(t *testing.T) {
+	newResponse := func(status int, body string) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		err := statusError("upload", newResponse(http.StatusUnauthorized, "bad key"))
+
+		var authErr *AuthError
+		if !errors.As(err, &authErr) || authErr.Forbidden {
+			t.Fatalf("statusError(401) = %#v, want AuthError", err)
+		}
+
+		// HTTPError доступен через Unwrap
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Status != 401 || httpErr.Body != "bad key" {
+			t.Errorf("unwrapped HTTPError = %#v", httpErr)
+		}
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		var authErr *AuthError
+		err := statusError("upload", newResponse(http.StatusForbidden, ""))
+		if !errors.As(err, &authErr) || !authErr.Forbidden {
+			t.Errorf("statusError(403) = %#v, want forbidden AuthError", err)
+		}
+	})
+
+	t.Run("Quota", func(t *testing.T) {
+		var quotaErr *QuotaError
+		err := statusError("upload", newResponse(http.StatusRequestEntityTooLarge, ""))
+		if !errors.As(err, &quotaErr) || quotaErr.Reason != QuotaFileTooLarge {
+			t.Errorf("statusError(413) = %#v, want QuotaFileTooLarge", err)
+		}
+
+		err = statusError("upload", newResponse(http.StatusTooManyRequests, ""))
+		if !errors.As(err, &quotaErr) || quotaErr.Reason != QuotaRateLimit {
+			t.Errorf("statusError(429) = %#v, want QuotaRateLimit", err)
+		}
+	})
+
+	t.Run("Server error", func(t *testing.T) {
+		err := statusError("complete upload", newResponse(http.StatusBadGateway, strings.Repeat("x", 2*maxErrorBody)))
+
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) {
+			t.Fatalf("statusError(502) = %#v, want HTTPError", err)
+		}
+		if len(httpErr.Body) != maxErrorBody {
+			t.Errorf("Body length = %d, want %d", len(httpErr.Body), maxErrorBody)
+		}
+		if want := "complete upload failed with status 502: " + strings.Repeat("x", maxErrorSnippet) + "…"; err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("Empty body", func(t *testing.T) {
+		err := statusError("upload", newResponse(http.StatusInternalServerError, ""))
+		if err.Error() != "upload failed with status 500" {
+			t.Errorf("Error() = %q", err.Error())
+		}
+	})
+}
+
+// TestSanitizeBody проверяет очистку тела ответа
+func TestSanitizeBody(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"error":"file rejected"}`, `{"error":"file rejected"}`},
+		{"<html><body><h1>502 Bad Gateway</h1>\n<hr>nginx</body></html>", "502 Bad Gateway nginx"},
+		{"line1\r\n\tline2\x00", "line1 line2"},
+		{"bad \xff byte", "bad ? byte"},
+	}
+
+	for _, tt := range tests {
+		if got := sanitizeBody([]byte(tt.body)); got != tt.want {
+			t.Errorf("sanitizeBody(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+// TestRetryAfter проверяет разбор заголовка Retry-After
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-5", 0},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.value != "" {
+			header.Set("Retry-After", tt.value)
+		}
+		if got := retryAfter(header, now); got != tt.want {
+			t.Errorf("retryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+// FuzzStatusError проверяет, что любой ответ сервера дает ошибку нужного типа,
+// а текст ошибки остается коротким и без разметки и управляющих символов
+func FuzzStatusError(f *testing.F) {
+	f.Add(401, []byte("bad key"), "")
+	f.Add(429, []byte(`{"error":"slow down"}`), "120")
+	f.Add(502, []byte("<html><body><h1>502 Bad Gateway</h1></body></html>"), "")
+	f.Add(500, []byte("bad \xff\x00 byte\r\n"), "Wed, 21 Oct 2015 07:28:00 GMT")
+	f.Add(-1, []byte{}, "-5")
+
+	f.Fuzz(func(t *testing.T, status int, body []byte, retry string) {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(string(body)))}
+		resp.Header.Set("Retry-After", retry)
+		err := statusError("upload", resp)
+
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Status != status {
+			t.Fatalf("statusError(%d) = %#v, want HTTPError with the same status", status, err)
+		}
+		var authErr *AuthError
+		var quotaErr *QuotaError
+		isAuth, isQuota := errors.As(err, &authErr), errors.As(err, &quotaErr)
+		switch status {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			if !isAuth || authErr.Forbidden != (status == http.StatusForbidden) {
+				t.Errorf("statusError(%d) = %#v, want AuthError", status, err)
+			}
+		case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+			if !isQuota || quotaErr.RetryAfter < 0 {
+				t.Errorf("statusError(%d) = %#v, want QuotaError", status, err)
+			}
+		default:
+			if isAuth || isQuota {
+				t.Errorf("statusError(%d) = %#v, want plain HTTPError", status, err)
+			}
+		}
+
+		if len(httpErr.Body) > maxErrorBody {
+			t.Errorf("Body length = %d, want at most %d", len(httpErr.Body), maxErrorBody)
+		}
+		msg := err.Error()
+		if !utf8.ValidString(msg) {
+			t.Errorf("Error() is not valid UTF-8: %q", msg)
+		}
+		if strings.ContainsFunc(msg, unicode.IsControl) {
+			t.Errorf("Error() contains control characters: %q", msg)
+		}
+		if n := utf8.RuneCountInString(httpErr.Error()); n > maxErrorSnippet+64 {
+			t.Errorf("Error() is %d characters long", n)
+		}
+	})
+}
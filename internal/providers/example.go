@@ -0,0 +1,201 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"multiUploader/internal/httpclient"
+)
+
+// Этот файл - образец нового провайдера. Он загружает на вымышленный хостинг
+// api.example.invalid, на запросы которого отвечает только режим dry run
+// (Help → Dry Run Mode), и включается в меню Developer (MULTIUPLOADER_DEVELOPER=1)
+//
+// API вымышленного хостинга устроено как у большинства S3-совместимых хостингов:
+//
+//	POST /v1/uploads                   {"name", "size"} → {"id", "part_size", "parts"}
+//	GET  /v1/uploads/{id}/parts/{n}    → {"url"} - presigned URL части, принимает PUT и отвечает ETag
+//	POST /v1/uploads/{id}/complete     {"parts": [{"number", "etag"}]} → {"url", "download_url"}
+//
+// Ошибки API приходят с HTTP статусом и телом {"error": "..."}
+// Чтобы сделать из образца свой провайдер, скопируйте файл, замените адреса и поля JSON
+// и зарегистрируйте фабрику в main.go
+
+// exampleBaseURL адрес API вымышленного хостинга
+const exampleBaseURL = "https://api.example.invalid"
+
+// ExampleProvider образец провайдера с загрузкой частями по presigned URL
+type ExampleProvider struct {
+	// apiKey ключ API из настроек провайдера ("" - анонимная загрузка)
+	apiKey string
+
+	// opts настройки из раздела "Advanced", их задает SetOptions
+	// Провайдер не читает конфиг сам: настройки передает UI, когда создает провайдер
+	opts Options
+}
+
+// NewExampleProvider создает образец провайдера
+// Конструктор только сохраняет параметры: сеть в нем не используется
+func NewExampleProvider(apiKey string) *ExampleProvider {
+	return &ExampleProvider{apiKey: apiKey}
+}
+
+// Name название провайдера в списке загрузки и в истории
+func (e *ExampleProvider) Name() string {
+	return "Example"
+}
+
+// RequiresAuth образец загружает и без ключа, чтобы его можно было попробовать сразу
+// Провайдер хостинга, который требует ключ, возвращает true: UI тогда не даст начать загрузку без него
+func (e *ExampleProvider) RequiresAuth() bool {
+	return false
+}
+
+// ValidateAPIKey проверяет формат ключа при сохранении настроек, без запросов к серверу
+func (e *ExampleProvider) ValidateAPIKey(apiKey string) error {
+	if len(apiKey) > 0 && len(apiKey) < 8 {
+		return fmt.Errorf("API key is too short")
+	}
+	return nil
+}
+
+// Probe проверяет доступность сервера для вкладки состояния провайдеров (необязательно)
+func (e *ExampleProvider) Probe(ctx context.Context) error {
+	return probeURL(ctx, exampleBaseURL)
+}
+
+// Capabilities сообщает UI ограничения провайдера (необязательно)
+// Multipart включает в настройках выбор размера части
+func (e *ExampleProvider) Capabilities() Capabilities {
+	return Capabilities{Multipart: true}
+}
+
+// SetOptions получает настройки из раздела "Advanced" (реализует Configurable)
+func (e *ExampleProvider) SetOptions(opts Options) {
+	e.opts = opts
+}
+
+// api клиент запросов к API с авторизацией ключом
+// Middleware применяется к каждой попытке запроса, повторы при сетевых ошибках и 5xx делает httpclient
+func (e *ExampleProvider) api() *httpclient.Client {
+	if e.apiKey == "" {
+		return httpclient.Default()
+	}
+	return httpclient.Default().With(httpclient.BearerAuth(e.apiKey))
+}
+
+// Upload загружает файл
+// Разбиение на части, параллельность, повторы частей, ожидание сети и прогресс берет на себя
+// presignedUpload - провайдер описывает только три запроса к своему API
+// Ошибки оборачиваются через %w: UI узнает по errors.As авторизацию, лимиты и отмену
+func (e *ExampleProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
+	// Состояние загрузки живет в замыканиях, а не в полях провайдера:
+	// один экземпляр провайдера может загружать несколько файлов одновременно
+	var uploadID string
+
+	upload := &presignedUpload{
+		provider:    e.Name(),
+		contentType: fileContentType(file, filename),
+		opts:        e.opts,
+		init: func(ctx context.Context) (partLayout, error) {
+			var resp struct {
+				ID       string `json:"id"`
+				PartSize int64  `json:"part_size"`
+				Parts    int    `json:"parts"`
+			}
+			err := e.call(ctx, "start upload", http.MethodPost, "/v1/uploads", map[string]any{"name": filename, "size": fileSize}, &resp)
+			if err != nil {
+				return partLayout{}, err
+			}
+			// Ответ сервера проверяется: паника на неожиданном JSON уронит все приложение
+			if resp.ID == "" || resp.PartSize <= 0 {
+				return partLayout{}, &ServerError{Op: "start upload", Message: "response has no upload id or part size"}
+			}
+			uploadID = resp.ID
+			return partLayout{ChunkSize: resp.PartSize, TotalParts: resp.Parts}, nil
+		},
+		// URL запрашивается на каждую попытку части, поэтому повтор всегда получает свежий
+		partURL: func(ctx context.Context, _ partLayout, partNum int, _ bool) (string, error) {
+			var resp struct {
+				URL string `json:"url"`
+			}
+			err := e.call(ctx, "get part URL", http.MethodGet, fmt.Sprintf("/v1/uploads/%s/parts/%d", url.PathEscape(uploadID), partNum), nil, &resp)
+			if err == nil && resp.URL == "" {
+				err = &ServerError{Op: "get part URL", Message: "response has no URL"}
+			}
+			return resp.URL, err
+		},
+		complete: func(ctx context.Context, parts []uploadedPart) (*UploadResult, error) {
+			type part struct {
+				Number int    `json:"number"`
+				ETag   string `json:"etag"`
+			}
+			list := make([]part, len(parts))
+			for i, p := range parts {
+				list[i] = part{Number: p.Number, ETag: p.ETag}
+			}
+
+			var resp struct {
+				URL         string `json:"url"`
+				DownloadURL string `json:"download_url"`
+			}
+			err := e.call(ctx, "complete upload", http.MethodPost, "/v1/uploads/"+url.PathEscape(uploadID)+"/complete", map[string]any{"parts": list}, &resp)
+			if err != nil {
+				return nil, err
+			}
+			if resp.URL == "" {
+				return nil, &ServerError{Op: "complete upload", Message: "response has no file URL"}
+			}
+			return &UploadResult{URL: resp.URL, DownloadURL: resp.DownloadURL}, nil
+		},
+	}
+
+	result, err := upload.run(ctx, file, fileSize, progress)
+	if err != nil && ctx.Err() != nil {
+		// Отмену пользователем UI показывает не как ошибку
+		return nil, ErrCancelled
+	}
+	return result, err
+}
+
+// call выполняет JSON запрос к API и разбирает ответ в result; op - название операции в ошибках
+// Неуспешный статус превращается в типизированную ошибку через statusError
+// (401/403 - AuthError, 413 и 429 - QuotaError, остальные - HTTPError)
+func (e *ExampleProvider) call(ctx context.Context, op, method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, exampleBaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := e.api().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError(op, resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
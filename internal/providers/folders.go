@@ -0,0 +1,35 @@
+package providers
+
+import "context"
+
+// Folder папка в аккаунте провайдера
+type Folder struct {
+	ID string
+	// Path путь от корня, например "Work/2024"
+	Path string
+}
+
+// FolderProvider реализуется провайдерами, которые умеют загружать в папку аккаунта
+type FolderProvider interface {
+	// ListFolders возвращает папки аккаунта (без корня)
+	ListFolders(ctx context.Context) ([]Folder, error)
+	// SetFolder задает папку для следующих загрузок ("" - корень)
+	SetFolder(id string)
+	// Folder папка, заданная SetFolder
+	Folder() string
+}
+
+// Collection папка-альбом, в которую загружается группа файлов
+type Collection struct {
+	ID string
+	// URL публичная ссылка на всю папку
+	URL string
+}
+
+// CollectionProvider реализуется провайдерами с папками, у которых есть общая ссылка на папку
+// Файлы группы загружаются в созданную папку через FolderProvider.SetFolder
+type CollectionProvider interface {
+	FolderProvider
+	// CreateCollection создает папку name внутри выбранной папки
+	CreateCollection(ctx context.Context, name string) (*Collection, error)
+}
@@ -0,0 +1,37 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+
+	"multiUploader/internal/httpclient"
+)
+
+// Prober реализуется провайдерами, которые умеют проверять доступность своего сервера
+type Prober interface {
+	// Probe возвращает nil, если сервер провайдера отвечает
+	Probe(ctx context.Context) error
+}
+
+// probeURL проверяет сервер легким HEAD запросом
+// Любой ответ, кроме 5xx, означает, что сервер доступен (404 и 405 на HEAD - нормально)
+func probeURL(ctx context.Context, target string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpclient.Probe().Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ErrCancelled
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return statusError("probe", resp)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestProbeURL проверяет, какие ответы считаются доступностью сервера
+func TestProbeURL(t *testing.T) {
+	tests := []struct {
+		status int
+		ok     bool
+	}{
+		{http.StatusOK, true},
+		{http.StatusMethodNotAllowed, true},
+		{http.StatusNotFound, true},
+		{http.StatusInternalServerError, false},
+		{http.StatusServiceUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodHead {
+				t.Errorf("method = %s, want HEAD", r.Method)
+			}
+			w.WriteHeader(tt.status)
+		}))
+
+		err := probeURL(context.Background(), server.URL)
+		if (err == nil) != tt.ok {
+			t.Errorf("probeURL() with status %d = %v, want ok %v", tt.status, err, tt.ok)
+		}
+		server.Close()
+	}
+
+	// Закрытый сервер недоступен
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	if err := probeURL(context.Background(), server.URL); err == nil {
+		t.Error("probeURL() of closed server = nil, want error")
+	}
+}
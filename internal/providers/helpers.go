@@ -1,10 +1,15 @@
 package providers
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/textproto"
+	"strings"
 	"sync/atomic"
+
+	"multiUploader/internal/mimetype"
 )
 
 type ByteCounter struct {
@@ -71,9 +76,9 @@ func NewMultipartWriter(w io.Writer) *MultipartWriter {
 	}
 }
 
-// WriteFile записывает файл в multipart form
+// WriteFile записывает файл в multipart form с MIME типом, определенным по содержимому и имени
 func (mw *MultipartWriter) WriteFile(fieldName, filename string, data []byte) error {
-	part, err := mw.writer.CreateFormFile(fieldName, filename)
+	part, err := createFormFile(mw.writer, fieldName, filename, mimetype.Detect(data[:min(len(data), mimetype.SniffLen)], filename))
 	if err != nil {
 		return err
 	}
@@ -95,3 +100,48 @@ func (mw *MultipartWriter) Close() error {
 func (mw *MultipartWriter) FormDataContentType() string {
 	return mw.writer.FormDataContentType()
 }
+
+// multipartFileBody собирает тело multipart/form-data с полями и одним файлом, не буферизуя файл
+// fileType - MIME тип файла. Возвращает тело, Content-Type формы и точную длину (для Content-Length)
+// Если size < 0, длина тоже -1: тело отправляется без Content-Length
+func multipartFileBody(fields [][2]string, fieldName, filename, fileType string, file io.Reader, size int64) (io.Reader, string, int64, error) {
+	var head bytes.Buffer
+	mw := multipart.NewWriter(&head)
+	for _, field := range fields {
+		if err := mw.WriteField(field[0], field[1]); err != nil {
+			return nil, "", 0, err
+		}
+	}
+	if _, err := createFormFile(mw, fieldName, filename, fileType); err != nil {
+		return nil, "", 0, err
+	}
+	tail := "\r\n--" + mw.Boundary() + "--\r\n"
+
+	// Размер потока неизвестен - тело уходит с chunked кодированием
+	length := int64(-1)
+	if size >= 0 {
+		length = int64(head.Len()) + size + int64(len(tail))
+	}
+	return io.MultiReader(&head, file, strings.NewReader(tail)), mw.FormDataContentType(), length, nil
+}
+
+// quoteEscaper экранирует кавычки в Content-Disposition так же, как multipart.Writer.CreateFormFile
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// createFormFile как multipart.Writer.CreateFormFile, но с MIME типом файла вместо application/octet-stream
+func createFormFile(mw *multipart.Writer, fieldName, filename, contentType string) (io.Writer, error) {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(fieldName), quoteEscaper.Replace(filename)))
+	header.Set("Content-Type", contentType)
+	return mw.CreatePart(header)
+}
+
+// fileContentType определяет MIME тип загружаемого файла, не сдвигая позицию чтения
+func fileContentType(file io.ReadSeeker, filename string) string {
+	contentType, err := mimetype.DetectReader(file, filename)
+	if err != nil {
+		return mimetype.Default
+	}
+	return contentType
+}
@@ -229,5 +229,8 @@ func TestMultipartWriter(t *testing.T) {
 		if !strings.Contains(content, "file content") {
 			t.Errorf("Buffer doesn't contain file data: %s", content)
 		}
+		if !strings.Contains(content, "Content-Type: text/plain") {
+			t.Errorf("Buffer doesn't contain file MIME type: %s", content)
+		}
 	})
 }
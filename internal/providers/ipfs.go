@@ -0,0 +1,257 @@
+package providers
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"multiUploader/internal/httpclient"
+)
+
+const (
+	// ipfsDefaultNode адрес HTTP API локального узла (Kubo)
+	ipfsDefaultNode = "http://127.0.0.1:5001"
+	// ipfsDefaultGateway публичный шлюз для ссылок
+	ipfsDefaultGateway = "https://ipfs.io"
+	// pinataAPIURL API сервиса закрепления Pinata
+	pinataAPIURL = "https://api.pinata.cloud"
+)
+
+// Ключи полей настроек IPFS
+const (
+	// IPFSNode адрес HTTP API локального узла
+	IPFSNode = "node"
+	// IPFSPinataJWT JWT ключ Pinata: если задан, файл закрепляется через Pinata, а не на узле
+	IPFSPinataJWT = "pinata_jwt"
+	// IPFSGateway шлюз, через который строятся ссылки
+	IPFSGateway = "gateway"
+)
+
+// ipfsAltGateways дополнительные шлюзы, ссылки на которые добавляются в сообщение результата
+var ipfsAltGateways = []string{"https://dweb.link", "https://w3s.link"}
+
+// IPFSProvider добавляет файл в IPFS через локальный узел или сервис закрепления Pinata
+// и возвращает ссылки на шлюзы
+type IPFSProvider struct {
+	node      string
+	pinataJWT string
+	gateway   string
+	opts      Options
+}
+
+// NewIPFSProvider создает провайдер IPFS; узел, ключ Pinata и шлюз задаются через SetSettings
+func NewIPFSProvider() *IPFSProvider {
+	return &IPFSProvider{node: ipfsDefaultNode, gateway: ipfsDefaultGateway}
+}
+
+func (p *IPFSProvider) Name() string {
+	return "IPFS"
+}
+
+// RequiresAuth локальному узлу ключ не нужен; ключ Pinata задается отдельным полем
+func (p *IPFSProvider) RequiresAuth() bool {
+	return false
+}
+
+func (p *IPFSProvider) ValidateAPIKey(apiKey string) error {
+	return nil
+}
+
+// Probe проверяет, что узел или Pinata отвечают
+func (p *IPFSProvider) Probe(ctx context.Context) error {
+	if p.pinataJWT != "" {
+		return probeURL(ctx, pinataAPIURL)
+	}
+	return probeURL(ctx, p.node)
+}
+
+func (p *IPFSProvider) SettingFields() []SettingField {
+	return []SettingField{
+		{Key: IPFSNode, Label: "Node API URL:", Placeholder: ipfsDefaultNode},
+		{Key: IPFSPinataJWT, Label: "Pinata JWT (instead of a local node):", Secret: true},
+		{Key: IPFSGateway, Label: "Gateway:", Placeholder: ipfsDefaultGateway},
+	}
+}
+
+// SetOptions применяет дополнительные настройки (используется буфер чтения)
+func (p *IPFSProvider) SetOptions(opts Options) {
+	p.opts = opts
+}
+
+// SetSettings применяет настройки; пустые поля получают значения по умолчанию
+func (p *IPFSProvider) SetSettings(values map[string]string) {
+	p.node = cmp.Or(strings.TrimSuffix(values[IPFSNode], "/"), ipfsDefaultNode)
+	p.pinataJWT = values[IPFSPinataJWT]
+	p.gateway = cmp.Or(strings.TrimSuffix(values[IPFSGateway], "/"), ipfsDefaultGateway)
+}
+
+func (p *IPFSProvider) ValidateSettings() error {
+	if p.pinataJWT == "" && !isHTTPURL(p.node) {
+		return errors.New("node API URL must be an http or https address")
+	}
+	if !isHTTPURL(p.gateway) {
+		return errors.New("gateway must be an http or https address")
+	}
+	return nil
+}
+
+// ServerURL адрес узла; при загрузке через Pinata свой сервер не используется
+func (p *IPFSProvider) ServerURL() string {
+	if p.pinataJWT != "" {
+		return ""
+	}
+	return p.node
+}
+
+// Upload добавляет файл в IPFS и возвращает ссылку на шлюз с его CID
+func (p *IPFSProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
+	return p.UploadStream(ctx, file, filename, fileSize, progress)
+}
+
+// UploadStream добавляет поток в IPFS одной формой multipart - Seek не нужен
+func (p *IPFSProvider) UploadStream(ctx context.Context, file io.Reader, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
+	if ctx.Err() != nil {
+		return nil, ErrCancelled
+	}
+
+	contentType, file := streamContentType(file, filename)
+	file = p.opts.readBuffer(file)
+	tracker := newProgressTracker(fileSize, progress)
+	body := &progressReader{reader: file, onProgress: tracker.Add}
+
+	var cid string
+	var err error
+	if p.pinataJWT != "" {
+		cid, err = p.pinPinata(ctx, body, filename, contentType, fileSize, progress)
+	} else {
+		cid, err = p.addToNode(ctx, body, filename, contentType, fileSize, progress)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ErrCancelled
+		}
+		return nil, err
+	}
+
+	link := ipfsGatewayURL(p.gateway, cid, filename)
+	links := []string{"ipfs://" + cid}
+	for _, gateway := range ipfsAltGateways {
+		if gateway != p.gateway {
+			links = append(links, ipfsGatewayURL(gateway, cid, filename))
+		}
+	}
+
+	return &UploadResult{
+		URL:         link,
+		DownloadURL: link,
+		FileID:      cid,
+		Message:     "Also available at " + strings.Join(links, " , "),
+	}, nil
+}
+
+// addToNode добавляет и закрепляет файл на узле (POST /api/v0/add)
+func (p *IPFSProvider) addToNode(ctx context.Context, file io.Reader, filename, contentType string, fileSize int64, progress chan<- UploadProgress) (string, error) {
+	query := url.Values{"pin": {"true"}, "cid-version": {"1"}}
+	resp, err := p.postFile(ctx, p.node+"/api/v0/add?"+query.Encode(), "file", file, filename, contentType, fileSize, progress, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError("add to IPFS node", resp)
+	}
+
+	var result struct {
+		Hash string `json:"Hash"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse IPFS node response: %w", err)
+	}
+	if result.Hash == "" {
+		return "", &ServerError{Op: "add to IPFS node", Message: "response has no CID"}
+	}
+	return result.Hash, nil
+}
+
+// pinPinata загружает и закрепляет файл через Pinata (POST /pinning/pinFileToIPFS)
+func (p *IPFSProvider) pinPinata(ctx context.Context, file io.Reader, filename, contentType string, fileSize int64, progress chan<- UploadProgress) (string, error) {
+	fields := [][2]string{{"pinataOptions", `{"cidVersion":1}`}}
+	resp, err := p.postFile(ctx, pinataAPIURL+"/pinning/pinFileToIPFS", "file", file, filename, contentType, fileSize, progress, fields)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError("pin to Pinata", resp)
+	}
+
+	var result struct {
+		IpfsHash string `json:"IpfsHash"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse Pinata response: %w", err)
+	}
+	if result.IpfsHash == "" {
+		return "", &ServerError{Op: "pin to Pinata", Message: "response has no CID"}
+	}
+	return result.IpfsHash, nil
+}
+
+// postFile отправляет файл формой multipart и сообщает о завершении передачи
+func (p *IPFSProvider) postFile(ctx context.Context, target, fieldName string, file io.Reader, filename, fileType string, fileSize int64, progress chan<- UploadProgress, fields [][2]string) (*http.Response, error) {
+	form, contentType, length, err := multipartFileBody(fields, fieldName, filename, fileType, &finalizingReader{reader: file, done: func() {
+		// Файл передан целиком - узел считает CID и закрепляет его
+		if fileSize >= 0 {
+			reportFinalizing(ctx, progress, fileSize)
+		}
+	}}, fileSize)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, form)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = length
+	req.Header.Set("Content-Type", contentType)
+	if p.pinataJWT != "" {
+		req.Header.Set("Authorization", "Bearer "+p.pinataJWT)
+	}
+
+	return httpclient.LongLived().Do(req)
+}
+
+// finalizingReader вызывает done один раз, когда reader дочитан до конца
+type finalizingReader struct {
+	reader io.Reader
+	done   func()
+}
+
+func (r *finalizingReader) Read(b []byte) (int, error) {
+	n, err := r.reader.Read(b)
+	if err == io.EOF && r.done != nil {
+		r.done()
+		r.done = nil
+	}
+	return n, err
+}
+
+// ipfsGatewayURL ссылка на файл через шлюз; filename подсказывает браузеру имя при скачивании
+func ipfsGatewayURL(gateway, cid, filename string) string {
+	return fmt.Sprintf("%s/ipfs/%s?filename=%s", gateway, cid, url.QueryEscape(filename))
+}
+
+// isHTTPURL сообщает, что строка - абсолютный http(s) адрес
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,74 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestIPFSNode проверяет добавление файла на локальный узел и ссылки на шлюзы
+func TestIPFSNode(t *testing.T) {
+	data := []byte("hello ipfs")
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v0/add" || r.URL.Query().Get("pin") != "true" || r.URL.Query().Get("cid-version") != "1" {
+			t.Errorf("request %s, want /api/v0/add with pin and CIDv1", r.URL)
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("FormFile() = %v", err)
+			return
+		}
+		received, _ = io.ReadAll(file)
+		io.WriteString(w, `{"Name":"hello.txt","Hash":"bafytest","Size":"18"}`)
+	}))
+	defer server.Close()
+
+	p := NewIPFSProvider()
+	p.SetSettings(map[string]string{IPFSNode: server.URL + "/", IPFSGateway: "https://gw.example.com/"})
+	if err := p.ValidateSettings(); err != nil {
+		t.Fatalf("ValidateSettings() = %v", err)
+	}
+
+	progress := make(chan UploadProgress, 100)
+	result, err := p.Upload(context.Background(), bytes.NewReader(data), "hello world.txt", int64(len(data)), progress)
+	if err != nil {
+		t.Fatalf("Upload() = %v", err)
+	}
+	if !bytes.Equal(received, data) {
+		t.Errorf("node got %q, want %q", received, data)
+	}
+	if want := "https://gw.example.com/ipfs/bafytest?filename=hello+world.txt"; result.URL != want || result.FileID != "bafytest" {
+		t.Errorf("result = %+v, want URL %s", result, want)
+	}
+
+	close(progress)
+	var last UploadProgress
+	for last = range progress {
+	}
+	if last.Phase != PhaseFinalizing {
+		t.Errorf("last progress phase = %v, want finalizing", last.Phase)
+	}
+}
+
+// TestIPFSValidateSettings проверяет, что без Pinata нужен адрес узла
+func TestIPFSValidateSettings(t *testing.T) {
+	for _, tc := range []struct {
+		values  map[string]string
+		wantErr bool
+	}{
+		{map[string]string{}, false},
+		{map[string]string{IPFSNode: "localhost:5001"}, true},
+		{map[string]string{IPFSNode: "localhost:5001", IPFSPinataJWT: "jwt"}, false},
+		{map[string]string{IPFSGateway: "ipfs.io"}, true},
+	} {
+		p := NewIPFSProvider()
+		p.SetSettings(tc.values)
+		if err := p.ValidateSettings(); (err != nil) != tc.wantErr {
+			t.Errorf("ValidateSettings(%v) = %v, want error %v", tc.values, err, tc.wantErr)
+		}
+	}
+}
@@ -4,32 +4,95 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
 	"time"
 )
 
+// MockConfig поведение мок провайдера: скорость, задержки и ошибки
+type MockConfig struct {
+	// SpeedMBPerSec средняя скорость загрузки в МБ/сек
+	SpeedMBPerSec float64
+	// Latency задержка перед началом передачи (рукопожатие с сервером)
+	Latency time.Duration
+	// Jitter разброс скорости и задержки, доля от 0 до 1
+	Jitter float64
+
+	// FailAtPercent процент, на котором загрузка завершается ошибкой (0 - без ошибки)
+	FailAtPercent int
+	// NetworkErrorRate вероятность обрыва соединения на каждом шаге прогресса
+	NetworkErrorRate float64
+	// RateLimitAttempts сколько первых попыток получают ответ 429
+	RateLimitAttempts int
+	// RetryAfter пауза, которую "сервер" указывает в ответе 429
+	RetryAfter time.Duration
+
+	// PauseEvery и PauseFor периодически останавливают передачу (симуляция зависшего соединения)
+	PauseEvery time.Duration
+	PauseFor   time.Duration
+
+	// Seed начальное значение генератора случайных чисел (0 - случайное)
+	Seed uint64
+}
+
+// MockProfile именованный набор настроек мок провайдера
+type MockProfile struct {
+	Name   string
+	Config MockConfig
+}
+
+// MockProfiles профили для проверки UI очереди, повторов и обработки ошибок
+func MockProfiles() []MockProfile {
+	return []MockProfile{
+		{"Mock Fast", MockConfig{SpeedMBPerSec: 10}},
+		{"Mock Slow", MockConfig{SpeedMBPerSec: 1}},
+		{"Mock Jittery", MockConfig{SpeedMBPerSec: 2, Latency: 2 * time.Second, Jitter: 0.8}},
+		{"Mock Fails at 50%", MockConfig{SpeedMBPerSec: 2, FailAtPercent: 50}},
+		{"Mock Network Errors", MockConfig{SpeedMBPerSec: 2, NetworkErrorRate: 0.02}},
+		{"Mock Rate Limited", MockConfig{SpeedMBPerSec: 2, RateLimitAttempts: 2, RetryAfter: 5 * time.Second}},
+		{"Mock Stalls", MockConfig{SpeedMBPerSec: 2, PauseEvery: 5 * time.Second, PauseFor: 40 * time.Second}},
+	}
+}
+
 // MockProvider симулирует работу реального провайдера для тестирования UI
 type MockProvider struct {
-	name                   string
-	uploadSpeedBytesPerSec int64
-	simulateError          bool
+	name         string
+	config       MockConfig
+	requiresAuth bool
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	attempts int
 }
 
 // NewMockProvider создает новый мок провайдер
 // uploadSpeedMBPerSec - скорость загрузки в МБ/сек (например, 2 для симуляции медленной загрузки)
 func NewMockProvider(name string, uploadSpeedMBPerSec int) *MockProvider {
-	return &MockProvider{
-		name:                   name,
-		uploadSpeedBytesPerSec: int64(uploadSpeedMBPerSec) * 1024 * 1024,
-		simulateError:          false,
-	}
+	m := NewMockProviderWithConfig(name, MockConfig{SpeedMBPerSec: float64(uploadSpeedMBPerSec)})
+	m.requiresAuth = true
+	return m
 }
 
 // NewMockProviderWithError создает мок провайдер который симулирует ошибку
 func NewMockProviderWithError(name string) *MockProvider {
+	m := NewMockProviderWithConfig(name, MockConfig{SpeedMBPerSec: 2, FailAtPercent: 50})
+	m.requiresAuth = true
+	return m
+}
+
+// NewMockProviderWithConfig создает мок провайдер с заданным поведением (API ключ не нужен)
+func NewMockProviderWithConfig(name string, config MockConfig) *MockProvider {
+	seed := config.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
 	return &MockProvider{
-		name:                   name,
-		uploadSpeedBytesPerSec: 2 * 1024 * 1024, // 2 MB/s
-		simulateError:          true,
+		name:   name,
+		config: config,
+		rng:    rand.New(rand.NewPCG(seed, seed)),
 	}
 }
 
@@ -40,59 +103,83 @@ func (m *MockProvider) Name() string {
 
 // Upload симулирует загрузку файла
 func (m *MockProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
+	cfg := m.config
+
+	// Первые попытки получают 429, как от сервера с ограничением частоты запросов
+	m.mu.Lock()
+	m.attempts++
+	limited := m.attempts <= cfg.RateLimitAttempts
+	m.mu.Unlock()
+	if limited {
+		return nil, &QuotaError{
+			Reason:     QuotaRateLimit,
+			RetryAfter: cfg.RetryAfter,
+			Err:        &HTTPError{Op: "upload", Status: http.StatusTooManyRequests},
+		}
+	}
+
+	if cfg.Latency > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ErrCancelled
+		case <-time.After(time.Duration(m.vary(float64(cfg.Latency)))):
+		}
+	}
+
 	// Создаем калькулятор скорости
 	speedCalc := NewSpeedCalculator()
 
-	// Начальное время
-	startTime := time.Now()
-	var totalUploaded int64 = 0
-
 	// Вычисляем сколько байт загружать за каждый интервал обновления
-	bytesPerUpdate := int64(float64(m.uploadSpeedBytesPerSec) * ProgressUpdateInterval.Seconds())
-	if bytesPerUpdate <= 0 {
+	bytesPerUpdate := cfg.SpeedMBPerSec * 1024 * 1024 * ProgressUpdateInterval.Seconds()
+	if bytesPerUpdate < 1024 {
 		bytesPerUpdate = 1024 // минимум 1 KB за обновление
 	}
 
 	ticker := time.NewTicker(ProgressUpdateInterval)
 	defer ticker.Stop()
 
+	var totalUploaded int64
+	resumedAt := time.Now()
+	var pausedUntil time.Time
+
 	for totalUploaded < fileSize {
 		select {
 		case <-ctx.Done():
 			// Загрузка отменена
-			return nil, fmt.Errorf("upload cancelled")
-		case <-ticker.C:
-			// Вычисляем сколько должно быть загружено к текущему моменту
-			elapsed := time.Since(startTime).Seconds()
-			expectedUploaded := int64(float64(m.uploadSpeedBytesPerSec) * elapsed)
-
-			// Не превышаем размер файла
-			if expectedUploaded > fileSize {
-				expectedUploaded = fileSize
+			return nil, ErrCancelled
+		case now := <-ticker.C:
+			// Остановка передачи: прогресс не меняется, как при зависшем соединении
+			if now.Before(pausedUntil) {
+				continue
+			}
+			if cfg.PauseEvery > 0 && now.Sub(resumedAt) >= cfg.PauseEvery {
+				pausedUntil = now.Add(cfg.PauseFor)
+				resumedAt = pausedUntil
+				continue
 			}
 
-			totalUploaded = expectedUploaded
+			if m.chance(cfg.NetworkErrorRate) {
+				return nil, &net.OpError{Op: "write", Net: "tcp", Err: syscall.ECONNRESET}
+			}
 
-			// Вычисляем скорость
-			speed := speedCalc.Update(totalUploaded)
+			totalUploaded += int64(m.vary(bytesPerUpdate))
+			if totalUploaded > fileSize {
+				totalUploaded = fileSize
+			}
 
 			// Вычисляем процент
 			percentage := int((float64(totalUploaded) / float64(fileSize)) * 100)
-			if percentage > 100 {
-				percentage = 100
-			}
 
 			// Отправляем прогресс
 			progress <- UploadProgress{
 				BytesUploaded: totalUploaded,
 				TotalBytes:    fileSize,
-				Speed:         speed,
+				Speed:         speedCalc.Update(totalUploaded),
 				Percentage:    percentage,
 			}
 
-			// Симулируем ошибку на 50%
-			if m.simulateError && percentage >= 50 {
-				return nil, fmt.Errorf("simulated upload error at 50%%")
+			if cfg.FailAtPercent > 0 && percentage >= cfg.FailAtPercent {
+				return nil, fmt.Errorf("simulated upload error at %d%%", cfg.FailAtPercent)
 			}
 		}
 	}
@@ -117,13 +204,36 @@ func (m *MockProvider) Upload(ctx context.Context, file io.ReadSeeker, filename
 	return result, nil
 }
 
-// RequiresAuth возвращает true для тестирования настроек API ключа
+// vary возвращает значение со случайным отклонением в пределах config.Jitter
+func (m *MockProvider) vary(v float64) float64 {
+	if m.config.Jitter <= 0 {
+		return v
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return v * (1 + m.config.Jitter*(2*m.rng.Float64()-1))
+}
+
+// chance возвращает true с вероятностью p
+func (m *MockProvider) chance(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rng.Float64() < p
+}
+
+// RequiresAuth возвращает true для тестирования настроек API ключа (профилям ключ не нужен)
 func (m *MockProvider) RequiresAuth() bool {
-	return true
+	return m.requiresAuth
 }
 
 // ValidateAPIKey симулирует валидацию API ключа
 func (m *MockProvider) ValidateAPIKey(apiKey string) error {
+	if !m.requiresAuth {
+		return nil
+	}
 	if apiKey == "" {
 		return fmt.Errorf("API key is required")
 	}
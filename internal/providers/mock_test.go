@@ -0,0 +1,59 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestMockProviderFailures проверяет симулируемые ошибки мок провайдера
+func TestMockProviderFailures(t *testing.T) {
+	const size = 1024 * 1024
+
+	upload := func(m *MockProvider) error {
+		progress := make(chan UploadProgress, 100)
+		_, err := m.Upload(context.Background(), bytes.NewReader(make([]byte, size)), "file.bin", size, progress)
+		return err
+	}
+
+	t.Run("Rate limit", func(t *testing.T) {
+		m := NewMockProviderWithConfig("Mock", MockConfig{SpeedMBPerSec: 10, RateLimitAttempts: 2, RetryAfter: time.Second})
+		for attempt := 1; attempt <= 2; attempt++ {
+			var quota *QuotaError
+			err := upload(m)
+			if !errors.As(err, &quota) || quota.Reason != QuotaRateLimit || quota.RetryAfter != time.Second {
+				t.Fatalf("attempt %d: Upload() = %v, want rate limit error", attempt, err)
+			}
+		}
+		if err := upload(m); err != nil {
+			t.Errorf("attempt 3: Upload() = %v, want success", err)
+		}
+	})
+
+	t.Run("Fail at percent", func(t *testing.T) {
+		m := NewMockProviderWithConfig("Mock", MockConfig{SpeedMBPerSec: 10, FailAtPercent: 50})
+		if err := upload(m); err == nil {
+			t.Error("Upload() succeeded, want simulated error")
+		}
+	})
+
+	t.Run("Network error", func(t *testing.T) {
+		m := NewMockProviderWithConfig("Mock", MockConfig{SpeedMBPerSec: 10, NetworkErrorRate: 1, Seed: 1})
+		var opErr *net.OpError
+		if err := upload(m); !errors.As(err, &opErr) {
+			t.Errorf("Upload() = %v, want network error", err)
+		}
+	})
+
+	t.Run("Jitter keeps speed in range", func(t *testing.T) {
+		m := NewMockProviderWithConfig("Mock", MockConfig{Jitter: 0.5, Seed: 1})
+		for i := 0; i < 100; i++ {
+			if v := m.vary(100); v < 50 || v > 150 {
+				t.Fatalf("vary(100) = %v, want within [50, 150]", v)
+			}
+		}
+	})
+}
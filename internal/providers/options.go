@@ -0,0 +1,69 @@
+package providers
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"time"
+)
+
+// Options дополнительные настройки провайдера из раздела "Advanced"
+type Options struct {
+	// ChunkSize размер части при multipart загрузке в байтах (0 - выбирает сервер)
+	ChunkSize int64
+
+	// MultipartThreshold размер файла в байтах, начиная с которого загрузка идет частями
+	// (0 - порог провайдера); действует для провайдеров с Capabilities.SinglePart
+	MultipartThreshold int64
+
+	// RetryStalled повторять часть, по которой данные не передаются дольше StallTimeout
+	RetryStalled bool
+
+	// WaitOnline ждет возвращения связи, если часть прервалась из-за обрыва сети
+	// (nil - обрыв считается обычной ошибкой части)
+	WaitOnline WaitOnlineFunc
+
+	// ReadBufferSize буфер чтения файла потоковыми провайдерами в байтах (0 - без буфера)
+	ReadBufferSize int
+}
+
+// WaitOnlineFunc ждет подключения к интернету
+// offline вызывается, если связи нет; возвращает true, если связь пропадала
+type WaitOnlineFunc func(ctx context.Context, offline func()) (bool, error)
+
+// Configurable реализуется провайдерами, поддерживающими Options
+type Configurable interface {
+	SetOptions(opts Options)
+}
+
+// ChunkSizesMB варианты размера части и порога multipart загрузки для настроек (0 - авто)
+var ChunkSizesMB = []int{0, 4, 8, 16, 64}
+
+// ReadBufferSizesKB варианты буфера чтения файла для настроек (0 - без буфера)
+var ReadBufferSizesKB = []int{0, 256, 1024, 4096}
+
+// readBuffer читает файл блоками ReadBufferSize: на многогигабайтных файлах
+// системных вызовов чтения становится в десятки раз меньше, чем при чтении по 4-32 КБ
+// Нулевой копии (sendfile) не бывает: данные шифруются TLS и проходят через multipart
+func (o Options) readBuffer(file io.Reader) io.Reader {
+	if o.ReadBufferSize <= 0 {
+		return file
+	}
+	return bufio.NewReaderSize(file, o.ReadBufferSize)
+}
+
+// partStallTimeout таймаут зависания части при RetryStalled (переопределяется в тестах)
+var partStallTimeout = StallTimeout
+
+// stallTimeout возвращает таймаут зависания части (0 - не отслеживать)
+func (o Options) stallTimeout() time.Duration {
+	if o.RetryStalled {
+		return partStallTimeout
+	}
+	return 0
+}
+
+// partCount возвращает количество частей для файла при заданном размере части
+func partCount(fileSize, chunkSize int64) int {
+	return int((fileSize + chunkSize - 1) / chunkSize)
+}
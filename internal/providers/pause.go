@@ -0,0 +1,25 @@
+package providers
+
+import "context"
+
+// PauseFunc ждет, пока пользователь снимет паузу загрузок (без паузы возвращается сразу)
+// Возвращает ошибку, если ctx отменен раньше
+type PauseFunc func(ctx context.Context) error
+
+// pauseKey ключ PauseFunc в контексте загрузки
+type pauseKey struct{}
+
+// WithPause передает провайдеру ожидание паузы загрузок
+// Загрузки частями останавливаются перед следующей частью, уже начатые части досылаются
+func WithPause(ctx context.Context, wait PauseFunc) context.Context {
+	return context.WithValue(ctx, pauseKey{}, wait)
+}
+
+// waitUnpaused точка паузы: ждет снятия паузы, если она передана в ctx
+func waitUnpaused(ctx context.Context) error {
+	wait, _ := ctx.Value(pauseKey{}).(PauseFunc)
+	if wait == nil {
+		return nil
+	}
+	return wait(ctx)
+}
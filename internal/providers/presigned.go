@@ -0,0 +1,104 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/tracing"
+)
+
+// partLayout разбиение файла на части
+type partLayout struct {
+	ChunkSize  int64
+	TotalParts int
+}
+
+// presignedUpload multipart загрузка по presigned URL частей (S3-совместимое API):
+// инициализация, PUT каждой части с ETag в ответе и завершение списком частей
+// Провайдер задает только запросы к своему API; разбиение, параллельность, повторы и прогресс общие
+type presignedUpload struct {
+	// provider имя провайдера для трассировки
+	provider string
+	// opts настройки провайдера; ChunkSize 0 - размер части, предложенный сервером
+	opts Options
+	// contentType Content-Type частей ("" - не передается)
+	contentType string
+
+	// init начинает загрузку и возвращает разбиение, предложенное сервером
+	init func(ctx context.Context) (partLayout, error)
+	// partURL возвращает URL части; retry - повторная попытка, URL мог истечь
+	partURL func(ctx context.Context, layout partLayout, partNum int, retry bool) (string, error)
+	// complete завершает загрузку списком частей в порядке номеров
+	complete func(ctx context.Context, parts []uploadedPart) (*UploadResult, error)
+}
+
+// run загружает файл: init, части и complete
+func (u *presignedUpload) run(ctx context.Context, file io.ReadSeeker, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
+	initCtx, span := tracing.Start(ctx, "init", tracing.String("provider.name", u.provider))
+	layout, err := u.init(initCtx)
+	span.End(err)
+	if err != nil {
+		return nil, fmt.Errorf("init failed: %w", err)
+	}
+	// Части загружаются по URL с номером, поэтому размер части можно выбрать на клиенте
+	if u.opts.ChunkSize > 0 {
+		layout = partLayout{ChunkSize: u.opts.ChunkSize, TotalParts: partCount(fileSize, u.opts.ChunkSize)}
+	}
+
+	uploader := &chunkUploader{
+		file:       file,
+		fileSize:   fileSize,
+		chunkSize:  layout.ChunkSize,
+		totalParts: layout.TotalParts,
+		progress:   progress,
+		opts:       u.opts,
+		uploadPart: func(ctx context.Context, partNum int, retry bool, body io.Reader, size int64) (string, error) {
+			partURL, err := u.partURL(ctx, layout, partNum, retry)
+			if err != nil {
+				return "", fmt.Errorf("failed to get URL: %w", err)
+			}
+			return putPart(ctx, partURL, u.contentType, body, size)
+		},
+	}
+	parts, err := uploader.run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("upload parts failed: %w", err)
+	}
+
+	reportFinalizing(ctx, progress, fileSize)
+
+	completeCtx, span := tracing.Start(ctx, "complete", tracing.String("provider.name", u.provider))
+	result, err := u.complete(completeCtx, parts)
+	span.End(err)
+	if err != nil {
+		return nil, fmt.Errorf("complete failed: %w", err)
+	}
+	return result, nil
+}
+
+// putPart загружает часть по presigned URL и возвращает ее ETag без кавычек
+func putPart(ctx context.Context, partURL, contentType string, body io.Reader, size int64) (string, error) {
+	req, err := newBodyRequest(ctx, http.MethodPut, partURL, body)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := httpclient.LongLived().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError("upload", resp)
+	}
+	return strings.Trim(resp.Header.Get("ETag"), "\""), nil
+}
@@ -0,0 +1,87 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// TestPresignedUpload проверяет общий поток presigned загрузки: размер части из настроек,
+// свежий URL при повторе, ETag без кавычек и части в порядке номеров
+func TestPresignedUpload(t *testing.T) {
+	noRetryDelay(t)
+
+	data := make([]byte, 10*1024+17)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	var mu sync.Mutex
+	received := make(map[int][]byte)
+	failed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		num, _ := strconv.Atoi(r.URL.Query().Get("part"))
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		// Первая попытка части 2 падает, повтор должен прийти на новый URL
+		if num == 2 && !failed {
+			failed = true
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if num == 2 && r.URL.Query().Get("retry") != "true" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		received[num] = body
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, num))
+	}))
+	defer server.Close()
+
+	var completed []uploadedPart
+	upload := &presignedUpload{
+		provider: "Test",
+		opts:     Options{ChunkSize: 4096},
+		init: func(ctx context.Context) (partLayout, error) {
+			// Разбиение сервера заменяется размером части из настроек
+			return partLayout{ChunkSize: 1024, TotalParts: 11}, nil
+		},
+		partURL: func(ctx context.Context, layout partLayout, partNum int, retry bool) (string, error) {
+			if layout.TotalParts != 3 {
+				return "", fmt.Errorf("layout %+v, want 3 parts", layout)
+			}
+			return fmt.Sprintf("%s/?part=%d&retry=%t", server.URL, partNum, retry), nil
+		},
+		complete: func(ctx context.Context, parts []uploadedPart) (*UploadResult, error) {
+			completed = parts
+			return &UploadResult{URL: "https://example.com/f"}, nil
+		},
+	}
+
+	result, err := upload.run(context.Background(), bytes.NewReader(data), int64(len(data)), make(chan UploadProgress, 100))
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if result.URL != "https://example.com/f" {
+		t.Errorf("URL = %q", result.URL)
+	}
+
+	var assembled []byte
+	for i, part := range completed {
+		if part.Number != i+1 || part.ETag != fmt.Sprintf("etag-%d", i+1) {
+			t.Errorf("parts[%d] = %+v", i, part)
+		}
+		assembled = append(assembled, received[part.Number]...)
+	}
+	if len(completed) != 3 || !bytes.Equal(assembled, data) {
+		t.Errorf("got %d parts, assembled data matches = %v", len(completed), bytes.Equal(assembled, data))
+	}
+}
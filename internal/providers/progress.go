@@ -1,10 +1,32 @@
 package providers
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"time"
 )
 
+const (
+	// StallTimeout время без передачи данных, после которого загрузка считается зависшей
+	StallTimeout = 30 * time.Second
+
+	// etaSmoothing постоянная времени экспоненциального сглаживания скорости для ETA
+	etaSmoothing = 10 * time.Second
+)
+
+// UploadPhase этап загрузки
+type UploadPhase int
+
+const (
+	// PhaseUploading передача данных файла
+	PhaseUploading UploadPhase = iota
+	// PhaseFinalizing данные отправлены, сервер собирает файл и готовит ссылку
+	PhaseFinalizing
+	// PhaseOffline связь пропала, загрузка продолжится после ее возвращения
+	PhaseOffline
+)
+
 // UploadProgress содержит информацию о прогрессе загрузки
 type UploadProgress struct {
 	// BytesUploaded количество загруженных байт
@@ -18,6 +40,63 @@ type UploadProgress struct {
 
 	// Percentage процент выполнения (0-100)
 	Percentage int
+
+	// Phase текущий этап загрузки
+	Phase UploadPhase
+
+	// Parts состояние частей при загрузке частями (nil - файл отправляется одним запросом)
+	// Каждое обновление несет свою копию, ее можно хранить без блокировок
+	Parts []PartStatus
+}
+
+// PartState состояние одной части при загрузке частями
+type PartState int
+
+const (
+	// PartPending часть ждет своей очереди
+	PartPending PartState = iota
+	// PartUploading часть передается
+	PartUploading
+	// PartRetrying попытка не удалась, часть будет отправлена снова
+	PartRetrying
+	// PartDone часть загружена
+	PartDone
+	// PartFailed попытки закончились или ошибку повтор не исправит
+	PartFailed
+)
+
+// PartStatus состояние одной части файла
+type PartStatus struct {
+	Number   int
+	Size     int64
+	Sent     int64 // отправлено байт в текущей попытке
+	Attempts int   // сколько попыток начато
+	State    PartState
+	// Err ошибка последней неудачной попытки ("" - ошибок не было)
+	Err string
+}
+
+// reportFinalizing сообщает о переходе к завершению загрузки
+// Отправка блокирующая, чтобы UI гарантированно получил смену этапа
+func reportFinalizing(ctx context.Context, progress chan<- UploadProgress, fileSize int64) {
+	select {
+	case progress <- UploadProgress{
+		BytesUploaded: fileSize,
+		TotalBytes:    fileSize,
+		Percentage:    100,
+		Phase:         PhaseFinalizing,
+	}:
+	case <-ctx.Done():
+	}
+}
+
+// percentOf процент отправленного (0, если размер неизвестен)
+func percentOf(sent, total int64) int {
+	if total <= 0 {
+		return 0
+	}
+	// float64 не переполняется на файлах больше 4 ГБ, в отличие от sent*100 в int на 32-битных системах
+	return min(max(int(float64(sent)/float64(total)*100), 0), 100)
 }
 
 // SpeedCalculator отслеживает и вычисляет скорость загрузки
@@ -80,6 +159,67 @@ func (s *SpeedCalculator) Reset() {
 	s.smoothingWindow = s.smoothingWindow[:0]
 }
 
+// TransferMonitor сглаживает скорость экспоненциальным средним по всей передаче
+// и определяет зависание, когда байты долго не двигаются
+type TransferMonitor struct {
+	speed     float64
+	lastBytes int64
+	lastMove  time.Time
+	started   bool
+}
+
+// NewTransferMonitor создает монитор передачи
+func NewTransferMonitor() *TransferMonitor {
+	return &TransferMonitor{}
+}
+
+// Observe учитывает текущее количество переданных байт
+// Скорость пересчитывается только при движении данных; вес нового замера
+// зависит от прошедшего времени, поэтому частота вызовов не влияет на результат
+func (m *TransferMonitor) Observe(bytesUploaded int64, now time.Time) {
+	if !m.started {
+		m.started = true
+		m.lastBytes = bytesUploaded
+		m.lastMove = now
+		return
+	}
+
+	if bytesUploaded < m.lastBytes {
+		// Откат неудачной попытки части, скорость не трогаем
+		m.lastBytes = bytesUploaded
+		return
+	}
+	if bytesUploaded == m.lastBytes {
+		return
+	}
+
+	elapsed := now.Sub(m.lastMove)
+	if elapsed <= 0 {
+		return
+	}
+
+	current := float64(bytesUploaded-m.lastBytes) / elapsed.Seconds()
+	if m.speed == 0 {
+		m.speed = current
+	} else {
+		alpha := 1 - math.Exp(-elapsed.Seconds()/etaSmoothing.Seconds())
+		m.speed += alpha * (current - m.speed)
+	}
+
+	m.lastBytes = bytesUploaded
+	m.lastMove = now
+}
+
+// Speed возвращает сглаженную скорость в байтах/сек (0 - пока неизвестна)
+func (m *TransferMonitor) Speed() float64 {
+	return m.speed
+}
+
+// Stalled сообщает, что данные не передавались дольше StallTimeout
+func (m *TransferMonitor) Stalled(now time.Time) bool {
+	return m.started && now.Sub(m.lastMove) >= StallTimeout
+}
+
 // FormatSpeed форматирует скорость для отображения
 func FormatSpeed(bytesPerSec float64) string {
 	if bytesPerSec < 1024 {
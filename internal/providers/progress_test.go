@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"testing"
 	"time"
 )
@@ -177,3 +178,85 @@ func TestUploadProgress(t *testing.T) {
 		t.Errorf("Percentage = %d, want 50", progress.Percentage)
 	}
 }
+
+// TestLargeFileMath проверяет процент и число частей для файлов больше 4 ГБ
+func TestLargeFileMath(t *testing.T) {
+	const gb = int64(1) << 30
+	for _, tc := range []struct {
+		sent, total int64
+		want        int
+	}{
+		{3 * gb, 6 * gb, 50},
+		{100 * gb, 400 * gb, 25},
+		{5*gb - 1, 5 * gb, 99},
+		{7 * gb, 5 * gb, 100},
+		{-1, 5 * gb, 0},
+		{gb, 0, 0},
+	} {
+		if got := percentOf(tc.sent, tc.total); got != tc.want {
+			t.Errorf("percentOf(%d, %d) = %d, want %d", tc.sent, tc.total, got, tc.want)
+		}
+	}
+
+	if got := partCount(5*gb+1, 64<<20); got != 81 {
+		t.Errorf("partCount(5 GB + 1, 64 MB) = %d, want 81", got)
+	}
+	if got := partCount(1<<40, 8<<20); got != 131072 {
+		t.Errorf("partCount(1 TB, 8 MB) = %d, want 131072", got)
+	}
+}
+
+// TestReportFinalizing проверяет сообщение о завершающем этапе
+func TestReportFinalizing(t *testing.T) {
+	progress := make(chan UploadProgress, 1)
+	reportFinalizing(context.Background(), progress, 1024)
+
+	p := <-progress
+	if p.Phase != PhaseFinalizing || p.Percentage != 100 || p.BytesUploaded != 1024 {
+		t.Errorf("reportFinalizing() sent %+v", p)
+	}
+
+	// Отмененный контекст не блокирует отправку в заполненный канал
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	progress <- UploadProgress{}
+	reportFinalizing(ctx, progress, 1024)
+}
+
+// TestTransferMonitor проверяет сглаживание скорости и определение зависания
+func TestTransferMonitor(t *testing.T) {
+	start := time.Unix(0, 0)
+	m := NewTransferMonitor()
+
+	m.Observe(0, start)
+	if m.Speed() != 0 {
+		t.Errorf("Speed() before data = %f, want 0", m.Speed())
+	}
+
+	// Первый замер задает скорость как есть
+	m.Observe(1000, start.Add(time.Second))
+	if m.Speed() != 1000 {
+		t.Errorf("Speed() = %f, want 1000", m.Speed())
+	}
+
+	// Кратковременный всплеск сдвигает скорость лишь частично
+	m.Observe(11000, start.Add(2*time.Second))
+	if s := m.Speed(); s <= 1000 || s >= 10000 {
+		t.Errorf("Speed() after spike = %f, want between 1000 and 10000", s)
+	}
+
+	// Повтор того же значения и откат не меняют скорость
+	before := m.Speed()
+	m.Observe(11000, start.Add(3*time.Second))
+	m.Observe(5000, start.Add(3*time.Second))
+	if m.Speed() != before {
+		t.Errorf("Speed() changed without progress: %f -> %f", before, m.Speed())
+	}
+
+	if m.Stalled(start.Add(2*time.Second + StallTimeout - time.Millisecond)) {
+		t.Error("Stalled() = true before timeout")
+	}
+	if !m.Stalled(start.Add(2*time.Second + StallTimeout)) {
+		t.Error("Stalled() = false after timeout")
+	}
+}
@@ -41,4 +41,7 @@ type UploadResult struct {
 
 	// Message дополнительное сообщение от провайдера
 	Message string
+
+	// MD5 хеш файла в hex, если провайдер его вернул (для проверки целостности)
+	MD5 string
 }
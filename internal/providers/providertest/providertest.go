@@ -0,0 +1,263 @@
+// Package providertest содержит общий набор проверок, которые должен проходить каждый провайдер
+//
+// Использование в тестах провайдера:
+//
+//	func TestConformance(t *testing.T) {
+//		providertest.Run(t, providertest.Config{
+//			New: func() providers.Provider { return providers.NewYourProvider("test-key") },
+//		})
+//	}
+//
+// HTTP запросы провайдера получают ответы из Config.Handler (по умолчанию providers.DryRunHandler)
+// через httpclient.EnableDryRun, поэтому тесты не обращаются к настоящим сервисам
+// Проверки меняют глобальное состояние httpclient - не запускайте их параллельно
+package providertest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/providers"
+)
+
+const (
+	// defaultFileSize размер тестового файла по умолчанию
+	defaultFileSize = 1 << 20
+	// uploadTimeout сколько ждать завершения загрузки, прежде чем считать провайдер зависшим
+	uploadTimeout = 30 * time.Second
+)
+
+// Config описывает проверяемый провайдер
+type Config struct {
+	// New создает провайдер (каждая проверка получает новый экземпляр)
+	New func() providers.Provider
+	// FileSize размер тестового файла (0 - 1 МБ)
+	FileSize int64
+	// Handler отвечает на HTTP запросы провайдера (nil - providers.DryRunHandler)
+	Handler http.Handler
+	// Offline провайдер не делает HTTP запросов (мок) - проверка ошибок сервера пропускается
+	Offline bool
+}
+
+// Run запускает все проверки
+func Run(t *testing.T, cfg Config) {
+	t.Helper()
+
+	if cfg.FileSize == 0 {
+		cfg.FileSize = defaultFileSize
+	}
+	if cfg.Handler == nil {
+		cfg.Handler = providers.DryRunHandler()
+	}
+
+	t.Run("Upload", func(t *testing.T) { testUpload(t, cfg) })
+	t.Run("Cancelled before start", func(t *testing.T) { testCancelledBeforeStart(t, cfg) })
+	t.Run("Cancelled during upload", func(t *testing.T) { testCancelledDuringUpload(t, cfg) })
+	if !cfg.Offline {
+		t.Run("Server errors are typed", func(t *testing.T) { testServerError(t, cfg) })
+	}
+	if !providers.NeedsSeek(cfg.New()) {
+		t.Run("Stream of unknown size", func(t *testing.T) { testStream(t, cfg) })
+	}
+}
+
+// testStream проверяет, что потоковый провайдер загружает io.Reader без Seek и без известного размера
+func testStream(t *testing.T, cfg Config) {
+	useHandler(t, cfg.Handler)
+
+	data := &countingReader{reader: bytes.NewReader(make([]byte, cfg.FileSize))}
+	progress := make(chan providers.UploadProgress, 100)
+	go func() {
+		for range progress {
+		}
+	}()
+	defer close(progress)
+
+	stream := cfg.New().(providers.StreamUploader)
+	result, err := stream.UploadStream(context.Background(), data, "conformance.bin", -1, progress)
+	if err != nil {
+		t.Fatalf("UploadStream() error = %v", err)
+	}
+	if result == nil || result.URL == "" {
+		t.Fatalf("UploadStream() result = %+v, want non-empty URL", result)
+	}
+	if data.n != cfg.FileSize {
+		t.Errorf("UploadStream() read %d bytes, want %d", data.n, cfg.FileSize)
+	}
+}
+
+// countingReader считает прочитанные байты и не поддерживает Seek
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// testUpload проверяет успешную загрузку: ссылка не пустая, прогресс не убывает и не превышает размер файла
+func testUpload(t *testing.T, cfg Config) {
+	useHandler(t, cfg.Handler)
+
+	result, updates, err := upload(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if result == nil || result.URL == "" {
+		t.Fatalf("Upload() result = %+v, want non-empty URL", result)
+	}
+
+	var last int64
+	for i, p := range updates {
+		if p.BytesUploaded < last {
+			t.Errorf("progress %d went back: %d after %d bytes", i, p.BytesUploaded, last)
+		}
+		if p.BytesUploaded > cfg.FileSize {
+			t.Errorf("progress %d reports %d bytes of %d", i, p.BytesUploaded, cfg.FileSize)
+		}
+		if p.TotalBytes != 0 && p.TotalBytes != cfg.FileSize {
+			t.Errorf("progress %d TotalBytes = %d, want %d", i, p.TotalBytes, cfg.FileSize)
+		}
+		if p.Percentage < 0 || p.Percentage > 100 {
+			t.Errorf("progress %d Percentage = %d", i, p.Percentage)
+		}
+		last = p.BytesUploaded
+	}
+}
+
+// testCancelledBeforeStart проверяет, что загрузка с отмененным контекстом не начинается
+func testCancelledBeforeStart(t *testing.T, cfg Config) {
+	useHandler(t, cfg.Handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := upload(ctx, cfg, nil)
+	checkCancelled(t, err)
+}
+
+// testCancelledDuringUpload отменяет загрузку, когда прочитана половина файла
+// или пришло первое обновление прогресса (провайдеры-моки файл не читают)
+func testCancelledDuringUpload(t *testing.T, cfg Config) {
+	useHandler(t, cfg.Handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _, err := upload(ctx, cfg, cancel)
+	checkCancelled(t, err)
+}
+
+// testServerError проверяет, что ошибка сервера доходит до вызывающего как типизированная ошибка провайдера
+func testServerError(t *testing.T, cfg Config) {
+	useHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		io.WriteString(w, `{"status":401,"msg":"Invalid key","success":false,"error":"Invalid key"}`)
+	}))
+
+	_, _, err := upload(context.Background(), cfg, nil)
+	if err == nil {
+		t.Fatal("Upload() succeeded, want error")
+	}
+
+	var authErr *providers.AuthError
+	var httpErr *providers.HTTPError
+	var serverErr *providers.ServerError
+	if !errors.As(err, &authErr) && !errors.As(err, &httpErr) && !errors.As(err, &serverErr) {
+		t.Errorf("Upload() error = %v (%T), want AuthError, HTTPError or ServerError in the chain", err, err)
+	}
+}
+
+// upload загружает тестовый файл и собирает обновления прогресса
+// cancelMidway вызывается на середине чтения файла или на первом обновлении прогресса
+func upload(ctx context.Context, cfg Config, cancelMidway context.CancelFunc) (*providers.UploadResult, []providers.UploadProgress, error) {
+	var once sync.Once
+	trigger := func() {
+		if cancelMidway != nil {
+			once.Do(cancelMidway)
+		}
+	}
+
+	var file io.ReadSeeker = bytes.NewReader(make([]byte, cfg.FileSize))
+	file = &triggerReader{ReadSeeker: file, at: cfg.FileSize / 2, trigger: trigger}
+
+	progress := make(chan providers.UploadProgress, 100)
+	var updates []providers.UploadProgress
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for p := range progress {
+			updates = append(updates, p)
+			trigger()
+		}
+	}()
+
+	type outcome struct {
+		result *providers.UploadResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := cfg.New().Upload(ctx, file, "conformance.bin", cfg.FileSize, progress)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case o := <-done:
+		close(progress)
+		<-collected
+		return o.result, updates, o.err
+	case <-time.After(uploadTimeout):
+		return nil, nil, errors.New("upload did not return in time")
+	}
+}
+
+// checkCancelled проверяет, что отмена вернула ошибку отмены
+func checkCancelled(t *testing.T, err error) {
+	t.Helper()
+	if !errors.Is(err, providers.ErrCancelled) && !errors.Is(err, context.Canceled) {
+		t.Errorf("Upload() error = %v, want providers.ErrCancelled or context.Canceled", err)
+	}
+}
+
+// useHandler направляет HTTP запросы провайдера в handler на время теста
+func useHandler(t *testing.T, handler http.Handler) {
+	httpclient.EnableDryRun(handler)
+	t.Cleanup(httpclient.DisableDryRun)
+}
+
+// triggerReader вызывает trigger, когда прочитано at байт
+type triggerReader struct {
+	io.ReadSeeker
+	at      int64
+	read    int64
+	trigger func()
+}
+
+func (r *triggerReader) Read(p []byte) (int, error) {
+	n, err := r.ReadSeeker.Read(p)
+	r.read += int64(n)
+	if r.read >= r.at {
+		r.trigger()
+	}
+	return n, err
+}
+
+func (r *triggerReader) Seek(offset int64, whence int) (int64, error) {
+	pos, err := r.ReadSeeker.Seek(offset, whence)
+	if err == nil {
+		r.read = pos
+	}
+	return pos, err
+}
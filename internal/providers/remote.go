@@ -0,0 +1,12 @@
+package providers
+
+import "context"
+
+// remoteUploadMessage сообщение для результата remote upload: файл появится после скачивания сервером
+const remoteUploadMessage = "The provider is downloading the file in the background. The link will work once the transfer finishes."
+
+// RemoteUploader реализуется провайдерами, которые умеют сами скачать файл по ссылке
+// ("remote upload"): данные идут напрямую от источника к хостингу, минуя пользователя
+type RemoteUploader interface {
+	UploadRemote(ctx context.Context, url string) (*UploadResult, error)
+}
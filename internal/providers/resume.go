@@ -0,0 +1,42 @@
+package providers
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInterrupted причина отмены загрузки при выходе из приложения
+// В отличие от отмены пользователем, незавершенная загрузка остается на сервере,
+// чтобы продолжить ее при следующем запуске
+var ErrInterrupted = errors.New("upload interrupted by quit")
+
+// Checkpoint точка, с которой загрузку можно продолжить после перезапуска приложения
+type Checkpoint struct {
+	// Upload адрес или идентификатор незавершенной загрузки на сервере
+	Upload string
+	// Offset сколько байт сервер подтвердил
+	Offset int64
+}
+
+// Checkpoints хранит точку продолжения одной загрузки
+// Провайдеры, умеющие продолжать загрузку (tus), берут из нее начальную точку и сообщают новые
+type Checkpoints interface {
+	// Load возвращает последнюю точку (nil - начать заново)
+	Load() *Checkpoint
+	// Save запоминает подтвержденную сервером точку
+	Save(c Checkpoint)
+}
+
+// checkpointsKey ключ Checkpoints в контексте загрузки
+type checkpointsKey struct{}
+
+// WithCheckpoints передает провайдеру хранилище точек продолжения загрузки
+func WithCheckpoints(ctx context.Context, c Checkpoints) context.Context {
+	return context.WithValue(ctx, checkpointsKey{}, c)
+}
+
+// CheckpointsFrom возвращает хранилище точек загрузки (nil - загрузка не продолжается после перезапуска)
+func CheckpointsFrom(ctx context.Context) Checkpoints {
+	c, _ := ctx.Value(checkpointsKey{}).(Checkpoints)
+	return c
+}
@@ -2,12 +2,13 @@ package providers
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
+	"sync"
 
 	"multiUploader/internal/httpclient"
 )
@@ -20,6 +21,9 @@ const (
 // RootzProvider провайдер для Rootz.so
 type RootzProvider struct {
 	apiKey string
+
+	// opts настройки "Advanced"; MultipartThreshold 0 - rootzMultipartThreshold
+	opts Options
 }
 
 // NewRootzProvider создает новый провайдер Rootz.so
@@ -27,6 +31,11 @@ func NewRootzProvider(apiKey string) *RootzProvider {
 	return &RootzProvider{apiKey: apiKey}
 }
 
+// api клиент запросов к API Rootz с авторизацией ключом
+func (r *RootzProvider) api() *httpclient.Client {
+	return httpclient.Default().With(httpclient.BearerAuth(r.apiKey))
+}
+
 func (r *RootzProvider) Name() string {
 	return "Rootz"
 }
@@ -35,6 +44,19 @@ func (r *RootzProvider) RequiresAuth() bool {
 	return true
 }
 
+// Probe проверяет доступность сервера Rootz
+func (r *RootzProvider) Probe(ctx context.Context) error {
+	return probeURL(ctx, rootzBaseURL)
+}
+
+func (r *RootzProvider) Capabilities() Capabilities {
+	return Capabilities{MaxFileSize: NoSizeLimit, Multipart: true, SinglePart: true}
+}
+
+func (r *RootzProvider) SetOptions(opts Options) {
+	r.opts = opts
+}
+
 func (r *RootzProvider) ValidateAPIKey(apiKey string) error {
 	if apiKey == "" {
 		return fmt.Errorf("API key is required")
@@ -45,13 +67,13 @@ func (r *RootzProvider) ValidateAPIKey(apiKey string) error {
 // Upload загружает файл на Rootz.so
 func (r *RootzProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
 	// Выбираем метод загрузки в зависимости от размера файла
-	if fileSize < rootzMultipartThreshold {
+	if fileSize < cmp.Or(r.opts.MultipartThreshold, rootzMultipartThreshold) {
 		return r.uploadSmallFile(ctx, file, filename, fileSize, progress)
 	}
 	return r.uploadLargeFile(ctx, file, filename, fileSize, progress)
 }
 
-// uploadSmallFile загружает маленький файл (<4MB) напрямую
+// uploadSmallFile загружает маленький файл (меньше порога multipart) напрямую
 func (r *RootzProvider) uploadSmallFile(ctx context.Context, file io.Reader, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
 	// Читаем весь файл в память (он маленький)
 	fileData, err := io.ReadAll(file)
@@ -78,19 +100,16 @@ func (r *RootzProvider) uploadSmallFile(ctx context.Context, file io.Reader, fil
 	}
 
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	if r.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+r.apiKey)
-	}
 
 	// Отправляем запрос
-	resp, err := httpclient.Default().Do(req)
+	resp, err := r.api().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("upload failed with status %d", resp.StatusCode)
+		return nil, statusError("upload", resp)
 	}
 
 	// Парсим ответ
@@ -107,7 +126,7 @@ func (r *RootzProvider) uploadSmallFile(ctx context.Context, file io.Reader, fil
 	}
 
 	if !result.Success {
-		return nil, fmt.Errorf("upload failed: %s", result.Error)
+		return nil, &ServerError{Op: "upload", Message: result.Error}
 	}
 
 	// Отправляем финальный прогресс
@@ -126,64 +145,67 @@ func (r *RootzProvider) uploadSmallFile(ctx context.Context, file io.Reader, fil
 
 // uploadLargeFile загружает большой файл (≥4MB) через multipart upload
 func (r *RootzProvider) uploadLargeFile(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
-	// 1. Инициализация multipart upload
-	initReq := map[string]interface{}{
-		"fileName": filename,
-		"fileSize": fileSize,
-		"fileType": "application/octet-stream",
-	}
-
-	initResp, err := r.makeJSONRequest(ctx, http.MethodPost, "/api/files/multipart/init", initReq)
-	if err != nil {
-		return nil, fmt.Errorf("init failed: %w", err)
-	}
-
-	uploadID := initResp["uploadId"].(string)
-	key := initResp["key"].(string)
-	serverChunkSize := int64(initResp["chunkSize"].(float64))
-	totalParts := int(initResp["totalParts"].(float64))
-
-	// 2. Получаем presigned URLs для всех частей
-	urlsReq := map[string]interface{}{
-		"key":        key,
-		"uploadId":   uploadID,
-		"totalParts": totalParts,
-	}
-
-	urlsResp, err := r.makeJSONRequestNoAuth(ctx, http.MethodPost, "/api/files/multipart/batch-urls", urlsReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get URLs: %w", err)
+	contentType := fileContentType(file, filename)
+	var key, uploadID string
+	var partURLs *rootzPartURLs
+
+	upload := &presignedUpload{
+		provider: r.Name(),
+		opts:     r.opts,
+		init: func(ctx context.Context) (partLayout, error) {
+			initResp, err := r.makeJSONRequest(ctx, http.MethodPost, "/api/files/multipart/init", map[string]interface{}{
+				"fileName": filename,
+				"fileSize": fileSize,
+				"fileType": contentType,
+			})
+			if err != nil {
+				return partLayout{}, err
+			}
+			uploadID = initResp["uploadId"].(string)
+			key = initResp["key"].(string)
+			partURLs = &rootzPartURLs{refresh: func(ctx context.Context, totalParts int) (map[string]interface{}, error) {
+				return r.getPartURLs(ctx, map[string]interface{}{
+					"key":        key,
+					"uploadId":   uploadID,
+					"totalParts": totalParts,
+				})
+			}}
+			return partLayout{
+				ChunkSize:  int64(initResp["chunkSize"].(float64)),
+				TotalParts: int(initResp["totalParts"].(float64)),
+			}, nil
+		},
+		// Presigned URLs всех частей запрашиваются одним запросом, когда число частей уже выбрано
+		partURL: func(ctx context.Context, layout partLayout, partNum int, retry bool) (string, error) {
+			return partURLs.get(ctx, layout.TotalParts, partNum, retry)
+		},
+		complete: func(ctx context.Context, parts []uploadedPart) (*UploadResult, error) {
+			return r.completeUpload(ctx, key, uploadID, filename, fileSize, contentType, parts)
+		},
 	}
+	return upload.run(ctx, file, fileSize, progress)
+}
 
-	if !urlsResp["success"].(bool) {
-		errMsg := "unknown error"
-		if e, ok := urlsResp["error"].(string); ok {
-			errMsg = e
+// completeUpload завершает multipart upload и возвращает ссылку на файл
+func (r *RootzProvider) completeUpload(ctx context.Context, key, uploadID, filename string, fileSize int64, contentType string, parts []uploadedPart) (*UploadResult, error) {
+	partList := make([]map[string]interface{}, len(parts))
+	for i, part := range parts {
+		partList[i] = map[string]interface{}{
+			"partNumber": part.Number,
+			"etag":       part.ETag,
 		}
-		return nil, fmt.Errorf("failed to get URLs: %s", errMsg)
 	}
 
-	urls := urlsResp["urls"].(map[string]interface{})
-
-	// 3. Загружаем части
-	uploadedParts, err := r.uploadParts(ctx, file, fileSize, serverChunkSize, totalParts, urls, progress)
-	if err != nil {
-		return nil, fmt.Errorf("upload parts failed: %w", err)
-	}
-
-	// 4. Завершаем upload
-	completeReq := map[string]interface{}{
+	completeResp, err := r.makeJSONRequest(ctx, http.MethodPost, "/api/files/multipart/complete", map[string]interface{}{
 		"key":         key,
 		"uploadId":    uploadID,
-		"parts":       uploadedParts,
+		"parts":       partList,
 		"fileName":    filename,
 		"fileSize":    fileSize,
-		"contentType": "application/octet-stream",
-	}
-
-	completeResp, err := r.makeJSONRequest(ctx, http.MethodPost, "/api/files/multipart/complete", completeReq)
+		"contentType": contentType,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("complete failed: %w", err)
+		return nil, err
 	}
 
 	if !completeResp["success"].(bool) {
@@ -191,7 +213,7 @@ func (r *RootzProvider) uploadLargeFile(ctx context.Context, file io.ReadSeeker,
 		if e, ok := completeResp["error"].(string); ok {
 			errMsg = e
 		}
-		return nil, fmt.Errorf("complete failed: %s", errMsg)
+		return nil, &ServerError{Op: "complete", Message: errMsg}
 	}
 
 	fileData := completeResp["file"].(map[string]interface{})
@@ -203,108 +225,50 @@ func (r *RootzProvider) uploadLargeFile(ctx context.Context, file io.ReadSeeker,
 	}, nil
 }
 
-// uploadParts загружает части файла используя Seek для эффективной работы с большими файлами
-func (r *RootzProvider) uploadParts(ctx context.Context, file io.ReadSeeker, fileSize int64, chunkSize int64, totalParts int, urls map[string]interface{}, progress chan<- UploadProgress) ([]map[string]interface{}, error) {
-	speedCalc := NewSpeedCalculator()
-	uploadedParts := make([]map[string]interface{}, totalParts)
-	var totalUploaded int64
-
-	// Загружаем части последовательно
-	for partNum := 1; partNum <= totalParts; partNum++ {
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("upload cancelled")
-		default:
-		}
-
-		// Вычисляем границы части
-		start := int64(partNum-1) * chunkSize
-		partSize := chunkSize
-		if start+partSize > fileSize {
-			partSize = fileSize - start
-		}
-
-		// Перемещаемся к началу части
-		_, err := file.Seek(start, io.SeekStart)
-		if err != nil {
-			return nil, fmt.Errorf("failed to seek to part %d: %w", partNum, err)
-		}
-
-		// Создаем LimitReader для чтения только текущего чанка
-		limitedReader := io.LimitReader(file, partSize)
-
-		url := urls[fmt.Sprintf("%d", partNum)].(string)
-
-		// Загружаем часть с отслеживанием прогресса
-		etag, err := r.uploadPartWithProgress(ctx, url, limitedReader, partSize, &totalUploaded, fileSize, speedCalc, progress)
-		if err != nil {
-			return nil, fmt.Errorf("failed to upload part %d: %w", partNum, err)
-		}
+// getPartURLs запрашивает presigned URLs для всех частей
+func (r *RootzProvider) getPartURLs(ctx context.Context, urlsReq map[string]interface{}) (map[string]interface{}, error) {
+	urlsResp, err := r.makeJSONRequestNoAuth(ctx, http.MethodPost, "/api/files/multipart/batch-urls", urlsReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get URLs: %w", err)
+	}
 
-		// Сохраняем информацию о части
-		uploadedParts[partNum-1] = map[string]interface{}{
-			"partNumber": partNum,
-			"etag":       etag,
+	if !urlsResp["success"].(bool) {
+		errMsg := "unknown error"
+		if e, ok := urlsResp["error"].(string); ok {
+			errMsg = e
 		}
+		return nil, &ServerError{Op: "get URLs", Message: errMsg}
 	}
 
-	return uploadedParts, nil
+	return urlsResp["urls"].(map[string]interface{}), nil
 }
 
-// uploadPartWithProgress загружает часть файла с отслеживанием прогресса в реальном времени
-func (r *RootzProvider) uploadPartWithProgress(ctx context.Context, url string, reader io.Reader, partSize int64, totalUploaded *int64, fileSize int64, speedCalc *SpeedCalculator, progress chan<- UploadProgress) (string, error) {
-	// Создаем reader с отслеживанием прогресса
-	// Обновляем прогресс каждые 512KB для плавного отображения
-	const progressChunkSize = 512 * 1024 // 512KB
-	var lastProgressUpdate int64
-
-	progressReader := &progressReader{
-		reader: reader,
-		onProgress: func(n int64) {
-			*totalUploaded += n
-
-			// Обновляем прогресс не чаще чем каждые 512KB
-			if *totalUploaded-lastProgressUpdate >= progressChunkSize || *totalUploaded == fileSize {
-				lastProgressUpdate = *totalUploaded
-				speed := speedCalc.Update(*totalUploaded)
-				percentage := int(float64(*totalUploaded) / float64(fileSize) * 100)
-
-				select {
-				case progress <- UploadProgress{
-					BytesUploaded: *totalUploaded,
-					TotalBytes:    fileSize,
-					Speed:         speed,
-					Percentage:    percentage,
-				}:
-				default:
-					// Канал прогресса заполнен, пропускаем обновление
-				}
-			}
-		},
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, progressReader)
-	if err != nil {
-		return "", err
-	}
+// rootzPartURLs presigned URLs частей: запрашиваются для первой части, когда число частей уже выбрано,
+// и заново при повторной попытке (URL мог истечь, пока загружались предыдущие части)
+type rootzPartURLs struct {
+	mu      sync.Mutex
+	urls    map[string]interface{}
+	refresh func(ctx context.Context, totalParts int) (map[string]interface{}, error)
+}
 
-	req.ContentLength = partSize
+// get возвращает URL части; при первом вызове и при retry сначала запрашивает свежие URLs
+func (p *rootzPartURLs) get(ctx context.Context, totalParts, partNum int, retry bool) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	resp, err := httpclient.LongLived().Do(req)
-	if err != nil {
-		return "", err
+	if retry || p.urls == nil {
+		urls, err := p.refresh(ctx, totalParts)
+		if err != nil {
+			return "", err
+		}
+		p.urls = urls
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("upload failed with status %d", resp.StatusCode)
+	url, ok := p.urls[fmt.Sprintf("%d", partNum)].(string)
+	if !ok {
+		return "", fmt.Errorf("no upload URL for part %d", partNum)
 	}
-
-	// Получаем ETag и убираем кавычки
-	etag := resp.Header.Get("ETag")
-	etag = strings.Trim(etag, "\"")
-
-	return etag, nil
+	return url, nil
 }
 
 // progressReader оборачивает io.Reader и вызывает callback при каждом чтении
@@ -334,18 +298,15 @@ func (r *RootzProvider) makeJSONRequest(ctx context.Context, method, path string
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	if r.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+r.apiKey)
-	}
 
-	resp, err := httpclient.Default().Do(req)
+	resp, err := r.api().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+		return nil, statusError("request", resp)
 	}
 
 	var result map[string]interface{}
@@ -377,7 +338,7 @@ func (r *RootzProvider) makeJSONRequestNoAuth(ctx context.Context, method, path
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+		return nil, statusError("request", resp)
 	}
 
 	var result map[string]interface{}
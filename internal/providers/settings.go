@@ -0,0 +1,33 @@
+package providers
+
+// SettingField дополнительное поле настроек провайдера (адрес сервера, ID чата и т.п.)
+type SettingField struct {
+	// Key ключ значения в конфиге
+	Key string
+	// Label подпись поля (переводится в UI)
+	Label string
+	// Placeholder подсказка в пустом поле (переводится в UI)
+	Placeholder string
+	// Secret значение скрывается при вводе (токены, пароли)
+	Secret bool
+	// Multiline многострочное значение (например, пары "ключ=значение")
+	Multiline bool
+}
+
+// SettingsProvider реализуется провайдерами с собственными полями настроек
+// Значения хранятся в конфиге провайдера и передаются через SetSettings при каждом создании
+type SettingsProvider interface {
+	// SettingFields возвращает поля настроек в порядке показа
+	SettingFields() []SettingField
+	// SetSettings задает значения полей (отсутствующие ключи - пустые значения)
+	SetSettings(values map[string]string)
+	// ValidateSettings проверяет, что значений достаточно для загрузки
+	ValidateSettings() error
+}
+
+// SelfHosted реализуется провайдерами, которые загружают на сервер пользователя (tus, свой узел IPFS)
+// Для такого сервера в настройках можно отключить проверку TLS сертификата
+type SelfHosted interface {
+	// ServerURL адрес сервера из настроек ("" - загрузка идет не на свой сервер)
+	ServerURL() string
+}
@@ -0,0 +1,102 @@
+package providers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// s3Credentials ключи доступа к S3-совместимому API
+type s3Credentials struct {
+	AccessKey string
+	SecretKey string
+}
+
+// presignV4 подписывает URL запроса к S3 по AWS Signature Version 4 (подпись в query):
+// по такому URL запрос идет без заголовка Authorization, как по presigned URL частей у других провайдеров
+// Подписывается только Host, тело не хешируется (UNSIGNED-PAYLOAD)
+func presignV4(method, rawURL string, creds s3Credentials, region string, expires time.Duration, now time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	now = now.UTC()
+	scope := now.Format("20060102") + "/" + region + "/s3/aws4_request"
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", creds.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonical := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		s3CanonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	hash := sha256.Sum256([]byte(canonical))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", query.Get("X-Amz-Date"), scope, hex.EncodeToString(hash[:])}, "\n")
+
+	key := []byte("AWS4" + creds.SecretKey)
+	for _, part := range []string{now.Format("20060102"), region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	query.Set("X-Amz-Signature", hex.EncodeToString(hmacSHA256(key, stringToSign)))
+
+	u.RawQuery = s3CanonicalQuery(query)
+	return u.String(), nil
+}
+
+// hmacSHA256 HMAC-SHA256 строки data ключом key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3CanonicalQuery query в каноническом виде SigV4: параметры по алфавиту, строгое %XX кодирование
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, s3Escape(key, false)+"="+s3Escape(value, false))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// s3Escape кодирует строку по правилам SigV4: все, кроме букв, цифр и "-._~", заменяется на %XX
+// keepSlash оставляет "/" (ключ объекта в пути). Так же кодируются имена файлов B2: "+" он читает как пробел,
+// поэтому url.PathEscape не подходит
+func s3Escape(s string, keepSlash bool) string {
+	var sb strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', strings.IndexByte("-._~", c) >= 0:
+			sb.WriteByte(c)
+		case c == '/' && keepSlash:
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
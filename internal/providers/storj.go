@@ -0,0 +1,370 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"multiUploader/internal/httpclient"
+)
+
+const (
+	// storjAuthURL сервис Storj, который выдает S3 ключи по access grant
+	storjAuthURL = "https://auth.storjshare.io"
+	// storjLinkshareURL сервис публичных ссылок Storj (linksharing)
+	storjLinkshareURL = "https://link.storjshare.io"
+	// storjRegion регион для подписи запросов к S3 шлюзу Storj (шлюз принимает любой)
+	storjRegion = "us-1"
+	// storjPartSize размер части по умолчанию: Storj хранит данные сегментами по 64 МБ
+	storjPartSize = 64 * 1024 * 1024
+	// s3MinPartSize и s3MaxParts ограничения multipart upload в S3
+	s3MinPartSize = 5 * 1024 * 1024
+	s3MaxParts    = 10000
+	// storjURLExpires срок действия подписанных URL запросов
+	storjURLExpires = time.Hour
+	// storjAbortTimeout ограничивает отмену незавершенной загрузки
+	storjAbortTimeout = 5 * time.Second
+)
+
+// Ключи полей настроек Storj
+const (
+	// StorjBucket имя бакета
+	StorjBucket = "bucket"
+	// StorjPrefix папка внутри бакета, например "uploads/" (необязательно)
+	StorjPrefix = "prefix"
+	// StorjLinkshareKey публичный ключ доступа только на чтение для ссылок linksharing (необязательно)
+	StorjLinkshareKey = "linkshare_key"
+)
+
+// storjBucketPattern имя бакета Storj: 3-63 строчных латинских букв, цифр, точек и дефисов
+var storjBucketPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// storjGrantPattern access grant: длинная строка base58
+var storjGrantPattern = regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{50,}$`)
+
+// storjCredentials S3 ключи, выданные по access grant (на время работы приложения: ключи не истекают)
+var storjCredentials = struct {
+	sync.Mutex
+	byGrant map[string]storjGateway
+}{byGrant: make(map[string]storjGateway)}
+
+// storjGateway S3 шлюз Storj и ключи к нему
+type storjGateway struct {
+	Endpoint string
+	Creds    s3Credentials
+}
+
+// StorjProvider загружает файлы в зашифрованное децентрализованное хранилище Storj DCS
+// Access grant из поля API ключа обменивается на S3 ключи, файл уходит multipart загрузкой через S3 шлюз,
+// а ссылка строится через linksharing публичным ключом из настроек
+type StorjProvider struct {
+	// apiKey access grant
+	apiKey       string
+	bucket       string
+	prefix       string
+	linkshareKey string
+
+	// opts настройки "Advanced"; ChunkSize 0 - storjPartSize
+	opts Options
+}
+
+// NewStorjProvider создает провайдер Storj; apiKey - access grant, бакет задается через SetSettings
+func NewStorjProvider(apiKey string) *StorjProvider {
+	return &StorjProvider{apiKey: apiKey}
+}
+
+func (p *StorjProvider) Name() string {
+	return "Storj"
+}
+
+func (p *StorjProvider) RequiresAuth() bool {
+	return true
+}
+
+func (p *StorjProvider) ValidateAPIKey(apiKey string) error {
+	if !storjGrantPattern.MatchString(strings.TrimSpace(apiKey)) {
+		return errors.New("access grant must be the long key from the Storj console or uplink share")
+	}
+	return nil
+}
+
+func (p *StorjProvider) Capabilities() Capabilities {
+	return Capabilities{Multipart: true}
+}
+
+func (p *StorjProvider) SetOptions(opts Options) {
+	p.opts = opts
+}
+
+func (p *StorjProvider) SettingFields() []SettingField {
+	return []SettingField{
+		{Key: StorjBucket, Label: "Bucket:", Placeholder: "my-bucket"},
+		{Key: StorjPrefix, Label: "Path prefix:", Placeholder: "uploads/"},
+		{Key: StorjLinkshareKey, Label: "Linkshare access key:", Placeholder: "jwaohtj3dhixxfpzhwj522x7z3pb"},
+	}
+}
+
+func (p *StorjProvider) SetSettings(values map[string]string) {
+	p.bucket = strings.TrimSpace(values[StorjBucket])
+	p.prefix = strings.TrimSpace(values[StorjPrefix])
+	p.linkshareKey = strings.TrimSpace(values[StorjLinkshareKey])
+}
+
+func (p *StorjProvider) ValidateSettings() error {
+	if !storjBucketPattern.MatchString(p.bucket) {
+		return errors.New("bucket name must be 3-63 lowercase letters, digits, dots or hyphens")
+	}
+	if strings.HasPrefix(p.prefix, "/") {
+		return errors.New("path prefix must not start with /")
+	}
+	if strings.ContainsAny(p.linkshareKey, "/ ") {
+		return errors.New("linkshare access key must be the access key ID, not a URL")
+	}
+	return nil
+}
+
+// Probe проверяет доступность сервиса авторизации Storj
+func (p *StorjProvider) Probe(ctx context.Context) error {
+	return probeURL(ctx, storjAuthURL)
+}
+
+// Upload загружает файл multipart загрузкой через S3 шлюз и возвращает ссылку linksharing
+// Незавершенная загрузка отменяется, чтобы ее части не занимали место в хранилище
+func (p *StorjProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- UploadProgress) (_ *UploadResult, err error) {
+	if ctx.Err() != nil {
+		return nil, ErrCancelled
+	}
+	defer func() {
+		if err != nil && ctx.Err() != nil {
+			err = ErrCancelled
+		}
+	}()
+
+	gateway, err := p.gateway(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := p.prefix + filename
+	object := strings.TrimRight(gateway.Endpoint, "/") + "/" + s3Escape(p.bucket, true) + "/" + s3Escape(key, true)
+	contentType := fileContentType(file, filename)
+	var uploadID string
+	// Размер части выбирает partSize в init: он не меньше минимума S3
+	opts := p.opts
+	opts.ChunkSize = 0
+
+	upload := &presignedUpload{
+		provider: p.Name(),
+		opts:     opts,
+		init: func(ctx context.Context) (partLayout, error) {
+			id, err := p.createUpload(ctx, gateway, object, contentType)
+			if err != nil {
+				return partLayout{}, err
+			}
+			uploadID = id
+			size := p.partSize(fileSize)
+			return partLayout{ChunkSize: size, TotalParts: max(partCount(fileSize, size), 1)}, nil
+		},
+		partURL: func(ctx context.Context, layout partLayout, partNum int, retry bool) (string, error) {
+			return presignV4(http.MethodPut, fmt.Sprintf("%s?partNumber=%d&uploadId=%s", object, partNum, s3Escape(uploadID, false)),
+				gateway.Creds, storjRegion, storjURLExpires, time.Now())
+		},
+		complete: func(ctx context.Context, parts []uploadedPart) (*UploadResult, error) {
+			return p.completeUpload(ctx, gateway, object, uploadID, key, parts)
+		},
+	}
+
+	result, err := upload.run(ctx, file, fileSize, progress)
+	if err != nil && uploadID != "" {
+		p.abortUpload(ctx, gateway, object, uploadID)
+	}
+	return result, err
+}
+
+// gateway возвращает S3 шлюз и ключи для access grant: регистрирует его в сервисе авторизации Storj
+// Ключи запоминаются, чтобы каждая загрузка не регистрировала grant заново
+func (p *StorjProvider) gateway(ctx context.Context) (storjGateway, error) {
+	grant := strings.TrimSpace(p.apiKey)
+	storjCredentials.Lock()
+	gateway, ok := storjCredentials.byGrant[grant]
+	storjCredentials.Unlock()
+	if ok {
+		return gateway, nil
+	}
+
+	body, err := json.Marshal(map[string]any{"access_grant": grant, "public": false})
+	if err != nil {
+		return storjGateway{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, storjAuthURL+"/v1/access", bytes.NewReader(body))
+	if err != nil {
+		return storjGateway{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return storjGateway{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return storjGateway{}, statusError("register access grant", resp)
+	}
+
+	var registered struct {
+		AccessKeyID string `json:"access_key_id"`
+		SecretKey   string `json:"secret_key"`
+		Endpoint    string `json:"endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&registered); err != nil {
+		return storjGateway{}, fmt.Errorf("failed to decode register response: %w", err)
+	}
+	if registered.AccessKeyID == "" || !isHTTPURL(registered.Endpoint) {
+		return storjGateway{}, &ServerError{Op: "register access grant", Message: "response has no S3 credentials"}
+	}
+
+	gateway = storjGateway{
+		Endpoint: registered.Endpoint,
+		Creds:    s3Credentials{AccessKey: registered.AccessKeyID, SecretKey: registered.SecretKey},
+	}
+	storjCredentials.Lock()
+	storjCredentials.byGrant[grant] = gateway
+	storjCredentials.Unlock()
+	return gateway, nil
+}
+
+// partSize размер части: из настроек или storjPartSize, не меньше минимума S3
+// и такой, чтобы частей было не больше s3MaxParts
+func (p *StorjProvider) partSize(fileSize int64) int64 {
+	size := int64(storjPartSize)
+	if p.opts.ChunkSize > 0 {
+		size = max(p.opts.ChunkSize, s3MinPartSize)
+	}
+	return max(size, (fileSize+s3MaxParts-1)/s3MaxParts)
+}
+
+// createUpload начинает multipart загрузку (CreateMultipartUpload) и возвращает ее UploadId
+func (p *StorjProvider) createUpload(ctx context.Context, gateway storjGateway, object, contentType string) (string, error) {
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err := p.s3Request(ctx, gateway, http.MethodPost, object+"?uploads", nil, map[string]string{"Content-Type": contentType}, "start upload", &result)
+	if err != nil {
+		return "", err
+	}
+	if result.UploadID == "" {
+		return "", &ServerError{Op: "start upload", Message: "response has no UploadId"}
+	}
+	return result.UploadID, nil
+}
+
+// completeUpload завершает загрузку списком частей и возвращает ссылки на файл
+func (p *StorjProvider) completeUpload(ctx context.Context, gateway storjGateway, object, uploadID, key string, parts []uploadedPart) (*UploadResult, error) {
+	type completedPart struct {
+		PartNumber int
+		ETag       string
+	}
+	request := struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{}
+	for _, part := range parts {
+		request.Parts = append(request.Parts, completedPart{PartNumber: part.Number, ETag: `"` + part.ETag + `"`})
+	}
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.s3Request(ctx, gateway, http.MethodPost, object+"?uploadId="+s3Escape(uploadID, false), body,
+		map[string]string{"Content-Type": "application/xml"}, "complete", &struct{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	if p.linkshareKey == "" {
+		return &UploadResult{
+			URL:     object,
+			FileID:  key,
+			Message: "Add a linkshare access key in Settings to get a public link; this link needs the S3 credentials",
+		}, nil
+	}
+	path := p.linkshareKey + "/" + s3Escape(p.bucket, true) + "/" + s3Escape(key, true)
+	return &UploadResult{
+		URL:         storjLinkshareURL + "/s/" + path,
+		DownloadURL: storjLinkshareURL + "/raw/" + path,
+		FileID:      key,
+	}, nil
+}
+
+// abortUpload удаляет незавершенную загрузку вместе с частями (AbortMultipartUpload)
+// Вызывается и после отмены ctx, поэтому запрос идет с отдельным коротким таймаутом; ошибка не важна
+func (p *StorjProvider) abortUpload(ctx context.Context, gateway storjGateway, object, uploadID string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), storjAbortTimeout)
+	defer cancel()
+	_ = p.s3Request(ctx, gateway, http.MethodDelete, object+"?uploadId="+s3Escape(uploadID, false), nil, nil, "abort", nil)
+}
+
+// s3Request выполняет подписанный запрос к S3 шлюзу и разбирает XML ответ в out (nil - ответ не нужен)
+// S3 может ответить 200 с ошибкой в теле (CompleteMultipartUpload), поэтому тело проверяется на <Error>
+func (p *StorjProvider) s3Request(ctx context.Context, gateway storjGateway, method, target string, body []byte, headers map[string]string, op string, out any) error {
+	signed, err := presignV4(method, target, gateway.Creds, storjRegion, storjURLExpires, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, signed, reader)
+	if err != nil {
+		return err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return statusError(op, resp)
+	}
+	if out == nil {
+		return nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var s3Err struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(data, &s3Err) == nil && s3Err.XMLName.Local == "Error" {
+		return &ServerError{Op: op, Message: strings.TrimSpace(s3Err.Code + " " + s3Err.Message)}
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := xml.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// storjTestGrant access grant для тестов
+const storjTestGrant = "1Dv4ZLuyhhvTPjPqJWamsUzGLs1TCcmzsPLEyqM4ZMEhMVqpsLgc1TBfi8d5iUY7rH"
+
+// storjTestServer сервис авторизации и S3 шлюз Storj для тестов
+type storjTestServer struct {
+	t *testing.T
+	// failParts части отвечают 400
+	failParts bool
+
+	mu          sync.Mutex
+	registered  int
+	contentType string
+	parts       map[string]int
+	completed   []string
+	aborted     bool
+}
+
+func (s *storjTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Host == "auth.storjshare.io" {
+		var req struct {
+			Grant  string `json:"access_grant"`
+			Public bool   `json:"public"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Grant != storjTestGrant || req.Public {
+			s.t.Errorf("register request = %+v, want the grant as a private access", req)
+		}
+		s.registered++
+		writeDryRunJSON(w, map[string]any{"access_key_id": "ak", "secret_key": "sk", "endpoint": "https://gateway.storj.test"})
+		return
+	}
+
+	query := r.URL.Query()
+	if query.Get("X-Amz-Signature") == "" || !strings.HasPrefix(query.Get("X-Amz-Credential"), "ak/") {
+		s.t.Errorf("%s %s is not signed with the registered key", r.Method, r.URL)
+	}
+	if r.URL.EscapedPath() != "/my-bucket/docs/a%2Bb.bin" {
+		s.t.Errorf("object path = %s", r.URL.EscapedPath())
+	}
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		s.contentType = r.Header.Get("Content-Type")
+		fmt.Fprint(w, "<InitiateMultipartUploadResult><UploadId>up/1</UploadId></InitiateMultipartUploadResult>")
+	case r.Method == http.MethodPut:
+		if query.Get("uploadId") != "up/1" {
+			s.t.Errorf("part uploadId = %q", query.Get("uploadId"))
+		}
+		if s.failParts {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		etag := fmt.Sprintf("etag-%s", query.Get("partNumber"))
+		s.parts[etag] = len(body)
+		w.Header().Set("ETag", `"`+etag+`"`)
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		var req struct {
+			Parts []struct {
+				PartNumber int
+				ETag       string
+			} `xml:"Part"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := xml.Unmarshal(body, &req); err != nil {
+			s.t.Errorf("complete body %q: %v", body, err)
+		}
+		for _, part := range req.Parts {
+			s.completed = append(s.completed, fmt.Sprintf("%d:%s", part.PartNumber, part.ETag))
+		}
+		fmt.Fprint(w, "<CompleteMultipartUploadResult><Key>docs/a+b.bin</Key></CompleteMultipartUploadResult>")
+	case r.Method == http.MethodDelete:
+		s.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		http.NotFound(w, r)
+	}
+}
+
+// useStorjTestServer направляет запросы провайдера в тестовый Storj; ключи шлюза не переживают тест
+func useStorjTestServer(t *testing.T) *storjTestServer {
+	forgetCredentials := func() {
+		storjCredentials.Lock()
+		clear(storjCredentials.byGrant)
+		storjCredentials.Unlock()
+	}
+	forgetCredentials()
+	t.Cleanup(forgetCredentials)
+
+	server := &storjTestServer{t: t, parts: make(map[string]int)}
+	useDryRunServer(t, server)
+	return server
+}
+
+// newTestStorjProvider провайдер с бакетом тестового сервера и частями по 5 МБ
+func newTestStorjProvider(t *testing.T, linkshareKey string) *StorjProvider {
+	p := configured(t, NewStorjProvider(storjTestGrant), map[string]string{StorjBucket: "my-bucket", StorjPrefix: "docs/", StorjLinkshareKey: linkshareKey})
+	p.SetOptions(Options{ChunkSize: 4 << 20})
+	return p
+}
+
+// TestStorjUpload проверяет регистрацию grant, части не меньше 5 МБ, список частей при завершении и ссылку linksharing
+func TestStorjUpload(t *testing.T) {
+	server := useStorjTestServer(t)
+	// Обработчик видит только начало тела (лимит dry run), размер части проверяем по числу частей:
+	// 9 МБ частями по 4 МБ - 3 части, с нижней границей S3 в 5 МБ - 2
+	data := make([]byte, 9<<20)
+
+	for range 2 {
+		server.completed = nil
+		result, err := newTestStorjProvider(t, "jwpublic").Upload(context.Background(), bytes.NewReader(data), "a+b.bin", int64(len(data)), make(chan UploadProgress, 100))
+		if err != nil {
+			t.Fatalf("Upload() = %v", err)
+		}
+		if result.URL != "https://link.storjshare.io/s/jwpublic/my-bucket/docs/a%2Bb.bin" ||
+			result.DownloadURL != "https://link.storjshare.io/raw/jwpublic/my-bucket/docs/a%2Bb.bin" {
+			t.Errorf("result = %+v, want linksharing URLs", result)
+		}
+		if want := []string{`1:"etag-1"`, `2:"etag-2"`}; !slices.Equal(server.completed, want) {
+			t.Errorf("completed parts = %v, want %v", server.completed, want)
+		}
+	}
+
+	if server.registered != 1 {
+		t.Errorf("grant registered %d times, want once", server.registered)
+	}
+	if server.contentType == "" {
+		t.Error("CreateMultipartUpload sent no Content-Type")
+	}
+}
+
+// TestStorjAbort проверяет, что незавершенная загрузка удаляется, а без публичного ключа ссылка не выдается за публичную
+func TestStorjAbort(t *testing.T) {
+	server := useStorjTestServer(t)
+	server.failParts = true
+
+	data := make([]byte, 1024)
+	if _, err := newTestStorjProvider(t, "").Upload(context.Background(), bytes.NewReader(data), "a+b.bin", int64(len(data)), make(chan UploadProgress, 100)); err == nil {
+		t.Fatal("Upload() succeeded, want error")
+	}
+	if !server.aborted {
+		t.Error("unfinished upload was not aborted")
+	}
+
+	server.failParts = false
+	result, err := newTestStorjProvider(t, "").Upload(context.Background(), bytes.NewReader(data), "a+b.bin", int64(len(data)), make(chan UploadProgress, 100))
+	if err != nil {
+		t.Fatalf("Upload() = %v", err)
+	}
+	if result.URL != "https://gateway.storj.test/my-bucket/docs/a%2Bb.bin" || result.Message == "" {
+		t.Errorf("result = %+v, want gateway URL with a note about the linkshare key", result)
+	}
+}
@@ -0,0 +1,97 @@
+package providers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"multiUploader/internal/mimetype"
+)
+
+// StreamUploader реализуется провайдерами, которые читают файл один раз от начала до конца
+// Таким провайдерам не нужен Seek: они принимают поток из stdin, pipe или сжатия на лету без временного файла
+// fileSize - размер потока; -1, если он неизвестен (прогресс тогда показывает только отправленный объем)
+type StreamUploader interface {
+	UploadStream(ctx context.Context, file io.Reader, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error)
+}
+
+// NeedsSeek сообщает, нужен ли провайдеру io.ReadSeeker
+// Провайдеры, загружающие части параллельно или докачивающие после обрыва, перечитывают файл
+func NeedsSeek(p Provider) bool {
+	_, ok := p.(StreamUploader)
+	return !ok
+}
+
+// UploadReader загружает данные из file провайдером p
+// Потоковые провайдеры получают file напрямую; остальным он передается как io.ReadSeeker,
+// а если file его не поддерживает - сначала сохраняется во временный файл
+func UploadReader(ctx context.Context, p Provider, file io.Reader, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
+	if stream, ok := p.(StreamUploader); ok {
+		return stream.UploadStream(ctx, file, filename, fileSize, progress)
+	}
+	if seeker, ok := file.(io.ReadSeeker); ok && fileSize >= 0 {
+		return p.Upload(ctx, seeker, filename, fileSize, progress)
+	}
+
+	spooled, size, err := spoolToTemp(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		spooled.Close()
+		os.Remove(spooled.Name())
+	}()
+	return p.Upload(ctx, spooled, filename, size, progress)
+}
+
+// spoolToTemp сохраняет поток во временный файл и возвращает его открытым с начала
+func spoolToTemp(ctx context.Context, r io.Reader) (*os.File, int64, error) {
+	tmp, err := os.CreateTemp("", "multiuploader-stream-*")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	size, err := io.Copy(tmp, &contextReader{ctx: ctx, reader: r})
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		if ctx.Err() != nil {
+			return nil, 0, ErrCancelled
+		}
+		return nil, 0, fmt.Errorf("failed to buffer stream: %w", err)
+	}
+	return tmp, size, nil
+}
+
+// contextReader прекращает чтение после отмены ctx
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
+
+// streamContentType определяет MIME тип по началу потока
+// Возвращает reader, который отдает поток целиком, включая прочитанное начало
+func streamContentType(file io.Reader, filename string) (string, io.Reader) {
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		return fileContentType(seeker, filename), file
+	}
+
+	buffered := bufio.NewReaderSize(file, mimetype.SniffLen)
+	head, err := buffered.Peek(mimetype.SniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return mimetype.Default, buffered
+	}
+	return mimetype.Detect(head, filename), buffered
+}
@@ -0,0 +1,141 @@
+package providers
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// seekingProvider провайдер, которому нужен Seek: запоминает полученный файл
+type seekingProvider struct {
+	got  string
+	size int64
+	path string
+}
+
+func (p *seekingProvider) Name() string                { return "seeking" }
+func (p *seekingProvider) RequiresAuth() bool          { return false }
+func (p *seekingProvider) ValidateAPIKey(string) error { return nil }
+
+func (p *seekingProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
+	if f, ok := file.(*os.File); ok {
+		p.path = f.Name()
+	}
+	data, err := io.ReadAll(file)
+	p.got, p.size = string(data), fileSize
+	return &UploadResult{URL: "https://example.com/" + filename}, err
+}
+
+// streamingProvider потоковый провайдер
+type streamingProvider struct {
+	seekingProvider
+	streamed bool
+}
+
+func (p *streamingProvider) UploadStream(ctx context.Context, file io.Reader, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
+	p.streamed = true
+	data, err := io.ReadAll(file)
+	p.got, p.size = string(data), fileSize
+	return &UploadResult{URL: "https://example.com/" + filename}, err
+}
+
+// TestUploadReader проверяет выбор пути загрузки по возможностям провайдера
+func TestUploadReader(t *testing.T) {
+	ctx := context.Background()
+	const content = "streamed content"
+
+	t.Run("Stream provider gets the reader", func(t *testing.T) {
+		p := &streamingProvider{}
+		if NeedsSeek(p) {
+			t.Fatal("NeedsSeek() = true for StreamUploader")
+		}
+		// Обертка без Seek, размер неизвестен
+		if _, err := UploadReader(ctx, p, io.MultiReader(strings.NewReader(content)), "a.txt", -1, nil); err != nil {
+			t.Fatal(err)
+		}
+		if !p.streamed || p.got != content || p.size != -1 {
+			t.Errorf("streamed = %v, got %q, size %d", p.streamed, p.got, p.size)
+		}
+	})
+
+	t.Run("Seekable reader is passed as is", func(t *testing.T) {
+		p := &seekingProvider{}
+		if _, err := UploadReader(ctx, p, strings.NewReader(content), "a.txt", int64(len(content)), nil); err != nil {
+			t.Fatal(err)
+		}
+		if p.got != content || p.path != "" {
+			t.Errorf("got %q via %q, want the original reader", p.got, p.path)
+		}
+	})
+
+	t.Run("Stream is buffered for seeking provider", func(t *testing.T) {
+		p := &seekingProvider{}
+		if !NeedsSeek(p) {
+			t.Fatal("NeedsSeek() = false for plain Provider")
+		}
+		if _, err := UploadReader(ctx, p, io.MultiReader(strings.NewReader(content)), "a.txt", -1, nil); err != nil {
+			t.Fatal(err)
+		}
+		if p.got != content || p.size != int64(len(content)) {
+			t.Errorf("got %q with size %d", p.got, p.size)
+		}
+		if p.path == "" {
+			t.Fatal("provider did not get a temp file")
+		}
+		if _, err := os.Stat(p.path); !os.IsNotExist(err) {
+			t.Errorf("temp file %s was not removed", p.path)
+		}
+	})
+}
+
+// TestStreamContentType проверяет определение типа по началу потока без потери данных
+func TestStreamContentType(t *testing.T) {
+	const page = "<html><body>page</body></html>"
+	contentType, r := streamContentType(io.MultiReader(strings.NewReader(page)), "page.bin")
+	if contentType != "text/html" {
+		t.Errorf("content type = %q, want text/html", contentType)
+	}
+	data, _ := io.ReadAll(r)
+	if string(data) != page {
+		t.Errorf("reader returned %q, want the whole stream", data)
+	}
+}
+
+// readCounter считает вызовы Read источника
+type readCounter struct {
+	reader io.Reader
+	calls  int
+}
+
+func (r *readCounter) Read(p []byte) (int, error) {
+	r.calls++
+	return r.reader.Read(p)
+}
+
+// TestReadBuffer проверяет, что буфер чтения уменьшает число чтений файла и не меняет данные
+func TestReadBuffer(t *testing.T) {
+	data := strings.Repeat("0123456789abcdef", 256<<10) // 4 МБ
+
+	for _, tc := range []struct {
+		size     int
+		maxCalls int
+	}{
+		{0, 0},
+		{1 << 20, 5},
+	} {
+		src := &readCounter{reader: strings.NewReader(data)}
+		var got strings.Builder
+		// Запись блоками по 32 КБ, как у io.Copy без ReaderFrom
+		if _, err := io.CopyBuffer(struct{ io.Writer }{&got}, struct{ io.Reader }{Options{ReadBufferSize: tc.size}.readBuffer(src)}, make([]byte, 32<<10)); err != nil {
+			t.Fatalf("copy with buffer %d: %v", tc.size, err)
+		}
+		if got.String() != data {
+			t.Errorf("buffer %d changed the data", tc.size)
+		}
+		if tc.maxCalls > 0 && src.calls > tc.maxCalls {
+			t.Errorf("buffer %d: %d reads of the file, want at most %d", tc.size, src.calls, tc.maxCalls)
+		}
+	}
+}
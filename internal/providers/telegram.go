@@ -0,0 +1,261 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/mimetype"
+)
+
+const telegramAPIURL = "https://api.telegram.org"
+
+// telegramPartSize размер части: Bot API принимает файлы до 50 МБ (переопределяется в тестах)
+var telegramPartSize int64 = 48 * 1024 * 1024
+
+// TelegramChatID ключ поля настроек с чатом, куда отправляются файлы
+const TelegramChatID = "chat_id"
+
+// telegramTokenPattern формат токена бота от @BotFather
+var telegramTokenPattern = regexp.MustCompile(`^\d+:[A-Za-z0-9_-]{30,}$`)
+
+// telegramChatPattern @username канала или числовой ID чата
+var telegramChatPattern = regexp.MustCompile(`^(@[A-Za-z0-9_]{5,}|-?\d+)$`)
+
+// TelegramProvider отправляет файлы документами через Telegram Bot API
+// Файлы больше лимита Bot API отправляются частями file.ext.001, file.ext.002, ...
+type TelegramProvider struct {
+	// apiKey токен бота
+	apiKey string
+	chatID string
+	opts   Options
+}
+
+// NewTelegramProvider создает провайдер Telegram; apiKey - токен бота
+func NewTelegramProvider(apiKey string) *TelegramProvider {
+	return &TelegramProvider{apiKey: apiKey}
+}
+
+func (t *TelegramProvider) Name() string {
+	return "Telegram"
+}
+
+func (t *TelegramProvider) RequiresAuth() bool {
+	return true
+}
+
+func (t *TelegramProvider) ValidateAPIKey(apiKey string) error {
+	if !telegramTokenPattern.MatchString(apiKey) {
+		return errors.New("bot token must look like 123456:ABC-DEF... (get it from @BotFather)")
+	}
+	return nil
+}
+
+// Probe проверяет доступность Bot API
+func (t *TelegramProvider) Probe(ctx context.Context) error {
+	return probeURL(ctx, telegramAPIURL)
+}
+
+func (t *TelegramProvider) SetOptions(opts Options) {
+	t.opts = opts
+}
+
+func (t *TelegramProvider) SettingFields() []SettingField {
+	return []SettingField{
+		{Key: TelegramChatID, Label: "Chat ID:", Placeholder: "@channel or -1001234567890"},
+	}
+}
+
+func (t *TelegramProvider) SetSettings(values map[string]string) {
+	t.chatID = values[TelegramChatID]
+}
+
+func (t *TelegramProvider) ValidateSettings() error {
+	if !telegramChatPattern.MatchString(t.chatID) {
+		return errors.New("chat ID must be @channel or a numeric ID")
+	}
+	return nil
+}
+
+// telegramChat чат, в который пришло сообщение
+type telegramChat struct {
+	ID       int64  `json:"id"`
+	Type     string `json:"type"`
+	Username string `json:"username"`
+}
+
+// telegramMessage отправленное сообщение с документом
+type telegramMessage struct {
+	MessageID int64        `json:"message_id"`
+	Chat      telegramChat `json:"chat"`
+	Document  struct {
+		FileID string `json:"file_id"`
+	} `json:"document"`
+}
+
+// telegramResponse ответ Bot API
+type telegramResponse struct {
+	OK          bool            `json:"ok"`
+	Result      telegramMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// Upload отправляет файл в чат; большой файл уходит несколькими сообщениями по порядку
+func (t *TelegramProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
+	if ctx.Err() != nil {
+		return nil, ErrCancelled
+	}
+
+	totalParts := max(partCount(fileSize, telegramPartSize), 1)
+	// Части разрезанного файла по отдельности не открываются, поэтому тип известен только для целого
+	contentType := mimetype.Default
+	if totalParts == 1 {
+		contentType = fileContentType(file, filename)
+	}
+	messages := make([]telegramMessage, totalParts)
+
+	uploader := &chunkUploader{
+		file:       file,
+		fileSize:   fileSize,
+		chunkSize:  telegramPartSize,
+		totalParts: totalParts,
+		progress:   progress,
+		opts:       t.opts,
+		sequential: true,
+		permanent:  telegramPermanent,
+		uploadPart: func(ctx context.Context, partNum int, retry bool, body io.Reader, size int64) (string, error) {
+			name, caption := filename, ""
+			if totalParts > 1 {
+				name = fmt.Sprintf("%s.%03d", filename, partNum)
+				caption = fmt.Sprintf("%s (%d/%d)", filename, partNum, totalParts)
+			}
+			message, err := t.sendDocument(ctx, name, caption, contentType, body, size)
+			if err != nil {
+				return "", err
+			}
+			messages[partNum-1] = *message
+			return message.Document.FileID, nil
+		},
+	}
+	if _, err := uploader.run(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil, ErrCancelled
+		}
+		return nil, err
+	}
+
+	first := messages[0]
+	result := &UploadResult{
+		URL:    telegramMessageURL(first),
+		FileID: first.Document.FileID,
+	}
+	if totalParts > 1 {
+		result.Message = fmt.Sprintf("File was sent in %d parts (%s.001 ... %s.%03d); join them to restore it", totalParts, filename, filename, totalParts)
+	}
+	return result, nil
+}
+
+// sendDocument отправляет одну часть файла сообщением (sendDocument)
+func (t *TelegramProvider) sendDocument(ctx context.Context, name, caption, contentType string, body io.Reader, size int64) (*telegramMessage, error) {
+	fields := [][2]string{{"chat_id", t.chatID}}
+	if caption != "" {
+		fields = append(fields, [2]string{"caption", caption})
+	}
+	form, formType, length, err := multipartFileBody(fields, "document", name, contentType, body, size)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/sendDocument", telegramAPIURL, t.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, form)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = length
+	req.Header.Set("Content-Type", formType)
+
+	resp, err := httpclient.LongLived().Do(req)
+	if err != nil {
+		return nil, redactTelegramToken(err, t.apiKey)
+	}
+	defer resp.Body.Close()
+
+	var result telegramResponse
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if err := json.Unmarshal(raw, &result); err != nil || !result.OK {
+		return nil, telegramError("send document", resp, raw, result)
+	}
+	return &result.Result, nil
+}
+
+// telegramError превращает ответ Bot API с ошибкой в типизированную ошибку провайдера
+func telegramError(op string, resp *http.Response, raw []byte, result telegramResponse) error {
+	if resp.StatusCode == http.StatusTooManyRequests && result.Parameters.RetryAfter > 0 {
+		return &QuotaError{
+			Reason:     QuotaRateLimit,
+			RetryAfter: time.Duration(result.Parameters.RetryAfter) * time.Second,
+			Err:        &HTTPError{Op: op, Status: resp.StatusCode, Body: result.Description},
+		}
+	}
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusBadRequest {
+		// "Bad Request: chat not found" и т.п. - ошибка настроек, а не сервера
+		if result.Description != "" {
+			return &ServerError{Op: op, Message: result.Description}
+		}
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+	return statusError(op, resp)
+}
+
+// telegramPermanent ошибки, после которых повторять часть бесполезно: неверный токен или чат,
+// превышен лимит (пауза после 429 выдерживается при повторе всей загрузки)
+func telegramPermanent(err error) bool {
+	var authErr *AuthError
+	var serverErr *ServerError
+	var quotaErr *QuotaError
+	return errors.As(err, &authErr) || errors.As(err, &serverErr) || errors.As(err, &quotaErr)
+}
+
+// redactTelegramToken убирает токен бота из ошибки: он входит в URL запроса
+func redactTelegramToken(err error, token string) error {
+	if token == "" || !strings.Contains(err.Error(), token) {
+		return err
+	}
+	return &redactedError{msg: strings.ReplaceAll(err.Error(), token, "<token>"), err: err}
+}
+
+// redactedError ошибка с замененным текстом; цепочка errors.Is/As сохраняется
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// telegramMessageURL возвращает ссылку на сообщение
+// Ссылки есть только у каналов и супергрупп; для личного чата открываем его в приложении
+func telegramMessageURL(m telegramMessage) string {
+	switch {
+	case m.Chat.Username != "":
+		return fmt.Sprintf("https://t.me/%s/%d", m.Chat.Username, m.MessageID)
+	case strings.HasPrefix(strconv.FormatInt(m.Chat.ID, 10), "-100"):
+		return fmt.Sprintf("https://t.me/c/%s/%d", strings.TrimPrefix(strconv.FormatInt(m.Chat.ID, 10), "-100"), m.MessageID)
+	case m.Chat.ID > 0:
+		return fmt.Sprintf("tg://openmessage?user_id=%d&message_id=%d", m.Chat.ID, m.MessageID)
+	}
+	return fmt.Sprintf("tg://openmessage?chat_id=%d&message_id=%d", m.Chat.ID, m.MessageID)
+}
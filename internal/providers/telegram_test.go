@@ -0,0 +1,126 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"multiUploader/internal/httpclient"
+)
+
+// TestTelegramSplit проверяет, что большой файл уходит частями по порядку с именами .001, .002, ...
+func TestTelegramSplit(t *testing.T) {
+	old := telegramPartSize
+	telegramPartSize = 4096
+	t.Cleanup(func() { telegramPartSize = old })
+
+	data := make([]byte, 10*1024+123)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	var mu sync.Mutex
+	var names, captions []string
+	var received []byte
+	httpclient.EnableDryRun(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path != "/bot123456:token/sendDocument" || r.FormValue("chat_id") != "-1001234567890" {
+			t.Errorf("request %s with chat_id %q", r.URL.Path, r.FormValue("chat_id"))
+		}
+		file, header, err := r.FormFile("document")
+		if err != nil {
+			t.Errorf("FormFile() = %v", err)
+			return
+		}
+		part, _ := io.ReadAll(file)
+		received = append(received, part...)
+		names = append(names, header.Filename)
+		captions = append(captions, r.FormValue("caption"))
+		fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d,"chat":{"id":-1001234567890,"type":"channel"},"document":{"file_id":"f%d"}}}`, len(names)+10, len(names))
+	}))
+	t.Cleanup(httpclient.DisableDryRun)
+
+	p := NewTelegramProvider("123456:token")
+	p.SetSettings(map[string]string{TelegramChatID: "-1001234567890"})
+
+	// Файл меньше лимита dry run транспорта (64 КБ), поэтому обработчик видит тело целиком
+	result, err := p.Upload(context.Background(), bytes.NewReader(data), "file.bin", int64(len(data)), make(chan UploadProgress, 100))
+	if err != nil {
+		t.Fatalf("Upload() = %v", err)
+	}
+
+	if want := "file.bin.001,file.bin.002,file.bin.003"; strings.Join(names, ",") != want {
+		t.Errorf("documents = %v, want %s", names, want)
+	}
+	if captions[0] != "file.bin (1/3)" {
+		t.Errorf("caption = %q, want file.bin (1/3)", captions[0])
+	}
+	if !bytes.Equal(received, data) {
+		t.Errorf("received %d bytes, want the %d bytes of the file", len(received), len(data))
+	}
+	if result.URL != "https://t.me/c/1234567890/11" || result.FileID != "f1" || result.Message == "" {
+		t.Errorf("result = %+v, want link to the first part and a note about parts", result)
+	}
+}
+
+// TestTelegramErrors проверяет разбор ошибок Bot API
+func TestTelegramErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		status int
+		body   string
+		check  func(error) bool
+	}{
+		{"Chat not found", http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`, func(err error) bool {
+			var serverErr *ServerError
+			return errors.As(err, &serverErr) && serverErr.Message == "Bad Request: chat not found"
+		}},
+		{"Flood wait", http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":7}}`, func(err error) bool {
+			var quotaErr *QuotaError
+			return errors.As(err, &quotaErr) && quotaErr.RetryAfter.Seconds() == 7
+		}},
+		{"Bad token", http.StatusUnauthorized, `{"ok":false,"error_code":401,"description":"Unauthorized"}`, func(err error) bool {
+			var authErr *AuthError
+			return errors.As(err, &authErr)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			httpclient.EnableDryRun(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				io.WriteString(w, tc.body)
+			}))
+			t.Cleanup(httpclient.DisableDryRun)
+
+			p := NewTelegramProvider("123456:token")
+			p.SetSettings(map[string]string{TelegramChatID: "@channel"})
+			_, err := p.Upload(context.Background(), bytes.NewReader([]byte("data")), "file.bin", 4, make(chan UploadProgress, 10))
+			if !tc.check(err) {
+				t.Errorf("Upload() error = %v (%T)", err, err)
+			}
+		})
+	}
+}
+
+// TestTelegramMessageURL проверяет ссылки на сообщения в разных типах чатов
+func TestTelegramMessageURL(t *testing.T) {
+	for _, tc := range []struct {
+		chat telegramChat
+		want string
+	}{
+		{telegramChat{ID: -1001234567890, Username: "channel"}, "https://t.me/channel/5"},
+		{telegramChat{ID: -1001234567890}, "https://t.me/c/1234567890/5"},
+		{telegramChat{ID: 42}, "tg://openmessage?user_id=42&message_id=5"},
+	} {
+		if got := telegramMessageURL(telegramMessage{MessageID: 5, Chat: tc.chat}); got != tc.want {
+			t.Errorf("telegramMessageURL(%+v) = %s, want %s", tc.chat, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package providers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"multiUploader/internal/httpclient"
+)
+
+const (
+	// tusVersion версия протокола tus (заголовок Tus-Resumable)
+	tusVersion = "1.0.0"
+	// tusOffsetContentType тип тела PATCH запроса
+	tusOffsetContentType = "application/offset+octet-stream"
+	// tusTerminateTimeout ограничивает удаление отмененной загрузки с сервера
+	tusTerminateTimeout = 5 * time.Second
+)
+
+// Ключи полей настроек tus
+const (
+	// TusEndpoint адрес создания загрузок (например, https://tusd.example.com/files/)
+	TusEndpoint = "endpoint"
+	// TusAuthorization значение заголовка Authorization (необязательно)
+	TusAuthorization = "authorization"
+	// TusMetadata дополнительные метаданные, по паре "ключ=значение" на строку
+	TusMetadata = "metadata"
+)
+
+// TusProvider загружает файлы на любой сервер с протоколом tus.io (в том числе tusd)
+// Загрузка возобновляется с подтвержденного сервером смещения после обрыва или ошибки
+type TusProvider struct {
+	endpoint      string
+	authorization string
+	metadata      string
+
+	// opts настройки "Advanced"; ChunkSize - размер одного PATCH запроса (0 - весь остаток файла)
+	opts Options
+}
+
+// NewTusProvider создает провайдер tus; адрес сервера задается через SetSettings
+func NewTusProvider() *TusProvider {
+	return &TusProvider{}
+}
+
+func (p *TusProvider) Name() string {
+	return "tus"
+}
+
+// RequiresAuth tus серверы обычно без ключа; авторизация задается отдельным полем
+func (p *TusProvider) RequiresAuth() bool {
+	return false
+}
+
+func (p *TusProvider) ValidateAPIKey(apiKey string) error {
+	return nil
+}
+
+func (p *TusProvider) Capabilities() Capabilities {
+	return Capabilities{Multipart: true}
+}
+
+func (p *TusProvider) SetOptions(opts Options) {
+	p.opts = opts
+}
+
+func (p *TusProvider) SettingFields() []SettingField {
+	return []SettingField{
+		{Key: TusEndpoint, Label: "Server URL:", Placeholder: "https://tusd.example.com/files/"},
+		{Key: TusAuthorization, Label: "Authorization header:", Placeholder: "Bearer …", Secret: true},
+		{Key: TusMetadata, Label: "Metadata (key=value per line):", Multiline: true},
+	}
+}
+
+func (p *TusProvider) SetSettings(values map[string]string) {
+	p.endpoint = values[TusEndpoint]
+	p.authorization = values[TusAuthorization]
+	p.metadata = values[TusMetadata]
+}
+
+func (p *TusProvider) ValidateSettings() error {
+	if !isHTTPURL(p.endpoint) {
+		return errors.New("server URL must be an http or https address")
+	}
+	_, err := parseTusMetadata(p.metadata)
+	return err
+}
+
+func (p *TusProvider) ServerURL() string {
+	return p.endpoint
+}
+
+// Probe проверяет, что сервер отвечает
+func (p *TusProvider) Probe(ctx context.Context) error {
+	return probeURL(ctx, p.endpoint)
+}
+
+// Upload создает загрузку на сервере и передает файл PATCH запросами
+// Отмененная загрузка удаляется с сервера, чтобы не занимать на нем место;
+// прерванная выходом из приложения остается, чтобы продолжить ее после перезапуска
+func (p *TusProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- UploadProgress) (_ *UploadResult, err error) {
+	if ctx.Err() != nil {
+		return nil, ErrCancelled
+	}
+
+	location, offset, err := p.start(ctx, filename, fileSize)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ErrCancelled
+		}
+		return nil, err
+	}
+	defer func() {
+		if err != nil && ctx.Err() != nil && !errors.Is(context.Cause(ctx), ErrInterrupted) {
+			p.terminate(ctx, location)
+		}
+	}()
+
+	tracker := newProgressTracker(fileSize, progress)
+	tracker.Add(offset)
+	saved := CheckpointsFrom(ctx)
+	if saved != nil {
+		saved.Save(Checkpoint{Upload: location, Offset: offset})
+	}
+	failures := 0
+
+	for offset < fileSize {
+		size := fileSize - offset
+		if p.opts.ChunkSize > 0 {
+			size = min(size, p.opts.ChunkSize)
+		}
+
+		if err := waitUnpaused(ctx); err != nil {
+			return nil, ErrCancelled
+		}
+
+		var sent int64
+		next, err := p.patch(ctx, location, file, offset, size, func(n int64) {
+			sent += n
+			tracker.Add(n)
+		})
+		if err == nil && next <= offset {
+			// Сервер ничего не сохранил - считаем это ошибкой, иначе цикл повторялся бы бесконечно
+			err = &ServerError{Op: "upload", Message: fmt.Sprintf("Upload-Offset did not advance from %d", offset)}
+		}
+		if err == nil {
+			// Сервер мог сохранить не всю часть - прогресс следует его смещению
+			tracker.Add(next - offset - sent)
+			offset = next
+			failures = 0
+			if saved != nil {
+				saved.Save(Checkpoint{Upload: location, Offset: offset})
+			}
+			continue
+		}
+
+		// Откатываем прогресс неудачного запроса: сервер сообщит, сколько он сохранил
+		tracker.Add(-sent)
+		if ctx.Err() != nil {
+			return nil, ErrCancelled
+		}
+
+		if lost, waitErr := p.waitForNetwork(ctx, err, tracker); waitErr != nil {
+			return nil, waitErr
+		} else if !lost {
+			failures++
+			if failures >= maxPartAttempts {
+				return nil, fmt.Errorf("failed to upload at offset %d: %w (after %d attempts)", offset, err, failures)
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ErrCancelled
+			case <-time.After(partRetryDelay * time.Duration(failures)):
+			}
+		}
+
+		// Продолжаем с того, что сервер уже принял
+		confirmed, err := p.offset(ctx, location)
+		if err != nil {
+			continue
+		}
+		tracker.Add(confirmed - offset)
+		offset = confirmed
+	}
+
+	return &UploadResult{URL: location, DownloadURL: location}, nil
+}
+
+// start продолжает сохраненную загрузку, если сервер ее еще хранит, иначе создает новую
+// Возвращает адрес загрузки и смещение, с которого передавать файл
+func (p *TusProvider) start(ctx context.Context, filename string, fileSize int64) (string, int64, error) {
+	if saved := CheckpointsFrom(ctx); saved != nil {
+		// Загрузка могла истечь на сервере - тогда начинаем заново
+		if from := saved.Load(); from != nil {
+			if offset, err := p.offset(ctx, from.Upload); err == nil && offset <= fileSize {
+				return from.Upload, offset, nil
+			}
+			if ctx.Err() != nil {
+				return "", 0, ctx.Err()
+			}
+		}
+	}
+
+	location, err := p.create(ctx, filename, fileSize)
+	return location, 0, err
+}
+
+// waitForNetwork ждет возвращения связи, если запрос прервался из-за сети
+// Возвращает true, если связь пропадала
+func (p *TusProvider) waitForNetwork(ctx context.Context, err error, tracker *progressTracker) (bool, error) {
+	if p.opts.WaitOnline == nil || !httpclient.IsNetworkError(err) {
+		return false, nil
+	}
+
+	lost, waitErr := p.opts.WaitOnline(ctx, tracker.Offline)
+	if lost && waitErr == nil {
+		tracker.Online()
+	}
+	return lost, waitErr
+}
+
+// create создает загрузку (POST) и возвращает ее адрес
+func (p *TusProvider) create(ctx context.Context, filename string, fileSize int64) (string, error) {
+	metadata, err := parseTusMetadata(p.metadata)
+	if err != nil {
+		return "", err
+	}
+	metadata["filename"] = filename
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Upload-Length", strconv.FormatInt(fileSize, 10))
+	req.Header.Set("Upload-Metadata", encodeTusMetadata(metadata))
+
+	resp, err := p.client(httpclient.Default()).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", statusError("create upload", resp)
+	}
+
+	// Адрес может быть относительным
+	location, err := resp.Location()
+	if err != nil {
+		return "", &ServerError{Op: "create upload", Message: "response has no Location header"}
+	}
+	return location.String(), nil
+}
+
+// patch отправляет часть файла начиная с offset и возвращает новое смещение
+func (p *TusProvider) patch(ctx context.Context, location string, file io.ReadSeeker, offset, size int64, onProgress func(n int64)) (int64, error) {
+	var body io.Reader
+	if readerAt, ok := file.(io.ReaderAt); ok {
+		body = io.NewSectionReader(readerAt, offset, size)
+	} else {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return 0, fmt.Errorf("failed to seek to offset %d: %w", offset, err)
+		}
+		body = io.LimitReader(file, size)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, location, &progressReader{reader: body, onProgress: onProgress})
+	if err != nil {
+		return 0, err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", tusOffsetContentType)
+	req.Header.Set("Upload-Offset", strconv.FormatInt(offset, 10))
+
+	resp, err := p.client(httpclient.LongLived()).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return 0, statusError("upload", resp)
+	}
+	return parseTusOffset(resp)
+}
+
+// offset запрашивает у сервера, сколько байт загрузки уже сохранено (HEAD)
+func (p *TusProvider) offset(ctx context.Context, location string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, location, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := p.client(httpclient.Default()).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return 0, statusError("get upload offset", resp)
+	}
+	return parseTusOffset(resp)
+}
+
+// terminate удаляет незавершенную загрузку с сервера (расширение termination протокола)
+// Вызывается после отмены ctx, поэтому запрос идет с отдельным коротким таймаутом
+// Ошибка не важна: сервер без расширения termination удалит загрузку сам по истечении срока
+func (p *TusProvider) terminate(ctx context.Context, location string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tusTerminateTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, location, nil)
+	if err != nil {
+		return
+	}
+	resp, err := p.client(httpclient.Probe()).Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
+
+// client добавляет к запросам base заголовок протокола и авторизацию
+func (p *TusProvider) client(base *httpclient.Client) *httpclient.Client {
+	return base.With(
+		httpclient.SetHeader("Tus-Resumable", tusVersion),
+		httpclient.SetHeader("Authorization", p.authorization),
+	)
+}
+
+// parseTusOffset читает Upload-Offset из ответа сервера
+func parseTusOffset(resp *http.Response) (int64, error) {
+	offset, err := strconv.ParseInt(resp.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		return 0, &ServerError{Op: "upload", Message: "response has no valid Upload-Offset header"}
+	}
+	return offset, nil
+}
+
+// parseTusMetadata разбирает метаданные из настроек: по паре "ключ=значение" на строку
+func parseTusMetadata(text string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " ,") {
+			return nil, fmt.Errorf("invalid metadata line %q, want key=value", line)
+		}
+		metadata[key] = strings.TrimSpace(value)
+	}
+	return metadata, nil
+}
+
+// encodeTusMetadata кодирует метаданные для Upload-Metadata: "ключ base64(значение)" через запятую
+func encodeTusMetadata(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+" "+base64.StdEncoding.EncodeToString([]byte(metadata[key])))
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,237 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// TestTusResume проверяет метаданные и продолжение загрузки с сохраненного сервером смещения
+func TestTusResume(t *testing.T) {
+	noRetryDelay(t)
+
+	data := make([]byte, 10*1024+123)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	var mu sync.Mutex
+	var stored []byte
+	var metadata, authorization string
+	failed := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Header.Get("Tus-Resumable") != tusVersion {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			metadata = r.Header.Get("Upload-Metadata")
+			authorization = r.Header.Get("Authorization")
+			w.Header().Set("Location", "/files/abc")
+			w.WriteHeader(http.StatusCreated)
+			return
+		case http.MethodPatch:
+			if offset, _ := strconv.Atoi(r.Header.Get("Upload-Offset")); offset != len(stored) {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			if !failed {
+				// Первая часть обрывается: сервер успел сохранить только половину
+				failed = true
+				stored = append(stored, body[:len(body)/2]...)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			stored = append(stored, body...)
+		}
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(stored)))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	p := NewTusProvider()
+	p.SetSettings(map[string]string{
+		TusEndpoint:      server.URL + "/files/",
+		TusAuthorization: "Bearer secret",
+		TusMetadata:      "folder = backups\n\n",
+	})
+	p.SetOptions(Options{ChunkSize: 4096})
+	if err := p.ValidateSettings(); err != nil {
+		t.Fatalf("ValidateSettings() = %v", err)
+	}
+
+	result, err := p.Upload(context.Background(), bytes.NewReader(data), "file.bin", int64(len(data)), make(chan UploadProgress, 100))
+	if err != nil {
+		t.Fatalf("Upload() = %v", err)
+	}
+	if want := server.URL + "/files/abc"; result.URL != want {
+		t.Errorf("URL = %q, want %q", result.URL, want)
+	}
+	if !bytes.Equal(stored, data) {
+		t.Errorf("server got %d bytes, want the %d bytes of the file", len(stored), len(data))
+	}
+	if want := "filename ZmlsZS5iaW4=,folder YmFja3Vwcw=="; metadata != want {
+		t.Errorf("Upload-Metadata = %q, want %q", metadata, want)
+	}
+	if authorization != "Bearer secret" {
+		t.Errorf("Authorization = %q, want Bearer secret", authorization)
+	}
+}
+
+// TestTusStuckOffset проверяет, что PATCH без продвижения Upload-Offset считается неудачной попыткой
+func TestTusStuckOffset(t *testing.T) {
+	noRetryDelay(t)
+
+	var mu sync.Mutex
+	patches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPost:
+			w.Header().Set("Location", "/files/abc")
+			w.WriteHeader(http.StatusCreated)
+			return
+		case http.MethodPatch:
+			io.Copy(io.Discard, r.Body)
+			patches++
+		}
+		w.Header().Set("Upload-Offset", "0")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	p := NewTusProvider()
+	p.SetSettings(map[string]string{TusEndpoint: server.URL + "/files/"})
+	data := make([]byte, 1024)
+	if _, err := p.Upload(context.Background(), bytes.NewReader(data), "file.bin", int64(len(data)), make(chan UploadProgress, 100)); err == nil {
+		t.Fatal("Upload() succeeded, want error")
+	}
+	if patches != maxPartAttempts {
+		t.Errorf("sent %d PATCH requests, want %d", patches, maxPartAttempts)
+	}
+}
+
+// TestTusValidateSettings проверяет проверку адреса сервера и метаданных
+func TestTusValidateSettings(t *testing.T) {
+	for _, tc := range []struct {
+		endpoint, metadata string
+		wantErr            bool
+	}{
+		{"https://tusd.example.com/files/", "", false},
+		{"", "", true},
+		{"ftp://example.com/", "", true},
+		{"https://tusd.example.com/files/", "no separator", true},
+		{"https://tusd.example.com/files/", "bad key=1", true},
+	} {
+		p := NewTusProvider()
+		p.SetSettings(map[string]string{TusEndpoint: tc.endpoint, TusMetadata: tc.metadata})
+		if err := p.ValidateSettings(); (err != nil) != tc.wantErr {
+			t.Errorf("ValidateSettings(%q, %q) = %v, want error %v", tc.endpoint, tc.metadata, err, tc.wantErr)
+		}
+	}
+}
+
+// TestTusTerminateOnCancel проверяет, что отмененная загрузка удаляется с сервера
+func TestTusTerminateOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	deleted := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			w.Header().Set("Location", "/files/abc")
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodPatch:
+			// Пользователь отменяет загрузку, пока идет первая часть
+			cancel()
+			io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+		case http.MethodDelete:
+			deleted <- r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	p := NewTusProvider()
+	p.SetSettings(map[string]string{TusEndpoint: server.URL + "/files/"})
+
+	_, err := p.Upload(ctx, bytes.NewReader(make([]byte, 1024)), "file.bin", 1024, make(chan UploadProgress, 100))
+	if !errors.Is(err, ErrCancelled) {
+		t.Fatalf("Upload() error = %v, want ErrCancelled", err)
+	}
+	select {
+	case path := <-deleted:
+		if path != "/files/abc" {
+			t.Errorf("DELETE %s, want /files/abc", path)
+		}
+	default:
+		t.Error("cancelled upload was not terminated on the server")
+	}
+}
+
+// memCheckpoints хранит точку продолжения загрузки в памяти
+type memCheckpoints struct {
+	last *Checkpoint
+}
+
+func (c *memCheckpoints) Load() *Checkpoint  { return c.last }
+func (c *memCheckpoints) Save(cp Checkpoint) { c.last = &cp }
+
+// TestTusContinueCheckpoint проверяет продолжение загрузки, начатой до перезапуска приложения
+func TestTusContinueCheckpoint(t *testing.T) {
+	data := make([]byte, 8192)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	// Сервер хранит начало файла из прошлого запуска
+	stored := append([]byte(nil), data[:5000]...)
+	created := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			created = true
+			w.WriteHeader(http.StatusCreated)
+			return
+		case http.MethodPatch:
+			body, _ := io.ReadAll(r.Body)
+			stored = append(stored, body...)
+		}
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(stored)))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	p := NewTusProvider()
+	p.SetSettings(map[string]string{TusEndpoint: server.URL + "/files/"})
+
+	saved := &memCheckpoints{last: &Checkpoint{Upload: server.URL + "/files/abc", Offset: 4096}}
+	ctx := WithCheckpoints(context.Background(), saved)
+	if _, err := p.Upload(ctx, bytes.NewReader(data), "file.bin", int64(len(data)), make(chan UploadProgress, 100)); err != nil {
+		t.Fatalf("Upload() = %v", err)
+	}
+	if created {
+		t.Error("new upload created instead of continuing the saved one")
+	}
+	if !bytes.Equal(stored, data) {
+		t.Errorf("server got %d bytes, want the %d bytes of the file", len(stored), len(data))
+	}
+	if want := (Checkpoint{Upload: server.URL + "/files/abc", Offset: int64(len(data))}); *saved.last != want {
+		t.Errorf("last checkpoint = %+v, want %+v", *saved.last, want)
+	}
+}
@@ -0,0 +1,291 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"multiUploader/internal/httpclient"
+)
+
+// xfsRemoteUploadResponse ответ XFileSharing API на /api/upload/url
+type xfsRemoteUploadResponse struct {
+	Status int    `json:"status"`
+	Msg    string `json:"msg"`
+	Result struct {
+		FileCode string `json:"filecode"`
+	} `json:"result"`
+}
+
+// xfsRemoteUpload ставит в очередь remote upload на хостинге XFileSharing
+// Сервер скачивает файл сам; возвращается код файла, ссылка на него доступна сразу
+func xfsRemoteUpload(ctx context.Context, baseURL, apiKey, sourceURL, folderID string) (string, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/api/upload/url")
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{"key": {apiKey}, "url": {sourceURL}}
+	if folderID != "" {
+		query.Set("fld_id", folderID)
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ErrCancelled
+		}
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError("remote upload", resp)
+	}
+
+	var result xfsRemoteUploadResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	if result.Status != 200 || result.Result.FileCode == "" {
+		return "", &ServerError{Op: "remote upload", Message: result.Msg}
+	}
+
+	return result.Result.FileCode, nil
+}
+
+const (
+	// xfsMaxFolderDepth глубина обхода дерева папок
+	xfsMaxFolderDepth = 3
+	// xfsMaxFolders максимальное количество папок в списке
+	xfsMaxFolders = 200
+)
+
+// xfsID идентификатор XFileSharing API: приходит то числом, то строкой
+type xfsID string
+
+func (id *xfsID) UnmarshalJSON(data []byte) error {
+	*id = xfsID(strings.Trim(string(data), `"`))
+	return nil
+}
+
+// xfsFolder папка в ответе XFileSharing API
+type xfsFolder struct {
+	Name  string `json:"name"`
+	FldID xfsID  `json:"fld_id"`
+}
+
+// xfsFolderListResponse ответ XFileSharing API на /api/folder/list
+type xfsFolderListResponse struct {
+	Status int    `json:"status"`
+	Msg    string `json:"msg"`
+	Result struct {
+		Folders []xfsFolder `json:"folders"`
+	} `json:"result"`
+}
+
+// xfsListFolders обходит дерево папок аккаунта в ширину, начиная с корня (fld_id=0)
+func xfsListFolders(ctx context.Context, baseURL, apiKey string) ([]Folder, error) {
+	type pending struct {
+		id    string
+		path  string
+		depth int
+	}
+
+	var folders []Folder
+	queue := []pending{{id: "0"}}
+	for len(queue) > 0 && len(folders) < xfsMaxFolders {
+		parent := queue[0]
+		queue = queue[1:]
+
+		children, err := xfsFolderChildren(ctx, baseURL, apiKey, parent.id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range children {
+			path := child.Name
+			if parent.path != "" {
+				path = parent.path + "/" + child.Name
+			}
+			folders = append(folders, Folder{ID: string(child.FldID), Path: path})
+			if parent.depth+1 < xfsMaxFolderDepth {
+				queue = append(queue, pending{id: string(child.FldID), path: path, depth: parent.depth + 1})
+			}
+		}
+	}
+
+	if len(folders) > xfsMaxFolders {
+		folders = folders[:xfsMaxFolders]
+	}
+	return folders, nil
+}
+
+// xfsFolderChildren возвращает вложенные папки одной папки
+func xfsFolderChildren(ctx context.Context, baseURL, apiKey, folderID string) ([]xfsFolder, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/api/folder/list")
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = url.Values{"key": {apiKey}, "fld_id": {folderID}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ErrCancelled
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("list folders", resp)
+	}
+
+	var result xfsFolderListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse folder list: %w", err)
+	}
+	if result.Status != 200 {
+		return nil, &ServerError{Op: "list folders", Message: result.Msg}
+	}
+
+	return result.Result.Folders, nil
+}
+
+// xfsFolderCreateResponse ответ XFileSharing API на /api/folder/create
+type xfsFolderCreateResponse struct {
+	Status int    `json:"status"`
+	Msg    string `json:"msg"`
+	Result struct {
+		FldID xfsID `json:"fld_id"`
+	} `json:"result"`
+}
+
+// xfsCreateFolder создает папку name внутри parentID ("" - корень) и возвращает ее с публичной ссылкой
+func xfsCreateFolder(ctx context.Context, baseURL, apiKey, parentID, name string) (*Collection, error) {
+	if parentID == "" {
+		parentID = "0"
+	}
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/api/folder/create")
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = url.Values{"key": {apiKey}, "parent_id": {parentID}, "name": {name}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ErrCancelled
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("create folder", resp)
+	}
+
+	var result xfsFolderCreateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse folder: %w", err)
+	}
+	if result.Status != 200 || result.Result.FldID == "" {
+		return nil, &ServerError{Op: "create folder", Message: result.Msg}
+	}
+
+	id := string(result.Result.FldID)
+	return &Collection{
+		ID:  id,
+		URL: strings.TrimSuffix(baseURL, "/") + "/folder/" + id,
+	}, nil
+}
+
+// xfsTimeLayout формат дат XFileSharing API
+const xfsTimeLayout = "2006-01-02 15:04:05"
+
+// xfsAccountInfoResponse ответ XFileSharing API на /api/account/info
+type xfsAccountInfoResponse struct {
+	Status int    `json:"status"`
+	Msg    string `json:"msg"`
+	Result struct {
+		StorageUsed xfsID `json:"storage_used"`
+		StorageLeft xfsID `json:"storage_left"`
+		// Большинство установок XFS отдают поле с опечаткой
+		PremimExpire  string `json:"premim_expire"`
+		PremiumExpire string `json:"premium_expire"`
+	} `json:"result"`
+}
+
+// xfsAccountInfo запрашивает занятое место и срок премиума
+func xfsAccountInfo(ctx context.Context, baseURL, apiKey string) (*AccountInfo, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/api/account/info")
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = url.Values{"key": {apiKey}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ErrCancelled
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("account info", resp)
+	}
+
+	var result xfsAccountInfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse account info: %w", err)
+	}
+	if result.Status != 200 {
+		return nil, &ServerError{Op: "account info", Message: result.Msg}
+	}
+
+	info := &AccountInfo{}
+	info.StorageUsed, _ = strconv.ParseInt(string(result.Result.StorageUsed), 10, 64)
+	// storage_left бывает "inf" у безлимитных аккаунтов - тогда квота неизвестна
+	if left, err := strconv.ParseInt(string(result.Result.StorageLeft), 10, 64); err == nil {
+		info.StorageQuota = info.StorageUsed + left
+	}
+
+	expire := result.Result.PremimExpire
+	if expire == "" {
+		expire = result.Result.PremiumExpire
+	}
+	if t, err := time.ParseInLocation(xfsTimeLayout, expire, time.UTC); err == nil {
+		info.PremiumExpires = t
+		info.Premium = t.After(time.Now())
+	}
+
+	return info, nil
+}
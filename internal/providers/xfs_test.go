@@ -0,0 +1,239 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestXFSRemoteUpload проверяет запрос remote upload к XFileSharing API
+func TestXFSRemoteUpload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/upload/url" {
+			t.Errorf("path = %s, want /api/upload/url", r.URL.Path)
+		}
+		q := r.URL.Query()
+		switch {
+		case q.Get("key") == "":
+			w.WriteHeader(http.StatusForbidden)
+		case q.Get("url") == "https://example.com/bad.zip":
+			w.Write([]byte(`{"status":400,"msg":"Invalid URL"}`))
+		default:
+			w.Write([]byte(`{"status":200,"msg":"OK","result":{"filecode":"abc123"}}`))
+		}
+	}))
+	defer server.Close()
+
+	code, err := xfsRemoteUpload(context.Background(), server.URL+"/", "key", "https://example.com/file.zip", "")
+	if err != nil || code != "abc123" {
+		t.Errorf("xfsRemoteUpload() = %q, %v; want abc123", code, err)
+	}
+
+	_, err = xfsRemoteUpload(context.Background(), server.URL, "key", "https://example.com/bad.zip", "")
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) || serverErr.Message != "Invalid URL" {
+		t.Errorf("error = %v, want ServerError \"Invalid URL\"", err)
+	}
+
+	_, err = xfsRemoteUpload(context.Background(), server.URL, "", "https://example.com/file.zip", "")
+	var authErr *AuthError
+	if !errors.As(err, &authErr) {
+		t.Errorf("error = %v, want AuthError", err)
+	}
+}
+
+// TestXFSListFolders проверяет обход дерева папок
+func TestXFSListFolders(t *testing.T) {
+	// Идентификаторы приходят и числом, и строкой
+	tree := map[string]string{
+		"0":  `[{"name":"Work","fld_id":10},{"name":"Photos","fld_id":"20"}]`,
+		"10": `[{"name":"2024","fld_id":11}]`,
+		"11": `[]`,
+		"20": `[]`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		folders, ok := tree[r.URL.Query().Get("fld_id")]
+		if r.URL.Path != "/api/folder/list" || !ok {
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"status":200,"msg":"OK","result":{"folders":` + folders + `,"files":[]}}`))
+	}))
+	defer server.Close()
+
+	folders, err := xfsListFolders(context.Background(), server.URL, "key")
+	if err != nil {
+		t.Fatalf("xfsListFolders() error = %v", err)
+	}
+
+	want := []Folder{{ID: "10", Path: "Work"}, {ID: "20", Path: "Photos"}, {ID: "11", Path: "Work/2024"}}
+	if len(folders) != len(want) {
+		t.Fatalf("got %v, want %v", folders, want)
+	}
+	for i := range want {
+		if folders[i] != want[i] {
+			t.Errorf("folder %d = %+v, want %+v", i, folders[i], want[i])
+		}
+	}
+}
+
+// TestXFSCreateFolder проверяет создание папки для группы файлов
+func TestXFSCreateFolder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/api/folder/create" || query.Get("parent_id") != "0" || query.Get("name") != "Trip photos" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if query.Get("key") != "key" {
+			w.Write([]byte(`{"status":403,"msg":"Invalid key"}`))
+			return
+		}
+		w.Write([]byte(`{"status":200,"msg":"OK","result":{"fld_id":42}}`))
+	}))
+	defer server.Close()
+
+	collection, err := xfsCreateFolder(context.Background(), server.URL+"/", "key", "", "Trip photos")
+	if err != nil {
+		t.Fatalf("xfsCreateFolder() error = %v", err)
+	}
+	if want := (Collection{ID: "42", URL: server.URL + "/folder/42"}); *collection != want {
+		t.Errorf("collection = %+v, want %+v", *collection, want)
+	}
+
+	_, err = xfsCreateFolder(context.Background(), server.URL, "bad", "", "Trip photos")
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) {
+		t.Errorf("error = %v, want ServerError", err)
+	}
+}
+
+// TestXFSAccountInfo проверяет разбор сведений об аккаунте
+func TestXFSAccountInfo(t *testing.T) {
+	responses := map[string]string{
+		"premium":   `{"status":200,"msg":"OK","result":{"storage_used":"1024","storage_left":3072,"premim_expire":"2999-01-02 03:04:05"}}`,
+		"unlimited": `{"status":200,"msg":"OK","result":{"storage_used":512,"storage_left":"inf","premim_expire":""}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/account/info" {
+			t.Errorf("path = %s, want /api/account/info", r.URL.Path)
+		}
+		response, ok := responses[r.URL.Query().Get("key")]
+		if !ok {
+			w.Write([]byte(`{"status":403,"msg":"Invalid key"}`))
+			return
+		}
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	info, err := xfsAccountInfo(context.Background(), server.URL, "premium")
+	if err != nil {
+		t.Fatalf("xfsAccountInfo() error = %v", err)
+	}
+	wantExpires := time.Date(2999, 1, 2, 3, 4, 5, 0, time.UTC)
+	if info.StorageUsed != 1024 || info.StorageQuota != 4096 || !info.Premium || !info.PremiumExpires.Equal(wantExpires) {
+		t.Errorf("premium account = %+v", info)
+	}
+
+	info, err = xfsAccountInfo(context.Background(), server.URL, "unlimited")
+	if err != nil {
+		t.Fatalf("xfsAccountInfo() error = %v", err)
+	}
+	if info.StorageUsed != 512 || info.StorageQuota != 0 || info.Premium || !info.PremiumExpires.IsZero() {
+		t.Errorf("free account = %+v", info)
+	}
+
+	_, err = xfsAccountInfo(context.Background(), server.URL, "bad")
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) || serverErr.Message != "Invalid key" {
+		t.Errorf("error = %v, want ServerError \"Invalid key\"", err)
+	}
+}
+
+// TestXFSProviderUpload проверяет загрузку на хостинг, заданный только описанием
+func TestXFSProviderUpload(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/upload/server":
+			w.Write([]byte(`{"status":200,"msg":"OK","sess_id":"sess","result":"` + server.URL + `/upload"}`))
+		case "/upload":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("ParseMultipartForm() error = %v", err)
+			}
+			if got := r.FormValue("sess_id") + "," + r.FormValue("utype") + "," + r.FormValue("fld_id"); got != "sess,prem,7" {
+				t.Errorf("form fields = %q, want sess,prem,7", got)
+			}
+			if _, _, err := r.FormFile("file_0"); err != nil {
+				t.Errorf("file field file_0: %v", err)
+			}
+			w.Write([]byte(`[{"file_code":"xyz789","file_status":"OK"}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewXFSProvider(XFSHost{
+		Name:         "Custom",
+		BaseURL:      server.URL + "/",
+		FileField:    "file_0",
+		UploadFields: [][2]string{{"utype", "prem"}},
+	}, "key")
+	provider.SetFolder("7")
+
+	progress := make(chan UploadProgress, 100)
+	go func() {
+		for range progress {
+		}
+	}()
+	result, err := provider.UploadStream(context.Background(), strings.NewReader("data"), "a.txt", 4, progress)
+	close(progress)
+	if err != nil {
+		t.Fatalf("UploadStream() error = %v", err)
+	}
+	if want := server.URL + "/xyz789"; result.URL != want {
+		t.Errorf("URL = %q, want %q", result.URL, want)
+	}
+}
+
+// TestParseXFSHosts проверяет разбор хостингов из настроек
+func TestParseXFSHosts(t *testing.T) {
+	hosts, err := ParseXFSHosts("MyHost = https://myhost.example/\n\n  Other=http://10.0.0.2:8080 file_0  \nBig = https://big.example max=2GB upload\nSmall = https://small.example max=500m")
+	if err != nil {
+		t.Fatalf("ParseXFSHosts() error = %v", err)
+	}
+	want := []XFSHost{
+		{Name: "MyHost", BaseURL: "https://myhost.example"},
+		{Name: "Other", BaseURL: "http://10.0.0.2:8080", FileField: "file_0"},
+		{Name: "Big", BaseURL: "https://big.example", FileField: "upload", MaxFileSize: 2 << 30},
+		{Name: "Small", BaseURL: "https://small.example", MaxFileSize: 500 << 20},
+	}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Errorf("ParseXFSHosts() = %+v, want %+v", hosts, want)
+	}
+
+	for _, text := range []string{
+		"https://myhost.example",
+		"MyHost = ftp://myhost.example",
+		"MyHost = https://a.example\nmyhost = https://b.example",
+		" = https://myhost.example",
+		"MyHost = https://myhost.example file extra",
+		"MyHost = https://myhost.example max=2.5GB",
+		"MyHost = https://myhost.example max=0",
+		"MyHost = https://myhost.example max=10PB",
+		"MyHost = https://myhost.example max=99999999999T",
+	} {
+		if _, err := ParseXFSHosts(text); err == nil {
+			t.Errorf("ParseXFSHosts(%q) error = nil", text)
+		}
+	}
+}
@@ -0,0 +1,435 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"multiUploader/internal/httpclient"
+)
+
+// XFSHost описание файлового хостинга на движке XFileSharing
+// Хостинги отличаются только адресом и именами полей формы загрузки, поэтому новый
+// хостинг добавляется описанием, без отдельного провайдера
+type XFSHost struct {
+	// Name имя провайдера в приложении
+	Name string
+	// BaseURL адрес сайта без завершающего "/" (https://datavaults.co)
+	BaseURL string
+	// FileField имя поля файла в форме загрузки ("" - "file")
+	FileField string
+	// UploadFields дополнительные поля формы загрузки (имя, значение)
+	UploadFields [][2]string
+	// BlockedTypes расширения файлов, которые хостинг отклоняет
+	BlockedTypes []string
+	// MaxFileSize наибольший размер файла в байтах (NoSizeLimit - не опубликован)
+	MaxFileSize int64
+}
+
+// xfsDefaultFileField поле файла в форме загрузки по умолчанию
+const xfsDefaultFileField = "file"
+
+// DataVaultsHost хостинг DataVaults
+var DataVaultsHost = XFSHost{
+	Name:         "DataVaults",
+	BaseURL:      "https://datavaults.co",
+	FileField:    "file_0",
+	UploadFields: [][2]string{{"utype", "prem"}},
+	BlockedTypes: ExecutableTypes,
+	MaxFileSize:  NoSizeLimit,
+}
+
+// FileKeeperHost хостинг FileKeeper.net
+var FileKeeperHost = XFSHost{
+	Name:         "FileKeeper",
+	BaseURL:      "https://filekeeper.net",
+	BlockedTypes: ExecutableTypes,
+	MaxFileSize:  NoSizeLimit,
+}
+
+// XFSProvider провайдер хостинга на XFileSharing
+type XFSProvider struct {
+	host   XFSHost
+	apiKey string
+	// folderID папка аккаунта для загрузки ("" - корень)
+	folderID string
+	opts     Options
+}
+
+// NewXFSProvider создает провайдер хостинга host
+func NewXFSProvider(host XFSHost, apiKey string) *XFSProvider {
+	host.BaseURL = strings.TrimSuffix(host.BaseURL, "/")
+	if host.FileField == "" {
+		host.FileField = xfsDefaultFileField
+	}
+	return &XFSProvider{host: host, apiKey: apiKey}
+}
+
+// NewDataVaultsProvider создает провайдер DataVaults
+func NewDataVaultsProvider(apiKey string) *XFSProvider {
+	return NewXFSProvider(DataVaultsHost, apiKey)
+}
+
+// NewFileKeeperProvider создает провайдер FileKeeper.net
+func NewFileKeeperProvider(apiKey string) *XFSProvider {
+	return NewXFSProvider(FileKeeperHost, apiKey)
+}
+
+// ParseXFSHosts разбирает хостинги из настроек: по одному "Имя = https://адрес [поле файла] [max=размер]" на строку
+func ParseXFSHosts(text string) ([]XFSHost, error) {
+	var hosts []XFSHost
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, rest, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		fields := strings.Fields(rest)
+		if !ok || name == "" || len(fields) == 0 || len(fields) > 3 {
+			return nil, fmt.Errorf("invalid host line %q, expected \"Name = https://host\"", line)
+		}
+		u, err := url.Parse(fields[0])
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("%s: invalid address %q", name, fields[0])
+		}
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("host %s is listed twice", name)
+		}
+		seen[strings.ToLower(name)] = true
+
+		host := XFSHost{Name: name, BaseURL: strings.TrimSuffix(u.String(), "/")}
+		for _, field := range fields[1:] {
+			if size, ok := strings.CutPrefix(field, "max="); ok {
+				if host.MaxFileSize, err = parseXFSSize(size); err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+				continue
+			}
+			if host.FileField != "" {
+				return nil, fmt.Errorf("invalid host line %q, expected \"Name = https://host\"", line)
+			}
+			host.FileField = field
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// xfsSizeUnits множители единиц размера в настройках хостинга (как в FormatSize, по 1024)
+var xfsSizeUnits = map[string]int64{"": 1, "B": 1, "K": 1 << 10, "KB": 1 << 10, "M": 1 << 20, "MB": 1 << 20, "G": 1 << 30, "GB": 1 << 30, "T": 1 << 40, "TB": 1 << 40}
+
+// parseXFSSize разбирает размер вида 500MB или 2G
+func parseXFSSize(text string) (int64, error) {
+	digits := strings.TrimRightFunc(text, func(r rune) bool { return r < '0' || r > '9' })
+	unit, ok := xfsSizeUnits[strings.ToUpper(text[len(digits):])]
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if !ok || err != nil || n <= 0 || n > math.MaxInt64/unit {
+		return 0, fmt.Errorf("invalid size %q, expected a number like 500MB or 2GB", text)
+	}
+	return n * unit, nil
+}
+
+func (p *XFSProvider) Name() string {
+	return p.host.Name
+}
+
+func (p *XFSProvider) RequiresAuth() bool {
+	return true
+}
+
+func (p *XFSProvider) Capabilities() Capabilities {
+	return Capabilities{MaxFileSize: p.host.MaxFileSize, BlockedTypes: p.host.BlockedTypes}
+}
+
+// Probe проверяет доступность сайта хостинга
+func (p *XFSProvider) Probe(ctx context.Context) error {
+	return probeURL(ctx, p.host.BaseURL)
+}
+
+func (p *XFSProvider) ValidateAPIKey(apiKey string) error {
+	if apiKey == "" {
+		return fmt.Errorf("API key is required")
+	}
+	return nil
+}
+
+// Upload загружает файл на хостинг
+func (p *XFSProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
+	return p.UploadStream(ctx, file, filename, fileSize, progress)
+}
+
+// UploadStream загружает поток одной формой multipart - Seek не нужен
+func (p *XFSProvider) UploadStream(ctx context.Context, file io.Reader, filename string, fileSize int64, progress chan<- UploadProgress) (*UploadResult, error) {
+	// 1. Получаем URL сервера для загрузки
+	server, err := p.getUploadServer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upload server: %w", err)
+	}
+
+	// 2. Загружаем файл
+	fileCode, err := p.uploadFile(ctx, server, file, filename, fileSize, progress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	return &UploadResult{
+		URL:    p.fileURL(fileCode),
+		FileID: fileCode,
+	}, nil
+}
+
+// UploadRemote ставит файл по ссылке в очередь remote upload хостинга
+func (p *XFSProvider) UploadRemote(ctx context.Context, sourceURL string) (*UploadResult, error) {
+	fileCode, err := xfsRemoteUpload(ctx, p.host.BaseURL, p.apiKey, sourceURL, p.folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadResult{
+		URL:     p.fileURL(fileCode),
+		FileID:  fileCode,
+		Message: remoteUploadMessage,
+	}, nil
+}
+
+// ListFolders возвращает папки аккаунта
+func (p *XFSProvider) ListFolders(ctx context.Context) ([]Folder, error) {
+	return xfsListFolders(ctx, p.host.BaseURL, p.apiKey)
+}
+
+// SetFolder задает папку для загрузки
+func (p *XFSProvider) SetFolder(id string) {
+	p.folderID = id
+}
+
+// Folder возвращает папку для загрузки
+func (p *XFSProvider) Folder() string {
+	return p.folderID
+}
+
+// SetOptions применяет дополнительные настройки (используется буфер чтения)
+func (p *XFSProvider) SetOptions(opts Options) {
+	p.opts = opts
+}
+
+// CreateCollection создает папку для группы файлов внутри выбранной папки
+func (p *XFSProvider) CreateCollection(ctx context.Context, name string) (*Collection, error) {
+	return xfsCreateFolder(ctx, p.host.BaseURL, p.apiKey, p.folderID, name)
+}
+
+// AccountInfo возвращает занятое место и срок премиума
+func (p *XFSProvider) AccountInfo(ctx context.Context) (*AccountInfo, error) {
+	return xfsAccountInfo(ctx, p.host.BaseURL, p.apiKey)
+}
+
+// fileURL ссылка на страницу файла
+func (p *XFSProvider) fileURL(fileCode string) string {
+	return p.host.BaseURL + "/" + fileCode
+}
+
+// xfsServerResponse ответ /api/upload/server
+type xfsServerResponse struct {
+	Msg    string `json:"msg"`
+	Result string `json:"result"`
+	SessID string `json:"sess_id"`
+	Status int    `json:"status"`
+}
+
+// getUploadServer получает URL сервера для загрузки
+func (p *XFSProvider) getUploadServer(ctx context.Context) (*xfsServerResponse, error) {
+	u, err := url.Parse(p.host.BaseURL + "/api/upload/server")
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = url.Values{"key": {p.apiKey}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("get upload server", resp)
+	}
+
+	var result xfsServerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if result.Status != 200 {
+		return nil, &ServerError{Op: "get upload server", Message: result.Msg}
+	}
+
+	return &result, nil
+}
+
+// xfsUploadResponse элемент ответа сервера загрузки
+type xfsUploadResponse struct {
+	FileCode   string `json:"file_code"`
+	FileStatus string `json:"file_status"`
+}
+
+// uploadFile загружает файл на сервер и возвращает его код
+func (p *XFSProvider) uploadFile(ctx context.Context, server *xfsServerResponse, file io.Reader, filename string, fileSize int64, progress chan<- UploadProgress) (string, error) {
+	contentType, file := streamContentType(file, filename)
+	file = p.opts.readBuffer(file)
+	pipeR, pipeW := io.Pipe()
+	mw := multipart.NewWriter(pipeW)
+
+	var fileSent ByteCounter
+
+	// Горутина для записи multipart данных в pipe
+	go func() {
+		defer func() {
+			_ = mw.Close()
+			_ = pipeW.Close()
+		}()
+
+		// Текстовые поля: сессия, поля хостинга и папка назначения
+		fields := append([][2]string{{"sess_id", server.SessID}}, p.host.UploadFields...)
+		if p.folderID != "" {
+			fields = append(fields, [2]string{"fld_id", p.folderID})
+		}
+		for _, field := range fields {
+			if err := mw.WriteField(field[0], field[1]); err != nil {
+				_ = pipeW.CloseWithError(err)
+				return
+			}
+		}
+
+		// Файл
+		part, err := createFormFile(mw, p.host.FileField, filename, contentType)
+		if err != nil {
+			_ = pipeW.CloseWithError(err)
+			return
+		}
+
+		// Считаем байты файла при чтении
+		cr := CountingReader{
+			r: file,
+			cb: func(n int64) {
+				fileSent.Add(n)
+			},
+		}
+
+		_, err = io.Copy(part, cr)
+		if err != nil {
+			_ = pipeW.CloseWithError(err)
+			return
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.Result, pipeR)
+	if err != nil {
+		_ = pipeR.Close()
+		_ = pipeW.Close()
+		return "", err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	stopProgress := make(chan struct{})
+	defer close(stopProgress)
+
+	// Горутина для отслеживания прогресса
+	go func() {
+		ticker := time.NewTicker(ProgressUpdateInterval)
+		defer ticker.Stop()
+
+		start := time.Now()
+		var lastFile int64
+		var lastT = start
+		var speed float64
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stopProgress:
+				return
+			case <-ticker.C:
+				now := time.Now()
+				fs := fileSent.N()
+
+				dt := now.Sub(lastT).Seconds()
+				df := fs - lastFile
+				if dt > 0 && df > 0 {
+					speed = float64(df) / dt // bytes/sec
+				}
+
+				var pct float64
+				if fileSize > 0 {
+					pct = (float64(fs) / float64(fileSize)) * 100.0
+					if pct > 100 {
+						pct = 100
+					}
+				}
+
+				upd := UploadProgress{
+					BytesUploaded: fs,
+					TotalBytes:    fileSize,
+					Speed:         speed,
+					Percentage:    int(pct),
+				}
+				// Файл отправлен целиком, ждем ответа сервера
+				if fileSize > 0 && fs >= fileSize {
+					upd.Phase = PhaseFinalizing
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-stopProgress:
+					return
+				case progress <- upd:
+				}
+
+				lastFile = fs
+				lastT = now
+			}
+		}
+	}()
+
+	resp, reqErr := httpclient.LongLived().Do(req)
+	_ = pipeR.Close()
+	if reqErr != nil {
+		if errors.Is(reqErr, context.Canceled) {
+			return "", ErrCancelled
+		}
+		return "", reqErr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError("upload", resp)
+	}
+
+	// Ответ - массив с одним элементом
+	var uploadResp []xfsUploadResponse
+	if err := json.NewDecoder(resp.Body).Decode(&uploadResp); err != nil {
+		return "", err
+	}
+
+	if len(uploadResp) == 0 {
+		return "", fmt.Errorf("%s returned empty response", p.host.Name)
+	}
+
+	return uploadResp[0].FileCode, nil
+}
@@ -0,0 +1,118 @@
+// Package queue сохраняет незавершенные загрузки, чтобы предложить продолжить их после перезапуска
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"multiUploader/internal/jsonfile"
+	"multiUploader/internal/upload"
+)
+
+// fileName имя файла очереди в директории состояния приложения
+const fileName = "queue.json"
+
+// Item незавершенная загрузка
+// Указывается FilePath, SourceURL или SourceURI
+type Item struct {
+	FilePath  string `json:"file_path,omitempty"`
+	SourceURL string `json:"source_url,omitempty"`
+	// SourceURI файл хранилища, которое не является локальной файловой системой (MTP устройство, хранилище Fyne)
+	SourceURI string `json:"source_uri,omitempty"`
+	Provider  string `json:"provider"`
+	// HighPriority загрузка выходит из очереди раньше обычных
+	HighPriority bool `json:"high_priority,omitempty"`
+	// SkipScan пользователь решил загрузить файл без проверки на вирусы
+	SkipScan bool `json:"skip_scan,omitempty"`
+	// FolderID папка аккаунта провайдера, выбранная при постановке в очередь ("" - корень или провайдер без папок)
+	FolderID string `json:"folder_id,omitempty"`
+	// Progress снимок идущей загрузки (nil - загрузка не начиналась или начнется заново)
+	Progress *Progress `json:"progress,omitempty"`
+}
+
+// Progress снимок идущей загрузки: с какого места ее можно продолжить после перезапуска
+type Progress struct {
+	// Size и ModTime загружаемого файла: если файл изменился, загрузка начинается заново
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	// Bytes сколько байт было отправлено на момент снимка
+	Bytes int64 `json:"bytes"`
+	// Parts загруженные части разрезанного файла
+	Parts []upload.PartLink `json:"parts,omitempty"`
+	// Checkpoint незавершенная загрузка на сервере провайдера с докачкой (tus)
+	Checkpoint *Checkpoint `json:"checkpoint,omitempty"`
+}
+
+// Checkpoint незавершенная загрузка файла или части Name на сервере
+type Checkpoint struct {
+	Name   string `json:"name"`
+	Upload string `json:"upload"`
+	Offset int64  `json:"offset"`
+}
+
+// Matches сообщает, что снимок сделан для файла с такими размером и временем изменения
+func (p *Progress) Matches(size int64, modTime time.Time) bool {
+	return p.Size == size && p.ModTime.Equal(modTime)
+}
+
+// Store очередь загрузок, сохраняемая в JSON файл
+type Store struct {
+	mu    sync.Mutex
+	path  string
+	items []Item
+}
+
+// DefaultPath возвращает путь к файлу очереди в директории настроек пользователя
+func DefaultPath() (string, error) {
+	return jsonfile.DefaultPath(fileName)
+}
+
+// Open загружает очередь из файла; отсутствующий файл означает пустую очередь
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	if err := jsonfile.Load(path, &s.items, "queue"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewInMemory создает очередь без файла (если файл очереди недоступен)
+func NewInMemory() *Store {
+	return &Store{}
+}
+
+// Items возвращает копию сохраненных загрузок в порядке очереди
+func (s *Store) Items() []Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Item(nil), s.items...)
+}
+
+// Save заменяет очередь и сохраняет файл
+func (s *Store) Save(items []Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items = append([]Item(nil), items...)
+	return s.save()
+}
+
+// save записывает очередь через временный файл, чтобы сбой не испортил ее
+// Пустая очередь удаляет файл
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	if len(s.items) == 0 {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove queue: %w", err)
+		}
+		return nil
+	}
+	return jsonfile.Save(s.path, s.items, "queue")
+}
@@ -0,0 +1,59 @@
+package queue
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// TestStore проверяет сохранение, загрузку и очистку очереди
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", fileName)
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() on missing file error = %v", err)
+	}
+	if len(s.Items()) != 0 {
+		t.Fatalf("new queue has %d items", len(s.Items()))
+	}
+
+	items := []Item{
+		{FilePath: "/tmp/a.bin", Provider: "Rootz"},
+		{SourceURL: "https://example.com/b.bin", Provider: "AkiraBox", HighPriority: true},
+	}
+	if err := s.Save(items); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Перечитываем с диска
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if got := reopened.Items(); !slices.Equal(got, items) {
+		t.Errorf("Items() = %+v, want %+v", got, items)
+	}
+
+	// Пустая очередь не оставляет файла
+	if err := reopened.Save(nil); err != nil {
+		t.Fatalf("Save(nil) error = %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("queue file after Save(nil): %v, want not exist", err)
+	}
+}
+
+// TestOpenCorrupted проверяет, что поврежденный файл не открывается
+func TestOpenCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), fileName)
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); err == nil {
+		t.Error("Open() of corrupted file error = nil")
+	}
+}
@@ -0,0 +1,122 @@
+// Package report собирает итоги нескольких загрузок в готовый для вставки текст по шаблону
+package report
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+// Template шаблон итога: заголовок, строка на каждый файл и окончание
+// В строке подставляются {n}, {file}, {size}, {provider}, {type}, {url}, {download_url} и {delete_url},
+// в заголовке и окончании - {count} и {total_size}
+type Template struct {
+	Header string
+	Row    string
+	Footer string
+	// HTML значения экранируются для вставки в HTML
+	HTML bool
+}
+
+// Preset готовый шаблон
+type Preset struct {
+	// ID ключ шаблона в настройках
+	ID string
+	// Name название для интерфейса (переводится при показе)
+	Name     string
+	Template Template
+}
+
+// Custom ключ шаблона, заданного пользователем
+const Custom = "custom"
+
+// Presets готовые шаблоны в порядке показа; первый используется по умолчанию
+var Presets = []Preset{
+	{ID: "lines", Name: "Plain text", Template: Template{Row: "{file} ({size}): {url}"}},
+	{ID: "markdown", Name: "Markdown list", Template: Template{Row: "- [{file}]({url}) ({size})"}},
+	{ID: "bbcode", Name: "BBCode (forums)", Template: Template{Row: "[url={url}]{file}[/url] ({size})"}},
+	{ID: "html", Name: "HTML table", Template: Template{
+		Header: "<table>\n<tr><th>File</th><th>Size</th><th>Link</th></tr>",
+		Row:    `<tr><td>{file}</td><td>{size}</td><td><a href="{url}">{url}</a></td></tr>`,
+		Footer: "</table>",
+		HTML:   true,
+	}},
+	{ID: "urls", Name: "Links only", Template: Template{Row: "{url}"}},
+}
+
+// PresetByID возвращает готовый шаблон по ключу
+func PresetByID(id string) (Preset, bool) {
+	for _, p := range Presets {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Preset{}, false
+}
+
+// Item загруженный файл
+type Item struct {
+	FileName    string
+	Size        int64 // 0 - неизвестен
+	Provider    string
+	MIMEType    string
+	URL         string
+	DownloadURL string
+	DeleteURL   string
+}
+
+// Render собирает итог по шаблону; formatSize форматирует размеры для показа
+// Пустые строки заголовка и окончания пропускаются
+func Render(t Template, items []Item, formatSize func(int64) string) string {
+	escape := func(s string) string { return s }
+	if t.HTML {
+		escape = html.EscapeString
+	}
+	size := func(n int64) string {
+		if n <= 0 {
+			return "?"
+		}
+		return formatSize(n)
+	}
+
+	var total int64
+	for _, item := range items {
+		total += item.Size
+	}
+	summary := strings.NewReplacer(
+		"{count}", strconv.Itoa(len(items)),
+		"{total_size}", escape(size(total)),
+	)
+
+	var lines []string
+	if t.Header != "" {
+		lines = append(lines, summary.Replace(t.Header))
+	}
+	for i, item := range items {
+		download := item.DownloadURL
+		if download == "" {
+			download = item.URL
+		}
+		lines = append(lines, strings.NewReplacer(
+			"{n}", strconv.Itoa(i+1),
+			"{file}", escape(item.FileName),
+			"{size}", escape(size(item.Size)),
+			"{provider}", escape(item.Provider),
+			"{type}", escape(item.MIMEType),
+			"{url}", escape(item.URL),
+			"{download_url}", escape(download),
+			"{delete_url}", escape(item.DeleteURL),
+		).Replace(t.Row))
+	}
+	if t.Footer != "" {
+		lines = append(lines, summary.Replace(t.Footer))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Sample файлы для предпросмотра шаблона в настройках
+var Sample = []Item{
+	{FileName: "holiday.jpg", Size: 2_457_600, Provider: "Rootz", MIMEType: "image/jpeg", URL: "https://rootz.so/d/a1b2c3"},
+	{FileName: "notes & plans.pdf", Size: 184_320, Provider: "DataVaults", MIMEType: "application/pdf", URL: "https://datavaults.co/x7y8z9",
+		DeleteURL: "https://datavaults.co/del/x7y8z9"},
+}
@@ -0,0 +1,42 @@
+package report
+
+import (
+	"fmt"
+	"testing"
+)
+
+// testSize форматирует размер без локализации
+func testSize(n int64) string { return fmt.Sprintf("%d B", n) }
+
+// TestRender проверяет подстановку значений в строки, заголовок и окончание
+func TestRender(t *testing.T) {
+	items := []Item{
+		{FileName: "a.zip", Size: 10, Provider: "Rootz", URL: "https://example.com/a", DownloadURL: "https://example.com/a/dl"},
+		{FileName: "b.txt", Provider: "FileKeeper", MIMEType: "text/plain", URL: "https://example.com/b"},
+	}
+	tmpl := Template{
+		Header: "{count} files, {total_size}",
+		Row:    "{n}. {file} [{type}] ({size}) {provider}: {url} {download_url}{delete_url} {unknown}",
+	}
+	want := "2 files, 10 B\n" +
+		"1. a.zip [] (10 B) Rootz: https://example.com/a https://example.com/a/dl {unknown}\n" +
+		"2. b.txt [text/plain] (?) FileKeeper: https://example.com/b https://example.com/b {unknown}"
+	if got := Render(tmpl, items, testSize); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+// TestRenderHTML проверяет экранирование значений в HTML шаблоне
+func TestRenderHTML(t *testing.T) {
+	preset, ok := PresetByID("html")
+	if !ok {
+		t.Fatal("html preset is missing")
+	}
+	got := Render(preset.Template, []Item{{FileName: "<b>&.txt", Size: 1, URL: "https://example.com/?a=1&b=2"}}, testSize)
+	want := "<table>\n<tr><th>File</th><th>Size</th><th>Link</th></tr>\n" +
+		`<tr><td>&lt;b&gt;&amp;.txt</td><td>1 B</td><td><a href="https://example.com/?a=1&amp;b=2">https://example.com/?a=1&amp;b=2</a></td></tr>` + "\n" +
+		"</table>"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
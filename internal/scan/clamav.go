@@ -0,0 +1,112 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// clamChunkSize размер куска файла в команде INSTREAM
+const clamChunkSize = 64 * 1024
+
+// ClamAV проверка демоном clamd по команде INSTREAM: файл передается в сокет,
+// поэтому clamd не нужен доступ к файлу на диске
+type ClamAV struct {
+	// Address сокет clamd: путь к unix сокету (/var/run/clamav/clamd.ctl) или host:port
+	Address string
+}
+
+func (c ClamAV) Name() string {
+	return "ClamAV"
+}
+
+// Scan передает файл clamd и разбирает его ответ
+func (c ClamAV) Scan(ctx context.Context, path string) (Result, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer file.Close()
+
+	network, address := clamNetwork(c.Address)
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	// Отмена прерывает передачу и ожидание ответа
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	sendErr := clamSend(conn, file)
+	// clamd закрывает соединение посреди передачи, если файл больше StreamMaxLength,
+	// но перед этим присылает ответ с причиной
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if ctx.Err() != nil {
+		return Result{}, ctx.Err()
+	}
+	if reply == "" && sendErr != nil {
+		return Result{}, fmt.Errorf("failed to send file to clamd: %w", sendErr)
+	}
+	if reply == "" {
+		return Result{}, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return parseClamReply(reply)
+}
+
+// clamSend отправляет команду zINSTREAM и файл кусками с длиной в 4 байтах big-endian
+func clamSend(conn net.Conn, file io.Reader) error {
+	if _, err := io.WriteString(conn, "zINSTREAM\x00"); err != nil {
+		return err
+	}
+	buf := make([]byte, 4+clamChunkSize)
+	for {
+		n, err := file.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf, uint32(n))
+			if _, werr := conn.Write(buf[:4+n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// Кусок нулевой длины завершает поток
+	_, err := conn.Write(make([]byte, 4))
+	return err
+}
+
+// clamNetwork сеть и адрес сокета clamd: путь (или "unix:путь") - unix сокет, иначе TCP
+func clamNetwork(address string) (string, string) {
+	if path, ok := strings.CutPrefix(address, "unix:"); ok {
+		return "unix", path
+	}
+	if strings.HasPrefix(address, "/") {
+		return "unix", address
+	}
+	return "tcp", address
+}
+
+// parseClamReply разбирает ответ clamd: "stream: OK", "stream: <угроза> FOUND" или "... ERROR"
+func parseClamReply(reply string) (Result, error) {
+	reply = strings.TrimRight(reply, "\x00\n")
+	status := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case status == "OK":
+		return Result{}, nil
+	case strings.HasSuffix(status, " FOUND"):
+		return Result{Threat: strings.TrimSuffix(status, " FOUND")}, nil
+	}
+	return Result{}, fmt.Errorf("clamd: %s", reply)
+}
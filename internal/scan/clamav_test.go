@@ -0,0 +1,115 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// eicar тестовая сигнатура EICAR: ее находит любой антивирус
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd принимает одно соединение INSTREAM и отвечает по содержимому файла
+func fakeClamd(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			serveClamd(t, conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveClamd(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	command, err := r.ReadString(0)
+	if err != nil || command != "zINSTREAM\x00" {
+		t.Errorf("command = %q, %v", command, err)
+		return
+	}
+
+	var data bytes.Buffer
+	for {
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			t.Errorf("read chunk size: %v", err)
+			return
+		}
+		if size == 0 {
+			break
+		}
+		if _, err := io.CopyN(&data, r, int64(size)); err != nil {
+			t.Errorf("read chunk: %v", err)
+			return
+		}
+	}
+
+	switch {
+	case strings.Contains(data.String(), "EICAR"):
+		io.WriteString(conn, "stream: Win.Test.EICAR_HDB-1 FOUND\x00")
+	case data.Len() > 100*1024:
+		io.WriteString(conn, "INSTREAM size limit exceeded. ERROR\x00")
+	default:
+		io.WriteString(conn, "stream: OK\x00")
+	}
+}
+
+// TestClamAVScan проверяет передачу файла кусками и разбор ответов clamd
+func TestClamAVScan(t *testing.T) {
+	scanner := ClamAV{Address: fakeClamd(t)}
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	result, err := scanner.Scan(context.Background(), write("eicar.com", []byte(eicar)))
+	if err != nil || result.Threat != "Win.Test.EICAR_HDB-1" {
+		t.Errorf("Scan(eicar) = %+v, %v, want the EICAR threat", result, err)
+	}
+
+	// Больше одного куска INSTREAM
+	result, err = scanner.Scan(context.Background(), write("clean.bin", make([]byte, clamChunkSize+10)))
+	if err != nil || result.Threat != "" {
+		t.Errorf("Scan(clean) = %+v, %v, want no threat", result, err)
+	}
+
+	if _, err := scanner.Scan(context.Background(), write("large.bin", make([]byte, 200*1024))); err == nil || !strings.Contains(err.Error(), "size limit") {
+		t.Errorf("Scan(large) error = %v, want the clamd error", err)
+	}
+}
+
+func TestClamNetwork(t *testing.T) {
+	tests := []struct {
+		address, network, want string
+	}{
+		{"/var/run/clamav/clamd.ctl", "unix", "/var/run/clamav/clamd.ctl"},
+		{"unix:clamd.sock", "unix", "clamd.sock"},
+		{"127.0.0.1:3310", "tcp", "127.0.0.1:3310"},
+	}
+	for _, tt := range tests {
+		if network, address := clamNetwork(tt.address); network != tt.network || address != tt.want {
+			t.Errorf("clamNetwork(%q) = %s %s, want %s %s", tt.address, network, address, tt.network, tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Package scan проверяет файлы на вирусы перед загрузкой: локальным ClamAV (clamd)
+// или поиском хеша файла в VirusTotal
+package scan
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+)
+
+// Scanner антивирус, которым файл проверяется перед загрузкой
+type Scanner interface {
+	// Name название антивируса для сообщений и журнала
+	Name() string
+
+	// Scan проверяет файл path
+	Scan(ctx context.Context, path string) (Result, error)
+}
+
+// Result итог проверки файла
+type Result struct {
+	// Threat название найденной угрозы ("" - угроз не найдено)
+	Threat string
+
+	// Unknown антивирус ничего не знает о файле (VirusTotal не видел такой хеш)
+	Unknown bool
+}
+
+// InfectedError файл не загружается: антивирус нашел в нем угрозу
+type InfectedError struct {
+	Scanner string
+	Path    string
+	Threat  string
+}
+
+func (e *InfectedError) Error() string {
+	return fmt.Sprintf("%s found %s in %s", e.Scanner, e.Threat, filepath.Base(e.Path))
+}
+
+// FailedError файл не загружается: проверить его не удалось
+type FailedError struct {
+	Scanner string
+	Err     error
+}
+
+func (e *FailedError) Error() string {
+	return fmt.Sprintf("%s scan failed: %v", e.Scanner, e.Err)
+}
+
+func (e *FailedError) Unwrap() error { return e.Err }
@@ -0,0 +1,103 @@
+package scan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"multiUploader/internal/httpclient"
+)
+
+// virusTotalURL адрес API VirusTotal (переопределяется в тестах)
+var virusTotalURL = "https://www.virustotal.com/api/v3"
+
+// VirusTotal поиск SHA-256 файла в базе VirusTotal: сам файл никуда не отправляется,
+// поэтому о файле, которого VirusTotal еще не видел, он ничего не скажет
+type VirusTotal struct {
+	APIKey string
+}
+
+func (v VirusTotal) Name() string {
+	return "VirusTotal"
+}
+
+// virusTotalReport нужная часть отчета /files/{hash}
+type virusTotalReport struct {
+	Data struct {
+		Attributes struct {
+			Stats struct {
+				Malicious int `json:"malicious"`
+			} `json:"last_analysis_stats"`
+			Classification struct {
+				Label string `json:"suggested_threat_label"`
+			} `json:"popular_threat_classification"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// Scan считает SHA-256 файла и запрашивает отчет о нем
+func (v VirusTotal) Scan(ctx context.Context, path string) (Result, error) {
+	sum, err := fileSHA256(path)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// Заголовки провайдера загрузки не уходят в VirusTotal
+	req, err := http.NewRequestWithContext(httpclient.WithProvider(ctx, ""), http.MethodGet, virusTotalURL+"/files/"+sum, nil)
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("x-apikey", v.APIKey)
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return Result{Unknown: true}, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return Result{}, fmt.Errorf("VirusTotal rejected the API key")
+	case http.StatusTooManyRequests:
+		return Result{}, fmt.Errorf("VirusTotal request quota exceeded")
+	default:
+		return Result{}, fmt.Errorf("VirusTotal returned status %d", resp.StatusCode)
+	}
+
+	var report virusTotalReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return Result{}, fmt.Errorf("failed to parse VirusTotal report: %w", err)
+	}
+	attrs := report.Data.Attributes
+	if attrs.Stats.Malicious == 0 {
+		return Result{}, nil
+	}
+	threat := attrs.Classification.Label
+	if threat == "" {
+		threat = "malware"
+	}
+	return Result{Threat: fmt.Sprintf("%s (%d engines)", threat, attrs.Stats.Malicious)}, nil
+}
+
+// fileSHA256 SHA-256 файла в hex
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
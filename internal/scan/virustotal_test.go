@@ -0,0 +1,62 @@
+package scan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"multiUploader/internal/httpclient"
+)
+
+// TestVirusTotalScan проверяет поиск по SHA-256 файла и разбор отчета
+func TestVirusTotalScan(t *testing.T) {
+	httpclient.EnableDryRun(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-apikey") != "vt-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch strings.TrimPrefix(r.URL.Path, "/api/v3/files/") {
+		case sha256Hex("bad"):
+			fmt.Fprint(w, `{"data":{"attributes":{"last_analysis_stats":{"malicious":12,"undetected":50},"popular_threat_classification":{"suggested_threat_label":"trojan.agent/generic"}}}}`)
+		case sha256Hex("clean"):
+			fmt.Fprint(w, `{"data":{"attributes":{"last_analysis_stats":{"malicious":0,"undetected":70}}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(httpclient.DisableDryRun)
+
+	dir := t.TempDir()
+	scanFile := func(key, content string) (Result, error) {
+		path := filepath.Join(dir, content+".bin")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return VirusTotal{APIKey: key}.Scan(context.Background(), path)
+	}
+
+	if result, err := scanFile("vt-key", "bad"); err != nil || result.Threat != "trojan.agent/generic (12 engines)" {
+		t.Errorf("Scan(bad) = %+v, %v", result, err)
+	}
+	if result, err := scanFile("vt-key", "clean"); err != nil || result != (Result{}) {
+		t.Errorf("Scan(clean) = %+v, %v, want no threat", result, err)
+	}
+	if result, err := scanFile("vt-key", "new"); err != nil || !result.Unknown {
+		t.Errorf("Scan(new) = %+v, %v, want unknown file", result, err)
+	}
+	if _, err := scanFile("wrong", "clean"); err == nil {
+		t.Error("Scan() with a rejected key succeeded")
+	}
+}
+
+// sha256Hex SHA-256 строки в hex
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,150 @@
+// Package share публикует результат загрузки в чаты через входящие webhook (Discord, Slack)
+package share
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"multiUploader/internal/httpclient"
+)
+
+// Target чат, куда публикуется результат
+type Target string
+
+const (
+	// Discord webhook канала Discord
+	Discord Target = "discord"
+	// Slack incoming webhook Slack
+	Slack Target = "slack"
+)
+
+// Targets все поддерживаемые чаты в порядке показа
+var Targets = []Target{Discord, Slack}
+
+// discordMaxContent лимит длины сообщения Discord
+const discordMaxContent = 2000
+
+// Title название чата для интерфейса
+func (t Target) Title() string {
+	switch t {
+	case Discord:
+		return "Discord"
+	case Slack:
+		return "Slack"
+	}
+	return string(t)
+}
+
+// DefaultTemplate шаблон сообщения по умолчанию (разметка чата: Discord - Markdown, Slack - mrkdwn)
+func (t Target) DefaultTemplate() string {
+	if t == Slack {
+		return "*{file}* ({size}) uploaded to {provider}\n{url}"
+	}
+	return "**{file}** ({size}) uploaded to {provider}\n{url}"
+}
+
+// Message данные загрузки для шаблона
+type Message struct {
+	FileName    string
+	Size        string // уже отформатированный размер (пусто, если неизвестен)
+	Provider    string
+	URL         string
+	DownloadURL string
+}
+
+// Render подставляет данные загрузки в шаблон
+// Поддерживаются {file}, {size}, {provider}, {url} и {download_url}
+func Render(template string, m Message) string {
+	size := m.Size
+	if size == "" {
+		size = "?"
+	}
+	download := m.DownloadURL
+	if download == "" {
+		download = m.URL
+	}
+	return strings.NewReplacer(
+		"{file}", m.FileName,
+		"{size}", size,
+		"{provider}", m.Provider,
+		"{url}", m.URL,
+		"{download_url}", download,
+	).Replace(template)
+}
+
+// ValidateWebhook проверяет, что адрес похож на webhook выбранного чата
+func ValidateWebhook(target Target, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" {
+		return errors.New("webhook URL must be an https address")
+	}
+	switch target {
+	case Discord:
+		if (u.Host != "discord.com" && u.Host != "discordapp.com" && !strings.HasSuffix(u.Host, ".discord.com")) ||
+			!strings.HasPrefix(u.Path, "/api/webhooks/") {
+			return errors.New("not a Discord webhook URL (https://discord.com/api/webhooks/...)")
+		}
+	case Slack:
+		if u.Host != "hooks.slack.com" {
+			return errors.New("not a Slack webhook URL (https://hooks.slack.com/...)")
+		}
+	}
+	return nil
+}
+
+// Post публикует текст через webhook
+func Post(ctx context.Context, target Target, webhook, text string) error {
+	var payload any
+	switch target {
+	case Discord:
+		payload = map[string]any{"content": truncate(text, discordMaxContent)}
+	case Slack:
+		payload = map[string]any{"text": text}
+	default:
+		return fmt.Errorf("unknown share target %q", target)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		// Адрес webhook - секрет, в тексте ошибки его быть не должно
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("post to %s: %w", target.Title(), urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("%s webhook returned status %d: %s", target.Title(), resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// truncate обрезает текст до limit символов
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,70 @@
+package share
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRender проверяет подстановку данных загрузки в шаблон
+func TestRender(t *testing.T) {
+	m := Message{FileName: "a.zip", Size: "1.5 MB", Provider: "Rootz", URL: "https://example.com/a"}
+	got := Render("{file} ({size}) -> {provider}: {url} / {download_url} {unknown}", m)
+	if want := "a.zip (1.5 MB) -> Rootz: https://example.com/a / https://example.com/a {unknown}"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+// TestValidateWebhook проверяет распознавание адресов webhook
+func TestValidateWebhook(t *testing.T) {
+	for _, tc := range []struct {
+		target  Target
+		url     string
+		wantErr bool
+	}{
+		{Discord, "https://discord.com/api/webhooks/1/abc", false},
+		{Discord, "https://ptb.discord.com/api/webhooks/1/abc", false},
+		{Discord, "http://discord.com/api/webhooks/1/abc", true},
+		{Discord, "https://hooks.slack.com/services/T/B/x", true},
+		{Slack, "https://hooks.slack.com/services/T/B/x", false},
+		{Slack, "https://example.com/hook", true},
+	} {
+		if err := ValidateWebhook(tc.target, tc.url); (err != nil) != tc.wantErr {
+			t.Errorf("ValidateWebhook(%s, %s) = %v, want error %v", tc.target, tc.url, err, tc.wantErr)
+		}
+	}
+}
+
+// TestPost проверяет формат запроса для каждого чата и разбор ошибок
+func TestPost(t *testing.T) {
+	var got map[string]string
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	if err := Post(context.Background(), Discord, server.URL, "hello"); err != nil || got["content"] != "hello" {
+		t.Errorf("Discord Post() = %v, payload %v", err, got)
+	}
+	status = http.StatusOK
+	if err := Post(context.Background(), Slack, server.URL, "hello"); err != nil || got["text"] != "hello" {
+		t.Errorf("Slack Post() = %v, payload %v", err, got)
+	}
+
+	// Discord не принимает сообщения длиннее 2000 символов
+	Post(context.Background(), Discord, server.URL, strings.Repeat("я", 3000))
+	if n := len([]rune(got["content"])); n != discordMaxContent {
+		t.Errorf("long Discord message has %d characters, want %d", n, discordMaxContent)
+	}
+
+	status = http.StatusNotFound
+	if err := Post(context.Background(), Slack, server.URL, "hello"); err == nil {
+		t.Error("Post() to a missing webhook succeeded")
+	}
+}
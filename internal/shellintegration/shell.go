@@ -0,0 +1,51 @@
+package shellintegration
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// MenuLabel текст пункта контекстного меню файлового менеджера
+	MenuLabel = "Send to multiUploader"
+
+	// appID идентификатор, используемый в именах файлов и ключах реестра
+	appID = "multiUploader"
+)
+
+// ErrUnsupported возвращается на платформах без поддержки интеграции
+var ErrUnsupported = errors.New("shell integration is not supported on this platform")
+
+// Register добавляет пункт "Send to multiUploader" в контекстное меню файлового менеджера
+// и, где ОС это позволяет, регистрирует приложение получателем файлов из других программ
+// Пункт запускает приложение с путями выбранных файлов в аргументах
+func Register() error {
+	exe, err := executablePath()
+	if err != nil {
+		return err
+	}
+	return register(exe)
+}
+
+// Unregister удаляет пункт из контекстного меню
+func Unregister() error {
+	return unregister()
+}
+
+// IsRegistered проверяет, установлен ли пункт контекстного меню
+func IsRegistered() bool {
+	return isRegistered()
+}
+
+// executablePath возвращает абсолютный путь к текущему бинарнику без симлинков
+func executablePath() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return exe, nil
+}
@@ -0,0 +1,181 @@
+//go:build !ios
+
+package shellintegration
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// workflowName имя Quick Action в ~/Library/Services
+const workflowName = MenuLabel + ".workflow"
+
+// infoPlist описание сервиса для Finder (принимает любые файлы)
+const infoPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>NSServices</key>
+	<array>
+		<dict>
+			<key>NSMenuItem</key>
+			<dict>
+				<key>default</key>
+				<string>` + MenuLabel + `</string>
+			</dict>
+			<key>NSMessage</key>
+			<string>runWorkflowAsService</string>
+			<key>NSRequiredContext</key>
+			<dict>
+				<key>NSApplicationIdentifier</key>
+				<string>com.apple.finder</string>
+			</dict>
+			<key>NSSendFileTypes</key>
+			<array>
+				<string>public.item</string>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`
+
+// documentWflow workflow Automator с одним действием "Run Shell Script"
+// Файлы передаются скрипту как аргументы
+const documentWflow = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>AMApplicationBuild</key>
+	<string>523</string>
+	<key>AMApplicationVersion</key>
+	<string>2.10</string>
+	<key>AMDocumentVersion</key>
+	<string>2</string>
+	<key>actions</key>
+	<array>
+		<dict>
+			<key>action</key>
+			<dict>
+				<key>AMAccepts</key>
+				<dict>
+					<key>Container</key>
+					<string>List</string>
+					<key>Optional</key>
+					<true/>
+					<key>Types</key>
+					<array>
+						<string>com.apple.cocoa.string</string>
+					</array>
+				</dict>
+				<key>AMActionVersion</key>
+				<string>2.0.3</string>
+				<key>AMParameterProperties</key>
+				<dict>
+					<key>COMMAND_STRING</key>
+					<dict/>
+					<key>inputMethod</key>
+					<dict/>
+					<key>shell</key>
+					<dict/>
+				</dict>
+				<key>ActionBundlePath</key>
+				<string>/System/Library/Automator/Run Shell Script.action</string>
+				<key>ActionName</key>
+				<string>Run Shell Script</string>
+				<key>ActionParameters</key>
+				<dict>
+					<key>COMMAND_STRING</key>
+					<string>%s "$@" &gt;/dev/null 2&gt;&amp;1 &amp;</string>
+					<key>CheckedForUserDefaultShell</key>
+					<true/>
+					<key>inputMethod</key>
+					<integer>1</integer>
+					<key>shell</key>
+					<string>/bin/sh</string>
+				</dict>
+				<key>BundleIdentifier</key>
+				<string>com.apple.RunShellScript</string>
+				<key>CFBundleVersion</key>
+				<string>2.0.3</string>
+				<key>Class Name</key>
+				<string>RunShellScriptAction</string>
+			</dict>
+		</dict>
+	</array>
+	<key>workflowMetaData</key>
+	<dict>
+		<key>serviceInputTypeIdentifier</key>
+		<string>com.apple.Automator.fileSystemObject</string>
+		<key>serviceOutputTypeIdentifier</key>
+		<string>com.apple.Automator.nothing</string>
+		<key>serviceProcessesInput</key>
+		<integer>0</integer>
+		<key>workflowTypeIdentifier</key>
+		<string>com.apple.Automator.servicesMenu</string>
+	</dict>
+</dict>
+</plist>
+`
+
+// workflowPath возвращает путь к Quick Action
+func workflowPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "Services", workflowName), nil
+}
+
+func register(exe string) error {
+	path, err := workflowPath()
+	if err != nil {
+		return err
+	}
+
+	contents := filepath.Join(path, "Contents")
+	if err := os.MkdirAll(contents, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", contents, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(contents, "Info.plist"), []byte(infoPlist), 0644); err != nil {
+		return fmt.Errorf("failed to write Info.plist: %w", err)
+	}
+
+	// Путь к бинарнику в одинарных кавычках для shell, с XML-экранированием
+	quoted := "'" + strings.ReplaceAll(exe, "'", `'\''`) + "'"
+	quoted = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(quoted)
+	wflow := fmt.Sprintf(documentWflow, quoted)
+	if err := os.WriteFile(filepath.Join(contents, "document.wflow"), []byte(wflow), 0644); err != nil {
+		return fmt.Errorf("failed to write document.wflow: %w", err)
+	}
+
+	// Просим систему перечитать список сервисов (ошибку игнорируем - подхватится после перелогина)
+	_ = exec.Command("/System/Library/CoreServices/pbs", "-update").Run()
+
+	return nil
+}
+
+func unregister() error {
+	path, err := workflowPath()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	_ = exec.Command("/System/Library/CoreServices/pbs", "-update").Run()
+	return nil
+}
+
+func isRegistered() bool {
+	path, err := workflowPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,125 @@
+//go:build !android
+
+package shellintegration
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// desktopFileName имя .desktop файла (пункт "Открыть с помощью" и действие в меню приложений)
+const desktopFileName = "multiuploader-send.desktop"
+
+// desktopEntry .desktop файл с действием для файловых менеджеров freedesktop
+// MimeType делает приложение обработчиком любых файлов (все типы наследуют application/octet-stream
+// или text/plain): его предлагает выбор приложения xdg-desktop-portal, через который файл отдают
+// другие программы, в том числе из Flatpak и Snap. Скрытые (NoDisplay) приложения в этом выборе не показываются
+const desktopEntry = `[Desktop Entry]
+Type=Application
+Name=` + MenuLabel + `
+Exec="%[1]s" %%F
+MimeType=application/octet-stream;text/plain;
+Terminal=false
+Actions=send;
+
+[Desktop Action send]
+Name=` + MenuLabel + `
+Exec="%[1]s" %%F
+`
+
+// kdeServiceMenu пункт контекстного меню Dolphin
+const kdeServiceMenu = `[Desktop Entry]
+Type=Service
+MimeType=all/allfiles;
+X-KDE-ServiceTypes=KonqPopupMenu/Plugin
+Actions=send;
+
+[Desktop Action send]
+Name=` + MenuLabel + `
+Exec="%[1]s" %%F
+`
+
+// nautilusScript скрипт для меню "Scripts" в Nautilus
+const nautilusScript = `#!/bin/sh
+# Sends the selected files to multiUploader
+exec "%[1]s" "$@"
+`
+
+// integrationFile файл интеграции с шаблоном содержимого
+type integrationFile struct {
+	path     string
+	template string
+	mode     os.FileMode
+}
+
+// integrationFiles возвращает файлы интеграции для разных файловых менеджеров
+func integrationFiles() ([]integrationFile, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+
+	return []integrationFile{
+		{filepath.Join(dataHome, "applications", desktopFileName), desktopEntry, 0644},
+		{filepath.Join(dataHome, "kio", "servicemenus", desktopFileName), kdeServiceMenu, 0755},
+		{filepath.Join(dataHome, "nautilus", "scripts", MenuLabel), nautilusScript, 0755},
+	}, nil
+}
+
+func register(exe string) error {
+	files, err := integrationFiles()
+	if err != nil {
+		return err
+	}
+
+	// Экранируем кавычки в пути для Exec= строки
+	exe = strings.ReplaceAll(exe, `"`, `\"`)
+
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(f.path), err)
+		}
+		if err := os.WriteFile(f.path, []byte(fmt.Sprintf(f.template, exe)), f.mode); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.path, err)
+		}
+	}
+	updateDesktopDatabase(filepath.Dir(files[0].path))
+	return nil
+}
+
+func unregister() error {
+	files, err := integrationFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", f.path, err)
+		}
+	}
+	updateDesktopDatabase(filepath.Dir(files[0].path))
+	return nil
+}
+
+// updateDesktopDatabase обновляет кеш обработчиков типов файлов, по которому выбор приложения находит
+// обработчики (ошибку игнорируем: без утилиты окружения читают .desktop файлы сами)
+func updateDesktopDatabase(dir string) {
+	_ = exec.Command("update-desktop-database", dir).Run()
+}
+
+func isRegistered() bool {
+	files, err := integrationFiles()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(files[0].path)
+	return err == nil
+}
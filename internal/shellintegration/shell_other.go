@@ -0,0 +1,15 @@
+//go:build (!windows && !darwin && !linux) || android || ios
+
+package shellintegration
+
+func register(exe string) error {
+	return ErrUnsupported
+}
+
+func unregister() error {
+	return ErrUnsupported
+}
+
+func isRegistered() bool {
+	return false
+}
@@ -0,0 +1,89 @@
+package shellintegration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Ключ реестра для пункта меню всех файлов текущего пользователя (не требует прав администратора)
+const registryKey = `HKCU\Software\Classes\*\shell\` + appID
+
+// Регистрация приложения в "Открыть с помощью": через этот список файл отдают Explorer и другие программы
+// Панель "Поделиться" Windows принимает только упакованные (MSIX) приложения, поэтому обычный exe
+// получает файлы от других программ через "Открыть с помощью" и "Отправить"
+const (
+	applicationKey = `HKCU\Software\Classes\Applications\` + appID + `.exe`
+	openWithKey    = `HKCU\Software\Classes\*\OpenWithList\` + appID + `.exe`
+)
+
+// shortcutScript создает ярлык в папке "Отправить" (пути передаются через окружение, без экранирования)
+const shortcutScript = `$s = (New-Object -ComObject WScript.Shell).CreateShortcut($env:MULTIUPLOADER_SHORTCUT)
+$s.TargetPath = $env:MULTIUPLOADER_EXE
+$s.Save()`
+
+// register создает ключи реестра Explorer через reg.exe и ярлык в "Отправить"
+func register(exe string) error {
+	command := fmt.Sprintf(`"%s" "%%1"`, exe)
+	commands := [][]string{
+		{"add", registryKey, "/ve", "/d", MenuLabel, "/f"},
+		{"add", registryKey, "/v", "Icon", "/d", exe, "/f"},
+		{"add", registryKey + `\command`, "/ve", "/d", command, "/f"},
+		{"add", applicationKey, "/v", "FriendlyAppName", "/d", appID, "/f"},
+		{"add", applicationKey + `\shell\open\command`, "/ve", "/d", command, "/f"},
+		{"add", openWithKey, "/f"},
+	}
+
+	for _, args := range commands {
+		if out, err := exec.Command("reg", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("reg %s failed: %w: %s", args[0], err, out)
+		}
+	}
+
+	shortcut, err := sendToShortcut()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", shortcutScript)
+	cmd.Env = append(os.Environ(), "MULTIUPLOADER_SHORTCUT="+shortcut, "MULTIUPLOADER_EXE="+exe)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create %s: %w: %s", shortcut, err, out)
+	}
+	return nil
+}
+
+// unregister удаляет ключи реестра вместе с подключами и ярлык в "Отправить"
+func unregister() error {
+	for _, key := range []string{registryKey, applicationKey, openWithKey} {
+		if exec.Command("reg", "query", key).Run() != nil {
+			continue
+		}
+		if out, err := exec.Command("reg", "delete", key, "/f").CombinedOutput(); err != nil {
+			return fmt.Errorf("reg delete failed: %w: %s", err, out)
+		}
+	}
+
+	shortcut, err := sendToShortcut()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(shortcut); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", shortcut, err)
+	}
+	return nil
+}
+
+func isRegistered() bool {
+	return exec.Command("reg", "query", registryKey).Run() == nil
+}
+
+// sendToShortcut путь к ярлыку в папке "Отправить" текущего пользователя
+func sendToShortcut() (string, error) {
+	appData := os.Getenv("APPDATA")
+	if appData == "" {
+		return "", errors.New("APPDATA is not set")
+	}
+	return filepath.Join(appData, "Microsoft", "Windows", "SendTo", appID+".lnk"), nil
+}
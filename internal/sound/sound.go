@@ -0,0 +1,125 @@
+package sound
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Event звуковое событие
+type Event int
+
+const (
+	// EventSuccess загрузка завершена успешно
+	EventSuccess Event = iota
+	// EventFailure загрузка завершилась ошибкой
+	EventFailure
+)
+
+const (
+	sampleRate = 22050
+	amplitude  = 0.3
+)
+
+// tone нота звукового сигнала
+type tone struct {
+	frequency float64 // Гц, 0 - пауза
+	duration  float64 // секунды
+}
+
+// melodies короткие сигналы: восходящий для успеха, нисходящий для ошибки
+var melodies = map[Event][]tone{
+	EventSuccess: {{880, 0.12}, {0, 0.03}, {1318.5, 0.18}},
+	EventFailure: {{392, 0.18}, {0, 0.05}, {261.6, 0.3}},
+}
+
+var (
+	cacheMutex sync.Mutex
+	cachedWAV  = make(map[Event]string)
+)
+
+// Play проигрывает звук события в фоне
+// Ошибки воспроизведения игнорируются - звук не должен мешать загрузке
+func Play(event Event) {
+	go func() {
+		_ = play(event)
+	}()
+}
+
+// wavFile возвращает путь к WAV файлу события, создавая его при первом вызове
+func wavFile(event Event) (string, error) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	if path, ok := cachedWAV[event]; ok {
+		return path, nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	dir = filepath.Join(dir, "multiUploader", "sounds")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	name := "success.wav"
+	if event == EventFailure {
+		name = "failure.wav"
+	}
+	path := filepath.Join(dir, name)
+
+	if err := os.WriteFile(path, generateWAV(melodies[event]), 0644); err != nil {
+		return "", err
+	}
+
+	cachedWAV[event] = path
+	return path, nil
+}
+
+// generateWAV синтезирует моно 16-bit PCM WAV из последовательности нот
+func generateWAV(tones []tone) []byte {
+	samples := make([]int16, 0)
+	for _, t := range tones {
+		n := int(t.duration * sampleRate)
+		for i := 0; i < n; i++ {
+			if t.frequency == 0 {
+				samples = append(samples, 0)
+				continue
+			}
+			// Плавное затухание, чтобы не было щелчков на границах нот
+			envelope := 1.0 - float64(i)/float64(n)
+			v := amplitude * envelope * math.Sin(2*math.Pi*t.frequency*float64(i)/sampleRate)
+			samples = append(samples, int16(v*math.MaxInt16))
+		}
+	}
+
+	dataSize := uint32(len(samples) * 2)
+	buf := &bytes.Buffer{}
+
+	// RIFF заголовок
+	buf.WriteString("RIFF")
+	_ = binary.Write(buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVE")
+
+	// fmt chunk: PCM, 1 канал, 16 бит
+	buf.WriteString("fmt ")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(16))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(1))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(1))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(sampleRate))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(sampleRate*2))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(2))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(16))
+
+	// data chunk
+	buf.WriteString("data")
+	_ = binary.Write(buf, binary.LittleEndian, dataSize)
+	_ = binary.Write(buf, binary.LittleEndian, samples)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,13 @@
+package sound
+
+import "os/exec"
+
+// systemSounds системные звуки macOS
+var systemSounds = map[Event]string{
+	EventSuccess: "/System/Library/Sounds/Glass.aiff",
+	EventFailure: "/System/Library/Sounds/Basso.aiff",
+}
+
+func play(event Event) error {
+	return exec.Command("afplay", systemSounds[event]).Run()
+}
@@ -0,0 +1,37 @@
+package sound
+
+import (
+	"os/exec"
+)
+
+// canberraEvents имена звуков из freedesktop sound theme
+var canberraEvents = map[Event]string{
+	EventSuccess: "complete",
+	EventFailure: "dialog-error",
+}
+
+// play пробует звуковую тему рабочего окружения, затем PulseAudio/ALSA со встроенным сигналом
+func play(event Event) error {
+	if path, err := exec.LookPath("canberra-gtk-play"); err == nil {
+		if exec.Command(path, "-i", canberraEvents[event]).Run() == nil {
+			return nil
+		}
+	}
+
+	file, err := wavFile(event)
+	if err != nil {
+		return err
+	}
+
+	for _, player := range []string{"paplay", "pw-play", "aplay"} {
+		if path, err := exec.LookPath(player); err == nil {
+			args := []string{file}
+			if player == "aplay" {
+				args = []string{"-q", file}
+			}
+			return exec.Command(path, args...).Run()
+		}
+	}
+
+	return exec.ErrNotFound
+}
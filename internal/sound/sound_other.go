@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !windows
+
+package sound
+
+import "errors"
+
+func play(event Event) error {
+	return errors.New("sound is not supported on this platform")
+}
@@ -0,0 +1,35 @@
+package sound
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// TestGenerateWAV проверяет заголовок и размер синтезированного WAV
+func TestGenerateWAV(t *testing.T) {
+	for event, tones := range melodies {
+		data := generateWAV(tones)
+
+		if string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+			t.Fatalf("event %d: invalid RIFF header", event)
+		}
+
+		riffSize := binary.LittleEndian.Uint32(data[4:8])
+		if int(riffSize) != len(data)-8 {
+			t.Errorf("event %d: RIFF size = %d, want %d", event, riffSize, len(data)-8)
+		}
+
+		dataSize := binary.LittleEndian.Uint32(data[40:44])
+		if int(dataSize) != len(data)-44 {
+			t.Errorf("event %d: data size = %d, want %d", event, dataSize, len(data)-44)
+		}
+
+		wantSamples := 0
+		for _, tn := range tones {
+			wantSamples += int(tn.duration * sampleRate)
+		}
+		if int(dataSize)/2 != wantSamples {
+			t.Errorf("event %d: %d samples, want %d", event, dataSize/2, wantSamples)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package sound
+
+import (
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// play проигрывает встроенный сигнал через System.Media.SoundPlayer
+func play(event Event) error {
+	file, err := wavFile(event)
+	if err != nil {
+		return err
+	}
+
+	script := "(New-Object Media.SoundPlayer '" + strings.ReplaceAll(file, "'", "''") + "').PlaySync()"
+	cmd := exec.Command("PowerShell", "-NoProfile", "-ExecutionPolicy", "Bypass", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	return cmd.Run()
+}
@@ -0,0 +1,242 @@
+package speedtest
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// blockSize размер случайного блока, из которого собирается тело запроса
+	blockSize = 1024 * 1024
+	// progressInterval как часто сообщается прогресс
+	progressInterval = 250 * time.Millisecond
+)
+
+// ErrInvalidURL адрес не http/https
+var ErrInvalidURL = errors.New("speed test URL must start with http:// or https://")
+
+// Request параметры замера
+type Request struct {
+	URL string
+	// Method PUT или POST
+	Method string
+	// Size сколько байт отправить
+	Size int64
+}
+
+// Progress прогресс отправки
+type Progress struct {
+	Sent  int64
+	Total int64
+	// Speed скорость в байтах/сек с начала отправки тела
+	Speed float64
+}
+
+// Result замер: этапы запроса по отдельности, чтобы отличить медленный канал от медленного сервера
+type Result struct {
+	// Status код ответа (0 - ответа нет)
+	Status int
+	Sent   int64
+
+	DNS     time.Duration // разрешение имени
+	Connect time.Duration // TCP соединение
+	TLS     time.Duration // TLS рукопожатие
+	// Upload от начала отправки тела до его последнего байта
+	Upload time.Duration
+	// Wait от последнего байта тела до ответа сервера
+	Wait  time.Duration
+	Total time.Duration
+}
+
+// Speed скорость отправки тела в байтах/сек
+func (r Result) Speed() float64 {
+	if r.Upload <= 0 {
+		return 0
+	}
+	return float64(r.Sent) / r.Upload.Seconds()
+}
+
+// Validate проверяет параметры замера
+func (r Request) Validate() error {
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	if r.Method != http.MethodPut && r.Method != http.MethodPost {
+		return fmt.Errorf("unsupported method %q", r.Method)
+	}
+	if r.Size <= 0 {
+		return errors.New("size must be positive")
+	}
+	return nil
+}
+
+// Run отправляет Size байт случайных данных на URL и замеряет этапы запроса
+// client == nil - отдельный клиент без общих соединений, чтобы замер включал подключение
+// Ответ не 2xx возвращается как ошибка, но Result при этом заполнен
+func Run(ctx context.Context, client *http.Client, req Request, progress chan<- Progress) (Result, error) {
+	if err := req.Validate(); err != nil {
+		return Result{}, err
+	}
+	if client == nil {
+		client = &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, DisableKeepAlives: true}}
+	}
+
+	var (
+		mu                    sync.Mutex
+		result                Result
+		dnsStart, connStart   time.Time
+		tlsStart, bodyStarted time.Time
+		bodyDone              time.Time
+	)
+
+	body := &generatedBody{remaining: req.Size, block: randomBlock()}
+	body.onRead = func(n int) {
+		now := time.Now()
+		mu.Lock()
+		defer mu.Unlock()
+		if bodyStarted.IsZero() {
+			bodyStarted = now
+		}
+		result.Sent += int64(n)
+		if result.Sent == req.Size {
+			bodyDone = now
+		}
+	}
+
+	// Колбэки трассировки вызываются из горутин транспорта
+	begin := func(at *time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		*at = time.Now()
+	}
+	finish := func(at *time.Time, dst *time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !at.IsZero() {
+			*dst = time.Since(*at)
+		}
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { begin(&dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { finish(&dnsStart, &result.DNS) },
+		ConnectStart:      func(string, string) { begin(&connStart) },
+		ConnectDone:       func(string, string, error) { finish(&connStart, &result.Connect) },
+		TLSHandshakeStart: func() { begin(&tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { finish(&tlsStart, &result.TLS) },
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), req.Method, req.URL, body)
+	if err != nil {
+		return Result{}, err
+	}
+	httpReq.ContentLength = req.Size
+	httpReq.Header.Set("Content-Type", "application/octet-stream")
+
+	// Прогресс сообщается из отдельной горутины, чтобы не тормозить отправку
+	// Run дожидается ее выхода, поэтому после возврата канал можно закрыть
+	progressDone := make(chan struct{})
+	if progress == nil {
+		close(progressDone)
+	} else {
+		go func() {
+			defer close(progressDone)
+			ticker := time.NewTicker(progressInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					mu.Lock()
+					p := Progress{Sent: result.Sent, Total: req.Size}
+					if !bodyStarted.IsZero() {
+						if elapsed := time.Since(bodyStarted).Seconds(); elapsed > 0 {
+							p.Speed = float64(p.Sent) / elapsed
+						}
+					}
+					mu.Unlock()
+					select {
+					case progress <- p:
+					default:
+					}
+				}
+			}
+		}()
+	}
+
+	start := time.Now()
+	resp, err := client.Do(httpReq)
+	responded := time.Now()
+	cancel()
+	<-progressDone
+
+	mu.Lock()
+	defer mu.Unlock()
+	result.Total = responded.Sub(start)
+	if !bodyStarted.IsZero() {
+		end := bodyDone
+		if end.IsZero() {
+			end = responded
+		}
+		result.Upload = end.Sub(bodyStarted)
+		result.Wait = responded.Sub(end)
+	}
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	result.Status = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return result, fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+	return result, nil
+}
+
+// randomBlock блок несжимаемых данных (сжатие по пути не должно завышать скорость)
+func randomBlock() []byte {
+	block := make([]byte, blockSize)
+	for i := 0; i < len(block); i += 8 {
+		v := rand.Uint64()
+		for j := 0; j < 8; j++ {
+			block[i+j] = byte(v >> (8 * j))
+		}
+	}
+	return block
+}
+
+// generatedBody отдает remaining байт, повторяя случайный блок
+type generatedBody struct {
+	remaining int64
+	block     []byte
+	offset    int
+	onRead    func(n int)
+}
+
+func (b *generatedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n := copy(p, b.block[b.offset:])
+	b.offset = (b.offset + n) % len(b.block)
+	b.remaining -= int64(n)
+	b.onRead(n)
+	return n, nil
+}
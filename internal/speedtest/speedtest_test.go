@@ -0,0 +1,77 @@
+package speedtest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	var received int64
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		received, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	const size = 3*blockSize + 123
+	progress := make(chan Progress, 100)
+	result, err := Run(context.Background(), nil, Request{URL: server.URL, Method: http.MethodPut, Size: size}, progress)
+	close(progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if method != http.MethodPut || received != size {
+		t.Errorf("server got %s with %d bytes, want PUT with %d", method, received, size)
+	}
+	if result.Status != http.StatusOK || result.Sent != size {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Upload <= 0 || result.Total < result.Upload || result.Speed() <= 0 {
+		t.Errorf("unexpected timings: %+v", result)
+	}
+	for p := range progress {
+		if p.Total != size || p.Sent > size {
+			t.Errorf("unexpected progress %+v", p)
+		}
+	}
+}
+
+func TestRunErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	result, err := Run(context.Background(), nil, Request{URL: server.URL, Method: http.MethodPost, Size: 1024}, nil)
+	if err == nil {
+		t.Fatal("Run() error = nil for 403 response")
+	}
+	// Замер все равно заполнен - данные ушли, сервер их отверг
+	if result.Status != http.StatusForbidden || result.Sent != 1024 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		req     Request
+		wantErr bool
+	}{
+		{Request{URL: "https://example.com/upload", Method: http.MethodPut, Size: 1}, false},
+		{Request{URL: "ftp://example.com", Method: http.MethodPut, Size: 1}, true},
+		{Request{URL: "https://", Method: http.MethodPut, Size: 1}, true},
+		{Request{URL: "https://example.com", Method: http.MethodGet, Size: 1}, true},
+		{Request{URL: "https://example.com", Method: http.MethodPost, Size: 0}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.req, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"multiUploader/internal/logging"
+)
+
+const (
+	// DefaultEndpoint адрес OTLP/HTTP приемника локального OpenTelemetry Collector
+	DefaultEndpoint = "http://localhost:4318/v1/traces"
+
+	// serviceName имя сервиса в трассах
+	serviceName = "multiUploader"
+
+	// queueSize сколько завершенных span ждут отправки; лишние отбрасываются
+	queueSize = 4096
+	// maxBatch максимум span в одном запросе к приемнику
+	maxBatch = 512
+	// exportTimeout ограничивает один запрос к приемнику
+	exportTimeout = 5 * time.Second
+)
+
+// exportInterval как часто отправляются накопленные span (переопределяется в тестах)
+var exportInterval = 2 * time.Second
+
+// active экспортер включенной трассировки (nil - трассировка выключена)
+var active atomic.Pointer[exporter]
+
+// spanData завершенный span
+type spanData struct {
+	traceID    [16]byte
+	spanID     [8]byte
+	parentID   [8]byte
+	name       string
+	kind       Kind
+	start, end time.Time
+	attrs      []Attr
+	err        string
+}
+
+// exporter отправляет span пачками на OTLP/HTTP приемник в JSON кодировке
+// Использует отдельный http.Client, чтобы собственные запросы не попадали в трассы
+type exporter struct {
+	endpoint string
+	client   *http.Client
+	spans    chan spanData
+	stop     chan struct{}
+	done     chan struct{}
+	dropped  atomic.Int64
+	// failing последняя отправка не удалась (ошибка пишется в лог один раз до успешной отправки)
+	failing bool
+}
+
+// Enable включает трассировку с отправкой на endpoint (полный адрес, например DefaultEndpoint)
+func Enable(endpoint string) {
+	Disable()
+
+	e := &exporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: exportTimeout},
+		spans:    make(chan spanData, queueSize),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	active.Store(e)
+	go e.loop()
+}
+
+// Disable выключает трассировку, отправив накопленные span
+func Disable() {
+	e := active.Swap(nil)
+	if e == nil {
+		return
+	}
+	close(e.stop)
+	<-e.done
+}
+
+// Enabled сообщает, включена ли трассировка
+func Enabled() bool {
+	return active.Load() != nil
+}
+
+// EndpointFromEnv адрес приемника из стандартных переменных OpenTelemetry
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT - полный адрес, OTEL_EXPORTER_OTLP_ENDPOINT - базовый
+func EndpointFromEnv() string {
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	if base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); base != "" {
+		return strings.TrimSuffix(base, "/") + "/v1/traces"
+	}
+	return DefaultEndpoint
+}
+
+// enqueue ставит span в очередь отправки без блокировки
+func (e *exporter) enqueue(data spanData) {
+	select {
+	case e.spans <- data:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+// loop отправляет span пачками, пока трассировка не выключена
+func (e *exporter) loop() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	var batch []spanData
+	for {
+		select {
+		case data := <-e.spans:
+			batch = append(batch, data)
+			if len(batch) >= maxBatch {
+				e.export(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			e.export(batch)
+			batch = nil
+		case <-e.stop:
+			// Забираем то, что уже завершилось, и отправляем последней пачкой
+			for {
+				select {
+				case data := <-e.spans:
+					batch = append(batch, data)
+				default:
+					e.export(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// export отправляет пачку span
+func (e *exporter) export(batch []spanData) {
+	if len(batch) == 0 {
+		return
+	}
+
+	err := e.post(batch)
+	switch {
+	case err != nil && !e.failing:
+		logging.ErrorWithError("Failed to export traces", err, "endpoint", e.endpoint)
+		e.failing = true
+	case err == nil:
+		e.failing = false
+	}
+	if dropped := e.dropped.Swap(0); dropped > 0 {
+		logging.Error("Trace queue overflow, spans dropped", "count", dropped)
+	}
+}
+
+func (e *exporter) post(batch []spanData) error {
+	body, err := json.Marshal(encodeRequest(batch))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Структуры запроса ExportTraceServiceRequest в JSON кодировке OTLP
+// Идентификаторы - hex строки, 64-битные числа - десятичные строки
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttr `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              Kind       `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []otlpAttr `json:"attributes,omitempty"`
+	Status            otlpStatus `json:"status"`
+}
+
+type otlpAttr struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"` // 0 - не задан, 2 - ошибка
+	Message string `json:"message,omitempty"`
+}
+
+// statusError код статуса STATUS_CODE_ERROR
+const statusError = 2
+
+func encodeRequest(batch []spanData) otlpRequest {
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, data := range batch {
+		span := otlpSpan{
+			TraceID:           hex.EncodeToString(data.traceID[:]),
+			SpanID:            hex.EncodeToString(data.spanID[:]),
+			Name:              data.name,
+			Kind:              data.kind,
+			StartTimeUnixNano: strconv.FormatInt(data.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(data.end.UnixNano(), 10),
+			Attributes:        encodeAttrs(data.attrs),
+		}
+		if data.parentID != [8]byte{} {
+			span.ParentSpanID = hex.EncodeToString(data.parentID[:])
+		}
+		if data.err != "" {
+			span.Status = otlpStatus{Code: statusError, Message: data.err}
+		}
+		spans = append(spans, span)
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: encodeAttrs([]Attr{String("service.name", serviceName)})},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: serviceName}, Spans: spans}},
+	}}}
+}
+
+func encodeAttrs(attrs []Attr) []otlpAttr {
+	encoded := make([]otlpAttr, 0, len(attrs))
+	for _, attr := range attrs {
+		value := attr.str
+		if attr.isInt {
+			value = strconv.FormatInt(attr.num, 10)
+			encoded = append(encoded, otlpAttr{Key: attr.Key, Value: otlpValue{IntValue: &value}})
+			continue
+		}
+		encoded = append(encoded, otlpAttr{Key: attr.Key, Value: otlpValue{StringValue: &value}})
+	}
+	return encoded
+}
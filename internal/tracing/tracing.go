@@ -0,0 +1,124 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// Kind тип span в терминах OpenTelemetry
+type Kind int
+
+const (
+	// KindInternal операция внутри приложения
+	KindInternal Kind = 1
+	// KindClient исходящий запрос к серверу
+	KindClient Kind = 3
+)
+
+// Attr атрибут span
+type Attr struct {
+	Key   string
+	str   string
+	num   int64
+	isInt bool
+}
+
+// String строковый атрибут
+func String(key, value string) Attr {
+	return Attr{Key: key, str: value}
+}
+
+// Int числовой атрибут
+func Int(key string, value int64) Attr {
+	return Attr{Key: key, num: value, isInt: true}
+}
+
+// Span отрезок трассы; nil, если трассировка выключена (все методы допускают nil)
+type Span struct {
+	exporter *exporter
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	kind     Kind
+	start    time.Time
+
+	mu    sync.Mutex
+	attrs []Attr
+	ended bool
+}
+
+// spanKey ключ текущего span в context
+type spanKey struct{}
+
+// Start начинает span внутренней операции как дочерний для span из ctx
+// Возвращает ctx с новым span; при выключенной трассировке ctx не меняется, span - nil
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	return start(ctx, name, KindInternal, attrs)
+}
+
+// StartClient начинает span исходящего запроса
+func StartClient(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	return start(ctx, name, KindClient, attrs)
+}
+
+func start(ctx context.Context, name string, kind Kind, attrs []Attr) (context.Context, *Span) {
+	e := active.Load()
+	if e == nil {
+		return ctx, nil
+	}
+
+	s := &Span{exporter: e, name: name, kind: kind, start: time.Now(), attrs: attrs}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent != nil {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+	} else {
+		rand.Read(s.traceID[:])
+	}
+	rand.Read(s.spanID[:])
+
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// SetAttributes добавляет атрибуты к span
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// End завершает span и отдает его на отправку; err != nil отмечает операцию как неудачную
+// Повторные вызовы игнорируются
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	data := spanData{
+		traceID:  s.traceID,
+		spanID:   s.spanID,
+		parentID: s.parentID,
+		name:     s.name,
+		kind:     s.kind,
+		start:    s.start,
+		end:      time.Now(),
+		attrs:    s.attrs,
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		data.err = err.Error()
+	}
+	s.exporter.enqueue(data)
+}
@@ -0,0 +1,125 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// collector принимает экспорт и запоминает полученные span
+type collector struct {
+	mu    sync.Mutex
+	spans []otlpSpan
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var req otlpRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			c.spans = append(c.spans, ss.Spans...)
+		}
+	}
+}
+
+func (c *collector) byName(name string) (otlpSpan, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, span := range c.spans {
+		if span.Name == name {
+			return span, true
+		}
+	}
+	return otlpSpan{}, false
+}
+
+func TestDisabled(t *testing.T) {
+	ctx := context.Background()
+	got, span := Start(ctx, "upload")
+	if span != nil || got != ctx {
+		t.Fatal("Start() with tracing disabled should return the same context and a nil span")
+	}
+	// Методы nil span ничего не делают
+	span.SetAttributes(String("k", "v"))
+	span.End(errors.New("ignored"))
+}
+
+func TestExport(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	Enable(server.URL + "/v1/traces")
+	if !Enabled() {
+		t.Fatal("Enabled() = false after Enable")
+	}
+
+	ctx, root := Start(context.Background(), "upload", String("provider", "Rootz"))
+	_, child := StartClient(ctx, "HTTP PUT", Int("http.response.status_code", 500))
+	child.End(errors.New("HTTP 500"))
+	child.End(nil) // повторное завершение игнорируется
+	root.End(nil)
+
+	Disable()
+	if Enabled() {
+		t.Fatal("Enabled() = true after Disable")
+	}
+
+	rootSpan, ok := c.byName("upload")
+	if !ok {
+		t.Fatal("root span not exported")
+	}
+	childSpan, ok := c.byName("HTTP PUT")
+	if !ok {
+		t.Fatal("child span not exported")
+	}
+	if len(c.spans) != 2 {
+		t.Errorf("exported %d spans, want 2", len(c.spans))
+	}
+
+	if rootSpan.ParentSpanID != "" || rootSpan.Kind != KindInternal || rootSpan.Status.Code != 0 {
+		t.Errorf("unexpected root span: %+v", rootSpan)
+	}
+	if len(rootSpan.TraceID) != 32 || len(rootSpan.SpanID) != 16 {
+		t.Errorf("ids are not hex encoded: %q %q", rootSpan.TraceID, rootSpan.SpanID)
+	}
+	if childSpan.TraceID != rootSpan.TraceID || childSpan.ParentSpanID != rootSpan.SpanID {
+		t.Errorf("child span is not linked to root: %+v", childSpan)
+	}
+	if childSpan.Kind != KindClient || childSpan.Status.Code != statusError || childSpan.Status.Message != "HTTP 500" {
+		t.Errorf("unexpected child span: %+v", childSpan)
+	}
+	if len(childSpan.Attributes) != 1 || childSpan.Attributes[0].Value.IntValue == nil || *childSpan.Attributes[0].Value.IntValue != "500" {
+		t.Errorf("unexpected child attributes: %+v", childSpan.Attributes)
+	}
+}
+
+func TestEndpointFromEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	if got := EndpointFromEnv(); got != DefaultEndpoint {
+		t.Errorf("EndpointFromEnv() = %q, want %q", got, DefaultEndpoint)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318/")
+	if got := EndpointFromEnv(); got != "http://collector:4318/v1/traces" {
+		t.Errorf("EndpointFromEnv() = %q with base endpoint", got)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://traces:9999/custom")
+	if got := EndpointFromEnv(); got != "http://traces:9999/custom" {
+		t.Errorf("EndpointFromEnv() = %q with traces endpoint", got)
+	}
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+	"multiUploader/internal/notify"
+	"multiUploader/internal/sound"
+	"multiUploader/internal/viewmodel"
+)
+
+// onAlbumCompleted сообщает о завершении всех загрузок альбома (вызывается из горутины)
+// Ошибки отдельных файлов уже показаны их уведомлениями
+func (t *UploadTab) onAlbumCompleted(album viewmodel.AlbumResult) {
+	if album.URL == "" {
+		return
+	}
+	t.app.PlaySound(sound.EventSuccess)
+
+	showResult := notify.Action{
+		Label: localization.T("Show result"),
+		Callback: func() {
+			fyne.Do(func() {
+				t.app.ShowWindow()
+				t.albumDialog(album).Show()
+			})
+		},
+	}
+	openLink := notify.Action{Label: localization.T("Open link"), URL: album.URL}
+
+	t.app.SendNotificationWithActions(
+		localization.T("Album uploaded"),
+		fmt.Sprintf(localization.TN("%d files uploaded to %s", album.Uploaded), album.Uploaded, album.Name),
+		&openLink,
+		openLink, showResult,
+	)
+}
+
+// albumDialog создает диалог со ссылкой на папку альбома (вызывается из главного потока)
+func (t *UploadTab) albumDialog(album viewmodel.AlbumResult) dialog.Dialog {
+	title := widget.NewLabel(album.Name)
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	uploaded := widget.NewLabel(fmt.Sprintf(localization.TN("%d files uploaded", album.Uploaded), album.Uploaded))
+	alignLabels(title, uploaded)
+	content := container.NewVBox(title, uploaded)
+	if album.Failed > 0 {
+		failed := widget.NewLabel(fmt.Sprintf(localization.TN("%d files failed", album.Failed), album.Failed))
+		failed.Importance = widget.DangerImportance
+		alignLabels(failed)
+		content.Add(failed)
+	}
+
+	content.Add(widget.NewLabel("")) // пустая строка для отступа
+	content.Add(newURLRow(t.app.MainWindow(), localization.T("Album URL"), album.URL))
+
+	d := dialog.NewCustom(localization.T("Upload Results"), localization.T("Close"), content, t.app.MainWindow())
+	resizeDialog(d, fyne.NewSize(600, 300))
+	return d
+}
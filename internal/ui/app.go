@@ -1,22 +1,35 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	neturl "net/url"
 	"os"
 	"os/exec"
 	"runtime"
+	"slices"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
-	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
 
 	"multiUploader/internal/config"
+	"multiUploader/internal/health"
+	"multiUploader/internal/history"
+	"multiUploader/internal/httpclient"
 	"multiUploader/internal/localization"
 	"multiUploader/internal/logging"
+	"multiUploader/internal/metrics"
+	"multiUploader/internal/notify"
 	"multiUploader/internal/providers"
+	"multiUploader/internal/queue"
+	"multiUploader/internal/sound"
 	"multiUploader/internal/updater"
+	"multiUploader/internal/upload"
 )
 
 const (
@@ -34,8 +47,41 @@ type App struct {
 	mainWindow        fyne.Window
 	config            *config.ConfigManager
 	providerFactories map[string]ProviderFactory
+	providerOrder     []string // имена провайдеров в порядке регистрации
 	uploadTab         *UploadTab
 	settingsTab       *SettingsTab
+	historyTab        *HistoryTab
+	healthIndicator   *HealthIndicator
+	history           *history.Store
+	queue             *queue.Store
+	rateLimiter       *upload.RateLimiter
+	tabs              *container.AppTabs
+	settingsItem      *container.TabItem
+
+	// miniWindow компактное окно вместо главного (nil - мини-режим выключен)
+	miniWindow *miniWindow
+	// speedTestWindow окно замера скорости (nil - закрыто)
+	speedTestWindow fyne.Window
+
+	// mockProviders мок провайдеры и образец провайдера, включенные в меню разработчика
+	mockProviders map[string]bool
+
+	// xfsHosts имена хостингов XFileSharing, добавленных пользователем в настройках
+	xfsHosts []string
+
+	// Файлы, полученные до построения UI (аргументы командной строки)
+	pendingFiles []string
+
+	// systemVariant последний известный вариант темы ОС (для режима auto)
+	systemVariant fyne.ThemeVariant
+
+	// metricsServer endpoint метрик (nil - выключен), metricsPort - порт, на котором он запущен
+	metricsServer *metrics.Server
+	metricsPort   int
+
+	// ctx отменяется при выходе из приложения: фоновые запросы не переживают окно
+	ctx  context.Context
+	stop context.CancelFunc
 }
 
 // NewApp создает новое приложение
@@ -44,19 +90,44 @@ func NewApp(fyneApp fyne.App) *App {
 		fyneApp:           fyneApp,
 		config:            config.NewConfigManager(fyneApp.Preferences()),
 		providerFactories: make(map[string]ProviderFactory),
+		history:           openHistory(),
+		queue:             openQueue(),
+		rateLimiter:       upload.NewRateLimiter(),
+		mockProviders:     make(map[string]bool),
 	}
+	app.ctx, app.stop = context.WithCancel(context.Background())
 
 	app.mainWindow = fyneApp.NewWindow("multiUploader")
 	app.mainWindow.Resize(fyne.NewSize(700, 500))
 
+	// Следим за сменой темы ОС, чтобы режим auto переключался на лету
+	app.systemVariant = fyneApp.Settings().ThemeVariant()
+	fyneApp.Settings().AddListener(app.onSystemSettingsChanged)
+
 	return app
 }
 
 // RegisterProviderFactory регистрирует фабрику провайдера в приложении
+// Повторная регистрация заменяет фабрику, не меняя место провайдера в списках
 func (a *App) RegisterProviderFactory(name string, factory ProviderFactory) {
+	if _, ok := a.providerFactories[name]; !ok {
+		a.providerOrder = append(a.providerOrder, name)
+	}
 	a.providerFactories[name] = factory
 }
 
+// unregisterProviderFactory убирает провайдер из приложения
+func (a *App) unregisterProviderFactory(name string) {
+	delete(a.providerFactories, name)
+	a.providerOrder = slices.DeleteFunc(a.providerOrder, func(n string) bool { return n == name })
+}
+
+// ProviderNames возвращает имена провайдеров в порядке регистрации
+// Этот порядок одинаков при каждом запуске и используется во всех списках провайдеров
+func (a *App) ProviderNames() []string {
+	return slices.Clone(a.providerOrder)
+}
+
 // GetProvider создает и возвращает провайдер с актуальным API ключом из конфига
 func (a *App) GetProvider(name string) (providers.Provider, bool) {
 	factory, ok := a.providerFactories[name]
@@ -64,21 +135,66 @@ func (a *App) GetProvider(name string) (providers.Provider, bool) {
 		return nil, false
 	}
 
-	// Получаем актуальный API ключ из конфига
-	apiKey := a.config.GetProviderAPIKey(name)
+	return a.newProvider(name, factory), true
+}
+
+// backupProvider создает провайдер с запасным API ключом из конфига (false - ключ не задан)
+// Реализует viewmodel.BackupFunc
+func (a *App) backupProvider(name string) (providers.Provider, string, bool) {
+	factory, ok := a.providerFactories[name]
+	apiKey := a.config.GetProviderConfig(name).BackupAPIKey
+	if !ok || apiKey == "" {
+		return nil, "", false
+	}
+	return a.newProviderWithKey(name, factory, apiKey), apiKey, true
+}
 
-	// Создаем провайдер с актуальным ключом
-	return factory(apiKey), true
+// newProvider создает провайдер с актуальным API ключом и дополнительными настройками из конфига
+func (a *App) newProvider(name string, factory ProviderFactory) providers.Provider {
+	return a.newProviderWithKey(name, factory, a.config.GetProviderAPIKey(name))
+}
+
+// newProviderWithKey создает провайдер с ключом apiKey и дополнительными настройками из конфига
+func (a *App) newProviderWithKey(name string, factory ProviderFactory, apiKey string) providers.Provider {
+	providerCfg := a.config.GetProviderConfig(name)
+	provider := factory(apiKey)
+
+	if configurable, ok := provider.(providers.Configurable); ok {
+		global := a.config.GetGlobalConfig()
+		configurable.SetOptions(providers.Options{
+			ChunkSize:          int64(providerCfg.ChunkSizeMB) * 1024 * 1024,
+			MultipartThreshold: int64(providerCfg.MultipartThresholdMB) * 1024 * 1024,
+			RetryStalled:       global.RetryStalled,
+			WaitOnline:         health.WaitOnline,
+			ReadBufferSize:     global.ReadBufferKB * 1024,
+		})
+	}
+	if folders, ok := provider.(providers.FolderProvider); ok {
+		folders.SetFolder(providerCfg.FolderID)
+	}
+	if settings, ok := provider.(providers.SettingsProvider); ok {
+		settings.SetSettings(a.config.GetProviderSettings(name, settingKeys(settings)))
+	}
+
+	return provider
+}
+
+// settingKeys возвращает ключи собственных полей настроек провайдера
+func settingKeys(provider providers.SettingsProvider) []string {
+	fields := provider.SettingFields()
+	keys := make([]string, 0, len(fields))
+	for _, field := range fields {
+		keys = append(keys, field.Key)
+	}
+	return keys
 }
 
 // GetEnabledProviders возвращает список включенных провайдеров с актуальными API ключами
 func (a *App) GetEnabledProviders() []providers.Provider {
 	enabled := make([]providers.Provider, 0)
-	for name, factory := range a.providerFactories {
-		if a.config.IsProviderEnabled(name) {
-			apiKey := a.config.GetProviderAPIKey(name)
-			provider := factory(apiKey)
-			enabled = append(enabled, provider)
+	for _, name := range a.providerOrder {
+		if a.config.IsProviderEnabled(name) || a.mockProviders[name] {
+			enabled = append(enabled, a.newProvider(name, a.providerFactories[name]))
 		}
 	}
 	return enabled
@@ -86,21 +202,80 @@ func (a *App) GetEnabledProviders() []providers.Provider {
 
 // Build создает UI приложения
 func (a *App) Build() {
-	// Создаем меню
-	a.mainWindow.SetMainMenu(a.buildMenu())
-
 	// Создаем вкладки
 	a.uploadTab = NewUploadTab(a)
 	a.settingsTab = NewSettingsTab(a)
+	a.historyTab = NewHistoryTab(a)
+	a.healthIndicator = NewHealthIndicator(a)
+
+	a.buildContent()
+	a.healthIndicator.Start()
+	a.startExpiryReminders()
+	a.mainWindow.SetCloseIntercept(a.quit)
+
+	// Применяем файлы, полученные до построения UI
+	if len(a.pendingFiles) > 0 {
+		a.uploadTab.SelectFiles(a.pendingFiles)
+		a.pendingFiles = nil
+	}
+}
+
+// Rebuild пересоздает меню и содержимое вкладок с текущим языком
+// Состояние вкладок (выбранный файл, идущая загрузка) сохраняется
+func (a *App) Rebuild() {
+	a.buildContent()
+}
+
+// buildContent создает меню и контейнер с вкладками
+func (a *App) buildContent() {
+	// Создаем меню
+	a.mainWindow.SetMainMenu(a.buildMenu())
+
+	// Запоминаем открытую вкладку, чтобы не сбрасывать ее при пересоздании
+	selected := 0
+	if a.tabs != nil {
+		selected = a.tabs.SelectedIndex()
+	}
 
 	// Создаем контейнер с вкладками
-	tabs := container.NewAppTabs(
+	a.settingsItem = container.NewTabItem(localization.T("Settings"), a.settingsTab.Build())
+	a.tabs = container.NewAppTabs(
 		container.NewTabItem(localization.T("Upload"), a.uploadTab.Build()),
-		container.NewTabItem(localization.T("Settings"), a.settingsTab.Build()),
+		container.NewTabItem(localization.T("History"), a.historyTab.Build()),
+		a.settingsItem,
 	)
+	a.tabs.SelectIndex(selected)
+
+	// Уходя с настроек, предлагаем сохранить несохраненные изменения
+	a.tabs.OnUnselected = func(item *container.TabItem) {
+		if item == a.settingsItem {
+			a.settingsTab.confirmUnsaved(func() {})
+		}
+	}
+
+	// Значок состояния соединения справа от вкладок
+	toolbar := container.NewVBox(newHRow(layout.NewSpacer(), a.healthIndicator.Build()))
 
 	// Устанавливаем содержимое окна
-	a.mainWindow.SetContent(tabs)
+	a.mainWindow.SetContent(container.NewStack(a.tabs, toolbar))
+}
+
+// OpenFiles выбирает файлы для загрузки (вызывается из главного потока)
+// Если UI еще не построен, файлы будут выбраны после Build
+func (a *App) OpenFiles(files []string) {
+	if a.uploadTab == nil {
+		a.pendingFiles = append(a.pendingFiles, files...)
+		return
+	}
+	a.uploadTab.SelectFiles(files)
+}
+
+// ReceiveFiles принимает файлы от другого экземпляра приложения (вызывается из горутины)
+func (a *App) ReceiveFiles(files []string) {
+	fyne.Do(func() {
+		a.OpenFiles(files)
+		a.ShowWindow()
+	})
 }
 
 // Run запускает приложение
@@ -108,17 +283,42 @@ func (a *App) Run() {
 	// Применяем тему из конфигурации перед показом окна
 	a.ApplyTheme()
 
+	// Добавленные пользователем хостинги - после встроенных провайдеров, до построения UI
+	a.applyXFSHosts()
+
+	// Свои CA, серверы без проверки сертификата и заголовки - до первых запросов к провайдерам
+	if err := a.applyTLS(); err != nil {
+		logging.ErrorWithError("Failed to apply TLS settings", err)
+	}
+	a.applyHeaders()
+
+	// Старые записи удаляются до того, как история попадет на экран
+	a.applyHistoryRetention()
+
 	a.Build()
+	a.watchLifecycle()
+
+	if err := a.applyMetrics(); err != nil {
+		logging.ErrorWithError("Failed to start metrics endpoint", err)
+	}
+
+	// Предлагаем продолжить загрузки, не завершенные до выхода
+	a.offerResume()
 
 	// Проверяем обновления в фоне после запуска окна (не блокируем UI)
 	go func() {
 		// Ждем 2 секунды чтобы окно успело полностью отобразиться
 		// (иначе диалог может появиться до готовности UI)
-		time.Sleep(2 * time.Second)
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-time.After(2 * time.Second):
+		}
 		a.checkForUpdates(false) // false = не показывать сообщение если обновлений нет
 	}()
 
 	a.mainWindow.ShowAndRun()
+	a.stop()
 }
 
 // Config возвращает менеджер конфигурации
@@ -126,23 +326,155 @@ func (a *App) Config() *config.ConfigManager {
 	return a.config
 }
 
+// History возвращает историю загрузок
+func (a *App) History() *history.Store {
+	return a.history
+}
+
+// Queue возвращает сохраненную очередь незавершенных загрузок
+func (a *App) Queue() *queue.Store {
+	return a.queue
+}
+
+// RateLimiter возвращает паузы провайдеров, ответивших 429
+func (a *App) RateLimiter() *upload.RateLimiter {
+	return a.rateLimiter
+}
+
+// openHistory открывает историю загрузок
+// Если файл недоступен или поврежден, история ведется только в памяти, чтобы не затереть его
+func openHistory() *history.Store {
+	path, err := history.DefaultPath()
+	if err != nil {
+		logging.ErrorWithError("Failed to locate history file", err)
+		return history.NewInMemory()
+	}
+
+	store, err := history.Open(path)
+	if err != nil {
+		logging.ErrorWithError("Failed to open history", err, "path", path)
+		return history.NewInMemory()
+	}
+	return store
+}
+
+// openQueue открывает сохраненную очередь загрузок
+// Если файл недоступен или поврежден, очередь ведется только в памяти
+func openQueue() *queue.Store {
+	path, err := queue.DefaultPath()
+	if err != nil {
+		logging.ErrorWithError("Failed to locate queue file", err)
+		return queue.NewInMemory()
+	}
+
+	store, err := queue.Open(path)
+	if err != nil {
+		logging.ErrorWithError("Failed to open upload queue", err, "path", path)
+		return queue.NewInMemory()
+	}
+	return store
+}
+
+// offerResume предлагает продолжить загрузки, сохраненные в очереди при прошлом запуске
+func (a *App) offerResume() {
+	items := a.queue.Items()
+	if len(items) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf(localization.TN("%d uploads were not finished when multiUploader was closed. Resume them?", len(items)), len(items))
+	// Загрузки со снимком прогресса не начинаются с нуля
+	continued := 0
+	for _, item := range items {
+		if p := item.Progress; p != nil && (len(p.Parts) > 0 || p.Checkpoint != nil) {
+			continued++
+		}
+	}
+	if continued > 0 {
+		message += "\n\n" + fmt.Sprintf(localization.TN("%d of them will continue from where they stopped.", continued), continued)
+	}
+	confirm := dialog.NewConfirm(localization.T("Resume uploads"), message, func(resume bool) {
+		// Очередь сохранится заново из возобновленных загрузок
+		if err := a.queue.Save(nil); err != nil {
+			logging.ErrorWithError("Failed to clear upload queue", err)
+		}
+		if resume {
+			a.uploadTab.ResumePending(items)
+		}
+	}, a.mainWindow)
+	confirm.SetConfirmText(localization.T("Resume"))
+	confirm.SetDismissText(localization.T("Discard"))
+	confirm.Show()
+}
+
+// showFriendlyError показывает дружественное сообщение об ошибке
+func (a *App) showFriendlyError(err error) {
+	if err == nil {
+		return
+	}
+
+	friendlyErr := MakeFriendly(err)
+	if alternative := a.alternativeProvider(err); alternative != "" {
+		friendlyErr.Hint = fmt.Sprintf(localization.T("%s accepts this file type. Select it in the provider list and upload again."), alternative)
+	}
+	message := FormatErrorMessage(friendlyErr)
+
+	// Показываем custom dialog с понятным сообщением
+	content := widget.NewLabel(message)
+	content.Wrapping = fyne.TextWrapWord
+
+	d := dialog.NewCustom(friendlyErr.Title, localization.T("OK"), content, a.mainWindow)
+	resizeDialog(d, fyne.NewSize(500, 200))
+	d.Show()
+}
+
+// alternativeProvider имя включенного и настроенного провайдера, который примет файл,
+// отклоненный по типу ("" - ошибка другая или такого провайдера нет)
+func (a *App) alternativeProvider(err error) string {
+	var validationErr *upload.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Err != upload.ErrFileType {
+		return ""
+	}
+	for _, provider := range a.GetEnabledProviders() {
+		name := provider.Name()
+		if name == validationErr.Provider || !providers.GetCapabilities(provider).AcceptsFile(validationErr.Path) {
+			continue
+		}
+		if provider.RequiresAuth() && provider.ValidateAPIKey(a.config.GetProviderAPIKey(name)) != nil {
+			continue
+		}
+		if settings, ok := provider.(providers.SettingsProvider); ok && settings.ValidateSettings() != nil {
+			continue
+		}
+		return name
+	}
+	return ""
+}
+
 // MainWindow возвращает главное окно приложения
 func (a *App) MainWindow() fyne.Window {
 	return a.mainWindow
 }
 
 // ApplyTheme применяет тему из конфигурации
+// "auto" или пустая строка - вариант темы берется из ОС и отслеживается во время работы
 func (a *App) ApplyTheme() {
 	cfg := a.config.GetGlobalConfig()
+	a.fyneApp.Settings().SetTheme(newAppTheme(cfg.Theme, cfg.AccentColor, cfg.Density, cfg.UIScale))
+}
 
-	switch cfg.Theme {
-	case "dark":
-		a.fyneApp.Settings().SetTheme(theme.DarkTheme())
-	case "light":
-		a.fyneApp.Settings().SetTheme(theme.LightTheme())
-	default:
-		// "auto" или пустая строка - используем системную тему по умолчанию
-		a.fyneApp.Settings().SetTheme(theme.DefaultTheme())
+// onSystemSettingsChanged вызывается Fyne при изменении настроек (в т.ч. темы ОС)
+func (a *App) onSystemSettingsChanged(settings fyne.Settings) {
+	variant := settings.ThemeVariant()
+	if variant == a.systemVariant {
+		return
+	}
+	a.systemVariant = variant
+
+	// В режиме auto перерисовываем окно с новым вариантом темы
+	theme := a.config.GetGlobalConfig().Theme
+	if (theme == "auto" || theme == "") && a.mainWindow.Content() != nil {
+		a.mainWindow.Content().Refresh()
 	}
 }
 
@@ -153,14 +485,24 @@ func (a *App) buildMenu() *fyne.MainMenu {
 		a.openLogsFolder()
 	})
 
+	downloadItem := fyne.NewMenuItem(localization.T("Download from URL..."), func() {
+		a.showDownloadDialog("", "")
+	})
+
 	fileMenu := fyne.NewMenu(localization.T("File"),
+		downloadItem,
 		openLogsItem,
 		fyne.NewMenuItemSeparator(),
-		fyne.NewMenuItem(localization.T("Quit"), func() {
-			a.fyneApp.Quit()
-		}),
+		fyne.NewMenuItem(localization.T("Quit"), a.quit),
 	)
 
+	// View menu
+	miniModeItem := fyne.NewMenuItem(localization.T("Mini Mode"), func() {
+		a.setMiniMode(a.miniWindow == nil)
+	})
+	miniModeItem.Checked = a.miniWindow != nil
+	viewMenu := fyne.NewMenu(localization.T("View"), miniModeItem)
+
 	// Help menu
 	checkUpdatesItem := fyne.NewMenuItem(localization.T("Check for Updates..."), func() {
 		go a.checkForUpdates(true) // true = показывать сообщение даже если обновлений нет
@@ -170,9 +512,38 @@ func (a *App) buildMenu() *fyne.MainMenu {
 		a.showAboutDialog()
 	})
 
-	helpMenu := fyne.NewMenu(localization.T("Help"), checkUpdatesItem, aboutItem)
+	// Dry run для диагностики провайдеров: загрузка проходит целиком, но данные не отправляются
+	dryRunItem := fyne.NewMenuItem(localization.T("Dry Run Mode"), func() {
+		a.setDryRun(!httpclient.DryRunEnabled())
+	})
+	dryRunItem.Checked = httpclient.DryRunEnabled()
 
-	return fyne.NewMainMenu(fileMenu, helpMenu)
+	dryRunLogItem := fyne.NewMenuItem(localization.T("Dry Run Log..."), a.showDryRunLog)
+	if !dryRunItem.Checked {
+		dryRunLogItem.Disabled = true
+	}
+
+	speedTestItem := fyne.NewMenuItem(localization.T("Speed Test..."), a.showSpeedTest)
+
+	helpMenu := fyne.NewMenu(localization.T("Help"),
+		checkUpdatesItem,
+		fyne.NewMenuItemSeparator(),
+		dryRunItem,
+		dryRunLogItem,
+		speedTestItem,
+		fyne.NewMenuItemSeparator(),
+		aboutItem,
+	)
+
+	// Мини-окно - отдельное окно рабочего стола, на телефоне его нет
+	menus := []*fyne.Menu{fileMenu, viewMenu}
+	if isMobile() {
+		menus = []*fyne.Menu{fileMenu}
+	}
+	if developerMode() {
+		menus = append(menus, a.buildDeveloperMenu())
+	}
+	return fyne.NewMainMenu(append(menus, helpMenu)...)
 }
 
 // openLogsFolder открывает папку с логами в файловом менеджере (кроссплатформенно)
@@ -199,6 +570,12 @@ func (a *App) openLogsFolder() {
 	var cmd *exec.Cmd
 
 	switch runtime.GOOS {
+	case "android", "ios":
+		// Папка приложения на телефоне недоступна файловому менеджеру: показываем путь
+		dialog.ShowInformation(localization.T("Logs Location"),
+			localization.T("Logs are located at:")+"\n"+logDir,
+			a.mainWindow)
+		return
 	case "darwin": // macOS
 		cmd = exec.Command("open", logDir)
 	case "windows":
@@ -218,6 +595,13 @@ func (a *App) openLogsFolder() {
 
 // SendNotification отправляет системное уведомление с учетом настроек и фокуса окна
 func (a *App) SendNotification(title, content string) {
+	a.SendNotificationWithActions(title, content, nil)
+}
+
+// SendNotificationWithActions отправляет уведомление с действиями
+// onClick - действие по клику на уведомление, actions - дополнительные кнопки
+// Если платформа не поддерживает действия, показывается обычное уведомление Fyne
+func (a *App) SendNotificationWithActions(title, content string, onClick *notify.Action, actions ...notify.Action) {
 	// Получаем режим уведомлений из конфига
 	globalCfg := a.config.GetGlobalConfig()
 	mode := globalCfg.NotificationMode
@@ -238,12 +622,107 @@ func (a *App) SendNotification(title, content string) {
 	}
 
 	// Режим "always" или "unfocused" - отправляем уведомление
+	if onClick != nil || len(actions) > 0 {
+		err := notify.Send(notify.Notification{
+			AppID:   a.fyneApp.UniqueID(),
+			AppName: a.fyneApp.Metadata().Name,
+			Title:   title,
+			Content: content,
+			Default: onClick,
+			Actions: actions,
+		})
+		if err == nil {
+			return
+		}
+		if !errors.Is(err, notify.ErrUnsupported) {
+			logging.ErrorWithError("Failed to send actionable notification", err)
+		}
+	}
+
 	a.fyneApp.SendNotification(&fyne.Notification{
 		Title:   title,
 		Content: content,
 	})
 }
 
+// PlaySound проигрывает звуковой сигнал события, если он включен в настройках
+func (a *App) PlaySound(event sound.Event) {
+	cfg := a.config.GetGlobalConfig()
+
+	switch event {
+	case sound.EventSuccess:
+		if !cfg.SoundOnSuccess {
+			return
+		}
+	case sound.EventFailure:
+		if !cfg.SoundOnFailure {
+			return
+		}
+	}
+
+	sound.Play(event)
+}
+
+// showSettings открывает вкладку настроек
+func (a *App) showSettings() {
+	a.ShowWindow()
+	a.tabs.Select(a.settingsItem)
+}
+
+// quit завершает приложение, предложив сохранить несохраненные настройки
+// и подтвердить остановку идущих загрузок
+func (a *App) quit() {
+	a.settingsTab.confirmUnsaved(a.confirmStopUploads)
+}
+
+// shutdownTimeout сколько выход ждет остановки загрузок
+const shutdownTimeout = 10 * time.Second
+
+// confirmStopUploads спрашивает, остановить ли идущие загрузки, и закрывает приложение
+func (a *App) confirmStopUploads() {
+	active := a.uploadTab.vm.State().Active()
+	if active == 0 {
+		a.fyneApp.Quit()
+		return
+	}
+
+	message := fmt.Sprintf(localization.TN("%d uploads are not finished. Quit anyway? They will be stopped and offered to resume on the next start.", active), active)
+	confirm := dialog.NewConfirm(localization.T("Quit"), message, func(ok bool) {
+		if ok {
+			a.shutdown()
+		}
+	}, a.mainWindow)
+	confirm.SetConfirmText(localization.T("Quit"))
+	confirm.Show()
+}
+
+// shutdown останавливает загрузки и закрывает приложение
+// Загрузки отменяются штатно: провайдеры убирают незавершенные загрузки с сервера,
+// история и очередь сохраняются до выхода
+func (a *App) shutdown() {
+	a.ShowWindow()
+	progress := widget.NewProgressBarInfinite()
+	d := dialog.NewCustomWithoutButtons(localization.T("Stopping uploads..."), progress, a.mainWindow)
+	d.Show()
+
+	go func() {
+		if !a.uploadTab.vm.Shutdown(shutdownTimeout) {
+			logging.Error("Uploads did not stop before quit", "timeout", shutdownTimeout)
+		}
+		fyne.Do(a.fyneApp.Quit)
+	}()
+}
+
+// ShowWindow выводит главное окно на передний план (мини-окно при этом закрывается)
+func (a *App) ShowWindow() {
+	if a.miniWindow != nil {
+		a.setMiniMode(false)
+		return
+	}
+	a.mainWindow.Show()
+	a.mainWindow.RequestFocus()
+}
+
 // showAboutDialog показывает диалог "О программе" с информацией о версии
 func (a *App) showAboutDialog() {
 	// Получаем метаданные приложения из FyneApp.toml
@@ -262,30 +741,38 @@ func (a *App) showAboutDialog() {
 
 // checkForUpdates проверяет наличие новой версии на GitHub
 // showNoUpdateMessage - если true, показывать сообщение даже если обновлений нет (для ручной проверки)
+// Вызывается из горутины
 func (a *App) checkForUpdates(showNoUpdateMessage bool) {
 	metadata := a.fyneApp.Metadata()
 	currentVersion := metadata.Version
 
 	// Проверяем обновления
-	release, err := updater.CheckForUpdates(githubOwner, githubRepo, currentVersion)
+	release, err := updater.CheckForUpdates(a.ctx, githubOwner, githubRepo, currentVersion)
 
-	// Обновляем UI из горутины через fyne.Do
-	if err != nil {
-		if showNoUpdateMessage {
-			dialog.ShowError(fmt.Errorf("failed to check for updates: %w", err), a.mainWindow)
-		}
+	// Приложение закрывается - показывать результат некому
+	if a.ctx.Err() != nil {
 		return
 	}
 
-	if release != nil {
-		// Есть новая версия - показываем диалог
-		a.showUpdateDialog(release)
-	} else if showNoUpdateMessage {
-		// Обновлений нет, но пользователь запросил проверку вручную
-		dialog.ShowInformation(localization.T("No Updates"),
-			fmt.Sprintf(localization.T("You are using the latest version")+" (%s)", currentVersion),
-			a.mainWindow)
-	}
+	// Обновляем UI из горутины через fyne.Do
+	fyne.Do(func() {
+		if err != nil {
+			if showNoUpdateMessage {
+				dialog.ShowError(fmt.Errorf("%s %w", localization.T("Failed to check for updates:"), err), a.mainWindow)
+			}
+			return
+		}
+
+		if release != nil {
+			// Есть новая версия - показываем диалог
+			a.showUpdateDialog(release)
+		} else if showNoUpdateMessage {
+			// Обновлений нет, но пользователь запросил проверку вручную
+			dialog.ShowInformation(localization.T("No Updates"),
+				fmt.Sprintf(localization.T("You are using the latest version")+" (%s)", currentVersion),
+				a.mainWindow)
+		}
+	})
 }
 
 // showUpdateDialog показывает диалог о доступности новой версии
@@ -312,9 +799,23 @@ func (a *App) showUpdateDialog(release *updater.ReleaseInfo) {
 
 // openURL открывает URL в браузере (кроссплатформенно)
 func (a *App) openURL(url string) {
+	openURL(a.mainWindow, url)
+}
+
+// openURL открывает URL в браузере; если не удалось, показывает его в диалоге поверх window
+func openURL(window fyne.Window, url string) {
 	var cmd *exec.Cmd
 
 	switch runtime.GOOS {
+	case "android", "ios":
+		// На телефоне внешних команд нет: ссылку открывает ОС
+		if u, err := neturl.Parse(url); err == nil && fyne.CurrentApp().OpenURL(u) == nil {
+			return
+		}
+		dialog.ShowInformation(localization.T("Download Link"),
+			localization.T("Please visit:")+"\n"+url,
+			window)
+		return
 	case "darwin": // macOS
 		cmd = exec.Command("open", url)
 	case "windows":
@@ -327,6 +828,6 @@ func (a *App) openURL(url string) {
 		// Если не удалось открыть, показываем URL
 		dialog.ShowInformation(localization.T("Download Link"),
 			localization.T("Please visit:")+"\n"+url,
-			a.mainWindow)
+			window)
 	}
 }
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"multiUploader/internal/config"
+	"multiUploader/internal/providers"
+	"multiUploader/internal/upload"
+)
+
+// TestProviderNamesOrder проверяет, что провайдеры перечисляются в порядке регистрации
+func TestProviderNamesOrder(t *testing.T) {
+	a := &App{providerFactories: make(map[string]ProviderFactory)}
+	factory := func(string) providers.Provider { return nil }
+
+	for _, name := range []string{"DataVaults", "Rootz", "AkiraBox", "FileKeeper", "Telegram"} {
+		a.RegisterProviderFactory(name, factory)
+	}
+	// Повторная регистрация не сдвигает провайдер в конец
+	a.RegisterProviderFactory("Rootz", factory)
+	a.unregisterProviderFactory("AkiraBox")
+
+	want := []string{"DataVaults", "Rootz", "FileKeeper", "Telegram"}
+	if got := a.ProviderNames(); !slices.Equal(got, want) {
+		t.Errorf("ProviderNames() = %v, want %v", got, want)
+	}
+}
+
+// TestApplyXFSHosts проверяет, что хостинги из настроек заменяют добавленные ранее и не подменяют встроенные провайдеры
+func TestApplyXFSHosts(t *testing.T) {
+	fyneApp := test.NewTempApp(t)
+	a := &App{config: config.NewConfigManager(fyneApp.Preferences()), providerFactories: make(map[string]ProviderFactory)}
+	a.RegisterProviderFactory("DataVaults", func(apiKey string) providers.Provider { return providers.NewDataVaultsProvider(apiKey) })
+
+	setHosts := func(text string) {
+		cfg := a.config.GetGlobalConfig()
+		cfg.XFSHosts = text
+		a.config.SetGlobalConfig(cfg)
+	}
+
+	setHosts("HostA = https://a.example\nHostB = https://b.example")
+	if !a.applyXFSHosts() {
+		t.Error("applyXFSHosts() = false after adding hosts")
+	}
+	if provider, ok := a.GetProvider("HostB"); !ok || provider.Name() != "HostB" {
+		t.Errorf("GetProvider(HostB) = %v, %v", provider, ok)
+	}
+
+	setHosts("HostB = https://b.example")
+	a.applyXFSHosts()
+	want := []string{"DataVaults", "HostB"}
+	if got := a.ProviderNames(); !slices.Equal(got, want) {
+		t.Errorf("ProviderNames() = %v, want %v", got, want)
+	}
+	if a.applyXFSHosts() {
+		t.Error("applyXFSHosts() = true without changes")
+	}
+
+	if _, err := a.parseXFSHosts("DataVaults = https://evil.example"); err == nil {
+		t.Error("parseXFSHosts() accepted a built-in provider name")
+	}
+	if _, err := a.parseXFSHosts("HostB = https://b2.example"); err != nil {
+		t.Errorf("parseXFSHosts() rejected an added host: %v", err)
+	}
+}
+
+// TestAlternativeProvider проверяет, что для отклоненного по типу файла предлагается включенный провайдер с ключом
+func TestAlternativeProvider(t *testing.T) {
+	fyneApp := test.NewTempApp(t)
+	a := &App{config: config.NewConfigManager(fyneApp.Preferences()), providerFactories: make(map[string]ProviderFactory)}
+	a.RegisterProviderFactory("DataVaults", func(apiKey string) providers.Provider { return providers.NewDataVaultsProvider(apiKey) })
+	a.RegisterProviderFactory("FileKeeper", func(apiKey string) providers.Provider { return providers.NewFileKeeperProvider(apiKey) })
+	a.RegisterProviderFactory("Rootz", func(apiKey string) providers.Provider { return providers.NewRootzProvider(apiKey) })
+	a.RegisterProviderFactory("AkiraBox", func(apiKey string) providers.Provider { return providers.NewAkiraBoxProvider(apiKey) })
+
+	for name, key := range map[string]string{"DataVaults": "key", "FileKeeper": "key", "Rootz": "", "AkiraBox": "key"} {
+		a.config.SetProviderConfig(name, config.ProviderConfig{Enabled: true, APIKey: key})
+	}
+
+	rejected := &upload.ValidationError{Err: upload.ErrFileType, Provider: "DataVaults", Path: "/tmp/setup.exe"}
+	// FileKeeper тоже не принимает .exe, у Rootz нет ключа
+	if got := a.alternativeProvider(fmt.Errorf("enqueue: %w", rejected)); got != "AkiraBox" {
+		t.Errorf("alternativeProvider() = %q, want AkiraBox", got)
+	}
+	if got := a.alternativeProvider(&upload.ValidationError{Err: upload.ErrEmptyFile, Provider: "DataVaults"}); got != "" {
+		t.Errorf("alternativeProvider() for another error = %q, want none", got)
+	}
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+	"multiUploader/internal/providers"
+)
+
+// chunkDetailsHeight высота списка частей в раскрытой панели
+const chunkDetailsHeight = 160
+
+// chunkDetails сворачиваемая панель карточки со списком частей загрузки частями
+// Показывает размер, число попыток и состояние каждой части, чтобы было видно, какая часть не проходит
+type chunkDetails struct {
+	accordion *widget.Accordion
+	item      *widget.AccordionItem
+	list      *widget.List
+
+	parts []providers.PartStatus
+}
+
+// newChunkDetails создает панель (скрыта, пока загрузка не идет частями)
+func newChunkDetails() *chunkDetails {
+	d := &chunkDetails{}
+
+	d.list = widget.NewList(
+		func() int { return len(d.parts) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			part := d.parts[id]
+			label := obj.(*widget.Label)
+			label.Importance = partImportance(part.State)
+			label.SetText(partText(part))
+		},
+	)
+
+	// List не имеет своей высоты - задаем ее прозрачной подложкой
+	spacer := canvas.NewRectangle(color.Transparent)
+	spacer.SetMinSize(fyne.NewSize(0, chunkDetailsHeight))
+
+	d.item = widget.NewAccordionItem(localization.T("Details"), container.NewStack(spacer, d.list))
+	d.accordion = widget.NewAccordion(d.item)
+	d.accordion.Hide()
+	return d
+}
+
+// render показывает состояние частей (вызывается из главного потока)
+func (d *chunkDetails) render(parts []providers.PartStatus) {
+	// Для файла из одной части список ничего не добавляет к общему прогрессу
+	setVisible(d.accordion, len(parts) > 1)
+	if len(parts) <= 1 {
+		return
+	}
+
+	d.parts = parts
+	d.item.Title = partsSummary(parts)
+	d.accordion.Refresh()
+	if d.item.Open {
+		d.list.Refresh()
+	}
+}
+
+// partsSummary заголовок панели: сколько частей готово и сколько пришлось повторять
+func partsSummary(parts []providers.PartStatus) string {
+	done, retried := 0, 0
+	for _, part := range parts {
+		if part.State == providers.PartDone {
+			done++
+		}
+		if part.Attempts > 1 {
+			retried++
+		}
+	}
+
+	summary := fmt.Sprintf(localization.T("Details: %d of %d parts done"), done, len(parts))
+	if retried > 0 {
+		summary += ", " + fmt.Sprintf(localization.TN("%d retried", retried), retried)
+	}
+	return summary
+}
+
+// partText строка списка частей
+func partText(part providers.PartStatus) string {
+	text := fmt.Sprintf(localization.T("Part %d · %s · attempts: %d"), part.Number, localization.FormatSize(part.Size), part.Attempts)
+
+	switch part.State {
+	case providers.PartPending:
+		return text + " · " + localization.T("waiting")
+	case providers.PartUploading:
+		percent := 0
+		if part.Size > 0 {
+			percent = int(part.Sent * 100 / part.Size)
+		}
+		return text + " · " + fmt.Sprintf(localization.T("uploading %d%%"), percent)
+	case providers.PartRetrying:
+		return text + " · " + localization.T("retrying") + ": " + part.Err
+	case providers.PartFailed:
+		return text + " · " + localization.T("failed") + ": " + part.Err
+	default:
+		return text + " · " + localization.T("done")
+	}
+}
+
+// partImportance цвет строки по состоянию части
+func partImportance(state providers.PartState) widget.Importance {
+	switch state {
+	case providers.PartRetrying:
+		return widget.WarningImportance
+	case providers.PartFailed:
+		return widget.DangerImportance
+	case providers.PartDone:
+		return widget.SuccessImportance
+	default:
+		return widget.MediumImportance
+	}
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+
+	"multiUploader/internal/localization"
+	"multiUploader/internal/providers"
+	"multiUploader/internal/tracing"
+)
+
+// developerModeEnv переменная окружения, открывающая скрытое меню разработчика
+const developerModeEnv = "MULTIUPLOADER_DEVELOPER"
+
+// developerMode сообщает, показывать ли меню разработчика
+func developerMode() bool {
+	return os.Getenv(developerModeEnv) != ""
+}
+
+// buildDeveloperMenu создает меню с мок провайдерами для проверки очереди, повторов и ошибок,
+// образцом провайдера и переключателем трассировки
+func (a *App) buildDeveloperMenu() *fyne.Menu {
+	items := make([]*fyne.MenuItem, 0, len(providers.MockProfiles())+4)
+	for _, profile := range providers.MockProfiles() {
+		item := fyne.NewMenuItem(profile.Name, func() {
+			a.toggleMockProvider(profile.Name, func(string) providers.Provider {
+				return providers.NewMockProviderWithConfig(profile.Name, profile.Config)
+			})
+		})
+		item.Checked = a.mockProviders[profile.Name]
+		items = append(items, item)
+	}
+
+	// Образец провайдера (internal/providers/example.go) отвечает только в режиме dry run
+	example := providers.NewExampleProvider("").Name()
+	exampleItem := fyne.NewMenuItem(localization.T("Example Provider (dry run)"), func() {
+		a.toggleMockProvider(example, func(apiKey string) providers.Provider {
+			return providers.NewExampleProvider(apiKey)
+		})
+	})
+	exampleItem.Checked = a.mockProviders[example]
+	items = append(items, fyne.NewMenuItemSeparator(), exampleItem)
+
+	tracingItem := fyne.NewMenuItem(localization.T("Send traces to local collector"), a.toggleTracing)
+	tracingItem.Checked = tracing.Enabled()
+	items = append(items, fyne.NewMenuItemSeparator(), tracingItem)
+
+	return fyne.NewMenu(localization.T("Developer"), items...)
+}
+
+// toggleTracing включает или выключает отправку трасс загрузок в OpenTelemetry Collector
+// Адрес берется из OTEL_EXPORTER_OTLP_ENDPOINT, по умолчанию - локальный приемник OTLP/HTTP
+func (a *App) toggleTracing() {
+	if tracing.Enabled() {
+		// Выключение дожидается отправки накопленных span - не держим главный поток
+		go func() {
+			tracing.Disable()
+			fyne.Do(func() { a.mainWindow.SetMainMenu(a.buildMenu()) })
+		}()
+		return
+	}
+
+	endpoint := tracing.EndpointFromEnv()
+	tracing.Enable(endpoint)
+	a.mainWindow.SetMainMenu(a.buildMenu())
+	dialog.ShowInformation(localization.T("Tracing"), fmt.Sprintf(localization.T("Upload traces are sent to %s."), endpoint), a.mainWindow)
+}
+
+// toggleMockProvider добавляет провайдер из меню разработчика в список загрузки или убирает его
+// Такие провайдеры включены, пока приложение запущено, и не попадают в настройки
+func (a *App) toggleMockProvider(name string, factory ProviderFactory) {
+	if a.mockProviders[name] {
+		delete(a.mockProviders, name)
+		a.unregisterProviderFactory(name)
+	} else {
+		a.mockProviders[name] = true
+		a.RegisterProviderFactory(name, factory)
+	}
+
+	a.mainWindow.SetMainMenu(a.buildMenu())
+	if a.uploadTab != nil {
+		a.uploadTab.Refresh()
+	}
+}
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/download"
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/localization"
+	"multiUploader/internal/logging"
+)
+
+// showDownloadDialog запрашивает ссылку и папку, затем скачивает файл
+// url и sha256 заполняются из записи истории (пустой sha256 - хеш не проверяется)
+func (a *App) showDownloadDialog(url, sha256 string) {
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("https://")
+	urlEntry.SetText(url)
+
+	dir := defaultDownloadDir()
+	dirLabel := widget.NewLabel(dir)
+	dirLabel.Truncation = fyne.TextTruncateEllipsis
+	chooseBtn := widget.NewButton(localization.T("Choose..."), func() {
+		picker := dialog.NewFolderOpen(func(folder fyne.ListableURI, err error) {
+			if err != nil || folder == nil {
+				return
+			}
+			// Папки хранилища телефона (content://) не открываются по пути
+			if folder.Scheme() != "file" {
+				dialog.ShowInformation(localization.T("Download File"),
+					localization.T("Files can only be saved to a folder on the device's file system."), a.mainWindow)
+				return
+			}
+			dir = folder.Path()
+			dirLabel.SetText(dir)
+		}, a.mainWindow)
+		if uri, err := storage.ListerForURI(storage.NewFileURI(dir)); err == nil {
+			picker.SetLocation(uri)
+		}
+		resizeDialog(picker, fyne.NewSize(800, 600))
+		picker.Show()
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(localization.T("URL"), urlEntry),
+		widget.NewFormItem(localization.T("Save to:"), newRow(nil, chooseBtn, dirLabel)),
+	}
+
+	form := dialog.NewForm(localization.T("Download File"), localization.T("Download"), localization.T("Cancel"), items, func(confirmed bool) {
+		link := strings.TrimSpace(urlEntry.Text)
+		if !confirmed || link == "" {
+			return
+		}
+		a.startDownload(download.Request{URL: link, Dir: dir, SHA256: sha256})
+	}, a.mainWindow)
+	resizeDialog(form, fyne.NewSize(600, 200))
+	form.Show()
+}
+
+// startDownload показывает прогресс скачивания с возможностью отмены
+func (a *App) startDownload(req download.Request) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	progressBinding := binding.NewFloat()
+	statusBinding := binding.NewString()
+	statusBinding.Set(localization.T("Connecting..."))
+
+	content := container.NewVBox(
+		widget.NewLabel(req.URL),
+		widget.NewProgressBarWithData(progressBinding),
+		widget.NewLabelWithData(statusBinding),
+	)
+	progressDialog := dialog.NewCustom(localization.T("Downloading"), localization.T("Cancel"), content, a.mainWindow)
+	progressDialog.SetOnClosed(cancel)
+	resizeDialog(progressDialog, fyne.NewSize(500, 150))
+	progressDialog.Show()
+
+	progress := make(chan download.Progress, 10)
+	go func() {
+		for p := range progress {
+			if p.Total > 0 {
+				progressBinding.Set(float64(p.Bytes) / float64(p.Total))
+			}
+			statusBinding.Set(fmt.Sprintf("%s · %s",
+				localization.FormatSize(p.Bytes),
+				localization.FormatSpeed(p.Speed),
+			))
+		}
+	}()
+
+	go func() {
+		result, err := download.Download(ctx, httpclient.LongLived(), req, progress)
+		close(progress)
+		cancelled := ctx.Err() != nil
+		cancel()
+
+		fyne.Do(func() {
+			// Закрываем диалог прогресса без повторной отмены
+			progressDialog.SetOnClosed(nil)
+			progressDialog.Hide()
+			if cancelled {
+				return
+			}
+			a.showDownloadResult(req, result, err)
+		})
+	}()
+}
+
+// showDownloadResult сообщает об итоге скачивания (вызывается из главного потока)
+func (a *App) showDownloadResult(req download.Request, result *download.Result, err error) {
+	switch {
+	case errors.Is(err, download.ErrChecksumMismatch):
+		logging.Error("Downloaded file checksum mismatch", "url", req.URL, "path", result.Path, "sha256", result.SHA256)
+		dialog.ShowError(fmt.Errorf(
+			localization.T("The downloaded file differs from the uploaded one (SHA-256 mismatch). It was saved to %s"),
+			result.Path,
+		), a.mainWindow)
+
+	case errors.Is(err, download.ErrNotAFile):
+		dialog.ShowInformation(localization.T("Download Failed"),
+			localization.T("This link opens a web page, not the file itself. Use the direct download link."),
+			a.mainWindow)
+
+	case err != nil:
+		logging.ErrorWithError("Download failed", err, "url", req.URL)
+		a.showFriendlyError(err)
+
+	default:
+		message := fmt.Sprintf(localization.T("Saved to %s"), result.Path)
+		if req.SHA256 != "" {
+			message += "\n" + localization.T("Checksum matches the uploaded file")
+		}
+		dialog.ShowInformation(localization.T("Download Complete"), message, a.mainWindow)
+	}
+}
+
+// defaultDownloadDir возвращает папку "Загрузки" пользователя, если она есть, иначе домашнюю
+func defaultDownloadDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return os.TempDir()
+	}
+
+	downloads := filepath.Join(home, "Downloads")
+	if info, err := os.Stat(downloads); err == nil && info.IsDir() {
+		return downloads
+	}
+	return home
+}
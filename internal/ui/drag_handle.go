@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// dragHandle значок, за который карточку перетаскивают по вертикали
+// В Fyne нет перестановки элементов списка, поэтому смещение передается в onDrop после отпускания
+type dragHandle struct {
+	widget.Icon
+
+	// dy накопленное смещение текущего перетаскивания
+	dy     float32
+	onDrop func(dy float32)
+}
+
+// newDragHandle создает значок перетаскивания
+func newDragHandle(onDrop func(dy float32)) *dragHandle {
+	h := &dragHandle{onDrop: onDrop}
+	h.SetResource(theme.MenuIcon())
+	h.ExtendBaseWidget(h)
+	return h
+}
+
+// Dragged накапливает смещение
+func (h *dragHandle) Dragged(e *fyne.DragEvent) {
+	h.dy += e.Dragged.DY
+}
+
+// DragEnd сообщает итоговое смещение
+func (h *dragHandle) DragEnd() {
+	dy := h.dy
+	h.dy = 0
+	if h.onDrop != nil {
+		h.onDrop(dy)
+	}
+}
+
+// Cursor показывает, что карточку можно двигать по вертикали
+func (h *dragHandle) Cursor() desktop.Cursor {
+	return desktop.VResizeCursor
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/localization"
+	"multiUploader/internal/providers"
+)
+
+// setDryRun включает или выключает dry run: запросы провайдеров получают заготовленные ответы,
+// файлы никуда не отправляются (для диагностики изменений API провайдеров)
+func (a *App) setDryRun(enabled bool) {
+	if enabled {
+		httpclient.EnableDryRun(providers.DryRunHandler())
+	} else {
+		httpclient.DisableDryRun()
+	}
+
+	// Обновляем отметку в меню и предупреждение на вкладке загрузки
+	a.mainWindow.SetMainMenu(a.buildMenu())
+	if a.uploadTab != nil {
+		a.uploadTab.Refresh()
+	}
+	if a.healthIndicator != nil {
+		a.healthIndicator.Check()
+	}
+}
+
+// showDryRunLog показывает запросы, на которые ответил dry run
+func (a *App) showDryRunLog() {
+	log := httpclient.DryRunLog()
+
+	var content fyne.CanvasObject
+	if len(log) == 0 {
+		content = widget.NewLabel(localization.T("No requests yet"))
+	} else {
+		list := widget.NewList(
+			func() int { return len(log) },
+			func() fyne.CanvasObject {
+				label := widget.NewLabel("")
+				label.Truncation = fyne.TextTruncateEllipsis
+				return label
+			},
+			func(id widget.ListItemID, item fyne.CanvasObject) {
+				tx := log[id]
+				item.(*widget.Label).SetText(fmt.Sprintf("%d  %s %s  (%s)",
+					tx.Status, tx.Method, tx.URL, localization.FormatSize(tx.Sent)))
+			},
+		)
+		content = container.NewStack(list)
+	}
+
+	d := dialog.NewCustom(localization.T("Dry Run Log"), localization.T("Close"), content, a.mainWindow)
+	resizeDialog(d, fyne.NewSize(700, 450))
+	d.Show()
+}
@@ -5,11 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"syscall"
+
+	"multiUploader/internal/localization"
+	"multiUploader/internal/providers"
+	"multiUploader/internal/scan"
+	"multiUploader/internal/upload"
 )
 
 // FriendlyError представляет понятное пользователю сообщение об ошибке
@@ -33,6 +40,7 @@ const (
 )
 
 // MakeFriendly конвертирует техническую ошибку в понятное сообщение
+// Тип ошибки определяется через errors.Is/errors.As по типизированным ошибкам провайдеров
 func MakeFriendly(err error) *FriendlyError {
 	if err == nil {
 		return nil
@@ -54,8 +62,8 @@ func MakeFriendly(err error) *FriendlyError {
 		return makeValidationError(err)
 	case ErrorTypeCancelled:
 		return &FriendlyError{
-			Title:   "Upload Cancelled",
-			Message: "The upload was cancelled by user.",
+			Title:   localization.T("Upload Cancelled"),
+			Message: localization.T("The upload was cancelled by user."),
 			Hint:    "",
 		}
 	default:
@@ -65,94 +73,91 @@ func MakeFriendly(err error) *FriendlyError {
 
 // classifyError определяет тип ошибки
 func classifyError(err error) ErrorType {
-	errMsg := strings.ToLower(err.Error())
-
-	// Context cancellation
-	if errors.Is(err, context.Canceled) || strings.Contains(errMsg, "cancelled") || strings.Contains(errMsg, "canceled") {
+	// Отмена загрузки
+	if errors.Is(err, providers.ErrCancelled) || errors.Is(err, context.Canceled) {
 		return ErrorTypeCancelled
 	}
 
-	// Network errors
+	// Проверка перед загрузкой
+	var validationErr *upload.ValidationError
+	if errors.As(err, &validationErr) {
+		return ErrorTypeValidation
+	}
+
+	// Проверка на вирусы: раньше сетевых ошибок, которыми может закончиться сама проверка
+	if scanStopped(err) {
+		return ErrorTypeValidation
+	}
+
+	// Ошибки провайдеров
+	var authErr *providers.AuthError
+	if errors.As(err, &authErr) {
+		return ErrorTypeAuth
+	}
+
+	var quotaErr *providers.QuotaError
+	if errors.As(err, &quotaErr) {
+		if quotaErr.Reason == providers.QuotaFileTooLarge {
+			return ErrorTypeValidation
+		}
+		return ErrorTypeServer
+	}
+
+	var httpErr *providers.HTTPError
+	if errors.As(err, &httpErr) {
+		if httpErr.Status == http.StatusBadRequest {
+			return ErrorTypeValidation
+		}
+		return ErrorTypeServer
+	}
+
+	var serverErr *providers.ServerError
+	if errors.As(err, &serverErr) {
+		return ErrorTypeServer
+	}
+
+	// Сетевые ошибки (таймауты, DNS, отказ в соединении)
 	var netErr net.Error
 	if errors.As(err, &netErr) {
 		return ErrorTypeNetwork
 	}
 
-	// DNS errors
 	var dnsErr *net.DNSError
 	if errors.As(err, &dnsErr) {
 		return ErrorTypeNetwork
 	}
 
-	// Connection errors
 	var opErr *net.OpError
 	if errors.As(err, &opErr) {
 		return ErrorTypeNetwork
 	}
 
-	// Syscall errors (connection refused, etc.)
-	var syscallErr *os.SyscallError
-	if errors.As(err, &syscallErr) {
-		if syscallErr.Err == syscall.ECONNREFUSED {
-			return ErrorTypeNetwork
-		}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return ErrorTypeNetwork
 	}
 
-	// File errors
+	// Файловые ошибки
 	var pathErr *os.PathError
 	if errors.As(err, &pathErr) {
 		return ErrorTypeFile
 	}
 
-	// Check error message for common patterns
-	if strings.Contains(errMsg, "connection") || strings.Contains(errMsg, "timeout") ||
-		strings.Contains(errMsg, "dial") || strings.Contains(errMsg, "network") {
-		return ErrorTypeNetwork
-	}
-
-	if strings.Contains(errMsg, "unauthorized") || strings.Contains(errMsg, "api key") ||
-		strings.Contains(errMsg, "forbidden") || strings.Contains(errMsg, "authentication") {
-		return ErrorTypeAuth
-	}
-
-	if strings.Contains(errMsg, "file") || strings.Contains(errMsg, "permission denied") ||
-		strings.Contains(errMsg, "no such file") {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
 		return ErrorTypeFile
 	}
 
-	if strings.Contains(errMsg, "status") {
-		// Проверяем HTTP статусы в тексте ошибки
-		if strings.Contains(errMsg, "401") || strings.Contains(errMsg, "403") {
-			return ErrorTypeAuth
-		}
-		if strings.Contains(errMsg, "400") || strings.Contains(errMsg, "413") {
-			return ErrorTypeValidation
-		}
-		if strings.Contains(errMsg, "500") || strings.Contains(errMsg, "502") ||
-			strings.Contains(errMsg, "503") || strings.Contains(errMsg, "504") {
-			return ErrorTypeServer
-		}
-		return ErrorTypeServer
-	}
-
-	if strings.Contains(errMsg, "invalid") || strings.Contains(errMsg, "too large") {
-		return ErrorTypeValidation
-	}
-
 	return ErrorTypeUnknown
 }
 
 // makeNetworkError создает дружественное сообщение для сетевых ошибок
 func makeNetworkError(err error) *FriendlyError {
-	errMsg := strings.ToLower(err.Error())
-
 	// Timeout
 	var netErr net.Error
 	if errors.As(err, &netErr) && netErr.Timeout() {
 		return &FriendlyError{
-			Title:   "Connection Timeout",
-			Message: "The connection to the server timed out.",
-			Hint:    "Please check your internet connection and try again. If the problem persists, the server may be experiencing issues.",
+			Title:   localization.T("Connection Timeout"),
+			Message: localization.T("The connection to the server timed out."),
+			Hint:    localization.T("Please check your internet connection and try again. If the problem persists, the server may be experiencing issues."),
 		}
 	}
 
@@ -160,256 +165,298 @@ func makeNetworkError(err error) *FriendlyError {
 	var dnsErr *net.DNSError
 	if errors.As(err, &dnsErr) {
 		return &FriendlyError{
-			Title:   "DNS Lookup Failed",
-			Message: "Could not resolve the server address.",
-			Hint:    "Please check your internet connection and DNS settings. Try again in a few moments.",
+			Title:   localization.T("DNS Lookup Failed"),
+			Message: localization.T("Could not resolve the server address."),
+			Hint:    localization.T("Please check your internet connection and DNS settings. Try again in a few moments."),
 		}
 	}
 
 	// Connection refused
-	if strings.Contains(errMsg, "connection refused") || strings.Contains(errMsg, "econnrefused") {
+	if errors.Is(err, syscall.ECONNREFUSED) {
 		return &FriendlyError{
-			Title:   "Connection Refused",
-			Message: "The server refused the connection.",
-			Hint:    "The service may be temporarily unavailable. Please try again later.",
+			Title:   localization.T("Connection Refused"),
+			Message: localization.T("The server refused the connection."),
+			Hint:    localization.T("The service may be temporarily unavailable. Please try again later."),
 		}
 	}
 
 	// Generic network error
 	return &FriendlyError{
-		Title:   "Network Error",
-		Message: "A network error occurred while communicating with the server.",
-		Hint:    "Please check your internet connection and try again.",
+		Title:   localization.T("Network Error"),
+		Message: localization.T("A network error occurred while communicating with the server."),
+		Hint:    localization.T("Please check your internet connection and try again."),
 	}
 }
 
 // makeAuthError создает дружественное сообщение для ошибок авторизации
 func makeAuthError(err error) *FriendlyError {
-	errMsg := strings.ToLower(err.Error())
-
-	if strings.Contains(errMsg, "401") || strings.Contains(errMsg, "unauthorized") {
+	var authErr *providers.AuthError
+	if errors.As(err, &authErr) && authErr.Forbidden {
 		return &FriendlyError{
-			Title:   "Invalid API Key",
-			Message: "The API key you provided is not valid.",
-			Hint:    "Please check your API key in Settings and make sure it's correct.",
-		}
-	}
-
-	if strings.Contains(errMsg, "403") || strings.Contains(errMsg, "forbidden") {
-		return &FriendlyError{
-			Title:   "Access Denied",
-			Message: "Your API key does not have permission to perform this operation.",
-			Hint:    "Please check that your API key has the necessary permissions, or contact the service provider.",
+			Title:   localization.T("Access Denied"),
+			Message: localization.T("Your API key does not have permission to perform this operation."),
+			Hint:    localization.T("Please check that your API key has the necessary permissions, or contact the service provider."),
 		}
 	}
 
 	return &FriendlyError{
-		Title:   "Authentication Error",
-		Message: "There was a problem authenticating with the service.",
-		Hint:    "Please check your API key in Settings.",
+		Title:   localization.T("Invalid API Key"),
+		Message: localization.T("The API key you provided is not valid."),
+		Hint:    localization.T("Please check your API key in Settings and make sure it's correct."),
 	}
 }
 
 // makeFileError создает дружественное сообщение для файловых ошибок
 func makeFileError(err error) *FriendlyError {
-	errMsg := strings.ToLower(err.Error())
-
-	if strings.Contains(errMsg, "no such file") || strings.Contains(errMsg, "not found") {
+	if errors.Is(err, fs.ErrNotExist) {
 		return &FriendlyError{
-			Title:   "File Not Found",
-			Message: "The selected file could not be found.",
-			Hint:    "The file may have been moved or deleted. Please select the file again.",
+			Title:   localization.T("File Not Found"),
+			Message: localization.T("The selected file could not be found."),
+			Hint:    localization.T("The file may have been moved or deleted. Please select the file again."),
 		}
 	}
 
-	if strings.Contains(errMsg, "permission denied") || strings.Contains(errMsg, "access is denied") {
+	if errors.Is(err, fs.ErrPermission) {
 		return &FriendlyError{
-			Title:   "Permission Denied",
-			Message: "You don't have permission to access this file.",
-			Hint:    "Please check the file permissions or try selecting a different file.",
+			Title:   localization.T("Permission Denied"),
+			Message: localization.T("You don't have permission to access this file."),
+			Hint:    localization.T("Please check the file permissions or try selecting a different file."),
 		}
 	}
 
-	if errors.Is(err, io.EOF) || strings.Contains(errMsg, "eof") {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
 		return &FriendlyError{
-			Title:   "File Read Error",
-			Message: "The file could not be read completely.",
-			Hint:    "The file may be corrupted or locked by another program. Please try again.",
+			Title:   localization.T("File Read Error"),
+			Message: localization.T("The file could not be read completely."),
+			Hint:    localization.T("The file may be corrupted or locked by another program. Please try again."),
 		}
 	}
 
 	return &FriendlyError{
-		Title:   "File Error",
-		Message: "There was a problem reading the file.",
-		Hint:    "Please make sure the file is accessible and not being used by another program.",
+		Title:   localization.T("File Error"),
+		Message: localization.T("There was a problem reading the file."),
+		Hint:    localization.T("Please make sure the file is accessible and not being used by another program."),
 	}
 }
 
 // makeServerError создает дружественное сообщение для серверных ошибок
 func makeServerError(err error) *FriendlyError {
-	errMsg := strings.ToLower(err.Error())
-
-	// Извлекаем HTTP статус код если есть
-	statusCode := extractStatusCode(errMsg)
-
-	switch statusCode {
-	case http.StatusBadRequest: // 400
+	// Сообщение сервера в теле ответа
+	var serverErr *providers.ServerError
+	if errors.As(err, &serverErr) && serverErr.Message != "" {
 		return &FriendlyError{
-			Title:   "Invalid Request",
-			Message: "The server could not process your request.",
-			Hint:    "Please try selecting the file again. If the problem persists, the file may not be supported.",
+			Title:   localization.T("Upload Failed"),
+			Message: fmt.Sprintf(localization.T("The server reported an error: %s"), serverErr.Message),
+			Hint:    localization.T("Please check your file and try again."),
 		}
+	}
 
-	case http.StatusNotFound: // 404
+	var quotaErr *providers.QuotaError
+	if errors.As(err, &quotaErr) && quotaErr.Reason == providers.QuotaRateLimit {
 		return &FriendlyError{
-			Title:   "Service Not Found",
-			Message: "The upload service endpoint could not be found.",
-			Hint:    "The service may be temporarily unavailable or under maintenance. Please try again later.",
+			Title:   localization.T("Rate Limit Exceeded"),
+			Message: localization.T("You've made too many requests in a short period."),
+			Hint:    localization.T("Please wait a few minutes before trying again."),
 		}
+	}
 
-	case http.StatusRequestEntityTooLarge: // 413
-		return &FriendlyError{
-			Title:   "File Too Large",
-			Message: "The file you're trying to upload is too large for this provider.",
-			Hint:    "Please try a smaller file or use a different provider that supports larger files.",
-		}
+	statusCode := 0
+	var httpErr *providers.HTTPError
+	if errors.As(err, &httpErr) {
+		statusCode = httpErr.Status
+	}
 
-	case http.StatusTooManyRequests: // 429
+	switch statusCode {
+	case http.StatusNotFound: // 404
 		return &FriendlyError{
-			Title:   "Rate Limit Exceeded",
-			Message: "You've made too many requests in a short period.",
-			Hint:    "Please wait a few minutes before trying again.",
+			Title:   localization.T("Service Not Found"),
+			Message: localization.T("The upload service endpoint could not be found."),
+			Hint:    localization.T("The service may be temporarily unavailable or under maintenance. Please try again later."),
 		}
 
 	case http.StatusInternalServerError: // 500
 		return &FriendlyError{
-			Title:   "Server Error",
-			Message: "The server encountered an internal error.",
-			Hint:    "This is a temporary server issue. Please try again in a few minutes.",
+			Title:   localization.T("Server Error"),
+			Message: localization.T("The server encountered an internal error."),
+			Hint:    localization.T("This is a temporary server issue. Please try again in a few minutes."),
 		}
 
 	case http.StatusBadGateway: // 502
 		return &FriendlyError{
-			Title:   "Bad Gateway",
-			Message: "The server received an invalid response from an upstream server.",
-			Hint:    "This is a temporary server issue. Please try again in a few minutes.",
+			Title:   localization.T("Bad Gateway"),
+			Message: localization.T("The server received an invalid response from an upstream server."),
+			Hint:    localization.T("This is a temporary server issue. Please try again in a few minutes."),
 		}
 
 	case http.StatusServiceUnavailable: // 503
 		return &FriendlyError{
-			Title:   "Service Unavailable",
-			Message: "The service is temporarily unavailable.",
-			Hint:    "The server may be under maintenance. Please try again later.",
+			Title:   localization.T("Service Unavailable"),
+			Message: localization.T("The service is temporarily unavailable."),
+			Hint:    localization.T("The server may be under maintenance. Please try again later."),
 		}
 
 	case http.StatusGatewayTimeout: // 504
 		return &FriendlyError{
-			Title:   "Gateway Timeout",
-			Message: "The server did not receive a timely response.",
-			Hint:    "The service may be experiencing high load. Please try again in a few minutes.",
-		}
-
-	default:
-		// Generic server error
-		if statusCode >= 500 {
-			return &FriendlyError{
-				Title:   "Server Error",
-				Message: fmt.Sprintf("The server returned an error (HTTP %d).", statusCode),
-				Hint:    "This is a temporary issue. Please try again later.",
-			}
-		}
-
-		// Check for provider-specific error messages
-		if strings.Contains(errMsg, "server returned error") {
-			// Extract error message after "server returned error:"
-			parts := strings.Split(err.Error(), ":")
-			if len(parts) >= 2 {
-				serverMsg := strings.TrimSpace(parts[len(parts)-1])
-				return &FriendlyError{
-					Title:   "Upload Failed",
-					Message: fmt.Sprintf("The server reported an error: %s", serverMsg),
-					Hint:    "Please check your file and try again.",
-				}
-			}
+			Title:   localization.T("Gateway Timeout"),
+			Message: localization.T("The server did not receive a timely response."),
+			Hint:    localization.T("The service may be experiencing high load. Please try again in a few minutes."),
 		}
+	}
 
+	if statusCode >= 500 {
 		return &FriendlyError{
-			Title:   "Server Error",
-			Message: "The server encountered an error while processing your request.",
-			Hint:    "Please try again. If the problem persists, try a different provider.",
+			Title:   localization.T("Server Error"),
+			Message: fmt.Sprintf(localization.T("The server returned an error (HTTP %d)."), statusCode),
+			Hint:    localization.T("This is a temporary issue. Please try again later."),
 		}
 	}
+
+	return &FriendlyError{
+		Title:   localization.T("Server Error"),
+		Message: localization.T("The server encountered an error while processing your request."),
+		Hint:    localization.T("Please try again. If the problem persists, try a different provider."),
+	}
 }
 
 // makeValidationError создает дружественное сообщение для ошибок валидации
 func makeValidationError(err error) *FriendlyError {
-	errMsg := strings.ToLower(err.Error())
+	var validationErr *upload.ValidationError
+	if errors.As(err, &validationErr) {
+		return makePreUploadError(validationErr)
+	}
 
-	if strings.Contains(errMsg, "too large") || strings.Contains(errMsg, "413") {
+	var infectedErr *scan.InfectedError
+	if errors.As(err, &infectedErr) {
 		return &FriendlyError{
-			Title:   "File Too Large",
-			Message: "The file exceeds the maximum size allowed by this provider.",
-			Hint:    "Please try a smaller file or use a different provider.",
+			Title:   localization.T("Threat Detected"),
+			Message: fmt.Sprintf(localization.T("%s found %s in %s. The file was not uploaded."), infectedErr.Scanner, infectedErr.Threat, filepath.Base(infectedErr.Path)),
+			Hint:    localization.T("Do not share this file. If you are sure it is a false positive, use Upload anyway on its card."),
 		}
 	}
 
-	if strings.Contains(errMsg, "invalid") || strings.Contains(errMsg, "400") {
+	var scanErr *scan.FailedError
+	if errors.As(err, &scanErr) {
 		return &FriendlyError{
-			Title:   "Invalid File",
-			Message: "The file or request parameters are not valid.",
-			Hint:    "Please make sure you selected a valid file and try again.",
+			Title:   localization.T("Virus Scan Failed"),
+			Message: fmt.Sprintf(localization.T("%s could not check the file: %v"), scanErr.Scanner, scanErr.Err),
+			Hint:    localization.T("Check the virus scan settings, or use Upload anyway on its card to upload without a check."),
 		}
 	}
 
-	return &FriendlyError{
-		Title:   "Validation Error",
-		Message: "The file or request could not be validated.",
-		Hint:    "Please check your file and try again.",
+	var quotaErr *providers.QuotaError
+	if errors.As(err, &quotaErr) && quotaErr.Reason == providers.QuotaFileTooLarge {
+		return &FriendlyError{
+			Title:   localization.T("File Too Large"),
+			Message: localization.T("The file you're trying to upload is too large for this provider."),
+			Hint:    localization.T("Please try a smaller file or use a different provider that supports larger files."),
+		}
+	}
+
+	var httpErr *providers.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Status == http.StatusBadRequest {
+		return &FriendlyError{
+			Title:   localization.T("Invalid Request"),
+			Message: localization.T("The server could not process your request."),
+			Hint:    localization.T("Please try selecting the file again. If the problem persists, the file may not be supported."),
+		}
 	}
-}
 
-// makeUnknownError создает дружественное сообщение для неизвестных ошибок
-func makeUnknownError(err error) *FriendlyError {
 	return &FriendlyError{
-		Title:   "Unexpected Error",
-		Message: "An unexpected error occurred.",
-		Hint:    fmt.Sprintf("Technical details: %s", err.Error()),
+		Title:   localization.T("Validation Error"),
+		Message: localization.T("The file or request could not be validated."),
+		Hint:    localization.T("Please check your file and try again."),
 	}
 }
 
-// extractStatusCode извлекает HTTP статус код из текста ошибки
-func extractStatusCode(errMsg string) int {
-	// Ищем паттерны типа "status 404", "status code 500", etc.
-	statusPatterns := []string{
-		"status ", "status code ", "http status ", "code ",
-	}
-
-	for _, pattern := range statusPatterns {
-		if idx := strings.Index(errMsg, pattern); idx != -1 {
-			// Извлекаем число после паттерна
-			start := idx + len(pattern)
-			if start < len(errMsg) {
-				// Читаем цифры
-				numStr := ""
-				for i := start; i < len(errMsg) && len(numStr) < 3; i++ {
-					if errMsg[i] >= '0' && errMsg[i] <= '9' {
-						numStr += string(errMsg[i])
-					} else if len(numStr) > 0 {
-						break
-					}
-				}
-
-				// Конвертируем в число
-				var code int
-				fmt.Sscanf(numStr, "%d", &code)
-				if code >= 100 && code < 600 {
-					return code
-				}
+// scanStopped сообщает, что загрузку остановила проверка на вирусы: найдена угроза или проверка не удалась
+func scanStopped(err error) bool {
+	var infectedErr *scan.InfectedError
+	var scanErr *scan.FailedError
+	return errors.As(err, &infectedErr) || errors.As(err, &scanErr)
+}
+
+// makePreUploadError создает дружественное сообщение для ошибок проверки перед загрузкой
+func makePreUploadError(err *upload.ValidationError) *FriendlyError {
+	switch err.Err {
+	case upload.ErrAPIKeyMissing:
+		if err.Reason != nil {
+			return &FriendlyError{
+				Title:   localization.T("API Key Required"),
+				Message: fmt.Sprintf(localization.T("%s API key is not valid: %v"), err.Provider, err.Reason),
+				Hint:    localization.T("Please enter your API key in Settings."),
 			}
 		}
+		return &FriendlyError{
+			Title:   localization.T("API Key Required"),
+			Message: fmt.Sprintf(localization.T("%s requires an API key."), err.Provider),
+			Hint:    localization.T("Please enter your API key in Settings."),
+		}
+
+	case upload.ErrSettings:
+		return &FriendlyError{
+			Title:   localization.T("Provider Not Configured"),
+			Message: fmt.Sprintf(localization.T("%s settings are incomplete: %v"), err.Provider, err.Reason),
+			Hint:    localization.T("Please fill in the provider fields in Settings."),
+		}
+
+	case upload.ErrInvalidURL:
+		return &FriendlyError{
+			Title:   localization.T("Invalid URL"),
+			Message: localization.T("The source link is not a valid web address."),
+			Hint:    localization.T("Please enter a full link starting with http:// or https://."),
+		}
+
+	case upload.ErrFileNotFound:
+		return &FriendlyError{
+			Title:   localization.T("File Not Found"),
+			Message: localization.T("The selected file could not be found."),
+			Hint:    localization.T("The file may have been moved or deleted. Please select the file again."),
+		}
+
+	case upload.ErrNotRegularFile:
+		return &FriendlyError{
+			Title:   localization.T("Invalid File"),
+			Message: localization.T("The selected item is not a regular file."),
+			Hint:    localization.T("Please select a file, not a folder."),
+		}
+
+	case upload.ErrEmptyFile:
+		return &FriendlyError{
+			Title:   localization.T("Empty File"),
+			Message: localization.T("The selected file is empty."),
+			Hint:    localization.T("Please select a file that contains data."),
+		}
+
+	case upload.ErrFileType:
+		return &FriendlyError{
+			Title:   localization.T("File Type Not Allowed"),
+			Message: fmt.Sprintf(localization.T("%s does not accept %s files."), err.Provider, strings.ToLower(filepath.Ext(err.Path))),
+			Hint:    localization.T("Please upload this file to a different provider."),
+		}
+
+	case upload.ErrFileTooLarge:
+		return &FriendlyError{
+			Title: localization.T("File Too Large"),
+			Message: fmt.Sprintf(localization.T("The file is %s, but %s accepts files up to %s."),
+				localization.FormatSize(err.Size), err.Provider, localization.FormatSize(err.Limit)),
+			Hint: localization.T("Please try a smaller file or use a different provider that supports larger files."),
+		}
+	}
+
+	return &FriendlyError{
+		Title:   localization.T("Permission Denied"),
+		Message: localization.T("You don't have permission to access this file."),
+		Hint:    localization.T("Please check the file permissions or try selecting a different file."),
 	}
+}
 
-	return 0
+// makeUnknownError создает дружественное сообщение для неизвестных ошибок
+func makeUnknownError(err error) *FriendlyError {
+	return &FriendlyError{
+		Title:   localization.T("Unexpected Error"),
+		Message: localization.T("An unexpected error occurred."),
+		Hint:    fmt.Sprintf(localization.T("Technical details: %s"), err.Error()),
+	}
 }
 
 // FormatErrorMessage форматирует FriendlyError в строку для отображения
@@ -425,7 +472,7 @@ func FormatErrorMessage(fe *FriendlyError) string {
 
 	if fe.Hint != "" {
 		sb.WriteString("\n\n")
-		sb.WriteString("💡 Tip: ")
+		sb.WriteString("💡 " + localization.T("Tip:") + " ")
 		sb.WriteString(fe.Hint)
 	}
 
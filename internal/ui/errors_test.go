@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/providers"
+	"multiUploader/internal/scan"
+	"multiUploader/internal/upload"
+)
+
+// TestMakeFriendly проверяет сопоставление типизированных ошибок и сообщений
+func TestMakeFriendly(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		title string
+	}{
+		{"Cancelled", fmt.Errorf("upload parts failed: %w", providers.ErrCancelled), "Upload Cancelled"},
+		{"Context cancelled", context.Canceled, "Upload Cancelled"},
+		{"Invalid key", &providers.AuthError{Err: &providers.HTTPError{Status: 401}}, "Invalid API Key"},
+		{"Forbidden", &providers.AuthError{Forbidden: true, Err: &providers.HTTPError{Status: 403}}, "Access Denied"},
+		{"Too large", &providers.QuotaError{Reason: providers.QuotaFileTooLarge, Err: &providers.HTTPError{Status: 413}}, "File Too Large"},
+		{"Rate limit", &providers.QuotaError{Reason: providers.QuotaRateLimit, Err: &providers.HTTPError{Status: 429}}, "Rate Limit Exceeded"},
+		{"Bad request", &providers.HTTPError{Status: 400}, "Invalid Request"},
+		{"Bad gateway", fmt.Errorf("start upload failed: %w", &providers.HTTPError{Status: 502}), "Bad Gateway"},
+		{"Server message", &providers.ServerError{Op: "upload", Message: "quota"}, "Upload Failed"},
+		{"Missing file", &os.PathError{Op: "open", Path: "/x", Err: os.ErrNotExist}, "File Not Found"},
+		{"Missing key", &upload.ValidationError{Err: upload.ErrAPIKeyMissing, Provider: "Rootz"}, "API Key Required"},
+		{"Malformed key", upload.KeyError("Telegram", "123", fmt.Errorf("bot token must look like 123456:ABC")), "API Key Required"},
+		{"Empty file", &upload.ValidationError{Err: upload.ErrEmptyFile}, "Empty File"},
+		{"Over limit", &upload.ValidationError{Err: upload.ErrFileTooLarge, Size: 2048, Limit: 1024}, "File Too Large"},
+		{"Blocked type", &upload.ValidationError{Err: upload.ErrFileType, Provider: "DataVaults", Path: "setup.exe"}, "File Type Not Allowed"},
+		{"Infected", &scan.InfectedError{Scanner: "ClamAV", Path: "/tmp/eicar.com", Threat: "Eicar-Signature"}, "Threat Detected"},
+		{"Scan failed", &scan.FailedError{Scanner: "ClamAV", Err: &net.OpError{Op: "dial", Net: "unix", Err: syscall.ECONNREFUSED}}, "Virus Scan Failed"},
+		{"Unknown", fmt.Errorf("something odd"), "Unexpected Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MakeFriendly(tt.err).Title; got != tt.title {
+				t.Errorf("MakeFriendly().Title = %q, want %q", got, tt.title)
+			}
+		})
+	}
+
+	if MakeFriendly(nil) != nil {
+		t.Error("MakeFriendly(nil) should be nil")
+	}
+}
+
+// TestMakeFriendlyProviderErrors проверяет сообщения для цепочек ошибок в том виде,
+// в каком их возвращают провайдеры: с обертками этапов, номеров частей и попыток
+func TestMakeFriendlyProviderErrors(t *testing.T) {
+	// partErr оборачивает ошибку так же, как загрузка частями
+	partErr := func(err error) error {
+		return fmt.Errorf("upload parts failed: %w", fmt.Errorf("failed to upload part 3: %w", fmt.Errorf("%w (after 4 attempts)", err)))
+	}
+	// requestErr ошибка http.Client для запроса PUT
+	requestErr := func(err error) error {
+		return &url.Error{Op: "Put", URL: "https://upload.example.com/part/3", Err: err}
+	}
+
+	tests := []struct {
+		name  string
+		err   error
+		title string
+	}{
+		{"DNS", partErr(requestErr(&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "upload.example.com", IsNotFound: true}})), "DNS Lookup Failed"},
+		{"Refused", requestErr(&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}), "Connection Refused"},
+		{"Reset", partErr(requestErr(&net.OpError{Op: "write", Net: "tcp", Err: os.NewSyscallError("write", syscall.ECONNRESET)})), "Network Error"},
+		{"Idle", partErr(requestErr(&httpclient.IdleTimeoutError{Idle: 30 * time.Second})), "Connection Timeout"},
+		{"Deadline", fmt.Errorf("init failed: %w", requestErr(context.DeadlineExceeded)), "Connection Timeout"},
+		{"Short read", partErr(fmt.Errorf("failed to read part 3: %w", io.ErrUnexpectedEOF)), "File Read Error"},
+		{"No access", fmt.Errorf("failed to open file: %w", &os.PathError{Op: "open", Path: "/x", Err: os.ErrPermission}), "Permission Denied"},
+		{"Unavailable", fmt.Errorf("complete failed: %w", &providers.HTTPError{Op: "complete upload", Status: 503, Body: "maintenance"}), "Service Unavailable"},
+		{"Not found", fmt.Errorf("init failed: %w", &providers.HTTPError{Op: "start upload", Status: 404}), "Service Not Found"},
+		{"Other 5xx", partErr(&providers.HTTPError{Op: "upload", Status: 507}), "Server Error"},
+		{"Expired URL", partErr(&providers.AuthError{Forbidden: true, Err: &providers.HTTPError{Op: "upload", Status: 403, Body: "Request has expired"}}), "Access Denied"},
+		{"Rate limit", fmt.Errorf("init failed: %w", &providers.QuotaError{Reason: providers.QuotaRateLimit, RetryAfter: time.Minute, Err: &providers.HTTPError{Status: 429}}), "Rate Limit Exceeded"},
+		{"Empty server message", fmt.Errorf("complete failed: %w", &providers.ServerError{Op: "complete"}), "Server Error"},
+		{"Settings", &upload.ValidationError{Err: upload.ErrSettings, Provider: "tus", Reason: errors.New("endpoint is required")}, "Provider Not Configured"},
+		{"Untyped", errors.New("telegram: Bad Request: chat not found"), "Unexpected Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MakeFriendly(tt.err).Title; got != tt.title {
+				t.Errorf("MakeFriendly().Title = %q, want %q", got, tt.title)
+			}
+		})
+	}
+
+	// Текст ошибки без типа остается в подсказке, иначе пользователь не узнает причину
+	if hint := MakeFriendly(errors.New("telegram: Bad Request: chat not found")).Hint; !strings.Contains(hint, "chat not found") {
+		t.Errorf("Hint = %q, want the original error text", hint)
+	}
+}
+
+// FuzzClassifyError проверяет, что обертки не меняют тип ошибки,
+// а MakeFriendly всегда возвращает заголовок и сообщение
+func FuzzClassifyError(f *testing.F) {
+	f.Add(uint8(0), 502, "bad gateway", uint8(2))
+	f.Add(uint8(1), 401, "", uint8(0))
+	f.Add(uint8(2), 429, "slow down", uint8(5))
+	f.Add(uint8(3), 0, "quota", uint8(1))
+	f.Add(uint8(4), 0, "/tmp/file.bin", uint8(3))
+	f.Add(uint8(5), 0, "no such host", uint8(1))
+	f.Add(uint8(6), 0, "something odd", uint8(0))
+
+	f.Fuzz(func(t *testing.T, kind uint8, status int, text string, wraps uint8) {
+		var base error
+		switch kind % 8 {
+		case 0:
+			base = &providers.HTTPError{Op: "upload", Status: status, Body: text}
+		case 1:
+			base = &providers.AuthError{Forbidden: status%2 == 0, Err: &providers.HTTPError{Status: status, Body: text}}
+		case 2:
+			base = &providers.QuotaError{Reason: providers.QuotaReason(status % 2), Err: &providers.HTTPError{Status: status, Body: text}}
+		case 3:
+			base = &providers.ServerError{Op: "complete", Message: text}
+		case 4:
+			base = &os.PathError{Op: "open", Path: text, Err: syscall.Errno(status & 0xff)}
+		case 5:
+			base = &net.DNSError{Err: text, Name: text, IsTimeout: status%2 == 0}
+		case 6:
+			base = errors.New(text)
+		case 7:
+			base = &upload.ValidationError{Err: upload.ErrFileTooLarge, Provider: text, Size: int64(status), Limit: 1}
+		}
+
+		wrapped := base
+		for i := range int(wraps % 8) {
+			wrapped = fmt.Errorf("step %d: %w", i, wrapped)
+		}
+
+		if got, want := classifyError(wrapped), classifyError(base); got != want {
+			t.Errorf("classifyError(wrapped %T) = %d, want %d", base, got, want)
+		}
+		friendly := MakeFriendly(wrapped)
+		if friendly == nil || friendly.Title == "" || friendly.Message == "" {
+			t.Errorf("MakeFriendly(%T) = %+v, want title and message", base, friendly)
+		}
+		if msg := FormatErrorMessage(friendly); !strings.HasPrefix(msg, friendly.Title) {
+			t.Errorf("FormatErrorMessage() = %q, want it to start with the title", msg)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"multiUploader/internal/localization"
+)
+
+const (
+	// expiryReminderDays за сколько дней до окончания премиума или срока ключа приходит напоминание
+	expiryReminderDays = 3
+	// expiryCheckInterval как часто сроки проверяются, пока приложение открыто
+	expiryCheckInterval = 6 * time.Hour
+)
+
+// startExpiryReminders проверяет сроки аккаунтов при запуске и затем раз в expiryCheckInterval
+func (a *App) startExpiryReminders() {
+	a.checkExpiry()
+
+	go func() {
+		ticker := time.NewTicker(expiryCheckInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			fyne.Do(a.checkExpiry)
+		}
+	}()
+}
+
+// checkExpiry напоминает уведомлением о скором окончании премиума или срока ключа включенных провайдеров
+// О каждом дне окончания напоминание приходит один раз (вызывается из главного потока)
+func (a *App) checkExpiry() {
+	now := time.Now()
+	for _, name := range a.providerOrder {
+		if !a.config.IsProviderEnabled(name) {
+			continue
+		}
+		expires := a.config.GetProviderConfig(name).Expires
+		if !expiryReminderDue(expires, a.config.GetExpiryReminded(name), now) {
+			continue
+		}
+		a.config.SetExpiryReminded(name, expires)
+		a.SendNotification(localization.T("Account expiring"), expiryText(name, expires, now))
+	}
+}
+
+// expiryDay день окончания срока в местном времени
+func expiryDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// expiryDaysLeft сколько дней осталось до дня окончания expires (0 - сегодня, меньше нуля - уже истек)
+func expiryDaysLeft(expires, now time.Time) int {
+	// Округление: из-за перевода часов в сутках бывает 23 или 25 часов
+	return int(math.Round(expiryDay(expires).Sub(expiryDay(now)).Hours() / 24))
+}
+
+// expiryReminderDue сообщает, что пора напомнить о дне окончания expires
+// reminded - день, о котором уже напоминали; истекший срок, о котором не напоминали, тоже напоминается
+func expiryReminderDue(expires, reminded, now time.Time) bool {
+	if expires.IsZero() || reminded.Equal(expires) {
+		return false
+	}
+	return expiryDaysLeft(expires, now) <= expiryReminderDays
+}
+
+// expiryText текст напоминания об окончании срока провайдера name
+func expiryText(name string, expires, now time.Time) string {
+	date := localization.FormatDate(expires)
+	switch days := expiryDaysLeft(expires, now); {
+	case days < 0:
+		return fmt.Sprintf(localization.T("%s account expired on %s. Renew it to keep uploading."), name, date)
+	case days == 0:
+		return fmt.Sprintf(localization.T("%s account expires today. Renew it to keep uploading."), name)
+	default:
+		return fmt.Sprintf(localization.TN("%s account expires in %d days, on %s. Renew it to keep uploading.", days), name, days, date)
+	}
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+// TestExpiryReminderDue проверяет, когда приходит напоминание об окончании срока
+func TestExpiryReminderDue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 18, 30, 0, 0, time.Local)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		name     string
+		expires  time.Time
+		reminded time.Time
+		want     bool
+	}{
+		{"no expiry date", time.Time{}, time.Time{}, false},
+		{"far away", day(20), time.Time{}, false},
+		{"one day before the window", day(14), time.Time{}, false},
+		{"first day of the window", day(13), time.Time{}, true},
+		{"today", day(10), time.Time{}, true},
+		{"already expired", day(1), time.Time{}, true},
+		{"already reminded", day(12), day(12), false},
+		{"new date after renewal", day(12), day(1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expiryReminderDue(tt.expires, tt.reminded, now); got != tt.want {
+				t.Errorf("expiryReminderDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := expiryDaysLeft(day(13), now); got != 3 {
+		t.Errorf("expiryDaysLeft() = %d, want 3", got)
+	}
+	if got := expiryDaysLeft(time.Date(2026, 3, 9, 23, 0, 0, 0, time.Local), now); got != -1 {
+		t.Errorf("expiryDaysLeft() of yesterday = %d, want -1", got)
+	}
+}
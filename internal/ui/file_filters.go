@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/config"
+	"multiUploader/internal/history"
+	"multiUploader/internal/localization"
+)
+
+// maxRecentFiles сколько недавних файлов показывается в быстром выборе
+const maxRecentFiles = 10
+
+// fileFilter группа файлов в диалоге выбора файла
+type fileFilter struct {
+	id   string // ключ в сохраненном состоянии интерфейса
+	name string // название до перевода
+	// extensions расширения с точкой (nil - все файлы)
+	extensions []string
+}
+
+// fileFilters группы файлов; первая показывает все файлы
+var fileFilters = []fileFilter{
+	{id: "", name: "All files"},
+	{id: "images", name: "Images", extensions: []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".bmp", ".tif", ".tiff", ".heic", ".svg"}},
+	{id: "archives", name: "Archives", extensions: []string{".zip", ".rar", ".7z", ".tar", ".gz", ".tgz", ".bz2", ".xz", ".zst", ".iso"}},
+	{id: "video", name: "Video", extensions: []string{".mp4", ".mkv", ".avi", ".mov", ".webm", ".wmv", ".flv", ".m4v", ".mpg", ".mpeg", ".ts"}},
+}
+
+// newFileFilterSelect создает выбор группы файлов для диалога выбора файла
+// Выбор сохраняется и восстанавливается при следующем запуске
+func (t *UploadTab) newFileFilterSelect() *widget.Select {
+	names := make([]string, len(fileFilters))
+	for i, f := range fileFilters {
+		names[i] = localization.T(f.name)
+	}
+
+	sel := widget.NewSelect(names, nil)
+	saved := t.app.Config().GetUIState().FileFilter
+	sel.SetSelectedIndex(0)
+	for i, f := range fileFilters {
+		if f.id == saved {
+			sel.SetSelectedIndex(i)
+		}
+	}
+	sel.OnChanged = func(string) {
+		id := fileFilters[sel.SelectedIndex()].id
+		t.rememberUIState(func(state *config.UIState) { state.FileFilter = id })
+	}
+	return sel
+}
+
+// selectedFileFilter фильтр диалога выбора файла (nil - все файлы)
+func (t *UploadTab) selectedFileFilter() storage.FileFilter {
+	i := t.fileFilterSelect.SelectedIndex()
+	if i < 0 || fileFilters[i].extensions == nil {
+		return nil
+	}
+	return storage.NewExtensionFileFilter(fileFilters[i].extensions)
+}
+
+// showRecentFiles показывает меню недавно загруженных файлов, которые еще есть на диске
+func (t *UploadTab) showRecentFiles() {
+	var items []*fyne.MenuItem
+	for _, path := range history.RecentFiles(t.app.History().Entries(), maxRecentFiles) {
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		items = append(items, fyne.NewMenuItem(recentFileLabel(path), func() {
+			t.pickFile(path)
+		}))
+	}
+	if len(items) == 0 {
+		empty := fyne.NewMenuItem(localization.T("No recent files"), nil)
+		empty.Disabled = true
+		items = append(items, empty)
+	}
+
+	canvas := fyne.CurrentApp().Driver().CanvasForObject(t.recentBtn)
+	widget.ShowPopUpMenuAtRelativePosition(fyne.NewMenu("", items...), canvas,
+		fyne.NewPos(0, t.recentBtn.Size().Height), t.recentBtn)
+}
+
+// recentFileLabel подпись недавнего файла: имя и папка, в которой он лежит
+func recentFileLabel(path string) string {
+	return filepath.Base(path) + " — " + filepath.Dir(path)
+}
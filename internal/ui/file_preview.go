@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+	"multiUploader/internal/preview"
+)
+
+// previewThumbSize размер миниатюры картинки в предпросмотре
+var previewThumbSize = fyne.NewSize(160, 120)
+
+// filePreview панель предпросмотра выбранного файла: миниатюра или значок, тип, дата и содержимое
+type filePreview struct {
+	box     *fyne.Container
+	thumb   *canvas.Image
+	icon    *widget.Icon
+	details *widget.Label
+	content *widget.Label
+
+	// path файл, для которого показан предпросмотр (устаревшие результаты отбрасываются)
+	path string
+}
+
+// newFilePreview создает скрытую панель предпросмотра
+func newFilePreview() *filePreview {
+	p := &filePreview{}
+	p.thumb = &canvas.Image{FillMode: canvas.ImageFillContain, ScaleMode: canvas.ImageScaleSmooth}
+	p.thumb.SetMinSize(previewThumbSize)
+	p.icon = widget.NewIcon(theme.FileIcon())
+	p.details = widget.NewLabel("")
+	p.details.Wrapping = fyne.TextWrapWord
+	p.content = widget.NewLabelWithStyle("", readingAlign(), fyne.TextStyle{Monospace: true})
+	p.content.Truncation = fyne.TextTruncateEllipsis
+
+	p.box = newRow(container.NewStack(p.thumb, p.icon), nil,
+		container.NewVBox(p.details, p.content))
+	p.box.Hide()
+	return p
+}
+
+// show читает сведения о файле в фоне и показывает их
+func (p *filePreview) show(path string) {
+	p.path = path
+	p.thumb.Hide()
+	p.icon.SetResource(theme.FileIcon())
+	p.icon.Show()
+	p.details.SetText(localization.T("Reading file…"))
+	p.content.Hide()
+	p.box.Show()
+
+	go func() {
+		info, err := preview.Inspect(path)
+		fyne.Do(func() {
+			if p.path != path {
+				return
+			}
+			if err != nil {
+				p.box.Hide()
+				return
+			}
+			p.render(path, info)
+		})
+	}()
+}
+
+// clear скрывает предпросмотр (выбрана ссылка вместо файла)
+func (p *filePreview) clear() {
+	p.path = ""
+	p.box.Hide()
+}
+
+// render показывает сведения о файле (вызывается из главного потока)
+func (p *filePreview) render(path string, info preview.Info) {
+	lines := []string{
+		fmt.Sprintf(localization.T("Type: %s"), info.MIMEType),
+		fmt.Sprintf(localization.T("Modified: %s"), localization.FormatDateTime(info.ModTime)),
+	}
+	var content string
+
+	switch info.Kind {
+	case preview.KindImage:
+		p.thumb.File = path
+		p.thumb.Refresh()
+		p.thumb.Show()
+		p.icon.Hide()
+		if info.Width > 0 {
+			lines = append(lines, fmt.Sprintf(localization.T("Dimensions: %d×%d"), info.Width, info.Height))
+		}
+	case preview.KindText:
+		p.icon.SetResource(theme.DocumentIcon())
+		content = info.TextHead
+	case preview.KindAudio, preview.KindVideo:
+		p.icon.SetResource(theme.MediaVideoIcon())
+		if info.Kind == preview.KindAudio {
+			p.icon.SetResource(theme.MediaMusicIcon())
+		}
+		if info.Duration > 0 {
+			lines = append(lines, fmt.Sprintf(localization.T("Duration: %s"), localization.FormatDuration(info.Duration)))
+		}
+		if info.Title != "" {
+			lines = append(lines, fmt.Sprintf(localization.T("Title: %s"), info.Title))
+		}
+		if info.Artist != "" {
+			lines = append(lines, fmt.Sprintf(localization.T("Artist: %s"), info.Artist))
+		}
+	case preview.KindArchive:
+		p.icon.SetResource(theme.FolderIcon())
+		entries := info.Entries
+		if info.More {
+			entries = append(entries, localization.T("…and more"))
+		}
+		content = strings.Join(entries, "\n")
+	}
+
+	p.details.SetText(strings.Join(lines, "\n"))
+	p.content.SetText(content)
+	setVisible(p.content, content != "")
+}
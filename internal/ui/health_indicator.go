@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/health"
+	"multiUploader/internal/localization"
+	"multiUploader/internal/logging"
+	"multiUploader/internal/metrics"
+	"multiUploader/internal/providers"
+)
+
+const (
+	// healthCheckInterval период фоновой проверки соединения
+	healthCheckInterval = 5 * time.Minute
+	// healthCheckTimeout ограничивает одну проверку
+	healthCheckTimeout = 20 * time.Second
+)
+
+// HealthIndicator значок состояния соединения в панели инструментов
+// Проверяет интернет и доступность включенных провайдеров при запуске и периодически
+type HealthIndicator struct {
+	app *App
+
+	action  *widget.ToolbarAction
+	toolbar *widget.Toolbar
+
+	mu       sync.Mutex
+	report   health.Report
+	checking bool
+}
+
+// NewHealthIndicator создает индикатор (проверки запускаются в Start)
+func NewHealthIndicator(app *App) *HealthIndicator {
+	return &HealthIndicator{app: app}
+}
+
+// Build создает панель инструментов со значком состояния
+func (h *HealthIndicator) Build() fyne.CanvasObject {
+	h.action = widget.NewToolbarAction(h.icon(), h.showDetails)
+	h.toolbar = widget.NewToolbar(h.action)
+	return h.toolbar
+}
+
+// Start запускает первую проверку и периодические проверки в фоне
+func (h *HealthIndicator) Start() {
+	h.Check()
+
+	go func() {
+		ticker := time.NewTicker(healthCheckInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			fyne.Do(h.Check)
+		}
+	}()
+}
+
+// Check запускает проверку соединения (вызывается из главного потока)
+func (h *HealthIndicator) Check() {
+	h.mu.Lock()
+	if h.checking {
+		h.mu.Unlock()
+		return
+	}
+	h.checking = true
+	h.mu.Unlock()
+
+	// Проверяем только включенные провайдеры, которые умеют проверять свой сервер
+	// Заданная в настройках страница состояния заменяет проверку сервера
+	probers := make(map[string]providers.Prober)
+	for _, provider := range h.app.GetEnabledProviders() {
+		if statusURL := h.app.Config().GetProviderConfig(provider.Name()).StatusURL; statusURL != "" {
+			probers[provider.Name()] = health.StatusPage{URL: statusURL}
+		} else if prober, ok := provider.(providers.Prober); ok {
+			probers[provider.Name()] = prober
+		}
+	}
+
+	h.refreshIcon()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+		defer cancel()
+
+		report := health.Check(ctx, probers)
+		for _, p := range report.Providers {
+			metrics.Default().ProbeFinished(p.Name, p.Latency, p.Err == nil)
+			if p.Err != nil {
+				logging.ErrorWithError("Provider is unreachable", p.Err, "provider", p.Name)
+			}
+		}
+
+		h.mu.Lock()
+		h.report = report
+		h.checking = false
+		h.mu.Unlock()
+
+		fyne.Do(func() {
+			h.refreshIcon()
+			if h.app.uploadTab != nil {
+				h.app.uploadTab.updateProviderStatus()
+			}
+		})
+	}()
+}
+
+// Report возвращает результат последней проверки
+func (h *HealthIndicator) Report() health.Report {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.report
+}
+
+// ProviderStatus результат последней проверки провайдера (false - провайдер не проверялся)
+func (h *HealthIndicator) ProviderStatus(name string) (health.ProviderResult, bool) {
+	for _, p := range h.Report().Providers {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return health.ProviderResult{}, false
+}
+
+// refreshIcon обновляет значок по последней проверке (вызывается из главного потока)
+func (h *HealthIndicator) refreshIcon() {
+	if h.action == nil {
+		return
+	}
+	h.action.SetIcon(h.icon())
+}
+
+// icon значок для текущего состояния
+func (h *HealthIndicator) icon() fyne.Resource {
+	h.mu.Lock()
+	status, checking := h.report.Status, h.checking
+	h.mu.Unlock()
+
+	if checking && status == health.StatusUnknown {
+		return theme.ViewRefreshIcon()
+	}
+	switch status {
+	case health.StatusOnline:
+		return theme.NewSuccessThemedResource(theme.ConfirmIcon())
+	case health.StatusDegraded:
+		return theme.NewWarningThemedResource(theme.WarningIcon())
+	case health.StatusOffline:
+		return theme.NewErrorThemedResource(theme.ErrorIcon())
+	default:
+		return theme.QuestionIcon()
+	}
+}
+
+// statusText описание итогового состояния
+func statusText(status health.Status) string {
+	switch status {
+	case health.StatusOnline:
+		return localization.T("Connection OK")
+	case health.StatusDegraded:
+		return localization.T("Some providers are unreachable")
+	case health.StatusOffline:
+		return localization.T("No internet connection")
+	default:
+		return localization.T("Connection not checked yet")
+	}
+}
+
+// showDetails показывает результат последней проверки по каждому провайдеру
+func (h *HealthIndicator) showDetails() {
+	report := h.Report()
+
+	title := widget.NewLabelWithStyle(statusText(report.Status), readingAlign(), fyne.TextStyle{Bold: true})
+	content := container.NewVBox(title)
+
+	if report.Status != health.StatusUnknown {
+		internet := localization.T("Connected")
+		if report.Internet != nil {
+			internet = localization.T("No connection")
+		}
+		content.Add(widget.NewLabel(localization.T("Internet:") + " " + internet))
+
+		for _, p := range report.Providers {
+			text := fmt.Sprintf(localization.T("%s: reachable (%d ms)"), p.Name, p.Latency.Milliseconds())
+			var icon fyne.Resource = theme.NewSuccessThemedResource(theme.ConfirmIcon())
+			if p.Err != nil {
+				text = fmt.Sprintf(localization.T("%s: unreachable"), p.Name)
+				icon = theme.NewErrorThemedResource(theme.ErrorIcon())
+			}
+			content.Add(newRow(widget.NewIcon(icon), nil, widget.NewLabel(text)))
+		}
+
+		checked := widget.NewLabel(fmt.Sprintf(localization.T("Last checked at %s"), localization.FormatTime(report.CheckedAt)))
+		checked.Importance = widget.LowImportance
+		content.Add(checked)
+	}
+
+	d := dialog.NewCustom(localization.T("Connection"), localization.T("Close"), content, h.app.MainWindow())
+	checkBtn := widget.NewButtonWithIcon(localization.T("Check again"), theme.ViewRefreshIcon(), func() {
+		d.Hide()
+		h.Check()
+	})
+	d.SetButtons([]fyne.CanvasObject{checkBtn, widget.NewButton(localization.T("Close"), d.Hide)})
+	d.Show()
+}
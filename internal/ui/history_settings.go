@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/config"
+	"multiUploader/internal/history"
+	"multiUploader/internal/localization"
+	"multiUploader/internal/logging"
+)
+
+// errHistoryLimit неверное ограничение истории
+var errHistoryLimit = errors.New("history limits must be whole numbers")
+
+// historyForm настройки автоматической очистки истории
+type historyForm struct {
+	maxEntriesEntry *widget.Entry
+	maxDaysEntry    *widget.Entry
+	successfulCheck *widget.Check
+}
+
+// historyRetention переводит настройки очистки в правила истории
+func historyRetention(cfg config.HistoryConfig) history.Retention {
+	return history.Retention{
+		MaxEntries:     cfg.MaxEntries,
+		MaxAge:         time.Duration(cfg.MaxDays) * 24 * time.Hour,
+		SuccessfulOnly: cfg.SuccessfulOnly,
+	}
+}
+
+// applyHistoryRetention очищает историю по сохраненным настройкам
+func (a *App) applyHistoryRetention() {
+	if err := a.history.SetRetention(historyRetention(a.config.GetHistoryConfig())); err != nil {
+		logging.ErrorWithError("Failed to prune history", err)
+	}
+	if a.historyTab != nil {
+		a.historyTab.Refresh()
+	}
+}
+
+// buildHistorySettings создает секцию очистки истории
+func (t *SettingsTab) buildHistorySettings() fyne.CanvasObject {
+	form := &historyForm{
+		maxEntriesEntry: widget.NewEntry(),
+		maxDaysEntry:    widget.NewEntry(),
+		successfulCheck: widget.NewCheck(localization.T("Keep only successful uploads"), nil),
+	}
+	form.maxEntriesEntry.SetPlaceHolder(localization.T("all"))
+	form.maxDaysEntry.SetPlaceHolder(localization.T("no limit"))
+	t.historyForm = form
+
+	clearBtn := widget.NewButtonWithIcon(localization.T("Clear history"), theme.DeleteIcon(), t.onClearHistory)
+	clearBtn.Importance = widget.DangerImportance
+
+	hint := widget.NewLabel(localization.T("Older entries are deleted when the settings are saved, at start and after each upload. Leave a field empty to keep everything. Successful uploads are the ones that passed the integrity check (or were not checked) and whose link was not found dead."))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	return container.NewVBox(
+		widget.NewLabelWithStyle(localization.T("History"), readingAlign(), fyne.TextStyle{Bold: true}),
+		newRow(widget.NewLabel(localization.T("Keep last:")), widget.NewLabel(localization.T("entries")), form.maxEntriesEntry),
+		newRow(widget.NewLabel(localization.T("Keep for:")), widget.NewLabel(localization.T("days")), form.maxDaysEntry),
+		form.successfulCheck,
+		hint,
+		newHRow(clearBtn),
+	)
+}
+
+// onClearHistory удаляет всю историю после подтверждения
+func (t *SettingsTab) onClearHistory() {
+	count := len(t.app.History().Entries())
+	if count == 0 {
+		dialog.ShowInformation(localization.T("Clear history"), localization.T("History is already empty."), t.app.MainWindow())
+		return
+	}
+	message := fmt.Sprintf(localization.TN("Delete all %d history entries? Uploaded files stay on the hosts.", count), count)
+	confirm := dialog.NewConfirm(localization.T("Clear history"), message, func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := t.app.History().Clear(); err != nil {
+			logging.ErrorWithError("Failed to clear history", err)
+			dialog.ShowError(err, t.app.MainWindow())
+		}
+		t.app.historyTab.Refresh()
+	}, t.app.MainWindow())
+	confirm.SetConfirmText(localization.T("Clear history"))
+	confirm.Show()
+}
+
+// load показывает настройки очистки истории
+func (f *historyForm) load(cfg config.HistoryConfig) {
+	f.maxEntriesEntry.SetText(limitToText(cfg.MaxEntries))
+	f.maxDaysEntry.SetText(limitToText(cfg.MaxDays))
+	f.successfulCheck.SetChecked(cfg.SuccessfulOnly)
+}
+
+// config читает настройки очистки истории из формы
+func (f *historyForm) config() (config.HistoryConfig, error) {
+	maxEntries, err := textToLimit(f.maxEntriesEntry.Text)
+	if err != nil {
+		return config.HistoryConfig{}, err
+	}
+	maxDays, err := textToLimit(f.maxDaysEntry.Text)
+	if err != nil {
+		return config.HistoryConfig{}, err
+	}
+	return config.HistoryConfig{
+		MaxEntries:     maxEntries,
+		MaxDays:        maxDays,
+		SuccessfulOnly: f.successfulCheck.Checked,
+	}, nil
+}
+
+// limitToText конвертирует ограничение в текст поля (0 - пусто)
+func limitToText(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// textToLimit конвертирует текст поля в ограничение (пусто - 0, без ограничения)
+func textToLimit(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %q", errHistoryLimit, text)
+	}
+	return n, nil
+}
@@ -0,0 +1,455 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/history"
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/localization"
+	"multiUploader/internal/logging"
+	"multiUploader/internal/upload"
+)
+
+// linkCheckWorkers сколько ссылок проверяется одновременно
+const linkCheckWorkers = 8
+
+// HistoryTab представляет вкладку истории загрузок
+type HistoryTab struct {
+	app *App
+
+	list        *widget.List
+	emptyLabel  *widget.Label
+	filterEntry *widget.Entry
+	tagSelect   *widget.Select
+	exportBtn   *widget.Button
+	checkBtn    *widget.Button
+
+	// entries снимок истории с учетом фильтра, отображаемый в списке
+	entries []history.Entry
+	// checking идет проверка ссылок
+	checking bool
+}
+
+// NewHistoryTab создает новую вкладку истории
+func NewHistoryTab(app *App) *HistoryTab {
+	return &HistoryTab{app: app}
+}
+
+// Build создает UI вкладки истории
+func (t *HistoryTab) Build() fyne.CanvasObject {
+	t.filterEntry = widget.NewEntry()
+	t.filterEntry.SetPlaceHolder(localization.T("Filter by name, provider or link"))
+	t.filterEntry.OnChanged = func(string) { t.Refresh() }
+	t.tagSelect = widget.NewSelect(nil, func(string) { t.Refresh() })
+	t.exportBtn = widget.NewButtonWithIcon(localization.T("Export…"), theme.DocumentSaveIcon(), t.onExport)
+	t.checkBtn = widget.NewButtonWithIcon(localization.T("Check links"), theme.SearchIcon(), t.onCheckLinks)
+	t.entries = t.app.History().Entries()
+	t.updateTags(t.entries)
+
+	t.list = widget.NewList(
+		func() int { return len(t.entries) },
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("")
+			name.TextStyle = fyne.TextStyle{Bold: true}
+			name.Truncation = fyne.TextTruncateEllipsis
+			details := widget.NewLabel("")
+			details.Importance = widget.LowImportance
+			// Значки провайдера и проверки целостности
+			icons := container.NewHBox(widget.NewIcon(nil), widget.NewIcon(nil))
+			return newRow(icons, nil, container.NewVBox(name, details))
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			entry := t.entries[id]
+			row := item.(*fyne.Container)
+			labels := row.Objects[0].(*fyne.Container)
+			labels.Objects[0].(*widget.Label).SetText(entry.FileName)
+			details := labels.Objects[1].(*widget.Label)
+			text := fmt.Sprintf("%s · %s · %s",
+				entry.Provider,
+				localization.FormatDateTime(entry.UploadedAt),
+				localization.FormatSize(entry.Size),
+			)
+			details.Importance = widget.LowImportance
+			if entry.Link == upload.LinkDead {
+				text += " · " + localization.T("Link is dead")
+				details.Importance = widget.DangerImportance
+			}
+			if len(entry.Tags) > 0 {
+				text += " · " + strings.Join(entry.Tags, ", ")
+			}
+			details.SetText(text)
+			icons := row.Objects[1].(*fyne.Container)
+			icons.Objects[0].(*widget.Icon).SetResource(providerIcon(entry.Provider))
+			icons.Objects[1].(*widget.Icon).SetResource(integrityIcon(entry.Integrity))
+		},
+	)
+	t.list.OnSelected = func(id widget.ListItemID) {
+		t.list.UnselectAll()
+		t.showEntry(t.entries[id])
+	}
+
+	t.emptyLabel = widget.NewLabel(localization.T("No uploads yet"))
+	t.emptyLabel.Alignment = fyne.TextAlignCenter
+	t.emptyLabel.Hidden = len(t.entries) > 0
+	t.updateButtons()
+
+	toolbar := newRow(nil, newHRow(t.tagSelect, t.checkBtn, t.exportBtn), t.filterEntry)
+	return container.NewBorder(toolbar, nil, nil, nil, container.NewStack(t.list, container.NewCenter(t.emptyLabel)))
+}
+
+// Refresh перечитывает историю (вызывается из главного потока после новой загрузки)
+func (t *HistoryTab) Refresh() {
+	if t.list == nil {
+		return
+	}
+
+	all := t.app.History().Entries()
+	t.updateTags(all)
+	t.entries = history.Filter(history.FilterTag(all, t.selectedTag()), t.filterEntry.Text)
+	t.emptyLabel.Hidden = len(t.entries) > 0
+	t.emptyLabel.Refresh()
+	t.list.Refresh()
+	t.updateButtons()
+}
+
+// updateTags обновляет список меток для фильтра; выбор сбрасывается, если метки больше нет
+// Без меток в истории фильтр скрыт
+func (t *HistoryTab) updateTags(entries []history.Entry) {
+	tags := history.Tags(entries)
+	t.tagSelect.Hidden = len(tags) == 0
+
+	options := append([]string{localization.T("All tags")}, tags...)
+	selected := t.tagSelect.SelectedIndex()
+	if selected <= 0 || !slices.Contains(tags, t.tagSelect.Selected) {
+		selected = 0
+	}
+	// Selected задается напрямую: SetSelected вызвал бы OnChanged и повторный Refresh
+	t.tagSelect.Options = options
+	t.tagSelect.Selected = options[selected]
+	t.tagSelect.Refresh()
+}
+
+// selectedTag выбранная в фильтре метка; пустая строка - все записи
+func (t *HistoryTab) selectedTag() string {
+	if t.tagSelect.SelectedIndex() <= 0 {
+		return ""
+	}
+	return t.tagSelect.Selected
+}
+
+// updateButtons выключает экспорт и проверку ссылок, если записей нет
+func (t *HistoryTab) updateButtons() {
+	if len(t.entries) == 0 {
+		t.exportBtn.Disable()
+		t.checkBtn.Disable()
+		return
+	}
+	t.exportBtn.Enable()
+	if !t.checking {
+		t.checkBtn.Enable()
+	}
+}
+
+// onCheckLinks проверяет ссылки показанных записей и отмечает недоступные
+func (t *HistoryTab) onCheckLinks() {
+	var entries []history.Entry
+	var urls []string
+	for _, e := range t.entries {
+		if link := e.PrimaryLink(); link != "" {
+			entries = append(entries, e)
+			urls = append(urls, link)
+		}
+	}
+	if len(urls) == 0 {
+		return
+	}
+
+	t.checking = true
+	t.checkBtn.Disable()
+	store := t.app.History()
+
+	go func() {
+		var checked, dead atomic.Int32
+		// Результаты сохраняются одной записью файла в конце, а не по записи на каждую ссылку
+		statuses := make([]upload.LinkStatus, len(urls))
+		upload.CheckLinks(context.Background(), httpclient.Default(), urls, linkCheckWorkers, func(i int, status upload.LinkStatus) {
+			statuses[i] = status
+			if status == upload.LinkDead {
+				dead.Add(1)
+			}
+			n := checked.Add(1)
+			fyne.Do(func() {
+				t.checkBtn.SetText(fmt.Sprintf(localization.T("Checking links… %d/%d"), n, len(urls)))
+			})
+		})
+
+		now := time.Now()
+		changes := make(map[string]func(e *history.Entry), len(entries))
+		for i, entry := range entries {
+			status := statuses[i]
+			if status == "" {
+				continue
+			}
+			changes[entry.ID] = func(e *history.Entry) {
+				e.Link = status
+				e.LinkCheckedAt = now
+			}
+		}
+		if err := store.UpdateEach(changes); err != nil {
+			logging.ErrorWithError("Failed to save link statuses", err)
+		}
+
+		fyne.Do(func() {
+			t.checking = false
+			t.checkBtn.SetText(localization.T("Check links"))
+			t.Refresh()
+			dialog.ShowInformation(localization.T("Links checked"),
+				fmt.Sprintf(localization.T("%d of %d links are dead"), dead.Load(), len(urls)), t.app.MainWindow())
+		})
+	}()
+}
+
+// linkStatusText описание результата проверки ссылки для подробностей записи
+func linkStatusText(e history.Entry) string {
+	var status string
+	switch e.Link {
+	case upload.LinkAlive:
+		status = localization.T("Link works")
+	case upload.LinkDead:
+		status = localization.T("Link is dead")
+	default:
+		status = localization.T("Link could not be checked")
+	}
+	return fmt.Sprintf("%s (%s)", status, localization.FormatDateTime(e.LinkCheckedAt))
+}
+
+// onExport сохраняет показанные записи (с учетом фильтра) в CSV или JSON
+// Формат выбирается по расширению файла
+func (t *HistoryTab) onExport() {
+	entries := t.entries
+	window := t.app.MainWindow()
+
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if writer == nil {
+			return // Пользователь отменил
+		}
+		defer writer.Close()
+
+		write := history.WriteCSV
+		if strings.EqualFold(writer.URI().Extension(), ".json") {
+			write = history.WriteJSON
+		}
+		if err := write(writer, entries); err != nil {
+			logging.ErrorWithError("Failed to export history", err, "path", writer.URI().Path())
+			dialog.ShowError(err, window)
+			return
+		}
+		dialog.ShowInformation(localization.T("History exported"),
+			fmt.Sprintf(localization.TN("%d uploads saved to %s", len(entries)), len(entries), writer.URI().Name()), window)
+	}, window)
+
+	saveDialog.SetFileName("upload-history.csv")
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".json"}))
+	resizeDialog(saveDialog, fyne.NewSize(800, 600))
+	saveDialog.Show()
+}
+
+// showEntry показывает подробности записи: ссылки и результат проверки целостности
+func (t *HistoryTab) showEntry(entry history.Entry) {
+	window := t.app.MainWindow()
+
+	details := widget.NewLabel(fmt.Sprintf("%s · %s · %s",
+		entry.Provider,
+		localization.FormatDateTime(entry.UploadedAt),
+		localization.FormatSize(entry.Size),
+	))
+	content := container.NewVBox(details)
+	if entry.MIMEType != "" {
+		content.Add(widget.NewLabel(fmt.Sprintf(localization.T("Type: %s"), entry.MIMEType)))
+	}
+
+	if entry.Integrity != "" {
+		content.Add(newIntegrityLabel(entry.Integrity, entry.IntegrityDetail))
+	}
+	if entry.Link != "" {
+		link := widget.NewLabel(linkStatusText(entry))
+		if entry.Link == upload.LinkDead {
+			link.Importance = widget.DangerImportance
+		}
+		content.Add(link)
+	}
+
+	if len(entry.Parts) > 0 {
+		content.Add(newPartsSection(window, entry.FileName, entry.Parts))
+	} else if entry.URL != "" {
+		content.Add(newURLRow(window, localization.T("URL"), entry.URL))
+	}
+	if entry.DownloadURL != "" && len(entry.Parts) == 0 {
+		content.Add(newURLRow(window, localization.T("Download URL"), entry.DownloadURL))
+	}
+	if entry.DeleteURL != "" {
+		content.Add(newURLRow(window, localization.T("Delete URL"), entry.DeleteURL))
+	}
+
+	if entry.SourceURL != "" {
+		content.Add(newURLRow(window, localization.T("Source URL"), entry.SourceURL))
+	}
+	if entry.CollectionURL != "" {
+		content.Add(newURLRow(window, localization.T("Album URL"), entry.CollectionURL))
+	}
+
+	if entry.SHA256 != "" {
+		hash := widget.NewLabel("SHA-256: " + entry.SHA256)
+		hash.Selectable = true
+		hash.Wrapping = fyne.TextWrapBreak
+		content.Add(hash)
+	}
+
+	// Заметка и метки сохраняются при закрытии окна
+	noteEntry := widget.NewMultiLineEntry()
+	noteEntry.SetPlaceHolder(localization.T("Note, e.g. who the link was sent to"))
+	noteEntry.Wrapping = fyne.TextWrapWord
+	noteEntry.SetMinRowsVisible(2)
+	noteEntry.SetText(entry.Note)
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetPlaceHolder(localization.T("Tags, separated by commas"))
+	tagsEntry.SetText(strings.Join(entry.Tags, ", "))
+	content.Add(noteEntry)
+	content.Add(newRow(widget.NewLabel(localization.T("Tags:")), nil, tagsEntry))
+
+	d := dialog.NewCustom(entry.FileName, localization.T("Close"), container.NewVScroll(content), window)
+	d.SetOnClosed(func() {
+		t.saveNotes(entry, noteEntry.Text, history.ParseTags(tagsEntry.Text))
+	})
+	var buttons []fyne.CanvasObject
+
+	// Скачивание по прямой ссылке со сверкой хеша загруженного файла
+	// Части разрезанного файла хеш целого файла не сверить
+	if link := entry.DownloadURL; (link != "" || entry.URL != "") && len(entry.Parts) == 0 {
+		if link == "" {
+			link = entry.URL
+		}
+		buttons = append(buttons, widget.NewButtonWithIcon(localization.T("Download"), theme.DownloadIcon(), func() {
+			d.Hide()
+			t.app.showDownloadDialog(link, entry.SHA256)
+		}))
+	}
+
+	// Повторная загрузка, пока локальный файл на месте или известен источник
+	if fileExists(entry.FilePath) || entry.SourceURL != "" {
+		buttons = append(buttons, widget.NewButtonWithIcon(localization.T("Re-upload"), theme.UploadIcon(), func() {
+			d.Hide()
+			t.showReupload(entry)
+		}))
+	}
+
+	// Журнал передачи есть только у загрузок, сделанных после его появления
+	if len(entry.Log) > 0 {
+		buttons = append(buttons, widget.NewButtonWithIcon(localization.T("Transfer log"), theme.ListIcon(), func() {
+			showTransferLog(window, entry)
+		}))
+	}
+	if len(buttons) > 0 {
+		d.SetButtons(append(buttons, widget.NewButton(localization.T("Close"), d.Hide)))
+	}
+
+	resizeDialog(d, fyne.NewSize(600, 400))
+	d.Show()
+}
+
+// saveNotes сохраняет заметку и метки записи, если они изменились
+func (t *HistoryTab) saveNotes(entry history.Entry, note string, tags []string) {
+	note = strings.TrimSpace(note)
+	if note == entry.Note && slices.Equal(tags, entry.Tags) {
+		return
+	}
+	if err := t.app.History().Update(entry.ID, func(e *history.Entry) {
+		e.Note = note
+		e.Tags = tags
+	}); err != nil {
+		logging.ErrorWithError("Failed to save history note", err, "filename", entry.FileName)
+		t.app.showFriendlyError(err)
+		return
+	}
+	t.Refresh()
+}
+
+// showReupload предлагает повторить загрузку записи; выбраны провайдер и зеркала исходной загрузки
+func (t *HistoryTab) showReupload(entry history.Entry) {
+	var names []string
+	for _, p := range t.app.GetEnabledProviders() {
+		names = append(names, p.Name())
+	}
+	if len(names) == 0 {
+		t.app.showFriendlyError(errNoProvider)
+		return
+	}
+
+	providerSelect := widget.NewSelect(names, nil)
+	if slices.Contains(names, entry.Provider) {
+		providerSelect.SetSelected(entry.Provider)
+	} else {
+		providerSelect.SetSelected(names[0])
+	}
+
+	// Отключенные с тех пор провайдеры не предлагаются
+	var mirrors []string
+	for _, name := range entry.Mirrors {
+		if slices.Contains(names, name) {
+			mirrors = append(mirrors, name)
+		}
+	}
+	mirrorsCheck := widget.NewCheck(fmt.Sprintf(localization.T("Also to: %s"), strings.Join(mirrors, ", ")), nil)
+	mirrorsCheck.SetChecked(true)
+
+	items := []*widget.FormItem{widget.NewFormItem(localization.T("Provider"), providerSelect)}
+	if len(mirrors) > 0 {
+		items = append(items, widget.NewFormItem("", mirrorsCheck))
+	}
+
+	title := fmt.Sprintf(localization.T("Re-upload %s"), entry.FileName)
+	form := dialog.NewForm(title, localization.T("Upload"), localization.T("Cancel"), items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		var selected []string
+		if mirrorsCheck.Checked {
+			selected = slices.DeleteFunc(slices.Clone(mirrors), func(name string) bool { return name == providerSelect.Selected })
+		}
+		// Вкладка загрузки показывает подставленные параметры, даже если загрузка не прошла проверку
+		t.app.tabs.SelectIndex(0)
+		if err := t.app.uploadTab.Reupload(entry, providerSelect.Selected, selected); err != nil {
+			t.app.showFriendlyError(err)
+		}
+	}, t.app.MainWindow())
+	resizeDialog(form, fyne.NewSize(500, 200))
+	form.Show()
+}
+
+// integrityIcon иконка результата проверки целостности для списка истории
+func integrityIcon(status upload.VerifyStatus) fyne.Resource {
+	switch status {
+	case upload.VerifyOK:
+		return theme.NewSuccessThemedResource(theme.ConfirmIcon())
+	case upload.VerifyMismatch:
+		return theme.NewErrorThemedResource(theme.WarningIcon())
+	default:
+		return theme.FileIcon()
+	}
+}
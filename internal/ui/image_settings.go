@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/config"
+	"multiUploader/internal/localization"
+	"multiUploader/internal/preprocess"
+)
+
+// errImageSize неверный наибольший размер картинки
+var errImageSize = errors.New("maximum image size must be a whole number of pixels")
+
+// imageForm настройки уменьшения и пережатия картинок
+type imageForm struct {
+	enabledCheck  *widget.Check
+	widthEntry    *widget.Entry
+	heightEntry   *widget.Entry
+	formatSelect  *widget.Select
+	qualitySlider *widget.Slider
+	qualityLabel  *widget.Label
+}
+
+// imageFormats варианты формата результата
+var imageFormats = []preprocess.Format{preprocess.FormatKeep, preprocess.FormatJPEG, preprocess.FormatPNG}
+
+// imageFormatToText конвертирует формат картинки в UI текст
+func imageFormatToText(format preprocess.Format) string {
+	switch format {
+	case preprocess.FormatJPEG:
+		return "JPEG"
+	case preprocess.FormatPNG:
+		return "PNG"
+	}
+	return localization.T("Keep format")
+}
+
+// imageOptions конвертирует настройки из конфига в параметры обработки
+func imageOptions(cfg config.ImageConfig) preprocess.ImageOptions {
+	return preprocess.ImageOptions{
+		Enabled:   cfg.Enabled,
+		MaxWidth:  cfg.MaxWidth,
+		MaxHeight: cfg.MaxHeight,
+		Format:    preprocess.Format(cfg.Format),
+		Quality:   cfg.Quality,
+	}
+}
+
+// buildImageSettings создает секцию обработки картинок перед загрузкой
+func (t *SettingsTab) buildImageSettings() fyne.CanvasObject {
+	form := &imageForm{
+		enabledCheck: widget.NewCheck(localization.T("Shrink images before upload"), nil),
+		widthEntry:   widget.NewEntry(),
+		heightEntry:  widget.NewEntry(),
+		qualityLabel: widget.NewLabel(""),
+	}
+	form.widthEntry.SetPlaceHolder(localization.T("width"))
+	form.heightEntry.SetPlaceHolder(localization.T("height"))
+
+	formatOptions := make([]string, len(imageFormats))
+	for i, format := range imageFormats {
+		formatOptions[i] = imageFormatToText(format)
+	}
+	form.formatSelect = widget.NewSelect(formatOptions, nil)
+
+	form.qualitySlider = widget.NewSlider(30, 100)
+	form.qualitySlider.Step = 5
+	form.qualitySlider.OnChanged = func(value float64) {
+		form.qualityLabel.SetText(fmt.Sprintf(localization.T("JPEG quality: %d"), int(value)))
+	}
+	t.imageForm = form
+
+	sizeRow := newRow(widget.NewLabel(localization.T("Max size:")), widget.NewLabel(localization.T("px")),
+		container.NewGridWithColumns(2, form.widthEntry, form.heightEntry))
+	formatRow := newRow(widget.NewLabel(localization.T("Format:")), nil, form.formatSelect)
+	qualityRow := newRow(form.qualityLabel, nil, form.qualitySlider)
+
+	hint := widget.NewLabel(localization.T("Applies to JPEG, PNG, WebP and BMP files. Leave a size empty for no limit; images are never enlarged. Photos are rotated according to their EXIF data, which is removed. WebP is saved as JPEG. If the result is not smaller, the original is uploaded."))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	return container.NewVBox(
+		widget.NewLabelWithStyle(localization.T("Images"), readingAlign(), fyne.TextStyle{Bold: true}),
+		form.enabledCheck,
+		sizeRow,
+		formatRow,
+		qualityRow,
+		hint,
+	)
+}
+
+// load показывает настройки картинок
+func (f *imageForm) load(cfg config.ImageConfig) {
+	f.enabledCheck.SetChecked(cfg.Enabled)
+	f.widthEntry.SetText(pixelsToText(cfg.MaxWidth))
+	f.heightEntry.SetText(pixelsToText(cfg.MaxHeight))
+	f.formatSelect.SetSelected(imageFormatToText(preprocess.Format(cfg.Format)))
+	f.qualitySlider.SetValue(float64(cfg.Quality))
+}
+
+// config читает настройки картинок из формы
+func (f *imageForm) config() (config.ImageConfig, error) {
+	width, err := textToPixels(f.widthEntry.Text)
+	if err != nil {
+		return config.ImageConfig{}, err
+	}
+	height, err := textToPixels(f.heightEntry.Text)
+	if err != nil {
+		return config.ImageConfig{}, err
+	}
+
+	cfg := config.ImageConfig{
+		Enabled:   f.enabledCheck.Checked,
+		MaxWidth:  width,
+		MaxHeight: height,
+		Quality:   int(f.qualitySlider.Value),
+	}
+	for _, format := range imageFormats {
+		if imageFormatToText(format) == f.formatSelect.Selected {
+			cfg.Format = string(format)
+		}
+	}
+	return cfg, nil
+}
+
+// pixelsToText конвертирует размер в пикселях в текст поля (0 - пусто)
+func pixelsToText(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// textToPixels конвертирует текст поля в размер в пикселях (пусто - 0, без ограничения)
+func textToPixels(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %q", errImageSize, text)
+	}
+	return n, nil
+}
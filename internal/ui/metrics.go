@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"multiUploader/internal/logging"
+	"multiUploader/internal/metrics"
+)
+
+// errInvalidMetricsPort порт endpoint метрик вне диапазона 1-65535
+var errInvalidMetricsPort = errors.New("metrics port must be a number from 1 to 65535")
+
+// applyMetrics запускает, перезапускает или останавливает endpoint метрик по текущим настройкам
+func (a *App) applyMetrics() error {
+	cfg := a.config.GetGlobalConfig()
+
+	// Сервер уже слушает нужный порт - перезапуск не нужен
+	if cfg.MetricsEnabled && a.metricsServer != nil && a.metricsPort == cfg.MetricsPort {
+		return nil
+	}
+
+	if a.metricsServer != nil {
+		if err := a.metricsServer.Close(); err != nil {
+			logging.ErrorWithError("Failed to stop metrics endpoint", err)
+		}
+		a.metricsServer = nil
+	}
+	if !cfg.MetricsEnabled {
+		return nil
+	}
+
+	server, err := metrics.Listen(metrics.Default(), cfg.MetricsPort)
+	if err != nil {
+		return err
+	}
+	a.metricsServer = server
+	a.metricsPort = cfg.MetricsPort
+	return nil
+}
+
+// parseMetricsPort проверяет порт, введенный в настройках
+func parseMetricsPort(text string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || port < 1 || port > 65535 {
+		return 0, errInvalidMetricsPort
+	}
+	return port, nil
+}
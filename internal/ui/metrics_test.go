@@ -0,0 +1,26 @@
+package ui
+
+import "testing"
+
+// TestParseMetricsPort проверяет разбор порта endpoint метрик из настроек
+func TestParseMetricsPort(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    int
+		wantErr bool
+	}{
+		{"9464", 9464, false},
+		{" 9100 ", 9100, false},
+		{"65535", 65535, false},
+		{"0", 0, true},
+		{"65536", 0, true},
+		{"", 0, true},
+		{"port", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseMetricsPort(tt.text)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseMetricsPort(%q) = %d, %v, want %d, error %v", tt.text, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+	"multiUploader/internal/logging"
+	"multiUploader/internal/ontop"
+	"multiUploader/internal/viewmodel"
+)
+
+// errNoProvider файлы перетащены в мини-окно, а провайдер для загрузки не выбран
+var errNoProvider = errors.New("no provider selected")
+
+// miniWindow компактное окно поверх остальных: зона перетаскивания файлов и общий прогресс
+// Пока оно открыто, главное окно скрыто
+type miniWindow struct {
+	app    *App
+	window fyne.Window
+
+	providerLabel *widget.Label
+	progress      *widget.ProgressBar
+	statusLabel   *widget.Label
+}
+
+// setMiniMode переключает мини-окно и главное окно (вызывается из главного потока)
+func (a *App) setMiniMode(on bool) {
+	if on == (a.miniWindow != nil) {
+		return
+	}
+	if !on {
+		mini := a.miniWindow
+		a.miniWindow = nil
+		mini.window.Close()
+		a.mainWindow.SetMainMenu(a.buildMenu())
+		a.ShowWindow()
+		return
+	}
+
+	a.miniWindow = newMiniWindow(a)
+	a.mainWindow.SetMainMenu(a.buildMenu())
+	a.mainWindow.Hide()
+	a.miniWindow.window.Show()
+	if err := ontop.Set(a.miniWindow.window, true); err != nil {
+		logging.ErrorWithError("Failed to keep the mini window on top", err)
+	}
+	a.miniWindow.render(a.uploadTab.vm.State())
+}
+
+// newMiniWindow создает мини-окно
+func newMiniWindow(a *App) *miniWindow {
+	m := &miniWindow{
+		app:           a,
+		window:        a.fyneApp.NewWindow("multiUploader"),
+		providerLabel: widget.NewLabel(""),
+		progress:      widget.NewProgressBar(),
+		statusLabel:   widget.NewLabel(""),
+	}
+	m.providerLabel.Importance = widget.LowImportance
+	m.providerLabel.Truncation = fyne.TextTruncateEllipsis
+	m.statusLabel.Truncation = fyne.TextTruncateEllipsis
+
+	// Зона перетаскивания: файлы можно бросить в любое место окна
+	border := canvas.NewRectangle(color.Transparent)
+	border.StrokeColor = theme.Color(theme.ColorNamePrimary)
+	border.StrokeWidth = 2
+	border.CornerRadius = theme.InputRadiusSize()
+	hint := widget.NewLabelWithStyle(localization.T("Drop files here"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	dropZone := container.NewStack(border, container.NewCenter(container.NewVBox(widget.NewIcon(theme.UploadIcon()), hint)))
+
+	expandBtn := widget.NewButtonWithIcon("", theme.ViewFullScreenIcon(), func() { a.setMiniMode(false) })
+	footer := container.NewVBox(
+		m.progress,
+		newRow(nil, expandBtn, container.NewVBox(m.statusLabel, m.providerLabel)),
+	)
+
+	m.window.SetContent(container.NewPadded(container.NewBorder(nil, footer, nil, nil, dropZone)))
+	m.window.Resize(fyne.NewSize(320, 240))
+	m.window.SetFixedSize(true)
+	m.window.SetOnDropped(m.onDropped)
+	m.window.SetCloseIntercept(func() { a.setMiniMode(false) })
+	return m
+}
+
+// onDropped ставит перетащенные файлы в очередь выбранному в главном окне провайдеру
+func (m *miniWindow) onDropped(_ fyne.Position, uris []fyne.URI) {
+	// Файлы других хранилищ ставятся в очередь, только если среди перетащенных нет локальных
+	var paths, others []string
+	for _, uri := range uris {
+		if uri.Scheme() == "file" {
+			paths = append(paths, uri.Path())
+		} else {
+			others = append(others, uri.String())
+		}
+	}
+
+	var err error
+	switch {
+	case len(paths) > 0:
+		err = m.app.uploadTab.UploadFiles(paths)
+	case len(others) > 0:
+		err = m.app.uploadTab.UploadURIs(others)
+	default:
+		return
+	}
+	if err != nil {
+		text := localization.T("Select a provider in the main window first")
+		if !errors.Is(err, errNoProvider) {
+			text = MakeFriendly(err).Title
+		}
+		m.statusLabel.Importance = widget.DangerImportance
+		m.statusLabel.SetText(text)
+	}
+}
+
+// render показывает общий прогресс очереди (вызывается из главного потока)
+// Безопасно вызывать, когда мини-окно закрыто
+func (m *miniWindow) render(s viewmodel.State) {
+	if m == nil {
+		return
+	}
+	m.providerLabel.SetText(fmt.Sprintf(localization.T("Uploading to %s"), s.Provider))
+	m.providerLabel.Hidden = s.Provider == ""
+
+	p := s.Progress()
+	m.progress.SetValue(p.Fraction())
+	m.statusLabel.Importance = widget.MediumImportance
+	if p.Active == 0 {
+		m.statusLabel.SetText(localization.T("No uploads running"))
+		return
+	}
+	m.statusLabel.SetText(fmt.Sprintf(localization.T("%d uploading, %d queued · %s"),
+		p.Active-p.Queued, p.Queued, localization.FormatSpeed(p.Speed)))
+}
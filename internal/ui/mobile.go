@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+
+	"multiUploader/internal/logging"
+)
+
+// isMobile сообщает, что приложение запущено на телефоне или планшете
+func isMobile() bool {
+	return fyne.CurrentDevice().IsMobile()
+}
+
+// resizeDialog задает размер диалога на компьютере
+// На телефоне размер, рассчитанный на окно рабочего стола, шире экрана: диалог остается по размеру содержимого
+func resizeDialog(d interface{ Resize(fyne.Size) }, size fyne.Size) {
+	if !isMobile() {
+		d.Resize(size)
+	}
+}
+
+// watchLifecycle следит за уходом приложения в фон на телефоне
+// В фоне ОС может завершить приложение без предупреждения, поэтому очередь сохраняется заранее:
+// при следующем запуске загрузки предлагается продолжить с сохраненного места
+// При возвращении на экран выбираются файлы, которыми поделились с приложением
+func (a *App) watchLifecycle() {
+	if !isMobile() {
+		return
+	}
+	lifecycle := a.fyneApp.Lifecycle()
+	lifecycle.SetOnExitedForeground(a.uploadTab.vm.SaveQueue)
+	lifecycle.SetOnEnteredForeground(a.receiveShared)
+}
+
+// receiveShared выбирает файлы, отправленные в приложение через "Поделиться" (Android)
+func (a *App) receiveShared() {
+	uris, err := sharedURIs()
+	if err != nil {
+		logging.ErrorWithError("Failed to read shared files", err)
+		return
+	}
+	if len(uris) > 0 {
+		a.uploadTab.setSourceURIs(uris)
+	}
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"net/http"
+	"net/url"
+
+	"multiUploader/internal/config"
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/logging"
+	"multiUploader/internal/providers"
+)
+
+// applyHeaders применяет User-Agent, дополнительные заголовки и подпись запросов провайдеров из настроек
+// Заголовки и подпись проверяются при сохранении; неверные (например, из старого конфига) пропускаются
+func (a *App) applyHeaders() {
+	httpclient.SetUserAgent(a.config.GetGlobalConfig().UserAgent)
+
+	headers := make(map[string]http.Header)
+	for _, name := range a.providerOrder {
+		text := a.config.GetProviderConfig(name).Headers
+		if text == "" {
+			continue
+		}
+		parsed, err := httpclient.ParseHeaders(text)
+		if err != nil {
+			logging.ErrorWithError("Invalid provider headers", err, "provider", name)
+			continue
+		}
+		headers[name] = parsed
+	}
+	httpclient.SetProviderHeaders(headers)
+
+	signers := make(map[string]*httpclient.Signer)
+	for _, name := range a.providerOrder {
+		if signer := providerSigner(a.config.GetProviderConfig(name)); signer != nil {
+			if err := signer.Validate(); err != nil {
+				logging.ErrorWithError("Invalid provider request signing", err, "provider", name)
+				continue
+			}
+			signers[name] = signer
+		}
+	}
+	httpclient.SetProviderSigners(signers)
+}
+
+// providerSigner подпись запросов провайдера из настроек (nil, если секрет не задан)
+func providerSigner(cfg config.ProviderConfig) *httpclient.Signer {
+	if cfg.SigningSecret == "" {
+		return nil
+	}
+	return &httpclient.Signer{KeyID: cfg.SigningKeyID, Secret: cfg.SigningSecret, Algorithm: cfg.SigningAlgorithm}
+}
+
+// applyTLS применяет дополнительные доверенные CA и серверы без проверки сертификата из настроек
+func (a *App) applyTLS() error {
+	var hosts []string
+	for _, name := range a.providerOrder {
+		if !a.config.GetProviderConfig(name).InsecureTLS {
+			continue
+		}
+		if host := serverHost(a.newProvider(name, a.providerFactories[name])); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	httpclient.SetInsecureHosts(hosts)
+
+	return httpclient.SetTrustedCAFile(a.config.GetGlobalConfig().CACertFile)
+}
+
+// serverHost имя хоста своего сервера провайдера ("" - у провайдера нет своего сервера)
+func serverHost(provider providers.Provider) string {
+	selfHosted, ok := provider.(providers.SelfHosted)
+	if !ok {
+		return ""
+	}
+	u, err := url.Parse(selfHosted.ServerURL())
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"testing"
+
+	"multiUploader/internal/providers"
+)
+
+func TestServerHost(t *testing.T) {
+	tus := providers.NewTusProvider()
+	tus.SetSettings(map[string]string{providers.TusEndpoint: "https://Files.Home.Lan:8443/files/"})
+	if got := serverHost(tus); got != "Files.Home.Lan" {
+		t.Errorf("serverHost(tus) = %q, want Files.Home.Lan", got)
+	}
+
+	ipfs := providers.NewIPFSProvider()
+	ipfs.SetSettings(map[string]string{providers.IPFSNode: "https://192.168.1.10:5001"})
+	if got := serverHost(ipfs); got != "192.168.1.10" {
+		t.Errorf("serverHost(ipfs) = %q, want 192.168.1.10", got)
+	}
+
+	// Через Pinata загрузка идет не на свой сервер
+	ipfs.SetSettings(map[string]string{providers.IPFSPinataJWT: "jwt"})
+	if got := serverHost(ipfs); got != "" {
+		t.Errorf("serverHost(ipfs with Pinata) = %q, want empty", got)
+	}
+
+	if got := serverHost(providers.NewRootzProvider("")); got != "" {
+		t.Errorf("serverHost(Rootz) = %q, want empty", got)
+	}
+}
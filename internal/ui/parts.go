@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+	"multiUploader/internal/upload"
+)
+
+// newPartsSection показывает ссылки на части разрезанного файла и команды, которыми их склеить
+func newPartsSection(window fyne.Window, fileName string, parts []upload.PartLink) fyne.CanvasObject {
+	title := widget.NewLabelWithStyle(localization.T("The file was uploaded in parts. Download all of them and join them:"),
+		readingAlign(), fyne.TextStyle{Bold: true})
+	title.Wrapping = fyne.TextWrapWord
+	content := container.NewVBox(title)
+
+	for _, part := range parts {
+		content.Add(newURLRow(window, part.Name, part.Link()))
+	}
+
+	commands := upload.RejoinCommands(fileName, parts)
+	snippet := widget.NewLabelWithStyle(commands, readingAlign(), fyne.TextStyle{Monospace: true})
+	snippet.Selectable = true
+	copyBtn := widget.NewButtonWithIcon(localization.T("Copy"), theme.ContentCopyIcon(), func() {
+		window.Clipboard().SetContent(commands)
+	})
+	content.Add(newRow(nil, container.NewVBox(copyBtn), snippet))
+	return content
+}
+
+// newUploadedPartsButton кнопка со ссылками на части, загруженные до ошибки
+func newUploadedPartsButton(window fyne.Window, parts []upload.PartLink) *widget.Button {
+	return widget.NewButtonWithIcon(localization.T("Uploaded parts"), theme.ListIcon(), func() {
+		content := container.NewVBox()
+		for _, part := range parts {
+			content.Add(newURLRow(window, part.Name, part.Link()))
+		}
+		title := fmt.Sprintf(localization.TN("%d parts were uploaded before the error", len(parts)), len(parts))
+		d := dialog.NewCustom(title, localization.T("Close"), container.NewVScroll(content), window)
+		d.Resize(fyne.NewSize(600, 300))
+		d.Show()
+	})
+}
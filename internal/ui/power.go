@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+	"multiUploader/internal/logging"
+	"multiUploader/internal/power"
+	"multiUploader/internal/viewmodel"
+)
+
+// shutdownDelay сколько ждем перед выключением, чтобы пользователь успел отменить его
+const shutdownDelay = 60 * time.Second
+
+// updatePower держит запрет сна, пока идут загрузки, и выключает компьютер по завершении очереди
+// Вызывается из главного потока при каждом изменении состояния
+func (t *UploadTab) updatePower(s viewmodel.State) {
+	active := s.Active() > 0
+
+	switch {
+	case !active:
+		t.inhibitor.Release()
+		t.inhibitFailed = false
+	case !t.inhibitor.Held() && !t.inhibitFailed && t.app.Config().GetGlobalConfig().PreventSleep:
+		if err := t.inhibitor.Acquire(); err != nil {
+			// Не повторяем попытку до конца очереди, чтобы не засорять лог
+			t.inhibitFailed = true
+			logging.ErrorWithError("Failed to prevent system sleep", err)
+		}
+	}
+
+	if t.wasActive && !active && t.shutdownWhenDone {
+		t.shutdownWhenDone = false
+		if t.shutdownCheck != nil {
+			t.shutdownCheck.SetChecked(false)
+		}
+		t.confirmShutdown()
+	}
+	t.wasActive = active
+}
+
+// confirmShutdown показывает обратный отсчет до выключения с возможностью отмены
+func (t *UploadTab) confirmShutdown() {
+	deadline := time.Now().Add(shutdownDelay)
+	label := widget.NewLabel("")
+	updateLabel := func() {
+		label.SetText(fmt.Sprintf(localization.T("All uploads have finished. The computer will shut down in %s."),
+			localization.FormatDuration(time.Until(deadline).Round(time.Second))))
+	}
+	updateLabel()
+
+	stop := make(chan struct{})
+	d := dialog.NewCustom(localization.T("Shut down"), localization.T("Cancel"), label, t.app.MainWindow())
+	d.SetOnClosed(func() { close(stop) })
+	d.Show()
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if time.Now().Before(deadline) {
+					fyne.Do(updateLabel)
+					continue
+				}
+				fyne.Do(d.Hide)
+				if err := power.Shutdown(); err != nil {
+					logging.ErrorWithError("Failed to shut down", err)
+					fyne.Do(func() { t.showFriendlyError(err) })
+				}
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"embed"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+)
+
+// providerIconsFS значки встроенных провайдеров: icons/<название в нижнем регистре>.svg
+//
+//go:embed icons/*.svg
+var providerIconsFS embed.FS
+
+// providerIcon возвращает значок провайдера по названию
+// У провайдеров без своего значка (добавленные хостинги XFileSharing, mock-провайдеры)
+// значок - круг цвета, который вычисляется из названия: у каждого провайдера он всегда один и тот же
+func providerIcon(name string) fyne.Resource {
+	file := strings.ToLower(name) + ".svg"
+	if content, err := providerIconsFS.ReadFile("icons/" + file); err == nil {
+		return fyne.NewStaticResource("provider-"+file, content)
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	// Без стандартного цвета темы: первый пресет пустой
+	colors := accentPresets[1:]
+	hex := colors[h.Sum32()%uint32(len(colors))].Hex
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><circle cx="12" cy="12" r="10" fill="%s"/><circle cx="12" cy="12" r="4" fill="#fff"/></svg>`, hex)
+	return fyne.NewStaticResource(fmt.Sprintf("provider-%x.svg", h.Sum32()), []byte(svg))
+}
+
+// providerPicker выбор провайдера: кнопка со значком и названием выбранного провайдера,
+// по нажатию - меню провайдеров со значками (widget.Select показывает только текст)
+type providerPicker struct {
+	button    *widget.Button
+	options   []string
+	selected  string
+	onChanged func(string)
+}
+
+// newProviderPicker создает выбор провайдера; onChanged вызывается при смене провайдера
+func newProviderPicker(onChanged func(string)) *providerPicker {
+	p := &providerPicker{onChanged: onChanged}
+	p.button = widget.NewButton("", p.showMenu)
+	p.button.Alignment = widget.ButtonAlignLeading
+	if localization.IsRTL() {
+		p.button.Alignment = widget.ButtonAlignTrailing
+	}
+	p.render()
+	return p
+}
+
+// SetOptions задает список провайдеров
+func (p *providerPicker) SetOptions(options []string) {
+	p.options = options
+	p.render()
+}
+
+// SetSelected выбирает провайдера; как и widget.Select, вызывает onChanged, только если выбор изменился
+func (p *providerPicker) SetSelected(name string) {
+	if name == p.selected {
+		return
+	}
+	p.selected = name
+	p.render()
+	if p.onChanged != nil {
+		p.onChanged(name)
+	}
+}
+
+// render показывает выбранного провайдера на кнопке
+func (p *providerPicker) render() {
+	switch {
+	case p.selected != "":
+		p.button.SetText(p.selected)
+		p.button.SetIcon(providerIcon(p.selected))
+	case len(p.options) == 0:
+		p.button.SetText(localization.T("No providers enabled"))
+		p.button.SetIcon(nil)
+	default:
+		p.button.SetText(localization.T("Select Providers"))
+		p.button.SetIcon(nil)
+	}
+	if len(p.options) == 0 {
+		p.button.Disable()
+	} else {
+		p.button.Enable()
+	}
+}
+
+// showMenu открывает меню провайдеров под кнопкой
+func (p *providerPicker) showMenu() {
+	items := make([]*fyne.MenuItem, 0, len(p.options))
+	for _, name := range p.options {
+		item := fyne.NewMenuItemWithIcon(name, providerIcon(name), func() { p.SetSelected(name) })
+		item.Checked = name == p.selected
+		items = append(items, item)
+	}
+
+	driver := fyne.CurrentApp().Driver()
+	canvas := driver.CanvasForObject(p.button)
+	if canvas == nil {
+		return
+	}
+	pos := driver.AbsolutePositionForObject(p.button).AddXY(0, p.button.Size().Height)
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), canvas, pos)
+}
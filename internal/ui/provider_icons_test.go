@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+// TestProviderIcon проверяет значки встроенных провайдеров и значки, вычисленные из названия
+func TestProviderIcon(t *testing.T) {
+	for _, name := range []string{"DataVaults", "Rootz", "AkiraBox", "FileKeeper", "Telegram", "IPFS", "tus"} {
+		icon := providerIcon(name)
+		if icon.Name() != "provider-"+strings.ToLower(name)+".svg" {
+			t.Errorf("%s: icon %q is not embedded", name, icon.Name())
+		}
+		// SVG с ошибкой разметки Fyne показывает пустым местом
+		decoder := xml.NewDecoder(bytes.NewReader(icon.Content()))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Errorf("%s: invalid SVG: %v", name, err)
+				break
+			}
+		}
+	}
+
+	custom := providerIcon("MyHost")
+	if !bytes.Equal(custom.Content(), providerIcon("MyHost").Content()) || custom.Name() != providerIcon("MyHost").Name() {
+		t.Error("generated icon changes between calls")
+	}
+	if custom.Name() == providerIcon("OtherHost").Name() {
+		t.Error("different providers share a generated icon name")
+	}
+}
+
+// TestProviderPicker проверяет, что выбор провайдера сообщает только об изменениях
+func TestProviderPicker(t *testing.T) {
+	test.NewTempApp(t)
+
+	var changes []string
+	picker := newProviderPicker(func(name string) { changes = append(changes, name) })
+	if !picker.button.Disabled() {
+		t.Error("picker without providers is enabled")
+	}
+
+	picker.SetOptions([]string{"Rootz", "tus"})
+	picker.SetSelected("tus")
+	picker.SetSelected("tus")
+	if len(changes) != 1 || changes[0] != "tus" {
+		t.Errorf("changes = %v, want [tus]", changes)
+	}
+	if picker.button.Text != "tus" || picker.button.Icon == nil || picker.button.Disabled() {
+		t.Errorf("button shows %q, icon %v, disabled %v", picker.button.Text, picker.button.Icon, picker.button.Disabled())
+	}
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+	"multiUploader/internal/report"
+)
+
+// onShowResults собирает итог завершенных загрузок по шаблону и показывает его для копирования
+func (t *UploadTab) onShowResults() {
+	done := t.vm.State().Succeeded()
+	items := make([]report.Item, 0, len(done))
+	for _, c := range done {
+		items = append(items, report.Item{
+			FileName:    c.FileName,
+			Size:        c.Size,
+			Provider:    c.Provider,
+			MIMEType:    c.MIMEType,
+			URL:         c.Result.URL,
+			DownloadURL: c.Result.DownloadURL,
+			DeleteURL:   c.Result.DeleteURL,
+		})
+	}
+	text := report.Render(resultTemplate(t.app.Config().GetResultConfig()), items, localization.FormatSize)
+
+	window := t.app.MainWindow()
+	output := widget.NewMultiLineEntry()
+	output.TextStyle = fyne.TextStyle{Monospace: true}
+	output.Wrapping = fyne.TextWrapOff
+	output.SetText(text)
+	output.SetMinRowsVisible(12)
+
+	d := dialog.NewCustom(localization.T("Upload Results"), localization.T("Close"), output, window)
+	copyBtn := widget.NewButtonWithIcon(localization.T("Copy"), theme.ContentCopyIcon(), func() {
+		// Текст можно поправить перед копированием
+		window.Clipboard().SetContent(output.Text)
+		d.Hide()
+	})
+	copyBtn.Importance = widget.HighImportance
+	d.SetButtons([]fyne.CanvasObject{widget.NewButton(localization.T("Close"), d.Hide), copyBtn})
+	resizeDialog(d, fyne.NewSize(700, 400))
+	d.Show()
+}
@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/config"
+	"multiUploader/internal/localization"
+	"multiUploader/internal/report"
+)
+
+// resultForm настройки шаблона итога нескольких загрузок
+type resultForm struct {
+	presetSelect *widget.Select
+	headerEntry  *widget.Entry
+	rowEntry     *widget.Entry
+	footerEntry  *widget.Entry
+	htmlCheck    *widget.Check
+	preview      *widget.Label
+
+	// current показанный шаблон, custom - собственный шаблон, который помнится при переключении на готовые
+	current string
+	custom  config.ResultConfig
+}
+
+// resultTemplate возвращает шаблон итога из настроек (неизвестный ключ - шаблон по умолчанию)
+func resultTemplate(cfg config.ResultConfig) report.Template {
+	if cfg.Preset == report.Custom {
+		return report.Template{Header: cfg.Header, Row: cfg.Row, Footer: cfg.Footer, HTML: cfg.HTML}
+	}
+	if preset, ok := report.PresetByID(cfg.Preset); ok {
+		return preset.Template
+	}
+	return report.Presets[0].Template
+}
+
+// presetToText конвертирует ключ шаблона итога в UI текст
+func presetToText(id string) string {
+	if id == report.Custom {
+		return localization.T("Custom")
+	}
+	preset, ok := report.PresetByID(id)
+	if !ok {
+		preset = report.Presets[0]
+	}
+	return localization.T(preset.Name)
+}
+
+// textToPreset конвертирует UI текст в ключ шаблона итога
+func textToPreset(text string) string {
+	for _, preset := range report.Presets {
+		if localization.T(preset.Name) == text {
+			return preset.ID
+		}
+	}
+	return report.Custom
+}
+
+// buildResultSettings создает секцию шаблона итога с предпросмотром
+func (t *SettingsTab) buildResultSettings() fyne.CanvasObject {
+	form := &resultForm{
+		headerEntry: widget.NewMultiLineEntry(),
+		rowEntry:    widget.NewMultiLineEntry(),
+		footerEntry: widget.NewMultiLineEntry(),
+		preview:     widget.NewLabel(""),
+	}
+	form.htmlCheck = widget.NewCheck(localization.T("Escape values for HTML"), func(bool) { form.updatePreview() })
+	for _, entry := range []*widget.Entry{form.headerEntry, form.rowEntry, form.footerEntry} {
+		entry.SetMinRowsVisible(2)
+		entry.OnChanged = func(string) { form.updatePreview() }
+	}
+	form.preview.TextStyle = fyne.TextStyle{Monospace: true}
+	form.preview.Wrapping = fyne.TextWrapBreak
+
+	options := make([]string, 0, len(report.Presets)+1)
+	for _, preset := range report.Presets {
+		options = append(options, localization.T(preset.Name))
+	}
+	options = append(options, localization.T("Custom"))
+	form.presetSelect = widget.NewSelect(options, form.onPresetSelected)
+	t.resultForm = form
+
+	hint := widget.NewLabel(localization.T("Used by the Results button on the Upload tab. Row placeholders: {n}, {file}, {size}, {provider}, {type}, {url}, {download_url}, {delete_url}. Header and footer: {count}, {total_size}."))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	return container.NewVBox(
+		widget.NewLabelWithStyle(localization.T("Result template"), readingAlign(), fyne.TextStyle{Bold: true}),
+		newRow(widget.NewLabel(localization.T("Template:")), nil, form.presetSelect),
+		widget.NewForm(
+			widget.NewFormItem(localization.T("Header"), form.headerEntry),
+			widget.NewFormItem(localization.T("Row"), form.rowEntry),
+			widget.NewFormItem(localization.T("Footer"), form.footerEntry),
+		),
+		form.htmlCheck,
+		hint,
+		widget.NewLabel(localization.T("Preview:")),
+		form.preview,
+	)
+}
+
+// onPresetSelected обработчик выбора шаблона
+func (f *resultForm) onPresetSelected(selected string) {
+	f.selectPreset(textToPreset(selected))
+}
+
+// selectPreset показывает выбранный шаблон; править можно только собственный
+func (f *resultForm) selectPreset(id string) {
+	if f.current == report.Custom {
+		f.custom = f.config()
+	}
+	tmpl := resultTemplate(config.ResultConfig{Preset: id})
+	if id == report.Custom {
+		// Собственный шаблон начинается с показанного готового, если еще не задан
+		tmpl = f.template()
+		if f.custom.Row != "" {
+			tmpl = resultTemplate(f.custom)
+		}
+	}
+	f.current = id
+
+	f.headerEntry.SetText(tmpl.Header)
+	f.rowEntry.SetText(tmpl.Row)
+	f.footerEntry.SetText(tmpl.Footer)
+	f.htmlCheck.SetChecked(tmpl.HTML)
+	for _, w := range []fyne.Disableable{f.headerEntry, f.rowEntry, f.footerEntry, f.htmlCheck} {
+		if id == report.Custom {
+			w.Enable()
+		} else {
+			w.Disable()
+		}
+	}
+	f.updatePreview()
+}
+
+// template шаблон из полей формы
+func (f *resultForm) template() report.Template {
+	return report.Template{Header: f.headerEntry.Text, Row: f.rowEntry.Text, Footer: f.footerEntry.Text, HTML: f.htmlCheck.Checked}
+}
+
+// updatePreview показывает итог шаблона для примерных файлов
+func (f *resultForm) updatePreview() {
+	if f.preview == nil || f.htmlCheck == nil {
+		return
+	}
+	f.preview.SetText(report.Render(f.template(), report.Sample, localization.FormatSize))
+}
+
+// load показывает шаблон итога
+func (f *resultForm) load(cfg config.ResultConfig) {
+	f.current = ""
+	f.custom = config.ResultConfig{Preset: report.Custom, Header: cfg.Header, Row: cfg.Row, Footer: cfg.Footer, HTML: cfg.HTML}
+	// SetSelected не вызывает обработчик, если шаблон не изменился, поэтому показываем его сами
+	onChanged := f.presetSelect.OnChanged
+	f.presetSelect.OnChanged = nil
+	f.presetSelect.SetSelected(presetToText(cfg.Preset))
+	f.presetSelect.OnChanged = onChanged
+	f.selectPreset(textToPreset(f.presetSelect.Selected))
+}
+
+// config читает шаблон итога из формы
+func (f *resultForm) config() config.ResultConfig {
+	id := f.current
+	if id != report.Custom {
+		// Собственный шаблон сохраняется, чтобы к нему можно было вернуться
+		cfg := f.custom
+		cfg.Preset = id
+		return cfg
+	}
+	tmpl := f.template()
+	return config.ResultConfig{Preset: id, Header: tmpl.Header, Row: tmpl.Row, Footer: tmpl.Footer, HTML: tmpl.HTML}
+}
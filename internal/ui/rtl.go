@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+)
+
+// Fyne не зеркалит раскладку для языков с письмом справа налево, поэтому строки интерфейса
+// собираются через эти функции: с RTL переводом начало строки справа
+// Раскладка выбирается при построении, а смена языка пересоздает интерфейс (App.Rebuild)
+
+// readingAlign выравнивание текста по началу строки: слева, для RTL языков справа
+func readingAlign() fyne.TextAlign {
+	if localization.IsRTL() {
+		return fyne.TextAlignTrailing
+	}
+	return fyne.TextAlignLeading
+}
+
+// alignLabels выравнивает подписи по началу строки
+func alignLabels(labels ...*widget.Label) {
+	for _, label := range labels {
+		label.Alignment = readingAlign()
+	}
+}
+
+// newRow строка с подписью в начале, полем посередине и кнопками в конце (как container.NewBorder
+// без верха и низа); для RTL языков края меняются местами
+func newRow(leading, trailing, center fyne.CanvasObject) *fyne.Container {
+	if localization.IsRTL() {
+		leading, trailing = trailing, leading
+	}
+	return container.NewBorder(nil, nil, leading, trailing, center)
+}
+
+// newHRow ряд элементов в порядке чтения; для RTL языков справа налево и прижат к правому краю
+// (если в ряду нет своего разделителя)
+func newHRow(objects ...fyne.CanvasObject) *fyne.Container {
+	if !localization.IsRTL() {
+		return container.NewHBox(objects...)
+	}
+	mirrored := slices.Clone(objects)
+	slices.Reverse(mirrored)
+	if !slices.ContainsFunc(objects, isSpacer) {
+		mirrored = append([]fyne.CanvasObject{layout.NewSpacer()}, mirrored...)
+	}
+	return container.NewHBox(mirrored...)
+}
+
+// isSpacer сообщает, что объект - растягивающийся разделитель layout.NewSpacer
+func isSpacer(object fyne.CanvasObject) bool {
+	_, ok := object.(layout.SpacerObject)
+	return ok
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+)
+
+// TestRTLRows проверяет зеркальную раскладку строк для языка с письмом справа налево
+func TestRTLRows(t *testing.T) {
+	test.NewTempApp(t)
+	if err := localization.Init("he"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { localization.Init("en") })
+
+	label, field, button := widget.NewLabel("label"), widget.NewEntry(), widget.NewButton("ok", nil)
+	row := newRow(label, button, field)
+	row.Resize(fyne.NewSize(400, 40))
+	if label.Position().X <= button.Position().X {
+		t.Errorf("label at x=%v, button at x=%v: label should be on the right", label.Position().X, button.Position().X)
+	}
+
+	first, second := widget.NewButton("first", nil), widget.NewButton("second", nil)
+	buttons := newHRow(first, second)
+	buttons.Resize(fyne.NewSize(400, 40))
+	if first.Position().X <= second.Position().X {
+		t.Errorf("first at x=%v, second at x=%v: first should be on the right", first.Position().X, second.Position().X)
+	}
+	if right := first.Position().X + first.Size().Width; right < 399 {
+		t.Errorf("row ends at x=%v, want it pressed to the right edge", right)
+	}
+	if readingAlign() != fyne.TextAlignTrailing {
+		t.Error("text is not aligned to the right")
+	}
+
+	localization.Init("en")
+	row = newRow(label, button, field)
+	row.Resize(fyne.NewSize(400, 40))
+	if label.Position().X >= button.Position().X {
+		t.Errorf("left-to-right row: label at x=%v, button at x=%v", label.Position().X, button.Position().X)
+	}
+}
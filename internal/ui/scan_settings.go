@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/config"
+	"multiUploader/internal/localization"
+	"multiUploader/internal/scan"
+)
+
+// Ошибки настроек проверки на вирусы
+var (
+	errClamdAddress  = errors.New("enter the clamd socket path or host:port")
+	errVirusTotalKey = errors.New("enter the VirusTotal API key")
+)
+
+// scanForm настройки проверки файлов на вирусы перед загрузкой
+type scanForm struct {
+	modeSelect *widget.Select
+	clamdEntry *widget.Entry
+	vtKeyEntry *widget.Entry
+}
+
+// scanModes варианты проверки на вирусы
+var scanModes = []string{config.ScanOff, config.ScanClamAV, config.ScanVirusTotal}
+
+// scanModeToText конвертирует режим проверки в UI текст
+func scanModeToText(mode string) string {
+	switch mode {
+	case config.ScanClamAV:
+		return localization.T("ClamAV (local clamd)")
+	case config.ScanVirusTotal:
+		return localization.T("VirusTotal (hash lookup)")
+	}
+	return localization.T("Off")
+}
+
+// newScanner создает антивирус по настройкам (nil - проверка выключена)
+func newScanner(cfg config.ScanConfig) scan.Scanner {
+	switch cfg.Mode {
+	case config.ScanClamAV:
+		return scan.ClamAV{Address: cfg.ClamdAddress}
+	case config.ScanVirusTotal:
+		return scan.VirusTotal{APIKey: cfg.VirusTotalKey}
+	}
+	return nil
+}
+
+// buildScanSettings создает секцию проверки на вирусы
+func (t *SettingsTab) buildScanSettings() fyne.CanvasObject {
+	form := &scanForm{
+		clamdEntry: widget.NewEntry(),
+		vtKeyEntry: widget.NewPasswordEntry(),
+	}
+	form.clamdEntry.SetPlaceHolder("/var/run/clamav/clamd.ctl")
+
+	modeOptions := make([]string, len(scanModes))
+	for i, mode := range scanModes {
+		modeOptions[i] = scanModeToText(mode)
+	}
+	form.modeSelect = widget.NewSelect(modeOptions, nil)
+	t.scanForm = form
+
+	hint := widget.NewLabel(localization.T("Files are checked before upload. ClamAV receives the file through the clamd socket (a path or host:port, e.g. 127.0.0.1:3310). VirusTotal only receives the SHA-256 of the file, so files it has never seen are not checked. If a threat is found or the check fails, the upload stops and its card offers to upload anyway."))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	return container.NewVBox(
+		widget.NewLabelWithStyle(localization.T("Virus scan"), readingAlign(), fyne.TextStyle{Bold: true}),
+		newRow(widget.NewLabel(localization.T("Scan with:")), nil, form.modeSelect),
+		newRow(widget.NewLabel(localization.T("clamd socket:")), nil, form.clamdEntry),
+		newRow(widget.NewLabel(localization.T("VirusTotal API key:")), nil, t.secretField(form.vtKeyEntry)),
+		hint,
+	)
+}
+
+// load показывает настройки проверки на вирусы
+func (f *scanForm) load(cfg config.ScanConfig) {
+	f.modeSelect.SetSelected(scanModeToText(cfg.Mode))
+	f.clamdEntry.SetText(cfg.ClamdAddress)
+	f.vtKeyEntry.SetText(cfg.VirusTotalKey)
+}
+
+// config читает настройки проверки из формы
+// Выбранной проверке нужен адрес clamd или ключ VirusTotal
+func (f *scanForm) config() (config.ScanConfig, error) {
+	cfg := config.ScanConfig{
+		ClamdAddress:  strings.TrimSpace(f.clamdEntry.Text),
+		VirusTotalKey: strings.TrimSpace(f.vtKeyEntry.Text),
+	}
+	for _, mode := range scanModes {
+		if scanModeToText(mode) == f.modeSelect.Selected {
+			cfg.Mode = mode
+		}
+	}
+
+	switch {
+	case cfg.Mode == config.ScanClamAV && cfg.ClamdAddress == "":
+		return config.ScanConfig{}, errClamdAddress
+	case cfg.Mode == config.ScanVirusTotal && cfg.VirusTotalKey == "":
+		return config.ScanConfig{}, errVirusTotalKey
+	}
+	return cfg, nil
+}
@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+)
+
+// trackChanges находит поля ввода в content и пересчитывает несохраненные изменения при их правке
+// Существующие обработчики OnChanged сохраняются
+func (t *SettingsTab) trackChanges(content fyne.CanvasObject) {
+	t.tracked = nil
+	t.walkInputs(content, func(obj fyne.CanvasObject) {
+		// Строка поиска провайдеров - не настройка
+		if obj == t.providerSearch {
+			return
+		}
+		t.tracked = append(t.tracked, obj)
+
+		switch w := obj.(type) {
+		case *widget.Entry:
+			w.OnChanged = chain(w.OnChanged, t.updateDirty)
+		case *widget.Check:
+			w.OnChanged = chain(w.OnChanged, t.updateDirty)
+		case *widget.Select:
+			w.OnChanged = chain(w.OnChanged, t.updateDirty)
+		case *widget.RadioGroup:
+			w.OnChanged = chain(w.OnChanged, t.updateDirty)
+		case *widget.Slider:
+			w.OnChanged = chain(w.OnChanged, t.updateDirty)
+		}
+	})
+}
+
+// walkInputs обходит дерево виджетов и вызывает visit для каждого поля ввода
+func (t *SettingsTab) walkInputs(obj fyne.CanvasObject, visit func(fyne.CanvasObject)) {
+	switch o := obj.(type) {
+	case *fyne.Container:
+		for _, child := range o.Objects {
+			t.walkInputs(child, visit)
+		}
+	case *container.Scroll:
+		t.walkInputs(o.Content, visit)
+	case *widget.Card:
+		t.walkInputs(o.Content, visit)
+	case *widget.Form:
+		for _, item := range o.Items {
+			t.walkInputs(item.Widget, visit)
+		}
+	case *widget.Accordion:
+		for _, item := range o.Items {
+			t.walkInputs(item.Detail, visit)
+		}
+	case *widget.Entry, *widget.Check, *widget.Select, *widget.RadioGroup, *widget.Slider:
+		visit(obj)
+	}
+}
+
+// chain дополняет обработчик OnChanged вызовом after
+func chain[T any](handler func(T), after func()) func(T) {
+	return func(value T) {
+		if handler != nil {
+			handler(value)
+		}
+		after()
+	}
+}
+
+// formValues текущие значения всех отслеживаемых полей
+func (t *SettingsTab) formValues() []string {
+	values := make([]string, 0, len(t.tracked)+1)
+	for _, obj := range t.tracked {
+		switch w := obj.(type) {
+		case *widget.Entry:
+			values = append(values, w.Text)
+		case *widget.Check:
+			values = append(values, fmt.Sprint(w.Checked))
+		case *widget.Select:
+			values = append(values, w.Selected)
+		case *widget.RadioGroup:
+			values = append(values, w.Selected)
+		case *widget.Slider:
+			values = append(values, fmt.Sprint(w.Value))
+		}
+	}
+	// Свой цвет меняется без смены пункта "Custom..."
+	return append(values, t.customAccent)
+}
+
+// markClean запоминает значения полей как сохраненные
+func (t *SettingsTab) markClean() {
+	t.savedValues = t.formValues()
+	t.updateDirty()
+}
+
+// setSavedText показывает в поле значение, сохраненное вне вкладки (ключ из карточки загрузки,
+// срок из сведений об аккаунте): поле не считается несохраненной правкой, остальные правки не меняются
+func (t *SettingsTab) setSavedText(entry *widget.Entry, text string) {
+	if i := slices.Index(t.tracked, fyne.CanvasObject(entry)); i >= 0 && i < len(t.savedValues) {
+		t.savedValues[i] = text
+	}
+	entry.SetText(text)
+	t.updateDirty()
+}
+
+// isDirty сообщает, есть ли несохраненные изменения
+func (t *SettingsTab) isDirty() bool {
+	return t.savedValues != nil && !slices.Equal(t.savedValues, t.formValues())
+}
+
+// updateDirty включает кнопки сохранения и отмены только при несохраненных изменениях
+func (t *SettingsTab) updateDirty() {
+	if t.saveBtn == nil {
+		return
+	}
+	dirty := t.isDirty()
+	setEnabled(t.saveBtn, dirty)
+	setEnabled(t.cancelBtn, dirty)
+	setVisible(t.dirtyLabel, dirty)
+}
+
+// confirmUnsaved вызывает next сразу, если изменений нет, иначе предлагает сохранить или отбросить их
+// "Продолжить редактирование" возвращает на вкладку настроек, next не вызывается
+func (t *SettingsTab) confirmUnsaved(next func()) {
+	if !t.isDirty() {
+		next()
+		return
+	}
+
+	var d dialog.Dialog
+	saveBtn := widget.NewButton(localization.T("Save"), func() {
+		d.Hide()
+		if t.save() {
+			next()
+		}
+	})
+	saveBtn.Importance = widget.HighImportance
+	discardBtn := widget.NewButton(localization.T("Discard"), func() {
+		d.Hide()
+		t.loadSettings()
+		next()
+	})
+	keepBtn := widget.NewButton(localization.T("Keep editing"), func() {
+		d.Hide()
+		t.app.showSettings()
+	})
+
+	message := widget.NewLabel(localization.T("You have unsaved changes in Settings."))
+	d = dialog.NewCustomWithoutButtons(localization.T("Unsaved changes"), container.NewVBox(
+		message,
+		newHRow(layout.NewSpacer(), keepBtn, discardBtn, saveBtn),
+	), t.app.MainWindow())
+	d.Show()
+}
+
+// setEnabled включает или выключает кнопку
+func setEnabled(btn *widget.Button, enabled bool) {
+	if enabled {
+		btn.Enable()
+	} else {
+		btn.Disable()
+	}
+}
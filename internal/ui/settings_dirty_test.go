@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TestSettingsDirty проверяет учет несохраненных изменений и их отмену возвратом значения
+func TestSettingsDirty(t *testing.T) {
+	test.NewTempApp(t)
+
+	entry := widget.NewEntry()
+	changed := 0
+	check := widget.NewCheck("", func(bool) { changed++ })
+	search := widget.NewEntry()
+
+	tab := &SettingsTab{providerSearch: search}
+	tab.trackChanges(container.NewVBox(widget.NewCard("", "", entry), container.NewScroll(check), search))
+	tab.markClean()
+
+	if tab.isDirty() {
+		t.Fatal("dirty right after markClean")
+	}
+
+	search.SetText("query")
+	if tab.isDirty() {
+		t.Error("provider search made settings dirty")
+	}
+
+	check.SetChecked(true)
+	if !tab.isDirty() {
+		t.Error("changed check not tracked")
+	}
+	if changed != 1 {
+		t.Errorf("original OnChanged called %d times, want 1", changed)
+	}
+
+	check.SetChecked(false)
+	entry.SetText("value")
+	entry.SetText("")
+	if tab.isDirty() {
+		t.Error("dirty after values returned to saved ones")
+	}
+}
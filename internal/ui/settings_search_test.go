@@ -0,0 +1,24 @@
+package ui
+
+import "testing"
+
+// TestMatchesSearch проверяет поиск провайдеров по названию и полям настроек
+func TestMatchesSearch(t *testing.T) {
+	keywords := []string{"DataVaults", "Folder ID"}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"  ", true},
+		{"vault", true},
+		{"FOLDER", true},
+		{"gofile", false},
+	}
+	for _, tt := range tests {
+		if got := matchesSearch(tt.query, keywords); got != tt.want {
+			t.Errorf("matchesSearch(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -1,39 +1,146 @@
 package ui
 
 import (
+	"context"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	"multiUploader/internal/config"
+	"multiUploader/internal/health"
+	"multiUploader/internal/httpclient"
 	"multiUploader/internal/localization"
+	"multiUploader/internal/logging"
 	"multiUploader/internal/providers"
+	"multiUploader/internal/share"
+	"multiUploader/internal/shellintegration"
+	"multiUploader/internal/upload"
 )
 
+// accountInfoTimeout ограничивает запрос сведений об аккаунте
+const accountInfoTimeout = 30 * time.Second
+
 // SettingsTab представляет вкладку настроек
 type SettingsTab struct {
 	app *App
 
 	// Глобальные настройки
 	themeSelect            *widget.Select
+	accentSelect           *widget.Select
+	compactCheck           *widget.Check
+	scaleSelect            *widget.Select
 	languageSelect         *widget.Select
 	notificationRadioGroup *widget.RadioGroup
+	soundSuccessCheck      *widget.Check
+	soundFailureCheck      *widget.Check
+	retryStalledCheck      *widget.Check
+	maxConcurrentSelect    *widget.Select
+	preventSleepCheck      *widget.Check
+	splitCheck             *widget.Check
+	splitSizeSelect        *widget.Select
+	readBufferSelect       *widget.Select
+	readAheadSelect        *widget.Select
+	metricsCheck           *widget.Check
+	metricsPortEntry       *widget.Entry
+	caFileEntry            *widget.Entry
+	userAgentEntry         *widget.Entry
+	shellIntegrationBtn    *widget.Button
 
 	// Настройки провайдеров
 	providerForms map[string]*ProviderSettingsForm
+	// providerOrder провайдеры в порядке показа, providerAccordion - их секции (с учетом поиска)
+	providerOrder     []string
+	providerAccordion *widget.Accordion
+	providerSearch    *widget.Entry
+	noProvidersLabel  *widget.Label
+	xfsHostsEntry     *widget.Entry
+
+	// Публикация результата в чаты
+	shareForms map[share.Target]*shareForm
+
+	// Обработка картинок перед загрузкой
+	imageForm *imageForm
+	scanForm  *scanForm
+
+	// Шаблон итога нескольких загрузок
+	resultForm *resultForm
+
+	// Очистка истории загрузок
+	historyForm *historyForm
 
 	// Кнопки
 	saveBtn   *widget.Button
 	cancelBtn *widget.Button
+
+	// tracked поля ввода настроек, savedValues - их значения на момент загрузки или сохранения
+	tracked     []fyne.CanvasObject
+	savedValues []string
+	dirtyLabel  *widget.Label
+
+	// customAccent выбранный пользователем цвет (для пункта "Custom...")
+	customAccent string
+}
+
+// shareForm настройки публикации в один чат
+type shareForm struct {
+	webhookEntry  *widget.Entry
+	templateEntry *widget.Entry
+	autoCheck     *widget.Check
 }
 
 // ProviderSettingsForm представляет форму настроек для одного провайдера
 type ProviderSettingsForm struct {
 	enabledCheck *widget.Check
 	apiKeyEntry  *widget.Entry
-	statusLabel  *widget.Label
+	// backupKeyEntry запасной ключ на случай отказа основного (виден у провайдеров с ключом)
+	backupKeyEntry *widget.Entry
+	statusLabel    *widget.Label
+
+	// chunkSelect размер части multipart загрузки (nil, если провайдер грузит одним запросом)
+	chunkSelect *widget.Select
+	// thresholdSelect размер файла, с которого загрузка идет частями (nil без Capabilities.SinglePart)
+	thresholdSelect *widget.Select
+
+	// statusEntry страница состояния провайдера для проверки доступности
+	statusEntry *widget.Entry
+
+	// headersEntry дополнительные заголовки запросов провайдера
+	headersEntry *widget.Entry
+
+	// signSecretEntry, signKeyEntry и signAlgSelect HMAC подпись запросов (для своих серверов и API с подписью)
+	signSecretEntry *widget.Entry
+	signKeyEntry    *widget.Entry
+	signAlgSelect   *widget.Select
+
+	// insecureCheck отключает проверку TLS сертификата своего сервера (nil, если у провайдера его нет)
+	insecureCheck *widget.Check
+
+	// settingFields и settingEntries собственные поля провайдера (адрес сервера и т.п.)
+	settingFields  []providers.SettingField
+	settingEntries map[string]*widget.Entry
+
+	// accountLabel место и срок премиума (nil, если провайдер не отдает сведения об аккаунте)
+	accountLabel      *widget.Label
+	accountRefreshBtn *widget.Button
+
+	// expiresEntry день окончания премиума или срока ключа для напоминания
+	// (nil у провайдеров без ключа и сведений об аккаунте)
+	expiresEntry *widget.Entry
+
+	// item сворачиваемая секция провайдера, keywords - по чему она находится поиском
+	item     *widget.AccordionItem
+	keywords []string
 }
 
 // NewSettingsTab создает новую вкладку настроек
@@ -41,6 +148,7 @@ func NewSettingsTab(app *App) *SettingsTab {
 	tab := &SettingsTab{
 		app:           app,
 		providerForms: make(map[string]*ProviderSettingsForm),
+		shareForms:    make(map[share.Target]*shareForm),
 	}
 
 	return tab
@@ -51,15 +159,33 @@ func (t *SettingsTab) Build() fyne.CanvasObject {
 	// Глобальные настройки
 	globalSection := t.buildGlobalSettings()
 
+	// Публикация в чаты
+	shareSection := t.buildShareSettings()
+
+	// Обработка картинок
+	imageSection := t.buildImageSettings()
+
+	// Проверка на вирусы
+	scanSection := t.buildScanSettings()
+
+	// Шаблон итога нескольких загрузок
+	resultSection := t.buildResultSettings()
+
+	// Очистка истории
+	historySection := t.buildHistorySettings()
+
 	// Настройки провайдеров
 	providerSection := t.buildProviderSettings()
 
 	// Кнопки
 	t.saveBtn = widget.NewButton(localization.T("Save Settings"), t.onSave)
 	t.cancelBtn = widget.NewButton(localization.T("Cancel"), t.onCancel)
+	t.dirtyLabel = widget.NewLabel(localization.T("Unsaved changes"))
+	t.dirtyLabel.Importance = widget.WarningImportance
 
 	// Кнопки в отдельном ряду
-	buttonRow := container.NewHBox(
+	buttonRow := newHRow(
+		t.dirtyLabel,
 		layout.NewSpacer(),
 		t.cancelBtn,
 		t.saveBtn,
@@ -71,11 +197,25 @@ func (t *SettingsTab) Build() fyne.CanvasObject {
 		widget.NewSeparator(),
 		globalSection,
 		widget.NewSeparator(),
+		shareSection,
+		widget.NewSeparator(),
+		imageSection,
+		widget.NewSeparator(),
+		scanSection,
+		widget.NewSeparator(),
+		resultSection,
+		widget.NewSeparator(),
+		historySection,
+		widget.NewSeparator(),
 		providerSection,
 	)
 
-	// Загружаем текущие настройки
+	// Загружаем текущие настройки и следим за их изменением
+	t.trackChanges(scrollContent)
 	t.loadSettings()
+	for name := range t.providerForms {
+		t.loadAccountInfo(name)
+	}
 
 	// Используем Border: скролл в центре, кнопки прибиты к низу
 	return container.NewBorder(
@@ -97,12 +237,36 @@ func (t *SettingsTab) buildGlobalSettings() fyne.CanvasObject {
 	}
 	t.themeSelect = widget.NewSelect(themeOptions, nil)
 	themeLabel := widget.NewLabel(localization.T("Theme:"))
-	themeRow := container.NewBorder(nil, nil, themeLabel, nil, t.themeSelect)
+	themeRow := newRow(themeLabel, nil, t.themeSelect)
+
+	// Accent color select
+	accentOptions := make([]string, 0, len(accentPresets)+1)
+	for _, preset := range accentPresets {
+		accentOptions = append(accentOptions, localization.T(preset.Name))
+	}
+	accentOptions = append(accentOptions, localization.T("Custom..."))
+	t.accentSelect = widget.NewSelect(accentOptions, t.onAccentSelected)
+	accentLabel := widget.NewLabel(localization.T("Accent color:"))
+	accentRow := newRow(accentLabel, nil, t.accentSelect)
+
+	// Density
+	t.compactCheck = widget.NewCheck(localization.T("Compact layout"), nil)
+
+	// Масштаб интерфейса поверх FYNE_SCALE: для экранов высокой плотности и крупного текста
+	scaleOptions := make([]string, 0, len(uiScales))
+	for _, percent := range uiScales {
+		scaleOptions = append(scaleOptions, scaleToText(percent))
+	}
+	t.scaleSelect = widget.NewSelect(scaleOptions, nil)
+	scaleRow := newRow(widget.NewLabel(localization.T("Interface scale:")), nil, t.scaleSelect)
+	scaleHint := widget.NewLabel(localization.T("Makes text, icons and spacing larger or smaller. Applies on top of the system scale and the FYNE_SCALE variable."))
+	scaleHint.Wrapping = fyne.TextWrapWord
+	scaleHint.Importance = widget.LowImportance
 
 	// Language select
 	t.languageSelect = widget.NewSelect(localization.GetAvailableLanguages(), nil)
 	languageLabel := widget.NewLabel(localization.T("Language:"))
-	languageRow := container.NewBorder(nil, nil, languageLabel, nil, t.languageSelect)
+	languageRow := newRow(languageLabel, nil, t.languageSelect)
 
 	// Notification settings
 	notificationOptions := []string{
@@ -117,66 +281,577 @@ func (t *SettingsTab) buildGlobalSettings() fyne.CanvasObject {
 		t.notificationRadioGroup,
 	)
 
+	// Звуковые сигналы
+	t.soundSuccessCheck = widget.NewCheck(localization.T("On successful upload"), nil)
+	t.soundFailureCheck = widget.NewCheck(localization.T("On failed upload"), nil)
+	soundLabel := widget.NewLabel(localization.T("Sounds:"))
+	soundBox := container.NewVBox(
+		soundLabel,
+		newHRow(t.soundSuccessCheck, t.soundFailureCheck),
+	)
+
+	// Перезапуск частей, по которым перестали идти данные
+	t.retryStalledCheck = widget.NewCheck(localization.T("Retry stalled uploads automatically"), nil)
+
+	// Сколько загрузок идет одновременно, остальные ждут в очереди
+	concurrencyOptions := make([]string, 0, len(concurrencyLimits))
+	for _, n := range concurrencyLimits {
+		concurrencyOptions = append(concurrencyOptions, concurrencyToText(n))
+	}
+	t.maxConcurrentSelect = widget.NewSelect(concurrencyOptions, nil)
+	maxConcurrentLabel := widget.NewLabel(localization.T("Simultaneous uploads:"))
+	maxConcurrentRow := newRow(maxConcurrentLabel, nil, t.maxConcurrentSelect)
+
+	// Запрет сна системы, пока идут загрузки
+	t.preventSleepCheck = widget.NewCheck(localization.T("Keep the computer awake while uploading"), nil)
+
+	// Нарезка файлов больше лимита провайдера на части
+	t.splitCheck = widget.NewCheck(localization.T("Split files larger than the provider limit"), nil)
+	splitOptions := make([]string, 0, len(splitPartSizesMB))
+	for _, mb := range splitPartSizesMB {
+		splitOptions = append(splitOptions, splitSizeToText(mb))
+	}
+	t.splitSizeSelect = widget.NewSelect(splitOptions, nil)
+	splitSizeRow := newRow(widget.NewLabel(localization.T("Part size:")), nil, t.splitSizeSelect)
+
+	// Буфер чтения файла: меньше нагрузка на процессор при многогигабайтных загрузках
+	readBufferOptions := make([]string, 0, len(providers.ReadBufferSizesKB))
+	for _, kb := range providers.ReadBufferSizesKB {
+		readBufferOptions = append(readBufferOptions, readBufferToText(kb))
+	}
+	t.readBufferSelect = widget.NewSelect(readBufferOptions, nil)
+	readBufferRow := newRow(widget.NewLabel(localization.T("Read buffer:")), nil, t.readBufferSelect)
+	readBufferHint := widget.NewLabel(localization.T("How much of the file IPFS and XFileSharing uploads read at once. A larger buffer lowers CPU use on multi-gigabyte files."))
+	readBufferHint.Wrapping = fyne.TextWrapWord
+	readBufferHint.Importance = widget.LowImportance
+
+	// Упреждающее чтение: медленный диск или сетевая папка не останавливают передачу
+	readAheadOptions := make([]string, 0, len(upload.ReadAheadSizesMB))
+	for _, mb := range upload.ReadAheadSizesMB {
+		readAheadOptions = append(readAheadOptions, readAheadToText(mb))
+	}
+	t.readAheadSelect = widget.NewSelect(readAheadOptions, nil)
+	readAheadRow := newRow(widget.NewLabel(localization.T("Read-ahead:")), nil, t.readAheadSelect)
+	readAheadHint := widget.NewLabel(localization.T("How much of the file is read from disk ahead of the network, so slow seeks on hard disks and network shares do not stall the upload."))
+	readAheadHint.Wrapping = fyne.TextWrapWord
+	readAheadHint.Importance = widget.LowImportance
+
+	// Счетчики загрузок для Prometheus, доступные только с этого компьютера
+	t.metricsCheck = widget.NewCheck(localization.T("Serve upload metrics for Prometheus"), nil)
+	t.metricsPortEntry = widget.NewEntry()
+	metricsPortRow := newRow(widget.NewLabel(localization.T("Metrics port:")), nil, t.metricsPortEntry)
+	metricsHint := widget.NewLabel(localization.T("Counters of uploads, bytes, failures and provider latency at http://127.0.0.1:<port>/metrics. Only this computer can connect."))
+	metricsHint.Wrapping = fyne.TextWrapWord
+	metricsHint.Importance = widget.LowImportance
+
+	// Дополнительные доверенные CA для своих серверов за корпоративным или домашним сертификатом
+	t.caFileEntry = widget.NewEntry()
+	t.caFileEntry.SetPlaceHolder(localization.T("System certificates only"))
+	caBrowseBtn := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), t.onBrowseCAFile)
+	caFileRow := newRow(widget.NewLabel(localization.T("Extra CA certificates:")), caBrowseBtn, t.caFileEntry)
+	caHint := widget.NewLabel(localization.T("A PEM file with certificate authorities to trust in addition to the system ones, for servers with a corporate or homelab certificate."))
+	caHint.Wrapping = fyne.TextWrapWord
+	caHint.Importance = widget.LowImportance
+
+	// User-Agent всех запросов: некоторые хосты пускают к API только известные клиенты
+	t.userAgentEntry = widget.NewEntry()
+	t.userAgentEntry.SetPlaceHolder(localization.T("Default"))
+	userAgentRow := newRow(widget.NewLabel(localization.T("User-Agent:")), nil, t.userAgentEntry)
+
+	// Интеграция с файловым менеджером (применяется сразу, без сохранения)
+	t.shellIntegrationBtn = widget.NewButton("", t.onToggleShellIntegration)
+	t.updateShellIntegrationButton()
+	shellIntegrationLabel := widget.NewLabel(localization.T("File manager:"))
+	shellIntegrationRow := newRow(shellIntegrationLabel, nil, t.shellIntegrationBtn)
+
+	// На телефоне нет контекстного меню файлов ("Поделиться" объявлено в манифесте) и запрета сна
+	if isMobile() {
+		shellIntegrationRow.Hide()
+		t.preventSleepCheck.Hide()
+	}
+
 	globalGroup := container.NewVBox(
-		widget.NewLabelWithStyle(localization.T("Global Settings"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(localization.T("Global Settings"), readingAlign(), fyne.TextStyle{Bold: true}),
 		themeRow,
+		accentRow,
+		t.compactCheck,
+		scaleRow,
+		scaleHint,
 		languageRow,
 		notificationBox,
+		soundBox,
+		t.retryStalledCheck,
+		maxConcurrentRow,
+		t.preventSleepCheck,
+		t.splitCheck,
+		splitSizeRow,
+		readBufferRow,
+		readBufferHint,
+		readAheadRow,
+		readAheadHint,
+		t.metricsCheck,
+		metricsPortRow,
+		metricsHint,
+		caFileRow,
+		caHint,
+		userAgentRow,
+		shellIntegrationRow,
 	)
 
 	return globalGroup
 }
 
+// buildShareSettings создает секцию публикации результата в Discord и Slack
+func (t *SettingsTab) buildShareSettings() fyne.CanvasObject {
+	group := container.NewVBox(
+		widget.NewLabelWithStyle(localization.T("Share"), readingAlign(), fyne.TextStyle{Bold: true}),
+	)
+
+	for _, target := range share.Targets {
+		form := &shareForm{
+			webhookEntry:  widget.NewPasswordEntry(),
+			templateEntry: widget.NewMultiLineEntry(),
+			autoCheck:     widget.NewCheck(localization.T("Post every upload automatically"), nil),
+		}
+		form.webhookEntry.SetPlaceHolder(localization.T("Webhook URL"))
+		form.templateEntry.SetPlaceHolder(target.DefaultTemplate())
+		form.templateEntry.SetMinRowsVisible(2)
+		t.shareForms[target] = form
+
+		webhookRow := newRow(widget.NewLabel(localization.T("Webhook:")), nil, form.webhookEntry)
+		templateBox := container.NewVBox(widget.NewLabel(localization.T("Message template:")), form.templateEntry)
+		group.Add(widget.NewCard(target.Title(), "", container.NewVBox(webhookRow, templateBox, form.autoCheck)))
+	}
+
+	hint := widget.NewLabel(localization.T("Template placeholders: {file}, {size}, {provider}, {url}, {download_url}. Without automatic posting, a share button appears on each finished upload."))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+	group.Add(hint)
+	return group
+}
+
+// onAccentSelected обработчик выбора акцентного цвета
+// Для пункта "Custom..." открывает диалог выбора цвета
+func (t *SettingsTab) onAccentSelected(selected string) {
+	if selected != localization.T("Custom...") {
+		return
+	}
+
+	picker := dialog.NewColorPicker(localization.T("Accent color:"), "", func(c color.Color) {
+		t.customAccent = formatHexColor(c)
+		t.updateDirty()
+	}, t.app.MainWindow())
+	picker.Advanced = true
+	if c, err := parseHexColor(t.customAccent); err == nil {
+		picker.SetColor(c)
+	}
+	picker.Show()
+}
+
+// accentToText конвертирует hex цвет в пункт списка (пресет или "Custom...")
+func (t *SettingsTab) accentToText(hex string) string {
+	for _, preset := range accentPresets {
+		if preset.Hex == hex {
+			return localization.T(preset.Name)
+		}
+	}
+	return localization.T("Custom...")
+}
+
+// textToAccent конвертирует пункт списка в hex цвет
+func (t *SettingsTab) textToAccent(text string) string {
+	for _, preset := range accentPresets {
+		if text == localization.T(preset.Name) {
+			return preset.Hex
+		}
+	}
+	return t.customAccent
+}
+
+// updateShellIntegrationButton обновляет текст кнопки интеграции с файловым менеджером
+func (t *SettingsTab) updateShellIntegrationButton() {
+	if shellintegration.IsRegistered() {
+		t.shellIntegrationBtn.SetText(localization.T("Remove \"Send to multiUploader\" from context menu"))
+	} else {
+		t.shellIntegrationBtn.SetText(localization.T("Add \"Send to multiUploader\" to context menu"))
+	}
+}
+
+// onToggleShellIntegration добавляет или удаляет пункт контекстного меню
+func (t *SettingsTab) onToggleShellIntegration() {
+	var err error
+	if shellintegration.IsRegistered() {
+		err = shellintegration.Unregister()
+	} else {
+		err = shellintegration.Register()
+	}
+
+	if err != nil {
+		logging.ErrorWithError("Shell integration failed", err)
+		dialog.ShowError(err, t.app.MainWindow())
+	}
+
+	t.updateShellIntegrationButton()
+}
+
 // buildProviderSettings создает секцию настроек провайдеров
 func (t *SettingsTab) buildProviderSettings() fyne.CanvasObject {
-	providerBoxes := container.NewVBox(
-		widget.NewLabelWithStyle(localization.T("Provider Settings"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-	)
+	t.providerAccordion = widget.NewAccordion()
+	t.providerAccordion.MultiOpen = true
+	t.providerOrder = nil
+	// Список провайдеров мог измениться (добавленные хостинги XFileSharing)
+	t.providerForms = make(map[string]*ProviderSettingsForm)
 
-	// Создаем форму для каждого провайдера
+	// Создаем сворачиваемую секцию для каждого провайдера
 	for _, provider := range t.getAllProviders() {
+		name := provider.Name()
 		form := t.createProviderForm(provider)
-		t.providerForms[provider.Name()] = form
+		t.providerForms[name] = form
+		t.providerOrder = append(t.providerOrder, name)
 
-		providerBox := container.NewVBox(
-			widget.NewLabelWithStyle(provider.Name(), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-			form.enabledCheck,
-		)
+		providerBox := container.NewVBox(newRow(widget.NewIcon(providerIcon(name)), nil, form.enabledCheck))
 
 		if provider.RequiresAuth() {
 			apiKeyLabel := widget.NewLabel(localization.T("API Key:"))
-			apiKeyRow := container.NewBorder(nil, nil, apiKeyLabel, nil, form.apiKeyEntry)
+			apiKeyRow := newRow(apiKeyLabel, nil, t.secretField(form.apiKeyEntry))
 			providerBox.Add(apiKeyRow)
+			backupKeyLabel := widget.NewLabel(localization.T("Backup API key:"))
+			providerBox.Add(newRow(backupKeyLabel, nil, t.secretField(form.backupKeyEntry)))
+		}
+		if form.expiresEntry != nil {
+			providerBox.Add(newRow(widget.NewLabel(localization.T("Expires on:")), nil, form.expiresEntry))
+		}
+
+		form.keywords = []string{name}
+		for _, field := range form.settingFields {
+			label := widget.NewLabel(localization.T(field.Label))
+			form.keywords = append(form.keywords, label.Text)
+			entry := form.settingEntries[field.Key]
+			switch {
+			case field.Multiline:
+				providerBox.Add(container.NewVBox(label, entry))
+			case field.Secret:
+				providerBox.Add(newRow(label, nil, t.secretField(entry)))
+			default:
+				providerBox.Add(newRow(label, nil, entry))
+			}
+		}
+
+		advanced := container.NewVBox()
+		if form.chunkSelect != nil {
+			chunkLabel := widget.NewLabel(localization.T("Chunk size:"))
+			advanced.Add(newRow(chunkLabel, nil, form.chunkSelect))
+		}
+		if form.thresholdSelect != nil {
+			thresholdLabel := widget.NewLabel(localization.T("Multipart from:"))
+			advanced.Add(newRow(thresholdLabel, nil, form.thresholdSelect))
+		}
+		statusLabel := widget.NewLabel(localization.T("Status page:"))
+		advanced.Add(newRow(statusLabel, nil, form.statusEntry))
+		advanced.Add(container.NewVBox(widget.NewLabel(localization.T("Extra headers (Name: value per line):")), form.headersEntry))
+		signSecretLabel := widget.NewLabel(localization.T("Signing secret (HMAC):"))
+		advanced.Add(newRow(signSecretLabel, nil, t.secretField(form.signSecretEntry)))
+		signKeyLabel := widget.NewLabel(localization.T("Signing key ID:"))
+		advanced.Add(newRow(signKeyLabel, nil, form.signKeyEntry))
+		signAlgLabel := widget.NewLabel(localization.T("Signing algorithm:"))
+		advanced.Add(newRow(signAlgLabel, nil, form.signAlgSelect))
+		if form.insecureCheck != nil {
+			form.insecureCheck.OnChanged = func(checked bool) { t.onInsecureChanged(form, checked) }
+			warning := widget.NewLabel(localization.T(insecureTLSWarning))
+			warning.Wrapping = fyne.TextWrapWord
+			warning.Importance = widget.DangerImportance
+			advanced.Add(form.insecureCheck)
+			advanced.Add(warning)
+		}
+		providerBox.Add(widget.NewAccordion(widget.NewAccordionItem(localization.T("Advanced"), advanced)))
+
+		if form.accountLabel != nil {
+			form.accountRefreshBtn = widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), func() {
+				t.loadAccountInfo(name)
+			})
+			providerBox.Add(newRow(nil, form.accountRefreshBtn, form.accountLabel))
 		}
 
 		providerBox.Add(form.statusLabel)
 
-		providerBoxes.Add(providerBox)
-		providerBoxes.Add(widget.NewSeparator())
+		// Заголовок секции показывает, включен ли провайдер, даже когда она свернута
+		form.item = widget.NewAccordionItem(name, providerBox)
+		form.enabledCheck.OnChanged = func(bool) { t.updateProviderTitle(name) }
+	}
+
+	// Поиск по названию провайдера и его полям
+	t.providerSearch = widget.NewEntry()
+	t.providerSearch.SetPlaceHolder(localization.T("Search providers"))
+	t.providerSearch.OnChanged = t.filterProviders
+	t.noProvidersLabel = widget.NewLabel(localization.T("No providers match the search"))
+	t.noProvidersLabel.Importance = widget.LowImportance
+
+	enableAllBtn := widget.NewButton(localization.T("Enable all"), func() { t.setProvidersEnabled(true) })
+	disableAllBtn := widget.NewButton(localization.T("Disable all"), func() { t.setProvidersEnabled(false) })
+	t.filterProviders("")
+
+	// Хостинги на XFileSharing с тем же API, что DataVaults и FileKeeper, добавляются без новой версии
+	t.xfsHostsEntry = widget.NewMultiLineEntry()
+	t.xfsHostsEntry.SetPlaceHolder("MyHost = https://myhost.example")
+	t.xfsHostsEntry.SetMinRowsVisible(2)
+	xfsHostsHint := widget.NewLabel(localization.T("One host per line: Name = https://host. Any host running XFileSharing, the same API as DataVaults and FileKeeper, becomes a provider with its own API key. Add the file field name after the address if the host expects something other than \"file\"."))
+	xfsHostsHint.Wrapping = fyne.TextWrapWord
+	xfsHostsHint.Importance = widget.LowImportance
+
+	return container.NewVBox(
+		widget.NewLabelWithStyle(localization.T("Provider Settings"), readingAlign(), fyne.TextStyle{Bold: true}),
+		newRow(nil, newHRow(enableAllBtn, disableAllBtn), t.providerSearch),
+		t.noProvidersLabel,
+		t.providerAccordion,
+		widget.NewLabel(localization.T("XFileSharing hosts:")),
+		t.xfsHostsEntry,
+		xfsHostsHint,
+	)
+}
+
+// updateProviderTitle показывает в заголовке секции, включен ли провайдер
+func (t *SettingsTab) updateProviderTitle(name string) {
+	form := t.providerForms[name]
+	form.item.Title = name
+	if form.enabledCheck.Checked {
+		form.item.Title = fmt.Sprintf("%s · %s", name, localization.T("Enabled"))
+	}
+	t.providerAccordion.Refresh()
+}
+
+// filterProviders оставляет секции провайдеров, подходящих под запрос поиска
+// Единственная найденная секция раскрывается
+func (t *SettingsTab) filterProviders(query string) {
+	items := make([]*widget.AccordionItem, 0, len(t.providerOrder))
+	for _, name := range t.providerOrder {
+		form := t.providerForms[name]
+		if matchesSearch(query, form.keywords) {
+			items = append(items, form.item)
+		}
+	}
+	if len(items) == 1 {
+		items[0].Open = true
+	}
+
+	t.providerAccordion.Items = items
+	t.providerAccordion.Refresh()
+	setVisible(t.noProvidersLabel, len(items) == 0)
+}
+
+// setProvidersEnabled включает или выключает найденных поиском провайдеров (применяется после сохранения)
+func (t *SettingsTab) setProvidersEnabled(enabled bool) {
+	for _, item := range t.providerAccordion.Items {
+		for _, form := range t.providerForms {
+			if form.item == item {
+				form.enabledCheck.SetChecked(enabled)
+			}
+		}
+	}
+}
+
+// matchesSearch сообщает, есть ли query (без учета регистра) в одном из keywords; пустой query подходит всем
+func matchesSearch(query string, keywords []string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+	for _, keyword := range keywords {
+		if strings.Contains(strings.ToLower(keyword), query) {
+			return true
+		}
 	}
+	return false
+}
 
-	return providerBoxes
+// secretField добавляет к скрытому полю ключа кнопку копирования
+// Показать или скрыть ключ можно значком в самом поле пароля
+func (t *SettingsTab) secretField(entry *widget.Entry) fyne.CanvasObject {
+	copyBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+		if entry.Text != "" {
+			t.app.MainWindow().Clipboard().SetContent(entry.Text)
+		}
+	})
+	return newRow(nil, copyBtn, entry)
 }
 
 // createProviderForm создает форму настроек для провайдера
 func (t *SettingsTab) createProviderForm(provider providers.Provider) *ProviderSettingsForm {
 	form := &ProviderSettingsForm{
-		enabledCheck: widget.NewCheck(localization.T("Enabled"), nil),
-		apiKeyEntry:  widget.NewEntry(),
-		statusLabel:  widget.NewLabel(""),
+		enabledCheck:   widget.NewCheck(localization.T("Enabled"), nil),
+		apiKeyEntry:    widget.NewPasswordEntry(),
+		backupKeyEntry: widget.NewPasswordEntry(),
+		statusLabel:    widget.NewLabel(""),
+		statusEntry:    widget.NewEntry(),
+		headersEntry:   widget.NewMultiLineEntry(),
+
+		signSecretEntry: widget.NewPasswordEntry(),
+		signKeyEntry:    widget.NewEntry(),
+		signAlgSelect:   widget.NewSelect(signingAlgorithms, nil),
 	}
+	form.signSecretEntry.SetPlaceHolder(localization.T("Empty - requests are not signed"))
+	form.headersEntry.SetPlaceHolder("X-Api-Client: multiUploader")
+	form.headersEntry.SetMinRowsVisible(2)
+
+	form.statusEntry.SetPlaceHolder("https://status.example.com/api/v2/status.json")
 
 	form.apiKeyEntry.SetPlaceHolder(localization.T("Enter API key"))
+	form.backupKeyEntry.SetPlaceHolder(localization.T("Used when the main key is rejected or hits its limit"))
+
+	if caps := providers.GetCapabilities(provider); caps.Multipart {
+		options := make([]string, 0, len(providers.ChunkSizesMB))
+		for _, size := range providers.ChunkSizesMB {
+			options = append(options, chunkSizeToText(size))
+		}
+		form.chunkSelect = widget.NewSelect(options, nil)
+		if caps.SinglePart {
+			form.thresholdSelect = widget.NewSelect(options, nil)
+		}
+	}
+
+	if settings, ok := provider.(providers.SettingsProvider); ok {
+		form.settingFields = settings.SettingFields()
+		form.settingEntries = make(map[string]*widget.Entry, len(form.settingFields))
+		for _, field := range form.settingFields {
+			var entry *widget.Entry
+			switch {
+			case field.Secret:
+				entry = widget.NewPasswordEntry()
+			case field.Multiline:
+				entry = widget.NewMultiLineEntry()
+				entry.SetMinRowsVisible(3)
+			default:
+				entry = widget.NewEntry()
+			}
+			if field.Placeholder != "" {
+				entry.SetPlaceHolder(localization.T(field.Placeholder))
+			}
+			form.settingEntries[field.Key] = entry
+		}
+	}
+
+	if _, ok := provider.(providers.SelfHosted); ok {
+		form.insecureCheck = widget.NewCheck(localization.T("Do not verify the server's TLS certificate"), nil)
+	}
+
+	_, hasAccount := provider.(providers.AccountInfoProvider)
+	if hasAccount {
+		form.accountLabel = widget.NewLabel("")
+		form.accountLabel.Wrapping = fyne.TextWrapWord
+	}
+	if hasAccount || provider.RequiresAuth() {
+		form.expiresEntry = widget.NewEntry()
+		form.expiresEntry.SetPlaceHolder(localization.T("YYYY-MM-DD, to be reminded before it ends"))
+	}
 
 	return form
 }
 
+// setSavedAPIKey показывает API ключ, сохраненный вне вкладки (исправление ключа в карточке загрузки)
+// Ключ считается сохраненным, остальные несохраненные правки вкладки не меняются
+func (t *SettingsTab) setSavedAPIKey(name, apiKey string) {
+	form := t.providerForms[name]
+	if form == nil {
+		return
+	}
+	t.setSavedText(form.apiKeyEntry, apiKey)
+	t.loadAccountInfo(name)
+}
+
+// loadAccountInfo запрашивает сведения об аккаунте по введенному API ключу
+func (t *SettingsTab) loadAccountInfo(name string) {
+	form := t.providerForms[name]
+	factory := t.app.providerFactories[name]
+	if form == nil || form.accountLabel == nil || factory == nil {
+		return
+	}
+
+	apiKey := form.apiKeyEntry.Text
+	provider := factory(apiKey)
+	if provider.ValidateAPIKey(apiKey) != nil {
+		form.accountLabel.SetText("")
+		return
+	}
+
+	form.accountLabel.SetText(localization.T("Loading account info…"))
+	form.accountRefreshBtn.Disable()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(httpclient.WithProvider(context.Background(), name), accountInfoTimeout)
+		defer cancel()
+
+		info, err := provider.(providers.AccountInfoProvider).AccountInfo(ctx)
+		if err != nil {
+			logging.ErrorWithError("Failed to load account info", err, "provider", name)
+		}
+
+		fyne.Do(func() {
+			form.accountRefreshBtn.Enable()
+			// Пока шел запрос, ключ могли поменять - ответ относится к старому
+			if form.apiKeyEntry.Text != apiKey {
+				return
+			}
+			if err != nil {
+				form.accountLabel.SetText(localization.T("Could not load account info"))
+				return
+			}
+			form.accountLabel.SetText(accountInfoText(info, time.Now()))
+			t.saveFetchedExpiry(name, apiKey, info)
+		})
+	}()
+}
+
+// saveFetchedExpiry сохраняет срок премиума из сведений об аккаунте для напоминания о его окончании
+// Срок сохраненного ключа заменяет введенный вручную; ответ по несохраненному ключу не сохраняется
+func (t *SettingsTab) saveFetchedExpiry(name, apiKey string, info *providers.AccountInfo) {
+	form := t.providerForms[name]
+	cfg := t.app.Config()
+	providerCfg := cfg.GetProviderConfig(name)
+	if info.PremiumExpires.IsZero() || form.expiresEntry == nil || providerCfg.APIKey != apiKey {
+		return
+	}
+
+	expires := expiryDay(info.PremiumExpires)
+	if !providerCfg.Expires.Equal(expires) {
+		providerCfg.Expires = expires
+		cfg.SetProviderConfig(name, providerCfg)
+	}
+	t.setSavedText(form.expiresEntry, expires.Format(config.ExpiresLayout))
+	t.app.checkExpiry()
+}
+
+// accountInfoText описывает занятое место и статус премиума
+func accountInfoText(info *providers.AccountInfo, now time.Time) string {
+	storage := fmt.Sprintf(localization.T("Storage used: %s"), localization.FormatSize(info.StorageUsed))
+	if info.StorageQuota > 0 {
+		storage = fmt.Sprintf(localization.T("Storage used: %s of %s"),
+			localization.FormatSize(info.StorageUsed), localization.FormatSize(info.StorageQuota))
+	}
+
+	var premium string
+	switch {
+	case info.PremiumExpires.IsZero() && info.Premium:
+		premium = localization.T("Premium")
+	case info.PremiumExpires.IsZero():
+		premium = localization.T("Free account")
+	case info.PremiumExpires.After(now):
+		premium = fmt.Sprintf(localization.T("Premium until %s"), localization.FormatDateTime(info.PremiumExpires.Local()))
+	default:
+		premium = fmt.Sprintf(localization.T("Premium expired %s"), localization.FormatDateTime(info.PremiumExpires.Local()))
+	}
+
+	return storage + " · " + premium
+}
+
 // getAllProviders возвращает все зарегистрированные провайдеры с актуальными API ключами
 func (t *SettingsTab) getAllProviders() []providers.Provider {
 	allProviders := make([]providers.Provider, 0, len(t.app.providerFactories))
-	for name, factory := range t.app.providerFactories {
+	for _, name := range t.app.ProviderNames() {
+		// Мок провайдеры из меню разработчика не настраиваются
+		if t.app.mockProviders[name] {
+			continue
+		}
 		apiKey := t.app.config.GetProviderAPIKey(name)
-		provider := factory(apiKey)
+		provider := t.app.providerFactories[name](apiKey)
 		allProviders = append(allProviders, provider)
 	}
 	return allProviders
@@ -191,6 +866,16 @@ func (t *SettingsTab) loadSettings() {
 	// Переводим значение темы для UI
 	t.themeSelect.SetSelected(localization.T(globalCfg.Theme))
 
+	// Акцентный цвет и плотность
+	// customAccent выставляем до SetSelected, чтобы "Custom..." не открывал диалог при загрузке
+	t.customAccent = globalCfg.AccentColor
+	onAccentChanged := t.accentSelect.OnChanged
+	t.accentSelect.OnChanged = nil
+	t.accentSelect.SetSelected(t.accentToText(globalCfg.AccentColor))
+	t.accentSelect.OnChanged = onAccentChanged
+	t.compactCheck.SetChecked(globalCfg.Density == DensityCompact)
+	t.scaleSelect.SetSelected(scaleToText(globalCfg.UIScale))
+
 	// Загружаем язык из preferences
 	savedLanguage := t.app.fyneApp.Preferences().StringWithFallback("language", "auto")
 	languageName := localization.LanguageCodeToName(savedLanguage)
@@ -200,13 +885,205 @@ func (t *SettingsTab) loadSettings() {
 	notificationText := t.notificationModeToText(globalCfg.NotificationMode)
 	t.notificationRadioGroup.SetSelected(notificationText)
 
+	t.soundSuccessCheck.SetChecked(globalCfg.SoundOnSuccess)
+	t.soundFailureCheck.SetChecked(globalCfg.SoundOnFailure)
+	t.retryStalledCheck.SetChecked(globalCfg.RetryStalled)
+	t.maxConcurrentSelect.SetSelected(concurrencyToText(globalCfg.MaxConcurrentUploads))
+	t.preventSleepCheck.SetChecked(globalCfg.PreventSleep)
+	t.splitCheck.SetChecked(globalCfg.SplitLargeFiles)
+	t.splitSizeSelect.SetSelected(splitSizeToText(globalCfg.SplitPartMB))
+	t.readBufferSelect.SetSelected(readBufferToText(globalCfg.ReadBufferKB))
+	t.readAheadSelect.SetSelected(readAheadToText(globalCfg.ReadAheadMB))
+	t.metricsCheck.SetChecked(globalCfg.MetricsEnabled)
+	t.metricsPortEntry.SetText(strconv.Itoa(globalCfg.MetricsPort))
+	t.caFileEntry.SetText(globalCfg.CACertFile)
+	t.userAgentEntry.SetText(globalCfg.UserAgent)
+	t.xfsHostsEntry.SetText(globalCfg.XFSHosts)
+
+	// Публикация в чаты
+	for target, form := range t.shareForms {
+		shareCfg := cfg.GetShareConfig(string(target))
+		form.webhookEntry.SetText(shareCfg.Webhook)
+		form.templateEntry.SetText(shareCfg.Template)
+		form.autoCheck.SetChecked(shareCfg.Auto)
+	}
+
+	// Обработка картинок
+	t.imageForm.load(cfg.GetImageConfig())
+	t.scanForm.load(cfg.GetScanConfig())
+	t.resultForm.load(cfg.GetResultConfig())
+	t.historyForm.load(cfg.GetHistoryConfig())
+
 	// Загружаем настройки провайдеров
 	for name, form := range t.providerForms {
 		providerCfg := cfg.GetProviderConfig(name)
 
 		form.enabledCheck.SetChecked(providerCfg.Enabled)
 		form.apiKeyEntry.SetText(providerCfg.APIKey)
+		form.backupKeyEntry.SetText(providerCfg.BackupAPIKey)
+		form.statusEntry.SetText(providerCfg.StatusURL)
+		form.headersEntry.SetText(providerCfg.Headers)
+		form.signSecretEntry.SetText(providerCfg.SigningSecret)
+		form.signKeyEntry.SetText(providerCfg.SigningKeyID)
+		form.signAlgSelect.SetSelected(signingAlgorithmText(providerCfg.SigningAlgorithm))
+		if form.expiresEntry != nil {
+			form.expiresEntry.SetText("")
+			if !providerCfg.Expires.IsZero() {
+				form.expiresEntry.SetText(providerCfg.Expires.Format(config.ExpiresLayout))
+			}
+		}
+		if form.chunkSelect != nil {
+			form.chunkSelect.SetSelected(chunkSizeToText(providerCfg.ChunkSizeMB))
+		}
+		if form.thresholdSelect != nil {
+			form.thresholdSelect.SetSelected(chunkSizeToText(providerCfg.MultipartThresholdMB))
+		}
+		if form.insecureCheck != nil {
+			// Загрузка сохраненного значения не спрашивает подтверждения
+			setCheckedQuietly(form.insecureCheck, providerCfg.InsecureTLS)
+		}
+		if len(form.settingEntries) > 0 {
+			keys := make([]string, 0, len(form.settingEntries))
+			for key := range form.settingEntries {
+				keys = append(keys, key)
+			}
+			for key, value := range cfg.GetProviderSettings(name, keys) {
+				form.settingEntries[key].SetText(value)
+			}
+		}
+	}
+
+	t.markClean()
+}
+
+// chunkSizeToText конвертирует размер части в МБ в UI текст
+func chunkSizeToText(sizeMB int) string {
+	if sizeMB == 0 {
+		return localization.T("auto")
+	}
+	return localization.FormatMegabytes(sizeMB)
+}
+
+// signingAlgorithms варианты алгоритма HMAC подписи запросов
+var signingAlgorithms = []string{"SHA-256", "SHA-512"}
+
+// signingAlgorithmText конвертирует алгоритм подписи из настроек в UI текст
+func signingAlgorithmText(algorithm string) string {
+	if algorithm == "sha512" {
+		return "SHA-512"
+	}
+	return "SHA-256"
+}
+
+// textToSigningAlgorithm конвертирует UI текст в алгоритм подписи для настроек
+func textToSigningAlgorithm(text string) string {
+	if text == "SHA-512" {
+		return "sha512"
+	}
+	return "sha256"
+}
+
+// concurrencyLimits варианты числа одновременных загрузок (0 - без ограничения)
+var concurrencyLimits = []int{1, 2, 3, 4, 5, 0}
+
+// concurrencyToText конвертирует число одновременных загрузок в UI текст
+func concurrencyToText(n int) string {
+	if n == 0 {
+		return localization.T("Unlimited")
 	}
+	return strconv.Itoa(n)
+}
+
+// textToConcurrency конвертирует UI текст в число одновременных загрузок
+func textToConcurrency(text string) int {
+	for _, n := range concurrencyLimits {
+		if concurrencyToText(n) == text {
+			return n
+		}
+	}
+	return config.DefaultMaxConcurrentUploads
+}
+
+// splitPartSizesMB варианты размера части разрезанного файла (0 - по лимиту провайдера)
+var splitPartSizesMB = []int{0, 100, 500, 1024, 2048, 4096}
+
+// splitSizeToText конвертирует размер части разрезанного файла в UI текст
+func splitSizeToText(sizeMB int) string {
+	if sizeMB == 0 {
+		return localization.T("Provider limit")
+	}
+	return localization.FormatMegabytes(sizeMB)
+}
+
+// textToSplitSize конвертирует UI текст в размер части разрезанного файла в МБ
+func textToSplitSize(text string) int {
+	for _, size := range splitPartSizesMB {
+		if splitSizeToText(size) == text {
+			return size
+		}
+	}
+	return 0
+}
+
+// scaleToText конвертирует масштаб интерфейса в процентах в UI текст
+func scaleToText(percent int) string {
+	return fmt.Sprintf("%d%%", percent)
+}
+
+// textToScale конвертирует UI текст в масштаб интерфейса в процентах
+func textToScale(text string) int {
+	for _, percent := range uiScales {
+		if scaleToText(percent) == text {
+			return percent
+		}
+	}
+	return config.DefaultUIScale
+}
+
+// readBufferToText конвертирует размер буфера чтения в КБ в UI текст
+func readBufferToText(sizeKB int) string {
+	if sizeKB == 0 {
+		return localization.T("Off")
+	}
+	return localization.FormatSize(int64(sizeKB) * 1024)
+}
+
+// textToReadBuffer конвертирует UI текст в размер буфера чтения в КБ
+func textToReadBuffer(text string) int {
+	for _, size := range providers.ReadBufferSizesKB {
+		if readBufferToText(size) == text {
+			return size
+		}
+	}
+	return 0
+}
+
+// readAheadToText конвертирует размер упреждающего чтения в МБ в UI текст
+func readAheadToText(sizeMB int) string {
+	if sizeMB == 0 {
+		return localization.T("Off")
+	}
+	return localization.FormatSize(int64(sizeMB) << 20)
+}
+
+// textToReadAhead конвертирует UI текст в размер упреждающего чтения в МБ
+func textToReadAhead(text string) int {
+	for _, size := range upload.ReadAheadSizesMB {
+		if readAheadToText(size) == text {
+			return size
+		}
+	}
+	return 0
+}
+
+// textToChunkSize конвертирует UI текст в размер части в МБ
+func textToChunkSize(text string) int {
+	for _, size := range providers.ChunkSizesMB {
+		if chunkSizeToText(size) == text {
+			return size
+		}
+	}
+	return 0
 }
 
 // notificationModeToText конвертирует NotificationMode в UI текст
@@ -254,8 +1131,101 @@ func (t *SettingsTab) translatedThemeToCode(text string) string {
 
 // onSave обработчик сохранения настроек
 func (t *SettingsTab) onSave() {
+	t.save()
+}
+
+// save сохраняет настройки; false - значения не прошли проверку, ошибка уже показана
+func (t *SettingsTab) save() bool {
 	cfg := t.app.Config()
 
+	// Неверный адрес webhook не сохраняем, чтобы публикация не падала после каждой загрузки
+	for _, target := range share.Targets {
+		webhook := strings.TrimSpace(t.shareForms[target].webhookEntry.Text)
+		if webhook == "" {
+			continue
+		}
+		if err := share.ValidateWebhook(target, webhook); err != nil {
+			dialog.ShowError(fmt.Errorf("%s: %w", target.Title(), err), t.app.MainWindow())
+			return false
+		}
+	}
+
+	// Неверный адрес страницы состояния сделал бы провайдера "недоступным" при каждой проверке
+	for _, name := range t.providerOrder {
+		statusURL := strings.TrimSpace(t.providerForms[name].statusEntry.Text)
+		if statusURL == "" {
+			continue
+		}
+		if err := health.ValidateStatusURL(statusURL); err != nil {
+			dialog.ShowError(fmt.Errorf("%s: %w", name, err), t.app.MainWindow())
+			return false
+		}
+	}
+
+	// Неверная дата окончания молча выключила бы напоминание
+	for _, name := range t.providerOrder {
+		if form := t.providerForms[name]; form.expiresEntry != nil {
+			if _, err := config.ParseExpires(form.expiresEntry.Text); err != nil {
+				dialog.ShowError(fmt.Errorf("%s: %w", name, err), t.app.MainWindow())
+				return false
+			}
+		}
+	}
+
+	// Неверный заголовок отклонил бы каждый запрос провайдера
+	for _, name := range t.providerOrder {
+		if _, err := httpclient.ParseHeaders(t.providerForms[name].headersEntry.Text); err != nil {
+			dialog.ShowError(fmt.Errorf("%s: %w", name, err), t.app.MainWindow())
+			return false
+		}
+	}
+
+	// Идентификатор ключа без секрета выглядел бы как включенная подпись
+	for _, name := range t.providerOrder {
+		form := t.providerForms[name]
+		if form.signSecretEntry.Text == "" && strings.TrimSpace(form.signKeyEntry.Text) != "" {
+			dialog.ShowError(fmt.Errorf("%s: %s", name, localization.T("Enter the signing secret or clear the signing key ID")), t.app.MainWindow())
+			return false
+		}
+	}
+
+	// Неверная строка или занятое имя оставили бы хостинг без провайдера
+	if _, err := t.app.parseXFSHosts(t.xfsHostsEntry.Text); err != nil {
+		dialog.ShowError(fmt.Errorf("%s %w", localization.T("XFileSharing hosts:"), err), t.app.MainWindow())
+		return false
+	}
+
+	imageCfg, err := t.imageForm.config()
+	if err != nil {
+		dialog.ShowError(err, t.app.MainWindow())
+		return false
+	}
+
+	scanCfg, err := t.scanForm.config()
+	if err != nil {
+		dialog.ShowError(err, t.app.MainWindow())
+		return false
+	}
+
+	historyCfg, err := t.historyForm.config()
+	if err != nil {
+		dialog.ShowError(err, t.app.MainWindow())
+		return false
+	}
+
+	metricsPort, err := parseMetricsPort(t.metricsPortEntry.Text)
+	if err != nil {
+		dialog.ShowError(err, t.app.MainWindow())
+		return false
+	}
+
+	// Без проверки файл без сертификатов молча оставил бы только системные CA
+	caFile := strings.TrimSpace(t.caFileEntry.Text)
+	if err := httpclient.ValidateCAFile(caFile); err != nil {
+		dialog.ShowError(fmt.Errorf("%s %w", localization.T("Extra CA certificates:"), err), t.app.MainWindow())
+		return false
+	}
+
 	// Проверяем, изменился ли язык
 	savedLanguage := t.app.fyneApp.Preferences().StringWithFallback("language", "auto")
 	newLanguageCode := localization.LanguageNameToCode(t.languageSelect.Selected)
@@ -265,33 +1235,101 @@ func (t *SettingsTab) onSave() {
 	themeCode := t.translatedThemeToCode(t.themeSelect.Selected)
 
 	// Сохраняем глобальные настройки
-	globalCfg := config.GlobalConfig{
-		Theme:            themeCode,
-		NotificationMode: t.textToNotificationMode(t.notificationRadioGroup.Selected),
+	globalCfg := cfg.GetGlobalConfig()
+	globalCfg.Theme = themeCode
+	globalCfg.NotificationMode = t.textToNotificationMode(t.notificationRadioGroup.Selected)
+	globalCfg.SoundOnSuccess = t.soundSuccessCheck.Checked
+	globalCfg.SoundOnFailure = t.soundFailureCheck.Checked
+	globalCfg.RetryStalled = t.retryStalledCheck.Checked
+	globalCfg.MaxConcurrentUploads = textToConcurrency(t.maxConcurrentSelect.Selected)
+	globalCfg.PreventSleep = t.preventSleepCheck.Checked
+	globalCfg.SplitLargeFiles = t.splitCheck.Checked
+	globalCfg.SplitPartMB = textToSplitSize(t.splitSizeSelect.Selected)
+	globalCfg.ReadBufferKB = textToReadBuffer(t.readBufferSelect.Selected)
+	globalCfg.ReadAheadMB = textToReadAhead(t.readAheadSelect.Selected)
+	globalCfg.MetricsEnabled = t.metricsCheck.Checked
+	globalCfg.MetricsPort = metricsPort
+	globalCfg.CACertFile = caFile
+	globalCfg.UserAgent = strings.TrimSpace(t.userAgentEntry.Text)
+	globalCfg.XFSHosts = strings.TrimSpace(t.xfsHostsEntry.Text)
+	globalCfg.AccentColor = t.textToAccent(t.accentSelect.Selected)
+	globalCfg.Density = DensityNormal
+	if t.compactCheck.Checked {
+		globalCfg.Density = DensityCompact
 	}
+	globalCfg.UIScale = textToScale(t.scaleSelect.Selected)
 	cfg.SetGlobalConfig(globalCfg)
 
+	// Публикация в чаты; шаблон, совпадающий с шаблоном по умолчанию, не храним
+	for target, form := range t.shareForms {
+		template := strings.TrimSpace(form.templateEntry.Text)
+		if template == target.DefaultTemplate() {
+			template = ""
+		}
+		cfg.SetShareConfig(string(target), config.ShareConfig{
+			Webhook:  strings.TrimSpace(form.webhookEntry.Text),
+			Template: template,
+			Auto:     form.autoCheck.Checked,
+		})
+	}
+
+	cfg.SetImageConfig(imageCfg)
+	cfg.SetScanConfig(scanCfg)
+	cfg.SetResultConfig(t.resultForm.config())
+	cfg.SetHistoryConfig(historyCfg)
+
 	// Сохраняем язык в preferences
 	t.app.fyneApp.Preferences().SetString("language", newLanguageCode)
 
 	// Сохраняем настройки провайдеров
 	for name, form := range t.providerForms {
-		providerCfg := config.ProviderConfig{
-			Enabled: form.enabledCheck.Checked,
-			APIKey:  form.apiKeyEntry.Text,
+		providerCfg := cfg.GetProviderConfig(name)
+		keyChanged := providerCfg.APIKey != form.apiKeyEntry.Text
+		providerCfg.Enabled = form.enabledCheck.Checked
+		providerCfg.APIKey = form.apiKeyEntry.Text
+		providerCfg.BackupAPIKey = strings.TrimSpace(form.backupKeyEntry.Text)
+		providerCfg.StatusURL = strings.TrimSpace(form.statusEntry.Text)
+		providerCfg.Headers = strings.TrimSpace(form.headersEntry.Text)
+		providerCfg.SigningSecret = form.signSecretEntry.Text
+		providerCfg.SigningKeyID = strings.TrimSpace(form.signKeyEntry.Text)
+		providerCfg.SigningAlgorithm = textToSigningAlgorithm(form.signAlgSelect.Selected)
+		if form.chunkSelect != nil {
+			providerCfg.ChunkSizeMB = textToChunkSize(form.chunkSelect.Selected)
+		}
+		if form.thresholdSelect != nil {
+			providerCfg.MultipartThresholdMB = textToChunkSize(form.thresholdSelect.Selected)
+		}
+		if form.insecureCheck != nil {
+			providerCfg.InsecureTLS = form.insecureCheck.Checked
+		}
+		if form.expiresEntry != nil {
+			providerCfg.Expires, _ = config.ParseExpires(form.expiresEntry.Text)
+		}
+		if len(form.settingEntries) > 0 {
+			values := make(map[string]string, len(form.settingEntries))
+			for key, entry := range form.settingEntries {
+				values[key] = strings.TrimSpace(entry.Text)
+			}
+			cfg.SetProviderSettings(name, values)
 		}
 
 		cfg.SetProviderConfig(name, providerCfg)
+		if keyChanged {
+			t.loadAccountInfo(name)
+		}
 	}
 
-	// Показываем соответствующее сообщение
-	if languageChanged {
-		dialog.ShowInformation(localization.T("Language changed"),
-			localization.T("Please restart the application to apply language changes"),
-			t.app.MainWindow())
-	} else {
-		dialog.ShowInformation(localization.T("Success"), localization.T("Settings saved successfully!"), t.app.MainWindow())
+	t.markClean()
+
+	// Добавленные и удаленные хостинги XFileSharing - до применения настроек провайдеров
+	hostsChanged := t.app.applyXFSHosts()
+
+	// Адреса серверов и доверенные CA могли измениться - до проверки доступности провайдеров
+	if err := t.app.applyTLS(); err != nil {
+		logging.ErrorWithError("Failed to apply TLS settings", err)
 	}
+	t.app.applyHeaders()
+	t.app.applyHistoryRetention()
 
 	// Применяем тему
 	t.app.ApplyTheme()
@@ -300,10 +1338,107 @@ func (t *SettingsTab) onSave() {
 	if t.app.uploadTab != nil {
 		t.app.uploadTab.Refresh()
 	}
+
+	// Набор включенных провайдеров мог измениться - проверяем их доступность
+	if t.app.healthIndicator != nil {
+		t.app.healthIndicator.Check()
+	}
+
+	// Применяем язык сразу: перезагружаем перевод и пересоздаем интерфейс
+	if languageChanged {
+		if err := localization.Init(newLanguageCode); err != nil {
+			logging.ErrorWithError("Failed to switch language", err, "language", newLanguageCode)
+		}
+	}
+	// Новые хостинги получают свои секции настроек
+	if languageChanged || hostsChanged {
+		t.app.Rebuild()
+	}
+
+	// Настройки сохранены, но порт может быть занят другой программой
+	if err := t.app.applyMetrics(); err != nil {
+		logging.ErrorWithError("Failed to start metrics endpoint", err)
+		dialog.ShowError(fmt.Errorf("%s: %w", localization.T("Metrics endpoint"), err), t.app.MainWindow())
+		return true
+	}
+
+	dialog.ShowInformation(localization.T("Success"), localization.T("Settings saved successfully!"), t.app.MainWindow())
+	return true
+}
+
+// insecureTLSWarning предупреждение об отключенной проверке сертификата
+const insecureTLSWarning = "Anyone on the network path can read and change your uploads to this server. Use it only for your own server with a self-signed certificate."
+
+// onInsecureChanged просит подтвердить отключение проверки сертификата
+func (t *SettingsTab) onInsecureChanged(form *ProviderSettingsForm, checked bool) {
+	if !checked {
+		return
+	}
+	dialog.ShowConfirm(localization.T("Disable certificate check?"), localization.T(insecureTLSWarning), func(ok bool) {
+		if !ok {
+			setCheckedQuietly(form.insecureCheck, false)
+			t.updateDirty()
+		}
+	}, t.app.MainWindow())
+}
+
+// onBrowseCAFile выбирает PEM файл с дополнительными CA
+func (t *SettingsTab) onBrowseCAFile() {
+	fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, t.app.MainWindow())
+			return
+		}
+		if reader == nil {
+			return // Пользователь отменил
+		}
+		defer reader.Close()
+
+		path := reader.URI().Path()
+		// Файл из хранилища телефона не открывается по пути: CA читаются из копии в папке приложения
+		if reader.URI().Scheme() != "file" {
+			var err error
+			if path, err = t.storeCAFile(reader); err != nil {
+				dialog.ShowError(err, t.app.MainWindow())
+				return
+			}
+		}
+		t.caFileEntry.SetText(path)
+	}, t.app.MainWindow())
+	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".pem", ".crt", ".cer"}))
+	resizeDialog(fileDialog, fyne.NewSize(800, 600))
+	fileDialog.Show()
+}
+
+// storeCAFile копирует PEM файл в папку приложения и возвращает путь копии
+func (t *SettingsTab) storeCAFile(reader io.Reader) (string, error) {
+	uri, err := storage.Child(t.app.fyneApp.Storage().RootURI(), "ca.pem")
+	if err != nil {
+		return "", err
+	}
+	writer, err := storage.Writer(uri)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(writer, reader); err != nil {
+		writer.Close()
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	return uri.Path(), nil
+}
+
+// setCheckedQuietly меняет флажок, не вызывая его обработчик
+func setCheckedQuietly(check *widget.Check, checked bool) {
+	onChanged := check.OnChanged
+	check.OnChanged = nil
+	check.SetChecked(checked)
+	check.OnChanged = onChanged
 }
 
-// onCancel обработчик отмены изменений
+// onCancel обработчик отмены изменений: поля возвращаются к сохраненным значениям
 func (t *SettingsTab) onCancel() {
 	t.loadSettings()
-	dialog.ShowInformation(localization.T("Cancelled"), localization.T("Changes discarded"), t.app.MainWindow())
 }
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+	"multiUploader/internal/logging"
+	"multiUploader/internal/share"
+	"multiUploader/internal/viewmodel"
+)
+
+// shareTimeout ограничивает отправку сообщения в чат
+const shareTimeout = 30 * time.Second
+
+// shareMessage данные загрузки для шаблона сообщения
+func shareMessage(c viewmodel.Completion) share.Message {
+	m := share.Message{
+		FileName:    c.FileName,
+		Provider:    c.Provider,
+		URL:         c.Result.URL,
+		DownloadURL: c.Result.DownloadURL,
+	}
+	if c.Size > 0 {
+		m.Size = localization.FormatSize(c.Size)
+	}
+	return m
+}
+
+// postShare публикует итог загрузки в чат по его настройкам
+func (a *App) postShare(target share.Target, c viewmodel.Completion) error {
+	cfg := a.config.GetShareConfig(string(target))
+	template := cfg.Template
+	if template == "" {
+		template = target.DefaultTemplate()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shareTimeout)
+	defer cancel()
+	return share.Post(ctx, target, cfg.Webhook, share.Render(template, shareMessage(c)))
+}
+
+// autoShare публикует успешную загрузку во все чаты с автоматической публикацией (вызывается из горутины)
+func (t *UploadTab) autoShare(c viewmodel.Completion) {
+	// В dry run ссылки ненастоящие - в чаты их не отправляем
+	if c.DryRun || c.Result == nil {
+		return
+	}
+	for _, target := range share.Targets {
+		cfg := t.app.Config().GetShareConfig(string(target))
+		if !cfg.Auto || cfg.Webhook == "" {
+			continue
+		}
+		if err := t.app.postShare(target, c); err != nil {
+			logging.ErrorWithError("Failed to share upload", err, "target", target, "filename", c.FileName)
+			t.app.SendNotification(
+				localization.T("Sharing failed"),
+				fmt.Sprintf(localization.T("Could not post %s to %s"), c.FileName, target.Title()),
+			)
+		}
+	}
+}
+
+// shareButtons кнопки публикации итога в настроенные чаты (вызывается из главного потока)
+func (t *UploadTab) shareButtons(c viewmodel.Completion) []fyne.CanvasObject {
+	if c.DryRun || c.Result == nil {
+		return nil
+	}
+
+	var buttons []fyne.CanvasObject
+	for _, target := range share.Targets {
+		if t.app.Config().GetShareConfig(string(target)).Webhook == "" {
+			continue
+		}
+		var btn *widget.Button
+		btn = widget.NewButtonWithIcon(fmt.Sprintf(localization.T("Share to %s"), target.Title()), theme.MailSendIcon(), func() {
+			btn.Disable()
+			go func() {
+				err := t.app.postShare(target, c)
+				fyne.Do(func() {
+					if err != nil {
+						logging.ErrorWithError("Failed to share upload", err, "target", target, "filename", c.FileName)
+						btn.Enable()
+						dialog.ShowError(err, t.app.MainWindow())
+						return
+					}
+					btn.SetText(fmt.Sprintf(localization.T("Shared to %s"), target.Title()))
+				})
+			}()
+		})
+		buttons = append(buttons, btn)
+	}
+	return buttons
+}
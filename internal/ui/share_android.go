@@ -0,0 +1,159 @@
+//go:build android
+
+package ui
+
+/*
+#include <jni.h>
+#include <stdint.h>
+#include <stdlib.h>
+#include <string.h>
+
+// check_exception сбрасывает исключение Java после вызова JNI; 1 - исключение было
+static int check_exception(JNIEnv *env) {
+	if ((*env)->ExceptionCheck(env)) {
+		(*env)->ExceptionClear(env);
+		return 1;
+	}
+	return 0;
+}
+
+// append_uri дописывает строковое представление объекта uri в list через перевод строки
+static char *append_uri(JNIEnv *env, char *list, jobject uri) {
+	if (uri == NULL) {
+		return list;
+	}
+	jclass cls = (*env)->GetObjectClass(env, uri);
+	jmethodID toString = (*env)->GetMethodID(env, cls, "toString", "()Ljava/lang/String;");
+	jstring str = (jstring)(*env)->CallObjectMethod(env, uri, toString);
+	(*env)->DeleteLocalRef(env, cls);
+	if (check_exception(env) || str == NULL) {
+		return list;
+	}
+
+	const char *chars = (*env)->GetStringUTFChars(env, str, NULL);
+	size_t used = list == NULL ? 0 : strlen(list);
+	size_t add = strlen(chars);
+	char *grown = realloc(list, used + add + 2);
+	if (grown != NULL) {
+		if (used > 0) {
+			grown[used++] = '\n';
+		}
+		memcpy(grown + used, chars, add + 1);
+		list = grown;
+	}
+	(*env)->ReleaseStringUTFChars(env, str, chars);
+	(*env)->DeleteLocalRef(env, str);
+	return list;
+}
+
+// shared_uris читает URI файлов из интента ACTION_SEND / ACTION_SEND_MULTIPLE, с которым запущена активити
+// Возвращает URI через перевод строки (NULL - интент не "Поделиться") и помечает интент обработанным,
+// чтобы при следующем возвращении на экран файлы не выбрались повторно
+static char *shared_uris(uintptr_t jni_env, uintptr_t ctx) {
+	JNIEnv *env = (JNIEnv *)jni_env;
+	jobject activity = (jobject)ctx;
+
+	jclass activityClass = (*env)->GetObjectClass(env, activity);
+	jmethodID getIntent = (*env)->GetMethodID(env, activityClass, "getIntent", "()Landroid/content/Intent;");
+	jobject intent = (*env)->CallObjectMethod(env, activity, getIntent);
+	(*env)->DeleteLocalRef(env, activityClass);
+	if (check_exception(env) || intent == NULL) {
+		return NULL;
+	}
+
+	jclass intentClass = (*env)->GetObjectClass(env, intent);
+	jmethodID getAction = (*env)->GetMethodID(env, intentClass, "getAction", "()Ljava/lang/String;");
+	jstring action = (jstring)(*env)->CallObjectMethod(env, intent, getAction);
+	if (check_exception(env) || action == NULL) {
+		(*env)->DeleteLocalRef(env, intentClass);
+		(*env)->DeleteLocalRef(env, intent);
+		return NULL;
+	}
+	const char *actionChars = (*env)->GetStringUTFChars(env, action, NULL);
+	int single = strcmp(actionChars, "android.intent.action.SEND") == 0;
+	int multiple = strcmp(actionChars, "android.intent.action.SEND_MULTIPLE") == 0;
+	(*env)->ReleaseStringUTFChars(env, action, actionChars);
+	(*env)->DeleteLocalRef(env, action);
+	if (!single && !multiple) {
+		(*env)->DeleteLocalRef(env, intentClass);
+		(*env)->DeleteLocalRef(env, intent);
+		return NULL;
+	}
+
+	char *list = NULL;
+	jstring extraStream = (*env)->NewStringUTF(env, "android.intent.extra.STREAM");
+	if (single) {
+		jmethodID getExtra = (*env)->GetMethodID(env, intentClass, "getParcelableExtra", "(Ljava/lang/String;)Landroid/os/Parcelable;");
+		jobject uri = (*env)->CallObjectMethod(env, intent, getExtra, extraStream);
+		if (!check_exception(env)) {
+			list = append_uri(env, list, uri);
+		}
+		if (uri != NULL) {
+			(*env)->DeleteLocalRef(env, uri);
+		}
+	} else {
+		jmethodID getExtras = (*env)->GetMethodID(env, intentClass, "getParcelableArrayListExtra", "(Ljava/lang/String;)Ljava/util/ArrayList;");
+		jobject uris = (*env)->CallObjectMethod(env, intent, getExtras, extraStream);
+		if (!check_exception(env) && uris != NULL) {
+			jclass listClass = (*env)->GetObjectClass(env, uris);
+			jmethodID size = (*env)->GetMethodID(env, listClass, "size", "()I");
+			jmethodID get = (*env)->GetMethodID(env, listClass, "get", "(I)Ljava/lang/Object;");
+			jint count = (*env)->CallIntMethod(env, uris, size);
+			for (jint i = 0; i < count && !check_exception(env); i++) {
+				jobject uri = (*env)->CallObjectMethod(env, uris, get, i);
+				if (check_exception(env)) {
+					break;
+				}
+				list = append_uri(env, list, uri);
+				if (uri != NULL) {
+					(*env)->DeleteLocalRef(env, uri);
+				}
+			}
+			(*env)->DeleteLocalRef(env, listClass);
+			(*env)->DeleteLocalRef(env, uris);
+		}
+	}
+	(*env)->DeleteLocalRef(env, extraStream);
+
+	// Интент остается у активити: меняем действие на обычный запуск
+	jmethodID setAction = (*env)->GetMethodID(env, intentClass, "setAction", "(Ljava/lang/String;)Landroid/content/Intent;");
+	jstring mainAction = (*env)->NewStringUTF(env, "android.intent.action.MAIN");
+	jobject same = (*env)->CallObjectMethod(env, intent, setAction, mainAction);
+	check_exception(env);
+	if (same != NULL) {
+		(*env)->DeleteLocalRef(env, same);
+	}
+	(*env)->DeleteLocalRef(env, mainAction);
+	(*env)->DeleteLocalRef(env, intentClass);
+	(*env)->DeleteLocalRef(env, intent);
+	return list;
+}
+*/
+import "C"
+
+import (
+	"strings"
+	"unsafe"
+
+	"fyne.io/fyne/v2/driver"
+)
+
+// sharedURIs возвращает content:// URI файлов, отправленных в приложение через "Поделиться"
+// Каждый интент обрабатывается один раз; приложение, запущенное как обычно, получает nil
+func sharedURIs() ([]string, error) {
+	var uris []string
+	err := driver.RunNative(func(ctx any) error {
+		android, ok := ctx.(*driver.AndroidContext)
+		if !ok {
+			return nil
+		}
+		list := C.shared_uris(C.uintptr_t(android.Env), C.uintptr_t(android.Ctx))
+		if list == nil {
+			return nil
+		}
+		defer C.free(unsafe.Pointer(list))
+		uris = strings.Split(C.GoString(list), "\n")
+		return nil
+	})
+	return uris, err
+}
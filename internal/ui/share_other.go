@@ -0,0 +1,8 @@
+//go:build !android
+
+package ui
+
+// sharedURIs файлы из "Поделиться" бывают только на Android
+func sharedURIs() ([]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// sparklineCapacity количество хранимых замеров (при замере раз в 500мс - последняя минута)
+	sparklineCapacity = 120
+	// sparklineHeight высота графика
+	sparklineHeight = 32
+)
+
+// Sparkline компактный график значения во времени (скорость загрузки)
+// Новые замеры добавляются справа, самые старые выходят за окно
+type Sparkline struct {
+	widget.BaseWidget
+
+	mu     sync.RWMutex
+	values []float64
+}
+
+// NewSparkline создает пустой график
+func NewSparkline() *Sparkline {
+	s := &Sparkline{}
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+// Add добавляет замер
+func (s *Sparkline) Add(value float64) {
+	s.mu.Lock()
+	s.values = append(s.values, value)
+	if len(s.values) > sparklineCapacity {
+		s.values = s.values[len(s.values)-sparklineCapacity:]
+	}
+	s.mu.Unlock()
+
+	s.Refresh()
+}
+
+// Reset очищает график
+func (s *Sparkline) Reset() {
+	s.SetValues(nil)
+}
+
+// Values возвращает копию замеров
+func (s *Sparkline) Values() []float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]float64(nil), s.values...)
+}
+
+// SetValues заменяет замеры (например, при пересоздании вкладки)
+func (s *Sparkline) SetValues(values []float64) {
+	s.mu.Lock()
+	if len(values) > sparklineCapacity {
+		values = values[len(values)-sparklineCapacity:]
+	}
+	s.values = append([]float64(nil), values...)
+	s.mu.Unlock()
+
+	s.Refresh()
+}
+
+// CreateRenderer реализует fyne.Widget
+func (s *Sparkline) CreateRenderer() fyne.WidgetRenderer {
+	r := &sparklineRenderer{
+		sparkline:  s,
+		background: canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground)),
+	}
+	r.background.CornerRadius = theme.InputRadiusSize()
+	r.Refresh()
+	return r
+}
+
+// sparklinePoints переводит замеры в точки графика заданного размера
+// Шаг по X рассчитан на полное окно, поэтому график заполняется слева направо
+func sparklinePoints(values []float64, size fyne.Size) []fyne.Position {
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+
+	step := size.Width / float32(sparklineCapacity-1)
+	points := make([]fyne.Position, len(values))
+	for i, v := range values {
+		y := size.Height
+		if peak > 0 {
+			y -= float32(v/peak) * size.Height
+		}
+		points[i] = fyne.NewPos(float32(i)*step, y)
+	}
+	return points
+}
+
+// sparklineRenderer рисует график отрезками между соседними замерами
+type sparklineRenderer struct {
+	sparkline  *Sparkline
+	background *canvas.Rectangle
+	lines      []*canvas.Line
+	objects    []fyne.CanvasObject
+}
+
+func (r *sparklineRenderer) Layout(size fyne.Size) {
+	r.background.Resize(size)
+
+	points := sparklinePoints(r.sparkline.Values(), size)
+	for i, line := range r.lines {
+		if i+1 >= len(points) {
+			line.Hide()
+			continue
+		}
+		line.Position1 = points[i]
+		line.Position2 = points[i+1]
+		line.Show()
+	}
+}
+
+func (r *sparklineRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(theme.Padding()*4, sparklineHeight)
+}
+
+func (r *sparklineRenderer) Refresh() {
+	segments := max(len(r.sparkline.Values())-1, 0)
+	lineColor := theme.Color(theme.ColorNamePrimary)
+
+	for len(r.lines) < segments {
+		line := canvas.NewLine(lineColor)
+		line.StrokeWidth = 1.5
+		r.lines = append(r.lines, line)
+	}
+
+	r.background.FillColor = theme.Color(theme.ColorNameInputBackground)
+	r.objects = r.objects[:0]
+	r.objects = append(r.objects, r.background)
+	for _, line := range r.lines {
+		line.StrokeColor = lineColor
+		r.objects = append(r.objects, line)
+	}
+
+	r.Layout(r.sparkline.Size())
+	canvas.Refresh(r.sparkline)
+}
+
+func (r *sparklineRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *sparklineRenderer) Destroy() {}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+// TestSparklinePoints проверяет масштабирование замеров в точки графика
+func TestSparklinePoints(t *testing.T) {
+	size := fyne.NewSize(float32(sparklineCapacity-1), 10)
+
+	points := sparklinePoints([]float64{0, 50, 100}, size)
+	want := []fyne.Position{{X: 0, Y: 10}, {X: 1, Y: 5}, {X: 2, Y: 0}}
+	for i := range want {
+		if points[i] != want[i] {
+			t.Errorf("point %d = %v, want %v", i, points[i], want[i])
+		}
+	}
+
+	// Без данных о скорости линия лежит внизу
+	for _, p := range sparklinePoints([]float64{0, 0}, size) {
+		if p.Y != 10 {
+			t.Errorf("zero value at Y = %v, want 10", p.Y)
+		}
+	}
+}
+
+// TestSparklineCapacity проверяет, что старые замеры выходят за окно
+func TestSparklineCapacity(t *testing.T) {
+	test.NewTempApp(t)
+
+	s := NewSparkline()
+	for i := range sparklineCapacity + 10 {
+		s.Add(float64(i))
+	}
+
+	values := s.Values()
+	if len(values) != sparklineCapacity {
+		t.Fatalf("len(Values()) = %d, want %d", len(values), sparklineCapacity)
+	}
+	if values[0] != 10 {
+		t.Errorf("oldest value = %v, want 10", values[0])
+	}
+
+	s.Reset()
+	if len(s.Values()) != 0 {
+		t.Errorf("Values() after Reset = %v, want empty", s.Values())
+	}
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+	"multiUploader/internal/logging"
+	"multiUploader/internal/speedtest"
+)
+
+// speedTestSizesMB объемы данных для замера
+var speedTestSizesMB = []int64{10, 50, 100, 500}
+
+// showSpeedTest открывает окно замера скорости отправки на произвольный адрес
+// Помогает понять, упирается загрузка в канал провайдера интернета или в сервер файлового хостинга
+func (a *App) showSpeedTest() {
+	if a.speedTestWindow != nil {
+		a.speedTestWindow.RequestFocus()
+		return
+	}
+
+	w := a.fyneApp.NewWindow(localization.T("Speed Test"))
+	a.speedTestWindow = w
+
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("https://")
+	methodSelect := widget.NewSelect([]string{http.MethodPut, http.MethodPost}, nil)
+	methodSelect.SetSelected(http.MethodPut)
+
+	sizeOptions := make([]string, 0, len(speedTestSizesMB))
+	for _, mb := range speedTestSizesMB {
+		sizeOptions = append(sizeOptions, localization.FormatSize(mb*1024*1024))
+	}
+	sizeSelect := widget.NewSelect(sizeOptions, nil)
+	sizeSelect.SetSelectedIndex(0)
+
+	hint := widget.NewLabel(localization.T("Sends generated data to the address and measures each stage. Use any endpoint that accepts uploads, such as a presigned upload URL from a provider. Compare the result with the speed of real uploads: if the test is much faster, the provider is the bottleneck, not your connection."))
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	progressBar := widget.NewProgressBar()
+	progressBar.Hide()
+	statusLabel := widget.NewLabel("")
+	statusLabel.Wrapping = fyne.TextWrapWord
+	resultGrid := container.New(layout.NewFormLayout())
+
+	var cancel context.CancelFunc
+	var startBtn *widget.Button
+	startBtn = widget.NewButton(localization.T("Start"), func() {
+		// Повторное нажатие во время замера отменяет его
+		if cancel != nil {
+			cancel()
+			return
+		}
+
+		req := speedtest.Request{
+			URL:    strings.TrimSpace(urlEntry.Text),
+			Method: methodSelect.Selected,
+			Size:   speedTestSizesMB[sizeSelect.SelectedIndex()] * 1024 * 1024,
+		}
+		if err := req.Validate(); err != nil {
+			statusLabel.SetText(err.Error())
+			return
+		}
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		startBtn.SetText(localization.T("Cancel"))
+		progressBar.SetValue(0)
+		progressBar.Show()
+		statusLabel.SetText(localization.T("Connecting..."))
+		resultGrid.RemoveAll()
+
+		progress := make(chan speedtest.Progress, 10)
+		drained := make(chan struct{})
+		go func() {
+			defer close(drained)
+			for p := range progress {
+				fyne.Do(func() {
+					progressBar.SetValue(float64(p.Sent) / float64(p.Total))
+					statusLabel.SetText(fmt.Sprintf("%s / %s · %s",
+						localization.FormatSize(p.Sent), localization.FormatSize(p.Total), localization.FormatSpeed(p.Speed)))
+				})
+			}
+		}()
+
+		go func() {
+			result, err := speedtest.Run(ctx, nil, req, progress)
+			close(progress)
+			// Итог показывается после последнего обновления прогресса
+			<-drained
+			cancelled := ctx.Err() != nil
+
+			fyne.Do(func() {
+				cancel()
+				cancel = nil
+				startBtn.SetText(localization.T("Start"))
+				progressBar.Hide()
+
+				switch {
+				case cancelled:
+					statusLabel.SetText(localization.T("Speed test cancelled"))
+				case err != nil:
+					logging.ErrorWithError("Speed test failed", err, "method", req.Method)
+					statusLabel.SetText(fmt.Sprintf(localization.T("Speed test failed: %s"), err))
+				default:
+					statusLabel.SetText(localization.T("Speed test finished"))
+				}
+				if result.Sent > 0 {
+					for _, row := range speedTestRows(result) {
+						resultGrid.Add(widget.NewLabelWithStyle(row[0], readingAlign(), fyne.TextStyle{Bold: true}))
+						resultGrid.Add(widget.NewLabel(row[1]))
+					}
+				}
+			})
+		}()
+	})
+	startBtn.Importance = widget.HighImportance
+
+	form := widget.NewForm(
+		widget.NewFormItem(localization.T("URL"), urlEntry),
+		widget.NewFormItem(localization.T("Method"), methodSelect),
+		widget.NewFormItem(localization.T("Data size"), sizeSelect),
+	)
+
+	w.SetContent(container.NewPadded(container.NewVBox(
+		form,
+		hint,
+		startBtn,
+		progressBar,
+		statusLabel,
+		resultGrid,
+	)))
+	w.SetOnClosed(func() {
+		if cancel != nil {
+			cancel()
+		}
+		a.speedTestWindow = nil
+	})
+	w.Resize(fyne.NewSize(560, 480))
+	w.Show()
+}
+
+// speedTestRows этапы замера: название и значение
+func speedTestRows(r speedtest.Result) [][2]string {
+	ms := func(d time.Duration) string {
+		return fmt.Sprintf(localization.T("%d ms"), d.Milliseconds())
+	}
+
+	rows := [][2]string{
+		{localization.T("Upload speed:"), localization.FormatSpeed(r.Speed())},
+		{localization.T("Sent:"), fmt.Sprintf("%s, %s", localization.FormatSize(r.Sent), ms(r.Upload))},
+		{localization.T("DNS lookup:"), ms(r.DNS)},
+		{localization.T("Connection:"), ms(r.Connect)},
+		{localization.T("TLS handshake:"), ms(r.TLS)},
+		{localization.T("Server response:"), ms(r.Wait)},
+	}
+	if r.Status != 0 {
+		rows = append(rows, [2]string{localization.T("HTTP status:"), fmt.Sprint(r.Status)})
+	}
+	return rows
+}
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+const (
+	// DensityNormal стандартные отступы Fyne
+	DensityNormal = "normal"
+	// DensityCompact уменьшенные отступы для небольших окон
+	DensityCompact = "compact"
+
+	// compactScale коэффициент отступов в компактном режиме
+	compactScale = 0.5
+)
+
+// uiScales масштабы интерфейса в настройках, в процентах
+var uiScales = []int{75, 90, 100, 110, 125, 150, 175, 200}
+
+// accentPresets предустановленные акцентные цвета (название -> hex)
+// Пустой hex означает стандартный цвет темы
+var accentPresets = []struct {
+	Name string
+	Hex  string
+}{
+	{"Default", ""},
+	{"Blue", "#2979ff"},
+	{"Green", "#43a047"},
+	{"Orange", "#fb8c00"},
+	{"Red", "#e53935"},
+	{"Purple", "#8e24aa"},
+	{"Teal", "#00897b"},
+}
+
+// appTheme тема приложения поверх стандартной темы Fyne
+// Варианты light/dark фиксируются, в режиме auto вариант берется из ОС при каждом запросе цвета,
+// поэтому смена темы системы применяется сразу без перезапуска
+type appTheme struct {
+	// variant принудительный вариант темы (nil - следовать за ОС)
+	variant *fyne.ThemeVariant
+
+	// accent акцентный цвет (nil - стандартный)
+	accent color.Color
+
+	// compact уменьшенные отступы
+	compact bool
+
+	// scale множитель всех размеров темы: текста, отступов, значков
+	// Действует поверх FYNE_SCALE и масштаба системы
+	scale float32
+}
+
+// newAppTheme создает тему из настроек; uiScale - масштаб интерфейса в процентах
+func newAppTheme(themeCode, accentHex, density string, uiScale int) *appTheme {
+	t := &appTheme{compact: density == DensityCompact, scale: 1}
+	if uiScale > 0 {
+		t.scale = float32(uiScale) / 100
+	}
+
+	switch themeCode {
+	case "dark":
+		v := theme.VariantDark
+		t.variant = &v
+	case "light":
+		v := theme.VariantLight
+		t.variant = &v
+	}
+
+	if c, err := parseHexColor(accentHex); err == nil {
+		t.accent = c
+	}
+
+	return t
+}
+
+func (t *appTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if t.variant != nil {
+		variant = *t.variant
+	}
+
+	if t.accent != nil {
+		switch name {
+		case theme.ColorNamePrimary, theme.ColorNameHyperlink:
+			return t.accent
+		case theme.ColorNameFocus:
+			return withAlpha(t.accent, 0x7f)
+		case theme.ColorNameSelection:
+			return withAlpha(t.accent, 0x3f)
+		case theme.ColorNameForegroundOnPrimary:
+			// Контрастный текст на акцентном фоне
+			if isLight(t.accent) {
+				return color.Black
+			}
+			return color.White
+		}
+	}
+
+	return theme.DefaultTheme().Color(name, variant)
+}
+
+func (t *appTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return theme.DefaultTheme().Font(style)
+}
+
+func (t *appTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return theme.DefaultTheme().Icon(name)
+}
+
+func (t *appTheme) Size(name fyne.ThemeSizeName) float32 {
+	size := theme.DefaultTheme().Size(name) * t.scale
+
+	if t.compact {
+		switch name {
+		case theme.SizeNamePadding, theme.SizeNameInnerPadding, theme.SizeNameLineSpacing:
+			return size * compactScale
+		}
+	}
+
+	return size
+}
+
+// parseHexColor разбирает цвет в формате "#RRGGBB"
+func parseHexColor(hex string) (color.Color, error) {
+	var r, g, b uint8
+	if len(hex) != 7 || hex[0] != '#' {
+		return nil, fmt.Errorf("invalid color %q", hex)
+	}
+	if _, err := fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return nil, fmt.Errorf("invalid color %q: %w", hex, err)
+	}
+	return color.NRGBA{R: r, G: g, B: b, A: 0xff}, nil
+}
+
+// formatHexColor форматирует цвет в "#RRGGBB"
+func formatHexColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+}
+
+// withAlpha возвращает цвет с заданной прозрачностью
+func withAlpha(c color.Color, alpha uint8) color.Color {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	n.A = alpha
+	return n
+}
+
+// isLight определяет светлый цвет по относительной яркости
+func isLight(c color.Color) bool {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	luminance := 0.299*float64(n.R) + 0.587*float64(n.G) + 0.114*float64(n.B)
+	return luminance > 160
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/theme"
+)
+
+// TestAppThemeScale проверяет, что масштаб интерфейса умножает размеры темы,
+// а компактный режим уменьшает отступы уже после масштабирования
+func TestAppThemeScale(t *testing.T) {
+	text := theme.DefaultTheme().Size(theme.SizeNameText)
+	padding := theme.DefaultTheme().Size(theme.SizeNamePadding)
+
+	tests := []struct {
+		scale       int
+		density     string
+		wantText    float32
+		wantPadding float32
+	}{
+		{100, DensityNormal, text, padding},
+		{0, DensityNormal, text, padding},
+		{150, DensityNormal, text * 1.5, padding * 1.5},
+		{200, DensityCompact, text * 2, padding * 2 * compactScale},
+	}
+	for _, tt := range tests {
+		th := newAppTheme("auto", "", tt.density, tt.scale)
+		if got := th.Size(theme.SizeNameText); got != tt.wantText {
+			t.Errorf("scale %d: text size = %v, want %v", tt.scale, got, tt.wantText)
+		}
+		if got := th.Size(theme.SizeNamePadding); got != tt.wantPadding {
+			t.Errorf("scale %d, %s: padding = %v, want %v", tt.scale, tt.density, got, tt.wantPadding)
+		}
+	}
+}
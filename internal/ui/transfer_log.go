@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/history"
+	"multiUploader/internal/localization"
+)
+
+// showTransferLog показывает журнал передачи записи истории с временем от начала загрузки
+func showTransferLog(window fyne.Window, entry history.Entry) {
+	text := transferLogText(entry.Log)
+
+	output := widget.NewMultiLineEntry()
+	output.TextStyle = fyne.TextStyle{Monospace: true}
+	output.Wrapping = fyne.TextWrapOff
+	output.SetText(text)
+	output.SetMinRowsVisible(14)
+
+	title := fmt.Sprintf("%s · %s", localization.T("Transfer log"), entry.FileName)
+	d := dialog.NewCustom(title, localization.T("Close"), output, window)
+	copyBtn := widget.NewButtonWithIcon(localization.T("Copy"), theme.ContentCopyIcon(), func() {
+		window.Clipboard().SetContent(text)
+	})
+	d.SetButtons([]fyne.CanvasObject{copyBtn, widget.NewButton(localization.T("Close"), d.Hide)})
+	resizeDialog(d, fyne.NewSize(700, 450))
+	d.Show()
+}
+
+// transferLogText строки журнала вида "+1.250s  событие"
+func transferLogText(events []history.LogEvent) string {
+	if len(events) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	start := events[0].Time
+	for _, e := range events {
+		fmt.Fprintf(&b, "+%8.3fs  %s\n", e.Time.Sub(start).Seconds(), e.Message)
+	}
+	return b.String()
+}
@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"math"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"multiUploader/internal/localization"
+	"multiUploader/internal/viewmodel"
+)
+
+// uploadCard карточка одной загрузки: прогресс, отмена и итог
+type uploadCard struct {
+	tab *UploadTab
+	id  int
+
+	card        *widget.Card
+	progressBar *widget.ProgressBar
+	speedGraph  *Sparkline
+	statusLabel *widget.Label
+	speedLabel  *widget.Label
+	etaLabel    *widget.Label
+	details     *chunkDetails
+	outcomeBox  *fyne.Container
+	cancelBtn   *widget.Button
+	dismissBtn  *widget.Button
+	// handle и priorityCheck видны, пока загрузка ждет в очереди
+	handle        *dragHandle
+	priorityCheck *widget.Check
+	// icon значок провайдера загрузки
+	icon *widget.Icon
+
+	// shownSamples сколько замеров скорости уже на графике (-1 - график нужно перерисовать)
+	shownSamples int
+	// shownOutcome итог уже показан в карточке
+	shownOutcome bool
+}
+
+// newUploadCard создает карточку загрузки
+func newUploadCard(tab *UploadTab, id int) *uploadCard {
+	c := &uploadCard{tab: tab, id: id, shownSamples: -1}
+
+	c.progressBar = widget.NewProgressBar()
+	c.speedGraph = NewSparkline()
+	c.statusLabel = widget.NewLabel("")
+	c.speedLabel = widget.NewLabel("")
+	c.etaLabel = widget.NewLabel("")
+	c.details = newChunkDetails()
+	c.outcomeBox = container.NewVBox()
+
+	c.cancelBtn = widget.NewButtonWithIcon(localization.T("Cancel"), theme.CancelIcon(), func() {
+		tab.vm.Cancel(id)
+	})
+	c.dismissBtn = widget.NewButtonWithIcon("", theme.WindowCloseIcon(), func() {
+		tab.vm.Dismiss(id)
+	})
+	c.dismissBtn.Importance = widget.LowImportance
+	c.dismissBtn.Hide()
+
+	c.handle = newDragHandle(c.onDrop)
+	c.handle.Hide()
+	c.icon = widget.NewIcon(nil)
+	c.priorityCheck = widget.NewCheck(localization.T("High priority"), func(high bool) {
+		tab.vm.SetHighPriority(id, high)
+	})
+	c.priorityCheck.Hide()
+
+	content := container.NewVBox(
+		c.progressBar,
+		c.speedGraph,
+		c.statusLabel,
+		c.priorityCheck,
+		newHRow(c.speedLabel, c.etaLabel),
+		c.details.accordion,
+		c.outcomeBox,
+	)
+	alignLabels(c.statusLabel)
+	c.card = widget.NewCard("", "", newRow(container.NewVBox(c.icon, c.handle),
+		container.NewVBox(c.cancelBtn, c.dismissBtn), content))
+	return c
+}
+
+// onDrop передвигает загрузку в очереди на столько карточек, на сколько ее перетащили
+func (c *uploadCard) onDrop(dy float32) {
+	step := c.card.Size().Height + theme.Padding()
+	if step <= 0 {
+		return
+	}
+	if offset := int(math.Round(float64(dy / step))); offset != 0 {
+		c.tab.vm.Move(c.id, offset)
+	}
+}
+
+// render отображает состояние загрузки (вызывается из главного потока)
+func (c *uploadCard) render(job viewmodel.JobState) {
+	c.card.SetTitle(job.FileName)
+	c.card.SetSubTitle(job.Provider)
+	if c.icon.Resource == nil {
+		c.icon.SetResource(providerIcon(job.Provider))
+	}
+
+	if job.SampleCount != c.shownSamples {
+		c.speedGraph.SetValues(job.SpeedSamples)
+		c.shownSamples = job.SampleCount
+	}
+
+	queued := job.Active && job.Phase == viewmodel.PhaseQueued
+	setVisible(c.handle, queued)
+	setVisible(c.priorityCheck, queued)
+	setVisible(c.progressBar, !queued)
+	if queued {
+		c.priorityCheck.SetChecked(job.HighPriority)
+	}
+
+	if job.Active {
+		c.progressBar.SetValue(job.Progress)
+		status, speed, eta := progressText(job)
+		c.statusLabel.SetText(status)
+		c.speedLabel.SetText(speed)
+		c.etaLabel.SetText(eta)
+		c.details.render(job.Parts)
+		// Remote upload идет без прогресса - график не нужен
+		setVisible(c.speedGraph, job.Phase != viewmodel.PhaseRemote && !queued)
+		return
+	}
+
+	// Загрузка завершена - вместо прогресса показываем итог
+	c.cancelBtn.Hide()
+	c.dismissBtn.Show()
+	for _, item := range []fyne.CanvasObject{c.progressBar, c.speedGraph, c.statusLabel, c.speedLabel, c.etaLabel, c.priorityCheck} {
+		item.Hide()
+	}
+	// После ошибки список частей остается - по нему видно, какая часть не прошла
+	if job.Outcome != nil && job.Outcome.Err != nil {
+		c.details.render(job.Parts)
+	} else {
+		c.details.accordion.Hide()
+	}
+	if job.Outcome != nil && !c.shownOutcome {
+		c.shownOutcome = true
+		c.showOutcome(*job.Outcome)
+	}
+}
+
+// newKeyRetry поле исправленного API ключа: ключ сохраняется в настройки провайдера,
+// и загрузка сразу повторяется без перехода в настройки
+func (c *uploadCard) newKeyRetry(providerName string) fyne.CanvasObject {
+	entry := widget.NewPasswordEntry()
+	entry.SetPlaceHolder(localization.T("Enter API key"))
+	entry.SetText(c.tab.app.Config().GetProviderAPIKey(providerName))
+	errorLabel := widget.NewLabel("")
+	errorLabel.Importance = widget.DangerImportance
+	errorLabel.Wrapping = fyne.TextWrapWord
+	alignLabels(errorLabel)
+	errorLabel.Hide()
+
+	retry := func() {
+		if err := c.tab.retryWithKey(c.id, providerName, strings.TrimSpace(entry.Text)); err != nil {
+			errorLabel.SetText(MakeFriendly(err).Message)
+			errorLabel.Show()
+		}
+	}
+	entry.OnSubmitted = func(string) { retry() }
+	retryBtn := widget.NewButtonWithIcon(localization.T("Save and retry"), theme.ViewRefreshIcon(), retry)
+	retryBtn.Importance = widget.HighImportance
+
+	return container.NewVBox(newRow(nil, retryBtn, entry), errorLabel)
+}
+
+// newUploadAnyway создает кнопку загрузки без проверки на вирусы
+// Загрузка повторяется на месте карточки; найденная угроза остается в журнале передачи
+func (c *uploadCard) newUploadAnyway(providerName string) fyne.CanvasObject {
+	button := widget.NewButtonWithIcon(localization.T("Upload anyway"), theme.WarningIcon(), nil)
+	button.Importance = widget.DangerImportance
+	button.OnTapped = func() {
+		window := c.tab.app.MainWindow()
+		dialog.ShowConfirm(localization.T("Upload anyway"),
+			localization.T("The file will be uploaded without a virus check. Share the link only if you are sure the file is safe."),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := c.tab.retryWithoutScan(c.id, providerName); err != nil {
+					c.tab.showFriendlyError(err)
+				}
+			}, window)
+	}
+	return newHRow(button)
+}
+
+// showOutcome показывает итог загрузки: ссылку и проверку целостности или ошибку
+func (c *uploadCard) showOutcome(outcome viewmodel.Completion) {
+	window := c.tab.app.MainWindow()
+
+	if outcome.Err != nil {
+		friendly := MakeFriendly(outcome.Err)
+		label := widget.NewLabel(friendly.Title)
+		label.Importance = widget.DangerImportance
+		alignLabels(label)
+		if classifyError(outcome.Err) == ErrorTypeCancelled {
+			label.Importance = widget.LowImportance
+		}
+		details := widget.NewButton(localization.T("Details"), func() {
+			c.tab.showFriendlyError(outcome.Err)
+		})
+		c.outcomeBox.Add(newRow(nil, details, label))
+		// Сервер отклонил ключ - его можно исправить прямо в карточке
+		if classifyError(outcome.Err) == ErrorTypeAuth {
+			c.outcomeBox.Add(c.newKeyRetry(outcome.Provider))
+		}
+		// Проверку на вирусы можно обойти, если пользователь уверен в файле
+		if scanStopped(outcome.Err) {
+			c.outcomeBox.Add(c.newUploadAnyway(outcome.Provider))
+		}
+		// Части разрезанного файла, загруженные до ошибки, не теряются
+		if len(outcome.Parts) > 0 {
+			c.outcomeBox.Add(newHRow(newUploadedPartsButton(window, outcome.Parts)))
+		}
+		return
+	}
+	if outcome.Result == nil {
+		return
+	}
+
+	title := widget.NewLabelWithStyle(localization.T("Upload Complete")+"!", readingAlign(), fyne.TextStyle{Bold: true})
+	c.outcomeBox.Add(title)
+	if outcome.Verification != nil {
+		c.outcomeBox.Add(newIntegrityLabel(outcome.Verification.Status, outcome.Verification.Detail))
+	}
+	if outcome.Result.URL != "" {
+		c.outcomeBox.Add(newURLRow(window, localization.T("URL"), outcome.Result.URL))
+	}
+	showBtn := widget.NewButton(localization.T("Show result"), func() {
+		c.tab.resultDialog(outcome).Show()
+	})
+	c.outcomeBox.Add(newHRow(append([]fyne.CanvasObject{showBtn}, c.tab.shareButtons(outcome)...)...))
+}
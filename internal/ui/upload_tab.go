@@ -3,154 +3,249 @@ package ui
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
-	"sync"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
-	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
+	"multiUploader/internal/config"
+	"multiUploader/internal/history"
+	"multiUploader/internal/httpclient"
 	"multiUploader/internal/localization"
 	"multiUploader/internal/logging"
+	"multiUploader/internal/notify"
+	"multiUploader/internal/power"
 	"multiUploader/internal/providers"
+	"multiUploader/internal/queue"
+	"multiUploader/internal/sound"
+	"multiUploader/internal/upload"
+	"multiUploader/internal/viewmodel"
+)
+
+const (
+	// folderListTimeout ограничение на получение списка папок аккаунта
+	folderListTimeout = 30 * time.Second
 )
 
 // UploadTab представляет вкладку загрузки файлов
+// Состояние и логика загрузок находятся в viewmodel.Upload, вкладка только отображает их
 type UploadTab struct {
 	app *App
+	vm  *viewmodel.Upload
 
 	// UI элементы
-	providerSelect *widget.Select
-	filePathLabel  *widget.Label
-	selectFileBtn  *widget.Button
-	uploadBtn      *widget.Button
-	progressBar    *widget.ProgressBar
-	speedLabel     *widget.Label
-	uploadedLabel  *widget.Label
-	etaLabel       *widget.Label
-	resultLabel    *widget.Label
-
-	// Data bindings (потокобезопасные)
-	progressBinding  binding.Float
-	uploadedBinding  binding.String
-	speedBinding     binding.String
-	etaBinding       binding.String
-	resultBinding    binding.String
-	uploadBtnBinding binding.String
-
-	// Состояние
-	selectedFile     fyne.URI
-	selectedProvider string
-	isUploading      bool
-	cancelUpload     context.CancelFunc
-
-	// Прогресс (потокобезопасный доступ)
-	progressMutex  sync.RWMutex
-	latestProgress *providers.UploadProgress
-	totalSize      int64
-	stopUIUpdate   chan struct{}
-	uploadResult   chan *uploadCompletion
-}
-
-// uploadCompletion содержит результат загрузки
-type uploadCompletion struct {
-	result *providers.UploadResult
-	err    error
+	providerSelect *providerPicker
+	providerStatus *widget.Icon
+	// mirrorsBtn выбор провайдеров, в которые файлы загружаются вместе с выбранным
+	mirrorsBtn    *widget.Button
+	filePathLabel *widget.Label
+	filePreview   *filePreview
+	selectFileBtn *widget.Button
+	selectURLBtn  *widget.Button
+	albumCheck    *widget.Check
+
+	// fileFilterSelect группа файлов, которые показывает диалог выбора файла
+	fileFilterSelect *widget.Select
+	// recentBtn открывает меню недавно загруженных файлов
+	recentBtn *widget.Button
+
+	folderRow        *fyne.Container
+	folderSelect     *widget.Select
+	folderRefreshBtn *widget.Button
+	uploadBtn        *widget.Button
+	clearBtn         *widget.Button
+	resultsBtn       *widget.Button
+	shutdownCheck    *widget.Check
+	dryRunBanner     *widget.Label
+
+	// queueBar кнопки всей очереди, пока есть незавершенные загрузки
+	queueBar     *fyne.Container
+	pauseAllBtn  *widget.Button
+	resumeAllBtn *widget.Button
+	cancelAllBtn *widget.Button
+
+	// totalBox общий прогресс очереди, пока идет больше одной загрузки
+	totalBox   *fyne.Container
+	totalBar   *widget.ProgressBar
+	totalLabel *widget.Label
+
+	// Карточки загрузок в порядке очереди
+	jobsBox *fyne.Container
+	cards   map[int]*uploadCard
+
+	// Состояние отображения
+	folders []providers.Folder
+
+	// inhibitor запрет сна, пока идут загрузки
+	inhibitor     *power.Inhibitor
+	inhibitFailed bool
+	// wasActive загрузки шли при прошлом обновлении
+	wasActive bool
+	// shutdownWhenDone выключить компьютер, когда очередь опустеет (только до выхода из приложения)
+	shutdownWhenDone bool
 }
 
 // NewUploadTab создает новую вкладку загрузки
 func NewUploadTab(app *App) *UploadTab {
 	tab := &UploadTab{app: app,
-		progressBinding:  binding.NewFloat(),
-		uploadedBinding:  binding.NewString(),
-		speedBinding:     binding.NewString(),
-		etaBinding:       binding.NewString(),
-		resultBinding:    binding.NewString(),
-		uploadBtnBinding: binding.NewString(),
+		vm:        viewmodel.NewUpload(app.RateLimiter(), app.History(), app.Queue()),
+		inhibitor: power.NewInhibitor("Uploading files"),
 	}
+	tab.applySettings()
+	tab.vm.SetBackup(app.backupProvider)
+	tab.vm.SetURIOpener(openURI)
 
-	// Устанавливаем начальные значения
-	tab.uploadedBinding.Set("")
-	tab.speedBinding.Set("")
-	tab.etaBinding.Set("")
-	tab.resultBinding.Set("")
-	tab.uploadBtnBinding.Set(localization.T("Start Upload"))
+	// Отображаем изменения модели в главном потоке
+	go func() {
+		for s := range tab.vm.Updates() {
+			fyne.Do(func() { tab.render(s) })
+		}
+	}()
+	go func() {
+		for c := range tab.vm.Results() {
+			tab.onCompleted(c)
+		}
+	}()
 
 	return tab
 }
 
 // Build создает UI вкладки загрузки
+// Может вызываться повторно (смена языка) - состояние берется из модели
 func (t *UploadTab) Build() fyne.CanvasObject {
 	// Выбор провайдера
 	providerLabel := widget.NewLabel(localization.T("Select Providers"))
-	t.providerSelect = widget.NewSelect([]string{}, func(selected string) {
-		t.selectedProvider = selected
-		t.updateUploadButton()
+	t.providerSelect = newProviderPicker(func(selected string) {
+		t.vm.SelectProvider(selected)
+		t.rememberUIState(func(state *config.UIState) { state.LastProvider = selected })
+		t.loadFolders()
+		t.updateMirrorsButton()
+		t.updateAlbumCheck()
+		t.updateProviderStatus()
 	})
+	// Значок доступности провайдера по последней проверке соединения
+	t.providerStatus = widget.NewIcon(nil)
+	t.providerStatus.Hide()
+
+	// Зеркала: файлы загружаются еще и в эти провайдеры, а читаются с диска один раз
+	t.mirrorsBtn = widget.NewButtonWithIcon("", theme.ContentAddIcon(), t.showMirrorsMenu)
+
+	// Папка назначения (только для провайдеров с папками)
+	t.folderSelect = widget.NewSelect(nil, t.onFolderSelected)
+	t.folderRefreshBtn = widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), t.loadFolders)
+	t.folderRow = newRow(widget.NewLabel(localization.T("Folder:")), t.folderRefreshBtn, t.folderSelect)
+	t.folderRow.Hide()
 
 	// Кнопка выбора файла
 	t.filePathLabel = widget.NewLabel(localization.T("No file selected"))
 	t.selectFileBtn = widget.NewButton(localization.T("Select File"), t.onSelectFile)
+	t.fileFilterSelect = t.newFileFilterSelect()
+	t.recentBtn = widget.NewButtonWithIcon("", theme.HistoryIcon(), t.showRecentFiles)
+	t.selectURLBtn = widget.NewButton(localization.T("From URL..."), t.onSelectURL)
+	t.filePreview = newFilePreview()
+
+	// Альбом: выбранные файлы загружаются в одну новую папку с общей ссылкой
+	t.albumCheck = widget.NewCheck(localization.T("Group as album"), func(checked bool) {
+		t.vm.SetAlbum(checked)
+	})
+	t.albumCheck.SetChecked(t.vm.State().Album)
+	t.albumCheck.Hide()
 
-	// Progress bar и информация о прогрессе
-	t.progressBar = widget.NewProgressBarWithData(t.progressBinding)
-	t.progressBar.Hide()
-
-	// Используем data binding для потокобезопасного обновления
-	t.uploadedLabel = widget.NewLabelWithData(t.uploadedBinding)
-	t.uploadedLabel.Hide()
+	// Кнопка загрузки: каждая загрузка получает свою карточку, можно запускать несколько
+	t.uploadBtn = widget.NewButtonWithIcon(localization.T("Start Upload"), theme.UploadIcon(), t.onUpload)
+	t.uploadBtn.Importance = widget.HighImportance
+	t.uploadBtn.Disable()
 
-	t.speedLabel = widget.NewLabelWithData(t.speedBinding)
-	t.speedLabel.Hide()
+	t.clearBtn = widget.NewButton(localization.T("Clear finished"), t.vm.ClearFinished)
+	t.clearBtn.Hide()
 
-	t.etaLabel = widget.NewLabelWithData(t.etaBinding)
-	t.etaLabel.Hide()
+	// Итог завершенных загрузок по шаблону из настроек
+	t.resultsBtn = widget.NewButtonWithIcon(localization.T("Results…"), theme.ContentCopyIcon(), t.onShowResults)
+	t.resultsBtn.Hide()
 
-	// Кнопка загрузки с binding
-	t.uploadBtn = widget.NewButtonWithIcon("", nil, t.onUpload)
-	t.uploadBtn.Disable()
+	// Выключение компьютера после очереди (для загрузок на ночь)
+	t.shutdownCheck = widget.NewCheck(localization.T("Shut down when done"), func(checked bool) {
+		t.shutdownWhenDone = checked
+	})
+	t.shutdownCheck.SetChecked(t.shutdownWhenDone)
+	// Телефон приложение не выключает
+	if isMobile() {
+		t.shutdownCheck.Hide()
+	}
 
-	// Результат загрузки с binding
-	t.resultLabel = widget.NewLabelWithData(t.resultBinding)
-	t.resultLabel.Wrapping = fyne.TextWrapWord
+	// Пауза, продолжение и отмена всех загрузок
+	t.pauseAllBtn = widget.NewButtonWithIcon(localization.T("Pause all"), theme.MediaPauseIcon(), t.vm.PauseAll)
+	t.resumeAllBtn = widget.NewButtonWithIcon(localization.T("Resume all"), theme.MediaPlayIcon(), t.vm.ResumeAll)
+	t.cancelAllBtn = widget.NewButtonWithIcon(localization.T("Cancel all"), theme.CancelIcon(), t.onCancelAll)
+	t.queueBar = newHRow(t.pauseAllBtn, t.resumeAllBtn, t.cancelAllBtn)
+	t.queueBar.Hide()
+
+	// Предупреждение о режиме dry run
+	t.dryRunBanner = widget.NewLabel(localization.T("Dry run: files are not sent, providers answer with simulated responses"))
+	t.dryRunBanner.Importance = widget.WarningImportance
+	t.dryRunBanner.Wrapping = fyne.TextWrapWord
+	t.dryRunBanner.Hidden = !httpclient.DryRunEnabled()
+
+	// Общий прогресс и ETA всей очереди
+	t.totalBar = widget.NewProgressBar()
+	t.totalLabel = widget.NewLabel("")
+	alignLabels(t.totalLabel)
+	t.totalBox = container.NewVBox(t.totalLabel, t.totalBar)
+	t.totalBox.Hide()
+
+	// Карточки пересоздаются из модели
+	t.jobsBox = container.NewVBox()
+	t.cards = make(map[int]*uploadCard)
 
 	// Обновляем список провайдеров
 	t.updateProviderList()
 
-	// Обновляем текст кнопки из binding при старте
-	go func() {
-		text, _ := t.uploadBtnBinding.Get()
-		t.uploadBtn.SetText(text)
-	}()
+	// Восстанавливаем состояние после пересоздания вкладки
+	state := t.vm.State()
+	switch {
+	case len(state.FilePaths) > 0:
+		t.showSelectedFiles(state.FilePaths[len(state.FilePaths)-1])
+	case len(state.URIs) > 0:
+		t.setSourceURIs(state.URIs)
+	case state.RemoteURL != "":
+		t.setRemoteURL(state.RemoteURL)
+	}
+	t.render(state)
 
 	// Компоновка UI
-	providerRow := container.NewBorder(nil, nil, providerLabel, nil, t.providerSelect)
-	fileRow := container.NewBorder(nil, nil, nil, t.selectFileBtn, t.filePathLabel)
-
-	progressGroup := container.NewVBox(
-		t.progressBar,
-		t.uploadedLabel,
-		t.speedLabel,
-		t.etaLabel,
-	)
+	providerRow := newRow(providerLabel, newHRow(t.providerStatus, t.mirrorsBtn), t.providerSelect.button)
+	fileRow := newRow(nil, newHRow(t.albumCheck, t.fileFilterSelect, t.selectFileBtn, t.recentBtn, t.selectURLBtn), t.filePathLabel)
 
-	content := container.NewVBox(
+	form := container.NewVBox(
 		widget.NewLabel(localization.T("Upload")),
 		widget.NewSeparator(),
+		t.dryRunBanner,
 		providerRow,
+		t.folderRow,
 		fileRow,
+		t.filePreview.box,
+		newRow(nil, newHRow(t.shutdownCheck, t.resultsBtn, t.clearBtn), t.uploadBtn),
+		t.queueBar,
+		t.totalBox,
 		widget.NewSeparator(),
-		progressGroup,
-		t.uploadBtn,
-		widget.NewSeparator(),
-		t.resultLabel,
 	)
 
-	return container.NewPadded(content)
+	return container.NewPadded(container.NewBorder(form, nil, nil, nil, container.NewVScroll(t.jobsBox)))
+}
+
+// selectedProvider возвращает выбранного провайдера
+func (t *UploadTab) selectedProvider() string {
+	return t.vm.State().Provider
 }
 
 // updateProviderList обновляет список доступных провайдеров
@@ -162,14 +257,119 @@ func (t *UploadTab) updateProviderList() {
 		providerNames = append(providerNames, p.Name())
 	}
 
-	t.providerSelect.Options = providerNames
+	t.providerSelect.SetOptions(providerNames)
 
-	if len(providerNames) > 0 && t.selectedProvider == "" {
-		t.providerSelect.SetSelected(providerNames[0])
-		t.selectedProvider = providerNames[0]
+	// Отключенные провайдеры больше не зеркала
+	mirrors := slices.DeleteFunc(slices.Clone(t.vm.State().Mirrors), func(name string) bool {
+		return !slices.Contains(providerNames, name)
+	})
+	t.vm.SelectMirrors(mirrors)
+	t.updateMirrorsButton()
+
+	selected := t.selectedProvider()
+	if len(providerNames) > 0 && selected == "" {
+		// При запуске выбирается провайдер прошлого запуска, если он еще включен
+		initial := providerNames[0]
+		if last := t.app.Config().GetUIState().LastProvider; slices.Contains(providerNames, last) {
+			initial = last
+		}
+		t.vm.SelectProvider(initial)
+		t.providerSelect.SetSelected(initial)
+	} else if selected != "" {
+		t.providerSelect.SetSelected(selected)
 	}
 }
 
+// loadFolders загружает папки аккаунта выбранного провайдера (вызывается из главного потока)
+// Для провайдеров без папок строка выбора скрывается
+func (t *UploadTab) loadFolders() {
+	name := t.selectedProvider()
+	provider, ok := t.app.GetProvider(name)
+	lister, hasFolders := provider.(providers.FolderProvider)
+	if !ok || !hasFolders {
+		t.folderRow.Hide()
+		return
+	}
+	t.folderRow.Show()
+
+	// Без API ключа запрашивать папки бессмысленно
+	if provider.ValidateAPIKey(t.app.Config().GetProviderAPIKey(name)) != nil {
+		t.setFolders(nil, localization.T("Root folder"))
+		return
+	}
+
+	t.folderSelect.Disable()
+	t.folderRefreshBtn.Disable()
+	t.folderSelect.PlaceHolder = localization.T("Loading folders…")
+	t.folderSelect.ClearSelected()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(httpclient.WithProvider(context.Background(), name), folderListTimeout)
+		defer cancel()
+
+		folders, err := lister.ListFolders(ctx)
+		if err != nil {
+			logging.ErrorWithError("Failed to list folders", err, "provider", name)
+		}
+
+		fyne.Do(func() {
+			// Пока шел запрос, пользователь мог выбрать другого провайдера
+			if t.selectedProvider() != name {
+				return
+			}
+			t.folderSelect.Enable()
+			t.folderRefreshBtn.Enable()
+			if err != nil {
+				t.setFolders(nil, localization.T("Could not load folders"))
+				return
+			}
+			t.setFolders(folders, localization.T("Root folder"))
+		})
+	}()
+}
+
+// setFolders заполняет список папок и выбирает сохраненную в настройках
+func (t *UploadTab) setFolders(folders []providers.Folder, placeholder string) {
+	t.folders = folders
+
+	options := []string{localization.T("Root folder")}
+	selected := options[0]
+	savedID := t.app.Config().GetProviderConfig(t.selectedProvider()).FolderID
+	for _, folder := range folders {
+		options = append(options, folder.Path)
+		if folder.ID == savedID {
+			selected = folder.Path
+		}
+	}
+
+	// Программный выбор не должен перезаписывать настройки
+	t.folderSelect.OnChanged = nil
+	t.folderSelect.Options = options
+	t.folderSelect.PlaceHolder = placeholder
+	if len(folders) > 0 || savedID == "" {
+		t.folderSelect.SetSelected(selected)
+	} else {
+		t.folderSelect.ClearSelected()
+	}
+	t.folderSelect.OnChanged = t.onFolderSelected
+}
+
+// onFolderSelected запоминает выбранную папку в настройках провайдера
+func (t *UploadTab) onFolderSelected(selected string) {
+	folderID := ""
+	for _, folder := range t.folders {
+		if folder.Path == selected {
+			folderID = folder.ID
+			break
+		}
+	}
+
+	name := t.selectedProvider()
+	cfg := t.app.Config().GetProviderConfig(name)
+	cfg.FolderID = folderID
+	t.app.Config().SetProviderConfig(name, cfg)
+}
+
 // onSelectFile обработчик выбора файла
 func (t *UploadTab) onSelectFile() {
 	// Создаем file dialog вручную, чтобы установить custom размер
@@ -183,336 +383,843 @@ func (t *UploadTab) onSelectFile() {
 		}
 		defer reader.Close()
 
-		t.selectedFile = reader.URI()
-
-		// Получаем размер файла
-		fileInfo, err := os.Stat(reader.URI().Path())
-		if err != nil {
-			t.filePathLabel.SetText(fmt.Sprintf("Selected: %s", reader.URI().Name()))
-		} else {
-			sizeStr := providers.FormatSize(fileInfo.Size())
-			t.filePathLabel.SetText(fmt.Sprintf("Selected: %s (%s)", reader.URI().Name(), sizeStr))
+		// MTP устройства и хранилище на мобильных не открываются как локальный файл
+		if reader.URI().Scheme() != "file" {
+			t.setSourceURIs([]string{reader.URI().String()})
+			return
 		}
-
-		t.updateUploadButton()
+		path := reader.URI().Path()
+		t.rememberUIState(func(state *config.UIState) { state.LastDir = filepath.Dir(path) })
+		t.pickFile(path)
 	}, t.app.MainWindow())
+	fileDialog.SetFilter(t.selectedFileFilter())
+
+	// Диалог открывается в папке, из которой файл выбирался в прошлый раз
+	if dir := t.app.Config().GetUIState().LastDir; dir != "" {
+		if uri, err := storage.ListerForURI(storage.NewFileURI(dir)); err == nil {
+			fileDialog.SetLocation(uri)
+		}
+	}
 
 	// Устанавливаем больший размер для удобства
-	fileDialog.Resize(fyne.NewSize(800, 600))
+	resizeDialog(fileDialog, fyne.NewSize(800, 600))
 	fileDialog.Show()
 }
 
-// onUpload обработчик загрузки файла
-func (t *UploadTab) onUpload() {
-	if t.selectedFile == nil || t.selectedProvider == "" {
+// pickFile выбирает файл из диалога или списка недавних
+// В альбом файлы добавляются по одному, иначе выбор заменяется
+func (t *UploadTab) pickFile(path string) {
+	if t.albumCheck.Visible() && t.albumCheck.Checked {
+		t.vm.AddFile(path)
+		t.showSelectedFiles(path)
 		return
 	}
+	t.setSelectedFile(path)
+}
+
+// rememberUIState сохраняет изменение состояния интерфейса для следующего запуска
+func (t *UploadTab) rememberUIState(update func(state *config.UIState)) {
+	state := t.app.Config().GetUIState()
+	update(&state)
+	t.app.Config().SetUIState(state)
+}
 
-	if t.isUploading {
-		// Отмена загрузки
-		if t.cancelUpload != nil {
-			t.cancelUpload()
+// setSelectedFile запоминает выбранный файл и показывает его имя, размер и предпросмотр
+func (t *UploadTab) setSelectedFile(path string) {
+	t.vm.SelectFile(path)
+	t.showSelectedFiles(path)
+}
+
+// showSelectedFiles показывает выбранные файлы и предпросмотр последнего добавленного
+func (t *UploadTab) showSelectedFiles(last string) {
+	paths := t.vm.State().FilePaths
+	importance := widget.MediumImportance
+	if len(paths) > 1 {
+		var total int64
+		for _, path := range paths {
+			if info, err := os.Stat(path); err == nil {
+				total += info.Size()
+			}
+		}
+		t.filePathLabel.SetText(fmt.Sprintf(localization.T("Selected: %s"),
+			fmt.Sprintf(localization.TN("%d files (%s)", len(paths)), len(paths), localization.FormatSize(total))))
+	} else {
+		// Получаем размер файла
+		name := filepath.Base(last)
+		fileInfo, err := os.Stat(last)
+		if err != nil {
+			t.filePathLabel.SetText(fmt.Sprintf(localization.T("Selected: %s"), name))
+		} else {
+			sizeStr := localization.FormatSize(fileInfo.Size())
+			text := fmt.Sprintf(localization.T("Selected: %s"), fmt.Sprintf("%s (%s)", name, sizeStr))
+			// Файл больше 4 ГБ на FAT32 обрезается при копировании - загружать его, скорее всего, бессмысленно
+			if upload.MaybeTruncated(fileInfo.Size()) {
+				text += "\n" + localization.T("The file is exactly 4 GB - 1 byte, the FAT32 size limit. It was probably cut off when copied to a FAT32 drive.")
+				importance = widget.WarningImportance
+			}
+			t.filePathLabel.SetText(text)
 		}
+	}
+	t.filePathLabel.Importance = importance
+	t.filePathLabel.Refresh()
+	t.filePreview.show(last)
+
+	t.updateUploadButton()
+}
+
+// updateAlbumCheck показывает выбор альбома только для провайдеров, которые умеют создавать папки со ссылкой
+// С зеркалами альбом не создается
+func (t *UploadTab) updateAlbumCheck() {
+	provider, ok := t.app.GetProvider(t.selectedProvider())
+	if _, canGroup := provider.(providers.CollectionProvider); ok && canGroup && len(t.vm.State().Mirrors) == 0 {
+		t.albumCheck.Show()
 		return
 	}
+	t.albumCheck.Hide()
+}
 
-	// Начинаем загрузку
-	t.startUpload()
+// updateMirrorsButton показывает выбранные зеркала на кнопке
+// Кнопка видна, если включено больше одного провайдера
+func (t *UploadTab) updateMirrorsButton() {
+	mirrors := t.vm.State().Mirrors
+	if len(mirrors) == 0 {
+		t.mirrorsBtn.SetText(localization.T("Also upload to…"))
+	} else {
+		t.mirrorsBtn.SetText(fmt.Sprintf(localization.T("Also to: %s"), strings.Join(mirrors, ", ")))
+	}
+	setVisible(t.mirrorsBtn, len(t.providerSelect.options) > 1)
 }
 
-// startUpload начинает процесс загрузки
-func (t *UploadTab) startUpload() {
-	t.isUploading = true
-	t.uploadBtn.SetText(localization.T("Cancel"))
-	t.resultBinding.Set("")
+// showMirrorsMenu открывает меню провайдеров-зеркал: выбор пункта включает или выключает зеркало
+func (t *UploadTab) showMirrorsMenu() {
+	s := t.vm.State()
+	var items []*fyne.MenuItem
+	for _, name := range t.providerSelect.options {
+		if name == s.Provider {
+			continue
+		}
+		item := fyne.NewMenuItemWithIcon(name, providerIcon(name), func() {
+			mirrors := slices.Clone(s.Mirrors)
+			if i := slices.Index(mirrors, name); i >= 0 {
+				mirrors = slices.Delete(mirrors, i, i+1)
+			} else {
+				mirrors = append(mirrors, name)
+			}
+			t.vm.SelectMirrors(mirrors)
+			t.updateMirrorsButton()
+			t.updateAlbumCheck()
+		})
+		item.Checked = slices.Contains(s.Mirrors, name)
+		items = append(items, item)
+	}
 
-	// Показываем элементы прогресса
-	t.progressBar.Show()
-	t.uploadedLabel.Show()
-	t.speedLabel.Show()
-	t.etaLabel.Show()
+	driver := fyne.CurrentApp().Driver()
+	canvas := driver.CanvasForObject(t.mirrorsBtn)
+	if canvas == nil || len(items) == 0 {
+		return
+	}
+	pos := driver.AbsolutePositionForObject(t.mirrorsBtn).AddXY(0, t.mirrorsBtn.Size().Height)
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), canvas, pos)
+}
+
+// onSelectURL обработчик выбора файла по ссылке
+func (t *UploadTab) onSelectURL() {
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("https://")
+	urlEntry.SetText(t.vm.State().RemoteURL)
+
+	// Подсказка, пойдут ли данные через наше соединение
+	hint := widget.NewLabel(t.remoteUploadHint())
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
 
-	// Используем binding для инициализации
-	t.progressBinding.Set(0)
-	t.uploadedBinding.Set(localization.T("Uploading..."))
-	t.speedBinding.Set("Speed: calculating...")
-	t.etaBinding.Set("ETA: calculating...")
+	items := []*widget.FormItem{
+		widget.NewFormItem(localization.T("URL"), urlEntry),
+		widget.NewFormItem("", hint),
+	}
+	form := dialog.NewForm(localization.T("Upload from URL"), localization.T("OK"), localization.T("Cancel"), items, func(confirmed bool) {
+		link := strings.TrimSpace(urlEntry.Text)
+		if confirmed && link != "" {
+			t.setRemoteURL(link)
+		}
+	}, t.app.MainWindow())
+	resizeDialog(form, fyne.NewSize(600, 200))
+	form.Show()
+}
 
-	// Получаем провайдер
-	provider, ok := t.app.GetProvider(t.selectedProvider)
+// remoteUploadHint описывает, как выбранный провайдер получит файл по ссылке
+func (t *UploadTab) remoteUploadHint() string {
+	provider, ok := t.app.GetProvider(t.selectedProvider())
 	if !ok {
-		t.finishUpload(fmt.Errorf("provider not found: %s", t.selectedProvider))
-		return
+		return ""
+	}
+	if _, remote := provider.(providers.RemoteUploader); remote {
+		return fmt.Sprintf(localization.T("%s downloads the file directly, it will not pass through your connection."), provider.Name())
+	}
+	return localization.T("The file will be downloaded to this computer first, then uploaded.")
+}
+
+// setRemoteURL выбирает ссылку на файл в качестве источника загрузки
+func (t *UploadTab) setRemoteURL(link string) {
+	t.vm.SelectURL(link)
+	t.filePathLabel.Importance = widget.MediumImportance
+	t.filePathLabel.SetText(fmt.Sprintf(localization.T("Selected: %s"), link))
+	t.filePreview.clear()
+	t.updateUploadButton()
+}
+
+// setSourceURIs выбирает файлы хранилищ, которые не являются локальной файловой системой
+// Размер таких файлов неизвестен до копирования, предпросмотра нет
+func (t *UploadTab) setSourceURIs(uris []string) {
+	t.vm.SelectURIs(uris)
+	t.filePathLabel.Importance = widget.MediumImportance
+	if len(uris) == 1 {
+		t.filePathLabel.SetText(fmt.Sprintf(localization.T("Selected: %s"), uris[0]))
+	} else {
+		t.filePathLabel.SetText(fmt.Sprintf(localization.T("Selected: %s"), fmt.Sprintf(localization.TN("%d files", len(uris)), len(uris))))
+	}
+	t.filePreview.clear()
+	t.updateUploadButton()
+}
+
+// UploadURIs выбирает файлы хранилищ и сразу ставит их в очередь выбранному провайдеру (перетаскивание в мини-окно)
+func (t *UploadTab) UploadURIs(uris []string) error {
+	t.setSourceURIs(uris)
+	if !t.vm.CanStart() {
+		return errNoProvider
 	}
+	return t.start()
+}
 
-	// Открываем файл
-	file, err := os.Open(t.selectedFile.Path())
+// openURI открывает файл любого хранилища Fyne по URI
+func openURI(raw string) (io.ReadCloser, error) {
+	uri, err := storage.ParseURI(raw)
 	if err != nil {
-		t.finishUpload(err)
+		return nil, err
+	}
+	return storage.Reader(uri)
+}
+
+// SelectFiles выбирает файлы по путям (контекстное меню файлового менеджера)
+// Несколько файлов загружаются по отдельности или одним альбомом
+func (t *UploadTab) SelectFiles(paths []string) {
+	if len(paths) == 0 {
 		return
 	}
+	t.vm.SelectFiles(paths)
+	t.showSelectedFiles(paths[len(paths)-1])
+}
 
-	// Получаем размер файла
-	fileInfo, err := file.Stat()
-	if err != nil {
-		file.Close()
-		t.finishUpload(err)
+// onUpload обработчик кнопки загрузки: начинает новую загрузку выбранного файла
+// Если последняя проверка показала, что провайдер недоступен, сначала спрашивает подтверждение
+func (t *UploadTab) onUpload() {
+	if !t.vm.CanStart() {
+		return
+	}
+	start := func() {
+		if err := t.start(); err != nil {
+			t.showFriendlyError(err)
+		}
+	}
+
+	name := t.selectedProvider()
+	if result, ok := t.app.healthIndicator.ProviderStatus(name); ok && result.Err != nil {
+		message := fmt.Sprintf(localization.T("%s looks unavailable right now (%v). Upload anyway?"), name, result.Err)
+		confirm := dialog.NewConfirm(localization.T("Provider unavailable"), message, func(ok bool) {
+			if ok {
+				start()
+			}
+		}, t.app.MainWindow())
+		confirm.SetConfirmText(localization.T("Upload anyway"))
+		confirm.Show()
+		return
+	}
+	start()
+}
+
+// onCancelAll отменяет все загрузки после подтверждения
+func (t *UploadTab) onCancelAll() {
+	active := t.vm.State().Active()
+	if active == 0 {
 		return
 	}
+	message := fmt.Sprintf(localization.TN("Cancel %d unfinished uploads?", active), active)
+	confirm := dialog.NewConfirm(localization.T("Cancel all"), message, func(ok bool) {
+		if ok {
+			t.vm.CancelAll()
+		}
+	}, t.app.MainWindow())
+	confirm.SetConfirmText(localization.T("Cancel all"))
+	confirm.Show()
+}
 
-	fileSize := fileInfo.Size()
-	filename := t.selectedFile.Name()
+// updateProviderStatus показывает доступность выбранного провайдера по последней проверке соединения
+// Провайдеры, которые не проверялись, значка не получают
+func (t *UploadTab) updateProviderStatus() {
+	if t.providerStatus == nil || t.app.healthIndicator == nil {
+		return
+	}
+	result, ok := t.app.healthIndicator.ProviderStatus(t.selectedProvider())
+	switch {
+	case !ok:
+		t.providerStatus.Hide()
+		return
+	case result.Err != nil:
+		t.providerStatus.SetResource(theme.NewErrorThemedResource(theme.ErrorIcon()))
+	default:
+		t.providerStatus.SetResource(theme.NewSuccessThemedResource(theme.ConfirmIcon()))
+	}
+	t.providerStatus.Show()
+}
 
-	// Создаем контекст с возможностью отмены
-	ctx, cancel := context.WithCancel(context.Background())
-	t.cancelUpload = cancel
+// UploadFiles выбирает файлы и сразу ставит их в очередь выбранному провайдеру (перетаскивание в мини-окно)
+func (t *UploadTab) UploadFiles(paths []string) error {
+	t.SelectFiles(paths)
+	if !t.vm.CanStart() {
+		return errNoProvider
+	}
+	return t.start()
+}
 
-	// Канал для прогресса
-	progressChan := make(chan providers.UploadProgress, 10)
+// Reupload повторяет загрузку записи истории в provider и зеркала mirrors
+// Источник берется из записи: локальный файл, если он еще есть, иначе ссылка или URI хранилища
+// Загрузка в того же провайдера идет в ту же папку аккаунта
+func (t *UploadTab) Reupload(entry history.Entry, provider string, mirrors []string) error {
+	switch {
+	case fileExists(entry.FilePath):
+		t.setSelectedFile(entry.FilePath)
+	case isWebURL(entry.SourceURL):
+		t.setRemoteURL(entry.SourceURL)
+	case entry.SourceURL != "":
+		t.setSourceURIs([]string{entry.SourceURL})
+	default:
+		return fmt.Errorf("%s: %w", entry.FilePath, os.ErrNotExist)
+	}
 
-	// Канал для результата загрузки
-	t.uploadResult = make(chan *uploadCompletion, 1)
+	// Папка запоминается в настройках провайдера, как при выборе в списке папок
+	if provider == entry.Provider && entry.FolderID != "" {
+		cfg := t.app.Config().GetProviderConfig(provider)
+		cfg.FolderID = entry.FolderID
+		t.app.Config().SetProviderConfig(provider, cfg)
+	}
+	if provider == t.selectedProvider() {
+		t.loadFolders()
+	} else {
+		t.providerSelect.SetSelected(provider)
+	}
+	t.vm.SelectMirrors(mirrors)
+	t.updateMirrorsButton()
+	t.albumCheck.SetChecked(false)
+	t.updateAlbumCheck()
 
-	// Запускаем загрузку в горутине
-	go func() {
-		defer file.Close()
-		defer close(progressChan)
+	return t.start()
+}
 
-		result, err := provider.Upload(ctx, file, filename, fileSize, progressChan)
+// fileExists сообщает, что локальный файл path существует
+func fileExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
 
-		// Отправляем результат в канал вместо прямого вызова UI функций
-		t.uploadResult <- &uploadCompletion{
-			result: result,
-			err:    err,
+// start ставит выбранный источник в очередь выбранному провайдеру и зеркалам
+func (t *UploadTab) start() error {
+	s := t.vm.State()
+	targets := make([]viewmodel.Target, 0, 1+len(s.Mirrors))
+	for _, name := range append([]string{s.Provider}, s.Mirrors...) {
+		provider, ok := t.app.GetProvider(name)
+		if !ok {
+			return fmt.Errorf("provider not found: %s", name)
 		}
-	}()
+		targets = append(targets, viewmodel.Target{Provider: provider, APIKey: t.app.Config().GetProviderAPIKey(name)})
+	}
 
-	// Сохраняем размер файла для UI обновлений
-	t.totalSize = fileSize
+	// Модель проверяет файлы и настройки провайдеров до начала загрузки
+	_, err := t.vm.StartMirrors(targets)
+	return err
+}
 
-	// Создаем канал для остановки UI обновлений
-	t.stopUIUpdate = make(chan struct{})
+// retryWithKey сохраняет исправленный API ключ провайдера и повторяет загрузку id с ним
+// Ключ проверяется до сохранения: неверный формат не попадает в настройки
+func (t *UploadTab) retryWithKey(id int, name, apiKey string) error {
+	provider, ok := t.app.GetProvider(name)
+	if !ok {
+		return fmt.Errorf("provider not found: %s", name)
+	}
+	if err := provider.ValidateAPIKey(apiKey); err != nil {
+		return upload.KeyError(name, apiKey, err)
+	}
 
-	// Запускаем горутину для обновления UI (потокобезопасно)
-	go t.updateUIFromProgress()
+	cfg := t.app.Config()
+	providerCfg := cfg.GetProviderConfig(name)
+	providerCfg.APIKey = apiKey
+	cfg.SetProviderConfig(name, providerCfg)
+	if t.app.settingsTab != nil {
+		t.app.settingsTab.setSavedAPIKey(name, apiKey)
+	}
 
-	// Отслеживаем прогресс (сохраняем данные без обновления UI)
-	go t.trackProgress(progressChan)
+	// Провайдер создается заново, чтобы получить сохраненный ключ
+	provider, _ = t.app.GetProvider(name)
+	_, err := t.vm.Retry(id, provider, apiKey)
+	return err
 }
 
-// trackProgress читает прогресс из канала и сохраняет его (БЕЗ обновления UI)
-func (t *UploadTab) trackProgress(progressChan <-chan providers.UploadProgress) {
-	for progress := range progressChan {
-		// Потокобезопасно сохраняем последний прогресс
-		t.progressMutex.Lock()
-		progressCopy := progress
-		t.latestProgress = &progressCopy
-		t.progressMutex.Unlock()
+// retryWithoutScan повторяет загрузку id, которую остановила проверка на вирусы, без проверки
+func (t *UploadTab) retryWithoutScan(id int, name string) error {
+	provider, ok := t.app.GetProvider(name)
+	if !ok {
+		return fmt.Errorf("provider not found: %s", name)
 	}
+	_, err := t.vm.RetryWithoutScan(id, provider, t.app.Config().GetProviderAPIKey(name))
+	return err
 }
 
-// updateUIFromProgress обновляет UI из тикера (потокобезопасно)
-func (t *UploadTab) updateUIFromProgress() {
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-t.stopUIUpdate:
-			return
+// ResumePending ставит в очередь загрузки, сохраненные при прошлом запуске
+// Загрузки, которые нельзя продолжить (файл удален, провайдер выключен), пропускаются
+func (t *UploadTab) ResumePending(items []queue.Item) {
+	skipped := 0
+	for _, item := range items {
+		provider, ok := t.app.GetProvider(item.Provider)
+		if !ok {
+			logging.Error("Provider of a saved upload is not available", "provider", item.Provider)
+			skipped++
+			continue
+		}
+		if _, err := t.vm.Resume(item, provider, t.app.Config().GetProviderAPIKey(item.Provider)); err != nil {
+			logging.ErrorWithError("Failed to resume upload", err, "provider", item.Provider, "file", item.FilePath, "url", item.SourceURL)
+			skipped++
+		}
+	}
 
-		case completion := <-t.uploadResult:
-			// Обрабатываем завершение загрузки из безопасного контекста
-			t.finishUpload(completion.err)
-			if completion.err == nil && completion.result != nil {
-				t.showResult(completion.result)
-			}
-			return
+	if skipped > 0 {
+		dialog.ShowInformation(localization.T("Resume uploads"),
+			fmt.Sprintf(localization.TN("%d saved uploads could not be resumed. See the log for details.", skipped), skipped),
+			t.app.MainWindow())
+	}
+}
 
-		case <-ticker.C:
-			// Потокобезопасно читаем последний прогресс
-			t.progressMutex.RLock()
-			progress := t.latestProgress
-			totalSize := t.totalSize
-			t.progressMutex.RUnlock()
+// render отображает состояние модели (вызывается из главного потока)
+func (t *UploadTab) render(s viewmodel.State) {
+	if t.jobsBox == nil {
+		return // вкладка еще не построена
+	}
 
-			if progress == nil {
-				continue
-			}
+	t.updateUploadButton()
+	setVisible(t.clearBtn, len(s.Jobs) > s.Active())
+	setVisible(t.resultsBtn, len(s.Succeeded()) > 0)
+	setVisible(t.queueBar, s.Active() > 0)
+	setVisible(t.pauseAllBtn, !s.Paused)
+	setVisible(t.resumeAllBtn, s.Paused)
+	t.updatePower(s)
+	t.updateTotal(s.Progress())
+	t.app.miniWindow.render(s)
+
+	// Карточки в порядке очереди; убранные из модели удаляются
+	objects := make([]fyne.CanvasObject, 0, len(s.Jobs))
+	seen := make(map[int]bool, len(s.Jobs))
+	for _, job := range s.Jobs {
+		card, ok := t.cards[job.ID]
+		if !ok {
+			card = newUploadCard(t, job.ID)
+			t.cards[job.ID] = card
+		}
+		card.render(job)
+		objects = append(objects, card.card)
+		seen[job.ID] = true
+	}
+	for id := range t.cards {
+		if !seen[id] {
+			delete(t.cards, id)
+		}
+	}
 
-			// Обновляем UI через data binding (ПОТОКОБЕЗОПАСНО!)
-			percentage := float64(progress.Percentage) / 100.0
-			t.progressBinding.Set(percentage)
+	if !sameObjects(t.jobsBox.Objects, objects) {
+		t.jobsBox.Objects = objects
+		t.jobsBox.Refresh()
+	}
+}
 
-			uploadedStr := providers.FormatSize(progress.BytesUploaded)
-			totalStr := providers.FormatSize(totalSize)
-			t.uploadedBinding.Set(fmt.Sprintf("Uploaded: %s / %s", uploadedStr, totalStr))
+// updateTotal показывает общий прогресс очереди и оставшееся время в окне и его заголовке
+// Заголовок виден на панели задач, поэтому прогресс заметен и со свернутым окном
+func (t *UploadTab) updateTotal(p viewmodel.Progress) {
+	title := "multiUploader"
+	if p.Active > 0 && p.Total > 0 {
+		progress := fmt.Sprintf("%d%%", int(p.Fraction()*100))
+		if remaining, ok := p.Remaining(); ok {
+			progress += " · ~" + localization.FormatDuration(remaining)
+		}
+		title = progress + " · " + title
+	}
+	if window := t.app.MainWindow(); window != nil && window.Title() != title {
+		window.SetTitle(title)
+	}
 
-			speedStr := providers.FormatSpeed(progress.Speed)
-			t.speedBinding.Set(fmt.Sprintf("Speed: %s", speedStr))
+	// Для одной загрузки общий прогресс повторяет ее карточку
+	setVisible(t.totalBox, p.Active > 1 && p.Total > 0)
+	if t.totalBox.Hidden {
+		return
+	}
+	t.totalBar.SetValue(p.Fraction())
+	eta := localization.FormatETA(p.Total-p.Bytes, p.AvgSpeed)
+	t.totalLabel.SetText(fmt.Sprintf("%s %s / %s · %s %s",
+		localization.T("Total:"), localization.FormatSize(p.Bytes), localization.FormatSize(p.Total),
+		localization.T("ETA:"), eta))
+}
 
-			bytesRemaining := totalSize - progress.BytesUploaded
-			etaStr := providers.CalculateETA(bytesRemaining, progress.Speed)
-			t.etaBinding.Set(fmt.Sprintf("ETA: %s", etaStr))
+// sameObjects сравнивает списки элементов
+func sameObjects(a, b []fyne.CanvasObject) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
 		}
 	}
+	return true
 }
 
-// finishUpload завершает процесс загрузки (вызывается из горутины!)
-func (t *UploadTab) finishUpload(err error) {
-	// Останавливаем UI обновления
-	if t.stopUIUpdate != nil {
-		close(t.stopUIUpdate)
-		t.stopUIUpdate = nil
+// setVisible показывает или скрывает элемент
+func setVisible(item fyne.CanvasObject, visible bool) {
+	if visible {
+		item.Show()
+	} else {
+		item.Hide()
 	}
+}
+
+// progressText возвращает строки прогресса загрузки: стадия или передано, скорость и ETA
+func progressText(job viewmodel.JobState) (uploaded, speed, eta string) {
+	calculating := localization.T("calculating...")
 
-	t.isUploading = false
-	// НЕ вызываем SetText из горутины - будет ошибка!
-	// t.uploadBtn.SetText("Upload")
-	t.cancelUpload = nil
+	switch job.Phase {
+	case viewmodel.PhaseFetching:
+		fetched := localization.FormatSize(job.Bytes)
+		if job.Total > 0 {
+			fetched += " / " + localization.FormatSize(job.Total)
+			eta = localization.T("ETA:") + " " + localization.FormatETA(job.Total-job.Bytes, job.AvgSpeed)
+		}
+		return fmt.Sprintf(localization.T("Fetching %s…"), job.FileName) + " " + fetched,
+			localization.T("Speed:") + " " + localization.FormatSpeed(job.Speed), eta
 
-	// Сбрасываем прогресс
-	t.progressMutex.Lock()
-	t.latestProgress = nil
-	t.progressMutex.Unlock()
+	case viewmodel.PhaseRemote:
+		return localization.T("The provider is fetching the file…"), "", ""
 
-	if err != nil {
-		// Логируем ошибку с контекстом
-		logging.ErrorWithError("Upload failed",
-			err,
-			"provider", t.selectedProvider,
-			"filename", t.selectedFile.Name(),
-			"filesize", t.totalSize,
-		)
+	case viewmodel.PhaseWaiting:
+		return fmt.Sprintf(localization.T("%s is limiting requests, retrying in %s"),
+			job.Provider, localization.FormatDuration(job.WaitRemaining)), "", ""
+
+	case viewmodel.PhaseFinalizing:
+		// Данные отправлены, сервер собирает файл - скорость и ETA уже не имеют смысла
+		return localization.T("Finalizing…"), "", ""
+
+	case viewmodel.PhaseVerifying:
+		return localization.T("Verifying upload…"), "", ""
+
+	case viewmodel.PhasePreparing:
+		return localization.T("Shrinking image…"), "", ""
+
+	case viewmodel.PhaseScanning:
+		return localization.T("Scanning for viruses…"), "", ""
+
+	case viewmodel.PhaseQueued:
+		return localization.T("Waiting in queue…"), "", ""
+
+	case viewmodel.PhaseOffline:
+		return localization.T("Connection lost, waiting for the network…"), "", ""
+
+	case viewmodel.PhasePaused:
+		return localization.T("Paused"), "", ""
+	}
 
-		// Отправляем уведомление об ошибке
+	if job.Bytes == 0 {
+		uploaded = localization.T("Uploading...")
+	} else {
+		uploaded = fmt.Sprintf("%s %s / %s", localization.T("Uploaded:"),
+			localization.FormatSize(job.Bytes), localization.FormatSize(job.Total))
+	}
+
+	// Данные давно не передаются - ETA бессмыслен, показываем зависание
+	if job.Stalled {
+		return uploaded, localization.T("Speed:") + " " + localization.T("Stalled"), localization.T("ETA:") + " " + calculating
+	}
+	if job.Bytes == 0 {
+		return uploaded, localization.T("Speed:") + " " + calculating, localization.T("ETA:") + " " + calculating
+	}
+	return uploaded,
+		localization.T("Speed:") + " " + localization.FormatSpeed(job.Speed),
+		localization.T("ETA:") + " " + localization.FormatETA(job.Total-job.Bytes, job.AvgSpeed)
+}
+
+// onCompleted сообщает об итоге загрузки звуком и уведомлением (вызывается из горутины)
+// Сам итог показывается в карточке загрузки
+func (t *UploadTab) onCompleted(c viewmodel.Completion) {
+	if c.Album != nil {
+		t.onAlbumCompleted(*c.Album)
+	}
+
+	if c.Err != nil {
+		// Отмену пользователем не озвучиваем и не уведомляем
+		if classifyError(c.Err) == ErrorTypeCancelled {
+			return
+		}
+		t.app.PlaySound(sound.EventFailure)
 		t.app.SendNotification(
 			localization.T("Upload Failed"),
-			fmt.Sprintf("%s - %s", t.selectedFile.Name(), localization.T("Check logs for details")),
+			fmt.Sprintf("%s - %s", c.FileName, localization.T("Check logs for details")),
 		)
-
-		// Показываем дружественное сообщение об ошибке
-		t.showFriendlyError(err)
+		return
+	}
+	// Загрузка альбома, отмененная в очереди, приносит только итог альбома
+	if c.Result == nil {
+		return
 	}
 
 	fyne.Do(func() {
-		t.uploadBtn.SetText(localization.T("Start Upload"))
-		t.progressBar.Hide()
-		t.uploadedLabel.Hide()
-		t.speedLabel.Hide()
-		t.etaLabel.Hide()
+		if !c.DryRun {
+			t.app.historyTab.Refresh()
+		}
 	})
-}
-
-// showResult показывает результат загрузки (вызывается из горутины!)
-func (t *UploadTab) showResult(result *providers.UploadResult) {
-	if result == nil {
+	// Файлы альбома не озвучиваются по одному - уведомление приходит одно на всю группу
+	if c.CollectionURL != "" {
+		go t.autoShare(c)
 		return
 	}
+	t.app.PlaySound(sound.EventSuccess)
+
+	// Клик по уведомлению открывает ссылку, кнопка "Show result" показывает диалог результата
+	showResult := notify.Action{
+		Label: localization.T("Show result"),
+		Callback: func() {
+			fyne.Do(func() {
+				t.app.ShowWindow()
+				t.resultDialog(c).Show()
+			})
+		},
+	}
+
+	var openLink *notify.Action
+	actions := []notify.Action{showResult}
+	if c.Result.URL != "" {
+		openLink = &notify.Action{Label: localization.T("Open link"), URL: c.Result.URL}
+		actions = []notify.Action{*openLink, showResult}
+	}
 
-	// Отправляем уведомление об успехе
-	t.app.SendNotification(
+	t.app.SendNotificationWithActions(
 		localization.T("Upload Complete"),
-		fmt.Sprintf("%s uploaded to %s", t.selectedFile.Name(), t.selectedProvider),
+		fmt.Sprintf(localization.T("%s uploaded to %s"), c.FileName, c.Provider),
+		openLink,
+		actions...,
 	)
 
+	// Чат может отвечать долго - не задерживаем итоги следующих загрузок
+	go t.autoShare(c)
+}
+
+// resultDialog создает диалог со ссылками загрузки (вызывается из главного потока)
+func (t *UploadTab) resultDialog(c viewmodel.Completion) dialog.Dialog {
+	result, verification := c.Result, c.Verification
+
 	// Создаем контейнер для результатов
 	content := container.NewVBox()
 
 	// Добавляем сообщение об успехе
 	successLabel := widget.NewLabel(localization.T("Upload Complete") + "!")
 	successLabel.TextStyle = fyne.TextStyle{Bold: true}
+	alignLabels(successLabel)
 	content.Add(successLabel)
+	if c.MIMEType != "" {
+		typeLabel := widget.NewLabel(fmt.Sprintf(localization.T("Type: %s"), c.MIMEType))
+		alignLabels(typeLabel)
+		content.Add(typeLabel)
+	}
 
-	// Функция для создания строки с URL и кнопкой копирования
-	createURLRow := func(label, url string) *fyne.Container {
-		// Label для описания
-		urlLabel := widget.NewLabel(label + ":")
-		urlLabel.TextStyle = fyne.TextStyle{Bold: true}
-
-		// Entry для URL (read-only, можно выделять текст)
-		urlEntry := widget.NewLabel(url)
-		urlEntry.SetText(url)
-		urlEntry.Selectable = true
-		//urlEntry.Disable() // Disable делает его read-only но позволяет выделять текст
-
-		// Кнопка копирования
-		copyBtn := widget.NewButton(localization.T("Copy"), func() {
-			t.app.MainWindow().Clipboard().SetContent(url)
-			// Можно добавить уведомление
-			dialog.ShowInformation(localization.T("Copied to clipboard"), localization.T("Link copied"), t.app.MainWindow())
-		})
+	// Результат проверки целостности
+	if verification != nil {
+		content.Add(newIntegrityLabel(verification.Status, verification.Detail))
+	}
 
-		copyBtn.SetIcon(theme.ContentCopyIcon())
+	// links все ссылки диалога для кнопки "Копировать все"
+	var links [][2]string
+	addLink := func(label, url string) {
+		links = append(links, [2]string{label, url})
+		content.Add(widget.NewLabel("")) // пустая строка для отступа
+		content.Add(newURLRow(t.app.MainWindow(), label, url))
+	}
 
-		return container.NewBorder(
-			nil, nil,
-			nil, copyBtn, // кнопка справа
-			container.NewVBox(urlLabel, urlEntry),
-		)
+	if c.CollectionURL != "" {
+		addLink(localization.T("Album URL"), c.CollectionURL)
 	}
 
-	// Добавляем основной URL
-	if result.URL != "" {
+	// Разрезанный файл: ссылки на все части вместо ссылки на первую
+	if len(c.Parts) > 0 {
 		content.Add(widget.NewLabel("")) // пустая строка для отступа
-		content.Add(createURLRow("URL", result.URL))
+		content.Add(newPartsSection(t.app.MainWindow(), c.FileName, c.Parts))
+		for _, part := range c.Parts {
+			links = append(links, [2]string{part.Name, part.Link()})
+		}
+	} else if result.URL != "" {
+		// Добавляем основной URL
+		addLink(localization.T("URL"), result.URL)
 	}
 
 	// Добавляем Download URL если есть
-	if result.DownloadURL != "" {
-		content.Add(widget.NewLabel("")) // пустая строка для отступа
-		content.Add(createURLRow("Download URL", result.DownloadURL))
+	if result.DownloadURL != "" && len(c.Parts) == 0 {
+		addLink(localization.T("Download URL"), result.DownloadURL)
 	}
 
 	// Добавляем Delete URL если есть
 	if result.DeleteURL != "" {
-		content.Add(widget.NewLabel("")) // пустая строка для отступа
-		content.Add(createURLRow("Delete URL", result.DeleteURL))
+		addLink(localization.T("Delete URL"), result.DeleteURL)
 	}
 
 	// Добавляем сообщение если есть
 	if result.Message != "" {
 		content.Add(widget.NewLabel("")) // пустая строка для отступа
-		messageLabel := widget.NewLabel(result.Message)
+		// Сообщения провайдеров без перевода возвращаются как есть
+		messageLabel := widget.NewLabel(localization.T(result.Message))
 		messageLabel.Wrapping = fyne.TextWrapWord
+		alignLabels(messageLabel)
 		content.Add(messageLabel)
 	}
 
-	// Показываем кастомный диалог
-	d := dialog.NewCustom(localization.T("Upload Results"), "Close", content, t.app.MainWindow())
-	d.Resize(fyne.NewSize(600, 400))
-	d.Show()
+	d := dialog.NewCustom(localization.T("Upload Results"), localization.T("Close"), content, t.app.MainWindow())
+	// Несколько ссылок копируются одним блоком, чтобы не собирать их по одной
+	if len(links) > 1 {
+		copyAll := widget.NewButtonWithIcon(localization.T("Copy all"), theme.ContentCopyIcon(), func() {
+			t.app.MainWindow().Clipboard().SetContent(formatLinks(c.FileName, links))
+			dialog.ShowInformation(localization.T("Copied to clipboard"), localization.T("All links copied"), t.app.MainWindow())
+		})
+		d.SetButtons([]fyne.CanvasObject{copyAll, widget.NewButton(localization.T("Close"), d.Hide)})
+	}
+	resizeDialog(d, fyne.NewSize(600, 400))
+	return d
+}
+
+// formatLinks собирает ссылки в текстовый блок: имя файла, затем "подпись: ссылка" по строке
+func formatLinks(fileName string, links [][2]string) string {
+	var b strings.Builder
+	if fileName != "" {
+		b.WriteString(fileName + "\n")
+	}
+	for _, link := range links {
+		fmt.Fprintf(&b, "%s: %s\n", link[0], link[1])
+	}
+	return b.String()
+}
+
+// newURLRow создает строку с подписью, выделяемой ссылкой и кнопками копирования и открытия в браузере
+func newURLRow(window fyne.Window, label, url string) *fyne.Container {
+	// Label для описания
+	urlLabel := widget.NewLabel(label + ":")
+	urlLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	// Entry для URL (read-only, можно выделять текст)
+	urlEntry := widget.NewLabel(url)
+	urlEntry.SetText(url)
+	urlEntry.Selectable = true
+	//urlEntry.Disable() // Disable делает его read-only но позволяет выделять текст
+
+	// Кнопка копирования
+	copyBtn := widget.NewButton(localization.T("Copy"), func() {
+		window.Clipboard().SetContent(url)
+		// Можно добавить уведомление
+		dialog.ShowInformation(localization.T("Copied to clipboard"), localization.T("Link copied"), window)
+	})
+
+	copyBtn.SetIcon(theme.ContentCopyIcon())
+	var buttons []fyne.CanvasObject
+
+	// Открыть можно только веб-ссылку: остальное (например, magnet) браузер не покажет
+	if isWebURL(url) {
+		buttons = append(buttons, widget.NewButtonWithIcon(localization.T("Open in browser"), theme.ComputerIcon(), func() {
+			openURL(window, url)
+		}))
+	}
+	buttons = append(buttons, copyBtn)
+
+	alignLabels(urlLabel, urlEntry)
+	return newRow(
+		nil, newHRow(buttons...), // кнопки в конце строки
+		container.NewVBox(urlLabel, urlEntry),
+	)
+}
+
+// isWebURL сообщает, что ссылка открывается в браузере (http или https)
+func isWebURL(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// newIntegrityLabel показывает итог проверки целостности, при расхождении - с подробностями
+func newIntegrityLabel(status upload.VerifyStatus, detail string) *widget.Label {
+	label := widget.NewLabel(integrityText(status))
+	label.Wrapping = fyne.TextWrapWord
+	alignLabels(label)
+
+	switch status {
+	case upload.VerifyOK:
+		label.Importance = widget.SuccessImportance
+	case upload.VerifyMismatch:
+		label.Importance = widget.DangerImportance
+		label.SetText(label.Text + "\n" + detail)
+	default:
+		label.Importance = widget.LowImportance
+	}
+	return label
+}
+
+// integrityText возвращает переведенное описание результата проверки целостности
+func integrityText(status upload.VerifyStatus) string {
+	switch status {
+	case upload.VerifyOK:
+		return localization.T("Integrity verified")
+	case upload.VerifyMismatch:
+		return localization.T("Integrity check failed: the uploaded file differs from the local file")
+	default:
+		return localization.T("Integrity could not be verified")
+	}
 }
 
 // updateUploadButton обновляет состояние кнопки загрузки
 func (t *UploadTab) updateUploadButton() {
-	if t.selectedFile != nil && t.selectedProvider != "" && !t.isUploading {
+	if t.vm.CanStart() {
 		t.uploadBtn.Enable()
-	} else if !t.isUploading {
+	} else {
 		t.uploadBtn.Disable()
 	}
 }
 
+// applySettings передает модели настройки очереди, обработки картинок, проверки на вирусы,
+// нарезки и чтения файлов
+func (t *UploadTab) applySettings() {
+	cfg := t.app.Config()
+	global := cfg.GetGlobalConfig()
+	t.vm.SetMaxConcurrent(global.MaxConcurrentUploads)
+	t.vm.SetImageOptions(imageOptions(cfg.GetImageConfig()))
+	t.vm.SetScanner(newScanner(cfg.GetScanConfig()))
+	t.vm.SetSplit(global.SplitLargeFiles, int64(global.SplitPartMB)<<20)
+	t.vm.SetReadAhead(global.ReadAheadMB << 20)
+}
+
 // Refresh обновляет список провайдеров (вызывается после изменения настроек)
 func (t *UploadTab) Refresh() {
+	t.dryRunBanner.Hidden = !httpclient.DryRunEnabled()
+	t.dryRunBanner.Refresh()
 	t.updateProviderList()
 	t.updateUploadButton()
+	t.applySettings()
+	if !t.app.Config().GetGlobalConfig().PreventSleep {
+		t.inhibitor.Release()
+	}
+	// API ключ мог измениться - перечитываем папки
+	t.loadFolders()
+	t.updateAlbumCheck()
 }
 
 // showFriendlyError показывает дружественное сообщение об ошибке
 func (t *UploadTab) showFriendlyError(err error) {
-	if err == nil {
-		return
-	}
-
-	friendlyErr := MakeFriendly(err)
-	message := FormatErrorMessage(friendlyErr)
-
-	// Показываем custom dialog с понятным сообщением
-	content := widget.NewLabel(message)
-	content.Wrapping = fyne.TextWrapWord
-
-	d := dialog.NewCustom(friendlyErr.Title, "OK", content, t.app.MainWindow())
-	d.Resize(fyne.NewSize(500, 200))
-	d.Show()
+	t.app.showFriendlyError(err)
 }
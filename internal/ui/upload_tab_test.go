@@ -0,0 +1,31 @@
+package ui
+
+import "testing"
+
+// TestFormatLinks проверяет текстовый блок ссылок для кнопки "Копировать все"
+func TestFormatLinks(t *testing.T) {
+	links := [][2]string{
+		{"URL", "https://example.com/f/abc"},
+		{"Delete URL", "https://example.com/del/abc"},
+	}
+	want := "file.bin\nURL: https://example.com/f/abc\nDelete URL: https://example.com/del/abc\n"
+	if got := formatLinks("file.bin", links); got != want {
+		t.Errorf("formatLinks() = %q, want %q", got, want)
+	}
+}
+
+// TestIsWebURL проверяет, какие ссылки открываются в браузере
+func TestIsWebURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://example.com/f/abc": true,
+		"http://127.0.0.1:8080/x":   true,
+		"magnet:?xt=urn:btih:abc":   false,
+		"/files/abc":                false,
+		"https://":                  false,
+	}
+	for link, want := range tests {
+		if got := isWebURL(link); got != want {
+			t.Errorf("isWebURL(%q) = %v, want %v", link, got, want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"multiUploader/internal/logging"
+	"multiUploader/internal/providers"
+)
+
+// parseXFSHosts разбирает хостинги XFileSharing из настроек
+// Имя не должно совпадать со встроенным провайдером: иначе хостинг подменил бы его
+func (a *App) parseXFSHosts(text string) ([]providers.XFSHost, error) {
+	hosts, err := providers.ParseXFSHosts(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, host := range hosts {
+		if _, ok := a.providerFactories[host.Name]; ok && !slices.Contains(a.xfsHosts, host.Name) {
+			return nil, fmt.Errorf("%s is already a provider name", host.Name)
+		}
+	}
+	return hosts, nil
+}
+
+// applyXFSHosts регистрирует хостинги XFileSharing из настроек вместо добавленных ранее
+// Возвращает true, если список провайдеров изменился
+func (a *App) applyXFSHosts() bool {
+	hosts, err := a.parseXFSHosts(a.config.GetGlobalConfig().XFSHosts)
+	if err != nil {
+		logging.ErrorWithError("Invalid XFileSharing hosts", err)
+		hosts = nil
+	}
+
+	for _, name := range a.xfsHosts {
+		a.unregisterProviderFactory(name)
+	}
+	names := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		a.RegisterProviderFactory(host.Name, func(apiKey string) providers.Provider {
+			return providers.NewXFSProvider(host, apiKey)
+		})
+		names = append(names, host.Name)
+	}
+
+	changed := !slices.Equal(names, a.xfsHosts)
+	a.xfsHosts = names
+	return changed
+}
@@ -1,19 +1,25 @@
 package updater
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+
+	"multiUploader/internal/httpclient"
 )
 
 const (
-	// GitHub API timeout
+	// GitHub API timeout (вместе с повторами)
 	apiTimeout = 10 * time.Second
 )
 
+// apiBaseURL адрес GitHub API (меняется в тестах)
+var apiBaseURL = "https://api.github.com"
+
 // ReleaseInfo содержит информацию о релизе с GitHub
 type ReleaseInfo struct {
 	TagName string `json:"tag_name"` // например "v1.0.2"
@@ -26,17 +32,22 @@ type ReleaseInfo struct {
 // repo - название репозитория (например "multiUploader")
 // currentVersion - текущая версия (например "1.0.1")
 // Возвращает информацию о последнем релизе или nil если обновлений нет
-func CheckForUpdates(owner, repo, currentVersion string) (*ReleaseInfo, error) {
+// Проверка прерывается при отмене ctx (выход из приложения) и не длится дольше apiTimeout
+func CheckForUpdates(ctx context.Context, owner, repo, currentVersion string) (*ReleaseInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, apiTimeout)
+	defer cancel()
+
 	// Формируем URL для GitHub API
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", owner, repo)
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/latest", apiBaseURL, owner, repo)
 
-	// Создаем HTTP клиент с таймаутом
-	client := &http.Client{
-		Timeout: apiTimeout,
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
 	}
+	req.Header.Set("Accept", "application/vnd.github+json")
 
 	// Делаем запрос
-	resp, err := client.Get(url)
+	resp, err := httpclient.Default().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
 	}
@@ -0,0 +1,54 @@
+package updater
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestCheckForUpdates проверяет сравнение с последним релизом на GitHub
+func TestCheckForUpdates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/VerTox/multiUploader/releases/latest" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"tag_name":"v1.2.0","name":"1.2.0","html_url":"https://github.com/VerTox/multiUploader/releases/tag/v1.2.0"}`))
+	}))
+	defer server.Close()
+	defer func(old string) { apiBaseURL = old }(apiBaseURL)
+	apiBaseURL = server.URL
+
+	release, err := CheckForUpdates(context.Background(), "VerTox", "multiUploader", "1.1.9")
+	if err != nil || release == nil || release.TagName != "v1.2.0" {
+		t.Errorf("CheckForUpdates(1.1.9) = %+v, %v; want v1.2.0", release, err)
+	}
+	release, err = CheckForUpdates(context.Background(), "VerTox", "multiUploader", "1.2.0")
+	if err != nil || release != nil {
+		t.Errorf("CheckForUpdates(1.2.0) = %+v, %v; want no update", release, err)
+	}
+}
+
+// TestCheckForUpdatesCancel проверяет, что зависший запрос прерывается отменой контекста
+func TestCheckForUpdatesCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	defer func(old string) { apiBaseURL = old }(apiBaseURL)
+	apiBaseURL = server.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	if _, err := CheckForUpdates(ctx, "VerTox", "multiUploader", "1.0.0"); err == nil {
+		t.Error("CheckForUpdates() error = nil after cancel")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("CheckForUpdates() returned after %v", elapsed)
+	}
+}
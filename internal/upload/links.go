@@ -0,0 +1,82 @@
+package upload
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// LinkStatus итог проверки ссылки на загруженный файл
+type LinkStatus string
+
+const (
+	// LinkUnknown проверить не удалось: нет сети, ошибка сервера
+	LinkUnknown LinkStatus = "unknown"
+	// LinkAlive сервер отдает файл по ссылке
+	LinkAlive LinkStatus = "alive"
+	// LinkDead файл удален или срок хранения истек (404, 410)
+	LinkDead LinkStatus = "dead"
+)
+
+// CheckLink проверяет ссылку HEAD запросом
+// Серверы, не поддерживающие HEAD, проверяются GET запросом первого байта
+func CheckLink(ctx context.Context, client Doer, url string) LinkStatus {
+	status := linkRequest(ctx, client, http.MethodHead, url)
+	if status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status == http.StatusForbidden {
+		status = linkRequest(ctx, client, http.MethodGet, url)
+	}
+
+	switch {
+	case status == http.StatusNotFound || status == http.StatusGone:
+		return LinkDead
+	case status >= 200 && status < 400:
+		return LinkAlive
+	}
+	return LinkUnknown
+}
+
+// linkRequest возвращает код ответа (0 при ошибке сети)
+func linkRequest(ctx context.Context, client Doer, method, url string) int {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0
+	}
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// CheckLinks проверяет ссылки параллельно, не больше workers одновременно
+// onResult вызывается из горутин проверки для каждой ссылки по ее индексу
+func CheckLinks(ctx context.Context, client Doer, urls []string, workers int, onResult func(i int, status LinkStatus)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				onResult(i, CheckLink(ctx, client, urls[i]))
+			}
+		}()
+	}
+
+	for i := range urls {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
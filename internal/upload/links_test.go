@@ -0,0 +1,61 @@
+package upload
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestCheckLinks проверяет распознавание живых и удаленных файлов
+func TestCheckLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/alive":
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/no-head":
+			// Сервер не поддерживает HEAD, но отдает первый байт файла
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			if r.Header.Get("Range") != "bytes=0-0" {
+				t.Errorf("GET without Range header")
+			}
+			w.WriteHeader(http.StatusPartialContent)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	paths := []string{"/alive", "/gone", "/missing", "/no-head", "/error"}
+	want := []LinkStatus{LinkAlive, LinkDead, LinkDead, LinkAlive, LinkUnknown}
+
+	urls := make([]string, len(paths))
+	for i, p := range paths {
+		urls[i] = server.URL + p
+	}
+
+	var mu sync.Mutex
+	got := make([]LinkStatus, len(urls))
+	CheckLinks(context.Background(), server.Client(), urls, 3, func(i int, status LinkStatus) {
+		mu.Lock()
+		got[i] = status
+		mu.Unlock()
+	})
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s: status = %s, want %s", paths[i], got[i], want[i])
+		}
+	}
+
+	if status := CheckLink(context.Background(), server.Client(), "http://127.0.0.1:1/"); status != LinkUnknown {
+		t.Errorf("unreachable server status = %s, want unknown", status)
+	}
+}
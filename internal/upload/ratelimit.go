@@ -0,0 +1,137 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"multiUploader/internal/providers"
+)
+
+const (
+	// maxRateLimitCooldown максимальная пауза после серии ответов 429
+	maxRateLimitCooldown = 10 * time.Minute
+	// maxRateLimitAttempts сколько раз повторять загрузку, на которую провайдер ответил 429
+	maxRateLimitAttempts = 4
+)
+
+// rateLimitBackoff начальная пауза, если сервер не прислал Retry-After (переопределяется в тестах)
+var rateLimitBackoff = 5 * time.Second
+
+// RateLimiter откладывает загрузки на провайдера, который ответил 429
+// Пауза общая для всех загрузок на провайдера и растет с каждым подряд идущим 429
+type RateLimiter struct {
+	mu        sync.Mutex
+	cooldowns map[string]*cooldown
+}
+
+// cooldown пауза одного провайдера
+type cooldown struct {
+	until   time.Time
+	strikes int // подряд идущие ответы 429
+}
+
+// NewRateLimiter создает ограничитель без пауз
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{cooldowns: make(map[string]*cooldown)}
+}
+
+// Throttled фиксирует ответ 429 и возвращает назначенную паузу
+// retryAfter из заголовка Retry-After используется, если он больше расчетной паузы
+func (l *RateLimiter) Throttled(provider string, retryAfter time.Duration) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c := l.cooldowns[provider]
+	if c == nil {
+		c = &cooldown{}
+		l.cooldowns[provider] = c
+	}
+	c.strikes++
+
+	delay := rateLimitBackoff << min(c.strikes-1, 16)
+	delay = min(max(delay, retryAfter), maxRateLimitCooldown)
+
+	// Параллельная загрузка могла уже назначить паузу длиннее
+	if until := time.Now().Add(delay); until.After(c.until) {
+		c.until = until
+	}
+	return time.Until(c.until)
+}
+
+// Succeeded сбрасывает счетчик 429 после успешного запроса
+func (l *RateLimiter) Succeeded(provider string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.cooldowns, provider)
+}
+
+// Cooldown возвращает оставшуюся паузу провайдера (0 - загружать можно)
+func (l *RateLimiter) Cooldown(provider string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c := l.cooldowns[provider]
+	if c == nil {
+		return 0
+	}
+	return max(time.Until(c.until), 0)
+}
+
+// Wait ждет окончания паузы провайдера
+// onWait вызывается перед ожиданием с его длительностью (может быть nil)
+func (l *RateLimiter) Wait(ctx context.Context, provider string, onWait func(time.Duration)) error {
+	for {
+		delay := l.Cooldown(provider)
+		if delay <= 0 {
+			return nil
+		}
+		if onWait != nil {
+			onWait(delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return providers.ErrCancelled
+		case <-timer.C:
+		}
+	}
+}
+
+// Do выполняет загрузку с учетом пауз провайдера: дожидается окончания паузы,
+// а на ответ 429 назначает новую паузу и повторяет попытку
+// attempt должен сам подготовить повтор (например, перемотать файл в начало)
+func (l *RateLimiter) Do(ctx context.Context, provider string, onWait func(time.Duration), attempt func() error) error {
+	for i := 1; ; i++ {
+		if err := l.Wait(ctx, provider, onWait); err != nil {
+			return err
+		}
+
+		err := attempt()
+		retryAfter, limited := RateLimited(err)
+		if !limited {
+			if err == nil {
+				l.Succeeded(provider)
+			}
+			return err
+		}
+
+		l.Throttled(provider, retryAfter)
+		if i >= maxRateLimitAttempts {
+			return err
+		}
+	}
+}
+
+// RateLimited сообщает, что провайдер отклонил запрос из-за частоты запросов,
+// и возвращает паузу из Retry-After (0 - сервер не указал)
+func RateLimited(err error) (time.Duration, bool) {
+	var quotaErr *providers.QuotaError
+	if errors.As(err, &quotaErr) && quotaErr.Reason == providers.QuotaRateLimit {
+		return quotaErr.RetryAfter, true
+	}
+	return 0, false
+}
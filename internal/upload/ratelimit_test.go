@@ -0,0 +1,112 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"multiUploader/internal/providers"
+)
+
+// TestRateLimiterBackoff проверяет рост паузы и сброс после успеха
+func TestRateLimiterBackoff(t *testing.T) {
+	l := NewRateLimiter()
+
+	if d := l.Cooldown("p"); d != 0 {
+		t.Fatalf("initial Cooldown() = %v, want 0", d)
+	}
+
+	first := l.Throttled("p", 0)
+	second := l.Throttled("p", 0)
+	if first <= 0 || first > rateLimitBackoff || second <= first {
+		t.Errorf("Throttled() = %v then %v, want growing pause up to %v", first, second, 2*rateLimitBackoff)
+	}
+
+	// Retry-After длиннее расчетной паузы побеждает, но не выходит за максимум
+	if d := l.Throttled("p", time.Minute); d < 59*time.Second {
+		t.Errorf("Throttled(Retry-After 1m) = %v", d)
+	}
+	if d := l.Throttled("p", time.Hour); d > maxRateLimitCooldown {
+		t.Errorf("Throttled(Retry-After 1h) = %v, want at most %v", d, maxRateLimitCooldown)
+	}
+
+	// Паузы провайдеров независимы
+	if d := l.Cooldown("other"); d != 0 {
+		t.Errorf("Cooldown(other) = %v, want 0", d)
+	}
+
+	l.Succeeded("p")
+	if d := l.Cooldown("p"); d != 0 {
+		t.Errorf("Cooldown() after success = %v, want 0", d)
+	}
+}
+
+// TestRateLimiterDo проверяет повтор загрузки после 429
+func TestRateLimiterDo(t *testing.T) {
+	defer func(d time.Duration) { rateLimitBackoff = d }(rateLimitBackoff)
+	rateLimitBackoff = time.Millisecond
+
+	limited := &providers.QuotaError{Reason: providers.QuotaRateLimit, Err: errors.New("429")}
+
+	t.Run("Retries until success", func(t *testing.T) {
+		l := NewRateLimiter()
+		attempts, waits := 0, 0
+		err := l.Do(context.Background(), "p", func(time.Duration) { waits++ }, func() error {
+			attempts++
+			if attempts < 3 {
+				return limited
+			}
+			return nil
+		})
+		if err != nil || attempts != 3 || waits != 2 {
+			t.Errorf("Do() = %v after %d attempts and %d waits, want nil after 3 and 2", err, attempts, waits)
+		}
+		if d := l.Cooldown("p"); d != 0 {
+			t.Errorf("Cooldown() after success = %v, want 0", d)
+		}
+	})
+
+	t.Run("Gives up", func(t *testing.T) {
+		l := NewRateLimiter()
+		attempts := 0
+		err := l.Do(context.Background(), "p", nil, func() error {
+			attempts++
+			return limited
+		})
+		if _, ok := RateLimited(err); !ok || attempts != maxRateLimitAttempts {
+			t.Errorf("Do() = %v after %d attempts, want rate limit error after %d", err, attempts, maxRateLimitAttempts)
+		}
+	})
+
+	t.Run("Other errors are not retried", func(t *testing.T) {
+		l := NewRateLimiter()
+		attempts := 0
+		boom := errors.New("boom")
+		err := l.Do(context.Background(), "p", nil, func() error {
+			attempts++
+			return boom
+		})
+		if err != boom || attempts != 1 {
+			t.Errorf("Do() = %v after %d attempts, want boom after 1", err, attempts)
+		}
+	})
+
+	t.Run("Cancelled while waiting", func(t *testing.T) {
+		rateLimitBackoff = time.Hour
+		defer func() { rateLimitBackoff = time.Millisecond }()
+
+		l := NewRateLimiter()
+		l.Throttled("p", 0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := l.Do(ctx, "p", nil, func() error {
+			t.Error("attempt started during cooldown")
+			return nil
+		})
+		if !errors.Is(err, providers.ErrCancelled) {
+			t.Errorf("Do() = %v, want ErrCancelled", err)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package upload
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// readAheadBlock сколько данных фоновая горутина читает с диска за раз
+const readAheadBlock = 256 << 10
+
+// ReadAheadSizesMB варианты буфера упреждающего чтения для настроек (0 - без буфера)
+var ReadAheadSizesMB = []int{0, 4, 16, 64}
+
+// File файл, который можно читать с упреждением
+type File interface {
+	io.ReadSeeker
+	io.ReaderAt
+}
+
+// ReadAhead читает файл впереди сети: фоновая горутина заполняет кольцевой буфер, пока провайдер
+// отправляет уже прочитанное. Медленный seek жесткого диска или сетевой папки не останавливает
+// передачу, пока в буфере есть данные, и провайдер не получает таймаут
+// Горутина читает, только пока в буфере есть место, поэтому диск не опережает сеть больше чем на буфер
+// ReadAt идет в файл напрямую (части, загружаемые параллельно), Seek сбрасывает буфер
+type ReadAhead struct {
+	file File
+	size int
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	buf  []byte // кольцевой буфер (создается при первом Read)
+	// start начало непрочитанных данных в buf, filled сколько их
+	start  int
+	filled int
+	// pos позиция в файле первого непрочитанного байта
+	pos int64
+	// err ошибка чтения файла после данных в буфере (io.EOF - файл прочитан до конца)
+	err error
+	// gen меняется при Seek: чтение, начатое до него, отбрасывается
+	gen     int
+	running bool
+	closed  bool
+}
+
+// NewReadAhead создает чтение file с упреждением на size байт
+func NewReadAhead(file File, size int) *ReadAhead {
+	r := &ReadAhead{file: file, size: size}
+	r.cond = sync.NewCond(&r.mu)
+	return r
+}
+
+func (r *ReadAhead) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.running && !r.closed {
+		r.running = true
+		r.buf = make([]byte, r.size)
+		go r.fill()
+	}
+
+	for r.filled == 0 && r.err == nil && !r.closed {
+		r.cond.Wait()
+	}
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	if r.filled == 0 {
+		return 0, r.err
+	}
+
+	n := copy(p, r.buf[r.start:min(r.start+r.filled, len(r.buf))])
+	r.start = (r.start + n) % len(r.buf)
+	r.filled -= n
+	r.pos += int64(n)
+	r.cond.Broadcast()
+	return n, nil
+}
+
+// fill читает файл в свободное место буфера (фоновая горутина)
+func (r *ReadAhead) fill() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for {
+		for !r.closed && (r.filled == len(r.buf) || r.err != nil) {
+			r.cond.Wait()
+		}
+		if r.closed {
+			return
+		}
+
+		// Свободное место до конца буфера: потребитель читает только заполненную часть
+		gen := r.gen
+		at := r.pos + int64(r.filled)
+		end := (r.start + r.filled) % len(r.buf)
+		n := min(len(r.buf)-end, len(r.buf)-r.filled, readAheadBlock)
+		r.mu.Unlock()
+
+		read, err := r.file.ReadAt(r.buf[end:end+n], at)
+
+		r.mu.Lock()
+		if gen != r.gen {
+			continue
+		}
+		r.filled += read
+		if err != nil {
+			r.err = err
+		}
+		r.cond.Broadcast()
+	}
+}
+
+// Seek переходит к offset; данные буфера после нового места сохраняются, иначе буфер сбрасывается
+func (r *ReadAhead) Seek(offset int64, whence int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.pos + offset
+	case io.SeekEnd:
+		end, err := r.file.Seek(offset, io.SeekEnd)
+		if err != nil {
+			return 0, err
+		}
+		abs = end
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if abs < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if skip := abs - r.pos; skip >= 0 && skip <= int64(r.filled) {
+		r.start = (r.start + int(skip)) % max(len(r.buf), 1)
+		r.filled -= int(skip)
+	} else {
+		r.start, r.filled, r.err = 0, 0, nil
+		r.gen++
+	}
+	r.pos = abs
+	r.cond.Broadcast()
+	return abs, nil
+}
+
+// ReadAt читает файл напрямую, мимо буфера
+func (r *ReadAhead) ReadAt(p []byte, off int64) (int, error) {
+	return r.file.ReadAt(p, off)
+}
+
+// Close останавливает фоновое чтение; сам файл не закрывается
+func (r *ReadAhead) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	r.cond.Broadcast()
+	r.mu.Unlock()
+	return nil
+}
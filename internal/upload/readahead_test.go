@@ -0,0 +1,72 @@
+package upload
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// slowFile файл, каждое чтение которого ждет delay, как seek жесткого диска
+type slowFile struct {
+	*bytes.Reader
+	delay time.Duration
+}
+
+func (f *slowFile) ReadAt(p []byte, off int64) (int, error) {
+	time.Sleep(f.delay)
+	return f.Reader.ReadAt(p, off)
+}
+
+// TestReadAhead проверяет, что данные не меняются при переходе через конец кольцевого буфера и после Seek
+func TestReadAhead(t *testing.T) {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	r := NewReadAhead(&slowFile{Reader: bytes.NewReader(data)}, 777)
+	defer r.Close()
+
+	if err := iotest.TestReader(r, data); err != nil {
+		t.Fatal(err)
+	}
+
+	// Повтор попытки с начала файла и переход внутри уже прочитанного вперед
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	head := make([]byte, 100)
+	if _, err := io.ReadFull(r, head); err != nil || !bytes.Equal(head, data[:100]) {
+		t.Fatalf("read after Seek(0) = %v, %v", head[:8], err)
+	}
+	if pos, err := r.Seek(50, io.SeekCurrent); err != nil || pos != 150 {
+		t.Fatalf("Seek(50, SeekCurrent) = %d, %v, want 150", pos, err)
+	}
+	rest, err := io.ReadAll(r)
+	if err != nil || !bytes.Equal(rest, data[150:]) {
+		t.Fatalf("read after Seek(150) = %d bytes, %v, want %d bytes", len(rest), err, len(data)-150)
+	}
+}
+
+// TestReadAheadSlowDisk проверяет, что медленное чтение файла идет, пока потребитель занят своими данными
+func TestReadAheadSlowDisk(t *testing.T) {
+	data := make([]byte, 4*readAheadBlock)
+	r := NewReadAhead(&slowFile{Reader: bytes.NewReader(data), delay: 50 * time.Millisecond}, len(data))
+	defer r.Close()
+
+	first := make([]byte, 1)
+	if _, err := r.Read(first); err != nil {
+		t.Fatal(err)
+	}
+	// Пока "сеть" отправляет первый байт, буфер дочитывается
+	time.Sleep(400 * time.Millisecond)
+
+	start := time.Now()
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("reading the buffered file took %v, want it served from the buffer", elapsed)
+	}
+}
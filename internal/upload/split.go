@@ -0,0 +1,76 @@
+package upload
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Part одна часть файла, разрезанного для провайдера с ограничением размера
+type Part struct {
+	Name   string // имя части: file.ext.001, file.ext.002, ...
+	Offset int64
+	Size   int64
+}
+
+// PartLink загруженная часть файла
+type PartLink struct {
+	Name        string `json:"name"`
+	URL         string `json:"url,omitempty"`
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// Link ссылка на часть: страница файла или прямая ссылка
+func (p PartLink) Link() string {
+	if p.URL != "" {
+		return p.URL
+	}
+	return p.DownloadURL
+}
+
+// SplitParts делит файл на части по partSize байт
+// Части нумеруются с 001 (как у split и 7-Zip); файл не больше partSize не делится (nil)
+func SplitParts(name string, size, partSize int64) []Part {
+	if partSize <= 0 || size <= partSize {
+		return nil
+	}
+
+	count := (size + partSize - 1) / partSize
+	width := max(len(fmt.Sprint(count)), 3)
+	parts := make([]Part, 0, count)
+	for i := int64(0); i < count; i++ {
+		offset := i * partSize
+		parts = append(parts, Part{
+			Name:   fmt.Sprintf("%s.%0*d", name, width, i+1),
+			Offset: offset,
+			Size:   min(partSize, size-offset),
+		})
+	}
+	return parts
+}
+
+// PartSize размер части для провайдера: наименьший из заданного в настройках и лимита провайдера
+// 0 - делить не нужно
+func PartSize(configured, limit int64) int64 {
+	switch {
+	case configured > 0 && limit > 0:
+		return min(configured, limit)
+	case configured > 0:
+		return configured
+	}
+	return max(limit, 0)
+}
+
+// RejoinCommands команды, которыми скачанные части склеиваются обратно в файл name
+func RejoinCommands(name string, parts []PartLink) string {
+	quoted := make([]string, len(parts))
+	for i, part := range parts {
+		quoted[i] = `"` + part.Name + `"`
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Linux, macOS\ncat %s > \"%s\"\n", strings.Join(quoted, " "), name)
+	fmt.Fprintf(&b, "# Windows (cmd)\ncopy /b %s \"%s\"\n", strings.Join(quoted, "+"), name)
+	if len(parts) > 0 {
+		fmt.Fprintf(&b, "# 7-Zip: select %s and use File > Combine Files", quoted[0])
+	}
+	return b.String()
+}
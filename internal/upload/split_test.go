@@ -0,0 +1,80 @@
+package upload
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestSplitParts проверяет нарезку на части и имена частей
+func TestSplitParts(t *testing.T) {
+	parts := SplitParts("movie.mkv", 250, 100)
+	want := []Part{
+		{Name: "movie.mkv.001", Offset: 0, Size: 100},
+		{Name: "movie.mkv.002", Offset: 100, Size: 100},
+		{Name: "movie.mkv.003", Offset: 200, Size: 50},
+	}
+	if !slices.Equal(parts, want) {
+		t.Errorf("SplitParts() = %+v, want %+v", parts, want)
+	}
+
+	if parts := SplitParts("small.bin", 100, 100); parts != nil {
+		t.Errorf("SplitParts() of file within part size = %+v, want nil", parts)
+	}
+	if parts := SplitParts("big.bin", 1000, 0); parts != nil {
+		t.Errorf("SplitParts() without part size = %+v, want nil", parts)
+	}
+	if parts := SplitParts("many.bin", 1001, 1); len(parts) != 1001 || parts[0].Name != "many.bin.0001" {
+		t.Errorf("SplitParts() into 1001 parts = %d parts, first %q, want 1001 and many.bin.0001", len(parts), parts[0].Name)
+	}
+}
+
+// TestSplitPartsLargeFile проверяет смещения и размеры частей файла больше 4 ГБ
+func TestSplitPartsLargeFile(t *testing.T) {
+	const gb = int64(1) << 30
+	size := 10*gb + 123
+	parts := SplitParts("backup.tar", size, 2*gb)
+	if len(parts) != 6 {
+		t.Fatalf("SplitParts() = %d parts, want 6", len(parts))
+	}
+
+	var total int64
+	for i, part := range parts {
+		if part.Offset != int64(i)*2*gb {
+			t.Errorf("part %d offset = %d, want %d", i+1, part.Offset, int64(i)*2*gb)
+		}
+		total += part.Size
+	}
+	if last := parts[5]; last.Size != 123 || total != size {
+		t.Errorf("last part size = %d, total = %d, want 123 and %d", last.Size, total, size)
+	}
+}
+
+// TestPartSize проверяет выбор размера части
+func TestPartSize(t *testing.T) {
+	for _, tc := range []struct{ configured, limit, want int64 }{
+		{0, 0, 0},
+		{0, 500, 500},
+		{100, 0, 100},
+		{100, 500, 100},
+		{1000, 500, 500},
+	} {
+		if got := PartSize(tc.configured, tc.limit); got != tc.want {
+			t.Errorf("PartSize(%d, %d) = %d, want %d", tc.configured, tc.limit, got, tc.want)
+		}
+	}
+}
+
+// TestRejoinCommands проверяет команды склейки для обеих систем и подсказку для 7-Zip
+func TestRejoinCommands(t *testing.T) {
+	got := RejoinCommands("my file.zip", []PartLink{{Name: "my file.zip.001"}, {Name: "my file.zip.002"}})
+	for _, want := range []string{
+		`cat "my file.zip.001" "my file.zip.002" > "my file.zip"`,
+		`copy /b "my file.zip.001"+"my file.zip.002" "my file.zip"`,
+		`7-Zip: select "my file.zip.001" and use File > Combine Files`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RejoinCommands() = %q, want it to contain %q", got, want)
+		}
+	}
+}
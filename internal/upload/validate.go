@@ -0,0 +1,185 @@
+package upload
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
+
+	"multiUploader/internal/providers"
+)
+
+// Ошибки проверки перед загрузкой
+var (
+	ErrFileNotFound   = errors.New("file not found")
+	ErrFileUnreadable = errors.New("file is not readable")
+	ErrNotRegularFile = errors.New("not a regular file")
+	ErrEmptyFile      = errors.New("file is empty")
+	ErrFileTooLarge   = errors.New("file is too large")
+	ErrFileType       = errors.New("file type is not allowed")
+	ErrAPIKeyMissing  = errors.New("API key is missing")
+	ErrInvalidURL     = errors.New("invalid source URL")
+	ErrSettings       = errors.New("provider settings are incomplete")
+)
+
+// FAT32MaxFileSize наибольший размер файла на FAT32 (4 ГБ - 1 байт)
+const FAT32MaxFileSize = 1<<32 - 1
+
+// MaybeTruncated сообщает, что размер файла ровно равен пределу FAT32
+// Файл больше 4 ГБ, скопированный на флешку или карту с FAT32, обрезается до этого размера
+func MaybeTruncated(size int64) bool {
+	return size == FAT32MaxFileSize
+}
+
+// ValidationError ошибка проверки файла или настроек перед загрузкой
+type ValidationError struct {
+	Err      error  // одна из ошибок Err*
+	Provider string // имя провайдера
+	Path     string // путь к файлу
+	URL      string // ссылка на источник (для загрузки по URL)
+	Size     int64  // размер файла (если известен)
+	Limit    int64  // максимальный размер провайдера (для ErrFileTooLarge)
+	Reason   error  // что не так с ключом или настройками (для ErrAPIKeyMissing и ErrSettings)
+}
+
+func (e *ValidationError) Error() string {
+	switch e.Err {
+	case ErrFileTooLarge:
+		return fmt.Sprintf("%s: %d bytes exceeds %s limit of %d bytes", e.Err, e.Size, e.Provider, e.Limit)
+	case ErrFileType:
+		return fmt.Sprintf("%s: %s does not accept %s", e.Err, e.Provider, e.Path)
+	case ErrAPIKeyMissing:
+		if e.Reason != nil {
+			return fmt.Sprintf("%s: %s: %v", e.Err, e.Provider, e.Reason)
+		}
+		return fmt.Sprintf("%s: %s", e.Err, e.Provider)
+	case ErrInvalidURL:
+		return fmt.Sprintf("%s: %s", e.Err, e.URL)
+	case ErrSettings:
+		return fmt.Sprintf("%s: %s: %v", e.Err, e.Provider, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s", e.Err, e.Path)
+}
+
+// Unwrap возвращает вид ошибки и ее причину, чтобы errors.Is находил обе
+func (e *ValidationError) Unwrap() []error {
+	if e.Reason == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.Reason}
+}
+
+// KeyError ошибка API ключа, который отклонил ValidateAPIKey провайдера
+// Причина сохраняется только для введенного ключа: пустой ключ просто не задан
+func KeyError(provider, apiKey string, err error) *ValidationError {
+	keyErr := &ValidationError{Err: ErrAPIKeyMissing, Provider: provider}
+	if strings.TrimSpace(apiKey) != "" {
+		keyErr.Reason = err
+	}
+	return keyErr
+}
+
+// ValidateRemote проверяет ссылку на источник и API ключ до загрузки по URL
+func ValidateRemote(rawURL string, provider providers.Provider, apiKey string) error {
+	name := provider.Name()
+
+	if provider.RequiresAuth() {
+		if err := provider.ValidateAPIKey(apiKey); err != nil {
+			keyErr := KeyError(name, apiKey, err)
+			keyErr.URL = rawURL
+			return keyErr
+		}
+	}
+	if err := validateSettings(provider); err != nil {
+		return &ValidationError{Err: ErrSettings, Provider: name, URL: rawURL, Reason: err}
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &ValidationError{Err: ErrInvalidURL, Provider: name, URL: rawURL}
+	}
+	return nil
+}
+
+// ValidateURI проверяет API ключ, настройки и тип файла до загрузки из хранилища,
+// которое не является локальной файловой системой: размер файла там становится известен только при чтении
+func ValidateURI(uri string, provider providers.Provider, apiKey string) error {
+	name := provider.Name()
+
+	if provider.RequiresAuth() {
+		if err := provider.ValidateAPIKey(apiKey); err != nil {
+			keyErr := KeyError(name, apiKey, err)
+			keyErr.Path = uri
+			return keyErr
+		}
+	}
+	if err := validateSettings(provider); err != nil {
+		return &ValidationError{Err: ErrSettings, Provider: name, Path: uri, Reason: err}
+	}
+	if !providers.GetCapabilities(provider).AcceptsFile(uri) {
+		return &ValidationError{Err: ErrFileType, Provider: name, Path: uri}
+	}
+	return nil
+}
+
+// Validate проверяет файл и провайдер до начала загрузки:
+// файл существует и читается, не пустой, провайдер принимает файлы такого типа
+// и укладывается в лимит провайдера, API ключ задан
+// Возвращает размер файла
+func Validate(path string, provider providers.Provider, apiKey string) (int64, error) {
+	name := provider.Name()
+
+	if provider.RequiresAuth() {
+		if err := provider.ValidateAPIKey(apiKey); err != nil {
+			keyErr := KeyError(name, apiKey, err)
+			keyErr.Path = path
+			return 0, keyErr
+		}
+	}
+	if err := validateSettings(provider); err != nil {
+		return 0, &ValidationError{Err: ErrSettings, Provider: name, Path: path, Reason: err}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, &ValidationError{Err: ErrFileNotFound, Provider: name, Path: path}
+		}
+		return 0, &ValidationError{Err: ErrFileUnreadable, Provider: name, Path: path}
+	}
+	if !info.Mode().IsRegular() {
+		return 0, &ValidationError{Err: ErrNotRegularFile, Provider: name, Path: path}
+	}
+
+	size := info.Size()
+	if size == 0 {
+		return 0, &ValidationError{Err: ErrEmptyFile, Provider: name, Path: path}
+	}
+
+	caps := providers.GetCapabilities(provider)
+	if !caps.AcceptsFile(path) {
+		return size, &ValidationError{Err: ErrFileType, Provider: name, Path: path, Size: size}
+	}
+	if limit := caps.MaxFileSize; limit > 0 && size > limit {
+		return size, &ValidationError{Err: ErrFileTooLarge, Provider: name, Path: path, Size: size, Limit: limit}
+	}
+
+	// Проверяем, что файл можно открыть на чтение
+	f, err := os.Open(path)
+	if err != nil {
+		return size, &ValidationError{Err: ErrFileUnreadable, Provider: name, Path: path, Size: size}
+	}
+	f.Close()
+
+	return size, nil
+}
+
+// validateSettings проверяет собственные поля настроек провайдера (адрес сервера и т.п.)
+func validateSettings(provider providers.Provider) error {
+	if sp, ok := provider.(providers.SettingsProvider); ok {
+		return sp.ValidateSettings()
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"multiUploader/internal/providers"
+)
+
+// limitedProvider провайдер с ограничением размера и типа файла
+type limitedProvider struct {
+	maxSize      int64
+	blockedTypes []string
+}
+
+func (p limitedProvider) Name() string       { return "Limited" }
+func (p limitedProvider) RequiresAuth() bool { return true }
+
+func (p limitedProvider) ValidateAPIKey(apiKey string) error {
+	if apiKey == "" {
+		return errors.New("API key is required")
+	}
+	return nil
+}
+
+func (p limitedProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- providers.UploadProgress) (*providers.UploadResult, error) {
+	return nil, nil
+}
+
+func (p limitedProvider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{MaxFileSize: p.maxSize, BlockedTypes: p.blockedTypes}
+}
+
+// TestValidate проверяет проверки перед загрузкой
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile := func(name string, size int) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	small := writeFile("small.bin", 10)
+	large := writeFile("large.bin", 100)
+	empty := writeFile("empty.bin", 0)
+	setup := writeFile("Setup.EXE", 10)
+	provider := limitedProvider{maxSize: 50, blockedTypes: []string{".exe"}}
+
+	tests := []struct {
+		name   string
+		path   string
+		apiKey string
+		want   error
+	}{
+		{"Valid", small, "key", nil},
+		{"Missing key", small, "", ErrAPIKeyMissing},
+		{"Not found", filepath.Join(dir, "missing.bin"), "key", ErrFileNotFound},
+		{"Directory", dir, "key", ErrNotRegularFile},
+		{"Empty", empty, "key", ErrEmptyFile},
+		{"Too large", large, "key", ErrFileTooLarge},
+		{"Blocked type", setup, "key", ErrFileType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Validate(tt.path, provider, tt.apiKey)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+			if err != nil {
+				var vErr *ValidationError
+				if !errors.As(err, &vErr) {
+					t.Errorf("Validate() error %T is not *ValidationError", err)
+				}
+			}
+		})
+	}
+
+	// Без ограничений в Capabilities большой файл проходит
+	if size, err := Validate(large, limitedProvider{}, "key"); err != nil || size != 100 {
+		t.Errorf("Validate() without limit = %d, %v", size, err)
+	}
+}
+
+// TestValidateLargeFile проверяет размер и лимит провайдера для файла больше 4 ГБ
+// Файл разреженный: место на диске не занимается
+func TestValidateLargeFile(t *testing.T) {
+	const gb = int64(1) << 30
+	path := filepath.Join(t.TempDir(), "huge.bin")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(5*gb + 1); err != nil {
+		f.Close()
+		t.Skipf("sparse files are not supported: %v", err)
+	}
+	f.Close()
+
+	if size, err := Validate(path, limitedProvider{maxSize: 10 * gb}, "key"); err != nil || size != 5*gb+1 {
+		t.Errorf("Validate() under 10 GB limit = %d, %v, want %d", size, err, 5*gb+1)
+	}
+
+	_, err = Validate(path, limitedProvider{maxSize: 4 * gb}, "key")
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Err != ErrFileTooLarge || vErr.Size != 5*gb+1 || vErr.Limit != 4*gb {
+		t.Errorf("Validate() over 4 GB limit = %v, want ErrFileTooLarge with exact sizes", err)
+	}
+}
+
+// TestMaybeTruncated проверяет распознавание файла, обрезанного до предела FAT32
+func TestMaybeTruncated(t *testing.T) {
+	for size, want := range map[int64]bool{
+		1<<32 - 1: true,
+		1 << 32:   false,
+		1<<32 - 2: false,
+		100:       false,
+	} {
+		if got := MaybeTruncated(size); got != want {
+			t.Errorf("MaybeTruncated(%d) = %v, want %v", size, got, want)
+		}
+	}
+}
+
+// TestValidateRemote проверяет ссылку на источник для загрузки по URL
+func TestValidateRemote(t *testing.T) {
+	provider := limitedProvider{}
+
+	tests := []struct {
+		name   string
+		url    string
+		apiKey string
+		want   error
+	}{
+		{"Valid", "https://example.com/file.zip", "key", nil},
+		{"Missing key", "https://example.com/file.zip", "", ErrAPIKeyMissing},
+		{"No scheme", "example.com/file.zip", "key", ErrInvalidURL},
+		{"FTP", "ftp://example.com/file.zip", "key", ErrInvalidURL},
+		{"No host", "https:///file.zip", "key", ErrInvalidURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRemote(tt.url, provider, tt.apiKey)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ValidateRemote() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestKeyError проверяет, что отказ для введенного ключа сохраняет причину, а пустой ключ - нет
+func TestKeyError(t *testing.T) {
+	reason := errors.New("bot token must look like 123456:ABC")
+
+	err := KeyError("Telegram", "123", reason)
+	if !errors.Is(err, ErrAPIKeyMissing) || !errors.Is(err, reason) {
+		t.Errorf("KeyError() = %v, want ErrAPIKeyMissing wrapping the reason", err)
+	}
+	if err := KeyError("Telegram", " ", reason); err.Reason != nil {
+		t.Errorf("KeyError() for an empty key has Reason %v, want nil", err.Reason)
+	}
+}
@@ -0,0 +1,160 @@
+package upload
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+
+	"multiUploader/internal/providers"
+)
+
+// VerifyStatus итог проверки целостности загруженного файла
+type VerifyStatus string
+
+const (
+	// VerifyUnavailable проверить нечем: провайдер не вернул хеш, HEAD не дал размера
+	VerifyUnavailable VerifyStatus = "unverified"
+	// VerifyOK размер или хеш на сервере совпали с локальным файлом
+	VerifyOK VerifyStatus = "verified"
+	// VerifyMismatch размер или хеш на сервере отличаются от локального файла
+	VerifyMismatch VerifyStatus = "mismatch"
+)
+
+// Doer выполняет HTTP запрос (httpclient.Client или *http.Client)
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Verification результат проверки целостности
+type Verification struct {
+	Status VerifyStatus
+	Size   int64  // размер локального файла
+	SHA256 string // хеш локального файла в hex
+	MD5    string // хеш локального файла в hex
+	Detail string // описание расхождения или причины, по которой проверка невозможна
+}
+
+// Hashes размер и хеши локального файла
+type Hashes struct {
+	Size   int64
+	SHA256 string
+	MD5    string
+}
+
+// Hasher считает размер и хеши данных, записанных в него: файл хешируется в том же проходе, что и читается
+type Hasher struct {
+	size int64
+	sha  hash.Hash
+	md5  hash.Hash
+}
+
+// NewHasher создает Hasher
+func NewHasher() *Hasher {
+	return &Hasher{sha: sha256.New(), md5: md5.New()}
+}
+
+func (h *Hasher) Write(p []byte) (int, error) {
+	h.sha.Write(p)
+	h.md5.Write(p)
+	h.size += int64(len(p))
+	return len(p), nil
+}
+
+// Hashes возвращает размер и хеши записанных данных
+func (h *Hasher) Hashes() Hashes {
+	return Hashes{Size: h.size, SHA256: hex.EncodeToString(h.sha.Sum(nil)), MD5: hex.EncodeToString(h.md5.Sum(nil))}
+}
+
+// Verify сравнивает загруженный файл с локальным:
+// MD5 из ответа провайдера, затем размер и ETag (если провайдер объявил его MD5) из ответа на HEAD запрос к ссылке на скачивание
+// Хеши локального файла возвращаются в любом случае, чтобы сохранить их в истории
+func Verify(ctx context.Context, client Doer, path string, result *providers.UploadResult) (Verification, error) {
+	hashes, err := hashFile(path)
+	if err != nil {
+		return Verification{Status: VerifyUnavailable}, err
+	}
+	return VerifyHashes(ctx, client, hashes, result), nil
+}
+
+// VerifyHashes то же, что Verify, по уже посчитанным хешам локального файла
+func VerifyHashes(ctx context.Context, client Doer, hashes Hashes, result *providers.UploadResult) Verification {
+	v := Verification{Status: VerifyUnavailable, Size: hashes.Size, SHA256: hashes.SHA256, MD5: hashes.MD5}
+
+	if result.MD5 != "" {
+		if !strings.EqualFold(result.MD5, v.MD5) {
+			v.Status = VerifyMismatch
+			v.Detail = fmt.Sprintf("MD5 mismatch: provider reported %s, local file is %s", result.MD5, v.MD5)
+			return v
+		}
+		v.Status = VerifyOK
+	}
+
+	if result.DownloadURL == "" {
+		if v.Status == VerifyUnavailable {
+			v.Detail = "provider did not return a checksum or download URL"
+		}
+		return v
+	}
+
+	if err := v.checkRemote(ctx, client, result.DownloadURL); err != nil && v.Status == VerifyUnavailable {
+		v.Detail = err.Error()
+	}
+	return v
+}
+
+// hashFile считает размер и хеши локального файла за один проход
+func hashFile(path string) (Hashes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Hashes{}, fmt.Errorf("failed to open file for verification: %w", err)
+	}
+	defer f.Close()
+
+	h := NewHasher()
+	if _, err := io.Copy(h, f); err != nil {
+		return Hashes{}, fmt.Errorf("failed to hash file: %w", err)
+	}
+	return h.Hashes(), nil
+}
+
+// checkRemote сравнивает размер из ответа на HEAD запрос
+// ETag не сравнивается: CDN и файлообменники отдают свои ETag, а ETag multipart загрузки S3 - не MD5 файла
+// Ответ с HTML считается страницей скачивания, а не самим файлом, и не проверяется
+func (v *Verification) checkRemote(ctx context.Context, client Doer, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HEAD request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("HEAD request failed: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HEAD request returned status %d", resp.StatusCode)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "text/html" {
+		return fmt.Errorf("download URL points to a web page, not the file")
+	}
+
+	if resp.ContentLength < 0 {
+		return fmt.Errorf("server did not report the file size")
+	}
+	if resp.ContentLength != v.Size {
+		v.Status = VerifyMismatch
+		v.Detail = fmt.Sprintf("size mismatch: server reports %d bytes, local file is %d bytes", resp.ContentLength, v.Size)
+		return nil
+	}
+	v.Status = VerifyOK
+	return nil
+}
@@ -0,0 +1,88 @@
+package upload
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"multiUploader/internal/providers"
+)
+
+// TestVerify проверяет сравнение загруженного файла с локальным
+func TestVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	const helloMD5 = "5d41402abc4b2a76b9719d911017c592"
+
+	tests := []struct {
+		name       string
+		md5        string
+		headers    map[string]string
+		status     int
+		noDownload bool
+		want       VerifyStatus
+	}{
+		{name: "size matches", headers: map[string]string{"Content-Length": "5"}, want: VerifyOK},
+		{name: "size differs", headers: map[string]string{"Content-Length": "4"}, want: VerifyMismatch},
+		// CDN и файлообменники отдают свои ETag: правильная загрузка не должна считаться испорченной
+		{name: "etag ignored", headers: map[string]string{"Content-Length": "5", "ETag": `"00000000000000000000000000000000"`}, want: VerifyOK},
+		{name: "etag only", headers: map[string]string{"ETag": `"` + helloMD5 + `"`}, want: VerifyUnavailable},
+		{name: "html page", headers: map[string]string{"Content-Type": "text/html; charset=utf-8", "Content-Length": "1234"}, want: VerifyUnavailable},
+		{name: "head not allowed", status: http.StatusMethodNotAllowed, want: VerifyUnavailable},
+		{name: "provider md5", md5: helloMD5, noDownload: true, want: VerifyOK},
+		{name: "provider md5 differs", md5: "ffffffffffffffffffffffffffffffff", want: VerifyMismatch},
+		{name: "nothing to compare", noDownload: true, want: VerifyUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodHead {
+					t.Errorf("method = %s, want HEAD", r.Method)
+				}
+				for k, v := range tt.headers {
+					w.Header().Set(k, v)
+				}
+				if _, ok := tt.headers["Content-Length"]; !ok {
+					// Без явного Content-Length сервер не сообщает размер
+					w.Header().Set("Transfer-Encoding", "chunked")
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+			}))
+			defer server.Close()
+
+			result := &providers.UploadResult{MD5: tt.md5}
+			if !tt.noDownload {
+				result.DownloadURL = server.URL
+			}
+
+			v, err := Verify(context.Background(), server.Client(), path, result)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if v.Status != tt.want {
+				t.Errorf("Status = %s, want %s (detail: %s)", v.Status, tt.want, v.Detail)
+			}
+			if v.Status != VerifyOK && v.Detail == "" {
+				t.Error("Detail is empty")
+			}
+			if v.Size != 5 || v.MD5 != helloMD5 || len(v.SHA256) != 64 {
+				t.Errorf("local file = %d/%s/%s", v.Size, v.MD5, v.SHA256)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := Verify(context.Background(), http.DefaultClient, filepath.Join(t.TempDir(), "none"), &providers.UploadResult{})
+		if err == nil {
+			t.Error("Verify() error = nil, want error")
+		}
+	})
+}
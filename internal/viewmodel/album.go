@@ -0,0 +1,93 @@
+package viewmodel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"multiUploader/internal/providers"
+)
+
+// albumCreateTimeout сколько ждать создания папки альбома
+const albumCreateTimeout = 30 * time.Second
+
+// AlbumResult итог загрузки группы файлов в одну папку
+type AlbumResult struct {
+	Name string
+	// URL ссылка на папку ("" - папку создать не удалось)
+	URL      string
+	Uploaded int
+	Failed   int
+}
+
+// album группа файлов, которые загружаются в одну новую папку провайдера
+// Папка создается первой вышедшей из очереди загрузкой группы, остальные ждут ее
+type album struct {
+	name     string
+	provider providers.CollectionProvider
+
+	once sync.Once
+	err  error
+
+	mu       sync.Mutex
+	link     string // ссылка на созданную папку
+	pending  int    // сколько загрузок группы еще не завершилось
+	uploaded int
+	failed   int
+}
+
+// newAlbum создает группу из files загрузок
+func newAlbum(name string, provider providers.CollectionProvider, files int) *album {
+	return &album{name: name, provider: provider, pending: files}
+}
+
+// open создает папку (один раз на группу) и направляет в нее загрузки провайдера
+// Папка не привязана к отмене одной загрузки - она нужна всей группе
+// Из parent берутся только значения (провайдер, трасса), но не отмена
+func (a *album) open(parent context.Context) error {
+	a.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), albumCreateTimeout)
+		defer cancel()
+		collection, err := a.provider.CreateCollection(ctx, a.name)
+		if err != nil {
+			a.err = err
+			return
+		}
+		a.provider.SetFolder(collection.ID)
+
+		a.mu.Lock()
+		a.link = collection.URL
+		a.mu.Unlock()
+	})
+	return a.err
+}
+
+// url ссылка на папку ("" - папка еще не создана или создать ее не удалось)
+func (a *album) url() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.link
+}
+
+// finish учитывает итог загрузки группы
+// Возвращает итог альбома, когда завершилась последняя загрузка, иначе nil
+func (a *album) finish(c *Completion) *AlbumResult {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if c.Err == nil && c.Result != nil {
+		a.uploaded++
+	} else {
+		a.failed++
+	}
+	a.pending--
+	if a.pending > 0 {
+		return nil
+	}
+
+	var url string
+	if a.uploaded > 0 {
+		url = a.link
+	}
+	return &AlbumResult{Name: a.name, URL: url, Uploaded: a.uploaded, Failed: a.failed}
+}
@@ -0,0 +1,407 @@
+package viewmodel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"multiUploader/internal/logging"
+	"multiUploader/internal/providers"
+	"multiUploader/internal/scan"
+	"multiUploader/internal/upload"
+)
+
+const (
+	// mirrorChunkSize сколько данных читается с диска за раз при общем проходе по файлу
+	mirrorChunkSize = 1 << 20
+	// mirrorBufferChunks сколько прочитанных кусков ждет каждую загрузку (буфер на загрузку - 16 МБ)
+	mirrorBufferChunks = 16
+)
+
+var (
+	// mirrorJoinWait сколько общий проход ждет остальные загрузки после первой (переопределяется в тестах)
+	mirrorJoinWait = 10 * time.Second
+	// mirrorStallTimeout сколько общий проход ждет загрузку с полным буфером, прежде чем она
+	// продолжит читать файл сама (переопределяется в тестах)
+	mirrorStallTimeout = 5 * time.Second
+)
+
+// Target провайдер и API ключ одной из загрузок в несколько провайдеров
+type Target struct {
+	Provider providers.Provider
+	APIKey   string
+}
+
+// mirrorGroup загрузки одного файла в несколько провайдеров (зеркала)
+// Загрузки получают данные из одного прохода по файлу: кусок читается с диска один раз
+// и раздается в буферы всех загрузок. В том же проходе считаются хеши для проверки целостности,
+// а проверка на вирусы делается один раз на группу
+// Повторные попытки, опоздавшие загрузки, разрезанные файлы и докачка читают файл сами
+type mirrorGroup struct {
+	path string
+
+	mu sync.Mutex
+	// waiting провайдеры группы, которые еще не подключились к общему проходу
+	waiting map[string]bool
+	readers []*mirrorReader
+	started bool
+	timer   *time.Timer
+	// shared хеши файла из общего прохода (nil - проход не дочитал файл)
+	shared *upload.Hashes
+
+	// scanning закрывается, когда закончилась идущая проверка на вирусы (nil - проверка не идет)
+	scanning   chan struct{}
+	scanned    bool
+	scanResult scan.Result
+	scanErr    error
+}
+
+// newMirrorGroup создает группу для файла path; names - провайдеры группы
+func newMirrorGroup(path string, names []string) *mirrorGroup {
+	g := &mirrorGroup{path: path, waiting: make(map[string]bool)}
+	for _, name := range names {
+		g.waiting[name] = true
+	}
+	return g
+}
+
+// expects сообщает, ждет ли общий проход провайдера name
+func (g *mirrorGroup) expects(name string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.waiting[name]
+}
+
+// join подключает загрузку sess к общему проходу по файлу
+// Возвращает nil, если проход уже начался: опоздавшая загрузка читает файл сама
+// file - собственный открытый файл загрузки: из него читается остаток, если она отстала от прохода
+func (g *mirrorGroup) join(sess *session, file io.ReaderAt, size int64) *mirrorReader {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.waiting, sess.provider)
+	if g.started {
+		return nil
+	}
+
+	r := &mirrorReader{
+		ctx:    sess.ctx,
+		log:    &sess.log,
+		file:   file,
+		size:   size,
+		chunks: make(chan []byte, mirrorBufferChunks),
+		closed: make(chan struct{}),
+	}
+	g.readers = append(g.readers, r)
+	// Проход начинается, когда подключились все загрузки группы или истекло ожидание
+	if len(g.waiting) == 0 {
+		g.startLocked()
+	} else if g.timer == nil {
+		g.timer = time.AfterFunc(mirrorJoinWait, g.start)
+	}
+	return r
+}
+
+// leave отмечает, что загрузка провайдера name не подключится к общему проходу
+func (g *mirrorGroup) leave(name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.waiting[name] {
+		return
+	}
+	delete(g.waiting, name)
+	if len(g.waiting) == 0 && len(g.readers) > 0 {
+		g.startLocked()
+	}
+}
+
+// start начинает общий проход по файлу
+func (g *mirrorGroup) start() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.startLocked()
+}
+
+func (g *mirrorGroup) startLocked() {
+	if g.started {
+		return
+	}
+	g.started = true
+	if g.timer != nil {
+		g.timer.Stop()
+	}
+	go g.pump(slices.Clone(g.readers))
+}
+
+// pump читает файл один раз и раздает куски подключенным загрузкам
+// Загрузка, которая не забирает данные дольше mirrorStallTimeout (пауза, медленный сервер),
+// отключается и дочитывает файл сама, чтобы не держать остальные
+func (g *mirrorGroup) pump(readers []*mirrorReader) {
+	active := readers
+	defer func() {
+		for _, r := range active {
+			close(r.chunks)
+		}
+	}()
+
+	f, err := os.Open(g.path)
+	if err != nil {
+		logging.ErrorWithError("Failed to open file for mirror uploads", err)
+		return
+	}
+	defer f.Close()
+
+	hasher := upload.NewHasher()
+	for len(active) > 0 {
+		chunk := make([]byte, mirrorChunkSize)
+		n, err := io.ReadFull(f, chunk)
+		if n > 0 {
+			chunk = chunk[:n]
+			hasher.Write(chunk)
+			active = deliver(active, chunk)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			hashes := hasher.Hashes()
+			g.mu.Lock()
+			g.shared = &hashes
+			g.mu.Unlock()
+			return
+		}
+		if err != nil {
+			logging.ErrorWithError("Failed to read file for mirror uploads", err)
+			return
+		}
+	}
+}
+
+// deliver отдает кусок каждой загрузке и возвращает загрузки, которые остаются в общем проходе
+func deliver(readers []*mirrorReader, chunk []byte) []*mirrorReader {
+	active := readers[:0]
+	for _, r := range readers {
+		timer := time.NewTimer(mirrorStallTimeout)
+		select {
+		case r.chunks <- chunk:
+			active = append(active, r)
+		case <-r.closed:
+			close(r.chunks)
+		case <-timer.C:
+			r.log.add("Fell behind the other mirror uploads, reading the file from disk")
+			close(r.chunks)
+		}
+		timer.Stop()
+	}
+	return active
+}
+
+// hashes хеши файла path, посчитанные общим проходом
+func (g *mirrorGroup) hashes(path string) (upload.Hashes, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if path != g.path || g.shared == nil {
+		return upload.Hashes{}, false
+	}
+	return *g.shared, true
+}
+
+// scan проверяет файл группы на вирусы один раз; остальные загрузки ждут и получают тот же итог
+// Проверка, прерванная отменой загрузки, не засчитывается - ее повторит следующая загрузка группы
+func (g *mirrorGroup) scan(ctx context.Context, scanner scan.Scanner) (scan.Result, error) {
+	for {
+		g.mu.Lock()
+		if g.scanned {
+			result, err := g.scanResult, g.scanErr
+			g.mu.Unlock()
+			return result, err
+		}
+		if g.scanning == nil {
+			done := make(chan struct{})
+			g.scanning = done
+			g.mu.Unlock()
+
+			result, err := scanner.Scan(ctx, g.path)
+
+			g.mu.Lock()
+			if ctx.Err() == nil {
+				g.scanned, g.scanResult, g.scanErr = true, result, err
+			}
+			g.scanning = nil
+			g.mu.Unlock()
+			close(done)
+			return result, err
+		}
+		wait := g.scanning
+		g.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return scan.Result{}, ctx.Err()
+		}
+	}
+}
+
+// mirrorReader данные файла для одной загрузки группы
+// Куски общего прохода хранятся в окне из mirrorBufferChunks последних кусков: из него читают
+// части, которые провайдер загружает параллельно. Участок, который уже вышел из окна или лежит
+// дальше окна, а также остаток после отключения от прохода читаются из собственного файла загрузки
+type mirrorReader struct {
+	ctx  context.Context
+	log  *transferLog
+	file io.ReaderAt
+	size int64
+
+	// chunks куски общего прохода (закрывается, когда проход больше не пишет этой загрузке)
+	chunks chan []byte
+	// closed закрывается, когда загрузка больше не читает
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	// fetching кусок из прохода ждет одна часть за раз; остальные тем временем читают окно
+	fetching sync.Mutex
+	mu       sync.Mutex
+	// window последние полученные куски, base - смещение первого из них, end - конец последнего
+	window    [][]byte
+	base, end int64
+	// own проход больше не пишет этой загрузке
+	own bool
+	// fromFile часть данных прочитана из файла, а не из общего прохода
+	fromFile bool
+
+	// pos позиция Read и Seek
+	pos int64
+}
+
+func (r *mirrorReader) Read(p []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+	n, err := r.ReadAt(p, r.pos)
+	r.pos += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (r *mirrorReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, errors.New("seek before the start of the file")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+// ReadAt читает участок файла: из окна общего прохода, если он там есть или скоро придет, иначе из файла
+func (r *mirrorReader) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	var eof error
+	if int64(len(p)) > r.size-off {
+		p, eof = p[:r.size-off], io.EOF
+	}
+
+	n := 0
+	for n < len(p) {
+		copied, err := r.readShared(p[n:], off+int64(n))
+		if err != nil {
+			return n, err
+		}
+		if copied == 0 {
+			read, err := r.file.ReadAt(p[n:], off+int64(n))
+			n += read
+			if err == io.EOF && n == len(p) {
+				err = nil
+			}
+			if err != nil {
+				return n, err
+			}
+			break
+		}
+		n += copied
+	}
+	return n, eof
+}
+
+// readShared копирует в p данные с позиции off из окна, дожидаясь нужного куска от прохода
+// Возвращает 0, если участок нужно прочитать из файла
+func (r *mirrorReader) readShared(p []byte, off int64) (int, error) {
+	for {
+		r.mu.Lock()
+		if r.own || off < r.end || off >= r.end+mirrorBufferChunks*mirrorChunkSize {
+			break
+		}
+		r.mu.Unlock()
+		if err := r.receive(off); err != nil {
+			return 0, err
+		}
+	}
+	defer r.mu.Unlock()
+
+	if off < r.base || off >= r.end {
+		r.fromFile = true
+		return 0, nil
+	}
+	start := r.base
+	for _, chunk := range r.window {
+		if off < start+int64(len(chunk)) {
+			return copy(p, chunk[off-start:]), nil
+		}
+		start += int64(len(chunk))
+	}
+	return 0, nil
+}
+
+// receive добавляет в окно следующий кусок прохода, если окно еще не дошло до позиции off
+func (r *mirrorReader) receive(off int64) error {
+	r.fetching.Lock()
+	defer r.fetching.Unlock()
+
+	r.mu.Lock()
+	done := r.own || off < r.end
+	r.mu.Unlock()
+	if done {
+		return nil
+	}
+
+	select {
+	case chunk, ok := <-r.chunks:
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if !ok {
+			r.own = true
+			return nil
+		}
+		r.window = append(r.window, chunk)
+		r.end += int64(len(chunk))
+		if len(r.window) > mirrorBufferChunks {
+			r.base += int64(len(r.window[0]))
+			r.window = r.window[1:]
+		}
+		return nil
+	case <-r.ctx.Done():
+		return r.ctx.Err()
+	}
+}
+
+// close отключает загрузку от общего прохода
+func (r *mirrorReader) close() {
+	r.closeOnce.Do(func() { close(r.closed) })
+}
+
+// shared сообщает, что загрузка получила весь файл из общего прохода
+func (r *mirrorReader) shared() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.fromFile && r.end == r.size
+}
@@ -0,0 +1,74 @@
+package viewmodel
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+)
+
+// TestMirrorReaderWindow проверяет, что участки из окна общего прохода читаются из него,
+// а вышедшие из окна и лежащие дальше него - из файла
+func TestMirrorReaderWindow(t *testing.T) {
+	const chunks = 2*mirrorBufferChunks + 4
+	data := make([]byte, chunks*mirrorChunkSize)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	r := &mirrorReader{
+		ctx:    context.Background(),
+		file:   bytes.NewReader(data),
+		size:   int64(len(data)),
+		chunks: make(chan []byte, chunks),
+		closed: make(chan struct{}),
+	}
+	for i := range chunks {
+		r.chunks <- data[i*mirrorChunkSize : (i+1)*mirrorChunkSize]
+	}
+	close(r.chunks)
+
+	read := func(off, size int64) {
+		t.Helper()
+		got := make([]byte, size)
+		if _, err := r.ReadAt(got, off); err != nil {
+			t.Fatalf("ReadAt(%d) error = %v", off, err)
+		}
+		if !bytes.Equal(got, data[off:off+size]) {
+			t.Fatalf("ReadAt(%d) returned wrong data", off)
+		}
+	}
+
+	// Две параллельные части: вторая начинается дальше первой, но в пределах окна
+	read(3*mirrorChunkSize+10, mirrorChunkSize)
+	read(10, 2*mirrorChunkSize)
+	if r.fromFile || r.base != 0 {
+		t.Fatalf("parts within the window were not read from the shared read (base %d)", r.base)
+	}
+
+	// Часть за окном читается из файла и не сдвигает окно
+	end := r.end
+	far := end + mirrorBufferChunks*mirrorChunkSize
+	read(far+5, 100)
+	if !r.fromFile || r.end != end {
+		t.Fatalf("part past the window: fromFile = %v, end = %d", r.fromFile, r.end)
+	}
+
+	// Часть в конце окна сдвигает его; повтор части, вышедшей из окна, и остаток
+	// после конца прохода читаются из файла
+	read(far-mirrorChunkSize, mirrorChunkSize)
+	if r.base == 0 {
+		t.Fatal("window did not move forward")
+	}
+	read(0, mirrorChunkSize)
+
+	if _, err := r.Seek(int64(len(data))-7, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := io.ReadAll(r)
+	if err != nil || !bytes.Equal(rest, data[len(data)-7:]) {
+		t.Fatalf("ReadAll after Seek = %d bytes, %v", len(rest), err)
+	}
+	if r.shared() {
+		t.Error("shared() = true after reading from the file")
+	}
+}
@@ -0,0 +1,306 @@
+package viewmodel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/providers"
+	"multiUploader/internal/queue"
+	"multiUploader/internal/tracing"
+	"multiUploader/internal/upload"
+)
+
+// session одна загрузка со всеми ее горутинами
+// Горутины запускаются через spawn и завершаются по отмене ctx; итог публикуется
+// только после выхода всех горутин, поэтому завершенная загрузка больше не меняется
+type session struct {
+	ctx context.Context
+	// stop отменяет ctx с причиной (providers.ErrInterrupted - выход из приложения)
+	stop context.CancelCauseFunc
+	wg   sync.WaitGroup
+	// done закрывается, когда итог опубликован
+	done chan struct{}
+
+	id        int    // идентификатор загрузки в State.Jobs
+	provider  string // провайдер загрузки
+	sourceURL string // ссылка на источник (пусто для локального файла)
+	filePath  string // выбранный локальный файл (пусто при загрузке по ссылке)
+	// sourceURI файл хранилища, которое не является локальной файловой системой (копируется перед загрузкой)
+	sourceURI string
+	// album группа, в папку которой загружается файл (nil - обычная загрузка)
+	album *album
+	// backupKey провайдер создан с запасным API ключом: повторно он не подменяется
+	backupKey bool
+	// skipScan файл загружается без проверки на вирусы
+	skipScan bool
+	// mirror загрузки этого же файла в другие провайдеры (nil - файл загружается в один провайдер)
+	mirror *mirrorGroup
+	// mirrors имена провайдеров, в которые файл загружается вместе с этим (записываются в историю)
+	mirrors []string
+	// folderID папка аккаунта, выбранная у провайдера при постановке в очередь
+	folderID string
+	// log журнал передачи, сохраняется с записью истории
+	log transferLog
+	// span корневой span трассы загрузки (nil - трассировка выключена или загрузка не начиналась)
+	span *tracing.Span
+
+	// run загрузка, которую запускает планировщик очереди
+	run func()
+	// started загрузка вышла из очереди (защищено Upload.mu)
+	started bool
+
+	mu         sync.Mutex
+	uploadPath string // локальный файл (пусто при remote upload)
+	mimeType   string // MIME тип локального файла
+	// parts ссылки на части файла, разрезанного под лимит провайдера
+	parts   []upload.PartLink
+	tempDir string // временная папка с файлом, скачанным по ссылке, и обработанными копиями
+	// latestProgress последний прогресс от провайдера
+	latestProgress *providers.UploadProgress
+	// waitUntil конец паузы после ответа 429 (провайдер ограничил частоту запросов)
+	waitUntil time.Time
+	// offline связь пропала, загрузка ждет ее возвращения
+	offline bool
+	// pausedWaiters сколько горутин загрузки ждут в точке паузы (части загружаются параллельно)
+	pausedWaiters int
+	// outcome итог загрузки (заполняется один раз)
+	outcome *Completion
+	// resume снимок загрузки для продолжения после перезапуска (nil - файл еще не открыт)
+	resume *queue.Progress
+}
+
+// newSession создает сессию загрузки
+func newSession(id int, provider, sourceURL string) *session {
+	// Запросы загрузки получают дополнительные заголовки провайдера из настроек
+	ctx, stop := context.WithCancelCause(httpclient.WithProvider(context.Background(), provider))
+	return &session{ctx: ctx, stop: stop, done: make(chan struct{}), id: id, provider: provider, sourceURL: sourceURL}
+}
+
+// cancel отменяет ctx сессии
+func (s *session) cancel() {
+	s.stop(nil)
+}
+
+// spawn запускает горутину сессии
+// Вызывается до wait или из горутины этой же сессии
+func (s *session) spawn(fn func()) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		fn()
+	}()
+}
+
+// wait дожидается выхода всех горутин и удаляет временные файлы
+func (s *session) wait() {
+	s.wg.Wait()
+	s.cancel()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Файл уже закрыт горутиной провайдера - временную копию можно удалить
+	if s.tempDir != "" {
+		os.RemoveAll(s.tempDir)
+		s.tempDir = ""
+	}
+}
+
+// workDir создает папку во временной папке сессии (удаляется вместе с ней)
+func (s *session) workDir(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tempDir == "" {
+		dir, err := os.MkdirTemp("", "multiUploader-")
+		if err != nil {
+			return "", fmt.Errorf("failed to create temporary folder: %w", err)
+		}
+		s.tempDir = dir
+	}
+	dir := filepath.Join(s.tempDir, name)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create temporary folder: %w", err)
+	}
+	return dir, nil
+}
+
+// finish запоминает итог загрузки и останавливает оставшиеся горутины
+// Повторные вызовы игнорируются: итог определяет первая завершившаяся стадия
+func (s *session) finish(c Completion) {
+	s.mu.Lock()
+	if s.outcome == nil {
+		c.JobID = s.id
+		c.Provider = s.provider
+		s.outcome = &c
+	}
+	s.mu.Unlock()
+	s.cancel()
+}
+
+// result возвращает итог загрузки (nil, если горутины завершились без итога)
+func (s *session) result() *Completion {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.outcome
+}
+
+// setProgress сохраняет последний прогресс провайдера
+func (s *session) setProgress(p *providers.UploadProgress) {
+	s.mu.Lock()
+	s.latestProgress = p
+	s.mu.Unlock()
+}
+
+// progress возвращает последний прогресс, конец паузы после 429 и признак обрыва связи
+func (s *session) progress() (*providers.UploadProgress, time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latestProgress, s.waitUntil, s.offline
+}
+
+// setPaused отмечает, что горутина загрузки встала в точке паузы или продолжила передачу
+func (s *session) setPaused(paused bool) {
+	s.mu.Lock()
+	if paused {
+		s.pausedWaiters++
+	} else {
+		s.pausedWaiters--
+	}
+	waiters := s.pausedWaiters
+	s.mu.Unlock()
+
+	// В журнал попадают только остановка и продолжение всей загрузки, а не каждой части
+	switch {
+	case paused && waiters == 1:
+		s.log.add("Paused by the user")
+	case !paused && waiters == 0:
+		s.log.add("Pause ended")
+	}
+}
+
+// isPaused сообщает, что загрузка стоит на паузе
+func (s *session) isPaused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pausedWaiters > 0
+}
+
+// path возвращает загружаемый локальный файл
+func (s *session) path() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.uploadPath
+}
+
+// fileType возвращает MIME тип загружаемого локального файла
+func (s *session) fileType() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mimeType
+}
+
+// mirrorHashes хеши файла path, посчитанные при общем чтении зеркалами
+func (s *session) mirrorHashes(path string) (upload.Hashes, bool) {
+	if s.mirror == nil {
+		return upload.Hashes{}, false
+	}
+	return s.mirror.hashes(path)
+}
+
+// partLinks возвращает ссылки на части файла (nil, если файл загружен целиком)
+func (s *session) partLinks() []upload.PartLink {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.parts
+}
+
+// albumURL ссылка на папку альбома ("" - файл загружается не в альбом)
+func (s *session) albumURL() string {
+	if s.album == nil {
+		return ""
+	}
+	return s.album.url()
+}
+
+// snapshot возвращает копию снимка загрузки с отправленным объемом (nil - файл еще не открыт)
+func (s *session) snapshot() *queue.Progress {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resume == nil {
+		return nil
+	}
+	snap := *s.resume
+	snap.Parts = append([]upload.PartLink(nil), s.resume.Parts...)
+	if s.latestProgress != nil {
+		snap.Bytes = s.latestProgress.BytesUploaded
+	}
+	return &snap
+}
+
+// addPart запоминает загруженную часть разрезанного файла
+func (s *session) addPart(link upload.PartLink) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resume != nil {
+		s.resume.Parts = append(s.resume.Parts, link)
+		s.resume.Checkpoint = nil
+	}
+}
+
+// uploadedPart возвращает часть name, загруженную до перезапуска
+func (s *session) uploadedPart(name string) (upload.PartLink, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resume == nil {
+		return upload.PartLink{}, false
+	}
+	for _, link := range s.resume.Parts {
+		if link.Name == name {
+			return link, true
+		}
+	}
+	return upload.PartLink{}, false
+}
+
+// checkpoints хранилище точек продолжения загрузки файла или части name
+// saved вызывается, когда провайдер начал новую загрузку на сервере
+func (s *session) checkpoints(name string, saved func()) providers.Checkpoints {
+	return &sessionCheckpoints{sess: s, name: name, saved: saved}
+}
+
+// sessionCheckpoints точки продолжения одного файла в снимке сессии
+type sessionCheckpoints struct {
+	sess  *session
+	name  string
+	saved func()
+}
+
+func (c *sessionCheckpoints) Load() *providers.Checkpoint {
+	c.sess.mu.Lock()
+	defer c.sess.mu.Unlock()
+	if c.sess.resume == nil || c.sess.resume.Checkpoint == nil || c.sess.resume.Checkpoint.Name != c.name {
+		return nil
+	}
+	cp := c.sess.resume.Checkpoint
+	return &providers.Checkpoint{Upload: cp.Upload, Offset: cp.Offset}
+}
+
+func (c *sessionCheckpoints) Save(cp providers.Checkpoint) {
+	c.sess.mu.Lock()
+	if c.sess.resume == nil {
+		c.sess.mu.Unlock()
+		return
+	}
+	started := c.sess.resume.Checkpoint == nil || c.sess.resume.Checkpoint.Upload != cp.Upload
+	c.sess.resume.Checkpoint = &queue.Checkpoint{Name: c.name, Upload: cp.Upload, Offset: cp.Offset}
+	c.sess.mu.Unlock()
+
+	// Адрес новой загрузки сохраняется сразу: без него продолжить ее нельзя
+	if started {
+		c.saved()
+	}
+}
@@ -0,0 +1,119 @@
+package viewmodel
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"multiUploader/internal/history"
+	"multiUploader/internal/providers"
+)
+
+// maxLogEvents сколько событий журнала передачи сохраняется с записью истории
+// Файл из тысяч частей не должен раздувать историю - остальные события только подсчитываются
+const maxLogEvents = 300
+
+// transferLog журнал передачи одной загрузки: этапы, части, повторы и итог
+// Сохраняется с записью истории для разбора медленных и сбойных загрузок
+type transferLog struct {
+	mu      sync.Mutex
+	events  []history.LogEvent
+	dropped int
+
+	// parts последнее увиденное состояние частей, partStart - начало первой попытки части
+	parts     []providers.PartStatus
+	partStart map[int]time.Time
+}
+
+// add записывает событие
+func (l *transferLog) add(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.addLocked(time.Now(), fmt.Sprintf(format, args...))
+}
+
+func (l *transferLog) addLocked(now time.Time, message string) {
+	if len(l.events) >= maxLogEvents {
+		l.dropped++
+		return
+	}
+	l.events = append(l.events, history.LogEvent{Time: now, Message: message})
+}
+
+// started время первого события (нулевое, если событий нет)
+func (l *transferLog) started() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.events) == 0 {
+		return time.Time{}
+	}
+	return l.events[0].Time
+}
+
+// observeParts записывает изменения состояния частей по сравнению с прошлым обновлением
+// Обновления прогресса могут теряться, поэтому неудачная попытка определяется и по росту числа попыток
+func (l *transferLog) observeParts(parts []providers.PartStatus) {
+	if parts == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Новый набор частей - следующий файл разрезанной загрузки или повтор с начала
+	if len(parts) != len(l.parts) {
+		l.parts = make([]providers.PartStatus, len(parts))
+		l.partStart = make(map[int]time.Time)
+	}
+
+	now := time.Now()
+	for i, cur := range parts {
+		prev := l.parts[i]
+		if prev.Attempts == 0 && cur.Attempts > 0 {
+			l.partStart[cur.Number] = now
+		}
+
+		switch {
+		case cur.State == providers.PartRetrying && prev.State != providers.PartRetrying:
+			l.addLocked(now, fmt.Sprintf("Part %d attempt %d failed: %s", cur.Number, cur.Attempts, cur.Err))
+		case cur.Attempts > prev.Attempts && prev.Attempts > 0 && prev.State != providers.PartRetrying:
+			// Обновление с неудачной попыткой потерялось - видна уже следующая попытка
+			l.addLocked(now, fmt.Sprintf("Part %d attempt %d failed: %s", cur.Number, prev.Attempts, cur.Err))
+		}
+
+		if cur.State == prev.State {
+			continue
+		}
+		switch cur.State {
+		case providers.PartDone:
+			message := fmt.Sprintf("Part %d (%s) done in %s", cur.Number, providers.FormatSize(cur.Size), roundDuration(now.Sub(l.partStart[cur.Number])))
+			if cur.Attempts > 1 {
+				message += fmt.Sprintf(" after %d attempts", cur.Attempts)
+			}
+			l.addLocked(now, message)
+		case providers.PartFailed:
+			l.addLocked(now, fmt.Sprintf("Part %d failed after %d attempts: %s", cur.Number, cur.Attempts, cur.Err))
+		}
+	}
+	copy(l.parts, parts)
+}
+
+// entries события для записи в историю
+func (l *transferLog) entries() []history.LogEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events := append([]history.LogEvent(nil), l.events...)
+	if l.dropped > 0 {
+		events = append(events, history.LogEvent{Time: time.Now(), Message: fmt.Sprintf("%d more events not recorded", l.dropped)})
+	}
+	return events
+}
+
+// roundDuration округляет длительность для журнала
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(100 * time.Millisecond)
+}
@@ -0,0 +1,69 @@
+package viewmodel
+
+import (
+	"strings"
+	"testing"
+
+	"multiUploader/internal/providers"
+)
+
+// TestTransferLogParts проверяет запись состояния частей, в том числе при потерянных обновлениях
+func TestTransferLogParts(t *testing.T) {
+	var l transferLog
+	parts := func(states ...providers.PartStatus) []providers.PartStatus { return states }
+
+	l.observeParts(parts(
+		providers.PartStatus{Number: 1, Size: 1024, Attempts: 1, State: providers.PartUploading},
+		providers.PartStatus{Number: 2, Size: 1024, Attempts: 1, State: providers.PartUploading},
+	))
+	l.observeParts(parts(
+		providers.PartStatus{Number: 1, Size: 1024, Attempts: 1, State: providers.PartRetrying, Err: "timeout"},
+		providers.PartStatus{Number: 2, Size: 1024, Attempts: 1, State: providers.PartDone},
+	))
+	// Обновление с неудачной второй попыткой части 1 потерялось
+	l.observeParts(parts(
+		providers.PartStatus{Number: 1, Size: 1024, Attempts: 2, State: providers.PartUploading, Err: "timeout"},
+		providers.PartStatus{Number: 2, Size: 1024, Attempts: 1, State: providers.PartDone},
+	))
+	l.observeParts(parts(
+		providers.PartStatus{Number: 1, Size: 1024, Attempts: 3, State: providers.PartUploading, Err: "reset"},
+		providers.PartStatus{Number: 2, Size: 1024, Attempts: 1, State: providers.PartDone},
+	))
+	l.observeParts(parts(
+		providers.PartStatus{Number: 1, Size: 1024, Attempts: 3, State: providers.PartDone, Err: "reset"},
+		providers.PartStatus{Number: 2, Size: 1024, Attempts: 1, State: providers.PartDone},
+	))
+
+	// Длительность зависит от скорости теста - сравниваем начало и конец событий
+	want := []struct{ prefix, suffix string }{
+		{"Part 1 attempt 1 failed: timeout", ""},
+		{"Part 2 (1.0 KB) done in ", "s"},
+		{"Part 1 attempt 2 failed: reset", ""},
+		{"Part 1 (1.0 KB) done in ", " after 3 attempts"},
+	}
+	events := l.entries()
+	if len(events) != len(want) {
+		t.Fatalf("log = %+v, want %d events", events, len(want))
+	}
+	for i, w := range want {
+		if msg := events[i].Message; !strings.HasPrefix(msg, w.prefix) || !strings.HasSuffix(msg, w.suffix) {
+			t.Errorf("event %d = %q, want %q...%q", i, msg, w.prefix, w.suffix)
+		}
+	}
+}
+
+// TestTransferLogLimit проверяет, что журнал не растет бесконечно
+func TestTransferLogLimit(t *testing.T) {
+	var l transferLog
+	for i := range maxLogEvents + 5 {
+		l.add("event %d", i)
+	}
+
+	events := l.entries()
+	if len(events) != maxLogEvents+1 {
+		t.Fatalf("got %d events, want %d", len(events), maxLogEvents+1)
+	}
+	if last := events[len(events)-1].Message; last != "5 more events not recorded" {
+		t.Errorf("last event = %q, want the dropped count", last)
+	}
+}
@@ -0,0 +1,2029 @@
+// Package viewmodel содержит состояние и логику экранов без привязки к Fyne,
+// чтобы их можно было тестировать без UI и переиспользовать вне GUI
+package viewmodel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"multiUploader/internal/download"
+	"multiUploader/internal/health"
+	"multiUploader/internal/history"
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/logging"
+	"multiUploader/internal/metrics"
+	"multiUploader/internal/mimetype"
+	"multiUploader/internal/preprocess"
+	"multiUploader/internal/providers"
+	"multiUploader/internal/queue"
+	"multiUploader/internal/scan"
+	"multiUploader/internal/tracing"
+	"multiUploader/internal/upload"
+)
+
+const (
+	// verifyTimeout ограничение на запрос проверки целостности после загрузки
+	verifyTimeout = time.Minute
+	// updateInterval период публикации состояния во время загрузки
+	updateInterval = 100 * time.Millisecond
+	// sampleTicks замер скорости для графика берется раз в sampleTicks обновлений
+	sampleTicks = 5
+	// maxSpeedSamples сколько последних замеров скорости хранится для графика
+	maxSpeedSamples = 120
+	// maxOfflineRetries сколько раз повторять загрузку, прерванную обрывом связи
+	maxOfflineRetries = 5
+	// snapshotInterval как часто очередь сохраняется с прогрессом идущих загрузок
+	snapshotInterval = 5 * time.Second
+)
+
+// Phase стадия загрузки
+type Phase int
+
+const (
+	// PhaseIdle загрузка не идет
+	PhaseIdle Phase = iota
+	// PhaseFetching файл скачивается по ссылке перед загрузкой
+	PhaseFetching
+	// PhaseUploading данные передаются провайдеру
+	PhaseUploading
+	// PhaseRemote провайдер сам скачивает файл по ссылке (прогресса нет)
+	PhaseRemote
+	// PhaseWaiting пауза перед повтором после ответа 429
+	PhaseWaiting
+	// PhaseFinalizing данные отправлены, сервер собирает файл
+	PhaseFinalizing
+	// PhaseVerifying проверка целостности загруженного файла
+	PhaseVerifying
+	// PhaseQueued загрузка ждет свободного места в очереди
+	PhaseQueued
+	// PhaseOffline связь пропала, загрузка продолжится после ее возвращения
+	PhaseOffline
+	// PhasePreparing картинка уменьшается и пережимается перед загрузкой
+	PhasePreparing
+	// PhasePaused загрузка остановлена пользователем и продолжится после снятия паузы
+	PhasePaused
+	// PhaseScanning файл проверяется на вирусы перед загрузкой
+	PhaseScanning
+)
+
+// State снимок состояния вкладки загрузки для отображения
+type State struct {
+	Provider string
+	// Mirrors провайдеры, в которые выбранные файлы загружаются вместе с Provider
+	Mirrors   []string
+	FilePath  string // выбранный локальный файл
+	RemoteURL string // выбранная ссылка на источник вместо локального файла
+	// FilePaths все выбранные локальные файлы, FilePath - первый из них
+	FilePaths []string
+	// URIs выбранные файлы хранилищ, которые не являются локальной файловой системой
+	// (MTP устройства, хранилище Fyne на мобильных), вместо локальных файлов
+	URIs []string
+	// Album выбранные файлы загружаются в одну новую папку провайдера
+	Album bool
+	// Paused загрузки на паузе: очередь не запускает новые, идущие ждут в точках паузы
+	Paused bool
+
+	// Jobs загрузки в порядке очереди, включая завершенные
+	Jobs []JobState
+}
+
+// Active возвращает число незавершенных загрузок, включая ожидающие в очереди
+func (s State) Active() int {
+	n := 0
+	for _, job := range s.Jobs {
+		if job.Active {
+			n++
+		}
+	}
+	return n
+}
+
+// Queued возвращает число загрузок, ожидающих в очереди
+func (s State) Queued() int {
+	n := 0
+	for _, job := range s.Jobs {
+		if job.Active && job.Phase == PhaseQueued {
+			n++
+		}
+	}
+	return n
+}
+
+// Progress сводный прогресс незавершенных загрузок
+type Progress struct {
+	Active int // идут или ждут в очереди
+	Queued int // ждут в очереди
+	Bytes  int64
+	// Total сумма известных размеров; загрузки с неизвестным размером в долю не входят
+	Total int64
+	Speed float64 // суммарная скорость в байтах/сек
+	// AvgSpeed суммарная сглаженная скорость загрузок известного размера для ETA
+	AvgSpeed float64
+}
+
+// Fraction доля переданных байт от 0 до 1
+func (p Progress) Fraction() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return min(float64(p.Bytes)/float64(p.Total), 1)
+}
+
+// Remaining оставшееся время загрузок известного размера, включая ожидающие в очереди,
+// при текущей сглаженной скорости (false - скорость еще неизвестна)
+func (p Progress) Remaining() (time.Duration, bool) {
+	if p.AvgSpeed <= 0 || p.Total <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(max(p.Total-p.Bytes, 0)) / p.AvgSpeed * float64(time.Second)), true
+}
+
+// Progress возвращает сводный прогресс незавершенных загрузок
+func (s State) Progress() Progress {
+	var p Progress
+	for _, job := range s.Jobs {
+		if !job.Active {
+			continue
+		}
+		p.Active++
+		if job.Phase == PhaseQueued {
+			p.Queued++
+		}
+		if job.Total > 0 {
+			p.Bytes += job.Bytes
+			p.Total += job.Total
+			p.AvgSpeed += job.AvgSpeed
+		}
+		p.Speed += job.Speed
+	}
+	return p
+}
+
+// Succeeded возвращает итоги успешно завершенных загрузок в порядке очереди
+func (s State) Succeeded() []Completion {
+	var done []Completion
+	for _, job := range s.Jobs {
+		if job.Outcome != nil && job.Outcome.Err == nil && job.Outcome.Result != nil {
+			done = append(done, *job.Outcome)
+		}
+	}
+	return done
+}
+
+// Job возвращает загрузку по идентификатору
+func (s State) Job(id int) (JobState, bool) {
+	for _, job := range s.Jobs {
+		if job.ID == id {
+			return job, true
+		}
+	}
+	return JobState{}, false
+}
+
+// JobState состояние одной загрузки
+type JobState struct {
+	ID       int
+	Provider string
+	FileName string // имя загружаемого файла
+
+	Active bool // загрузка идет или ждет в очереди
+	Phase  Phase
+	// HighPriority загрузка выходит из очереди раньше обычных
+	HighPriority bool
+
+	Progress float64 // доля от 0 до 1
+	Bytes    int64   // передано байт
+	Total    int64   // размер файла (0 - неизвестен)
+	Speed    float64 // текущая скорость в байтах/сек
+	AvgSpeed float64 // сглаженная скорость для ETA
+	Stalled  bool    // данные давно не передаются
+	// WaitRemaining сколько осталось до повтора в PhaseWaiting
+	WaitRemaining time.Duration
+
+	// SpeedSamples замеры скорости для графика, SampleCount - сколько их было всего
+	SpeedSamples []float64
+	SampleCount  int
+
+	// Parts состояние частей при загрузке частями (nil - файл отправляется одним запросом)
+	Parts []providers.PartStatus
+
+	// Outcome итог завершенной загрузки (nil, пока загрузка идет)
+	Outcome *Completion
+
+	// source источник загрузки для повтора через Retry
+	source queue.Item
+}
+
+// Completion итог загрузки
+type Completion struct {
+	JobID    int
+	Provider string
+	FileName string
+	// Size размер загруженного файла (0, если неизвестен)
+	Size int64
+	// MIMEType тип загруженного файла ("" при remote upload)
+	MIMEType string
+	// Parts части файла, если он был разрезан под лимит провайдера (Result ссылается на первую)
+	// При ошибке - части, загруженные до нее
+	Parts []upload.PartLink
+	// CollectionURL ссылка на папку, если файл загружен в составе альбома
+	CollectionURL string
+	// Album итог альбома, заполняется у последней завершившейся загрузки группы
+	Album        *AlbumResult
+	Result       *providers.UploadResult
+	Verification *upload.Verification
+	Err          error
+	// DryRun загрузка прошла в режиме dry run: ссылки ненастоящие, в историю не записана
+	DryRun bool
+}
+
+// Upload модель вкладки загрузки: выбор файла и провайдера и очередь загрузок
+// Одновременно идет не больше maxConcurrent загрузок, остальные ждут в очереди
+// Изменения публикуются в Updates (только последнее состояние), итоги - в Results
+type Upload struct {
+	rateLimiter *upload.RateLimiter
+	history     *history.Store
+	// pending сохраненная очередь незавершенных загрузок (nil - не сохраняется)
+	pending *queue.Store
+
+	updates chan State
+	results chan Completion
+
+	mu    sync.Mutex
+	state State
+	// sessions идущие и ожидающие в очереди загрузки по идентификатору
+	sessions map[int]*session
+	nextID   int
+	// maxConcurrent сколько загрузок идет одновременно (0 - без ограничения)
+	maxConcurrent int
+	// backup создает провайдер с запасным API ключом (nil или false - запасного ключа нет)
+	backup BackupFunc
+	// imageOptions обработка картинок перед загрузкой
+	imageOptions preprocess.ImageOptions
+	// scanner проверка файлов на вирусы перед загрузкой (nil - не проверяются)
+	scanner scan.Scanner
+	// splitEnabled и splitPartSize нарезка файлов больше лимита провайдера на части
+	splitEnabled  bool
+	splitPartSize int64
+	// readAhead сколько байт файла читается впереди сети (0 - без упреждения)
+	readAhead int
+	// openURI открывает файлы хранилищ, которые не являются локальной файловой системой (nil - не открываются)
+	openURI URIOpener
+
+	// waitOnline ждет возвращения связи после обрыва (переопределяется в тестах)
+	waitOnline providers.WaitOnlineFunc
+	// resume закрывается при снятии паузы загрузок (nil - паузы нет, защищено mu)
+	resume chan struct{}
+
+	// closing приложение закрывается: сохраненная очередь больше не меняется (защищено mu)
+	closing bool
+	// snapshotAt когда очередь сохранялась с прогрессом загрузок (защищено mu)
+	snapshotAt time.Time
+}
+
+// NewUpload создает модель вкладки загрузки
+// store может быть nil - тогда загрузки не записываются в историю,
+// pending может быть nil - тогда очередь не переживает перезапуск
+func NewUpload(rateLimiter *upload.RateLimiter, store *history.Store, pending *queue.Store) *Upload {
+	return &Upload{
+		rateLimiter: rateLimiter,
+		history:     store,
+		pending:     pending,
+		waitOnline:  health.WaitOnline,
+		updates:     make(chan State, 1),
+		results:     make(chan Completion, 1),
+		sessions:    make(map[int]*session),
+	}
+}
+
+// Updates канал с последним состоянием (промежуточные состояния могут пропускаться)
+func (u *Upload) Updates() <-chan State {
+	return u.updates
+}
+
+// Results канал с итогами загрузок
+func (u *Upload) Results() <-chan Completion {
+	return u.results
+}
+
+// State возвращает текущее состояние
+func (u *Upload) State() State {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.snapshot()
+}
+
+// snapshot копия состояния (вызывается под u.mu)
+func (u *Upload) snapshot() State {
+	s := u.state
+	s.Jobs = make([]JobState, len(u.state.Jobs))
+	for i, job := range u.state.Jobs {
+		job.SpeedSamples = append([]float64(nil), job.SpeedSamples...)
+		s.Jobs[i] = job
+	}
+	return s
+}
+
+// update изменяет состояние и публикует его
+func (u *Upload) update(fn func(s *State)) {
+	u.mu.Lock()
+	fn(&u.state)
+	s := u.snapshot()
+	u.mu.Unlock()
+
+	// Непрочитанное состояние устарело - заменяем его новым
+	for {
+		select {
+		case u.updates <- s:
+			return
+		default:
+		}
+		select {
+		case <-u.updates:
+		default:
+		}
+	}
+}
+
+// updateJob изменяет состояние загрузки и публикует его
+func (u *Upload) updateJob(id int, fn func(job *JobState)) {
+	u.update(func(s *State) {
+		for i := range s.Jobs {
+			if s.Jobs[i].ID == id {
+				fn(&s.Jobs[i])
+				return
+			}
+		}
+	})
+}
+
+// SelectProvider выбирает провайдера для загрузки
+func (u *Upload) SelectProvider(name string) {
+	u.update(func(s *State) {
+		s.Provider = name
+		s.Mirrors = slices.DeleteFunc(slices.Clone(s.Mirrors), func(m string) bool { return m == name })
+	})
+}
+
+// SelectMirrors выбирает провайдеров, в которые файлы загружаются вместе с основным
+func (u *Upload) SelectMirrors(names []string) {
+	u.update(func(s *State) {
+		s.Mirrors = slices.DeleteFunc(slices.Clone(names), func(m string) bool { return m == s.Provider })
+	})
+}
+
+// SelectFile выбирает локальный файл
+func (u *Upload) SelectFile(path string) {
+	u.SelectFiles([]string{path})
+}
+
+// SelectFiles выбирает несколько локальных файлов; каждый загружается отдельно или все в один альбом
+func (u *Upload) SelectFiles(paths []string) {
+	u.update(func(s *State) {
+		s.FilePaths = slices.Clone(paths)
+		s.FilePath = ""
+		if len(paths) > 0 {
+			s.FilePath = paths[0]
+		}
+		s.RemoteURL = ""
+		s.URIs = nil
+	})
+}
+
+// SelectURIs выбирает файлы хранилищ, которые не являются локальной файловой системой
+// Каждый файл копируется во временную папку в начале своей загрузки
+func (u *Upload) SelectURIs(uris []string) {
+	u.update(func(s *State) {
+		s.URIs = slices.Clone(uris)
+		s.FilePath = ""
+		s.FilePaths = nil
+		s.RemoteURL = ""
+	})
+}
+
+// AddFile добавляет файл к выбранным (уже выбранный файл не повторяется)
+func (u *Upload) AddFile(path string) {
+	paths := u.State().FilePaths
+	if !slices.Contains(paths, path) {
+		paths = append(slices.Clone(paths), path)
+	}
+	u.SelectFiles(paths)
+}
+
+// SetAlbum включает загрузку выбранных файлов в одну новую папку
+func (u *Upload) SetAlbum(enabled bool) {
+	u.update(func(s *State) { s.Album = enabled })
+}
+
+// SelectURL выбирает ссылку на файл в качестве источника загрузки
+func (u *Upload) SelectURL(link string) {
+	u.update(func(s *State) {
+		s.RemoteURL = link
+		s.FilePath = ""
+		s.FilePaths = nil
+		s.URIs = nil
+	})
+}
+
+// CanStart сообщает, выбраны ли источник и провайдер для новой загрузки
+func (u *Upload) CanStart() bool {
+	s := u.State()
+	return (s.FilePath != "" || s.RemoteURL != "" || len(s.URIs) > 0) && s.Provider != ""
+}
+
+// URIOpener открывает файл хранилища по URI для чтения
+type URIOpener func(uri string) (io.ReadCloser, error)
+
+// SetURIOpener задает, как открываются файлы хранилищ, которые не являются локальной файловой системой
+func (u *Upload) SetURIOpener(open URIOpener) {
+	u.mu.Lock()
+	u.openURI = open
+	u.mu.Unlock()
+}
+
+// BackupFunc возвращает провайдер name, созданный с запасным API ключом, и сам ключ
+// (false - запасной ключ не задан)
+type BackupFunc func(name string) (providers.Provider, string, bool)
+
+// SetBackup задает запасные API ключи провайдеров
+// Загрузка, которой сервер отказал из-за ключа (401/403) или его лимита (429), один раз
+// повторяется с запасным ключом вместо ошибки
+func (u *Upload) SetBackup(backup BackupFunc) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.backup = backup
+}
+
+// SetMaxConcurrent задает, сколько загрузок идет одновременно (0 - без ограничения)
+// Уже идущие загрузки не останавливаются, новые ждут освобождения места
+func (u *Upload) SetMaxConcurrent(n int) {
+	u.mu.Lock()
+	u.maxConcurrent = max(n, 0)
+	u.mu.Unlock()
+	u.schedule()
+}
+
+// SetImageOptions задает обработку картинок для загрузок, которые начнутся после вызова
+func (u *Upload) SetImageOptions(opts preprocess.ImageOptions) {
+	u.mu.Lock()
+	u.imageOptions = opts
+	u.mu.Unlock()
+}
+
+// SetScanner задает проверку на вирусы для загрузок, которые начнутся после вызова (nil - без проверки)
+func (u *Upload) SetScanner(scanner scan.Scanner) {
+	u.mu.Lock()
+	u.scanner = scanner
+	u.mu.Unlock()
+}
+
+// SetSplit включает нарезку файлов, которые больше лимита провайдера или partSize, на части
+// partSize 0 - части размером с лимит провайдера
+func (u *Upload) SetSplit(enabled bool, partSize int64) {
+	u.mu.Lock()
+	u.splitEnabled, u.splitPartSize = enabled, partSize
+	u.mu.Unlock()
+}
+
+// SetReadAhead задает, сколько байт файла читается с диска впереди сети (0 - без упреждения)
+func (u *Upload) SetReadAhead(size int) {
+	u.mu.Lock()
+	u.readAhead = size
+	u.mu.Unlock()
+}
+
+// partSize размер части для провайдера (0 - файл не режется)
+func (u *Upload) partSize(provider providers.Provider) int64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !u.splitEnabled {
+		return 0
+	}
+	return upload.PartSize(u.splitPartSize, providers.GetCapabilities(provider).MaxFileSize)
+}
+
+// Start проверяет источник и настройки провайдера и ставит загрузку в очередь
+// Загрузка начинается сразу, если есть свободное место; возвращается ее идентификатор
+// Ошибка проверки возвращается сразу, ошибки загрузки - в Results
+// Несколько выбранных файлов ставятся в очередь по отдельности или альбомом; возвращается первая загрузка
+func (u *Upload) Start(provider providers.Provider, apiKey string) (int, error) {
+	s := u.State()
+	if len(s.URIs) > 0 {
+		return u.startURIs(s.URIs, []Target{{Provider: provider, APIKey: apiKey}})
+	}
+	collections, canGroup := provider.(providers.CollectionProvider)
+	if s.RemoteURL != "" || (len(s.FilePaths) <= 1 && !(s.Album && canGroup)) {
+		return u.enqueue(queue.Item{FilePath: s.FilePath, SourceURL: s.RemoteURL, Provider: provider.Name()}, provider, apiKey, false)
+	}
+
+	// Проверяем все файлы до начала, чтобы не загрузить группу наполовину
+	items := make([]queue.Item, len(s.FilePaths))
+	for i, path := range s.FilePaths {
+		items[i] = queue.Item{FilePath: path, Provider: provider.Name()}
+		if err := u.validate(items[i], provider, apiKey); err != nil {
+			return 0, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+	}
+
+	var group *album
+	if s.Album && canGroup {
+		group = newAlbum(albumName(time.Now()), collections, len(items))
+	}
+	var first int
+	for i, item := range items {
+		id := u.add(item, provider, group, nil, false)
+		if i == 0 {
+			first = id
+		}
+	}
+	return first, nil
+}
+
+// StartMirrors ставит выбранный источник в очередь сразу нескольким провайдерам (зеркала)
+// Все файлы проверяются для всех провайдеров до начала; возвращается первая загрузка
+// Загрузки одного локального файла выходят из очереди вместе: потоковые провайдеры получают
+// данные из одного чтения файла с диска, хеши для проверки целостности и проверка на вирусы общие
+// Альбомы с зеркалами не создаются; ссылка на источник ставится каждому провайдеру отдельно
+func (u *Upload) StartMirrors(targets []Target) (int, error) {
+	if len(targets) == 1 {
+		return u.Start(targets[0].Provider, targets[0].APIKey)
+	}
+
+	s := u.State()
+	if len(s.URIs) > 0 {
+		return u.startURIs(s.URIs, targets)
+	}
+	paths := s.FilePaths
+	if s.RemoteURL != "" || len(paths) == 0 {
+		paths = []string{s.FilePath}
+	}
+	for _, path := range paths {
+		for _, target := range targets {
+			item := queue.Item{FilePath: path, SourceURL: s.RemoteURL, Provider: target.Provider.Name()}
+			if err := u.validate(item, target.Provider, target.APIKey); err != nil {
+				if path == "" {
+					return 0, fmt.Errorf("%s: %w", item.Provider, err)
+				}
+				return 0, fmt.Errorf("%s, %s: %w", filepath.Base(path), item.Provider, err)
+			}
+		}
+	}
+
+	var first int
+	for _, path := range paths {
+		var mirror *mirrorGroup
+		if s.RemoteURL == "" {
+			names := make([]string, 0, len(targets))
+			for _, target := range targets {
+				names = append(names, target.Provider.Name())
+			}
+			mirror = newMirrorGroup(path, names)
+		}
+		for _, target := range targets {
+			item := queue.Item{FilePath: path, SourceURL: s.RemoteURL, Provider: target.Provider.Name()}
+			id := u.push(item, target.Provider, nil, mirror, mirrorNames(targets, target), false)
+			if first == 0 {
+				first = id
+			}
+		}
+	}
+	// Очередь запускается, когда в ней уже все зеркала
+	u.schedule()
+	return first, nil
+}
+
+// startURIs ставит файлы хранилищ в очередь каждому провайдеру по отдельности
+// Все файлы проверяются для всех провайдеров до начала; возвращается первая загрузка
+func (u *Upload) startURIs(uris []string, targets []Target) (int, error) {
+	for _, uri := range uris {
+		for _, target := range targets {
+			item := queue.Item{SourceURI: uri, Provider: target.Provider.Name()}
+			if err := u.validate(item, target.Provider, target.APIKey); err != nil {
+				return 0, fmt.Errorf("%s: %w", uriName(uri), err)
+			}
+		}
+	}
+
+	var first int
+	for _, uri := range uris {
+		for _, target := range targets {
+			item := queue.Item{SourceURI: uri, Provider: target.Provider.Name()}
+			id := u.push(item, target.Provider, nil, nil, mirrorNames(targets, target), false)
+			if first == 0 {
+				first = id
+			}
+		}
+	}
+	u.schedule()
+	return first, nil
+}
+
+// mirrorNames имена провайдеров targets, кроме target
+func mirrorNames(targets []Target, target Target) []string {
+	var names []string
+	for _, other := range targets {
+		if other.Provider.Name() != target.Provider.Name() {
+			names = append(names, other.Provider.Name())
+		}
+	}
+	return names
+}
+
+// uriName имя файла из URI хранилища
+func uriName(uri string) string {
+	if parsed, err := url.Parse(uri); err == nil && parsed.Path != "" {
+		if name := filepath.Base(parsed.Path); name != "/" && name != "." {
+			return name
+		}
+	}
+	return "file"
+}
+
+// albumName имя новой папки альбома
+func albumName(now time.Time) string {
+	return "multiUploader " + now.Format("2006-01-02 15.04.05")
+}
+
+// Resume ставит в очередь загрузку, сохраненную до перезапуска приложения
+// Папка аккаунта, выбранная при постановке в очередь, задается провайдеру снова
+func (u *Upload) Resume(item queue.Item, provider providers.Provider, apiKey string) (int, error) {
+	item.Provider = provider.Name()
+	if folders, ok := provider.(providers.FolderProvider); ok && item.FolderID != "" {
+		folders.SetFolder(item.FolderID)
+	}
+	return u.enqueue(item, provider, apiKey, false)
+}
+
+// enqueue проверяет источник и ставит загрузку в очередь
+// backupKey - провайдер создан с запасным API ключом
+func (u *Upload) enqueue(item queue.Item, provider providers.Provider, apiKey string, backupKey bool) (int, error) {
+	if err := u.validate(item, provider, apiKey); err != nil {
+		return 0, err
+	}
+	return u.add(item, provider, nil, nil, backupKey), nil
+}
+
+// validate проверяет источник и настройки провайдера
+func (u *Upload) validate(item queue.Item, provider providers.Provider, apiKey string) error {
+	if item.SourceURL != "" {
+		return upload.ValidateRemote(item.SourceURL, provider, apiKey)
+	}
+	if item.SourceURI != "" {
+		return upload.ValidateURI(item.SourceURI, provider, apiKey)
+	}
+	if _, err := upload.Validate(item.FilePath, provider, apiKey); err != nil {
+		// Слишком большой файл будет разрезан на части, а картинка может уложиться в лимит после уменьшения
+		if !errors.Is(err, upload.ErrFileTooLarge) || (u.partSize(provider) == 0 && !u.shrinksImage(item.FilePath)) {
+			return err
+		}
+	}
+	return nil
+}
+
+// add ставит проверенную загрузку в очередь, запускает очередь и возвращает идентификатор загрузки
+// group - альбом, в папку которого загружается файл (nil - обычная загрузка),
+// mirror - загрузки этого же файла в другие провайдеры, backupKey - провайдер создан с запасным API ключом
+func (u *Upload) add(item queue.Item, provider providers.Provider, group *album, mirror *mirrorGroup, backupKey bool) int {
+	id := u.push(item, provider, group, mirror, nil, backupKey)
+	u.schedule()
+	return id
+}
+
+// push ставит проверенную загрузку в очередь, не запуская очередь
+// mirrors - имена остальных провайдеров, в которые файл загружается вместе с этим
+func (u *Upload) push(item queue.Item, provider providers.Provider, group *album, mirror *mirrorGroup, mirrors []string, backupKey bool) int {
+	fileName := filepath.Base(item.FilePath)
+	if item.SourceURL != "" {
+		fileName = download.NameFromURL(item.SourceURL)
+	}
+	if item.SourceURI != "" {
+		fileName = uriName(item.SourceURI)
+	}
+
+	u.mu.Lock()
+	u.nextID++
+	sess := newSession(u.nextID, item.Provider, item.SourceURL)
+	sess.filePath = item.FilePath
+	sess.sourceURI = item.SourceURI
+	sess.album = group
+	sess.mirror = mirror
+	sess.mirrors = mirrors
+	if folders, ok := provider.(providers.FolderProvider); ok {
+		sess.folderID = folders.Folder()
+	}
+	sess.backupKey = backupKey
+	sess.skipScan = item.SkipScan
+	// run задается до регистрации: планировщик другой загрузки может сразу запустить эту
+	sess.run = func() { u.run(sess, provider, item) }
+	u.sessions[sess.id] = sess
+	u.mu.Unlock()
+
+	u.update(func(s *State) {
+		s.Jobs = append(s.Jobs, JobState{
+			ID:           sess.id,
+			Provider:     sess.provider,
+			FileName:     fileName,
+			Active:       true,
+			Phase:        PhaseQueued,
+			HighPriority: item.HighPriority,
+			source:       item,
+		})
+	})
+	u.saveQueue()
+	return sess.id
+}
+
+// SaveQueue сразу сохраняет очередь со снимком прогресса, не дожидаясь очередного снимка
+// Вызывается, когда приложение уходит в фон: мобильная ОС может завершить его без предупреждения
+func (u *Upload) SaveQueue() {
+	u.saveQueue()
+}
+
+// saveQueue сохраняет незавершенные загрузки в порядке очереди
+// Идущие загрузки сохраняются со снимком прогресса: после перезапуска загруженные части
+// пропускаются, а провайдеры с докачкой продолжают файл с подтвержденного места
+func (u *Upload) saveQueue() {
+	if u.pending == nil {
+		return
+	}
+
+	// Запись под u.mu, чтобы файл не перезаписало более старое состояние
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.closing {
+		return
+	}
+
+	var items []queue.Item
+	for _, job := range u.state.Jobs {
+		sess := u.sessions[job.ID]
+		if !job.Active || sess == nil {
+			continue
+		}
+		items = append(items, queue.Item{
+			FilePath:     sess.filePath,
+			SourceURL:    sess.sourceURL,
+			SourceURI:    sess.sourceURI,
+			Provider:     sess.provider,
+			HighPriority: job.HighPriority,
+			SkipScan:     sess.skipScan,
+			FolderID:     sess.folderID,
+			Progress:     sess.snapshot(),
+		})
+	}
+	if err := u.pending.Save(items); err != nil {
+		logging.ErrorWithError("Failed to save upload queue", err)
+	}
+}
+
+// snapshotQueue сохраняет очередь, если с прошлого снимка прошло не меньше snapshotInterval
+// Вызывается во время загрузки, чтобы после сбоя приложения ее можно было продолжить
+func (u *Upload) snapshotQueue(now time.Time) {
+	u.mu.Lock()
+	due := now.Sub(u.snapshotAt) >= snapshotInterval
+	if due {
+		u.snapshotAt = now
+	}
+	u.mu.Unlock()
+
+	if due {
+		u.saveQueue()
+	}
+}
+
+// schedule запускает загрузки из очереди, пока есть свободные места
+// Первыми выходят загрузки с высоким приоритетом, затем - в порядке очереди
+// Зеркала одного файла выходят вместе, даже сверх лимита: иначе общее чтение файла ждало бы их
+func (u *Upload) schedule() {
+	var ready []*session
+	u.update(func(s *State) {
+		// На паузе очередь стоит
+		if u.resume != nil {
+			return
+		}
+
+		running := 0
+		for _, sess := range u.sessions {
+			if sess.started {
+				running++
+			}
+		}
+
+		for u.maxConcurrent == 0 || running < u.maxConcurrent {
+			next := -1
+			for i, job := range s.Jobs {
+				sess := u.sessions[job.ID]
+				if job.Phase != PhaseQueued || sess == nil || sess.started {
+					continue
+				}
+				if job.HighPriority {
+					next = i
+					break
+				}
+				if next < 0 {
+					next = i
+				}
+			}
+			if next < 0 {
+				break
+			}
+
+			sess := u.sessions[s.Jobs[next].ID]
+			for i, job := range s.Jobs {
+				other := u.sessions[job.ID]
+				if i != next && (other == nil || sess.mirror == nil || other.mirror != sess.mirror || other.started || job.Phase != PhaseQueued) {
+					continue
+				}
+				other.started = true
+				s.Jobs[i].Phase = PhaseUploading
+				ready = append(ready, other)
+				running++
+			}
+		}
+	})
+
+	for _, sess := range ready {
+		sess.spawn(sess.run)
+		go u.supervise(sess)
+	}
+}
+
+// Cancel отменяет загрузку или убирает ее из очереди
+func (u *Upload) Cancel(id int) {
+	u.mu.Lock()
+	sess := u.sessions[id]
+	u.mu.Unlock()
+	if sess != nil {
+		u.cancel(sess, nil)
+	}
+}
+
+// CancelAll отменяет все идущие загрузки и очищает очередь
+func (u *Upload) CancelAll() {
+	u.mu.Lock()
+	sessions := make([]*session, 0, len(u.sessions))
+	for _, sess := range u.sessions {
+		sessions = append(sessions, sess)
+	}
+	u.mu.Unlock()
+
+	for _, sess := range sessions {
+		u.cancel(sess, nil)
+	}
+}
+
+// PauseAll ставит все загрузки на паузу
+// Очередь перестает запускать загрузки, а идущие останавливаются в точках паузы: перед следующей
+// частью или повтором. Загрузка одним запросом досылает текущий запрос
+func (u *Upload) PauseAll() {
+	u.update(func(s *State) {
+		if u.resume == nil {
+			u.resume = make(chan struct{})
+		}
+		s.Paused = true
+	})
+}
+
+// ResumeAll снимает паузу и запускает загрузки из очереди
+func (u *Upload) ResumeAll() {
+	u.update(func(s *State) {
+		if u.resume != nil {
+			close(u.resume)
+			u.resume = nil
+		}
+		s.Paused = false
+	})
+	u.schedule()
+}
+
+// waitUnpaused точка паузы загрузки sess: ждет снятия паузы, пока не отменен ctx
+// Реализует providers.PauseFunc; вызывается из горутин сессии, в том числе из нескольких частей сразу
+func (u *Upload) waitUnpaused(sess *session) providers.PauseFunc {
+	return func(ctx context.Context) error {
+		u.mu.Lock()
+		resume := u.resume
+		u.mu.Unlock()
+		if resume == nil {
+			return nil
+		}
+
+		sess.setPaused(true)
+		defer sess.setPaused(false)
+		select {
+		case <-resume:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Shutdown останавливает загрузки перед выходом из приложения
+// Незавершенные загрузки остаются в сохраненной очереди и предлагаются к продолжению при следующем запуске
+// Ждет, пока горутины загрузок завершатся (провайдеры убирают с сервера незавершенные загрузки,
+// успевшие закончиться попадают в историю), но не дольше timeout; false - не все успели остановиться
+func (u *Upload) Shutdown(timeout time.Duration) bool {
+	u.saveQueue()
+
+	u.mu.Lock()
+	u.closing = true
+	sessions := make([]*session, 0, len(u.sessions))
+	for _, sess := range u.sessions {
+		sessions = append(sessions, sess)
+	}
+	u.mu.Unlock()
+
+	// Провайдеры с докачкой оставляют незавершенную загрузку на сервере
+	for _, sess := range sessions {
+		u.cancel(sess, providers.ErrInterrupted)
+	}
+
+	deadline := time.After(timeout)
+	for _, sess := range sessions {
+		select {
+		case <-sess.done:
+		case <-deadline:
+			return false
+		}
+	}
+	return true
+}
+
+// cancel отменяет сессию; cause - причина отмены для провайдера (nil - отмена пользователем)
+// Загрузка из очереди не запускается: ее итог сразу публикует supervise
+func (u *Upload) cancel(sess *session, cause error) {
+	u.mu.Lock()
+	queued := !sess.started
+	sess.started = true
+	u.mu.Unlock()
+
+	if queued {
+		sess.finish(Completion{Err: providers.ErrCancelled})
+		go u.supervise(sess)
+		return
+	}
+	sess.stop(cause)
+}
+
+// Move передвигает ожидающую загрузку на offset позиций в очереди (отрицательный - ближе к началу)
+// Идущие и завершенные загрузки остаются на своих местах
+func (u *Upload) Move(id, offset int) {
+	u.update(func(s *State) {
+		var slots []int
+		from := -1
+		for i, job := range s.Jobs {
+			if job.Active && job.Phase == PhaseQueued {
+				if job.ID == id {
+					from = len(slots)
+				}
+				slots = append(slots, i)
+			}
+		}
+		if from < 0 {
+			return
+		}
+		to := min(max(from+offset, 0), len(slots)-1)
+
+		// Переставляем только ожидающие загрузки внутри их позиций в списке
+		queued := make([]JobState, len(slots))
+		for i, slot := range slots {
+			queued[i] = s.Jobs[slot]
+		}
+		job := queued[from]
+		queued = append(queued[:from], queued[from+1:]...)
+		queued = append(queued[:to], append([]JobState{job}, queued[to:]...)...)
+		for i, slot := range slots {
+			s.Jobs[slot] = queued[i]
+		}
+	})
+	u.saveQueue()
+}
+
+// SetHighPriority отмечает загрузку как срочную: она выйдет из очереди раньше обычных
+func (u *Upload) SetHighPriority(id int, high bool) {
+	u.updateJob(id, func(job *JobState) { job.HighPriority = high })
+	u.saveQueue()
+}
+
+// Retry снова ставит в очередь завершенную загрузку id, например после исправления API ключа
+// Загрузка начинается заново (файл альбома - отдельной загрузкой) и занимает место прежней в списке;
+// возвращается идентификатор новой загрузки
+func (u *Upload) Retry(id int, provider providers.Provider, apiKey string) (int, error) {
+	return u.retry(id, provider, apiKey, false)
+}
+
+// RetryWithoutScan повторяет загрузку id, которую остановила проверка на вирусы, уже без проверки
+func (u *Upload) RetryWithoutScan(id int, provider providers.Provider, apiKey string) (int, error) {
+	u.update(func(s *State) {
+		for i := range s.Jobs {
+			if s.Jobs[i].ID == id && !s.Jobs[i].Active {
+				s.Jobs[i].source.SkipScan = true
+			}
+		}
+	})
+	return u.retry(id, provider, apiKey, false)
+}
+
+// retry повторяет завершенную загрузку id на месте прежней; backupKey - провайдер с запасным ключом
+func (u *Upload) retry(id int, provider providers.Provider, apiKey string, backupKey bool) (int, error) {
+	u.mu.Lock()
+	job, ok := u.state.Job(id)
+	u.mu.Unlock()
+	if !ok || job.Active {
+		return 0, fmt.Errorf("upload %d is not finished", id)
+	}
+
+	item := job.source
+	item.Provider = provider.Name()
+	item.Progress = nil
+	newID, err := u.enqueue(item, provider, apiKey, backupKey)
+	if err != nil {
+		return 0, err
+	}
+	u.update(func(s *State) {
+		from, to := -1, -1
+		for i, job := range s.Jobs {
+			switch job.ID {
+			case id:
+				from = i
+			case newID:
+				to = i
+			}
+		}
+		if from >= 0 && to >= 0 {
+			s.Jobs[from] = s.Jobs[to]
+			s.Jobs = slices.Delete(s.Jobs, to, to+1)
+		}
+	})
+	u.saveQueue()
+	return newID, nil
+}
+
+// failover повторяет загрузку с запасным API ключом, если сервер отказал основному
+// true - загрузка повторяется, итог прежней попытки не публикуется
+func (u *Upload) failover(sess *session, c *Completion) bool {
+	u.mu.Lock()
+	backup := u.backup
+	u.mu.Unlock()
+	if backup == nil || sess.backupKey || !keyRejected(c.Err) {
+		return false
+	}
+	provider, apiKey, ok := backup(sess.provider)
+	if !ok {
+		return false
+	}
+	if _, err := u.retry(sess.id, provider, apiKey, true); err != nil {
+		logging.ErrorWithError("Failed to retry upload with the backup API key", err, "provider", sess.provider)
+		return false
+	}
+	return true
+}
+
+// keyRejected сообщает, что сервер отказал из-за API ключа: ключ неверный или исчерпал свой лимит
+func keyRejected(err error) bool {
+	var authErr *providers.AuthError
+	if errors.As(err, &authErr) {
+		return true
+	}
+	var quotaErr *providers.QuotaError
+	return errors.As(err, &quotaErr) && quotaErr.Reason == providers.QuotaRateLimit
+}
+
+// Dismiss убирает завершенную загрузку из списка
+func (u *Upload) Dismiss(id int) {
+	u.update(func(s *State) {
+		for i, job := range s.Jobs {
+			if job.ID == id && !job.Active {
+				s.Jobs = append(s.Jobs[:i], s.Jobs[i+1:]...)
+				return
+			}
+		}
+	})
+}
+
+// ClearFinished убирает из списка все завершенные загрузки
+func (u *Upload) ClearFinished() {
+	u.update(func(s *State) {
+		jobs := s.Jobs[:0]
+		for _, job := range s.Jobs {
+			if job.Active {
+				jobs = append(jobs, job)
+			}
+		}
+		s.Jobs = jobs
+	})
+}
+
+// Wait дожидается завершения всех идущих загрузок
+func (u *Upload) Wait() {
+	u.mu.Lock()
+	sessions := make([]*session, 0, len(u.sessions))
+	for _, sess := range u.sessions {
+		sessions = append(sessions, sess)
+	}
+	u.mu.Unlock()
+
+	for _, sess := range sessions {
+		<-sess.done
+	}
+}
+
+// supervise дожидается выхода всех горутин загрузки и публикует итог
+func (u *Upload) supervise(sess *session) {
+	sess.wait()
+	c := sess.result()
+	if c == nil {
+		c = &Completion{JobID: sess.id, Provider: sess.provider}
+	}
+
+	var total int64
+	u.mu.Lock()
+	delete(u.sessions, sess.id)
+	if job, ok := u.state.Job(sess.id); ok {
+		total = job.Total
+		if c.FileName == "" {
+			c.FileName = job.FileName
+		}
+	}
+	u.mu.Unlock()
+
+	u.updateJob(sess.id, func(job *JobState) {
+		job.Active = false
+		job.Phase = PhaseIdle
+		job.Stalled = false
+		job.WaitRemaining = 0
+		job.Outcome = c
+	})
+	close(sess.done)
+	u.saveQueue()
+
+	// Место освободилось - запускаем следующую загрузку из очереди
+	u.schedule()
+
+	u.recordMetrics(sess, c)
+	if c.Result != nil {
+		sess.span.SetAttributes(tracing.Int("file.size", c.Size))
+	}
+	sess.span.End(c.Err)
+
+	// Ключ отклонен или исчерпал лимит - вместо ошибки загрузка повторяется с запасным ключом
+	// (файлы альбома не повторяются: альбом уже собирает итоги своей группы)
+	if sess.album == nil && u.failover(sess, c) {
+		return
+	}
+
+	if sess.album != nil {
+		c.Album = sess.album.finish(c)
+	}
+	if c.Err == nil && c.Result == nil && c.Album == nil {
+		return
+	}
+
+	if c.Err != nil {
+		// Логируем ошибку с контекстом
+		logArgs := []any{
+			"provider", c.Provider,
+			"filename", c.FileName,
+			"filesize", total,
+		}
+		var httpErr *providers.HTTPError
+		if errors.As(c.Err, &httpErr) {
+			logArgs = append(logArgs, "status", httpErr.Status, "response", httpErr.Body)
+		}
+		logging.ErrorWithError("Upload failed", c.Err, logArgs...)
+	}
+
+	u.results <- *c
+}
+
+// recordMetrics учитывает итог загрузки в счетчиках endpoint метрик
+// Отмененные загрузки и dry run не учитываются
+func (u *Upload) recordMetrics(sess *session, c *Completion) {
+	switch {
+	case c.DryRun:
+	case c.Result != nil:
+		metrics.Default().UploadCompleted(sess.provider, c.Size, time.Since(sess.log.started()))
+	case c.Err != nil && !errors.Is(c.Err, providers.ErrCancelled):
+		metrics.Default().UploadFailed(sess.provider)
+	}
+}
+
+// run выполняет загрузку выбранного источника (горутина сессии)
+func (u *Upload) run(sess *session, provider providers.Provider, item queue.Item) {
+	sess.log.add("Upload to %s started", sess.provider)
+	if sess.backupKey {
+		sess.log.add("Using the backup API key: the server rejected the main key or its limit was reached")
+	}
+	// Запросы провайдера становятся дочерними span загрузки
+	sess.ctx, sess.span = tracing.Start(sess.ctx, "upload", tracing.String("provider.name", sess.provider))
+	if !httpclient.DryRunEnabled() {
+		metrics.Default().UploadStarted(sess.provider)
+	}
+
+	// Папка альбома создается до загрузки первого файла группы
+	if sess.album != nil {
+		if err := sess.album.open(sess.ctx); err != nil {
+			sess.finish(Completion{Err: fmt.Errorf("failed to create album folder: %w", err)})
+			return
+		}
+		sess.log.add("Uploading into album folder %s", sess.albumURL())
+	}
+
+	if item.SourceURI != "" {
+		u.copyAndUpload(sess, provider, item.SourceURI)
+		return
+	}
+	if item.SourceURL == "" {
+		u.uploadFile(sess, provider, item.FilePath, item.Progress)
+		return
+	}
+
+	// Провайдер сам скачивает файл по ссылке - данные не идут через наше соединение
+	if remote, ok := provider.(providers.RemoteUploader); ok {
+		u.uploadRemote(sess, remote, item.SourceURL)
+		return
+	}
+	u.fetchAndUpload(sess, provider, item.SourceURL)
+}
+
+// uploadFile загружает локальный файл (горутина сессии)
+// saved - снимок загрузки до перезапуска; используется, только если файл с тех пор не менялся
+func (u *Upload) uploadFile(sess *session, provider providers.Provider, path string, saved *queue.Progress) {
+	// Если загрузка не подключится к общему чтению файла, остальные зеркала ее не ждут
+	if sess.mirror != nil {
+		defer sess.mirror.leave(sess.provider)
+	}
+	if err := u.scanFile(sess, path); err != nil {
+		sess.finish(Completion{FileName: filepath.Base(path), Err: err})
+		return
+	}
+	path, err := u.prepareImage(sess, provider, path)
+	if err != nil {
+		sess.finish(Completion{FileName: filepath.Base(path), Err: err})
+		return
+	}
+	filename := filepath.Base(path)
+
+	sess.mu.Lock()
+	sess.uploadPath = path
+	sess.mu.Unlock()
+
+	// Открываем файл
+	file, err := os.Open(path)
+	if err != nil {
+		sess.finish(Completion{FileName: filename, Err: err})
+		return
+	}
+	defer file.Close()
+
+	// Получаем размер файла
+	fileInfo, err := file.Stat()
+	if err != nil {
+		sess.finish(Completion{FileName: filename, Err: err})
+		return
+	}
+	fileSize := fileInfo.Size()
+
+	resume := &queue.Progress{Size: fileSize, ModTime: fileInfo.ModTime()}
+	if saved != nil && saved.Matches(fileSize, fileInfo.ModTime()) {
+		resume.Parts = saved.Parts
+		resume.Checkpoint = saved.Checkpoint
+		sess.log.add("Resuming upload saved before restart")
+	}
+	sess.mu.Lock()
+	sess.resume = resume
+	sess.mu.Unlock()
+
+	mimeType, err := mimetype.DetectReader(file, filename)
+	if err != nil {
+		sess.finish(Completion{FileName: filename, Err: err})
+		return
+	}
+	sess.mu.Lock()
+	sess.mimeType = mimeType
+	sess.mu.Unlock()
+	sess.log.add("File %s, %s, %s", filename, providers.FormatSize(fileSize), mimeType)
+	if upload.MaybeTruncated(fileSize) {
+		sess.log.add("File size equals the FAT32 limit of 4 GB - 1 byte, the file may have been truncated when copied")
+	}
+
+	u.updateJob(sess.id, func(job *JobState) {
+		job.Phase = PhaseUploading
+		job.FileName = filename
+		job.Total = fileSize
+		job.Progress, job.Bytes = 0, 0
+	})
+
+	// Прогресс сохраняется в trackProgress и публикуется по тикеру в publishProgress
+	progressChan := make(chan providers.UploadProgress, 10)
+	done := make(chan struct{})
+	sess.spawn(func() { u.trackProgress(sess, progressChan) })
+	sess.spawn(func() { u.publishProgress(sess, fileSize, done) })
+
+	var result *providers.UploadResult
+	parts := upload.SplitParts(filename, fileSize, u.partSize(provider))
+	// Разрезанный файл, сжатое изображение и докачка после перезапуска читают файл сами
+	var shared io.Reader
+	if mirror := sess.mirror; mirror != nil && parts == nil && resume.Checkpoint == nil && path == mirror.path && mirror.expects(sess.provider) {
+		if r := mirror.join(sess, file, fileSize); r != nil {
+			sess.log.add("Reading the file once for all mirror uploads")
+			defer r.close()
+			shared = r
+		}
+	} else if mirror != nil {
+		mirror.leave(sess.provider)
+	}
+	if parts != nil {
+		sess.log.add("Split into %d files of up to %s", len(parts), providers.FormatSize(parts[0].Size))
+		result, err = u.uploadParts(sess, provider, file, fileSize, parts, progressChan)
+	} else {
+		result, err = u.send(sess, provider, file, filename, fileSize, shared, progressChan)
+	}
+
+	// Провайдер больше не пишет в канал - закрываем его и останавливаем публикацию
+	close(progressChan)
+	close(done)
+
+	u.complete(sess, filename, fileSize, result, err)
+}
+
+// send передает файл провайдеру (горутина сессии)
+// На ответ 429 загрузка откладывается и повторяется с начала файла, после обрыва связи - тоже;
+// провайдеры с докачкой продолжают с последнего подтвержденного места
+// shared - данные общего чтения файла зеркалами (nil - файл читается сам): ими идет только первая попытка
+func (u *Upload) send(sess *session, provider providers.Provider, file io.ReadSeeker, filename string, size int64, shared io.Reader, progress chan<- providers.UploadProgress) (*providers.UploadResult, error) {
+	// Последовательное чтение идет через буфер упреждения; части, загружаемые параллельно, читают файл напрямую
+	u.mu.Lock()
+	readAhead := u.readAhead
+	u.mu.Unlock()
+	if f, ok := file.(upload.File); ok && readAhead > 0 {
+		buffered := upload.NewReadAhead(f, readAhead)
+		defer buffered.Close()
+		file = buffered
+	}
+
+	pause := u.waitUnpaused(sess)
+	ctx := providers.WithPause(providers.WithCheckpoints(sess.ctx, sess.checkpoints(filename, u.saveQueue)), pause)
+	var result *providers.UploadResult
+	err := u.rateLimiter.Do(sess.ctx, sess.provider, func(d time.Duration) { u.waitForRateLimit(sess, d) }, func() error {
+		return u.retryOffline(sess, func() error {
+			// Точка паузы перед каждой попыткой и каждой частью разрезанного файла
+			if err := pause(sess.ctx); err != nil {
+				return providers.ErrCancelled
+			}
+			var body io.Reader = file
+			if shared != nil {
+				body, shared = shared, nil
+			} else if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			var err error
+			result, err = providers.UploadReader(ctx, provider, body, filename, size, progress)
+			return err
+		})
+	})
+	return result, err
+}
+
+// uploadParts загружает части файла по очереди отдельными файлами (горутина сессии)
+// Прогресс части пересчитывается в прогресс всего файла; итог ссылается на первую часть,
+// ссылки на все части сохраняются в сессии. Части, загруженные до перезапуска, не загружаются снова
+func (u *Upload) uploadParts(sess *session, provider providers.Provider, file *os.File, fileSize int64, parts []upload.Part, progress chan<- providers.UploadProgress) (*providers.UploadResult, error) {
+	links := make([]upload.PartLink, 0, len(parts))
+	for _, part := range parts {
+		if link, ok := sess.uploadedPart(part.Name); ok {
+			sess.log.add("File %s was uploaded before restart", part.Name)
+			links = append(links, link)
+			continue
+		}
+
+		partProgress := make(chan providers.UploadProgress, 10)
+		forwarded := make(chan struct{})
+		sess.spawn(func() {
+			defer close(forwarded)
+			for p := range partProgress {
+				p.BytesUploaded += part.Offset
+				p.TotalBytes = fileSize
+				p.Percentage = int(p.BytesUploaded * 100 / fileSize)
+				progress <- p
+			}
+		})
+
+		result, err := u.send(sess, provider, io.NewSectionReader(file, part.Offset, part.Size), part.Name, part.Size, nil, partProgress)
+		close(partProgress)
+		<-forwarded
+		if err != nil {
+			// Ссылки на уже загруженные части попадают в итог с ошибкой
+			if len(links) > 0 {
+				sess.mu.Lock()
+				sess.parts = links
+				sess.mu.Unlock()
+			}
+			return nil, fmt.Errorf("failed to upload part %s: %w", part.Name, err)
+		}
+		if result == nil {
+			return nil, nil
+		}
+		sess.log.add("File %s uploaded", part.Name)
+		link := upload.PartLink{Name: part.Name, URL: result.URL, DownloadURL: result.DownloadURL}
+		links = append(links, link)
+		sess.addPart(link)
+		u.saveQueue()
+	}
+
+	sess.mu.Lock()
+	sess.parts = links
+	sess.mu.Unlock()
+	return &providers.UploadResult{URL: links[0].URL, DownloadURL: links[0].DownloadURL}, nil
+}
+
+// scanFile проверяет файл на вирусы, если проверка включена (горутина сессии)
+// Загрузка не начинается, если найдена угроза или проверить файл не удалось:
+// пользователь может повторить ее без проверки из карточки
+func (u *Upload) scanFile(sess *session, path string) error {
+	u.mu.Lock()
+	scanner := u.scanner
+	u.mu.Unlock()
+	if scanner == nil || sess.skipScan {
+		return nil
+	}
+
+	u.updateJob(sess.id, func(job *JobState) {
+		job.Phase = PhaseScanning
+		job.FileName = filepath.Base(path)
+	})
+	// Зеркала одного файла проверяют его один раз
+	var result scan.Result
+	var err error
+	if sess.mirror != nil && path == sess.mirror.path {
+		result, err = sess.mirror.scan(sess.ctx, scanner)
+	} else {
+		result, err = scanner.Scan(sess.ctx, path)
+	}
+	if err != nil {
+		if sess.ctx.Err() != nil {
+			return sess.ctx.Err()
+		}
+		sess.log.add("%s scan failed: %v", scanner.Name(), err)
+		return &scan.FailedError{Scanner: scanner.Name(), Err: err}
+	}
+
+	switch {
+	case result.Threat != "":
+		sess.log.add("%s found %s, upload stopped", scanner.Name(), result.Threat)
+		return &scan.InfectedError{Scanner: scanner.Name(), Path: path, Threat: result.Threat}
+	case result.Unknown:
+		sess.log.add("%s has no report for this file", scanner.Name())
+	default:
+		sess.log.add("%s found no threats", scanner.Name())
+	}
+	return nil
+}
+
+// shrinksImage сообщает, будет ли файл обработан как картинка перед загрузкой
+func (u *Upload) shrinksImage(path string) bool {
+	u.mu.Lock()
+	opts := u.imageOptions
+	u.mu.Unlock()
+	return opts.Enabled && preprocess.Supported(path)
+}
+
+// prepareImage уменьшает и пережимает картинку, если это включено в настройках (горутина сессии)
+// Возвращает файл для загрузки: обработанную копию во временной папке сессии или исходный файл
+// Если картинку не удалось обработать, загружается исходный файл
+func (u *Upload) prepareImage(sess *session, provider providers.Provider, path string) (string, error) {
+	u.mu.Lock()
+	opts := u.imageOptions
+	u.mu.Unlock()
+	if !opts.Enabled || !preprocess.Supported(path) {
+		return path, nil
+	}
+
+	u.updateJob(sess.id, func(job *JobState) {
+		job.Phase = PhasePreparing
+		job.FileName = filepath.Base(path)
+	})
+
+	dir, err := sess.workDir("image")
+	if err != nil {
+		return path, err
+	}
+	prepared, err := preprocess.Image(path, dir, opts)
+	if err != nil {
+		logging.ErrorWithError("Failed to process image, uploading the original", err, "path", path)
+		sess.log.add("Image processing failed, uploading the original: %v", err)
+	} else if prepared != "" {
+		path = prepared
+		sess.log.add("Image shrunk before upload")
+	}
+
+	// Лимит провайдера для исходного файла пропущен в enqueue - проверяем результат
+	// (файл, который будет разрезан на части, под лимит подгонять не нужно)
+	info, err := os.Stat(path)
+	if err != nil {
+		return path, err
+	}
+	if limit := providers.GetCapabilities(provider).MaxFileSize; limit > 0 && info.Size() > limit && u.partSize(provider) == 0 {
+		return path, &upload.ValidationError{Err: upload.ErrFileTooLarge, Provider: provider.Name(), Path: path, Size: info.Size(), Limit: limit}
+	}
+	return path, nil
+}
+
+// uploadRemote передает ссылку провайдеру с поддержкой remote upload (горутина сессии)
+// Прогресса нет: файл скачивает сервер провайдера
+func (u *Upload) uploadRemote(sess *session, remote providers.RemoteUploader, sourceURL string) {
+	filename := download.NameFromURL(sourceURL)
+	u.updateJob(sess.id, func(job *JobState) {
+		job.Phase = PhaseRemote
+		job.FileName = filename
+	})
+
+	sess.log.add("Remote upload of %s requested", sourceURL)
+	var result *providers.UploadResult
+	err := u.rateLimiter.Do(sess.ctx, sess.provider, func(d time.Duration) { u.waitForRateLimit(sess, d) }, func() error {
+		return u.retryOffline(sess, func() error {
+			var err error
+			result, err = remote.UploadRemote(sess.ctx, sourceURL)
+			return err
+		})
+	})
+	u.complete(sess, filename, 0, result, err)
+}
+
+// fetchAndUpload скачивает файл по ссылке во временную папку и загружает его как локальный
+// (горутина сессии). Временная папка удаляется после выхода всех горутин сессии
+func (u *Upload) fetchAndUpload(sess *session, provider providers.Provider, sourceURL string) {
+	filename := download.NameFromURL(sourceURL)
+	u.updateJob(sess.id, func(job *JobState) {
+		job.Phase = PhaseFetching
+		job.FileName = filename
+	})
+
+	dir, err := os.MkdirTemp("", "multiUploader-remote-")
+	if err != nil {
+		sess.finish(Completion{FileName: filename, Err: fmt.Errorf("failed to create temporary folder: %w", err)})
+		return
+	}
+	sess.mu.Lock()
+	sess.tempDir = dir
+	sess.mu.Unlock()
+
+	progress := make(chan download.Progress, 10)
+	sess.spawn(func() {
+		for p := range progress {
+			u.updateJob(sess.id, func(job *JobState) {
+				job.Bytes, job.Total = p.Bytes, p.Total
+				job.Speed, job.AvgSpeed = p.Speed, p.Speed
+				if p.Total > 0 {
+					job.Progress = float64(p.Bytes) / float64(p.Total)
+				}
+			})
+		}
+	})
+
+	sess.log.add("Downloading %s", sourceURL)
+	// Заголовки провайдера не уходят на чужой адрес источника
+	fetched, err := download.Download(httpclient.WithProvider(sess.ctx, ""), httpclient.LongLived(), download.Request{URL: sourceURL, Dir: dir}, progress)
+	close(progress)
+	if err != nil {
+		sess.finish(Completion{FileName: filename, Err: err})
+		return
+	}
+	sess.log.add("Download finished")
+	// Тип файла по ссылке становится известен только после скачивания
+	if !providers.GetCapabilities(provider).AcceptsFile(fetched.Path) {
+		sess.finish(Completion{FileName: filepath.Base(fetched.Path), Err: &upload.ValidationError{Err: upload.ErrFileType, Provider: provider.Name(), Path: fetched.Path}})
+		return
+	}
+
+	u.updateJob(sess.id, func(job *JobState) { job.SpeedSamples, job.SampleCount = nil, 0 })
+	u.uploadFile(sess, provider, fetched.Path, nil)
+}
+
+// copyAndUpload копирует файл хранилища, которое не является локальной файловой системой,
+// во временную папку сессии и загружает копию (горутина сессии)
+// Провайдерам, проверке на вирусы и проверке целостности нужен локальный файл известного размера
+func (u *Upload) copyAndUpload(sess *session, provider providers.Provider, uri string) {
+	filename := uriName(uri)
+	u.updateJob(sess.id, func(job *JobState) {
+		job.Phase = PhaseFetching
+		job.FileName = filename
+	})
+
+	u.mu.Lock()
+	open := u.openURI
+	u.mu.Unlock()
+	if open == nil {
+		sess.finish(Completion{FileName: filename, Err: fmt.Errorf("cannot open %s: storage is not available", uri)})
+		return
+	}
+
+	sess.log.add("Copying %s to a temporary file", uri)
+	path, size, err := u.copyURI(sess, open, uri, filename)
+	if err != nil {
+		if sess.ctx.Err() != nil {
+			err = sess.ctx.Err()
+		}
+		sess.finish(Completion{FileName: filename, Err: err})
+		return
+	}
+	sess.log.add("Copy finished")
+
+	// Размер файла становится известен только после копирования
+	if size == 0 {
+		sess.finish(Completion{FileName: filename, Err: &upload.ValidationError{Err: upload.ErrEmptyFile, Provider: provider.Name(), Path: uri}})
+		return
+	}
+	if limit := providers.GetCapabilities(provider).MaxFileSize; limit > 0 && size > limit && u.partSize(provider) == 0 && !u.shrinksImage(path) {
+		sess.finish(Completion{FileName: filename, Err: &upload.ValidationError{Err: upload.ErrFileTooLarge, Provider: provider.Name(), Path: uri, Size: size, Limit: limit}})
+		return
+	}
+
+	u.updateJob(sess.id, func(job *JobState) { job.SpeedSamples, job.SampleCount = nil, 0 })
+	u.uploadFile(sess, provider, path, nil)
+}
+
+// copyURI копирует файл uri в папку сессии, публикуя скопированные байты
+// Возвращает путь к копии и ее размер
+func (u *Upload) copyURI(sess *session, open URIOpener, uri, filename string) (string, int64, error) {
+	source, err := open(uri)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open %s: %w", uri, err)
+	}
+	defer source.Close()
+
+	dir, err := sess.workDir("source")
+	if err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(dir, filename)
+	out, err := os.Create(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer out.Close()
+
+	// Чтение с устройства может идти долго: отмена проверяется между блоками
+	buf := make([]byte, 1<<20)
+	start := time.Now()
+	var copied int64
+	for {
+		if err := sess.ctx.Err(); err != nil {
+			return "", 0, err
+		}
+		n, err := source.Read(buf)
+		if n > 0 {
+			if _, werr := out.Write(buf[:n]); werr != nil {
+				return "", 0, fmt.Errorf("failed to write temporary file: %w", werr)
+			}
+			copied += int64(n)
+			speed := float64(copied) / max(time.Since(start).Seconds(), 0.001)
+			u.updateJob(sess.id, func(job *JobState) {
+				job.Bytes = copied
+				job.Speed, job.AvgSpeed = speed, speed
+			})
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to read %s: %w", uri, err)
+		}
+	}
+	if err := out.Close(); err != nil {
+		return "", 0, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	return path, copied, nil
+}
+
+// waitForRateLimit публикует паузу перед повтором после ответа 429
+func (u *Upload) waitForRateLimit(sess *session, delay time.Duration) {
+	sess.log.add("Rate limited by the provider, retrying in %s", roundDuration(delay))
+	sess.mu.Lock()
+	sess.waitUntil = time.Now().Add(delay)
+	// Повтор начнется с начала файла
+	sess.latestProgress = nil
+	sess.mu.Unlock()
+
+	u.updateJob(sess.id, func(job *JobState) {
+		job.Phase = PhaseWaiting
+		job.WaitRemaining = delay
+		job.Progress, job.Bytes = 0, 0
+		job.Speed, job.AvgSpeed = 0, 0
+	})
+}
+
+// retryOffline повторяет попытку, прерванную обрывом связи (горутина сессии)
+// Пока связи нет, загрузка ждет в PhaseOffline; если сеть есть, ошибка возвращается как есть
+// Загрузки частями переживают обрыв сами, сюда доходят только потоковые и служебные запросы
+func (u *Upload) retryOffline(sess *session, attempt func() error) error {
+	for i := 0; ; i++ {
+		err := attempt()
+		if err == nil || i >= maxOfflineRetries || sess.ctx.Err() != nil || !httpclient.IsNetworkError(err) {
+			return err
+		}
+
+		lost, waitErr := u.waitOnline(sess.ctx, func() { u.setOffline(sess, true) })
+		if waitErr != nil {
+			return fmt.Errorf("%w (%w)", waitErr, err)
+		}
+		if !lost {
+			return err
+		}
+		u.setOffline(sess, false)
+	}
+}
+
+// setOffline отмечает обрыв связи или ее возвращение
+func (u *Upload) setOffline(sess *session, offline bool) {
+	if offline {
+		sess.log.add("Connection lost, waiting for it to return")
+	} else {
+		sess.log.add("Connection restored, uploading again")
+	}
+	sess.mu.Lock()
+	sess.offline = offline
+	if offline {
+		// Повтор начнется с начала файла
+		sess.latestProgress = nil
+	}
+	sess.mu.Unlock()
+
+	if offline {
+		u.updateJob(sess.id, func(job *JobState) {
+			job.Phase = PhaseOffline
+			job.Progress, job.Bytes = 0, 0
+			job.Speed, job.AvgSpeed = 0, 0
+			job.Stalled = false
+		})
+	}
+}
+
+// trackProgress читает прогресс из канала и сохраняет его (без публикации)
+// Завершается, когда загрузка закрывает канал
+// По пути записывает в журнал передачи смену этапов и состояния частей
+func (u *Upload) trackProgress(sess *session, progressChan <-chan providers.UploadProgress) {
+	phase := providers.PhaseUploading
+	for progress := range progressChan {
+		progressCopy := progress
+		sess.setProgress(&progressCopy)
+
+		sess.log.observeParts(progress.Parts)
+		if progress.Phase == phase {
+			continue
+		}
+		switch progress.Phase {
+		case providers.PhaseFinalizing:
+			sess.log.add("All data sent, waiting for the server to finish")
+		case providers.PhaseOffline:
+			sess.log.add("Connection lost, waiting for it to return")
+		case providers.PhaseUploading:
+			sess.log.add("Connection restored, continuing")
+		}
+		phase = progress.Phase
+	}
+}
+
+// publishProgress публикует прогресс по тикеру, пока не закрыт done или не отменена сессия
+func (u *Upload) publishProgress(sess *session, totalSize int64, done <-chan struct{}) {
+	ticker := time.NewTicker(updateInterval)
+	defer ticker.Stop()
+
+	// Сглаженная скорость для ETA и определение зависания по всей передаче
+	monitor := providers.NewTransferMonitor()
+	monitor.Observe(0, time.Now())
+
+	ticks := 0
+	// paused загрузка стояла (пауза пользователя, пауза после 429 или обрыв связи)
+	paused := false
+
+	for {
+		select {
+		case <-done:
+			return
+
+		case <-sess.ctx.Done():
+			return
+
+		case now := <-ticker.C:
+			ticks++
+			sample := ticks%sampleTicks == 0
+			u.snapshotQueue(now)
+
+			progress, waitUntil, offline := sess.progress()
+
+			// Пользователь поставил загрузки на паузу - ждем ее снятия
+			if sess.isPaused() {
+				u.updateJob(sess.id, func(job *JobState) {
+					job.Phase = PhasePaused
+					job.Speed = 0
+					job.Stalled = false
+				})
+				paused = true
+				continue
+			}
+
+			// Провайдер ограничил частоту запросов - ждем повтора
+			if remaining := waitUntil.Sub(now); remaining > 0 {
+				u.updateJob(sess.id, func(job *JobState) {
+					job.Phase = PhaseWaiting
+					job.WaitRemaining = remaining
+				})
+				paused = true
+				continue
+			}
+
+			// Связь пропала - ждем ее возвращения
+			if offline || (progress != nil && progress.Phase == providers.PhaseOffline) {
+				u.updateJob(sess.id, func(job *JobState) {
+					job.Phase = PhaseOffline
+					job.Stalled = false
+				})
+				paused = true
+				continue
+			}
+
+			if paused {
+				// Передача продолжается после паузы - скорость и зависание считаем заново
+				// (с нуля после повтора или с уже отправленных байт после обрыва при загрузке частями)
+				paused = false
+				var sent int64
+				if progress != nil {
+					sent = progress.BytesUploaded
+				}
+				monitor = providers.NewTransferMonitor()
+				monitor.Observe(sent, now)
+				u.updateJob(sess.id, func(job *JobState) { job.Phase = PhaseUploading })
+			}
+
+			if progress == nil {
+				if monitor.Stalled(now) {
+					u.updateJob(sess.id, func(job *JobState) { job.Stalled = true })
+				}
+				continue
+			}
+
+			// Данные отправлены, сервер собирает файл - скорость и ETA уже не имеют смысла
+			if progress.Phase == providers.PhaseFinalizing {
+				u.updateJob(sess.id, func(job *JobState) {
+					job.Phase = PhaseFinalizing
+					job.Progress = float64(progress.Percentage) / 100
+				})
+				continue
+			}
+
+			monitor.Observe(progress.BytesUploaded, now)
+			stalled := monitor.Stalled(now)
+			avgSpeed := monitor.Speed()
+
+			u.updateJob(sess.id, func(job *JobState) {
+				job.Phase = PhaseUploading
+				job.Progress = float64(progress.Percentage) / 100
+				job.Bytes = progress.BytesUploaded
+				job.Total = totalSize
+				job.Speed = progress.Speed
+				job.AvgSpeed = avgSpeed
+				job.Stalled = stalled
+				job.Parts = progress.Parts
+
+				if sample {
+					// Данные давно не передаются - на графике провал
+					speed := progress.Speed
+					if stalled {
+						speed = 0
+					}
+					job.SpeedSamples = append(job.SpeedSamples, speed)
+					if len(job.SpeedSamples) > maxSpeedSamples {
+						job.SpeedSamples = job.SpeedSamples[len(job.SpeedSamples)-maxSpeedSamples:]
+					}
+					job.SampleCount++
+				}
+			})
+		}
+	}
+}
+
+// complete проверяет и сохраняет успешную загрузку, затем запоминает итог (горутина сессии)
+func (u *Upload) complete(sess *session, filename string, totalSize int64, result *providers.UploadResult, err error) {
+	if err != nil || result == nil {
+		sess.finish(Completion{FileName: filename, Parts: sess.partLinks(), Err: err})
+		return
+	}
+
+	elapsed := time.Since(sess.log.started())
+	if totalSize > 0 && elapsed > 0 {
+		sess.log.add("Upload finished in %s, average %s", roundDuration(elapsed), providers.FormatSpeed(float64(totalSize)/elapsed.Seconds()))
+	} else {
+		sess.log.add("Upload finished in %s", roundDuration(elapsed))
+	}
+
+	// В dry run ссылки ненастоящие: не проверяем их и не сохраняем в историю
+	dryRun := httpclient.DryRunEnabled()
+	var verification *upload.Verification
+	if !dryRun {
+		verification = u.verify(sess, filename, result)
+		u.recordHistory(sess, filename, totalSize, result, verification)
+	}
+
+	sess.finish(Completion{
+		FileName:      filename,
+		Size:          totalSize,
+		MIMEType:      sess.fileType(),
+		Parts:         sess.partLinks(),
+		CollectionURL: sess.albumURL(),
+		Result:        result,
+		Verification:  verification,
+		DryRun:        dryRun,
+	})
+}
+
+// verify сравнивает загруженный файл с локальным
+// Возвращает nil, если локальный файл не удалось прочитать, его нет (remote upload)
+// или он загружен частями (ссылки ведут на части, а не на весь файл)
+func (u *Upload) verify(sess *session, filename string, result *providers.UploadResult) *upload.Verification {
+	path := sess.path()
+	if path == "" || sess.partLinks() != nil {
+		return nil
+	}
+
+	u.updateJob(sess.id, func(job *JobState) { job.Phase = PhaseVerifying })
+
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+
+	// Хеши файла, который зеркала прочитали вместе, уже посчитаны
+	var v upload.Verification
+	var err error
+	if hashes, ok := sess.mirrorHashes(path); ok {
+		v = upload.VerifyHashes(ctx, httpclient.Default(), hashes, result)
+	} else {
+		v, err = upload.Verify(ctx, httpclient.Default(), path, result)
+	}
+	if err != nil {
+		logging.ErrorWithError("Upload verification failed", err, "filename", filename)
+		sess.log.add("Integrity check failed: %v", err)
+		return nil
+	}
+	if v.Detail != "" {
+		sess.log.add("Integrity check: %s (%s)", v.Status, v.Detail)
+	} else {
+		sess.log.add("Integrity check: %s", v.Status)
+	}
+
+	if v.Status == upload.VerifyMismatch {
+		logging.Error("Uploaded file does not match local file",
+			"provider", sess.provider,
+			"filename", filename,
+			"detail", v.Detail,
+		)
+	}
+	return &v
+}
+
+// recordHistory добавляет успешную загрузку в историю
+func (u *Upload) recordHistory(sess *session, filename string, totalSize int64, result *providers.UploadResult, verification *upload.Verification) {
+	if u.history == nil {
+		return
+	}
+
+	sourceURL := sess.sourceURL
+	if sess.sourceURI != "" {
+		sourceURL = sess.sourceURI
+	}
+	entry := history.Entry{
+		FileName:      filename,
+		SourceURL:     sourceURL,
+		Size:          totalSize,
+		MIMEType:      sess.fileType(),
+		Parts:         sess.partLinks(),
+		CollectionURL: sess.albumURL(),
+		FolderID:      sess.folderID,
+		Mirrors:       sess.mirrors,
+		Provider:      sess.provider,
+		URL:           result.URL,
+		DownloadURL:   result.DownloadURL,
+		DeleteURL:     result.DeleteURL,
+		Log:           sess.log.entries(),
+	}
+	// Путь к временной копии файла, скачанного по ссылке, в истории не нужен
+	if sess.sourceURL == "" {
+		entry.FilePath = sess.filePath
+	}
+	if verification != nil {
+		entry.SHA256 = verification.SHA256
+		entry.Integrity = verification.Status
+		entry.IntegrityDetail = verification.Detail
+	}
+
+	if _, err := u.history.Add(entry); err != nil {
+		logging.ErrorWithError("Failed to save upload history", err)
+	}
+}
@@ -0,0 +1,1251 @@
+package viewmodel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"multiUploader/internal/history"
+	"multiUploader/internal/httpclient"
+	"multiUploader/internal/preprocess"
+	"multiUploader/internal/providers"
+	"multiUploader/internal/queue"
+	"multiUploader/internal/scan"
+	"multiUploader/internal/upload"
+)
+
+// fakeProvider отдает прогресс и результат без сети
+// Если block не nil, загрузка ждет отмены
+type fakeProvider struct {
+	block chan struct{}
+}
+
+func (p *fakeProvider) Name() string { return "Fake" }
+
+func (p *fakeProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- providers.UploadProgress) (*providers.UploadResult, error) {
+	progress <- providers.UploadProgress{BytesUploaded: fileSize / 2, TotalBytes: fileSize, Percentage: 50}
+	if p.block != nil {
+		close(p.block)
+		<-ctx.Done()
+		return nil, providers.ErrCancelled
+	}
+	progress <- providers.UploadProgress{BytesUploaded: fileSize, TotalBytes: fileSize, Percentage: 100}
+	return &providers.UploadResult{URL: "https://example.invalid/" + filename}, nil
+}
+
+func (p *fakeProvider) RequiresAuth() bool                 { return false }
+func (p *fakeProvider) ValidateAPIKey(apiKey string) error { return nil }
+
+// flakyProvider обрывает соединение на первых попытках
+type flakyProvider struct {
+	fakeProvider
+	failures int
+}
+
+func (p *flakyProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- providers.UploadProgress) (*providers.UploadResult, error) {
+	if p.failures > 0 {
+		p.failures--
+		return nil, &net.OpError{Op: "write", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+	return p.fakeProvider.Upload(ctx, file, filename, fileSize, progress)
+}
+
+// resumableProvider сообщает точку продолжения и ждет отмены, как провайдер с докачкой
+type resumableProvider struct {
+	fakeProvider
+	// from точка, с которой начата загрузка
+	from *providers.Checkpoint
+	// cause причина отмены загрузки
+	cause error
+}
+
+func (p *resumableProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- providers.UploadProgress) (*providers.UploadResult, error) {
+	saved := providers.CheckpointsFrom(ctx)
+	p.from = saved.Load()
+	saved.Save(providers.Checkpoint{Upload: "https://example.invalid/uploads/1", Offset: fileSize / 2})
+	result, err := p.fakeProvider.Upload(ctx, file, filename, fileSize, progress)
+	p.cause = context.Cause(ctx)
+	return result, err
+}
+
+// rejectingProvider отклоняет ключ, как сервер с ответом 401
+type rejectingProvider struct {
+	fakeProvider
+}
+
+func (p *rejectingProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- providers.UploadProgress) (*providers.UploadResult, error) {
+	return nil, &providers.AuthError{Err: errors.New("401 Unauthorized")}
+}
+
+// limitedProvider принимает файлы не больше maxSize
+type limitedProvider struct {
+	fakeProvider
+	maxSize int64
+	// failFile имя файла, загрузка которого отклоняется
+	failFile string
+}
+
+func (p *limitedProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- providers.UploadProgress) (*providers.UploadResult, error) {
+	if filename == p.failFile {
+		return nil, &providers.ServerError{Op: "upload", Message: "storage is full"}
+	}
+	return p.fakeProvider.Upload(ctx, file, filename, fileSize, progress)
+}
+
+func (p *limitedProvider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{MaxFileSize: p.maxSize}
+}
+
+// albumProvider создает папки для альбомов и запоминает, в какую папку ушел каждый файл
+type albumProvider struct {
+	fakeProvider
+	mu      sync.Mutex
+	created int
+	folder  string
+	folders map[string]string
+}
+
+func (p *albumProvider) ListFolders(ctx context.Context) ([]providers.Folder, error) { return nil, nil }
+
+func (p *albumProvider) SetFolder(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.folder = id
+}
+
+func (p *albumProvider) Folder() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.folder
+}
+
+func (p *albumProvider) CreateCollection(ctx context.Context, name string) (*providers.Collection, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.created++
+	return &providers.Collection{ID: "42", URL: "https://example.invalid/folder/42"}, nil
+}
+
+func (p *albumProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- providers.UploadProgress) (*providers.UploadResult, error) {
+	p.mu.Lock()
+	p.folders[filename] = p.folder
+	p.mu.Unlock()
+	return p.fakeProvider.Upload(ctx, file, filename, fileSize, progress)
+}
+
+// tempFile создает файл для загрузки
+func tempFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(path, make([]byte, 4096), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// waitResult ждет итог загрузки
+func waitResult(t *testing.T, u *Upload) Completion {
+	t.Helper()
+	select {
+	case c := <-u.Results():
+		return c
+	case <-time.After(5 * time.Second):
+		t.Fatal("upload did not finish")
+		return Completion{}
+	}
+}
+
+// TestUploadCompletes проверяет успешную загрузку и запись в историю
+func TestUploadCompletes(t *testing.T) {
+	store := history.NewInMemory()
+	u := NewUpload(upload.NewRateLimiter(), store, nil)
+	path := tempFile(t)
+
+	if u.CanStart() {
+		t.Fatal("CanStart() = true without file and provider")
+	}
+	u.SelectProvider("Fake")
+	u.SelectFile(path)
+	if !u.CanStart() {
+		t.Fatal("CanStart() = false with file and provider")
+	}
+
+	id, err := u.Start(&fakeProvider{}, "")
+	if err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+
+	c := waitResult(t, u)
+	if c.Err != nil || c.Result == nil || c.JobID != id || c.FileName != "file.bin" || c.Provider != "Fake" {
+		t.Fatalf("Completion = %+v, want successful upload of file.bin", c)
+	}
+	if c.MIMEType != "application/octet-stream" {
+		t.Errorf("MIMEType = %q, want application/octet-stream", c.MIMEType)
+	}
+	if c.Verification == nil || c.Verification.Status != upload.VerifyUnavailable {
+		t.Errorf("Verification = %+v, want unavailable", c.Verification)
+	}
+
+	job, ok := u.State().Job(id)
+	if !ok || job.Active || job.Outcome == nil || job.Outcome.Result == nil {
+		t.Errorf("Job(%d) after upload = %+v, want finished with result", id, job)
+	}
+
+	entries := store.Entries()
+	if len(entries) != 1 || entries[0].FilePath != path || entries[0].Size != 4096 || entries[0].MIMEType != c.MIMEType {
+		t.Errorf("history = %+v, want one entry for %s", entries, path)
+	}
+
+	// Журнал передачи сохраняется с записью истории
+	if len(entries) == 1 {
+		log := entries[0].Log
+		if len(log) < 3 || log[0].Message != "Upload to Fake started" || !strings.HasPrefix(log[len(log)-1].Message, "Integrity check: unverified") {
+			t.Errorf("transfer log = %+v, want start, file and integrity events", log)
+		}
+	}
+}
+
+// TestUploadFileNames проверяет, что каждая локальная загрузка получает имя своего файла,
+// а не имя предыдущей загрузки
+func TestUploadFileNames(t *testing.T) {
+	store := history.NewInMemory()
+	u := NewUpload(upload.NewRateLimiter(), store, nil)
+	dir := t.TempDir()
+
+	for _, name := range []string{"first.txt", "second.zip"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		u.SelectFile(path)
+		if _, err := u.Start(&fakeProvider{}, ""); err != nil {
+			t.Fatalf("Start(%s) = %v", name, err)
+		}
+		c := waitResult(t, u)
+		if c.FileName != name || c.Result == nil || c.Result.URL != "https://example.invalid/"+name {
+			t.Errorf("Completion = %+v, want upload of %s", c, name)
+		}
+		if entries := store.Entries(); len(entries) == 0 || entries[0].FileName != name {
+			t.Errorf("latest history entry = %+v, want %s", entries, name)
+		}
+	}
+}
+
+// TestUploadConcurrent проверяет независимые одновременные загрузки и отмену одной из них
+func TestUploadConcurrent(t *testing.T) {
+	u := NewUpload(upload.NewRateLimiter(), nil, nil)
+	u.SelectProvider("Fake")
+	u.SelectFile(tempFile(t))
+
+	started := make(chan struct{})
+	blocked, err := u.Start(&fakeProvider{block: started}, "")
+	if err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	<-started
+
+	if n := u.State().Active(); n != 1 {
+		t.Fatalf("Active() = %d during upload, want 1", n)
+	}
+
+	// Вторая загрузка идет параллельно с первой и завершается независимо
+	second, err := u.Start(&fakeProvider{}, "")
+	if err != nil {
+		t.Fatalf("second Start() = %v", err)
+	}
+	if c := waitResult(t, u); c.JobID != second || c.Err != nil {
+		t.Fatalf("Completion = %+v, want success of job %d", c, second)
+	}
+	if job, _ := u.State().Job(blocked); !job.Active {
+		t.Fatal("first upload finished together with the second")
+	}
+
+	u.Cancel(blocked)
+	if c := waitResult(t, u); c.JobID != blocked || !errors.Is(c.Err, providers.ErrCancelled) {
+		t.Errorf("Completion = %+v, want ErrCancelled for job %d", c, blocked)
+	}
+	if n := u.State().Active(); n != 0 {
+		t.Errorf("Active() = %d after cancel, want 0", n)
+	}
+
+	u.ClearFinished()
+	if jobs := u.State().Jobs; len(jobs) != 0 {
+		t.Errorf("Jobs after ClearFinished() = %+v, want none", jobs)
+	}
+}
+
+// TestUploadValidation проверяет, что ошибка проверки файла возвращается сразу
+func TestUploadValidation(t *testing.T) {
+	u := NewUpload(upload.NewRateLimiter(), nil, nil)
+	u.SelectProvider("Fake")
+	u.SelectFile(filepath.Join(t.TempDir(), "missing.bin"))
+
+	if _, err := u.Start(&fakeProvider{}, ""); err == nil {
+		t.Fatal("Start() with missing file succeeded")
+	}
+	if jobs := u.State().Jobs; len(jobs) != 0 {
+		t.Errorf("Jobs after failed validation = %+v, want none", jobs)
+	}
+}
+
+// TestUploadRestartDoesNotLeak проверяет, что быстрые старт и отмена не оставляют горутин
+func TestUploadRestartDoesNotLeak(t *testing.T) {
+	u := NewUpload(upload.NewRateLimiter(), nil, nil)
+	u.SelectProvider("Fake")
+	u.SelectFile(tempFile(t))
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		started := make(chan struct{})
+		id, err := u.Start(&fakeProvider{block: started}, "")
+		if err != nil {
+			t.Fatalf("Start() #%d = %v", i, err)
+		}
+		<-started
+		u.Cancel(id)
+		u.Cancel(id) // повторная отмена безопасна
+		if c := waitResult(t, u); !errors.Is(c.Err, providers.ErrCancelled) {
+			t.Fatalf("Completion.Err #%d = %v, want ErrCancelled", i, c.Err)
+		}
+		u.Wait()
+	}
+
+	// Горутины сессий завершаются до публикации итога
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("goroutines = %d after restarts, want at most %d", n, before)
+	}
+}
+
+// TestUploadQueue проверяет ограничение одновременных загрузок, приоритет и порядок очереди
+func TestUploadQueue(t *testing.T) {
+	u := NewUpload(upload.NewRateLimiter(), nil, nil)
+	u.SetMaxConcurrent(1)
+	u.SelectProvider("Fake")
+	u.SelectFile(tempFile(t))
+
+	started := make(chan struct{})
+	running, err := u.Start(&fakeProvider{block: started}, "")
+	if err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	<-started
+
+	ids := make([]int, 3)
+	for i := range ids {
+		if ids[i], err = u.Start(&fakeProvider{}, ""); err != nil {
+			t.Fatalf("Start() #%d = %v", i, err)
+		}
+	}
+	if n := u.State().Queued(); n != 3 {
+		t.Fatalf("Queued() = %d, want 3", n)
+	}
+
+	// Порядок очереди: ids[2], ids[0], ids[1]; ids[1] срочная и выходит первой
+	u.Move(ids[2], -5)
+	u.SetHighPriority(ids[1], true)
+
+	var order []int
+	for _, job := range u.State().Jobs {
+		order = append(order, job.ID)
+	}
+	if want := []int{running, ids[2], ids[0], ids[1]}; !slices.Equal(order, want) {
+		t.Fatalf("Jobs order = %v, want %v", order, want)
+	}
+
+	// Отмена из очереди не ждет освобождения места
+	u.Cancel(ids[0])
+	if c := waitResult(t, u); c.JobID != ids[0] || !errors.Is(c.Err, providers.ErrCancelled) {
+		t.Fatalf("Completion = %+v, want ErrCancelled for queued job %d", c, ids[0])
+	}
+
+	u.Cancel(running)
+	var finished []int
+	for range 3 {
+		finished = append(finished, waitResult(t, u).JobID)
+	}
+	if want := []int{running, ids[1], ids[2]}; !slices.Equal(finished, want) {
+		t.Errorf("finish order = %v, want %v", finished, want)
+	}
+}
+
+// TestUploadPauseAll проверяет, что на паузе очередь не запускает загрузки, а после нее они завершаются
+func TestUploadPauseAll(t *testing.T) {
+	u := NewUpload(upload.NewRateLimiter(), nil, nil)
+	u.SelectProvider("Fake")
+	u.SelectFile(tempFile(t))
+
+	u.PauseAll()
+	if !u.State().Paused {
+		t.Fatal("State().Paused = false after PauseAll")
+	}
+	id, err := u.Start(&fakeProvider{}, "")
+	if err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+
+	select {
+	case c := <-u.Results():
+		t.Fatalf("upload finished while paused: %+v", c)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if job, _ := u.State().Job(id); job.Phase != PhaseQueued {
+		t.Fatalf("Phase = %v while paused, want PhaseQueued", job.Phase)
+	}
+
+	u.ResumeAll()
+	if c := waitResult(t, u); c.JobID != id || c.Err != nil {
+		t.Fatalf("Completion = %+v, want success", c)
+	}
+	if u.State().Paused {
+		t.Error("State().Paused = true after ResumeAll")
+	}
+}
+
+// TestUploadPausePoint проверяет, что загрузка ждет в точке паузы и отменяется из нее
+func TestUploadPausePoint(t *testing.T) {
+	u := NewUpload(upload.NewRateLimiter(), nil, nil)
+	sess := newSession(1, "Fake", "")
+	wait := u.waitUnpaused(sess)
+
+	if err := wait(sess.ctx); err != nil {
+		t.Fatalf("wait() without pause = %v", err)
+	}
+
+	u.PauseAll()
+	done := make(chan error, 1)
+	go func() { done <- wait(sess.ctx) }()
+	deadline := time.Now().Add(5 * time.Second)
+	for !sess.isPaused() {
+		if time.Now().After(deadline) {
+			t.Fatal("session did not stop at the pause point")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	sess.cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("wait() after cancel = %v, want context.Canceled", err)
+	}
+	if sess.isPaused() {
+		t.Error("isPaused() = true after the wait returned")
+	}
+}
+
+// TestUploadRetry проверяет повтор загрузки, отклоненной из-за ключа: новая загрузка занимает место прежней
+func TestUploadRetry(t *testing.T) {
+	u := NewUpload(upload.NewRateLimiter(), nil, nil)
+	u.SelectProvider("Fake")
+	u.SelectFile(tempFile(t))
+
+	id, err := u.Start(&rejectingProvider{}, "old")
+	if err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	var authErr *providers.AuthError
+	if c := waitResult(t, u); !errors.As(c.Err, &authErr) {
+		t.Fatalf("Completion.Err = %v, want AuthError", c.Err)
+	}
+	other, err := u.Start(&fakeProvider{}, "")
+	if err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	waitResult(t, u)
+
+	retried, err := u.Retry(id, &fakeProvider{}, "new")
+	if err != nil {
+		t.Fatalf("Retry() = %v", err)
+	}
+	if c := waitResult(t, u); c.JobID != retried || c.Err != nil {
+		t.Fatalf("Completion = %+v, want success of job %d", c, retried)
+	}
+
+	var order []int
+	for _, job := range u.State().Jobs {
+		order = append(order, job.ID)
+	}
+	if want := []int{retried, other}; !slices.Equal(order, want) {
+		t.Errorf("Jobs order = %v, want %v", order, want)
+	}
+	if _, err := u.Retry(retried+1, &fakeProvider{}, ""); err == nil {
+		t.Error("Retry() of an unknown upload succeeded")
+	}
+}
+
+// fakeScanner антивирус, который находит угрозу в каждом файле
+type fakeScanner struct {
+	// clean файл без угроз
+	clean bool
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *fakeScanner) Name() string { return "FakeAV" }
+
+func (s *fakeScanner) Scan(ctx context.Context, path string) (scan.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.clean {
+		return scan.Result{}, nil
+	}
+	return scan.Result{Threat: "EICAR-Test"}, nil
+}
+
+// TestUploadScan проверяет, что зараженный файл не загружается, а повтор без проверки загружает его
+func TestUploadScan(t *testing.T) {
+	u := NewUpload(upload.NewRateLimiter(), nil, nil)
+	scanner := &fakeScanner{}
+	u.SetScanner(scanner)
+	u.SelectProvider("Fake")
+	u.SelectFile(tempFile(t))
+
+	provider := &fakeProvider{}
+	id, err := u.Start(provider, "")
+	if err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	var infected *scan.InfectedError
+	if c := waitResult(t, u); !errors.As(c.Err, &infected) || infected.Threat != "EICAR-Test" || c.Result != nil {
+		t.Fatalf("Completion = %+v, want InfectedError without upload", c)
+	}
+
+	if _, err := u.RetryWithoutScan(id, provider, ""); err != nil {
+		t.Fatalf("RetryWithoutScan() = %v", err)
+	}
+	if c := waitResult(t, u); c.Err != nil || c.Result == nil {
+		t.Fatalf("Completion = %+v after retry without scan, want success", c)
+	}
+	if scanner.calls != 1 {
+		t.Errorf("file scanned %d times, want once", scanner.calls)
+	}
+}
+
+// streamProvider потоковый провайдер: запоминает полученные данные и то, чем они были прочитаны
+type streamProvider struct {
+	fakeProvider
+	name string
+	// wait закрывается, когда провайдеру можно начать чтение (медленный сервер)
+	wait chan struct{}
+	// done закрывается, когда провайдер прочитал все данные
+	done chan struct{}
+
+	got    []byte
+	reader io.Reader
+}
+
+func (p *streamProvider) Name() string { return p.name }
+
+func (p *streamProvider) UploadStream(ctx context.Context, file io.Reader, filename string, fileSize int64, progress chan<- providers.UploadProgress) (*providers.UploadResult, error) {
+	if p.wait != nil {
+		<-p.wait
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	p.got, p.reader = data, file
+	if p.done != nil {
+		close(p.done)
+	}
+	return &providers.UploadResult{URL: "https://example.invalid/" + p.name}, nil
+}
+
+// partsProvider загружает файл частями по 2 МБ, читая их параллельно через io.ReaderAt
+type partsProvider struct {
+	fakeProvider
+
+	got  []byte
+	file io.ReadSeeker
+}
+
+func (p *partsProvider) Name() string { return "Parts" }
+
+func (p *partsProvider) Upload(ctx context.Context, file io.ReadSeeker, filename string, fileSize int64, progress chan<- providers.UploadProgress) (*providers.UploadResult, error) {
+	const partSize = 2 * mirrorChunkSize
+	readerAt, ok := file.(io.ReaderAt)
+	if !ok {
+		return nil, errors.New("file is not an io.ReaderAt")
+	}
+	data := make([]byte, fileSize)
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	sem := make(chan struct{}, 4)
+	for off := int64(0); off < fileSize; off += partSize {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			if _, err := readerAt.ReadAt(data[off:min(off+partSize, fileSize)], off); err != nil && err != io.EOF {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	p.got, p.file = data, file
+	return &providers.UploadResult{URL: "https://example.invalid/" + p.Name()}, nil
+}
+
+// TestUploadMirrors проверяет, что зеркала одного файла выходят из очереди вместе, потоковые провайдеры
+// и провайдеры с параллельными частями получают его из одного чтения, а проверка на вирусы и хеши общие.
+// Отставшее зеркало дочитывает файл само
+func TestUploadMirrors(t *testing.T) {
+	oldStall := mirrorStallTimeout
+	mirrorStallTimeout = 20 * time.Millisecond
+	t.Cleanup(func() { mirrorStallTimeout = oldStall })
+
+	data := make([]byte, 20*mirrorChunkSize+123)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	wantHashes := upload.NewHasher()
+	wantHashes.Write(data)
+
+	store := history.NewInMemory()
+	u := NewUpload(upload.NewRateLimiter(), store, nil)
+	u.SetMaxConcurrent(1)
+	scanner := &fakeScanner{clean: true}
+	u.SetScanner(scanner)
+	u.SelectFile(path)
+
+	// Медленное зеркало начинает читать, только когда быстрое уже получило весь файл
+	fast := &streamProvider{name: "Fast", done: make(chan struct{})}
+	slow := &streamProvider{name: "Slow", wait: fast.done}
+	parts := &partsProvider{}
+	targets := []Target{{Provider: fast}, {Provider: slow}, {Provider: parts}}
+	if _, err := u.StartMirrors(targets); err != nil {
+		t.Fatalf("StartMirrors() = %v", err)
+	}
+
+	for range targets {
+		c := waitResult(t, u)
+		if c.Err != nil || c.Result == nil {
+			t.Fatalf("Completion = %+v, want success", c)
+		}
+		if c.Verification == nil || c.Verification.SHA256 != wantHashes.Hashes().SHA256 {
+			t.Errorf("%s: verification = %+v, want hashes of the file", c.Provider, c.Verification)
+		}
+	}
+
+	for _, p := range []*streamProvider{fast, slow} {
+		if !bytes.Equal(p.got, data) {
+			t.Errorf("%s received %d bytes, want the whole file", p.name, len(p.got))
+		}
+	}
+	if r, ok := fast.reader.(*mirrorReader); !ok || !r.shared() {
+		t.Error("Fast did not read the file from the shared read")
+	}
+	if r, ok := slow.reader.(*mirrorReader); !ok || r.shared() {
+		t.Error("Slow did not fall back to reading the file itself")
+	}
+	if !bytes.Equal(parts.got, data) {
+		t.Errorf("Parts received %d bytes, want the whole file", len(parts.got))
+	}
+	if r, ok := parts.file.(*mirrorReader); !ok || !r.shared() {
+		t.Error("Parts did not read its parts from the shared read")
+	}
+	if scanner.calls != 1 {
+		t.Errorf("file scanned %d times, want once", scanner.calls)
+	}
+
+	// Повторная загрузка из истории выбирает те же зеркала
+	for _, entry := range store.Entries() {
+		if entry.Provider == "Fast" && !slices.Equal(entry.Mirrors, []string{"Slow", "Parts"}) {
+			t.Errorf("Fast history mirrors = %v, want [Slow Parts]", entry.Mirrors)
+		}
+	}
+}
+
+// TestUploadURI проверяет, что файл хранилища без локального пути копируется во временную папку и загружается,
+// а в историю попадает его URI
+func TestUploadURI(t *testing.T) {
+	store := history.NewInMemory()
+	u := NewUpload(upload.NewRateLimiter(), store, nil)
+	data := bytes.Repeat([]byte("mtp"), 1000)
+	var opened string
+	u.SetURIOpener(func(uri string) (io.ReadCloser, error) {
+		opened = uri
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
+	u.SelectProvider("Fake")
+	u.SelectURIs([]string{"mtp://phone/DCIM/photo%201.jpg"})
+	if !u.CanStart() {
+		t.Fatal("CanStart() = false with a URI selected")
+	}
+
+	if _, err := u.Start(&fakeProvider{}, ""); err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	c := waitResult(t, u)
+	if c.Err != nil || c.FileName != "photo 1.jpg" || c.Size != int64(len(data)) {
+		t.Fatalf("Completion = %+v, want photo 1.jpg of %d bytes", c, len(data))
+	}
+	if opened != "mtp://phone/DCIM/photo%201.jpg" {
+		t.Errorf("opened %q", opened)
+	}
+	entries := store.Entries()
+	if len(entries) != 1 || entries[0].SourceURL != opened || entries[0].FilePath != "" {
+		t.Errorf("history = %+v, want the URI as source and no temporary path", entries)
+	}
+}
+
+// TestUploadBackupKey проверяет, что отклоненная из-за ключа загрузка один раз повторяется с запасным ключом
+func TestUploadBackupKey(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		backup  providers.Provider
+		wantErr bool
+	}{
+		{"Backup succeeds", &fakeProvider{}, false},
+		{"Backup rejected too", &rejectingProvider{}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			u := NewUpload(upload.NewRateLimiter(), nil, nil)
+			var calls int
+			u.SetBackup(func(name string) (providers.Provider, string, bool) {
+				calls++
+				return tc.backup, "backup", true
+			})
+			u.SelectProvider("Fake")
+			u.SelectFile(tempFile(t))
+
+			id, err := u.Start(&rejectingProvider{}, "main")
+			if err != nil {
+				t.Fatalf("Start() = %v", err)
+			}
+			c := waitResult(t, u)
+			if c.JobID == id {
+				t.Fatalf("result of the rejected attempt was published: %+v", c)
+			}
+			if gotErr := c.Err != nil; gotErr != tc.wantErr {
+				t.Errorf("Completion.Err = %v, want error %v", c.Err, tc.wantErr)
+			}
+			if calls != 1 {
+				t.Errorf("backup called %d times, want 1", calls)
+			}
+			if jobs := u.State().Jobs; len(jobs) != 1 || jobs[0].ID != c.JobID {
+				t.Errorf("Jobs = %+v, want only the retried upload", jobs)
+			}
+		})
+	}
+}
+
+// TestUploadPersistsQueue проверяет, что незавершенные загрузки сохраняются и возобновляются
+func TestUploadPersistsQueue(t *testing.T) {
+	pending := queue.NewInMemory()
+	u := NewUpload(upload.NewRateLimiter(), nil, pending)
+	u.SetMaxConcurrent(1)
+	u.SelectProvider("Fake")
+	path := tempFile(t)
+	u.SelectFile(path)
+
+	started := make(chan struct{})
+	running, err := u.Start(&fakeProvider{block: started}, "")
+	if err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	<-started
+	// Параметры загрузки переживают перезапуск: папка аккаунта задается провайдеру снова
+	folders := &albumProvider{folders: make(map[string]string)}
+	queued, err := u.Resume(queue.Item{FilePath: path, HighPriority: true, SkipScan: true, FolderID: "42"}, folders, "")
+	if err != nil {
+		t.Fatalf("Resume() = %v", err)
+	}
+	if job, _ := u.State().Job(queued); job.Phase != PhaseQueued || !job.HighPriority {
+		t.Fatalf("resumed job = %+v, want queued with high priority", job)
+	}
+	if folders.Folder() != "42" {
+		t.Errorf("provider folder = %q, want the saved 42", folders.Folder())
+	}
+
+	want := []queue.Item{
+		{FilePath: path, Provider: "Fake"},
+		{FilePath: path, Provider: "Fake", HighPriority: true, SkipScan: true, FolderID: "42"},
+	}
+	got := pending.Items()
+	if got[0].Progress == nil || got[0].Progress.Size != 4096 {
+		t.Errorf("running job progress = %+v, want snapshot of 4096 byte file", got[0].Progress)
+	}
+	if got := withoutProgress(got); !slices.Equal(got, want) {
+		t.Fatalf("saved queue = %+v, want %+v", got, want)
+	}
+
+	// Завершенные загрузки убираются из сохраненной очереди
+	u.Cancel(running)
+	waitResult(t, u)
+	waitResult(t, u)
+	if got := pending.Items(); len(got) != 0 {
+		t.Errorf("saved queue after finish = %+v, want empty", got)
+	}
+}
+
+// TestUploadShrinksImage проверяет уменьшение картинки, которая без него не уложилась бы в лимит провайдера
+func TestUploadShrinksImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 400, 200))
+	for i := range img.Pix {
+		img.Pix[i] = byte(i * 31 % 255)
+	}
+	var data bytes.Buffer
+	if err := png.Encode(&data, img); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "shot.png")
+	if err := os.WriteFile(path, data.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := history.NewInMemory()
+	u := NewUpload(upload.NewRateLimiter(), store, nil)
+	u.SelectProvider("Fake")
+	u.SelectFile(path)
+	provider := &limitedProvider{maxSize: int64(data.Len()) - 1}
+
+	if _, err := u.Start(provider, ""); !errors.Is(err, upload.ErrFileTooLarge) {
+		t.Fatalf("Start() without image processing = %v, want ErrFileTooLarge", err)
+	}
+
+	u.SetImageOptions(preprocess.ImageOptions{Enabled: true, MaxWidth: 100, Format: preprocess.FormatJPEG})
+	if _, err := u.Start(provider, ""); err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	c := waitResult(t, u)
+	if c.Err != nil || c.FileName != "shot.jpg" || c.MIMEType != "image/jpeg" || c.Size >= int64(data.Len()) {
+		t.Fatalf("Completion = %+v, want smaller shot.jpg", c)
+	}
+	if entries := store.Entries(); len(entries) != 1 || entries[0].FilePath != path {
+		t.Errorf("history = %+v, want entry with original path %s", entries, path)
+	}
+}
+
+// TestUploadSplitsFile проверяет загрузку частями файла больше лимита провайдера
+func TestUploadSplitsFile(t *testing.T) {
+	store := history.NewInMemory()
+	u := NewUpload(upload.NewRateLimiter(), store, nil)
+	u.SetSplit(true, 0)
+	u.SelectProvider("Fake")
+	u.SelectFile(tempFile(t))
+
+	if _, err := u.Start(&limitedProvider{maxSize: 1500}, ""); err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	c := waitResult(t, u)
+	if c.Err != nil || c.Result == nil || c.Size != 4096 {
+		t.Fatalf("Completion = %+v, want successful upload", c)
+	}
+
+	var names []string
+	for _, part := range c.Parts {
+		names = append(names, part.Name)
+	}
+	if want := []string{"file.bin.001", "file.bin.002", "file.bin.003"}; !slices.Equal(names, want) {
+		t.Fatalf("parts = %v, want %v", names, want)
+	}
+	if c.Result.URL != c.Parts[0].URL || c.Verification != nil {
+		t.Errorf("Result.URL = %q, Verification = %+v, want first part and no verification", c.Result.URL, c.Verification)
+	}
+	if entries := store.Entries(); len(entries) != 1 || len(entries[0].Parts) != 3 {
+		t.Errorf("history = %+v, want one entry with 3 parts", entries)
+	}
+}
+
+// TestUploadSplitKeepsParts проверяет, что итог с ошибкой части содержит ссылки на части до нее
+func TestUploadSplitKeepsParts(t *testing.T) {
+	u := NewUpload(upload.NewRateLimiter(), nil, nil)
+	u.SetSplit(true, 0)
+	u.SelectProvider("Fake")
+	u.SelectFile(tempFile(t))
+
+	if _, err := u.Start(&limitedProvider{maxSize: 1500, failFile: "file.bin.003"}, ""); err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	c := waitResult(t, u)
+	if c.Err == nil {
+		t.Fatalf("Completion = %+v, want error of the third part", c)
+	}
+	var names []string
+	for _, part := range c.Parts {
+		names = append(names, part.Name)
+	}
+	if want := []string{"file.bin.001", "file.bin.002"}; !slices.Equal(names, want) {
+		t.Errorf("parts = %v, want %v", names, want)
+	}
+}
+
+// TestUploadOffline проверяет повтор загрузки после возвращения связи
+func TestUploadOffline(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		lost    bool // связь пропадала при обрыве соединения
+		wantErr bool
+	}{
+		{"Connection returns", true, false},
+		{"Network is up", false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			u := NewUpload(upload.NewRateLimiter(), nil, nil)
+			waits := 0
+			u.waitOnline = func(ctx context.Context, offline func()) (bool, error) {
+				waits++
+				if tc.lost {
+					offline()
+				}
+				return tc.lost, nil
+			}
+			u.SelectProvider("Fake")
+			u.SelectFile(tempFile(t))
+
+			if _, err := u.Start(&flakyProvider{failures: 2}, ""); err != nil {
+				t.Fatalf("Start() = %v", err)
+			}
+			c := waitResult(t, u)
+			if (c.Err != nil) != tc.wantErr {
+				t.Fatalf("Completion.Err = %v, want error %v", c.Err, tc.wantErr)
+			}
+
+			// Без обрыва связи ошибка возвращается сразу, иначе загрузка повторяется после каждого обрыва
+			wantWaits := 1
+			if tc.lost {
+				wantWaits = 2
+			}
+			if waits != wantWaits {
+				t.Errorf("waited for network %d times, want %d", waits, wantWaits)
+			}
+		})
+	}
+}
+
+// TestUploadAlbum проверяет загрузку нескольких файлов в одну новую папку
+func TestUploadAlbum(t *testing.T) {
+	store := history.NewInMemory()
+	u := NewUpload(upload.NewRateLimiter(), store, nil)
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"a.bin", "b.bin", "c.bin"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	u.SelectProvider("Fake")
+	u.SelectFiles(paths[:2])
+	u.AddFile(paths[2])
+	u.AddFile(paths[0])
+	if got := u.State().FilePaths; !slices.Equal(got, paths) {
+		t.Fatalf("FilePaths = %v, want %v", got, paths)
+	}
+	u.SetAlbum(true)
+
+	provider := &albumProvider{folders: make(map[string]string)}
+	if _, err := u.Start(provider, ""); err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+
+	var album *AlbumResult
+	for range paths {
+		c := waitResult(t, u)
+		if c.Err != nil || c.CollectionURL != "https://example.invalid/folder/42" {
+			t.Errorf("Completion = %+v, want upload into the album", c)
+		}
+		if c.Album != nil {
+			album = c.Album
+		}
+	}
+
+	if provider.created != 1 {
+		t.Errorf("created %d folders, want 1", provider.created)
+	}
+	for name, folder := range provider.folders {
+		if folder != "42" {
+			t.Errorf("%s uploaded to folder %q, want 42", name, folder)
+		}
+	}
+	if album == nil || album.URL != "https://example.invalid/folder/42" || album.Uploaded != 3 || album.Failed != 0 {
+		t.Errorf("album = %+v, want 3 files in folder 42", album)
+	}
+	if done := u.State().Succeeded(); len(done) != 3 {
+		t.Errorf("Succeeded() returned %d uploads, want 3", len(done))
+	}
+	if entries := store.Entries(); len(entries) != 3 || entries[0].CollectionURL != "https://example.invalid/folder/42" {
+		t.Errorf("history = %+v, want 3 entries with the album link", entries)
+	}
+}
+
+// TestStateProgress проверяет сводный прогресс незавершенных загрузок
+func TestStateProgress(t *testing.T) {
+	s := State{Jobs: []JobState{
+		{ID: 1, Active: true, Phase: PhaseUploading, Bytes: 300, Total: 1000, Speed: 50, AvgSpeed: 40},
+		{ID: 2, Active: true, Phase: PhaseQueued, Total: 1000},
+		{ID: 3, Active: true, Phase: PhaseFetching, Bytes: 700, Speed: 25, AvgSpeed: 20},
+		{ID: 4, Bytes: 500, Total: 500},
+	}}
+
+	want := Progress{Active: 3, Queued: 1, Bytes: 300, Total: 2000, Speed: 75, AvgSpeed: 40}
+	if got := s.Progress(); got != want {
+		t.Errorf("Progress() = %+v, want %+v", got, want)
+	}
+	if got := want.Fraction(); got != 0.15 {
+		t.Errorf("Fraction() = %v, want 0.15", got)
+	}
+	if got := (Progress{}).Fraction(); got != 0 {
+		t.Errorf("empty Fraction() = %v, want 0", got)
+	}
+
+	// Оставшиеся 1700 байт всей очереди при 40 байт/сек
+	if got, ok := want.Remaining(); !ok || got != 42500*time.Millisecond {
+		t.Errorf("Remaining() = %v, %v, want 42.5s", got, ok)
+	}
+	if _, ok := (Progress{Total: 100}).Remaining(); ok {
+		t.Error("Remaining() is known without speed")
+	}
+}
+
+// TestUploadShutdown проверяет, что выход останавливает загрузки, но оставляет их в сохраненной очереди
+func TestUploadShutdown(t *testing.T) {
+	pending := queue.NewInMemory()
+	u := NewUpload(upload.NewRateLimiter(), nil, pending)
+	u.SetMaxConcurrent(1)
+	u.SelectProvider("Fake")
+	path := tempFile(t)
+	u.SelectFile(path)
+
+	started := make(chan struct{})
+	if _, err := u.Start(&fakeProvider{block: started}, ""); err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	<-started
+	if _, err := u.Start(&fakeProvider{}, ""); err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+
+	if !u.Shutdown(5 * time.Second) {
+		t.Fatal("Shutdown() = false, uploads did not stop")
+	}
+	if active := u.State().Active(); active != 0 {
+		t.Errorf("active uploads after Shutdown() = %d", active)
+	}
+	want := []queue.Item{
+		{FilePath: path, Provider: "Fake"},
+		{FilePath: path, Provider: "Fake"},
+	}
+	if got := withoutProgress(pending.Items()); !slices.Equal(got, want) {
+		t.Errorf("saved queue after Shutdown() = %+v, want %+v", got, want)
+	}
+}
+
+// TestUploadResumesSnapshot проверяет, что точка продолжения сохраняется при выходе и передается провайдеру после перезапуска
+func TestUploadResumesSnapshot(t *testing.T) {
+	pending := queue.NewInMemory()
+	u := NewUpload(upload.NewRateLimiter(), nil, pending)
+	u.SelectProvider("Fake")
+	path := tempFile(t)
+	u.SelectFile(path)
+
+	started := make(chan struct{})
+	interrupted := &resumableProvider{fakeProvider: fakeProvider{block: started}}
+	if _, err := u.Start(interrupted, ""); err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	<-started
+	if !u.Shutdown(5 * time.Second) {
+		t.Fatal("Shutdown() = false, uploads did not stop")
+	}
+	if !errors.Is(interrupted.cause, providers.ErrInterrupted) {
+		t.Errorf("cancel cause = %v, want ErrInterrupted", interrupted.cause)
+	}
+
+	items := pending.Items()
+	if len(items) != 1 || items[0].Progress == nil || items[0].Progress.Checkpoint == nil {
+		t.Fatalf("saved queue = %+v, want upload with checkpoint", items)
+	}
+
+	// Следующий запуск продолжает загрузку с сохраненного места
+	resumed := NewUpload(upload.NewRateLimiter(), nil, queue.NewInMemory())
+	provider := &resumableProvider{}
+	if _, err := resumed.Resume(items[0], provider, ""); err != nil {
+		t.Fatalf("Resume() = %v", err)
+	}
+	if c := waitResult(t, resumed); c.Err != nil {
+		t.Fatalf("resumed upload error = %v", c.Err)
+	}
+	want := &providers.Checkpoint{Upload: "https://example.invalid/uploads/1", Offset: 2048}
+	if provider.from == nil || *provider.from != *want {
+		t.Errorf("resumed from %+v, want %+v", provider.from, want)
+	}
+
+	// Измененный файл загружается заново
+	if err := os.WriteFile(path, make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	provider = &resumableProvider{}
+	if _, err := resumed.Resume(items[0], provider, ""); err != nil {
+		t.Fatalf("Resume() = %v", err)
+	}
+	waitResult(t, resumed)
+	if provider.from != nil {
+		t.Errorf("changed file resumed from %+v, want new upload", provider.from)
+	}
+}
+
+// withoutProgress убирает снимки прогресса из сохраненной очереди
+func withoutProgress(items []queue.Item) []queue.Item {
+	for i := range items {
+		items[i].Progress = nil
+	}
+	return items
+}
+
+// TestUploadStress быстро запускает и отменяет сотни мок загрузок из нескольких горутин,
+// пока другая горутина читает состояние, как UI. Смысл теста - запуск с go test -race:
+// он ловит гонки доступа к состоянию и сессиям и утечки горутин загрузок
+func TestUploadStress(t *testing.T) {
+	const workers = 8
+	perWorker := 25
+	if testing.Short() {
+		perWorker = 5
+	}
+	total := workers * perWorker
+
+	// Ссылки мок провайдера ненастоящие: в dry run загрузка не проверяет их по сети
+	httpclient.EnableDryRun(http.NotFoundHandler())
+	defer httpclient.DisableDryRun()
+
+	pending := queue.NewInMemory()
+	u := NewUpload(upload.NewRateLimiter(), nil, pending)
+	u.waitOnline = func(ctx context.Context, offline func()) (bool, error) { return false, nil }
+	u.SelectProvider("Mock")
+	u.SelectFile(tempFile(t))
+	configs := []providers.MockConfig{
+		{SpeedMBPerSec: 100},
+		{SpeedMBPerSec: 1, Latency: 50 * time.Millisecond, Jitter: 0.5},
+		{SpeedMBPerSec: 1, FailAtPercent: 50},
+	}
+
+	before := runtime.NumGoroutine()
+
+	// Читатель состояния, как вкладка загрузки
+	stop := make(chan struct{})
+	var reader sync.WaitGroup
+	reader.Add(1)
+	go func() {
+		defer reader.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			case s := <-u.Updates():
+				_ = s.Active() + s.Queued()
+				for _, job := range s.Jobs {
+					_ = len(job.SpeedSamples)
+				}
+			}
+		}
+	}()
+
+	// Итоги собираются отдельно: канал итогов не буферизован
+	finished := make(chan map[int]int)
+	go func() {
+		counts := make(map[int]int)
+		for range total {
+			select {
+			case c := <-u.Results():
+				counts[c.JobID]++
+			case <-time.After(time.Minute):
+				finished <- counts
+				return
+			}
+		}
+		finished <- counts
+	}()
+
+	var workersDone sync.WaitGroup
+	for w := range workers {
+		workersDone.Add(1)
+		go func() {
+			defer workersDone.Done()
+			for i := range perWorker {
+				id, err := u.Start(providers.NewMockProviderWithConfig("Mock", configs[(w+i)%len(configs)]), "")
+				if err != nil {
+					t.Errorf("Start() = %v", err)
+					return
+				}
+				switch i % 4 {
+				case 0:
+					u.Cancel(id)
+				case 1:
+					workersDone.Add(1)
+					go func() {
+						defer workersDone.Done()
+						time.Sleep(time.Duration(i%5) * 10 * time.Millisecond)
+						u.Cancel(id)
+						u.Cancel(id)
+					}()
+				case 2:
+					u.SetHighPriority(id, true)
+					u.Move(id, -1)
+				}
+				if i%10 == 9 {
+					u.SetMaxConcurrent(w % 3 * 8)
+					u.ClearFinished()
+				}
+			}
+		}()
+	}
+	workersDone.Wait()
+	u.SetMaxConcurrent(0)
+
+	counts := <-finished
+	if len(counts) != total {
+		t.Errorf("got results for %d uploads, want %d", len(counts), total)
+	}
+	for id, n := range counts {
+		if n != 1 {
+			t.Errorf("upload %d finished %d times", id, n)
+		}
+	}
+
+	u.Wait()
+	close(stop)
+	reader.Wait()
+
+	if n := u.State().Active(); n != 0 {
+		t.Errorf("Active() = %d after all uploads finished", n)
+	}
+	if items := pending.Items(); len(items) != 0 {
+		t.Errorf("saved queue = %d items after all uploads finished, want none", len(items))
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("goroutines = %d after stress, want at most %d", n, before)
+	}
+}
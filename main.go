@@ -1,15 +1,43 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
 	"fyne.io/fyne/v2/app"
 
+	"multiUploader/internal/instance"
 	"multiUploader/internal/localization"
 	"multiUploader/internal/logging"
 	"multiUploader/internal/providers"
+	"multiUploader/internal/shellintegration"
+	"multiUploader/internal/tracing"
 	"multiUploader/internal/ui"
 )
 
 func main() {
+	// Флаги для установщика: регистрация пункта "Send to multiUploader" в файловом менеджере
+	registerShell := flag.Bool("register-shell", false, "add \""+shellintegration.MenuLabel+"\" to the file manager context menu and exit")
+	unregisterShell := flag.Bool("unregister-shell", false, "remove the file manager context menu entry and exit")
+	flag.Parse()
+
+	if *registerShell || *unregisterShell {
+		os.Exit(runShellIntegration(*registerShell))
+	}
+
+	// Файлы из аргументов (контекстное меню файлового менеджера)
+	// Если приложение уже запущено - передаем файлы ему и выходим
+	files := flag.Args()
+	if len(files) > 0 {
+		if err := instance.SendToRunning(files); err == nil {
+			return
+		} else if !errors.Is(err, instance.ErrNotRunning) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+
 	// Инициализируем логгер (пишет только errors в файл)
 	if err := logging.Init(); err != nil {
 		// Если не удалось инициализировать логгер, просто продолжаем
@@ -62,7 +90,59 @@ func main() {
 	multiApp.RegisterProviderFactory("FileKeeper", func(apiKey string) providers.Provider {
 		return providers.NewFileKeeperProvider(apiKey)
 	})
+	multiApp.RegisterProviderFactory("Telegram", func(apiKey string) providers.Provider {
+		return providers.NewTelegramProvider(apiKey)
+	})
+	// IPFS работает с локальным узлом или Pinata: адреса и ключ задаются в настройках
+	multiApp.RegisterProviderFactory("IPFS", func(apiKey string) providers.Provider {
+		return providers.NewIPFSProvider()
+	})
+	// tus не использует API ключ: адрес сервера и авторизация задаются в настройках
+	multiApp.RegisterProviderFactory("tus", func(apiKey string) providers.Provider {
+		return providers.NewTusProvider()
+	})
+	// Backblaze B2: в поле API ключа - ключ приложения, Key ID и бакет задаются в настройках
+	multiApp.RegisterProviderFactory("Backblaze B2", func(apiKey string) providers.Provider {
+		return providers.NewB2Provider(apiKey)
+	})
+	// Storj: в поле API ключа - access grant, бакет задается в настройках
+	multiApp.RegisterProviderFactory("Storj", func(apiKey string) providers.Provider {
+		return providers.NewStorjProvider(apiKey)
+	})
+
+	// Принимаем файлы от последующих запусков приложения
+	server, err := instance.Listen(multiApp.ReceiveFiles)
+	if err != nil {
+		logging.ErrorWithError("Failed to start instance listener", err)
+	} else {
+		defer server.Close()
+	}
+
+	// Файлы, переданные при первом запуске
+	if len(files) > 0 {
+		multiApp.OpenFiles(files)
+	}
 
 	// Запускаем приложение
 	multiApp.Run()
+
+	// Отправляем span, накопленные до выхода (если трассировка включена в меню разработчика)
+	tracing.Disable()
+}
+
+// runShellIntegration регистрирует или удаляет интеграцию с файловым менеджером
+// Возвращает код выхода процесса
+func runShellIntegration(register bool) int {
+	var err error
+	if register {
+		err = shellintegration.Register()
+	} else {
+		err = shellintegration.Unregister()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
 }